@@ -11,7 +11,15 @@ import (
 	"github.com/SovereignCloudStack/status-page-api/internal/app/metrics"
 	APIServer "github.com/SovereignCloudStack/status-page-api/internal/app/server"
 	"github.com/SovereignCloudStack/status-page-api/internal/app/util/shutdown"
+	"github.com/SovereignCloudStack/status-page-api/pkg/alertmanager"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	"github.com/SovereignCloudStack/status-page-api/pkg/events"
+	"github.com/SovereignCloudStack/status-page-api/pkg/mail"
+	"github.com/SovereignCloudStack/status-page-api/pkg/purge"
 	APIImplementation "github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/SovereignCloudStack/status-page-api/pkg/subscription"
+	"github.com/SovereignCloudStack/status-page-api/pkg/webhook"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -52,19 +60,70 @@ func main() { //nolint:funlen
 
 	// named logging
 	echoLogger := logger.With().Str("component", "echo").Logger()
+	eventsLogger := logger.With().Str("component", "events").Logger()
 	gormLogger := logger.With().Str("component", "gorm").Logger()
 	handlerLogger := logger.With().Str("component", "handler").Logger()
 	metricsLogger := logger.With().Str("component", "metrics").Logger()
+	provisioningLogger := logger.With().Str("component", "provisioning").Logger()
+	purgeLogger := logger.With().Str("component", "purge").Logger()
 	shutdownLogger := logger.With().Str("component", "shutdown").Logger()
+	subscriptionLogger := logger.With().Str("component", "subscription").Logger()
+	webhookLogger := logger.With().Str("component", "webhook").Logger()
 
-	// DB setup
-	dbWrapper, err := db.New(conf.Database.ConnectionString, &gormLogger)
-	if err != nil {
-		logger.Fatal().Err(err).Msg("error creating database wrapper")
+	// storage setup
+	var (
+		store       storage.Storage
+		dbWrapper   *db.Database
+		apiKeyStore auth.APIKeyStore
+	)
+
+	if conf.Storage == config.StorageMemory {
+		logger.Warn().Msg("using memory storage, all data is lost on shutdown")
+
+		store = storage.NewMemory()
+	} else {
+		dbWrapper, err = db.New(conf.Database.ConnectionString, &gormLogger)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("error creating database wrapper")
+		}
+
+		// run a command like `migrate up` instead of serving
+		if len(conf.Args) > 0 {
+			err = runCommand(dbWrapper, conf.Args, os.Stdout)
+			if err != nil {
+				logger.Fatal().Err(err).Msg("error running command")
+			}
+
+			return
+		}
+
+		// migrate like `migrate up`, if opted in, so deployments without a separate migration step keep starting
+		if conf.Database.MigrateOnStart {
+			var applied []db.Migration
+
+			applied, err = dbWrapper.MigrateUp()
+			if err != nil {
+				logger.Fatal().Err(err).Msg("error migrating database on start")
+			}
+
+			for _, migration := range applied {
+				logger.Info().Int64("version", migration.Version).Str("name", migration.Name).Msg("applied migration")
+			}
+		}
+
+		// refuse to serve an outdated schema
+		err = dbWrapper.CheckSchema()
+		if err != nil {
+			logger.Fatal().Err(err).
+				Msg("database schema is outdated, run `status-page-api migrate up` or set `--database-migrate-on-start`")
+		}
+
+		store = storage.NewGorm(dbWrapper.GetDBCon())
+		apiKeyStore = dbWrapper
 	}
 
-	// Initialize "static" DB contents
-	err = dbWrapper.Provision(conf.ProvisioningFile)
+	// Initialize "static" storage contents
+	err = db.Provision(store, conf.ProvisioningFile, &provisioningLogger)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("error provisioning data")
 	}
@@ -73,8 +132,115 @@ func main() { //nolint:funlen
 	metricsServer := metrics.New(&conf.Metrics, &metricsLogger)
 
 	// register api server
-	apiServer := APIServer.New(&conf.Server, &echoLogger, metricsServer.GetMiddlewareConfig())
-	apiServer.RegisterAPI(APIImplementation.New(dbWrapper.GetDBCon(), &handlerLogger))
+	apiServer, err := APIServer.New(&conf.Server, &echoLogger, metricsServer.GetMiddlewareConfig(), apiKeyStore)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("error creating api server")
+	}
+
+	// set up event fan out to the event streams of all instances
+	// the memory storage has no extension routes and workers, which depend on the database.
+	var (
+		eventListener     *events.Listener
+		webhookDispatcher *webhook.Dispatcher
+	)
+
+	eventBroker := events.NewBroker()
+
+	switch {
+	case dbWrapper != nil && dbWrapper.IsSQLite():
+		// SQLite can't notify other connections, so new events are polled.
+		eventListener = events.NewPoller(eventBroker, conf.Database.PollInterval, &eventsLogger)
+	case dbWrapper != nil:
+		eventListener = events.NewListener(conf.Database.ConnectionString, eventBroker, &eventsLogger)
+	}
+
+	// set up email subscriptions
+	var (
+		subscriptionComposer *subscription.Composer
+		subscriptionNotifier *subscription.Notifier
+	)
+
+	if conf.Subscriptions.Enabled {
+		subscriptionComposer = subscription.NewComposer(
+			subscription.NewSigner(conf.Subscriptions.Secret),
+			subscription.ComposerConfig{
+				PublicURL:  conf.Subscriptions.PublicURL,
+				ConfirmTTL: conf.Subscriptions.ConfirmTTL,
+			},
+		)
+		subscriptionNotifier = subscription.NewNotifier(
+			dbWrapper.GetDBCon(),
+			subscriptionComposer,
+			mail.NewSMTPSender(mail.SMTPConfig{
+				Address:  conf.Subscriptions.SMTP.Address,
+				Username: conf.Subscriptions.SMTP.Username,
+				Password: conf.Subscriptions.SMTP.Password,
+				Timeout:  conf.Subscriptions.SMTP.Timeout,
+			}),
+			subscription.NotifierConfig{
+				From:         conf.Subscriptions.SMTP.From,
+				PollInterval: conf.Subscriptions.PollInterval,
+				MaxAttempts:  conf.Subscriptions.MaxAttempts,
+				BackoffBase:  conf.Subscriptions.BackoffBase,
+				BackoffMax:   conf.Subscriptions.BackoffMax,
+				BatchSize:    conf.Subscriptions.BatchSize,
+			},
+			&subscriptionLogger,
+		)
+	}
+
+	// set up alertmanager receiver
+	var alertMapper *alertmanager.Mapper
+
+	if conf.Alertmanager.Enabled {
+		labelMatchers, err := conf.Alertmanager.ParseLabelMatchers()
+		if err != nil {
+			logger.Fatal().Err(err).Msg("error parsing alertmanager label matchers")
+		}
+
+		severities, err := conf.Alertmanager.ParseSeverityMapping()
+		if err != nil {
+			logger.Fatal().Err(err).Msg("error parsing alertmanager severity mapping")
+		}
+
+		alertMapper = alertmanager.NewMapper(alertmanager.Config{
+			ComponentLabel:    conf.Alertmanager.ComponentLabel,
+			LabelMatchers:     labelMatchers,
+			SeverityLabel:     conf.Alertmanager.SeverityLabel,
+			Severities:        severities,
+			DefaultSeverity:   conf.Alertmanager.DefaultSeverity,
+			ImpactTypeLabel:   conf.Alertmanager.ImpactTypeLabel,
+			DefaultImpactType: conf.Alertmanager.DefaultImpactType,
+		})
+	}
+
+	apiServer.RegisterAPI(
+		APIImplementation.New(store, eventBroker, subscriptionComposer, alertMapper, &handlerLogger).
+			RequireSeverityBands(conf.Incidents.RequireSeverityBands),
+	)
+
+	// set up webhook dispatcher
+	if dbWrapper != nil {
+		webhookDispatcher = webhook.New(dbWrapper.GetDBCon(), webhook.Config{
+			PollInterval: conf.Webhooks.PollInterval,
+			Timeout:      conf.Webhooks.Timeout,
+			MaxAttempts:  conf.Webhooks.MaxAttempts,
+			BackoffBase:  conf.Webhooks.BackoffBase,
+			BackoffMax:   conf.Webhooks.BackoffMax,
+			BatchSize:    conf.Webhooks.BatchSize,
+		}, &webhookLogger)
+	}
+
+	// set up purge of deleted incidents and components and of published events
+	var purger *purge.Purger
+
+	if conf.Purge.Enabled {
+		purger = purge.New(store, purge.Config{
+			Interval:       conf.Purge.Interval,
+			Retention:      conf.Purge.Retention,
+			EventRetention: conf.Purge.EventRetention,
+		}, &purgeLogger)
+	}
 
 	// start metric server
 	go func() {
@@ -84,6 +250,46 @@ func main() { //nolint:funlen
 		}
 	}()
 
+	// start event listener
+	if eventListener != nil {
+		go func() {
+			err := eventListener.Start()
+			if err != nil {
+				logger.Warn().Err(err).Msg("error running event listener")
+			}
+		}()
+	}
+
+	// start webhook dispatcher
+	if webhookDispatcher != nil {
+		go func() {
+			err := webhookDispatcher.Start()
+			if err != nil {
+				logger.Warn().Err(err).Msg("error running webhook dispatcher")
+			}
+		}()
+	}
+
+	// start purger
+	if purger != nil {
+		go func() {
+			err := purger.Start()
+			if err != nil {
+				logger.Warn().Err(err).Msg("error running purger")
+			}
+		}()
+	}
+
+	// start subscription notifier
+	if subscriptionNotifier != nil {
+		go func() {
+			err := subscriptionNotifier.Start()
+			if err != nil {
+				logger.Warn().Err(err).Msg("error running subscription notifier")
+			}
+		}()
+	}
+
 	// handle error of api server
 	errChan := make(chan error, 1)
 
@@ -100,11 +306,13 @@ func main() { //nolint:funlen
 	case err := <-errChan:
 		logger.Error().Err(err).Msg("error running server, shutting down")
 
-		shutdown.Shutdown(conf.ShutdownTimeout, apiServer, metricsServer, &shutdownLogger)
+		shutdown.Shutdown(conf.ShutdownTimeout, apiServer, metricsServer, webhookDispatcher, eventListener,
+			subscriptionNotifier, purger, &shutdownLogger)
 
 	case sig := <-shutdownChan:
 		logger.Log().Str("signal", sig.String()).Msg("got shutdown signal")
 
-		shutdown.Shutdown(conf.ShutdownTimeout, apiServer, metricsServer, &shutdownLogger)
+		shutdown.Shutdown(conf.ShutdownTimeout, apiServer, metricsServer, webhookDispatcher, eventListener,
+			subscriptionNotifier, purger, &shutdownLogger)
 	}
 }
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/db"
+)
+
+var (
+	// ErrUnknownCommand is an error, raised when the arguments name no known command.
+	ErrUnknownCommand = errors.New("unknown command, expected `migrate up`, `migrate down [steps]` or `migrate status`")
+	// ErrInvalidSteps is an error, raised when the steps to migrate down are no positive number.
+	ErrInvalidSteps = errors.New("steps must be a positive number")
+)
+
+// runCommand runs the command given by the arguments, writing its results to out.
+func runCommand(dbWrapper *db.Database, args []string, out io.Writer) error {
+	if len(args) < 2 || len(args) > 3 || args[0] != "migrate" { //nolint:mnd
+		return ErrUnknownCommand
+	}
+
+	switch {
+	case args[1] == "up" && len(args) == 2: //nolint:mnd
+		applied, err := dbWrapper.MigrateUp()
+		if err != nil {
+			return fmt.Errorf("error migrating up: %w", err)
+		}
+
+		printMigrations(out, "applied", applied)
+
+	case args[1] == "down":
+		steps := 1
+
+		if len(args) == 3 { //nolint:mnd
+			var err error
+
+			steps, err = strconv.Atoi(args[2])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("%w: %s", ErrInvalidSteps, args[2])
+			}
+		}
+
+		reverted, err := dbWrapper.MigrateDown(steps)
+		if err != nil {
+			return fmt.Errorf("error migrating down: %w", err)
+		}
+
+		printMigrations(out, "reverted", reverted)
+
+	case args[1] == "status" && len(args) == 2: //nolint:mnd
+		states, err := dbWrapper.MigrationStatus()
+		if err != nil {
+			return fmt.Errorf("error reading migration status: %w", err)
+		}
+
+		printMigrationStates(out, states)
+
+	default:
+		return ErrUnknownCommand
+	}
+
+	return nil
+}
+
+func printMigrations(out io.Writer, action string, migrations []db.Migration) {
+	if len(migrations) == 0 {
+		fmt.Fprintf(out, "no migrations %s\n", action)
+
+		return
+	}
+
+	for _, migration := range migrations {
+		fmt.Fprintf(out, "%s %d_%s\n", action, migration.Version, migration.Name)
+	}
+}
+
+func printMigrationStates(out io.Writer, states []db.MigrationState) {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0) //nolint:mnd
+
+	fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+
+	for _, state := range states {
+		appliedAt := "pending"
+		if state.AppliedAt != nil {
+			appliedAt = state.AppliedAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(writer, "%d\t%s\t%s\n", state.Version, state.Name, appliedAt)
+	}
+
+	writer.Flush()
+}
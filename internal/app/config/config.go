@@ -2,16 +2,31 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
+const (
+	// StoragePostgres selects the PostgreSQL storage, required by the extension routes and background workers.
+	StoragePostgres = "postgres"
+	// StorageMemory selects the volatile in-memory storage for tests and demos.
+	StorageMemory = "memory"
+)
+
 // Database holds configuration regarding the database connection.
 type Database struct {
 	ConnectionString string `json:"-"` // do not leak database password when logging.
+	PollInterval     time.Duration
+	MigrateOnStart   bool
 }
 
 func (db Database) isValid() error {
@@ -19,6 +34,10 @@ func (db Database) isValid() error {
 		return ErrNoDBConnectionString
 	}
 
+	if db.PollInterval <= 0 {
+		return ErrInvalidPollInterval
+	}
+
 	return nil
 }
 
@@ -36,10 +55,92 @@ func (c CORS) isValid() error {
 	return nil
 }
 
+// Auth holds the configuration regarding the authentication and authorization of requests.
+type Auth struct {
+	Enabled      bool
+	Issuer       string
+	Audience     string
+	JWKSURL      string
+	JWKSFile     string
+	PublicRead   bool
+	ScopeClaim   string
+	ScopeMapping []string
+}
+
+// ParseScopeMapping parses the scope mapping entries in the form of `<claim value>=<scope>`.
+func (a Auth) ParseScopeMapping() (map[string]auth.Scope, error) {
+	mapping := make(map[string]auth.Scope, len(a.ScopeMapping))
+
+	for _, entry := range a.ScopeMapping {
+		value, scopeName, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(value) == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScopeMapping, entry)
+		}
+
+		scope, err := auth.ParseScope(scopeName)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidScopeMapping, err)
+		}
+
+		mapping[strings.TrimSpace(value)] = scope
+	}
+
+	return mapping, nil
+}
+
+func (a Auth) isValid() error {
+	if !a.Enabled {
+		return nil
+	}
+
+	if a.Issuer == "" {
+		return ErrNoAuthIssuer
+	}
+
+	if a.Audience == "" {
+		return ErrNoAuthAudience
+	}
+
+	if a.JWKSURL == "" && a.JWKSFile == "" {
+		return ErrNoJWKSSource
+	}
+
+	if a.JWKSURL != "" && a.JWKSFile != "" {
+		return ErrMultipleJWKSSources
+	}
+
+	if a.ScopeClaim == "" {
+		return ErrNoScopeClaim
+	}
+
+	_, err := a.ParseScopeMapping()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validation holds the configuration regarding the validation against the OpenAPI spec.
+type Validation struct {
+	Enabled bool
+	Strict  bool
+}
+
+func (v Validation) isValid() error {
+	if v.Strict && !v.Enabled {
+		return ErrStrictValidationDisabled
+	}
+
+	return nil
+}
+
 // Server holds configuration regarding the api server.
 type Server struct {
 	Address        string
 	CORS           CORS
+	Auth           Auth
+	Validation     Validation
 	SwaggerEnabled bool
 }
 
@@ -53,6 +154,16 @@ func (s Server) isValid() error {
 		return fmt.Errorf("error validating CORS config: %w", err)
 	}
 
+	err = s.Auth.isValid()
+	if err != nil {
+		return fmt.Errorf("error validating auth config: %w", err)
+	}
+
+	err = s.Validation.isValid()
+	if err != nil {
+		return fmt.Errorf("error validating validation config: %w", err)
+	}
+
 	return nil
 }
 
@@ -75,14 +186,231 @@ func (m Metrics) isValid() error {
 	return nil
 }
 
+// Webhooks holds configuration regarding the delivery of webhooks.
+type Webhooks struct {
+	PollInterval time.Duration
+	Timeout      time.Duration
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	BatchSize    int
+}
+
+func (w Webhooks) isValid() error {
+	if w.PollInterval <= 0 || w.Timeout <= 0 || w.BackoffBase <= 0 || w.BackoffMax < w.BackoffBase {
+		return ErrInvalidWebhookTiming
+	}
+
+	if w.MaxAttempts < 1 || w.BatchSize < 1 {
+		return ErrInvalidWebhookLimits
+	}
+
+	return nil
+}
+
+// Purge holds configuration regarding the purge of deleted incidents and components and of published events.
+type Purge struct {
+	Enabled        bool
+	Interval       time.Duration
+	Retention      time.Duration
+	EventRetention time.Duration
+}
+
+func (p Purge) isValid() error {
+	if !p.Enabled {
+		return nil
+	}
+
+	if p.Interval <= 0 || p.Retention < 0 || p.EventRetention <= 0 {
+		return ErrInvalidPurgeTiming
+	}
+
+	return nil
+}
+
+// Incidents holds configuration regarding the validation of incidents.
+type Incidents struct {
+	RequireSeverityBands bool
+}
+
+// SMTP holds configuration regarding the mail server used for subscriptions.
+type SMTP struct {
+	Address  string
+	Username string
+	Password string `json:"-"` // do not leak SMTP password when logging.
+	From     string
+	Timeout  time.Duration
+}
+
+func (s SMTP) isValid() error {
+	_, _, err := net.SplitHostPort(s.Address)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSMTPAddress, err)
+	}
+
+	_, err = mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSMTPFrom, err)
+	}
+
+	if s.Timeout <= 0 {
+		return ErrInvalidSubscriptionTiming
+	}
+
+	return nil
+}
+
+// Subscriptions holds configuration regarding email subscriptions.
+type Subscriptions struct {
+	Enabled      bool
+	PublicURL    string
+	Secret       string `json:"-"` // do not leak token secret when logging.
+	ConfirmTTL   time.Duration
+	PollInterval time.Duration
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	BatchSize    int
+	SMTP         SMTP
+}
+
+// minimumSubscriptionSecretLength is the minimum length of the secret signing subscription tokens.
+const minimumSubscriptionSecretLength = 32
+
+func (s Subscriptions) isValid() error {
+	if !s.Enabled {
+		return nil
+	}
+
+	publicURL, err := url.Parse(s.PublicURL)
+	if err != nil || (publicURL.Scheme != "http" && publicURL.Scheme != "https") || publicURL.Host == "" {
+		return fmt.Errorf("%w: %s", ErrInvalidPublicURL, s.PublicURL)
+	}
+
+	if len(s.Secret) < minimumSubscriptionSecretLength {
+		return ErrShortSubscriptionSecret
+	}
+
+	if s.ConfirmTTL <= 0 || s.PollInterval <= 0 || s.BackoffBase <= 0 || s.BackoffMax < s.BackoffBase {
+		return ErrInvalidSubscriptionTiming
+	}
+
+	if s.MaxAttempts < 1 || s.BatchSize < 1 {
+		return ErrInvalidSubscriptionLimits
+	}
+
+	err = s.SMTP.isValid()
+	if err != nil {
+		return fmt.Errorf("error validating SMTP config: %w", err)
+	}
+
+	return nil
+}
+
+// Alertmanager holds configuration regarding the Alertmanager webhook receiver.
+type Alertmanager struct {
+	Enabled           bool
+	ComponentLabel    string
+	LabelMatchers     []string
+	SeverityLabel     string
+	SeverityMapping   []string
+	DefaultSeverity   int
+	ImpactTypeLabel   string
+	DefaultImpactType string
+}
+
+// ParseLabelMatchers parses the label matchers in the form of `<component label>=<alert label>`.
+func (a Alertmanager) ParseLabelMatchers() (map[string]string, error) {
+	matchers := make(map[string]string, len(a.LabelMatchers))
+
+	for _, entry := range a.LabelMatchers {
+		componentLabel, alertLabel, found := strings.Cut(entry, "=")
+		componentLabel = strings.TrimSpace(componentLabel)
+		alertLabel = strings.TrimSpace(alertLabel)
+
+		if !found || componentLabel == "" || alertLabel == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidLabelMatcher, entry)
+		}
+
+		matchers[componentLabel] = alertLabel
+	}
+
+	return matchers, nil
+}
+
+// ParseSeverityMapping parses the severity mapping entries in the form of `<alert severity>=<severity value>`.
+func (a Alertmanager) ParseSeverityMapping() (map[string]int, error) {
+	mapping := make(map[string]int, len(a.SeverityMapping))
+
+	for _, entry := range a.SeverityMapping {
+		alertSeverity, valueString, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(alertSeverity) == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSeverityMapping, entry)
+		}
+
+		value, err := strconv.Atoi(strings.TrimSpace(valueString))
+		if err != nil || !isAlertSeverity(value) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSeverityMapping, entry)
+		}
+
+		mapping[strings.TrimSpace(alertSeverity)] = value
+	}
+
+	return mapping, nil
+}
+
+// isAlertSeverity reports, if an alert can be mapped to the severity value.
+// Maintenances can't be opened by alerts, as they need an end.
+func isAlertSeverity(value int) bool {
+	return value > api.MaintenanceSeverity && value <= api.MaxSeverity
+}
+
+func (a Alertmanager) isValid() error {
+	if !a.Enabled {
+		return nil
+	}
+
+	if a.ComponentLabel == "" && len(a.LabelMatchers) == 0 {
+		return ErrNoComponentMatching
+	}
+
+	_, err := a.ParseLabelMatchers()
+	if err != nil {
+		return err
+	}
+
+	_, err = a.ParseSeverityMapping()
+	if err != nil {
+		return err
+	}
+
+	if !isAlertSeverity(a.DefaultSeverity) {
+		return fmt.Errorf("%w: %d", ErrInvalidDefaultSeverity, a.DefaultSeverity)
+	}
+
+	if a.DefaultImpactType == "" {
+		return ErrNoDefaultImpactType
+	}
+
+	return nil
+}
+
 // Config holds all application configuration.
 type Config struct {
+	Storage          string
 	ProvisioningFile string
 	Metrics          Metrics
 	Database         Database
 	Server           Server
+	Webhooks         Webhooks
+	Purge            Purge
+	Incidents        Incidents
+	Subscriptions    Subscriptions
+	Alertmanager     Alertmanager
 	Verbose          int
 	ShutdownTimeout  time.Duration
+	// Args holds the positional arguments, selecting a command like `migrate up` instead of serving.
+	Args []string
 }
 
 // IsValid validates the config by checking own values and calling isValid on sub config objects.
@@ -91,14 +419,14 @@ func (c Config) IsValid() error {
 		return ErrNoProvisioningFile
 	}
 
-	err := c.Metrics.isValid()
+	err := c.isStorageValid()
 	if err != nil {
-		return fmt.Errorf("error validating metrics config: %w", err)
+		return err
 	}
 
-	err = c.Database.isValid()
+	err = c.Metrics.isValid()
 	if err != nil {
-		return fmt.Errorf("error validating database config: %w", err)
+		return fmt.Errorf("error validating metrics config: %w", err)
 	}
 
 	err = c.Server.isValid()
@@ -106,6 +434,53 @@ func (c Config) IsValid() error {
 		return fmt.Errorf("error validating server config: %w", err)
 	}
 
+	err = c.Webhooks.isValid()
+	if err != nil {
+		return fmt.Errorf("error validating webhooks config: %w", err)
+	}
+
+	err = c.Purge.isValid()
+	if err != nil {
+		return fmt.Errorf("error validating purge config: %w", err)
+	}
+
+	err = c.Subscriptions.isValid()
+	if err != nil {
+		return fmt.Errorf("error validating subscriptions config: %w", err)
+	}
+
+	err = c.Alertmanager.isValid()
+	if err != nil {
+		return fmt.Errorf("error validating alertmanager config: %w", err)
+	}
+
+	return nil
+}
+
+// isStorageValid validates the storage and the features depending on it.
+func (c Config) isStorageValid() error {
+	switch c.Storage {
+	case StoragePostgres:
+		err := c.Database.isValid()
+		if err != nil {
+			return fmt.Errorf("error validating database config: %w", err)
+		}
+	case StorageMemory:
+		if c.Subscriptions.Enabled {
+			return fmt.Errorf("%w: subscriptions", ErrRequiresPostgres)
+		}
+
+		if c.Alertmanager.Enabled {
+			return fmt.Errorf("%w: alertmanager", ErrRequiresPostgres)
+		}
+
+		if len(c.Args) > 0 {
+			return fmt.Errorf("%w: commands", ErrRequiresPostgres)
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidStorage, c.Storage)
+	}
+
 	return nil
 }
 
@@ -114,8 +489,15 @@ const (
 
 	verbose = "verbose"
 
+	storage        = "storage"
+	storageDefault = StoragePostgres
+
 	databaseConnectionString        = "database.connection-string"
 	databaseConnectionStringDefault = ""
+	databasePollInterval            = "database.poll-interval"
+	databasePollIntervalDefault     = time.Second
+	databaseMigrateOnStart          = "database.migrate-on-start"
+	databaseMigrateOnStartDefault   = false
 
 	metricsNamespace        = "metrics.namespace"
 	metricsNamespaceDefault = "status_page"
@@ -130,10 +512,101 @@ const (
 	serverSwaggerUIEnabled        = "server.swagger.ui.enabled"
 	serverSwaggerUIEnabledDefault = false
 
+	serverValidationEnabled        = "server.validation.enabled"
+	serverValidationEnabledDefault = true
+	serverValidationStrict         = "server.validation.strict"
+	serverValidationStrictDefault  = false
+
 	serverCorsEnabled        = "server.cors.enabled"
 	serverCorsEnabledDefault = true
 	serverCorsAllowedOrigins = "server.cors.allowed-origins"
 
+	serverAuthEnabled           = "server.auth.enabled"
+	serverAuthEnabledDefault    = false
+	serverAuthIssuer            = "server.auth.issuer"
+	serverAuthIssuerDefault     = ""
+	serverAuthAudience          = "server.auth.audience"
+	serverAuthAudienceDefault   = ""
+	serverAuthJWKSURL           = "server.auth.jwks-url"
+	serverAuthJWKSURLDefault    = ""
+	serverAuthJWKSFile          = "server.auth.jwks-file"
+	serverAuthJWKSFileDefault   = ""
+	serverAuthPublicRead        = "server.auth.public-read"
+	serverAuthPublicReadDefault = true
+	serverAuthScopeClaim        = "server.auth.scope-claim"
+	serverAuthScopeClaimDefault = "scope"
+	serverAuthScopeMapping      = "server.auth.scope-mapping"
+
+	webhooksPollInterval        = "webhooks.poll-interval"
+	webhooksPollIntervalDefault = 5 * time.Second
+	webhooksTimeout             = "webhooks.timeout"
+	webhooksTimeoutDefault      = 10 * time.Second
+	webhooksMaxAttempts         = "webhooks.max-attempts"
+	webhooksMaxAttemptsDefault  = 8
+	webhooksBackoffBase         = "webhooks.backoff-base"
+	webhooksBackoffBaseDefault  = 30 * time.Second
+	webhooksBackoffMax          = "webhooks.backoff-max"
+	webhooksBackoffMaxDefault   = time.Hour
+	webhooksBatchSize           = "webhooks.batch-size"
+	webhooksBatchSizeDefault    = 100
+
+	purgeEnabled               = "purge.enabled"
+	purgeEnabledDefault        = true
+	purgeInterval              = "purge.interval"
+	purgeIntervalDefault       = time.Hour
+	purgeRetention             = "purge.retention"
+	purgeRetentionDefault      = 30 * 24 * time.Hour
+	purgeEventRetention        = "purge.event-retention"
+	purgeEventRetentionDefault = 7 * 24 * time.Hour
+
+	incidentsRequireSeverityBands        = "incidents.require-severity-bands"
+	incidentsRequireSeverityBandsDefault = false
+
+	subscriptionsEnabled             = "subscriptions.enabled"
+	subscriptionsEnabledDefault      = false
+	subscriptionsPublicURL           = "subscriptions.public-url"
+	subscriptionsPublicURLDefault    = ""
+	subscriptionsSecret              = "subscriptions.secret"
+	subscriptionsSecretDefault       = ""
+	subscriptionsConfirmTTL          = "subscriptions.confirm-ttl"
+	subscriptionsConfirmTTLDefault   = 48 * time.Hour
+	subscriptionsPollInterval        = "subscriptions.poll-interval"
+	subscriptionsPollIntervalDefault = 5 * time.Second
+	subscriptionsMaxAttempts         = "subscriptions.max-attempts"
+	subscriptionsMaxAttemptsDefault  = 8
+	subscriptionsBackoffBase         = "subscriptions.backoff-base"
+	subscriptionsBackoffBaseDefault  = time.Minute
+	subscriptionsBackoffMax          = "subscriptions.backoff-max"
+	subscriptionsBackoffMaxDefault   = time.Hour
+	subscriptionsBatchSize           = "subscriptions.batch-size"
+	subscriptionsBatchSizeDefault    = 100
+
+	subscriptionsSMTPAddress         = "subscriptions.smtp.address"
+	subscriptionsSMTPAddressDefault  = "localhost:25"
+	subscriptionsSMTPUsername        = "subscriptions.smtp.username"
+	subscriptionsSMTPUsernameDefault = ""
+	subscriptionsSMTPPassword        = "subscriptions.smtp.password"
+	subscriptionsSMTPPasswordDefault = ""
+	subscriptionsSMTPFrom            = "subscriptions.smtp.from"
+	subscriptionsSMTPFromDefault     = ""
+	subscriptionsSMTPTimeout         = "subscriptions.smtp.timeout"
+	subscriptionsSMTPTimeoutDefault  = 10 * time.Second
+
+	alertmanagerEnabled                  = "alertmanager.enabled"
+	alertmanagerEnabledDefault           = false
+	alertmanagerComponentLabel           = "alertmanager.component-label"
+	alertmanagerComponentLabelDefault    = "component"
+	alertmanagerLabelMatchers            = "alertmanager.label-matchers"
+	alertmanagerSeverityLabel            = "alertmanager.severity-label"
+	alertmanagerSeverityLabelDefault     = "severity"
+	alertmanagerSeverityMapping          = "alertmanager.severity-mapping"
+	alertmanagerDefaultSeverity          = "alertmanager.default-severity"
+	alertmanagerDefaultSeverityDefault   = 66
+	alertmanagerImpactTypeLabel          = "alertmanager.impact-type-label"
+	alertmanagerImpactTypeLabelDefault   = "impact_type"
+	alertmanagerDefaultImpactType        = "alertmanager.default-impact-type"
+	alertmanagerDefaultImpactTypeDefault = "Unknown"
+
 	provisioningFile        = "provisioning-file"
 	provisioningFileDefault = "./provisioning.yaml"
 
@@ -141,12 +614,22 @@ const (
 	shutdownTimeoutDefault = 10 * time.Second
 )
 
-var serverCorsAllowedOriginsDefault = []string{"http://127.0.0.1", "http://localhost"} //nolint:gochecknoglobals
+var (
+	serverCorsAllowedOriginsDefault = []string{"http://127.0.0.1", "http://localhost"} //nolint:gochecknoglobals
+	serverAuthScopeMappingDefault   = []string{}                                       //nolint:gochecknoglobals
+
+	alertmanagerLabelMatchersDefault   = []string{}                                        //nolint:gochecknoglobals
+	alertmanagerSeverityMappingDefault = []string{"critical=100", "warning=66", "info=33"} //nolint:gochecknoglobals
+)
 
 func setDefaults() {
 	viper.SetDefault(verbose, 0)
 
+	viper.SetDefault(storage, storageDefault)
+
 	viper.SetDefault(databaseConnectionString, databaseConnectionStringDefault)
+	viper.SetDefault(databasePollInterval, databasePollIntervalDefault)
+	viper.SetDefault(databaseMigrateOnStart, databaseMigrateOnStartDefault)
 
 	viper.SetDefault(metricsNamespace, metricsNamespaceDefault)
 	viper.SetDefault(metricsSubsystem, metricsSubsystemDefault)
@@ -156,9 +639,60 @@ func setDefaults() {
 
 	viper.SetDefault(serverSwaggerUIEnabled, serverSwaggerUIEnabledDefault)
 
+	viper.SetDefault(serverValidationEnabled, serverValidationEnabledDefault)
+	viper.SetDefault(serverValidationStrict, serverValidationStrictDefault)
+
 	viper.SetDefault(serverCorsEnabled, serverCorsEnabledDefault)
 	viper.SetDefault(serverCorsAllowedOrigins, serverCorsAllowedOriginsDefault)
 
+	viper.SetDefault(serverAuthEnabled, serverAuthEnabledDefault)
+	viper.SetDefault(serverAuthIssuer, serverAuthIssuerDefault)
+	viper.SetDefault(serverAuthAudience, serverAuthAudienceDefault)
+	viper.SetDefault(serverAuthJWKSURL, serverAuthJWKSURLDefault)
+	viper.SetDefault(serverAuthJWKSFile, serverAuthJWKSFileDefault)
+	viper.SetDefault(serverAuthPublicRead, serverAuthPublicReadDefault)
+	viper.SetDefault(serverAuthScopeClaim, serverAuthScopeClaimDefault)
+	viper.SetDefault(serverAuthScopeMapping, serverAuthScopeMappingDefault)
+
+	viper.SetDefault(webhooksPollInterval, webhooksPollIntervalDefault)
+	viper.SetDefault(webhooksTimeout, webhooksTimeoutDefault)
+	viper.SetDefault(webhooksMaxAttempts, webhooksMaxAttemptsDefault)
+	viper.SetDefault(webhooksBackoffBase, webhooksBackoffBaseDefault)
+	viper.SetDefault(webhooksBackoffMax, webhooksBackoffMaxDefault)
+	viper.SetDefault(webhooksBatchSize, webhooksBatchSizeDefault)
+
+	viper.SetDefault(purgeEnabled, purgeEnabledDefault)
+	viper.SetDefault(purgeInterval, purgeIntervalDefault)
+	viper.SetDefault(purgeRetention, purgeRetentionDefault)
+	viper.SetDefault(purgeEventRetention, purgeEventRetentionDefault)
+
+	viper.SetDefault(incidentsRequireSeverityBands, incidentsRequireSeverityBandsDefault)
+
+	viper.SetDefault(subscriptionsEnabled, subscriptionsEnabledDefault)
+	viper.SetDefault(subscriptionsPublicURL, subscriptionsPublicURLDefault)
+	viper.SetDefault(subscriptionsSecret, subscriptionsSecretDefault)
+	viper.SetDefault(subscriptionsConfirmTTL, subscriptionsConfirmTTLDefault)
+	viper.SetDefault(subscriptionsPollInterval, subscriptionsPollIntervalDefault)
+	viper.SetDefault(subscriptionsMaxAttempts, subscriptionsMaxAttemptsDefault)
+	viper.SetDefault(subscriptionsBackoffBase, subscriptionsBackoffBaseDefault)
+	viper.SetDefault(subscriptionsBackoffMax, subscriptionsBackoffMaxDefault)
+	viper.SetDefault(subscriptionsBatchSize, subscriptionsBatchSizeDefault)
+
+	viper.SetDefault(subscriptionsSMTPAddress, subscriptionsSMTPAddressDefault)
+	viper.SetDefault(subscriptionsSMTPUsername, subscriptionsSMTPUsernameDefault)
+	viper.SetDefault(subscriptionsSMTPPassword, subscriptionsSMTPPasswordDefault)
+	viper.SetDefault(subscriptionsSMTPFrom, subscriptionsSMTPFromDefault)
+	viper.SetDefault(subscriptionsSMTPTimeout, subscriptionsSMTPTimeoutDefault)
+
+	viper.SetDefault(alertmanagerEnabled, alertmanagerEnabledDefault)
+	viper.SetDefault(alertmanagerComponentLabel, alertmanagerComponentLabelDefault)
+	viper.SetDefault(alertmanagerLabelMatchers, alertmanagerLabelMatchersDefault)
+	viper.SetDefault(alertmanagerSeverityLabel, alertmanagerSeverityLabelDefault)
+	viper.SetDefault(alertmanagerSeverityMapping, alertmanagerSeverityMappingDefault)
+	viper.SetDefault(alertmanagerDefaultSeverity, alertmanagerDefaultSeverityDefault)
+	viper.SetDefault(alertmanagerImpactTypeLabel, alertmanagerImpactTypeLabelDefault)
+	viper.SetDefault(alertmanagerDefaultImpactType, alertmanagerDefaultImpactTypeDefault)
+
 	viper.SetDefault(provisioningFile, provisioningFileDefault)
 
 	viper.SetDefault(shutdownTimeout, shutdownTimeoutDefault)
@@ -167,7 +701,11 @@ func setDefaults() {
 func setFlags() {
 	pflag.CountP(verbose, "v", "Increase log level")
 
+	pflag.String(storage, storageDefault, "Storage of the resources (postgres, memory).")
+
 	pflag.String(databaseConnectionString, databaseConnectionStringDefault, "Database connection string.")
+	pflag.Duration(databasePollInterval, databasePollIntervalDefault, "Interval to check for events on SQLite.")
+	pflag.Bool(databaseMigrateOnStart, databaseMigrateOnStartDefault, "Apply pending database migrations on start.")
 
 	pflag.String(metricsNamespace, metricsNamespaceDefault, "Metrics namespace.")
 	pflag.String(metricsSubsystem, metricsSubsystemDefault, "Metrics sub system name.")
@@ -177,9 +715,65 @@ func setFlags() {
 
 	pflag.Bool(serverSwaggerUIEnabled, serverSwaggerUIEnabledDefault, "Enable swagger UI for development.")
 
+	pflag.Bool(serverValidationEnabled, serverValidationEnabledDefault, "Validate requests against the OpenAPI spec.")
+	pflag.Bool(serverValidationStrict, serverValidationStrictDefault, "Also validate responses, for development.")
+
 	pflag.Bool(serverCorsEnabled, serverCorsEnabledDefault, "Server handles CORS.")
 	pflag.StringArray(serverCorsAllowedOrigins, serverCorsAllowedOriginsDefault, "Server CORS origins to accept.")
 
+	pflag.Bool(serverAuthEnabled, serverAuthEnabledDefault, "Require authentication for API requests.")
+	pflag.String(serverAuthIssuer, serverAuthIssuerDefault, "Expected issuer of JWT bearer tokens.")
+	pflag.String(serverAuthAudience, serverAuthAudienceDefault, "Expected audience of JWT bearer tokens.")
+	pflag.String(serverAuthJWKSURL, serverAuthJWKSURLDefault, "URL of the JSON Web Key Set to verify tokens.")
+	pflag.String(serverAuthJWKSFile, serverAuthJWKSFileDefault, "File of the JSON Web Key Set to verify tokens.")
+	pflag.Bool(serverAuthPublicRead, serverAuthPublicReadDefault, "Allow unauthenticated read requests.")
+	pflag.String(serverAuthScopeClaim, serverAuthScopeClaimDefault, "Token claim holding scopes or roles.")
+	pflag.StringArray(serverAuthScopeMapping, serverAuthScopeMappingDefault, "Claim value to scope mapping (value=scope).")
+
+	pflag.Duration(webhooksPollInterval, webhooksPollIntervalDefault, "Interval to check for webhook deliveries.")
+	pflag.Duration(webhooksTimeout, webhooksTimeoutDefault, "Timeout of a single webhook delivery attempt.")
+	pflag.Int(webhooksMaxAttempts, webhooksMaxAttemptsDefault, "Attempts before a webhook delivery is dead.")
+	pflag.Duration(webhooksBackoffBase, webhooksBackoffBaseDefault, "Delay after the first failed webhook delivery.")
+	pflag.Duration(webhooksBackoffMax, webhooksBackoffMaxDefault, "Maximum delay between webhook delivery attempts.")
+	pflag.Int(webhooksBatchSize, webhooksBatchSizeDefault, "Events and deliveries handled per webhook poll.")
+
+	pflag.Bool(purgeEnabled, purgeEnabledDefault, "Enable the purge of deleted incidents and components.")
+	pflag.Duration(purgeInterval, purgeIntervalDefault, "Interval to purge deleted incidents and components.")
+	pflag.Duration(purgeRetention, purgeRetentionDefault, "Time deleted incidents and components are kept.")
+	pflag.Duration(purgeEventRetention, purgeEventRetentionDefault, "Time published events are kept for event streams.")
+
+	pflag.Bool(
+		incidentsRequireSeverityBands, incidentsRequireSeverityBandsDefault, "Reject severities above the highest severity.",
+	)
+
+	pflag.Bool(subscriptionsEnabled, subscriptionsEnabledDefault, "Enable email subscriptions.")
+	pflag.String(subscriptionsPublicURL, subscriptionsPublicURLDefault, "Public base URL of the API used in emails.")
+	pflag.String(subscriptionsSecret, subscriptionsSecretDefault, "Secret signing subscription tokens.")
+	pflag.Duration(subscriptionsConfirmTTL, subscriptionsConfirmTTLDefault, "Validity of confirmation links.")
+	pflag.Duration(subscriptionsPollInterval, subscriptionsPollIntervalDefault, "Interval to check for notifications.")
+	pflag.Int(subscriptionsMaxAttempts, subscriptionsMaxAttemptsDefault, "Attempts before a notification is dead.")
+	pflag.Duration(subscriptionsBackoffBase, subscriptionsBackoffBaseDefault, "Delay after the first failed notification.")
+	pflag.Duration(subscriptionsBackoffMax, subscriptionsBackoffMaxDefault, "Maximum delay between notification attempts.")
+	pflag.Int(subscriptionsBatchSize, subscriptionsBatchSizeDefault, "Events and notifications handled per poll.")
+
+	pflag.String(subscriptionsSMTPAddress, subscriptionsSMTPAddressDefault, "SMTP server address (host:port).")
+	pflag.String(subscriptionsSMTPUsername, subscriptionsSMTPUsernameDefault, "SMTP username, empty to skip auth.")
+	pflag.String(subscriptionsSMTPPassword, subscriptionsSMTPPasswordDefault, "SMTP password.")
+	pflag.String(subscriptionsSMTPFrom, subscriptionsSMTPFromDefault, "Sender address of notification emails.")
+	pflag.Duration(subscriptionsSMTPTimeout, subscriptionsSMTPTimeoutDefault, "Timeout of sending a single email.")
+
+	pflag.Bool(alertmanagerEnabled, alertmanagerEnabledDefault, "Enable the Alertmanager webhook receiver.")
+	pflag.String(alertmanagerComponentLabel, alertmanagerComponentLabelDefault, "Alert label naming a component.")
+	pflag.StringArray(alertmanagerLabelMatchers, alertmanagerLabelMatchersDefault,
+		"Component label to alert label matchers (component=alert).")
+	pflag.String(alertmanagerSeverityLabel, alertmanagerSeverityLabelDefault, "Alert label carrying the severity.")
+	pflag.StringArray(alertmanagerSeverityMapping, alertmanagerSeverityMappingDefault,
+		"Alert severity to severity value mapping (severity=value).")
+	pflag.Int(alertmanagerDefaultSeverity, alertmanagerDefaultSeverityDefault, "Severity value of unmapped alerts.")
+	pflag.String(alertmanagerImpactTypeLabel, alertmanagerImpactTypeLabelDefault, "Alert label naming an impact type.")
+	pflag.String(alertmanagerDefaultImpactType, alertmanagerDefaultImpactTypeDefault,
+		"Impact type of alerts without impact type label.")
+
 	pflag.String(provisioningFile, provisioningFileDefault, "YAML file with startup provisioning.")
 
 	pflag.Duration(shutdownTimeout, shutdownTimeoutDefault, "Duration to wait for the server to gracefully shutdown.")
@@ -193,6 +787,8 @@ func buildConfig() *Config {
 	return &Config{
 		Database: Database{
 			ConnectionString: strings.TrimSpace(viper.GetString(databaseConnectionString)),
+			PollInterval:     viper.GetDuration(databasePollInterval),
+			MigrateOnStart:   viper.GetBool(databaseMigrateOnStart),
 		},
 		Server: Server{
 			Address: strings.TrimSpace(viper.GetString(serverAddress)),
@@ -200,6 +796,20 @@ func buildConfig() *Config {
 				Enabled:        viper.GetBool(serverCorsEnabled),
 				AllowedOrigins: viper.GetStringSlice(serverCorsAllowedOrigins),
 			},
+			Auth: Auth{
+				Enabled:      viper.GetBool(serverAuthEnabled),
+				Issuer:       strings.TrimSpace(viper.GetString(serverAuthIssuer)),
+				Audience:     strings.TrimSpace(viper.GetString(serverAuthAudience)),
+				JWKSURL:      strings.TrimSpace(viper.GetString(serverAuthJWKSURL)),
+				JWKSFile:     strings.TrimSpace(viper.GetString(serverAuthJWKSFile)),
+				PublicRead:   viper.GetBool(serverAuthPublicRead),
+				ScopeClaim:   strings.TrimSpace(viper.GetString(serverAuthScopeClaim)),
+				ScopeMapping: viper.GetStringSlice(serverAuthScopeMapping),
+			},
+			Validation: Validation{
+				Enabled: viper.GetBool(serverValidationEnabled),
+				Strict:  viper.GetBool(serverValidationStrict),
+			},
 			SwaggerEnabled: viper.GetBool(serverSwaggerUIEnabled),
 		},
 		Metrics: Metrics{
@@ -207,9 +817,56 @@ func buildConfig() *Config {
 			Subsystem: strings.TrimSpace(viper.GetString(metricsSubsystem)),
 			Address:   strings.TrimSpace(viper.GetString(metricsAddress)),
 		},
+		Webhooks: Webhooks{
+			PollInterval: viper.GetDuration(webhooksPollInterval),
+			Timeout:      viper.GetDuration(webhooksTimeout),
+			MaxAttempts:  viper.GetInt(webhooksMaxAttempts),
+			BackoffBase:  viper.GetDuration(webhooksBackoffBase),
+			BackoffMax:   viper.GetDuration(webhooksBackoffMax),
+			BatchSize:    viper.GetInt(webhooksBatchSize),
+		},
+		Purge: Purge{
+			Enabled:        viper.GetBool(purgeEnabled),
+			Interval:       viper.GetDuration(purgeInterval),
+			Retention:      viper.GetDuration(purgeRetention),
+			EventRetention: viper.GetDuration(purgeEventRetention),
+		},
+		Incidents: Incidents{
+			RequireSeverityBands: viper.GetBool(incidentsRequireSeverityBands),
+		},
+		Subscriptions: Subscriptions{
+			Enabled:      viper.GetBool(subscriptionsEnabled),
+			PublicURL:    strings.TrimSpace(viper.GetString(subscriptionsPublicURL)),
+			Secret:       viper.GetString(subscriptionsSecret),
+			ConfirmTTL:   viper.GetDuration(subscriptionsConfirmTTL),
+			PollInterval: viper.GetDuration(subscriptionsPollInterval),
+			MaxAttempts:  viper.GetInt(subscriptionsMaxAttempts),
+			BackoffBase:  viper.GetDuration(subscriptionsBackoffBase),
+			BackoffMax:   viper.GetDuration(subscriptionsBackoffMax),
+			BatchSize:    viper.GetInt(subscriptionsBatchSize),
+			SMTP: SMTP{
+				Address:  strings.TrimSpace(viper.GetString(subscriptionsSMTPAddress)),
+				Username: strings.TrimSpace(viper.GetString(subscriptionsSMTPUsername)),
+				Password: viper.GetString(subscriptionsSMTPPassword),
+				From:     strings.TrimSpace(viper.GetString(subscriptionsSMTPFrom)),
+				Timeout:  viper.GetDuration(subscriptionsSMTPTimeout),
+			},
+		},
+		Alertmanager: Alertmanager{
+			Enabled:           viper.GetBool(alertmanagerEnabled),
+			ComponentLabel:    strings.TrimSpace(viper.GetString(alertmanagerComponentLabel)),
+			LabelMatchers:     viper.GetStringSlice(alertmanagerLabelMatchers),
+			SeverityLabel:     strings.TrimSpace(viper.GetString(alertmanagerSeverityLabel)),
+			SeverityMapping:   viper.GetStringSlice(alertmanagerSeverityMapping),
+			DefaultSeverity:   viper.GetInt(alertmanagerDefaultSeverity),
+			ImpactTypeLabel:   strings.TrimSpace(viper.GetString(alertmanagerImpactTypeLabel)),
+			DefaultImpactType: strings.TrimSpace(viper.GetString(alertmanagerDefaultImpactType)),
+		},
+		Storage:          strings.TrimSpace(viper.GetString(storage)),
 		ProvisioningFile: strings.TrimSpace(viper.GetString(provisioningFile)),
 		Verbose:          viper.GetInt(verbose),
 		ShutdownTimeout:  viper.GetDuration(shutdownTimeout),
+		Args:             pflag.Args(),
 	}
 }
 
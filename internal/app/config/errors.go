@@ -3,8 +3,15 @@ package config
 import "errors"
 
 var (
+	// ErrInvalidStorage is an error, raised when the configured storage is unknown.
+	ErrInvalidStorage = errors.New("invalid storage")
+	// ErrRequiresPostgres is an error, raised when a feature is enabled, which the memory storage does not support.
+	ErrRequiresPostgres = errors.New("requires postgres storage")
+
 	// ErrNoDBConnectionString is an error, raised when no database connection string is configured.
 	ErrNoDBConnectionString = errors.New("no database connection string")
+	// ErrInvalidPollInterval is an error, raised when the interval to poll the database for events is not positive.
+	ErrInvalidPollInterval = errors.New("invalid database poll interval")
 
 	// ErrNoProvisioningFile is an error, raised when no provisioning file is configured.
 	ErrNoProvisioningFile = errors.New("no provisioning file")
@@ -13,6 +20,54 @@ var (
 	ErrNoServerAddress = errors.New("no server address")
 	// ErrNoAllowedOrigins is an error, raised when no allowed origins is configured.
 	ErrNoAllowedOrigins = errors.New("no allowed origins")
+	// ErrStrictValidationDisabled is an error, raised when responses should be validated without validating requests.
+	ErrStrictValidationDisabled = errors.New("strict validation without validation")
+
+	// ErrNoAuthIssuer is an error, raised when authentication is enabled without a token issuer.
+	ErrNoAuthIssuer = errors.New("no auth issuer")
+	// ErrNoAuthAudience is an error, raised when authentication is enabled without a token audience.
+	ErrNoAuthAudience = errors.New("no auth audience")
+	// ErrNoJWKSSource is an error, raised when authentication is enabled without a JWKS URL or file.
+	ErrNoJWKSSource = errors.New("no JWKS URL or file")
+	// ErrMultipleJWKSSources is an error, raised when both JWKS URL and file are configured.
+	ErrMultipleJWKSSources = errors.New("JWKS URL and file are mutually exclusive")
+	// ErrNoScopeClaim is an error, raised when authentication is enabled without a scope claim.
+	ErrNoScopeClaim = errors.New("no scope claim")
+	// ErrInvalidScopeMapping is an error, raised when a scope mapping entry is malformed.
+	ErrInvalidScopeMapping = errors.New("invalid scope mapping")
+
+	// ErrInvalidWebhookTiming is an error, raised when a webhook interval, timeout or backoff is not positive.
+	ErrInvalidWebhookTiming = errors.New("invalid webhook timing")
+	// ErrInvalidWebhookLimits is an error, raised when the webhook attempts or batch size are below one.
+	ErrInvalidWebhookLimits = errors.New("invalid webhook limits")
+
+	// ErrInvalidPurgeTiming is an error, raised when the purge interval or event retention is not positive
+	// or the retention is negative.
+	ErrInvalidPurgeTiming = errors.New("invalid purge timing")
+
+	// ErrInvalidPublicURL is an error, raised when subscriptions are enabled without an absolute public URL.
+	ErrInvalidPublicURL = errors.New("invalid public URL")
+	// ErrShortSubscriptionSecret is an error, raised when the secret signing subscription tokens is too short.
+	ErrShortSubscriptionSecret = errors.New("subscription secret is shorter than 32 characters")
+	// ErrInvalidSubscriptionTiming is an error, raised when a subscription interval, timeout or backoff is not positive.
+	ErrInvalidSubscriptionTiming = errors.New("invalid subscription timing")
+	// ErrInvalidSubscriptionLimits is an error, raised when the notification attempts or batch size are below one.
+	ErrInvalidSubscriptionLimits = errors.New("invalid subscription limits")
+	// ErrInvalidSMTPAddress is an error, raised when the SMTP server address is not in the form of `host:port`.
+	ErrInvalidSMTPAddress = errors.New("invalid SMTP address")
+	// ErrInvalidSMTPFrom is an error, raised when the sender address of emails is invalid.
+	ErrInvalidSMTPFrom = errors.New("invalid SMTP sender address")
+
+	// ErrNoComponentMatching is an error, raised when the alertmanager receiver can't match alerts to components.
+	ErrNoComponentMatching = errors.New("no alertmanager component label or label matchers")
+	// ErrInvalidLabelMatcher is an error, raised when a label matcher entry is malformed.
+	ErrInvalidLabelMatcher = errors.New("invalid label matcher")
+	// ErrInvalidSeverityMapping is an error, raised when a severity mapping entry is malformed or out of range.
+	ErrInvalidSeverityMapping = errors.New("invalid severity mapping")
+	// ErrInvalidDefaultSeverity is an error, raised when the default severity of alerts is out of range.
+	ErrInvalidDefaultSeverity = errors.New("invalid default severity")
+	// ErrNoDefaultImpactType is an error, raised when the alertmanager receiver has no default impact type.
+	ErrNoDefaultImpactType = errors.New("no default impact type")
 
 	// ErrNoMetricNamespace is an error, raised when no metric namespace is configured.
 	ErrNoMetricNamespace = errors.New("no metrics namespace")
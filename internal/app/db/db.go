@@ -1,28 +1,41 @@
 package db
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"reflect"
+	"time"
 
+	"github.com/SovereignCloudStack/status-page-api/internal/app/db/migrations"
 	"github.com/SovereignCloudStack/status-page-api/internal/app/logging"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
 	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"gopkg.in/yaml.v3"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 // Database wraps the database connection.
 type Database struct {
-	conn   *gorm.DB
-	logger *zerolog.Logger
+	conn       *gorm.DB
+	migrations []Migration
+	logger     *zerolog.Logger
 }
 
-// New creates a new wrapper for the database and initialize it.
+// New creates a new wrapper for the database.
+// Connection strings starting with [SQLitePrefix] open a SQLite database, all others PostgreSQL.
+// The schema is not changed, see [Database.MigrateUp] and [Database.CheckSchema].
 func New(connection string, logger *zerolog.Logger) (*Database, error) {
-	conn, err := gorm.Open(postgres.Open(connection), &gorm.Config{ //nolint:exhaustruct
+	dialector, err := newDialector(connection)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := gorm.Open(dialector, &gorm.Config{ //nolint:exhaustruct
 		Logger:         logging.NewGormLogger(logger),
 		TranslateError: true,
 	})
@@ -30,48 +43,48 @@ func New(connection string, logger *zerolog.Logger) (*Database, error) {
 		return nil, fmt.Errorf("error connecting database: %w", err)
 	}
 
-	err = conn.AutoMigrate(
-		&DbDef.Component{},      //nolint:exhaustruct
-		&DbDef.Phase{},          //nolint:exhaustruct
-		&DbDef.IncidentUpdate{}, //nolint:exhaustruct
-		&DbDef.Incident{},       //nolint:exhaustruct
-		&DbDef.ImpactType{},     //nolint:exhaustruct
-		&DbDef.Impact{},         //nolint:exhaustruct
-		&DbDef.Severity{},       //nolint:exhaustruct
-	)
+	return NewWithConnection(conn, logger)
+}
+
+// NewWithConnection creates a new wrapper for an established database connection.
+func NewWithConnection(conn *gorm.DB, logger *zerolog.Logger) (*Database, error) {
+	dialectMigrations, err := fs.Sub(migrations.FS, conn.Dialector.Name())
 	if err != nil {
-		return nil, fmt.Errorf("error migrating database structure: %w", err)
+		return nil, fmt.Errorf("error loading migrations: %w", err)
+	}
+
+	schemaMigrations, err := LoadMigrations(dialectMigrations)
+	if err != nil {
+		return nil, fmt.Errorf("error loading migrations: %w", err)
 	}
 
 	return &Database{
-		conn:   conn,
-		logger: logger,
+		conn:       conn,
+		migrations: schemaMigrations,
+		logger:     logger,
 	}, nil
 }
 
-func provision[S ~[]E, E any](data S, dbTx *gorm.DB, logger *zerolog.Logger) error {
-	var (
-		limit  = 5
-		target = make(S, limit)
-	)
-
-	reflectType := reflect.TypeOf(data).Elem()
-	provisioningLogger := logger.With().Str("function", "provision").Str("type", reflectType.Name()).Logger()
-
-	// get from database if exist.
-	res := dbTx.
-		Limit(limit). // do not load all.
-		Find(&target)
-	if res.Error != nil {
-		if !errors.Is(res.Error, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("error getting %s: %w", reflectType.Name(), res.Error)
-		}
+// provision creates the resources of a type, if none exist yet.
+func provision[E any](
+	resourceType string,
+	data []E,
+	count func() (int, error),
+	create func(resource *E) error,
+	logger *zerolog.Logger,
+) error {
+	provisioningLogger := logger.With().Str("function", "provision").Str("type", resourceType).Logger()
+
+	// get from storage if exist.
+	existing, err := count()
+	if err != nil {
+		return fmt.Errorf("error getting %s: %w", resourceType, err)
 	}
 
-	provisioningLogger.Debug().Interface("found", target).Send()
+	provisioningLogger.Debug().Int("found", existing).Send()
 
 	// check if already provisioned.
-	if len(target) != 0 {
+	if existing != 0 {
 		provisioningLogger.Info().Msg("already provisioned")
 
 		return nil
@@ -80,16 +93,115 @@ func provision[S ~[]E, E any](data S, dbTx *gorm.DB, logger *zerolog.Logger) err
 	provisioningLogger.Info().Msg("provisioning")
 	provisioningLogger.Debug().Interface("data", data).Send()
 
-	// create new data in db.
-	res = dbTx.Create(data)
-	if res.Error != nil {
-		return fmt.Errorf("error creating data: %w", res.Error)
+	// create new data in storage.
+	for index := range data {
+		err = create(&data[index])
+		if err != nil {
+			return fmt.Errorf("error creating data: %w", err)
+		}
 	}
 
 	return nil
 }
 
-func provisionPhases(phases []DbDef.Phase, dbTx *gorm.DB, logger *zerolog.Logger) error {
+// countOf adapts a list function to count the resources for [provision].
+func countOf[E any](list func(page storage.Page) ([]E, *storage.Cursor, error)) func() (int, error) {
+	return func() (int, error) {
+		resources, _, err := list(storage.Page{Limit: 0, After: nil})
+
+		return len(resources), err
+	}
+}
+
+// ErrUnknownGroupComponent is returned, when a provisioned component group references an unknown component.
+var ErrUnknownGroupComponent = errors.New("unknown component in component group")
+
+// provisionedGroup is a component group of the provisioning file.
+// Members are referenced by the display name of the component, nested groups are declared in place.
+type provisionedGroup struct {
+	DisplayName string             `yaml:"displayname"`
+	Description *string            `yaml:"description"`
+	Components  []string           `yaml:"components"`
+	Groups      []provisionedGroup `yaml:"groups"`
+}
+
+// provisionComponentGroups creates the nested component groups, ordered like in the provisioning file.
+func provisionComponentGroups(groups []provisionedGroup, repo storage.Repository, logger *zerolog.Logger) error {
+	components, _, err := repo.ListComponents(nil, nil, storage.Page{Limit: 0, After: nil})
+	if err != nil {
+		return fmt.Errorf("error getting components: %w", err)
+	}
+
+	componentIDs := make(map[string]DbDef.ID, len(components))
+
+	for _, component := range components {
+		if component.DisplayName != nil {
+			componentIDs[*component.DisplayName] = component.ID
+		}
+	}
+
+	flattened, err := flattenGroups(groups, nil, componentIDs)
+	if err != nil {
+		return err
+	}
+
+	countGroups := func() (int, error) {
+		existing, listErr := repo.ListComponentGroups()
+
+		return len(existing), listErr //nolint:wrapcheck
+	}
+
+	return provision("ComponentGroup", flattened, countGroups, repo.CreateComponentGroup, logger)
+}
+
+// flattenGroups converts the groups nested in the parent, each followed by its nested groups.
+func flattenGroups(
+	groups []provisionedGroup,
+	parentID *DbDef.ID,
+	componentIDs map[string]DbDef.ID,
+) ([]DbDef.ComponentGroup, error) {
+	flattened := []DbDef.ComponentGroup{}
+
+	for groupIndex, group := range groups {
+		groupID := uuid.New()
+		order := groupIndex
+		members := make([]DbDef.ComponentGroupMember, 0, len(group.Components))
+
+		for _, name := range group.Components {
+			componentID, found := componentIDs[name]
+			if !found {
+				return nil, fmt.Errorf("%w: `%s` in `%s`", ErrUnknownGroupComponent, name, group.DisplayName)
+			}
+
+			members = append(members, DbDef.ComponentGroupMember{
+				Group:       nil,
+				Component:   nil,
+				GroupID:     &groupID,
+				ComponentID: &componentID,
+			})
+		}
+
+		flattened = append(flattened, DbDef.ComponentGroup{
+			DisplayName: &group.DisplayName,
+			Description: group.Description,
+			ParentID:    parentID,
+			Order:       &order,
+			Members:     &members,
+			Model:       DbDef.Model{ID: groupID},
+		})
+
+		nested, err := flattenGroups(group.Groups, &groupID, componentIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		flattened = append(flattened, nested...)
+	}
+
+	return flattened, nil
+}
+
+func provisionPhases(phases []DbDef.Phase, repo storage.Repository, logger *zerolog.Logger) error {
 	initialPhaseGeneration := 1
 
 	// set initial phase and orders.
@@ -100,23 +212,27 @@ func provisionPhases(phases []DbDef.Phase, dbTx *gorm.DB, logger *zerolog.Logger
 		phases[phaseIndex].Generation = &initialPhaseGeneration
 	}
 
-	err := provision(phases, dbTx, logger)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return provision("Phase", phases, repo.CurrentPhaseGeneration, func(phase *DbDef.Phase) error {
+		return repo.CreatePhases([]DbDef.Phase{*phase}) //nolint:wrapcheck
+	}, logger)
 }
 
 // Provision initializes the database with the contents of the provision file.
 func (db *Database) Provision(filename string) error {
-	provisioningLogger := db.logger.With().Str("method", "Provisioning").Logger()
+	return Provision(storage.NewGorm(db.conn), filename, db.logger)
+}
+
+// Provision initializes the storage with the contents of the provision file.
+// Each resource type is only provisioned, if the storage has no resources of the type yet.
+func Provision(store storage.Storage, filename string, logger *zerolog.Logger) error {
+	provisioningLogger := logger.With().Str("method", "Provisioning").Logger()
 
 	type ProvisionedResources struct {
-		Components  []DbDef.Component  `yaml:"components"`
-		ImpactTypes []DbDef.ImpactType `yaml:"impactTypes"`
-		Phases      []DbDef.Phase      `yaml:"phases"`
-		Severities  []DbDef.Severity   `yaml:"severities"`
+		Components      []DbDef.Component  `yaml:"components"`
+		ComponentGroups []provisionedGroup `yaml:"componentGroups"`
+		ImpactTypes     []DbDef.ImpactType `yaml:"impactTypes"`
+		Phases          []DbDef.Phase      `yaml:"phases"`
+		Severities      []DbDef.Severity   `yaml:"severities"`
 	}
 
 	provisioningLogger.Debug().Str("provisioningFile", filename).Msg("opening provisioning file")
@@ -137,27 +253,39 @@ func (db *Database) Provision(filename string) error {
 	provisioningLogger.Info().Msg("read resources from provisioning file")
 	provisioningLogger.Debug().Interface("resources", resources).Send()
 
-	err = db.conn.Transaction(func(dbTx *gorm.DB) error {
+	err = store.Transaction(context.Background(), func(repo storage.Repository) error {
 		var txErr error
 
-		txErr = provision(resources.Components, dbTx, &provisioningLogger)
+		listComponents := func(page storage.Page) ([]*DbDef.Component, *storage.Cursor, error) {
+			return repo.ListComponents(nil, nil, page) //nolint:wrapcheck
+		}
+
+		txErr = provision("Component", resources.Components, countOf(listComponents),
+			repo.CreateComponent, &provisioningLogger)
+		if txErr != nil {
+			return fmt.Errorf("error provisioning components: %w", txErr)
+		}
+
+		txErr = provisionComponentGroups(resources.ComponentGroups, repo, &provisioningLogger)
 		if txErr != nil {
-			return fmt.Errorf("error provisioning components: %w", err)
+			return fmt.Errorf("error provisioning component groups: %w", txErr)
 		}
 
-		txErr = provision(resources.ImpactTypes, dbTx, &provisioningLogger)
+		txErr = provision("ImpactType", resources.ImpactTypes, countOf(repo.ListImpactTypes),
+			repo.CreateImpactType, &provisioningLogger)
 		if txErr != nil {
-			return fmt.Errorf("error provisioning impact types: %w", err)
+			return fmt.Errorf("error provisioning impact types: %w", txErr)
 		}
 
-		txErr = provisionPhases(resources.Phases, dbTx, &provisioningLogger)
+		txErr = provisionPhases(resources.Phases, repo, &provisioningLogger)
 		if txErr != nil {
-			return fmt.Errorf("error provisioning phases: %w", err)
+			return fmt.Errorf("error provisioning phases: %w", txErr)
 		}
 
-		txErr = provision(resources.Severities, dbTx, &provisioningLogger)
+		txErr = provision("Severity", resources.Severities, countOf(repo.ListSeverities),
+			repo.CreateSeverity, &provisioningLogger)
 		if txErr != nil {
-			return fmt.Errorf("error provisioning severities: %w", err)
+			return fmt.Errorf("error provisioning severities: %w", txErr)
 		}
 
 		return nil
@@ -169,7 +297,41 @@ func (db *Database) Provision(filename string) error {
 	return nil
 }
 
+// IsSQLite reports, if the wrapped database is SQLite.
+func (db *Database) IsSQLite() bool {
+	return db.conn.Dialector.Name() == DbDef.DialectSQLite
+}
+
 // GetDBCon gets the wrapped database connection.
 func (db *Database) GetDBCon() *gorm.DB {
 	return db.conn
 }
+
+// APIKeyByHash implements [auth.APIKeyStore] and retrieves an API key by the hash of its secret.
+func (db *Database) APIKeyByHash(ctx context.Context, hash string) (*auth.StoredAPIKey, error) {
+	apiKey, err := DbDef.GetAPIKeyByHash(db.conn.WithContext(ctx), hash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth.ErrUnknownAPIKey
+		}
+
+		return nil, fmt.Errorf("error loading API key: %w", err)
+	}
+
+	return apiKey.ToStoredAPIKey() //nolint:wrapcheck
+}
+
+// MarkAPIKeyUsed implements [auth.APIKeyStore] and records the last usage of an API key.
+func (db *Database) MarkAPIKeyUsed(ctx context.Context, id string, usedAt time.Time) error {
+	apiKeyID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("error parsing API key ID: %w", err)
+	}
+
+	err = DbDef.MarkAPIKeyUsed(db.conn.WithContext(ctx), apiKeyID, usedAt)
+	if err != nil {
+		return fmt.Errorf("error updating API key usage: %w", err)
+	}
+
+	return nil
+}
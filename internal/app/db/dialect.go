@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	// SQLitePrefix marks connection strings of SQLite databases, followed by the path of the database file.
+	SQLitePrefix = "sqlite:"
+
+	// sqliteOptions enforce foreign keys and let readers proceed and writers wait while another one writes.
+	// Transactions take the write lock when they begin, so they wait instead of failing when writing later.
+	sqliteOptions = "_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate"
+)
+
+// IsSQLite reports, if the connection string refers to a SQLite database.
+func IsSQLite(connection string) bool {
+	return strings.HasPrefix(connection, SQLitePrefix)
+}
+
+// newDialector selects the dialect by the connection string.
+func newDialector(connection string) (gorm.Dialector, error) { //nolint:ireturn
+	if !IsSQLite(connection) {
+		return postgres.Open(connection), nil
+	}
+
+	dsn := strings.TrimPrefix(connection, SQLitePrefix)
+	if strings.Contains(dsn, "?") {
+		dsn += "&" + sqliteOptions
+	} else {
+		dsn += "?" + sqliteOptions
+	}
+
+	conn, err := sql.Open(sqlite.DriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening SQLite database: %w", err)
+	}
+
+	return sqlite.Dialector{ //nolint:exhaustruct
+		Conn: &utcConnPool{conn: conn},
+	}, nil
+}
+
+// inUTC converts time arguments to UTC.
+// SQLite stores times as text and compares them as such, which requires the same time zone for all of them.
+func inUTC(args []interface{}) []interface{} {
+	converted := slices.Clone(args)
+
+	for index, arg := range args {
+		switch typedArg := arg.(type) {
+		case time.Time:
+			converted[index] = typedArg.UTC()
+		case *time.Time:
+			if typedArg != nil {
+				converted[index] = typedArg.UTC()
+			}
+		}
+	}
+
+	return converted
+}
+
+// utcConnPool is a [gorm.ConnPool] passing all times in UTC, see [inUTC].
+type utcConnPool struct {
+	conn *sql.DB
+}
+
+func (p *utcConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.conn.PrepareContext(ctx, query) //nolint:wrapcheck
+}
+
+func (p *utcConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.conn.ExecContext(ctx, query, inUTC(args)...) //nolint:wrapcheck
+}
+
+func (p *utcConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.conn.QueryContext(ctx, query, inUTC(args)...) //nolint:wrapcheck
+}
+
+func (p *utcConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.conn.QueryRowContext(ctx, query, inUTC(args)...)
+}
+
+// BeginTx implements [gorm.ConnPoolBeginner].
+func (p *utcConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) { //nolint:ireturn
+	tx, err := p.conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return &utcTx{tx: tx}, nil
+}
+
+// GetDBConn implements [gorm.GetDBConnector].
+func (p *utcConnPool) GetDBConn() (*sql.DB, error) {
+	return p.conn, nil
+}
+
+// utcTx is a transaction of the [utcConnPool].
+type utcTx struct {
+	tx *sql.Tx
+}
+
+func (t *utcTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return t.tx.PrepareContext(ctx, query) //nolint:wrapcheck
+}
+
+func (t *utcTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, query, inUTC(args)...) //nolint:wrapcheck
+}
+
+func (t *utcTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, query, inUTC(args)...) //nolint:wrapcheck
+}
+
+func (t *utcTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.tx.QueryRowContext(ctx, query, inUTC(args)...)
+}
+
+// Commit implements [gorm.TxCommitter].
+func (t *utcTx) Commit() error {
+	return t.tx.Commit() //nolint:wrapcheck
+}
+
+// Rollback implements [gorm.TxCommitter].
+func (t *utcTx) Rollback() error {
+	return t.tx.Rollback() //nolint:wrapcheck
+}
@@ -0,0 +1,339 @@
+package db
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"gorm.io/gorm"
+)
+
+const (
+	// MigrationLock is the key of the transaction level advisory lock held while migrating.
+	// Only one instance migrates at a time, others wait and find the migrations applied.
+	MigrationLock = 0x5354415455530002
+
+	createSchemaVersionTable = `CREATE TABLE IF NOT EXISTS "schema_version" (
+	"version" bigint PRIMARY KEY,
+	"name" text NOT NULL,
+	"applied_at" timestamptz NOT NULL
+)`
+	createSQLiteSchemaVersionTable = `CREATE TABLE IF NOT EXISTS "schema_version" (
+	"version" integer PRIMARY KEY,
+	"name" text NOT NULL,
+	"applied_at" datetime NOT NULL
+)`
+)
+
+var (
+	// ErrInvalidMigrationName is an error, raised when a migration file is not named `<version>_<name>.<up|down>.sql`.
+	ErrInvalidMigrationName = errors.New("invalid migration name")
+	// ErrDuplicateMigration is an error, raised when two migrations share a version.
+	ErrDuplicateMigration = errors.New("duplicate migration version")
+	// ErrIncompleteMigration is an error, raised when a migration lacks its up or down file.
+	ErrIncompleteMigration = errors.New("incomplete migration")
+	// ErrUnknownMigration is an error, raised when the database has a migration applied, which is not known.
+	ErrUnknownMigration = errors.New("unknown migration")
+	// ErrSchemaBehind is an error, raised when migrations are pending.
+	ErrSchemaBehind = errors.New("database schema is behind")
+
+	migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+)
+
+// Migration is a single versioned change of the database schema.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState is a [Migration] with the time it was applied, nil if it is pending.
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// schemaVersion records an applied [Migration].
+type schemaVersion struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+// TableName implements [gorm.Tabler], the table is named in singular.
+func (schemaVersion) TableName() string {
+	return "schema_version"
+}
+
+// LoadMigrations reads all migrations of the file system, ordered by version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	migrations := map[int64]*Migration{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMigrationName, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMigrationName, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", entry.Name(), err)
+		}
+
+		migration, found := migrations[version]
+		if !found {
+			migration = &Migration{Version: version, Name: matches[2], Up: "", Down: ""}
+			migrations[version] = migration
+		}
+
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateMigration, version)
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	ordered := make([]Migration, 0, len(migrations))
+
+	for _, migration := range migrations {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrIncompleteMigration, migration.Version, migration.Name)
+		}
+
+		ordered = append(ordered, *migration)
+	}
+
+	slices.SortFunc(ordered, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return ordered, nil
+}
+
+// lockSchema takes the migration lock for the transaction and returns the applied migrations by version.
+// SQLite locks the database for the write transaction itself.
+func lockSchema(dbTx *gorm.DB) (map[int64]schemaVersion, error) {
+	createTable := createSQLiteSchemaVersionTable
+
+	if dbTx.Dialector.Name() != DbDef.DialectSQLite {
+		err := dbTx.Exec("SELECT pg_advisory_xact_lock(?)", MigrationLock).Error
+		if err != nil {
+			return nil, fmt.Errorf("error locking schema: %w", err)
+		}
+
+		createTable = createSchemaVersionTable
+	}
+
+	err := dbTx.Exec(createTable).Error
+	if err != nil {
+		return nil, fmt.Errorf("error creating schema version table: %w", err)
+	}
+
+	return appliedVersions(dbTx)
+}
+
+func appliedVersions(dbTx *gorm.DB) (map[int64]schemaVersion, error) {
+	var versions []schemaVersion
+
+	err := dbTx.Order("version").Find(&versions).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading schema versions: %w", err)
+	}
+
+	applied := make(map[int64]schemaVersion, len(versions))
+
+	for _, version := range versions {
+		applied[version.Version] = version
+	}
+
+	return applied, nil
+}
+
+// migrateUp applies all pending migrations in one transaction and returns them.
+func migrateUp(conn *gorm.DB, migrations []Migration, now time.Time) ([]Migration, error) {
+	var applied []Migration
+
+	err := conn.Transaction(func(dbTx *gorm.DB) error {
+		versions, err := lockSchema(dbTx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if _, found := versions[migration.Version]; found {
+				continue
+			}
+
+			// executed directly, as gorm would interpret placeholders in the statements.
+			_, err = dbTx.Statement.ConnPool.ExecContext(dbTx.Statement.Context, migration.Up)
+			if err != nil {
+				return fmt.Errorf("error applying migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			err = dbTx.Create(&schemaVersion{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: now,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("error recording migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error in migration transaction: %w", err)
+	}
+
+	return applied, nil
+}
+
+// migrateDown reverts the latest steps applied migrations in one transaction and returns them.
+func migrateDown(conn *gorm.DB, migrations []Migration, steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	err := conn.Transaction(func(dbTx *gorm.DB) error {
+		versions, err := lockSchema(dbTx)
+		if err != nil {
+			return err
+		}
+
+		known := make(map[int64]Migration, len(migrations))
+		for _, migration := range migrations {
+			known[migration.Version] = migration
+		}
+
+		applied := slices.Sorted(maps.Keys(versions))
+
+		for index := len(applied) - 1; index >= 0 && len(reverted) < steps; index-- {
+			// migrations newer than this binary can only be reverted by a newer one.
+			migration, found := known[applied[index]]
+			if !found {
+				return fmt.Errorf("%w: %d", ErrUnknownMigration, applied[index])
+			}
+
+			_, err = dbTx.Statement.ConnPool.ExecContext(dbTx.Statement.Context, migration.Down)
+			if err != nil {
+				return fmt.Errorf("error reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			err = dbTx.Delete(&schemaVersion{}, "version = ?", migration.Version).Error //nolint:exhaustruct
+			if err != nil {
+				return fmt.Errorf("error removing migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			reverted = append(reverted, migration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error in migration transaction: %w", err)
+	}
+
+	return reverted, nil
+}
+
+// migrationStates lists all known migrations and whether they are applied.
+func migrationStates(conn *gorm.DB, migrations []Migration) ([]MigrationState, error) {
+	versions := map[int64]schemaVersion{}
+
+	if conn.Migrator().HasTable(&schemaVersion{}) { //nolint:exhaustruct
+		var err error
+
+		versions, err = appliedVersions(conn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+
+	for _, migration := range migrations {
+		state := MigrationState{Migration: migration, AppliedAt: nil}
+
+		if version, found := versions[migration.Version]; found {
+			state.AppliedAt = &version.AppliedAt
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func latestVersion(migrations []Migration) int64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+
+	return migrations[len(migrations)-1].Version
+}
+
+// MigrateUp applies all pending migrations and returns them.
+func (db *Database) MigrateUp() ([]Migration, error) {
+	return migrateUp(db.conn, db.migrations, time.Now())
+}
+
+// MigrateDown reverts the latest steps applied migrations and returns them.
+func (db *Database) MigrateDown(steps int) ([]Migration, error) {
+	return migrateDown(db.conn, db.migrations, steps)
+}
+
+// MigrationStatus lists all known migrations and whether they are applied.
+func (db *Database) MigrationStatus() ([]MigrationState, error) {
+	return migrationStates(db.conn, db.migrations)
+}
+
+// CheckSchema returns [ErrSchemaBehind], if any migration is pending.
+func (db *Database) CheckSchema() error {
+	states, err := db.MigrationStatus()
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			pending++
+		}
+	}
+
+	if pending > 0 {
+		return fmt.Errorf("%w: %d pending migrations up to version %d",
+			ErrSchemaBehind, pending, latestVersion(db.migrations))
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package db_test
+
+import (
+	"database/sql"
+	"io/fs"
+	"regexp"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/db"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/db/migrations"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Migrations", func() {
+	var (
+		// sub loggers
+		_, gormLogger, _ = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// actual functions under test
+		dbWrapper *db.Database
+
+		// known migrations
+		knownMigrations []db.Migration
+
+		// expected SQL
+		expectedLock = regexp.
+				QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)
+		expectedSchemaVersionTable = regexp.
+						QuoteMeta(`CREATE TABLE IF NOT EXISTS "schema_version"`)
+		expectedSchemaVersionQuery = regexp.
+						QuoteMeta(`SELECT * FROM "schema_version" ORDER BY version`)
+		expectedSchemaVersionInsert = regexp.
+						QuoteMeta(`INSERT INTO "schema_version" ("version","name","applied_at") VALUES ($1,$2,$3)`)
+		expectedSchemaVersionDelete = regexp.
+						QuoteMeta(`DELETE FROM "schema_version" WHERE version = $1`)
+		expectedHasTableQuery = regexp.
+					QuoteMeta(`SELECT count(*) FROM information_schema.tables`)
+
+		expectLockedSchema = func(versionRows *sqlmock.Rows) {
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectExec(expectedLock).WithArgs(db.MigrationLock).WillReturnResult(sqlmock.NewResult(0, 0))
+			sqlMock.ExpectExec(expectedSchemaVersionTable).WillReturnResult(sqlmock.NewResult(0, 0))
+			sqlMock.ExpectQuery(expectedSchemaVersionQuery).WillReturnRows(versionRows)
+		}
+		newVersionRows = func() *sqlmock.Rows {
+			return sqlmock.NewRows([]string{"version", "name", "applied_at"})
+		}
+	)
+
+	BeforeEach(func() {
+		// setup database and mock before each test
+		var (
+			gormDB *gorm.DB
+			err    error
+		)
+
+		sqlDB, sqlMock, gormDB = test.MustMockGorm(gormLogger)
+
+		dbWrapper, err = db.NewWithConnection(gormDB, gormLogger)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		postgresMigrations, err := fs.Sub(migrations.FS, "postgres")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		knownMigrations, err = db.LoadMigrations(postgresMigrations)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+	})
+
+	Describe("LoadMigrations", func() {
+		It("should load the embedded migrations in order", func() {
+			// Assert
+			Ω(knownMigrations).ShouldNot(BeEmpty())
+			Ω(knownMigrations[0].Version).Should(BeEquivalentTo(1))
+			Ω(knownMigrations[0].Name).Should(Equal("initial_schema"))
+
+			for index := 1; index < len(knownMigrations); index++ {
+				Ω(knownMigrations[index].Version).Should(BeNumerically(">", knownMigrations[index-1].Version))
+			}
+		})
+
+		It("should order by version", func() {
+			// Arrange
+			fsys := fstest.MapFS{
+				"0010_second.up.sql":   {Data: []byte("UP 10")},
+				"0010_second.down.sql": {Data: []byte("DOWN 10")},
+				"0002_first.up.sql":    {Data: []byte("UP 2")},
+				"0002_first.down.sql":  {Data: []byte("DOWN 2")},
+				"README.md":            {Data: []byte("ignored")},
+			}
+
+			// Act
+			loaded, err := db.LoadMigrations(fsys)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(loaded).Should(Equal([]db.Migration{
+				{Version: 2, Name: "first", Up: "UP 2", Down: "DOWN 2"},
+				{Version: 10, Name: "second", Up: "UP 10", Down: "DOWN 10"},
+			}))
+		})
+
+		It("should reject invalid names", func() {
+			// Act
+			_, err := db.LoadMigrations(fstest.MapFS{"first.up.sql": {Data: []byte("UP")}})
+
+			// Assert
+			Ω(err).Should(MatchError(db.ErrInvalidMigrationName))
+		})
+
+		It("should reject migrations without down", func() {
+			// Act
+			_, err := db.LoadMigrations(fstest.MapFS{"0001_first.up.sql": {Data: []byte("UP")}})
+
+			// Assert
+			Ω(err).Should(MatchError(db.ErrIncompleteMigration))
+		})
+
+		It("should reject duplicate versions", func() {
+			// Arrange
+			fsys := fstest.MapFS{
+				"0001_first.up.sql":   {Data: []byte("UP")},
+				"0001_first.down.sql": {Data: []byte("DOWN")},
+				"0001_other.up.sql":   {Data: []byte("UP")},
+			}
+
+			// Act
+			_, err := db.LoadMigrations(fsys)
+
+			// Assert
+			Ω(err).Should(MatchError(db.ErrDuplicateMigration))
+		})
+	})
+
+	Describe("MigrateUp", func() {
+		It("should apply all pending migrations", func() {
+			// Arrange
+			expectLockedSchema(newVersionRows())
+
+			for _, migration := range knownMigrations {
+				sqlMock.ExpectExec(regexp.QuoteMeta(migration.Up)).WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.
+					ExpectExec(expectedSchemaVersionInsert).
+					WithArgs(migration.Version, migration.Name, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			sqlMock.ExpectCommit()
+
+			// Act
+			applied, err := dbWrapper.MigrateUp()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(applied).Should(Equal(knownMigrations))
+		})
+
+		It("should skip applied migrations", func() {
+			// Arrange
+			versionRows := newVersionRows()
+			for _, migration := range knownMigrations {
+				versionRows.AddRow(migration.Version, migration.Name, time.Now())
+			}
+
+			expectLockedSchema(versionRows)
+			sqlMock.ExpectCommit()
+
+			// Act
+			applied, err := dbWrapper.MigrateUp()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(applied).Should(BeEmpty())
+		})
+
+		It("should roll back on errors", func() {
+			// Arrange
+			expectLockedSchema(newVersionRows())
+			sqlMock.ExpectExec(regexp.QuoteMeta(knownMigrations[0].Up)).WillReturnError(test.ErrTestError)
+			sqlMock.ExpectRollback()
+
+			// Act
+			applied, err := dbWrapper.MigrateUp()
+
+			// Assert
+			Ω(err).Should(MatchError(test.ErrTestError))
+			Ω(applied).Should(BeNil())
+		})
+	})
+
+	Describe("MigrateDown", func() {
+		It("should revert the latest migration", func() {
+			// Arrange
+			latest := knownMigrations[len(knownMigrations)-1]
+			versionRows := newVersionRows()
+
+			for _, migration := range knownMigrations {
+				versionRows.AddRow(migration.Version, migration.Name, time.Now())
+			}
+
+			expectLockedSchema(versionRows)
+			sqlMock.ExpectExec(regexp.QuoteMeta(latest.Down)).WillReturnResult(sqlmock.NewResult(0, 0))
+			sqlMock.ExpectExec(expectedSchemaVersionDelete).WithArgs(latest.Version).WillReturnResult(sqlmock.NewResult(0, 1))
+			sqlMock.ExpectCommit()
+
+			// Act
+			reverted, err := dbWrapper.MigrateDown(1)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(reverted).Should(Equal([]db.Migration{latest}))
+		})
+
+		It("should refuse to revert unknown migrations", func() {
+			// Arrange
+			expectLockedSchema(newVersionRows().AddRow(9999, "from_the_future", time.Now()))
+			sqlMock.ExpectRollback()
+
+			// Act
+			_, err := dbWrapper.MigrateDown(1)
+
+			// Assert
+			Ω(err).Should(MatchError(db.ErrUnknownMigration))
+		})
+	})
+
+	Describe("CheckSchema", func() {
+		It("should fail without schema version table", func() {
+			// Arrange
+			sqlMock.ExpectQuery(expectedHasTableQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+			// Act
+			err := dbWrapper.CheckSchema()
+
+			// Assert
+			Ω(err).Should(MatchError(db.ErrSchemaBehind))
+		})
+
+		It("should succeed with all migrations applied", func() {
+			// Arrange
+			versionRows := newVersionRows()
+			for _, migration := range knownMigrations {
+				versionRows.AddRow(migration.Version, migration.Name, time.Now())
+			}
+
+			sqlMock.ExpectQuery(expectedHasTableQuery).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			sqlMock.ExpectQuery(expectedSchemaVersionQuery).WillReturnRows(versionRows)
+
+			// Act
+			err := dbWrapper.CheckSchema()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,13 @@
+// Package migrations embeds the versioned SQL migrations of the database schema.
+//
+// Every dialect has its own directory of migrations, named `<version>_<name>.up.sql` and `<version>_<name>.down.sql`.
+// Both dialects share the versions, every schema change adds a migration to each of them.
+// Applied migrations must never change, schema changes are new migrations with the next version.
+package migrations
+
+import "embed"
+
+// FS holds all migrations, in the directories `postgres` and `sqlite`.
+//
+//go:embed postgres/*.sql sqlite/*.sql
+var FS embed.FS //nolint:gochecknoglobals
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS "alert_incidents";
+DROP TABLE IF EXISTS "notifications";
+DROP TABLE IF EXISTS "subscribers";
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "webhook_subscriptions";
+DROP TABLE IF EXISTS "events";
+DROP TABLE IF EXISTS "audit_entries";
+DROP TABLE IF EXISTS "api_keys";
+DROP TABLE IF EXISTS "severities";
+DROP TABLE IF EXISTS "impacts";
+DROP TABLE IF EXISTS "impact_types";
+DROP TABLE IF EXISTS "incident_updates";
+DROP TABLE IF EXISTS "incidents";
+DROP TABLE IF EXISTS "phases";
+DROP TABLE IF EXISTS "components";
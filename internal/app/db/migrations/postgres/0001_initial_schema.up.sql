@@ -0,0 +1,185 @@
+-- Schema as previously created by GORM AutoMigrate.
+-- Every statement is guarded, so databases created before versioned migrations adopt this version unchanged.
+
+CREATE TABLE IF NOT EXISTS "components" (
+    "id" uuid,
+    "display_name" text,
+    "labels" jsonb,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "phases" (
+    "name" text NOT NULL,
+    "generation" bigint,
+    "order" bigint,
+    PRIMARY KEY ("generation", "order")
+);
+
+CREATE TABLE IF NOT EXISTS "incidents" (
+    "id" uuid,
+    "display_name" text,
+    "description" text,
+    "began_at" timestamptz,
+    "ended_at" timestamptz,
+    "phase_generation" bigint,
+    "phase_order" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_incidents_phase" FOREIGN KEY ("phase_generation", "phase_order")
+        REFERENCES "phases" ("generation", "order")
+);
+
+CREATE TABLE IF NOT EXISTS "incident_updates" (
+    "incident_id" uuid,
+    "order" bigint,
+    "display_name" text,
+    "description" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("incident_id", "order"),
+    CONSTRAINT "fk_incidents_updates" FOREIGN KEY ("incident_id") REFERENCES "incidents" ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "impact_types" (
+    "id" uuid,
+    "display_name" text NOT NULL,
+    "description" text,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "impacts" (
+    "incident_id" uuid,
+    "component_id" uuid,
+    "impact_type_id" uuid,
+    "severity" smallint,
+    PRIMARY KEY ("incident_id", "component_id", "impact_type_id"),
+    CONSTRAINT "fk_incidents_affects" FOREIGN KEY ("incident_id") REFERENCES "incidents" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_impacts_impact_type" FOREIGN KEY ("impact_type_id") REFERENCES "impact_types" ("id"),
+    CONSTRAINT "fk_components_actively_affected_by" FOREIGN KEY ("component_id") REFERENCES "components" ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "severities" (
+    "display_name" text,
+    "value" smallint,
+    CONSTRAINT "uni_severities_value" UNIQUE ("value")
+);
+
+CREATE TABLE IF NOT EXISTS "api_keys" (
+    "id" uuid,
+    "name" text NOT NULL,
+    "prefix" text NOT NULL,
+    "secret_hash" text NOT NULL,
+    "scopes" jsonb NOT NULL,
+    "created_at" timestamptz,
+    "expires_at" timestamptz,
+    "last_used_at" timestamptz,
+    "revoked_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_api_keys_secret_hash" ON "api_keys" ("secret_hash");
+
+CREATE TABLE IF NOT EXISTS "audit_entries" (
+    "id" bigserial,
+    "created_at" timestamptz NOT NULL,
+    "actor" text NOT NULL,
+    "operation" text NOT NULL,
+    "target_type" text NOT NULL,
+    "target_id" text NOT NULL,
+    "before" jsonb,
+    "after" jsonb,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_target" ON "audit_entries" ("target_type", "target_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_actor" ON "audit_entries" ("actor");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_created_at" ON "audit_entries" ("created_at");
+
+CREATE TABLE IF NOT EXISTS "events" (
+    "id" bigserial,
+    "created_at" timestamptz NOT NULL,
+    "type" text NOT NULL,
+    "payload" jsonb,
+    "dispatched_at" timestamptz,
+    "notified_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_events_notified_at" ON "events" ("notified_at");
+CREATE INDEX IF NOT EXISTS "idx_events_dispatched_at" ON "events" ("dispatched_at");
+
+CREATE TABLE IF NOT EXISTS "webhook_subscriptions" (
+    "id" uuid,
+    "target_url" text NOT NULL,
+    "events" jsonb NOT NULL,
+    "secret" text NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+    "id" bigserial,
+    "event_id" bigint NOT NULL,
+    "subscription_id" uuid NOT NULL,
+    "status" text NOT NULL,
+    "attempts" bigint NOT NULL,
+    "next_attempt_at" timestamptz NOT NULL,
+    "last_error" text,
+    "created_at" timestamptz NOT NULL,
+    "delivered_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_webhook_deliveries_event" FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_webhook_deliveries_subscription" FOREIGN KEY ("subscription_id")
+        REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_due" ON "webhook_deliveries" ("status", "next_attempt_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_webhook_deliveries_event_subscription"
+    ON "webhook_deliveries" ("event_id", "subscription_id");
+
+CREATE TABLE IF NOT EXISTS "subscribers" (
+    "id" uuid,
+    "email" text NOT NULL,
+    "components" jsonb,
+    "labels" jsonb,
+    "created_at" timestamptz,
+    "confirmed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_subscribers_email" ON "subscribers" ("email");
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" bigserial,
+    "subscriber_id" uuid NOT NULL,
+    "recipient" text NOT NULL,
+    "subject" text NOT NULL,
+    "text_body" text NOT NULL,
+    "html_body" text NOT NULL,
+    "unsubscribe_url" text NOT NULL,
+    "status" text NOT NULL,
+    "attempts" bigint NOT NULL,
+    "next_attempt_at" timestamptz NOT NULL,
+    "last_error" text,
+    "created_at" timestamptz NOT NULL,
+    "sent_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_notifications_subscriber" FOREIGN KEY ("subscriber_id")
+        REFERENCES "subscribers" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "idx_notifications_due" ON "notifications" ("status", "next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_notifications_subscriber_id" ON "notifications" ("subscriber_id");
+
+CREATE TABLE IF NOT EXISTS "alert_incidents" (
+    "id" bigserial,
+    "fingerprint" text NOT NULL,
+    "incident_id" uuid NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    "resolved_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_alert_incidents_incident" FOREIGN KEY ("incident_id")
+        REFERENCES "incidents" ("id") ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_alert_incidents_active" ON "alert_incidents" ("fingerprint")
+    WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_alert_incidents_incident_id" ON "alert_incidents" ("incident_id");
@@ -0,0 +1,2 @@
+-- Label selectors on components are translated to containment and key existence operators, which GIN indexes support.
+CREATE INDEX IF NOT EXISTS "idx_components_labels" ON "components" USING GIN ("labels");
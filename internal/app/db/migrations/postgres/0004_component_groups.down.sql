@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "component_group_members";
+DROP TABLE IF EXISTS "component_groups";
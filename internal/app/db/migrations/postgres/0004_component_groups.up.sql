@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS "component_groups" (
+    "id" uuid,
+    "display_name" text,
+    "description" text,
+    "parent_id" uuid,
+    "order" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_component_groups_parent" FOREIGN KEY ("parent_id") REFERENCES "component_groups" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_component_groups_parent_id" ON "component_groups" ("parent_id");
+
+CREATE TABLE IF NOT EXISTS "component_group_members" (
+    "group_id" uuid,
+    "component_id" uuid,
+    PRIMARY KEY ("group_id", "component_id"),
+    CONSTRAINT "fk_component_groups_members" FOREIGN KEY ("group_id") REFERENCES "component_groups" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_component_group_members_component" FOREIGN KEY ("component_id") REFERENCES "components" ("id") ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+ALTER TABLE "components" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "incidents" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "incident_updates" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "impact_types" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "severities" DROP COLUMN IF EXISTS "version";
@@ -0,0 +1,6 @@
+-- Versions of resources, incremented on each update for optimistic concurrency control.
+ALTER TABLE "components" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 1;
+ALTER TABLE "incidents" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 1;
+ALTER TABLE "incident_updates" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 1;
+ALTER TABLE "impact_types" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 1;
+ALTER TABLE "severities" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 1;
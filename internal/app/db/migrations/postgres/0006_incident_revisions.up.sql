@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS "incident_revisions" (
+    "incident_id" uuid,
+    "revision" bigint,
+    "display_name" text,
+    "description" text,
+    "began_at" timestamptz,
+    "ended_at" timestamptz,
+    "phase_generation" bigint,
+    "phase_order" bigint,
+    "recorded_at" timestamptz NOT NULL,
+    PRIMARY KEY ("incident_id", "revision"),
+    CONSTRAINT "fk_incident_revisions_incident" FOREIGN KEY ("incident_id") REFERENCES "incidents" ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "incident_revision_impacts" (
+    "incident_id" uuid,
+    "revision" bigint,
+    "component_id" uuid,
+    "impact_type_id" uuid,
+    "severity" smallint,
+    PRIMARY KEY ("incident_id", "revision", "component_id", "impact_type_id"),
+    CONSTRAINT "fk_incident_revisions_affects" FOREIGN KEY ("incident_id", "revision")
+        REFERENCES "incident_revisions" ("incident_id", "revision") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "idx_incident_revision_impacts_component_id" ON "incident_revision_impacts" ("component_id");
+
+INSERT INTO "incident_revisions"
+    ("incident_id", "revision", "display_name", "description", "began_at", "ended_at", "phase_generation", "phase_order", "recorded_at")
+SELECT "id", 1, "display_name", "description", "began_at", "ended_at", "phase_generation", "phase_order", CURRENT_TIMESTAMP
+FROM "incidents";
+
+INSERT INTO "incident_revision_impacts" ("incident_id", "revision", "component_id", "impact_type_id", "severity")
+SELECT "incident_id", 1, "component_id", "impact_type_id", "severity"
+FROM "impacts";
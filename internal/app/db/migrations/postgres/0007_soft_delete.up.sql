@@ -0,0 +1,8 @@
+-- Soft deletion of components and incidents, which are kept until purged after a retention period.
+ALTER TABLE "components" ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+ALTER TABLE "components" ADD COLUMN IF NOT EXISTS "deleted_by" text;
+ALTER TABLE "incidents" ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+ALTER TABLE "incidents" ADD COLUMN IF NOT EXISTS "deleted_by" text;
+
+CREATE INDEX IF NOT EXISTS "idx_components_deleted_at" ON "components" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_incidents_deleted_at" ON "incidents" ("deleted_at");
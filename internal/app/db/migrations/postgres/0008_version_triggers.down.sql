@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS "severities_version" ON "severities";
+DROP TRIGGER IF EXISTS "impact_types_version" ON "impact_types";
+DROP TRIGGER IF EXISTS "incident_updates_version" ON "incident_updates";
+DROP TRIGGER IF EXISTS "incidents_version" ON "incidents";
+DROP TRIGGER IF EXISTS "components_version" ON "components";
+
+DROP FUNCTION IF EXISTS "increment_version"();
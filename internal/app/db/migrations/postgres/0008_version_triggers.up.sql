@@ -0,0 +1,27 @@
+-- Every update increments the version of the resource, even if the writer doesn't,
+-- so conditional requests based on an older version always fail.
+CREATE OR REPLACE FUNCTION "increment_version"() RETURNS trigger AS $$
+BEGIN
+    IF NEW."version" = OLD."version" THEN
+        NEW."version" := OLD."version" + 1;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS "components_version" ON "components";
+CREATE TRIGGER "components_version" BEFORE UPDATE ON "components"
+    FOR EACH ROW EXECUTE FUNCTION "increment_version"();
+DROP TRIGGER IF EXISTS "incidents_version" ON "incidents";
+CREATE TRIGGER "incidents_version" BEFORE UPDATE ON "incidents"
+    FOR EACH ROW EXECUTE FUNCTION "increment_version"();
+DROP TRIGGER IF EXISTS "incident_updates_version" ON "incident_updates";
+CREATE TRIGGER "incident_updates_version" BEFORE UPDATE ON "incident_updates"
+    FOR EACH ROW EXECUTE FUNCTION "increment_version"();
+DROP TRIGGER IF EXISTS "impact_types_version" ON "impact_types";
+CREATE TRIGGER "impact_types_version" BEFORE UPDATE ON "impact_types"
+    FOR EACH ROW EXECUTE FUNCTION "increment_version"();
+DROP TRIGGER IF EXISTS "severities_version" ON "severities";
+CREATE TRIGGER "severities_version" BEFORE UPDATE ON "severities"
+    FOR EACH ROW EXECUTE FUNCTION "increment_version"();
@@ -0,0 +1,180 @@
+-- Initial schema, matching the PostgreSQL migration of the same version.
+-- UUIDs and JSON documents are stored as text, times as text in UTC.
+
+CREATE TABLE IF NOT EXISTS "components" (
+    "id" text,
+    "display_name" text,
+    "labels" text,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "phases" (
+    "name" text NOT NULL,
+    "generation" integer,
+    "order" integer,
+    PRIMARY KEY ("generation", "order")
+);
+
+CREATE TABLE IF NOT EXISTS "incidents" (
+    "id" text,
+    "display_name" text,
+    "description" text,
+    "began_at" datetime,
+    "ended_at" datetime,
+    "phase_generation" integer,
+    "phase_order" integer,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_incidents_phase" FOREIGN KEY ("phase_generation", "phase_order")
+        REFERENCES "phases" ("generation", "order")
+);
+
+CREATE TABLE IF NOT EXISTS "incident_updates" (
+    "incident_id" text,
+    "order" integer,
+    "display_name" text,
+    "description" text,
+    "created_at" datetime,
+    PRIMARY KEY ("incident_id", "order"),
+    CONSTRAINT "fk_incidents_updates" FOREIGN KEY ("incident_id") REFERENCES "incidents" ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "impact_types" (
+    "id" text,
+    "display_name" text NOT NULL,
+    "description" text,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "impacts" (
+    "incident_id" text,
+    "component_id" text,
+    "impact_type_id" text,
+    "severity" integer,
+    PRIMARY KEY ("incident_id", "component_id", "impact_type_id"),
+    CONSTRAINT "fk_incidents_affects" FOREIGN KEY ("incident_id") REFERENCES "incidents" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_impacts_impact_type" FOREIGN KEY ("impact_type_id") REFERENCES "impact_types" ("id"),
+    CONSTRAINT "fk_components_actively_affected_by" FOREIGN KEY ("component_id") REFERENCES "components" ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "severities" (
+    "display_name" text,
+    "value" integer,
+    CONSTRAINT "uni_severities_value" UNIQUE ("value")
+);
+
+CREATE TABLE IF NOT EXISTS "api_keys" (
+    "id" text,
+    "name" text NOT NULL,
+    "prefix" text NOT NULL,
+    "secret_hash" text NOT NULL,
+    "scopes" text NOT NULL,
+    "created_at" datetime,
+    "expires_at" datetime,
+    "last_used_at" datetime,
+    "revoked_at" datetime,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_api_keys_secret_hash" ON "api_keys" ("secret_hash");
+
+CREATE TABLE IF NOT EXISTS "audit_entries" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime NOT NULL,
+    "actor" text NOT NULL,
+    "operation" text NOT NULL,
+    "target_type" text NOT NULL,
+    "target_id" text NOT NULL,
+    "before" text,
+    "after" text
+);
+
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_target" ON "audit_entries" ("target_type", "target_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_actor" ON "audit_entries" ("actor");
+CREATE INDEX IF NOT EXISTS "idx_audit_entries_created_at" ON "audit_entries" ("created_at");
+
+CREATE TABLE IF NOT EXISTS "events" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "created_at" datetime NOT NULL,
+    "type" text NOT NULL,
+    "payload" text,
+    "dispatched_at" datetime,
+    "notified_at" datetime
+);
+
+CREATE INDEX IF NOT EXISTS "idx_events_notified_at" ON "events" ("notified_at");
+CREATE INDEX IF NOT EXISTS "idx_events_dispatched_at" ON "events" ("dispatched_at");
+
+CREATE TABLE IF NOT EXISTS "webhook_subscriptions" (
+    "id" text,
+    "target_url" text NOT NULL,
+    "events" text NOT NULL,
+    "secret" text NOT NULL,
+    "created_at" datetime,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "event_id" integer NOT NULL,
+    "subscription_id" text NOT NULL,
+    "status" text NOT NULL,
+    "attempts" integer NOT NULL,
+    "next_attempt_at" datetime NOT NULL,
+    "last_error" text,
+    "created_at" datetime NOT NULL,
+    "delivered_at" datetime,
+    CONSTRAINT "fk_webhook_deliveries_event" FOREIGN KEY ("event_id") REFERENCES "events" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_webhook_deliveries_subscription" FOREIGN KEY ("subscription_id")
+        REFERENCES "webhook_subscriptions" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_due" ON "webhook_deliveries" ("status", "next_attempt_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_webhook_deliveries_event_subscription"
+    ON "webhook_deliveries" ("event_id", "subscription_id");
+
+CREATE TABLE IF NOT EXISTS "subscribers" (
+    "id" text,
+    "email" text NOT NULL,
+    "components" text,
+    "labels" text,
+    "created_at" datetime,
+    "confirmed_at" datetime,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_subscribers_email" ON "subscribers" ("email");
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "subscriber_id" text NOT NULL,
+    "recipient" text NOT NULL,
+    "subject" text NOT NULL,
+    "text_body" text NOT NULL,
+    "html_body" text NOT NULL,
+    "unsubscribe_url" text NOT NULL,
+    "status" text NOT NULL,
+    "attempts" integer NOT NULL,
+    "next_attempt_at" datetime NOT NULL,
+    "last_error" text,
+    "created_at" datetime NOT NULL,
+    "sent_at" datetime,
+    CONSTRAINT "fk_notifications_subscriber" FOREIGN KEY ("subscriber_id")
+        REFERENCES "subscribers" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "idx_notifications_due" ON "notifications" ("status", "next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_notifications_subscriber_id" ON "notifications" ("subscriber_id");
+
+CREATE TABLE IF NOT EXISTS "alert_incidents" (
+    "id" integer PRIMARY KEY AUTOINCREMENT,
+    "fingerprint" text NOT NULL,
+    "incident_id" text NOT NULL,
+    "created_at" datetime NOT NULL,
+    "resolved_at" datetime,
+    CONSTRAINT "fk_alert_incidents_incident" FOREIGN KEY ("incident_id")
+        REFERENCES "incidents" ("id") ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_alert_incidents_active" ON "alert_incidents" ("fingerprint")
+    WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_alert_incidents_incident_id" ON "alert_incidents" ("incident_id");
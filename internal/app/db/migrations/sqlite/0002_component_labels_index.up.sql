@@ -0,0 +1,3 @@
+-- SQLite lacks indexes on JSON documents, label selectors on components are evaluated on each row.
+-- Kept to share the version with PostgreSQL.
+SELECT 1;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS "component_dependencies" (
+    "component_id" text,
+    "depends_on_id" text,
+    PRIMARY KEY ("component_id", "depends_on_id"),
+    CONSTRAINT "fk_component_dependencies_component" FOREIGN KEY ("component_id") REFERENCES "components" ("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_component_dependencies_depends_on" FOREIGN KEY ("depends_on_id") REFERENCES "components" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "idx_component_dependencies_depends_on_id" ON "component_dependencies" ("depends_on_id");
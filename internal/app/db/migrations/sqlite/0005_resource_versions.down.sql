@@ -0,0 +1,5 @@
+ALTER TABLE "components" DROP COLUMN "version";
+ALTER TABLE "incidents" DROP COLUMN "version";
+ALTER TABLE "incident_updates" DROP COLUMN "version";
+ALTER TABLE "impact_types" DROP COLUMN "version";
+ALTER TABLE "severities" DROP COLUMN "version";
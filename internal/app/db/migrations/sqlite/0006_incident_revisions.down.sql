@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "incident_revision_impacts";
+DROP TABLE IF EXISTS "incident_revisions";
@@ -0,0 +1,12 @@
+-- Deleted components and incidents are purged, as they can't be told apart afterwards.
+DELETE FROM "incidents" WHERE "deleted_at" IS NOT NULL;
+DELETE FROM "components" WHERE "deleted_at" IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM "impacts" WHERE "impacts"."component_id" = "components"."id");
+
+DROP INDEX IF EXISTS "idx_incidents_deleted_at";
+DROP INDEX IF EXISTS "idx_components_deleted_at";
+
+ALTER TABLE "incidents" DROP COLUMN "deleted_by";
+ALTER TABLE "incidents" DROP COLUMN "deleted_at";
+ALTER TABLE "components" DROP COLUMN "deleted_by";
+ALTER TABLE "components" DROP COLUMN "deleted_at";
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS "severities_version";
+DROP TRIGGER IF EXISTS "impact_types_version";
+DROP TRIGGER IF EXISTS "incident_updates_version";
+DROP TRIGGER IF EXISTS "incidents_version";
+DROP TRIGGER IF EXISTS "components_version";
@@ -0,0 +1,31 @@
+-- Every update increments the version of the resource, even if the writer doesn't,
+-- so conditional requests based on an older version always fail.
+CREATE TRIGGER IF NOT EXISTS "components_version" AFTER UPDATE ON "components"
+    FOR EACH ROW WHEN NEW."version" = OLD."version"
+BEGIN
+    UPDATE "components" SET "version" = OLD."version" + 1 WHERE rowid = NEW.rowid;
+END;
+
+CREATE TRIGGER IF NOT EXISTS "incidents_version" AFTER UPDATE ON "incidents"
+    FOR EACH ROW WHEN NEW."version" = OLD."version"
+BEGIN
+    UPDATE "incidents" SET "version" = OLD."version" + 1 WHERE rowid = NEW.rowid;
+END;
+
+CREATE TRIGGER IF NOT EXISTS "incident_updates_version" AFTER UPDATE ON "incident_updates"
+    FOR EACH ROW WHEN NEW."version" = OLD."version"
+BEGIN
+    UPDATE "incident_updates" SET "version" = OLD."version" + 1 WHERE rowid = NEW.rowid;
+END;
+
+CREATE TRIGGER IF NOT EXISTS "impact_types_version" AFTER UPDATE ON "impact_types"
+    FOR EACH ROW WHEN NEW."version" = OLD."version"
+BEGIN
+    UPDATE "impact_types" SET "version" = OLD."version" + 1 WHERE rowid = NEW.rowid;
+END;
+
+CREATE TRIGGER IF NOT EXISTS "severities_version" AFTER UPDATE ON "severities"
+    FOR EACH ROW WHEN NEW."version" = OLD."version"
+BEGIN
+    UPDATE "severities" SET "version" = OLD."version" + 1 WHERE rowid = NEW.rowid;
+END;
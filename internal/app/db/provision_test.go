@@ -0,0 +1,107 @@
+package db_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/db"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Provision", func() {
+	const provisioningFile = `
+components:
+- displayname: Network
+- displayname: hypervisor-00001
+- displayname: hypervisor-00002
+
+componentGroups:
+- displayname: Infrastructure
+  description: Core services
+  components:
+  - Network
+  groups:
+  - displayname: Compute
+    components:
+    - hypervisor-00001
+    - hypervisor-00002
+- displayname: Platform
+`
+
+	var (
+		// sub loggers
+		_, gormLogger, _ = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// storage under test
+		store *storage.Memory
+
+		writeFile = func(content string) string {
+			filename := filepath.Join(GinkgoT().TempDir(), "provisioning.yaml")
+			Ω(os.WriteFile(filename, []byte(content), 0o600)).Should(Succeed())
+
+			return filename
+		}
+	)
+
+	BeforeEach(func() {
+		store = storage.NewMemory()
+	})
+
+	It("should create nested component groups in order", func() {
+		// Act
+		err := db.Provision(store, writeFile(provisioningFile), gormLogger)
+
+		// Assert
+		Ω(err).ShouldNot(HaveOccurred())
+
+		groups, err := store.WithContext(context.Background()).ListComponentGroups()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(groups).Should(HaveLen(3))
+
+		Ω(*groups[0].DisplayName).Should(Equal("Compute"))
+		Ω(*groups[0].ParentID).Should(Equal(groups[1].ID))
+		Ω(groups[0].ComponentIDs()).Should(HaveLen(2))
+
+		Ω(*groups[1].DisplayName).Should(Equal("Infrastructure"))
+		Ω(*groups[1].Description).Should(Equal("Core services"))
+		Ω(groups[1].ParentID).Should(BeNil())
+		Ω(groups[1].ComponentIDs()).Should(HaveLen(1))
+
+		Ω(*groups[2].DisplayName).Should(Equal("Platform"))
+		Ω(*groups[2].Order).Should(Equal(1))
+	})
+
+	It("should not provision component groups twice", func() {
+		// Arrange
+		filename := writeFile(provisioningFile)
+		Ω(db.Provision(store, filename, gormLogger)).Should(Succeed())
+
+		// Act
+		err := db.Provision(store, filename, gormLogger)
+
+		// Assert
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(store.WithContext(context.Background()).ListComponentGroups()).Should(HaveLen(3))
+	})
+
+	It("should refuse unknown components", func() {
+		// Arrange
+		filename := writeFile(`
+componentGroups:
+- displayname: Infrastructure
+  components:
+  - Storage
+`)
+
+		// Act
+		err := db.Provision(store, filename, gormLogger)
+
+		// Assert
+		Ω(err).Should(MatchError(db.ErrUnknownGroupComponent))
+	})
+})
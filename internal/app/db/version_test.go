@@ -0,0 +1,61 @@
+package db_test
+
+import (
+	"path/filepath"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/db"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Versions", func() {
+	var (
+		// sub loggers
+		_, gormLogger, _ = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// database under test
+		gormDB *gorm.DB
+
+		versionOf = func(incidentID string) int {
+			var version int
+			Ω(gormDB.Raw(`SELECT "version" FROM "incidents" WHERE "id" = ?`, incidentID).Scan(&version).Error).
+				Should(Succeed())
+
+			return version
+		}
+	)
+
+	BeforeEach(func() {
+		database, err := db.New(db.SQLitePrefix+filepath.Join(GinkgoT().TempDir(), "status.db"), gormLogger)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = database.MigrateUp()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		gormDB = database.GetDBCon()
+
+		Ω(gormDB.Exec(`INSERT INTO "incidents" ("id", "display_name") VALUES ('incident', 'Disk failure')`).Error).
+			Should(Succeed())
+	})
+
+	It("should increment the version on updates, which don't set it", func() {
+		// Act
+		err := gormDB.Exec(`UPDATE "incidents" SET "ended_at" = CURRENT_TIMESTAMP WHERE "id" = 'incident'`).Error
+
+		// Assert
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(versionOf("incident")).Should(Equal(2))
+	})
+
+	It("should keep the version set by updates", func() {
+		// Act
+		err := gormDB.Exec(`UPDATE "incidents" SET "description" = 'Replaced', "version" = 2 WHERE "id" = 'incident'`).Error
+
+		// Assert
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(versionOf("incident")).Should(Equal(2))
+	})
+})
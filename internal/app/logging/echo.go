@@ -8,13 +8,14 @@ import (
 
 // defaultZerlogRequestLoggerConfig is the default config for logging a request.
 var defaultZerlogRequestLoggerConfig = middleware.RequestLoggerConfig{ //nolint:gochecknoglobals,exhaustruct
-	Skipper:     middleware.DefaultSkipper,
-	LogLatency:  true,
-	LogRemoteIP: true,
-	LogMethod:   true,
-	LogURI:      true,
-	LogStatus:   true,
-	LogError:    true,
+	Skipper:      middleware.DefaultSkipper,
+	LogLatency:   true,
+	LogRemoteIP:  true,
+	LogMethod:    true,
+	LogURI:       true,
+	LogStatus:    true,
+	LogError:     true,
+	LogRequestID: true,
 }
 
 // NewZerlogRequestLogger generates the logger function being used by the logging middleware.
@@ -37,6 +38,7 @@ func NewZerlogRequestLogger(
 			Str("method", values.Method).
 			Str("URI", values.URI).
 			Int("status", values.Status).
+			Str("requestId", values.RequestID).
 			Msg("request")
 
 		return nil
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/config"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	APIImplementation "github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// authRealm is reported to clients in the `WWW-Authenticate` header.
+	authRealm = "status-page-api"
+
+	// keySetTimeout limits the time to load a remote key set.
+	keySetTimeout = 10 * time.Second
+)
+
+// newKeySet loads the key set from the configured URL or file.
+func newKeySet(conf *config.Auth) (*auth.KeySet, error) {
+	if conf.JWKSFile != "" {
+		return auth.NewKeySetFromFile(conf.JWKSFile) //nolint:wrapcheck
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), keySetTimeout)
+	defer cancel()
+
+	client := &http.Client{ //nolint:exhaustruct
+		Timeout: keySetTimeout,
+	}
+
+	return auth.NewKeySetFromURL(ctx, conf.JWKSURL, client, auth.DefaultKeySetRefreshInterval) //nolint:wrapcheck
+}
+
+// isPublicPath reports, if the requested path is always accessible without authentication.
+func isPublicPath(ctx echo.Context) bool {
+	switch ctx.Path() {
+	case "/openapi.json", "/swagger":
+		return true
+	default:
+		return false
+	}
+}
+
+// newAuthMiddleware creates the authentication and authorization middleware from the config.
+// API keys are verified against the store, API key authentication is disabled without a store.
+func newAuthMiddleware(
+	conf *config.Auth,
+	apiKeyStore auth.APIKeyStore,
+	logger *zerolog.Logger,
+) (echo.MiddlewareFunc, error) {
+	keySet, err := newKeySet(conf)
+	if err != nil {
+		return nil, fmt.Errorf("error loading JWKS: %w", err)
+	}
+
+	scopeMapping, err := conf.ParseScopeMapping()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing scope mapping: %w", err)
+	}
+
+	policy, err := APIImplementation.NewAuthorizationPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("error creating authorization policy: %w", err)
+	}
+
+	authLogger := logger.With().Str("middleware", "auth").Logger()
+
+	var apiKeyAuthenticator auth.Authenticator
+	if apiKeyStore != nil {
+		apiKeyAuthenticator = auth.NewAPIKeyAuthenticator(apiKeyStore, &authLogger)
+	}
+
+	return auth.Middleware(auth.MiddlewareConfig{
+		Skipper: isPublicPath,
+		Authenticator: auth.NewJWTAuthenticator(conf.Issuer, conf.Audience, keySet, &auth.ClaimScopeMapper{
+			Claim:   conf.ScopeClaim,
+			Mapping: scopeMapping,
+		}),
+		APIKeyAuthenticator: apiKeyAuthenticator,
+		Policy:              policy,
+		PublicRead:          conf.PublicRead,
+		Realm:               authRealm,
+	}, &authLogger), nil
+}
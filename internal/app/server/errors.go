@@ -0,0 +1,6 @@
+package server
+
+import "errors"
+
+// ErrInvalidResponse means a response violates the OpenAPI spec.
+var ErrInvalidResponse = errors.New("response violates the OpenAPI spec")
@@ -9,6 +9,8 @@ import (
 	"github.com/SovereignCloudStack/status-page-api/internal/app/config"
 	"github.com/SovereignCloudStack/status-page-api/internal/app/logging"
 	"github.com/SovereignCloudStack/status-page-api/internal/app/swagger"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	APIImplementation "github.com/SovereignCloudStack/status-page-api/pkg/server"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v4"
@@ -23,14 +25,27 @@ type Server struct {
 	logger *zerolog.Logger
 }
 
+// API combines the handlers of the OpenAPI spec and the extension routes.
+type API interface {
+	apiServerDefinition.ServerInterface
+	APIImplementation.ExtensionInterface
+}
+
 // New creates a new wrapped server.
-func New(conf *config.Server, logger *zerolog.Logger, promMiddlewareConfig echoprometheus.MiddlewareConfig) *Server {
+func New(
+	conf *config.Server,
+	logger *zerolog.Logger,
+	promMiddlewareConfig echoprometheus.MiddlewareConfig,
+	apiKeyStore auth.APIKeyStore,
+) (*Server, error) {
 	// general server settings
 	echoServer := echo.New()
 	echoServer.HideBanner = true
 	echoServer.HidePort = true
+	echoServer.HTTPErrorHandler = APIImplementation.NewErrorHandler(logger)
 
 	// middlewares
+	echoServer.Use(middleware.RequestID())
 	echoServer.Use(logging.NewEchoZerlogLogger(logger))
 	echoServer.Use(middleware.Recover())
 	echoServer.Use(middleware.RemoveTrailingSlash())
@@ -43,6 +58,24 @@ func New(conf *config.Server, logger *zerolog.Logger, promMiddlewareConfig echop
 
 	echoServer.Use(echoprometheus.NewMiddlewareWithConfig(promMiddlewareConfig))
 
+	if conf.Auth.Enabled {
+		authMiddleware, err := newAuthMiddleware(&conf.Auth, apiKeyStore, logger)
+		if err != nil {
+			return nil, fmt.Errorf("error setting up authentication: %w", err)
+		}
+
+		echoServer.Use(authMiddleware)
+	}
+
+	if conf.Validation.Enabled {
+		validationMiddleware, err := newValidationMiddleware(conf.Validation.Strict, logger)
+		if err != nil {
+			return nil, fmt.Errorf("error setting up validation: %w", err)
+		}
+
+		echoServer.Use(validationMiddleware)
+	}
+
 	// open api spec and swagger
 	echoServer.GET("/openapi.json", swagger.ServeOpenAPISpec)
 
@@ -54,12 +87,18 @@ func New(conf *config.Server, logger *zerolog.Logger, promMiddlewareConfig echop
 		echo:   echoServer,
 		conf:   conf,
 		logger: logger,
-	}
+	}, nil
 }
 
-// RegisterAPI registers api spec and api implementation to the echo server.
-func (s *Server) RegisterAPI(apiImplementation apiServerDefinition.ServerInterface) {
+// RegisterAPI registers api spec, extension routes and api implementation to the echo server.
+func (s *Server) RegisterAPI(apiImplementation API) {
 	apiServerDefinition.RegisterHandlers(s.echo, apiImplementation)
+	APIImplementation.RegisterExtensionHandlers(s.echo, apiImplementation)
+}
+
+// ServeHTTP handles a single request, as the wrapped echo server does.
+func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	s.echo.ServeHTTP(writer, request)
 }
 
 // Start starts the wrapped echo server.
@@ -0,0 +1,294 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	APIImplementation "github.com/SovereignCloudStack/status-page-api/pkg/server"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// uuidPattern matches the hyphenated UUIDs identifying resources, regardless of their version.
+const uuidPattern = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+
+// newValidationMiddleware validates requests against the OpenAPI spec, before they are handled.
+// In strict mode, the responses are validated too and replaced by an internal server error, if they violate the spec.
+// Requests of extension routes, which are not part of the spec, are not validated.
+func newValidationMiddleware(strict bool, logger *zerolog.Logger) (echo.MiddlewareFunc, error) {
+	swagger, err := apiServerDefinition.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("error loading OpenAPI spec: %w", err)
+	}
+
+	// The servers of the spec name public deployments, routes are matched by their path only.
+	swagger.Servers = nil
+
+	relaxRequiredProperties(swagger)
+	patternUUIDFormats(swagger)
+
+	router, err := legacy.NewRouter(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("error creating OpenAPI router: %w", err)
+	}
+
+	options := &openapi3filter.Options{ //nolint:exhaustruct
+		MultiError:          true,
+		SkipSettingDefaults: true,
+		AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			route, pathParams, err := router.FindRoute(ctx.Request())
+			if err != nil {
+				// Extension routes and unknown routes are left to the echo router.
+				return next(ctx)
+			}
+
+			requestInput := &openapi3filter.RequestValidationInput{ //nolint:exhaustruct
+				Request:    ctx.Request(),
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+
+			err = openapi3filter.ValidateRequest(ctx.Request().Context(), requestInput)
+			if err != nil {
+				logger.Warn().Err(err).Msg("request violates OpenAPI spec")
+
+				return echo.ErrBadRequest.WithInternal(requestViolation(err))
+			}
+
+			if !strict {
+				return next(ctx)
+			}
+
+			return validateResponse(ctx, next, requestInput, logger)
+		}
+	}, nil
+}
+
+// relaxRequiredProperties drops required properties, which a schema does not define.
+// The spec requires the `id` of incidents and the `order` of incident updates, which are never part of a request.
+func relaxRequiredProperties(swagger *openapi3.T) {
+	for _, schemaRef := range swagger.Components.Schemas {
+		schema := schemaRef.Value
+		if schema == nil || len(schema.Required) == 0 {
+			continue
+		}
+
+		schema.Required = slices.DeleteFunc(schema.Required, func(name string) bool {
+			_, defined := schema.Properties[name]
+
+			return !defined
+		})
+	}
+}
+
+// patternUUIDFormats validates the UUID format of the schemas by a pattern.
+// Formats are ignored, unless they are defined in the registry of kin-openapi. The registry is global to the
+// process, the pattern keeps the validation scoped to the spec loaded here.
+func patternUUIDFormats(swagger *openapi3.T) {
+	for _, schemaRef := range swagger.Components.Schemas {
+		schema := schemaRef.Value
+		if schema == nil || schema.Format != "uuid" || schema.Pattern != "" {
+			continue
+		}
+
+		schema.Pattern = uuidPattern
+	}
+}
+
+// validateResponse buffers the response of the handler and only sends it, if it fulfills the spec.
+func validateResponse(
+	ctx echo.Context,
+	next echo.HandlerFunc,
+	requestInput *openapi3filter.RequestValidationInput,
+	logger *zerolog.Logger,
+) error {
+	response := ctx.Response()
+	writer := response.Writer
+	header := response.Header().Clone()
+
+	recorder := &responseRecorder{ResponseWriter: writer, status: http.StatusOK, body: bytes.Buffer{}}
+	response.Writer = recorder
+
+	err := next(ctx)
+
+	response.Writer = writer
+
+	if err != nil || !response.Committed {
+		return err
+	}
+
+	responseInput := &openapi3filter.ResponseValidationInput{ //nolint:exhaustruct
+		RequestValidationInput: requestInput,
+		Status:                 recorder.status,
+		Header:                 response.Header(),
+		Options:                requestInput.Options,
+	}
+	responseInput.SetBodyBytes(recorder.body.Bytes())
+
+	err = openapi3filter.ValidateResponse(ctx.Request().Context(), responseInput)
+	if err != nil {
+		logger.Error().Err(err).Msg("response violates OpenAPI spec")
+
+		// Discard the buffered response, so the error can be sent instead.
+		for name := range response.Header() {
+			delete(response.Header(), name)
+		}
+
+		for name, values := range header {
+			response.Header()[name] = values
+		}
+
+		response.Committed = false
+		response.Size = 0
+
+		return echo.ErrInternalServerError.WithInternal(fmt.Errorf("%w: %w", ErrInvalidResponse, err))
+	}
+
+	writer.WriteHeader(recorder.status)
+
+	_, err = writer.Write(recorder.body.Bytes())
+	if err != nil {
+		return fmt.Errorf("error sending validated response: %w", err)
+	}
+
+	return nil
+}
+
+// responseRecorder holds back the response of a handler.
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status code.
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+// Write records the body.
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data) //nolint:wrapcheck
+}
+
+// requestViolation describes the violations of the spec by a request.
+// The violating fields are reported as [DbDef.FieldError].
+func requestViolation(err error) error {
+	var fieldErrs []error
+
+	var multiErr openapi3.MultiError
+	if !errors.As(err, &multiErr) {
+		multiErr = openapi3.MultiError{err}
+	}
+
+	for _, violationErr := range multiErr {
+		var requestErr *openapi3filter.RequestError
+		if !errors.As(violationErr, &requestErr) {
+			continue
+		}
+
+		if requestErr.Parameter != nil {
+			fieldErrs = append(fieldErrs, DbDef.NewFieldError(
+				requestErr.Parameter.Name,
+				violation(parameterReason(requestErr)),
+			))
+
+			continue
+		}
+
+		fieldErrs = append(fieldErrs, schemaViolations(requestErr.Err)...)
+	}
+
+	if len(fieldErrs) == 0 {
+		return fmt.Errorf("%w: %w", APIImplementation.ErrInvalidRequest, err)
+	}
+
+	return fmt.Errorf("%w: %w", APIImplementation.ErrInvalidRequest, errors.Join(fieldErrs...))
+}
+
+// violation is the reason, why a field violates the spec.
+type violation string
+
+// Error implements the error interface.
+func (v violation) Error() string {
+	return string(v)
+}
+
+// parameterReason describes the violation of a parameter.
+func parameterReason(requestErr *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(requestErr.Err, &schemaErr) {
+		return schemaErr.Reason
+	}
+
+	if requestErr.Reason != "" {
+		return requestErr.Reason
+	}
+
+	if requestErr.Err != nil {
+		return requestErr.Err.Error()
+	}
+
+	return "invalid value"
+}
+
+// schemaViolations converts the schema errors of a request body to field errors.
+func schemaViolations(err error) []error {
+	var multiErr openapi3.MultiError
+	if errors.As(err, &multiErr) {
+		var fieldErrs []error
+
+		for _, childErr := range multiErr {
+			fieldErrs = append(fieldErrs, schemaViolations(childErr)...)
+		}
+
+		return fieldErrs
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return nil
+	}
+
+	return []error{DbDef.NewFieldError(
+		fieldPath(schemaErr.JSONPointer()),
+		violation(schemaErr.Reason),
+	)}
+}
+
+// fieldPath converts a JSON pointer to the path of a field, e.g. `affects[0].severity`.
+func fieldPath(pointer []string) string {
+	var path strings.Builder
+
+	for _, token := range pointer {
+		if _, err := strconv.Atoi(token); err == nil {
+			path.WriteString("[" + token + "]")
+
+			continue
+		}
+
+		if path.Len() > 0 {
+			path.WriteString(".")
+		}
+
+		path.WriteString(token)
+	}
+
+	return path.String()
+}
@@ -0,0 +1,236 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/config"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/server"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	APIImplementation "github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo-contrib/echoprometheus"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+)
+
+// driftingAPI answers the phase list in violation of the spec.
+type driftingAPI struct {
+	*APIImplementation.Implementation
+}
+
+func (driftingAPI) GetPhaseList(ctx echo.Context, _ apiServerDefinition.GetPhaseListParams) error {
+	return ctx.JSON(http.StatusOK, map[string]string{"data": "Investigation"}) //nolint:wrapcheck
+}
+
+var _ = Describe("Validation", func() {
+	var (
+		// sub loggers
+		_, _, serverLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// storage of the api implementation
+		store storage.Storage
+
+		// server under test
+		apiServer *server.Server
+
+		componentID  db.ID
+		impactTypeID db.ID
+
+		newServer = func(validation config.Validation, apiImplementation server.API) *server.Server {
+			apiServer, err := server.New(&config.Server{ //nolint:exhaustruct
+				Address:    ":0",
+				Validation: validation,
+			}, serverLogger, echoprometheus.MiddlewareConfig{ //nolint:exhaustruct
+				Registerer: prometheus.NewRegistry(),
+			}, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			apiServer.RegisterAPI(apiImplementation)
+
+			return apiServer
+		}
+
+		serve = func(method, target, body string) *httptest.ResponseRecorder {
+			request := httptest.NewRequest(method, target, strings.NewReader(body))
+			if body != "" {
+				request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+
+			response := httptest.NewRecorder()
+			apiServer.ServeHTTP(response, request)
+
+			return response
+		}
+
+		problemOf = func(response *httptest.ResponseRecorder) api.Problem {
+			Ω(response.Header().Get(echo.HeaderContentType)).Should(Equal(api.ProblemContentType))
+
+			var problem api.Problem
+			Ω(json.Unmarshal(response.Body.Bytes(), &problem)).Should(Succeed())
+
+			return problem
+		}
+	)
+
+	BeforeEach(func() {
+		store = storage.NewMemory()
+		repo := store.WithContext(context.Background())
+
+		component := &db.Component{DisplayName: test.Ptr("Network")}
+		Ω(repo.CreateComponent(component)).Should(Succeed())
+		componentID = component.ID
+
+		impactType := &db.ImpactType{DisplayName: test.Ptr("Connectivity issues")}
+		Ω(repo.CreateImpactType(impactType)).Should(Succeed())
+		impactTypeID = impactType.ID
+
+		Ω(repo.CreatePhases([]db.Phase{
+			{Name: test.Ptr("Investigation"), Generation: test.Ptr(1), Order: test.Ptr(0)},
+		})).Should(Succeed())
+
+		apiServer = newServer(
+			config.Validation{Enabled: true, Strict: true},
+			APIImplementation.New(store, nil, nil, nil, serverLogger),
+		)
+	})
+
+	Context("with invalid requests", func() {
+		It("should list the invalid fields of the body", func() {
+			// Act
+			response := serve(http.MethodPost, "/incidents", `{
+				"displayName": "Switch failure",
+				"affects": [{"reference": "`+componentID.String()+`", "type": "`+impactTypeID.String()+`", "severity": 101}]
+			}`)
+
+			// Assert
+			Ω(response.Code).Should(Equal(http.StatusBadRequest))
+
+			problem := problemOf(response)
+			Ω(problem.Type).Should(Equal(api.ProblemTypeBase + "invalid-request"))
+			Ω(problem.InvalidParams).Should(ConsistOf(HaveField("Name", "affects[0].severity")))
+		})
+
+		It("should list the invalid parameters", func() {
+			// Act
+			response := serve(http.MethodGet, "/incidents?end="+time.Now().UTC().Format(time.RFC3339), "")
+
+			// Assert
+			Ω(response.Code).Should(Equal(http.StatusBadRequest))
+			Ω(problemOf(response).InvalidParams).Should(ConsistOf(HaveField("Name", "start")))
+		})
+
+		It("should reject malformed path parameters", func() {
+			// Act
+			response := serve(http.MethodGet, "/components/network", "")
+
+			// Assert
+			Ω(response.Code).Should(Equal(http.StatusBadRequest))
+			Ω(problemOf(response).InvalidParams).Should(ConsistOf(HaveField("Name", "componentId")))
+		})
+
+		It("should not define formats for other specs", func() {
+			// Assert
+			Ω(openapi3.SchemaStringFormats).ShouldNot(HaveKey("uuid"))
+		})
+	})
+
+	Context("with valid requests", func() {
+		It("should answer as specified", func() {
+			// Arrange
+			start := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+			end := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+			// Act & Assert
+			Ω(serve(http.MethodGet, "/phases", "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodGet, "/impacttypes", "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodGet, "/impacttypes/"+impactTypeID.String(), "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodGet, "/severities", "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodGet, "/components", "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodGet, "/components/"+componentID.String(), "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodPatch, "/components/"+componentID.String(), `{"labels": {"region": "west"}}`).Code).
+				Should(Equal(http.StatusNoContent))
+			Ω(serve(http.MethodPost, "/components", `{"displayName": "Storage"}`).Code).Should(Equal(http.StatusCreated))
+			Ω(serve(http.MethodPost, "/impacttypes", `{"displayName": "Performance"}`).Code).
+				Should(Equal(http.StatusCreated))
+			Ω(serve(http.MethodPost, "/severities", `{"displayName": "degraded", "value": 50}`).Code).
+				Should(Equal(http.StatusNoContent))
+			Ω(serve(http.MethodGet, "/severities/degraded", "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodPost, "/phases", `{"phases": ["Investigation", "Resolved"]}`).Code).
+				Should(Equal(http.StatusCreated))
+
+			created := serve(http.MethodPost, "/incidents", `{
+				"displayName": "Switch failure",
+				"beganAt": "`+start+`",
+				"phase": {"generation": 1, "order": 0},
+				"affects": [{"reference": "`+componentID.String()+`", "type": "`+impactTypeID.String()+`", "severity": 50}]
+			}`)
+			Ω(created.Code).Should(Equal(http.StatusCreated))
+
+			var idResponse struct {
+				ID string `json:"id"`
+			}
+			Ω(json.Unmarshal(created.Body.Bytes(), &idResponse)).Should(Succeed())
+
+			incidentPath := "/incidents/" + idResponse.ID
+
+			Ω(serve(http.MethodGet, "/incidents?start="+start+"&end="+end, "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodGet, incidentPath, "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodPost, incidentPath+"/updates", `{"displayName": "Replaced"}`).Code).
+				Should(Equal(http.StatusCreated))
+			Ω(serve(http.MethodGet, incidentPath+"/updates", "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodGet, incidentPath+"/updates/0", "").Code).Should(Equal(http.StatusOK))
+			Ω(serve(http.MethodPatch, incidentPath, `{"description": "Core switch failed."}`).Code).
+				Should(Equal(http.StatusNoContent))
+			Ω(serve(http.MethodDelete, incidentPath, "").Code).Should(Equal(http.StatusNoContent))
+		})
+
+		It("should replace responses violating the spec", func() {
+			// Arrange
+			apiServer = newServer(
+				config.Validation{Enabled: true, Strict: true},
+				driftingAPI{APIImplementation.New(store, nil, nil, nil, serverLogger)},
+			)
+
+			// Act
+			response := serve(http.MethodGet, "/phases", "")
+
+			// Assert
+			Ω(response.Code).Should(Equal(http.StatusInternalServerError))
+			Ω(problemOf(response).Detail).Should(BeEmpty())
+		})
+
+		It("should only validate responses in strict mode", func() {
+			// Arrange
+			apiServer = newServer(
+				config.Validation{Enabled: true, Strict: false},
+				driftingAPI{APIImplementation.New(store, nil, nil, nil, serverLogger)},
+			)
+
+			// Act
+			response := serve(http.MethodGet, "/phases", "")
+
+			// Assert
+			Ω(response.Code).Should(Equal(http.StatusOK))
+		})
+
+		It("should leave extension routes alone", func() {
+			// Act
+			response := serve(http.MethodGet, "/openapi.json", "")
+
+			// Assert
+			Ω(response.Code).Should(Equal(http.StatusOK))
+		})
+	})
+})
@@ -7,14 +7,23 @@ import (
 
 	metricsServer "github.com/SovereignCloudStack/status-page-api/internal/app/metrics"
 	apiServer "github.com/SovereignCloudStack/status-page-api/internal/app/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/events"
+	"github.com/SovereignCloudStack/status-page-api/pkg/purge"
+	"github.com/SovereignCloudStack/status-page-api/pkg/subscription"
+	"github.com/SovereignCloudStack/status-page-api/pkg/webhook"
 	"github.com/rs/zerolog"
 )
 
 // Shutdown gracefully shutdowns all services in the timeout duration.
+// The webhook dispatcher, event listener, subscription notifier and purger are optional.
 func Shutdown(
 	timeout time.Duration,
 	apiServer *apiServer.Server,
 	metricsServer *metricsServer.Server,
+	webhookDispatcher *webhook.Dispatcher,
+	eventListener *events.Listener,
+	subscriptionNotifier *subscription.Notifier,
+	purger *purge.Purger,
 	logger *zerolog.Logger,
 ) {
 	var waitGroup sync.WaitGroup
@@ -42,6 +51,59 @@ func Shutdown(
 		}
 	}()
 
+	if webhookDispatcher != nil {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			err := webhookDispatcher.Shutdown(ctx)
+			if err != nil {
+				logger.Warn().Err(err).Msg("error shutting down webhook dispatcher")
+			}
+		}()
+	}
+
+	if eventListener != nil {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			// Ends all event streams, which the api server waits for.
+			err := eventListener.Shutdown(ctx)
+			if err != nil {
+				logger.Warn().Err(err).Msg("error shutting down event listener")
+			}
+		}()
+	}
+
+	if subscriptionNotifier != nil {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			err := subscriptionNotifier.Shutdown(ctx)
+			if err != nil {
+				logger.Warn().Err(err).Msg("error shutting down subscription notifier")
+			}
+		}()
+	}
+
+	if purger != nil {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			err := purger.Shutdown(ctx)
+			if err != nil {
+				logger.Warn().Err(err).Msg("error shutting down purger")
+			}
+		}()
+	}
+
 	waitGroup.Wait()
 	cancel()
 }
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/logging"
+	"github.com/glebarez/sqlite"
+	. "github.com/onsi/ginkgo/v2" //nolint:revive,stylecheck
+	. "github.com/onsi/gomega"    //nolint:revive,stylecheck
+	"github.com/rs/zerolog"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Dialect is a database dialect, mocked SQL is expected in.
+type Dialect string
+
+const (
+	// Postgres is the PostgreSQL dialect.
+	Postgres Dialect = "postgres"
+	// SQLite is the SQLite dialect.
+	SQLite Dialect = "sqlite"
+)
+
+var (
+	whitespacePattern = regexp.MustCompile(`\s+`)
+	placeholderRegexp = regexp.MustCompile(`\?`)
+)
+
+// DialectEntries are the entries of [DescribeTableSubtree] to run specs against every dialect.
+func DialectEntries() []TableEntry {
+	return []TableEntry{
+		Entry("with PostgreSQL", Postgres),
+		Entry("with SQLite", SQLite),
+	}
+}
+
+// postgresQueryMatcher matches the SQL of any dialect against expectations in the PostgreSQL dialect.
+// Identifiers quoted with backticks are quoted with double quotes and placeholders are numbered.
+func postgresQueryMatcher(dialect Dialect) sqlmock.QueryMatcher { //nolint:ireturn
+	return sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+		if dialect == SQLite {
+			actualSQL = strings.ReplaceAll(actualSQL, "`", `"`)
+
+			placeholder := 0
+			actualSQL = placeholderRegexp.ReplaceAllStringFunc(actualSQL, func(string) string {
+				placeholder++
+
+				return "$" + strconv.Itoa(placeholder)
+			})
+		}
+
+		expected := strings.TrimSpace(whitespacePattern.ReplaceAllString(expectedSQL, " "))
+		actual := strings.TrimSpace(whitespacePattern.ReplaceAllString(actualSQL, " "))
+
+		pattern, err := regexp.Compile(expected)
+		if err != nil {
+			return fmt.Errorf("error compiling expected SQL: %w", err)
+		}
+
+		if !pattern.MatchString(actual) {
+			return fmt.Errorf(`could not match actual sql: "%s" with expected regexp "%s"`, actual, expected) //nolint:err113
+		}
+
+		return nil
+	})
+}
+
+// MustMockGormWithDialect creates SQL mock and connects gorm in the dialect to the mock.
+// Expected SQL is written in the PostgreSQL dialect for all dialects.
+// Fails matching in tests, when an error occures.
+func MustMockGormWithDialect( //nolint:ireturn,nolintlint
+	dialect Dialect,
+	gormLogger *zerolog.Logger,
+) (*sql.DB, sqlmock.Sqlmock, *gorm.DB) {
+	// mock sql connection
+	sqlDB, sqlMock, err := sqlmock.New(sqlmock.QueryMatcherOption(postgresQueryMatcher(dialect)))
+	Ω(err).ShouldNot(HaveOccurred())
+
+	var dialector gorm.Dialector
+
+	switch dialect {
+	case SQLite:
+		// queried to decide on support for `RETURNING`.
+		sqlMock.
+			ExpectQuery(`select sqlite_version\(\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("3.46.0"))
+
+		dialector = sqlite.Dialector{Conn: sqlDB} //nolint:exhaustruct
+	default:
+		dialector = postgres.New(postgres.Config{Conn: sqlDB}) //nolint:exhaustruct
+	}
+
+	// connect gorm to mock db
+	dbCon, err := gorm.Open(dialector, &gorm.Config{ //nolint:exhaustruct
+		Logger: logging.NewGormLogger(gormLogger),
+	})
+	Ω(err).ShouldNot(HaveOccurred())
+
+	// SQLite renders limits as literals, as parameters like PostgreSQL they share the expectations.
+	if dialect == SQLite {
+		delete(dbCon.ClauseBuilders, "LIMIT")
+	}
+
+	return sqlDB, sqlMock, dbCon
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/gomega" //nolint:revive,stylecheck
+)
+
+// SMTPMessage is a message received by the [SMTPServer].
+type SMTPMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// SMTPServer is a local SMTP stand-in, accepting messages without TLS and authentication.
+type SMTPServer struct {
+	// Address of the server as `host:port`.
+	Address string
+
+	listener  net.Listener
+	mutex     sync.Mutex
+	messages  []SMTPMessage
+	rejecting bool
+}
+
+// MustStartSMTPServer starts a SMTP stand-in on a random local port.
+// Fails matching in tests, when an error occures.
+func MustStartSMTPServer() *SMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Ω(err).ShouldNot(HaveOccurred())
+
+	server := &SMTPServer{ //nolint:exhaustruct
+		Address:  listener.Addr().String(),
+		listener: listener,
+	}
+
+	go server.serve()
+
+	return server
+}
+
+// Messages returns the received messages.
+func (s *SMTPServer) Messages() []SMTPMessage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]SMTPMessage{}, s.messages...)
+}
+
+// SetRejecting makes the server reject all recipients.
+func (s *SMTPServer) SetRejecting(rejecting bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.rejecting = rejecting
+}
+
+// Close stops the server.
+func (s *SMTPServer) Close() {
+	s.listener.Close()
+}
+
+func (s *SMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(textproto.NewConn(conn))
+	}
+}
+
+func (s *SMTPServer) handle(conn *textproto.Conn) {
+	defer conn.Close()
+
+	var message SMTPMessage
+
+	_ = conn.PrintfLine("220 localhost SMTP stand-in")
+
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command, argument, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			_ = conn.PrintfLine("250 localhost")
+		case "MAIL":
+			message = SMTPMessage{From: smtpPath(argument), To: nil, Data: ""}
+			_ = conn.PrintfLine("250 OK")
+		case "RCPT":
+			if s.isRejecting() {
+				_ = conn.PrintfLine("550 mailbox unavailable")
+
+				continue
+			}
+
+			message.To = append(message.To, smtpPath(argument))
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+
+			data, err := conn.ReadDotBytes()
+			if err != nil {
+				return
+			}
+
+			message.Data = string(data)
+			s.receive(message)
+			_ = conn.PrintfLine("250 OK")
+		case "RSET", "NOOP":
+			_ = conn.PrintfLine("250 OK")
+		case "QUIT":
+			_ = conn.PrintfLine("221 bye")
+
+			return
+		default:
+			_ = conn.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+func (s *SMTPServer) isRejecting() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.rejecting
+}
+
+func (s *SMTPServer) receive(message SMTPMessage) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.messages = append(s.messages, message)
+}
+
+// smtpPath extracts the address of `FROM:<address>` and `TO:<address>`.
+func smtpPath(argument string) string {
+	_, path, _ := strings.Cut(argument, ":")
+	path, _, _ = strings.Cut(strings.TrimPrefix(path, "<"), ">")
+
+	return path
+}
@@ -16,7 +16,6 @@ import (
 	. "github.com/onsi/gomega" //nolint:revive,stylecheck
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -40,19 +39,7 @@ func MustSetupLogging(loglevel zerolog.Level) (*zerolog.Logger, *zerolog.Logger,
 // MustMockGorm creates SQL mock and connects gorm to the mock.
 // Fails matching in tests, when an error occures.
 func MustMockGorm(gormLogger *zerolog.Logger) (*sql.DB, sqlmock.Sqlmock, *gorm.DB) { //nolint:ireturn,nolintlint
-	// mock sql connection
-	sqlDB, sqlMock, err := sqlmock.New()
-	Ω(err).ShouldNot(HaveOccurred())
-
-	// connect gorm to mock db
-	dbCon, err := gorm.Open(postgres.New(postgres.Config{ //nolint:exhaustruct
-		Conn: sqlDB,
-	}), &gorm.Config{ //nolint:exhaustruct
-		Logger: logging.NewGormLogger(gormLogger),
-	})
-	Ω(err).ShouldNot(HaveOccurred())
-
-	return sqlDB, sqlMock, dbCon
+	return MustMockGormWithDialect(Postgres, gormLogger)
 }
 
 // MustCreateRequestAndResponseWriter creates a http request and a response writer in form of a response recorder.
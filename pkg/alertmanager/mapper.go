@@ -0,0 +1,158 @@
+package alertmanager
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+)
+
+const (
+	// summaryAnnotation is the conventional annotation of a short alert description.
+	summaryAnnotation = "summary"
+	// descriptionAnnotation is the conventional annotation of a detailed alert description.
+	descriptionAnnotation = "description"
+	// alertNameLabel is the label of every alert naming its alerting rule.
+	alertNameLabel = "alertname"
+)
+
+// Config holds the settings of the [Mapper].
+type Config struct {
+	// ComponentLabel is the alert label naming the display name of the affected component, empty to disable.
+	ComponentLabel string
+	// LabelMatchers maps component labels to the alert labels, whose values they must equal.
+	LabelMatchers map[string]string
+	// SeverityLabel is the alert label carrying the alert severity.
+	SeverityLabel string
+	// Severities maps values of the severity label to severity values.
+	Severities map[string]int
+	// DefaultSeverity is used for alerts without or with an unmapped severity label.
+	DefaultSeverity int
+	// ImpactTypeLabel is the alert label naming the display name of the impact type, empty to disable.
+	ImpactTypeLabel string
+	// DefaultImpactType is the display name of the impact type used for alerts without impact type label.
+	DefaultImpactType string
+}
+
+// Mapper maps alerts of Alertmanager to the components, impact type and severity of incidents.
+type Mapper struct {
+	conf Config
+}
+
+// NewMapper creates a new mapper.
+func NewMapper(conf Config) *Mapper {
+	return &Mapper{
+		conf: conf,
+	}
+}
+
+// Components returns the components affected by the alert.
+//
+// A component is affected, if its display name is the value of the component label of the alert,
+// or if it carries all labels matched by label matchers, whose alert label is set on the alert, with equal values.
+// At least one label matcher must apply, so alerts without any matched label don't affect all components.
+func (m *Mapper) Components(alert *api.Alert, components []*DbDef.Component) []*DbDef.Component {
+	var affected []*DbDef.Component
+
+	componentName, hasComponentName := alert.Labels[m.conf.ComponentLabel]
+	hasComponentName = hasComponentName && m.conf.ComponentLabel != ""
+
+	for _, component := range components {
+		if hasComponentName && component.DisplayName != nil && *component.DisplayName == componentName {
+			affected = append(affected, component)
+
+			continue
+		}
+
+		if m.matchesLabels(alert, component) {
+			affected = append(affected, component)
+		}
+	}
+
+	return affected
+}
+
+func (m *Mapper) matchesLabels(alert *api.Alert, component *DbDef.Component) bool {
+	applied := 0
+
+	for componentLabel, alertLabel := range m.conf.LabelMatchers {
+		alertValue, found := alert.Labels[alertLabel]
+		if !found {
+			continue
+		}
+
+		if component.Labels == nil {
+			return false
+		}
+
+		componentValue, found := (*component.Labels)[componentLabel]
+		if !found || componentValue != alertValue {
+			return false
+		}
+
+		applied++
+	}
+
+	return applied > 0
+}
+
+// Severity returns the severity value of the alert.
+func (m *Mapper) Severity(alert *api.Alert) int {
+	severity, found := m.conf.Severities[alert.Labels[m.conf.SeverityLabel]]
+	if !found {
+		return m.conf.DefaultSeverity
+	}
+
+	return severity
+}
+
+// ImpactType returns the display name of the impact type of the alert.
+func (m *Mapper) ImpactType(alert *api.Alert) string {
+	if m.conf.ImpactTypeLabel != "" {
+		impactType, found := alert.Labels[m.conf.ImpactTypeLabel]
+		if found && impactType != "" {
+			return impactType
+		}
+	}
+
+	return m.conf.DefaultImpactType
+}
+
+// Title returns the display name of an incident opened for the alert.
+func Title(alert *api.Alert) string {
+	if summary := alert.Annotations[summaryAnnotation]; summary != "" {
+		return summary
+	}
+
+	if alertName := alert.Labels[alertNameLabel]; alertName != "" {
+		return alertName
+	}
+
+	return "Alert " + alert.Fingerprint
+}
+
+// Description returns the description of an incident opened for the alert.
+func Description(alert *api.Alert) string {
+	if description := alert.Annotations[descriptionAnnotation]; description != "" {
+		return description
+	}
+
+	return Title(alert)
+}
+
+// ChangeDescription describes the affected components and severity of an alert for an incident update.
+func ChangeDescription(components []*DbDef.Component, severity int) string {
+	names := make([]string, 0, len(components))
+
+	for _, component := range components {
+		if component.DisplayName != nil {
+			names = append(names, *component.DisplayName)
+		}
+	}
+
+	slices.Sort(names)
+
+	return fmt.Sprintf("The alert now affects %s with severity %d.", strings.Join(names, ", "), severity)
+}
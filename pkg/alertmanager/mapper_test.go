@@ -0,0 +1,122 @@
+package alertmanager_test
+
+import (
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/alertmanager"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mapper", func() {
+	var (
+		mapper = alertmanager.NewMapper(alertmanager.Config{
+			ComponentLabel:    "component",
+			LabelMatchers:     map[string]string{"region": "region", "az": "availability_zone"},
+			SeverityLabel:     "severity",
+			Severities:        map[string]int{"critical": 100, "warning": 66},
+			DefaultSeverity:   33,
+			ImpactTypeLabel:   "impact_type",
+			DefaultImpactType: "Unknown",
+		})
+
+		storage     = &db.Component{DisplayName: test.Ptr("Storage"), Labels: &db.Labels{}}
+		westAZ1     = &db.Component{DisplayName: test.Ptr("hypervisor-00001"), Labels: &db.Labels{"region": "west", "az": "1"}}
+		westAZ2     = &db.Component{DisplayName: test.Ptr("hypervisor-00002"), Labels: &db.Labels{"region": "west", "az": "2"}}
+		eastAZ1     = &db.Component{DisplayName: test.Ptr("hypervisor-00003"), Labels: &db.Labels{"region": "east", "az": "1"}}
+		unlabeled   = &db.Component{DisplayName: test.Ptr("IdP")}
+		components  = []*db.Component{storage, westAZ1, westAZ2, eastAZ1, unlabeled}
+		alertLabels = func(labels map[string]string) *api.Alert {
+			return &api.Alert{Labels: labels}
+		}
+	)
+
+	Describe("Components", func() {
+		It("should match the component by display name", func() {
+			// Act
+			affected := mapper.Components(alertLabels(map[string]string{"component": "Storage"}), components)
+
+			// Assert
+			Ω(affected).Should(ConsistOf(storage))
+		})
+
+		It("should match all components carrying the matched labels", func() {
+			// Act
+			affected := mapper.Components(alertLabels(map[string]string{"region": "west"}), components)
+
+			// Assert
+			Ω(affected).Should(ConsistOf(westAZ1, westAZ2))
+		})
+
+		It("should require all matched labels", func() {
+			// Act
+			affected := mapper.Components(
+				alertLabels(map[string]string{"region": "west", "availability_zone": "2"}),
+				components,
+			)
+
+			// Assert
+			Ω(affected).Should(ConsistOf(westAZ2))
+		})
+
+		It("should not match any component without matched labels", func() {
+			// Act
+			affected := mapper.Components(alertLabels(map[string]string{"alertname": "DiskFull"}), components)
+
+			// Assert
+			Ω(affected).Should(BeEmpty())
+		})
+	})
+
+	Describe("Severity", func() {
+		It("should map the severity label", func() {
+			Ω(mapper.Severity(alertLabels(map[string]string{"severity": "warning"}))).Should(Equal(66))
+		})
+
+		It("should fall back to the default severity", func() {
+			Ω(mapper.Severity(alertLabels(map[string]string{"severity": "page"}))).Should(Equal(33))
+			Ω(mapper.Severity(alertLabels(map[string]string{}))).Should(Equal(33))
+		})
+	})
+
+	Describe("ImpactType", func() {
+		It("should use the impact type label", func() {
+			Ω(mapper.ImpactType(alertLabels(map[string]string{"impact_type": "Connectivity Problems"}))).
+				Should(Equal("Connectivity Problems"))
+		})
+
+		It("should fall back to the default impact type", func() {
+			Ω(mapper.ImpactType(alertLabels(map[string]string{}))).Should(Equal("Unknown"))
+		})
+	})
+
+	Describe("Title", func() {
+		It("should prefer the summary annotation", func() {
+			// Arrange
+			alert := &api.Alert{
+				Labels:      map[string]string{"alertname": "DiskFull"},
+				Annotations: map[string]string{"summary": "Disk is full"},
+			}
+
+			// Act & Assert
+			Ω(alertmanager.Title(alert)).Should(Equal("Disk is full"))
+			Ω(alertmanager.Description(alert)).Should(Equal("Disk is full"))
+		})
+
+		It("should fall back to the alert name", func() {
+			// Arrange
+			alert := &api.Alert{Labels: map[string]string{"alertname": "DiskFull"}}
+
+			// Act & Assert
+			Ω(alertmanager.Title(alert)).Should(Equal("DiskFull"))
+		})
+	})
+
+	Describe("ChangeDescription", func() {
+		It("should list the sorted component names", func() {
+			Ω(alertmanager.ChangeDescription([]*db.Component{westAZ2, westAZ1}, 66)).
+				Should(Equal("The alert now affects hypervisor-00001, hypervisor-00002 with severity 66."))
+		})
+	})
+})
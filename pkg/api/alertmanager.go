@@ -0,0 +1,59 @@
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertStatus is the state of an alert sent by Alertmanager, either `firing` or `resolved`.
+type AlertStatus string
+
+const (
+	// AlertFiring is an alert, which is currently active.
+	AlertFiring AlertStatus = "firing"
+	// AlertResolved is an alert, which is not active anymore.
+	AlertResolved AlertStatus = "resolved"
+)
+
+// Alert is a single alert of an [AlertmanagerRequest].
+type Alert struct {
+	Status       AlertStatus       `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	// Fingerprint identifies the alert across notifications.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// AlertmanagerRequest is the request body of the Alertmanager webhook receiver, see
+// https://prometheus.io/docs/alerting/latest/configuration/#webhook_config.
+type AlertmanagerRequest struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            AlertStatus       `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []Alert           `json:"alerts"`
+}
+
+// AlertResult describes, how a single alert of an [AlertmanagerRequest] was handled.
+type AlertResult struct {
+	Fingerprint string `json:"fingerprint"`
+	// Action is one of `created`, `updated`, `resolved` or `skipped`.
+	Action     string     `json:"action"`
+	IncidentID *uuid.UUID `json:"incidentId,omitempty"`
+	// Reason explains, why an alert was skipped.
+	Reason string `json:"reason,omitempty"`
+}
+
+// AlertmanagerResponse is the response of the Alertmanager webhook receiver.
+type AlertmanagerResponse struct {
+	Data []AlertResult `json:"data"`
+}
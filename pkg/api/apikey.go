@@ -0,0 +1,43 @@
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyRequest is the request body to create an API key.
+type APIKeyRequest struct {
+	Name      *string    `json:"name,omitempty"`
+	Scopes    []string   `json:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// APIKeyResponseData describes an API key without its secret.
+type APIKeyResponseData struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// APIKeyListResponse is the response listing all API keys.
+type APIKeyListResponse struct {
+	Data []APIKeyResponseData `json:"data"`
+}
+
+// APIKeyCreatedResponseData describes a newly created API key including its secret.
+// The secret is only ever returned once.
+type APIKeyCreatedResponseData struct {
+	APIKeyResponseData
+	Key string `json:"key"`
+}
+
+// APIKeyCreatedResponse is the response to the creation of an API key.
+type APIKeyCreatedResponse struct {
+	Data APIKeyCreatedResponseData `json:"data"`
+}
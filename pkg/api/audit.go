@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// GetAuditEntriesParams defines parameters for listing audit entries.
+// The listing is paged by the [PageParams].
+type GetAuditEntriesParams struct {
+	// Actor filters by the subject, that performed the operation.
+	Actor *string `query:"actor"`
+	// Operation filters by the kind of operation, one of `create`, `update` or `delete`.
+	Operation *string `query:"operation"`
+	// TargetType filters by the type of the changed resource, e.g. `incident`.
+	TargetType *string `query:"targetType"`
+	// TargetID filters by the ID of the changed resource.
+	TargetID *string `query:"targetId"`
+	// Since filters entries recorded at or after the point in time.
+	Since *time.Time `query:"since"`
+	// Until filters entries recorded before the point in time.
+	Until *time.Time `query:"until"`
+}
+
+// AuditChange describes the change of a single field of a resource.
+type AuditChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditEntryResponseData describes a recorded write operation.
+type AuditEntryResponseData struct {
+	ID         uint64          `json:"id"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	Actor      string          `json:"actor"`
+	Operation  string          `json:"operation"`
+	TargetType string          `json:"targetType"`
+	TargetID   string          `json:"targetId"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	Changes    []AuditChange   `json:"changes"`
+}
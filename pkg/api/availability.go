@@ -0,0 +1,43 @@
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultAvailabilityGranularity is the granularity of availability buckets, if not requested.
+const DefaultAvailabilityGranularity = "day"
+
+// GetAvailabilityParams defines parameters for the availability of a component.
+type GetAvailabilityParams struct {
+	// Start of the period, required.
+	Start *time.Time `query:"start"`
+	// End of the period, required.
+	End *time.Time `query:"end"`
+	// Granularity is the length of the buckets, one of `hour`, `day`, `week` or `month`.
+	Granularity *string `query:"granularity"`
+	// ExcludeMaintenance does not consider times, the component only spent in maintenance.
+	ExcludeMaintenance *bool `query:"excludeMaintenance"`
+}
+
+// AvailabilityBucket is the availability of a component in a part of the period.
+type AvailabilityBucket struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Availability is the percentage of the considered time without impacts, omitted if no time was considered.
+	Availability *float64 `json:"availability,omitempty"`
+	// Severities is the percentage of the considered time spent at or above each severity, by name.
+	Severities map[string]float64 `json:"severities,omitempty"`
+}
+
+// AvailabilityResponse is the availability of a component in a period, in total and per bucket.
+type AvailabilityResponse struct {
+	ComponentID        uuid.UUID            `json:"componentId"`
+	Start              time.Time            `json:"start"`
+	End                time.Time            `json:"end"`
+	Granularity        string               `json:"granularity"`
+	ExcludeMaintenance bool                 `json:"excludeMaintenance"`
+	Total              AvailabilityBucket   `json:"total"`
+	Buckets            []AvailabilityBucket `json:"buckets"`
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"time"
+
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+)
+
+// DeletionParams defines parameters of reads including deleted incidents and components.
+// They are read by the incident and component endpoints of the OpenAPI spec in addition to their own parameters.
+type DeletionParams struct {
+	// IncludeDeleted adds the deleted incidents or components, that are not purged yet. Only allowed for admins.
+	IncludeDeleted *bool `query:"includeDeleted"`
+}
+
+// Deletion marks a deleted incident or component. Both fields are omitted for resources, which are not deleted.
+type Deletion struct {
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+	DeletedBy *string    `json:"deletedBy,omitempty"`
+}
+
+// DeletableComponentResponseData is a component of the OpenAPI spec, marked if it is deleted.
+// It replaces the component of the OpenAPI spec, when deleted components are requested.
+type DeletableComponentResponseData struct {
+	apiServerDefinition.ComponentResponseData
+	Deletion
+}
+
+// DeletableComponentResponse is a single component, marked if it is deleted.
+type DeletableComponentResponse struct {
+	Data DeletableComponentResponseData `json:"data"`
+}
+
+// DeletableIncidentResponseData is an incident of the OpenAPI spec, marked if it is deleted.
+// It replaces the incident of the OpenAPI spec, when deleted incidents are requested.
+type DeletableIncidentResponseData struct {
+	apiServerDefinition.IncidentResponseData
+	Deletion
+}
+
+// DeletableIncidentResponse is a single incident, marked if it is deleted.
+type DeletableIncidentResponse struct {
+	Data DeletableIncidentResponseData `json:"data"`
+}
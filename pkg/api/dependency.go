@@ -0,0 +1,61 @@
+package api
+
+import "github.com/google/uuid"
+
+// DependencyParams defines parameters of components for impacts propagated from their dependencies.
+// They are read by the component endpoints of the OpenAPI spec in addition to their own parameters.
+type DependencyParams struct {
+	// IncludeIndirect adds the active impacts on the dependencies to the active impacts of the components.
+	IncludeIndirect *bool `query:"includeIndirect"`
+}
+
+// Dependency is the dependency of a component on another one.
+type Dependency struct {
+	ComponentID uuid.UUID `json:"componentId"`
+	DependsOnID uuid.UUID `json:"dependsOnId"`
+}
+
+// DependencyListResponse lists all dependencies between components.
+type DependencyListResponse struct {
+	Data []Dependency `json:"data"`
+}
+
+// ComponentDependencies are the dependencies of a component and the components depending on it.
+type ComponentDependencies struct {
+	ComponentID uuid.UUID `json:"componentId"`
+	// Dependencies are the components, the component directly depends on.
+	Dependencies []uuid.UUID `json:"dependencies"`
+	// Dependents are the components directly depending on the component.
+	Dependents []uuid.UUID `json:"dependents"`
+	// AllDependencies are the direct and indirect dependencies, nearest first.
+	AllDependencies []uuid.UUID `json:"allDependencies"`
+	// AllDependents are the direct and indirect dependents, nearest first.
+	AllDependents []uuid.UUID `json:"allDependents"`
+}
+
+// ComponentImpact is an active impact on a component, either direct or propagated from a dependency.
+type ComponentImpact struct {
+	// Reference is the ID of the incident, for indirect impacts the incident on the dependency.
+	Reference *uuid.UUID `json:"reference,omitempty"`
+	Type      *uuid.UUID `json:"type,omitempty"`
+	Severity  *int       `json:"severity,omitempty"`
+	// Indirect marks impacts propagated from a dependency.
+	Indirect bool `json:"indirect,omitempty"`
+	// Origin is the dependency directly affected by an indirect impact.
+	Origin *uuid.UUID `json:"origin,omitempty"`
+}
+
+// ComponentResponseData is a component with its direct and indirect impacts, marked if it is deleted.
+// It replaces the component of the OpenAPI spec, when indirect impacts are requested.
+type ComponentResponseData struct {
+	ID                 uuid.UUID          `json:"id"`
+	DisplayName        *string            `json:"displayName,omitempty"`
+	Labels             *map[string]string `json:"labels,omitempty"`
+	ActivelyAffectedBy []ComponentImpact  `json:"activelyAffectedBy"`
+	Deletion
+}
+
+// ComponentResponse is a single component with its direct and indirect impacts.
+type ComponentResponse struct {
+	Data ComponentResponseData `json:"data"`
+}
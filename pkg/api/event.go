@@ -0,0 +1,20 @@
+package api
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventResponseData describes a change notification.
+type EventResponseData struct {
+	ID        uint64          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// GetEventsParams defines parameters for streaming events.
+type GetEventsParams struct {
+	// LastEventID resumes the stream after this event. The `Last-Event-ID` header takes precedence.
+	LastEventID *uint64 `query:"lastEventId"`
+}
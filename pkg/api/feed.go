@@ -0,0 +1,11 @@
+package api
+
+import "github.com/google/uuid"
+
+// GetFeedParams defines parameters for feeds and calendars of incidents.
+type GetFeedParams struct {
+	// Component filters by the ID of an affected component.
+	Component *uuid.UUID `query:"component"`
+	// Label filters by `key:value` labels of an affected component, all labels must match.
+	Label []string `query:"label"`
+}
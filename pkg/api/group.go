@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Nullable is a JSON field, that distinguishes being set to null from being omitted.
+type Nullable[T any] struct {
+	// Set is true, if the field is present, even if null.
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON implements the [encoding/json.Unmarshaler] interface, it is only called for present fields.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set, n.Value = true, nil
+
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var value T
+
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return fmt.Errorf("error decoding value: %w", err)
+	}
+
+	n.Value = &value
+
+	return nil
+}
+
+// MarshalJSON implements the [encoding/json.Marshaler] interface, unset fields are null.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(n.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding value: %w", err)
+	}
+
+	return data, nil
+}
+
+// ComponentGroupRequest creates a component group or changes the set fields of one.
+type ComponentGroupRequest struct {
+	DisplayName *string `json:"displayName"`
+	Description *string `json:"description"`
+	// ParentID nests the group in another one, null moves it to the top level.
+	ParentID Nullable[uuid.UUID] `json:"parentId"`
+	// Order sorts the group among the groups of its parent, it is placed last if unset on creation.
+	Order *int `json:"order"`
+}
+
+// ComponentGroupResponseData is a component group with the IDs of its member components.
+type ComponentGroupResponseData struct {
+	ID          uuid.UUID  `json:"id"`
+	DisplayName *string    `json:"displayName"`
+	Description *string    `json:"description,omitempty"`
+	ParentID    *uuid.UUID `json:"parentId,omitempty"`
+	Order       int        `json:"order"`
+	// Components references the direct members, not the members of nested groups.
+	Components []uuid.UUID `json:"components"`
+}
+
+// ComponentGroupResponse is a single component group.
+type ComponentGroupResponse struct {
+	Data ComponentGroupResponseData `json:"data"`
+}
+
+// ComponentGroupListResponse lists all component groups, sorted by order.
+type ComponentGroupListResponse struct {
+	Data []ComponentGroupResponseData `json:"data"`
+}
@@ -0,0 +1,17 @@
+package api
+
+// PageParams defines the parameters to page through listings.
+// They are read by all list endpoints of the OpenAPI spec in addition to their own parameters.
+type PageParams struct {
+	// Limit restricts the number of returned items.
+	Limit *int `query:"limit"`
+	// Cursor continues the listing with the page, the `next` link of the previous page points to.
+	Cursor *string `query:"cursor"`
+}
+
+// PageResponse is a page of a listing.
+type PageResponse[T any] struct {
+	Data []T `json:"data"`
+	// Next links the following page, omitted on the last page.
+	Next *string `json:"next,omitempty"`
+}
@@ -0,0 +1,37 @@
+package api
+
+// ProblemContentType is the content type of [Problem] responses, see RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase prefixes the type of every problem defined by this API.
+const ProblemTypeBase = "urn:status-page:problem:"
+
+// ProblemTypeGeneric is the type of problems, which are described by their HTTP status only.
+const ProblemTypeGeneric = "about:blank"
+
+// Problem is the body of every error response, see RFC 7807.
+type Problem struct {
+	// Type is a stable URI identifying the kind of problem.
+	// It is [ProblemTypeGeneric] for errors without a more specific type.
+	Type string `json:"type"`
+	// Title is the short summary of the HTTP status.
+	Title string `json:"title"`
+	// Status is the HTTP status code of the response.
+	Status int `json:"status"`
+	// Detail explains this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+	// Instance is the path of the request, which caused the problem.
+	Instance string `json:"instance,omitempty"`
+	// RequestID identifies the request in the logs of the server.
+	RequestID string `json:"requestId,omitempty"`
+	// InvalidParams lists the offending fields of the request.
+	InvalidParams []InvalidParam `json:"invalidParams,omitempty"`
+}
+
+// InvalidParam is a single offending field of a request.
+type InvalidParam struct {
+	// Name is the path of the field in the request body, e.g. `affects[0].severity`.
+	Name string `json:"name"`
+	// Reason describes, why the value of the field is invalid.
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevisionParams defines parameters of incidents evaluated at a point in time.
+// They are read by the incident endpoints of the OpenAPI spec in addition to their own parameters.
+type RevisionParams struct {
+	// At evaluates the incidents by their revisions valid at the time instead of their current state.
+	At *time.Time `query:"at"`
+}
+
+// IncidentRevisionImpact is an impact of an incident revision on a component.
+type IncidentRevisionImpact struct {
+	Reference *uuid.UUID `json:"reference,omitempty"`
+	Type      *uuid.UUID `json:"type,omitempty"`
+	Severity  *int       `json:"severity,omitempty"`
+}
+
+// IncidentRevisionPhase references the phase of an incident revision by its generation and order.
+type IncidentRevisionPhase struct {
+	Generation int `json:"generation"`
+	Order      int `json:"order"`
+}
+
+// IncidentRevision is the state of an incident after a change.
+// It is valid from its recording until the next revision of the incident was recorded.
+type IncidentRevision struct {
+	IncidentID  uuid.UUID                `json:"incidentId"`
+	Revision    int                      `json:"revision"`
+	RecordedAt  time.Time                `json:"recordedAt"`
+	DisplayName *string                  `json:"displayName,omitempty"`
+	Description *string                  `json:"description,omitempty"`
+	BeganAt     *time.Time               `json:"beganAt"`
+	EndedAt     *time.Time               `json:"endedAt"`
+	Phase       *IncidentRevisionPhase   `json:"phase,omitempty"`
+	Affects     []IncidentRevisionImpact `json:"affects"`
+}
+
+// IncidentRevisionListResponse lists the revisions of an incident, oldest first.
+type IncidentRevisionListResponse struct {
+	Data []IncidentRevision `json:"data"`
+}
@@ -0,0 +1,8 @@
+package api
+
+// SelectorParams defines the label selector of listings related to components.
+// It is read by the component and incident listings in addition to their parameters of the OpenAPI spec.
+type SelectorParams struct {
+	// LabelSelector selects components by their labels, e.g. `region=datacenter-west,az in (1,2),!deprecated`.
+	LabelSelector *string `query:"labelSelector"`
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetStatusParams defines parameters for the status summary.
+type GetStatusParams struct {
+	// At evaluates the impacts active at the point in time, the ongoing impacts if unset.
+	At *time.Time `query:"at"`
+}
+
+// Status is the worst state of one or more components, derived from their active impacts.
+type Status struct {
+	// Severity is the highest severity of the active impacts, omitted without impacts.
+	Severity *int `json:"severity,omitempty"`
+	// DisplayName names the severity, whose value range contains the highest severity.
+	// It is omitted without impacts or if no severity covers the value.
+	DisplayName *string `json:"displayName,omitempty"`
+	// Maintenance is set, when all active impacts are maintenances.
+	Maintenance bool `json:"maintenance,omitempty"`
+}
+
+// ComponentStatus is the status of a single component.
+type ComponentStatus struct {
+	ID          uuid.UUID `json:"id"`
+	DisplayName *string   `json:"displayName"`
+	Status      Status    `json:"status"`
+	// Incidents references the incidents with active impacts on the component.
+	Incidents []uuid.UUID `json:"incidents"`
+}
+
+// StatusResponse summarizes the status of all components.
+type StatusResponse struct {
+	// At is the point in time the status is evaluated at, omitted for the ongoing impacts.
+	At         *time.Time        `json:"at,omitempty"`
+	Overall    Status            `json:"overall"`
+	Components []ComponentStatus `json:"components"`
+	// Labels rolls up the status of all components carrying a label, by label key and value.
+	Labels map[string]map[string]Status `json:"labels"`
+	// Groups rolls up the status of the top level component groups, with their nested groups.
+	Groups []GroupStatus `json:"groups"`
+}
+
+// GroupStatus is the worst status of the member components of a component group and its nested groups.
+type GroupStatus struct {
+	ID          uuid.UUID `json:"id"`
+	DisplayName *string   `json:"displayName"`
+	Description *string   `json:"description,omitempty"`
+	Status      Status    `json:"status"`
+	// Components references the direct members of the group.
+	Components []uuid.UUID   `json:"components"`
+	Groups     []GroupStatus `json:"groups"`
+}
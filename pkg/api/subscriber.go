@@ -0,0 +1,38 @@
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriberRequest defines the request to subscribe to email notifications.
+type SubscriberRequest struct {
+	// Email is the address notified after confirming the subscription.
+	Email *string `json:"email"`
+	// Components limits notifications to incidents affecting one of these components.
+	Components []uuid.UUID `json:"components"`
+	// Labels limits notifications to incidents affecting a component with all of these `key:value` labels.
+	Labels []string `json:"labels"`
+}
+
+// SubscriberResponseData describes a subscriber.
+type SubscriberResponseData struct {
+	ID          uuid.UUID         `json:"id"`
+	Email       string            `json:"email"`
+	Components  []uuid.UUID       `json:"components"`
+	Labels      map[string]string `json:"labels"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	ConfirmedAt *time.Time        `json:"confirmedAt"`
+}
+
+// SubscriberResponse is the response for a single subscriber.
+type SubscriberResponse struct {
+	Data SubscriberResponseData `json:"data"`
+}
+
+// SubscriberTokenParams defines parameters for confirming and cancelling subscriptions.
+type SubscriberTokenParams struct {
+	// Token is the signed token sent to the subscriber.
+	Token *string `query:"token"`
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookRequest is the request body to create a webhook subscription.
+type WebhookRequest struct {
+	TargetURL *string  `json:"targetUrl,omitempty"`
+	Events    []string `json:"events,omitempty"`
+	// Secret signs the deliveries, a random secret is generated if omitted.
+	Secret *string `json:"secret,omitempty"`
+}
+
+// WebhookResponseData describes a webhook subscription without its secret.
+type WebhookResponseData struct {
+	ID        uuid.UUID `json:"id"`
+	TargetURL string    `json:"targetUrl"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WebhookListResponse is the response listing all webhook subscriptions.
+type WebhookListResponse struct {
+	Data []WebhookResponseData `json:"data"`
+}
+
+// WebhookCreatedResponseData describes a newly created webhook subscription including its secret.
+// The secret is only ever returned once.
+type WebhookCreatedResponseData struct {
+	WebhookResponseData
+	Secret string `json:"secret"`
+}
+
+// WebhookCreatedResponse is the response to the creation of a webhook subscription.
+type WebhookCreatedResponse struct {
+	Data WebhookCreatedResponseData `json:"data"`
+}
+
+// GetWebhookDeliveriesParams defines parameters for listing webhook deliveries.
+type GetWebhookDeliveriesParams struct {
+	// Status filters by the delivery status, one of `pending`, `delivered` or `dead`.
+	Status *string `query:"status"`
+}
+
+// WebhookDeliveryResponseData describes the delivery of an event to a webhook subscription.
+type WebhookDeliveryResponseData struct {
+	ID            uint64     `json:"id"`
+	EventID       uint64     `json:"eventId"`
+	EventType     string     `json:"eventType,omitempty"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	LastError     *string    `json:"lastError,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	DeliveredAt   *time.Time `json:"deliveredAt,omitempty"`
+}
+
+// WebhookDeliveryListResponse is the response listing deliveries of a webhook subscription.
+type WebhookDeliveryListResponse struct {
+	Data []WebhookDeliveryResponseData `json:"data"`
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// APIKeyPrefix marks a bearer token as API key.
+	APIKeyPrefix = "spk_"
+
+	// apiKeySecretLength is the number of random bytes of an API key.
+	apiKeySecretLength = 32
+
+	// apiKeyDisplayPrefixLength is the number of characters shown to identify a key.
+	apiKeyDisplayPrefixLength = len(APIKeyPrefix) + 8
+
+	// apiKeyUsageResolution limits how often the last usage of a key is written.
+	apiKeyUsageResolution = time.Minute
+
+	// apiKeyUsageTimeout limits the time to write the last usage of a key.
+	apiKeyUsageTimeout = 5 * time.Second
+)
+
+// GeneratedAPIKey holds a newly generated API key.
+type GeneratedAPIKey struct {
+	// Key is the plaintext key, which must only be shown once.
+	Key string
+	// Prefix identifies the key for humans without revealing it.
+	Prefix string
+	// Hash is stored to verify the key.
+	Hash string
+}
+
+// GenerateAPIKey creates a new random API key.
+func GenerateAPIKey() (*GeneratedAPIKey, error) {
+	secret := make([]byte, apiKeySecretLength)
+
+	_, err := rand.Read(secret)
+	if err != nil {
+		return nil, fmt.Errorf("error generating API key: %w", err)
+	}
+
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &GeneratedAPIKey{
+		Key:    key,
+		Prefix: key[:apiKeyDisplayPrefixLength],
+		Hash:   HashAPIKey(key),
+	}, nil
+}
+
+// HashAPIKey hashes the plaintext API key for storage and lookup.
+// API keys have enough entropy, that a fast hash is sufficient.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// IsAPIKey reports, if the bearer token is an API key.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// StoredAPIKey describes a stored API key as needed for authentication.
+type StoredAPIKey struct {
+	ID         string
+	Name       string
+	Scopes     []Scope
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// APIKeyStore looks up API keys and records their usage.
+type APIKeyStore interface {
+	// APIKeyByHash returns the API key with the hash or [ErrUnknownAPIKey].
+	APIKeyByHash(ctx context.Context, hash string) (*StoredAPIKey, error)
+	// MarkAPIKeyUsed records the last usage of the API key.
+	MarkAPIKeyUsed(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// APIKeyAuthenticator verifies API keys against the [APIKeyStore].
+type APIKeyAuthenticator struct {
+	store  APIKeyStore
+	logger *zerolog.Logger
+}
+
+// NewAPIKeyAuthenticator creates a new [APIKeyAuthenticator].
+func NewAPIKeyAuthenticator(store APIKeyStore, logger *zerolog.Logger) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Authenticate verifies the API key and returns the principal with the scopes of the key.
+// The last usage is recorded asynchronously, to not delay the request.
+func (ak *APIKeyAuthenticator) Authenticate(ctx context.Context, key string) (*Principal, error) {
+	if !IsAPIKey(key) {
+		return nil, fmt.Errorf("%w: malformed API key", ErrInvalidToken)
+	}
+
+	storedKey, err := ak.store.APIKeyByHash(ctx, HashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, ErrUnknownAPIKey) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+
+		return nil, fmt.Errorf("error loading API key: %w", err)
+	}
+
+	now := time.Now()
+
+	if storedKey.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: API key revoked", ErrInvalidToken)
+	}
+
+	if storedKey.ExpiresAt != nil && !now.Before(*storedKey.ExpiresAt) {
+		return nil, fmt.Errorf("%w: API key expired", ErrInvalidToken)
+	}
+
+	if storedKey.LastUsedAt == nil || now.Sub(*storedKey.LastUsedAt) >= apiKeyUsageResolution {
+		go ak.markUsed(storedKey.ID, now)
+	}
+
+	return &Principal{
+		Subject: "apikey:" + storedKey.Name,
+		Scopes:  storedKey.Scopes,
+		Claims:  map[string]interface{}{"apiKeyId": storedKey.ID},
+	}, nil
+}
+
+func (ak *APIKeyAuthenticator) markUsed(id string, usedAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), apiKeyUsageTimeout)
+	defer cancel()
+
+	err := ak.store.MarkAPIKeyUsed(ctx, id, usedAt)
+	if err != nil {
+		ak.logger.Warn().Err(err).Str("apiKeyId", id).Msg("error recording API key usage")
+	}
+}
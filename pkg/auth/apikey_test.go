@@ -0,0 +1,188 @@
+package auth_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+const testAPIKeyID = "4f9c3e2b-1d6a-4b8e-9f0a-2c5d7e8f9a1b"
+
+// memoryAPIKeyStore holds API keys by hash and records their usage.
+type memoryAPIKeyStore struct {
+	mutex  sync.Mutex
+	keys   map[string]*auth.StoredAPIKey
+	err    error
+	usages []string
+}
+
+func (s *memoryAPIKeyStore) APIKeyByHash(_ context.Context, hash string) (*auth.StoredAPIKey, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	key, ok := s.keys[hash]
+	if !ok {
+		return nil, auth.ErrUnknownAPIKey
+	}
+
+	return key, nil
+}
+
+func (s *memoryAPIKeyStore) MarkAPIKeyUsed(_ context.Context, id string, _ time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.usages = append(s.usages, id)
+
+	return nil
+}
+
+func (s *memoryAPIKeyStore) recordedUsages() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]string{}, s.usages...)
+}
+
+var _ = Describe("APIKey", func() {
+	var (
+		_, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store         *memoryAPIKeyStore
+		authenticator *auth.APIKeyAuthenticator
+		generatedKey  *auth.GeneratedAPIKey
+		storedKey     *auth.StoredAPIKey
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		generatedKey, err = auth.GenerateAPIKey()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		storedKey = &auth.StoredAPIKey{
+			ID:     testAPIKeyID,
+			Name:   "ci",
+			Scopes: []auth.Scope{auth.ScopeEditor},
+		}
+
+		store = &memoryAPIKeyStore{keys: map[string]*auth.StoredAPIKey{generatedKey.Hash: storedKey}}
+		authenticator = auth.NewAPIKeyAuthenticator(store, handlerLogger)
+	})
+
+	Describe("GenerateAPIKey", func() {
+		It("should generate prefixed unique keys", func() {
+			// Act
+			otherKey, err := auth.GenerateAPIKey()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(auth.IsAPIKey(generatedKey.Key)).Should(BeTrue())
+			Ω(generatedKey.Key).ShouldNot(Equal(otherKey.Key))
+			Ω(generatedKey.Key).Should(HavePrefix(generatedKey.Prefix))
+			Ω(generatedKey.Hash).Should(Equal(auth.HashAPIKey(generatedKey.Key)))
+			Ω(generatedKey.Hash).ShouldNot(ContainSubstring(generatedKey.Key))
+		})
+	})
+
+	Describe("Authenticate", func() {
+		Context("with valid key", func() {
+			It("should return the principal with the scopes of the key and record the usage", func() {
+				// Act
+				principal, err := authenticator.Authenticate(context.Background(), generatedKey.Key)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(principal.Subject).Should(Equal("apikey:ci"))
+				Ω(principal.Scopes).Should(Equal([]auth.Scope{auth.ScopeEditor}))
+				Eventually(store.recordedUsages).Should(Equal([]string{testAPIKeyID}))
+			})
+		})
+
+		Context("with recently used key", func() {
+			It("should not record the usage again", func() {
+				// Arrange
+				storedKey.LastUsedAt = test.Ptr(time.Now())
+
+				// Act
+				_, err := authenticator.Authenticate(context.Background(), generatedKey.Key)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Consistently(store.recordedUsages, 50*time.Millisecond).Should(BeEmpty())
+			})
+		})
+
+		Context("with unknown key", func() {
+			It("should return an ErrInvalidToken", func() {
+				// Act
+				principal, err := authenticator.Authenticate(context.Background(), auth.APIKeyPrefix+"unknown")
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrInvalidToken))
+				Ω(principal).Should(BeNil())
+			})
+		})
+
+		Context("with malformed key", func() {
+			It("should return an ErrInvalidToken", func() {
+				// Act
+				principal, err := authenticator.Authenticate(context.Background(), "not-a-key")
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrInvalidToken))
+				Ω(principal).Should(BeNil())
+			})
+		})
+
+		Context("with revoked key", func() {
+			It("should return an ErrInvalidToken", func() {
+				// Arrange
+				storedKey.RevokedAt = test.Ptr(time.Now().Add(-time.Hour))
+
+				// Act
+				principal, err := authenticator.Authenticate(context.Background(), generatedKey.Key)
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrInvalidToken))
+				Ω(principal).Should(BeNil())
+			})
+		})
+
+		Context("with expired key", func() {
+			It("should return an ErrInvalidToken", func() {
+				// Arrange
+				storedKey.ExpiresAt = test.Ptr(time.Now().Add(-time.Hour))
+
+				// Act
+				principal, err := authenticator.Authenticate(context.Background(), generatedKey.Key)
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrInvalidToken))
+				Ω(principal).Should(BeNil())
+			})
+		})
+
+		Context("with store error", func() {
+			It("should return the error", func() {
+				// Arrange
+				store.err = test.ErrTestError
+
+				// Act
+				principal, err := authenticator.Authenticate(context.Background(), generatedKey.Key)
+
+				// Assert
+				Ω(err).Should(MatchError(test.ErrTestError))
+				Ω(err).ShouldNot(MatchError(auth.ErrInvalidToken))
+				Ω(principal).Should(BeNil())
+			})
+		})
+	})
+})
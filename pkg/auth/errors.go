@@ -0,0 +1,36 @@
+package auth
+
+import "errors"
+
+var (
+	// ErrNoCredentials means the request did not carry any credentials.
+	// This can be seen as 401 - Unauthorized.
+	ErrNoCredentials = errors.New("no credentials")
+
+	// ErrInvalidToken means the given token could not be verified.
+	// This can be seen as 401 - Unauthorized.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrUnknownAPIKey means no API key with the given hash exists.
+	ErrUnknownAPIKey = errors.New("unknown API key")
+
+	// ErrInsufficientScope means the principal lacks the scope required for the operation.
+	// This can be seen as 403 - Forbidden.
+	ErrInsufficientScope = errors.New("insufficient scope")
+
+	// ErrUnmappedOperation means no required scope is declared for an operation.
+	// Such operations are denied.
+	ErrUnmappedOperation = errors.New("operation without required scope")
+
+	// ErrUnknownScope means a scope name is not known.
+	ErrUnknownScope = errors.New("unknown scope")
+
+	// ErrUnsupportedKey means a key of the key set has an unsupported type or parameters.
+	ErrUnsupportedKey = errors.New("unsupported key")
+
+	// ErrUnknownKey means no key with the requested key ID is known.
+	ErrUnknownKey = errors.New("unknown key")
+
+	// ErrKeySetUnavailable means the key set could not be loaded.
+	ErrKeySetUnavailable = errors.New("key set unavailable")
+)
@@ -0,0 +1,315 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultKeySetRefreshInterval is the minimal time between two refreshes of a remote key set.
+const DefaultKeySetRefreshInterval = time.Minute
+
+// maxKeySetSize limits the size of a key set document read from a remote source.
+const maxKeySetSize = 1 << 20
+
+// jsonWebKey is a single key of a JSON Web Key Set as defined by RFC 7517.
+type jsonWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	Curve   string `json:"crv"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// jsonWebKeySet is a JSON Web Key Set as defined by RFC 7517.
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+func decodeBase64URLInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding key parameter: %w", err)
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+func (jwk *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	modulus, err := decodeBase64URLInt(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent, err := decodeBase64URLInt(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exponent.IsInt64() || exponent.Int64() > int64(^uint32(0)>>1) {
+		return nil, fmt.Errorf("%w: invalid RSA exponent", ErrUnsupportedKey)
+	}
+
+	return &rsa.PublicKey{
+		N: modulus,
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+func (jwk *jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+
+	switch jwk.Curve {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKey, jwk.Curve)
+	}
+
+	pointX, err := decodeBase64URLInt(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+
+	pointY, err := decodeBase64URLInt(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	if !curve.IsOnCurve(pointX, pointY) { //nolint:staticcheck // only used to validate the public key.
+		return nil, fmt.Errorf("%w: point not on curve", ErrUnsupportedKey)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     pointX,
+		Y:     pointY,
+	}, nil
+}
+
+func (jwk *jsonWebKey) ed25519PublicKey() (ed25519.PublicKey, error) {
+	if jwk.Curve != "Ed25519" {
+		return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKey, jwk.Curve)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding key parameter: %w", err)
+	}
+
+	if len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: invalid Ed25519 key size", ErrUnsupportedKey)
+	}
+
+	return ed25519.PublicKey(data), nil
+}
+
+// publicKey converts the JSON Web Key to a public key usable for verification.
+func (jwk *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		return jwk.rsaPublicKey()
+	case "EC":
+		return jwk.ecdsaPublicKey()
+	case "OKP":
+		return jwk.ed25519PublicKey()
+	default:
+		return nil, fmt.Errorf("%w: key type %s", ErrUnsupportedKey, jwk.KeyType)
+	}
+}
+
+// ParseKeySet parses a JSON Web Key Set and returns all signing keys by their key ID.
+// Keys with unsupported types are skipped.
+func ParseKeySet(data []byte) (map[string]crypto.PublicKey, error) {
+	var keySet jsonWebKeySet
+
+	err := json.Unmarshal(data, &keySet)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding key set: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(keySet.Keys))
+
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.KeyID] = key
+	}
+
+	return keys, nil
+}
+
+// KeySet holds the public keys used to verify token signatures.
+// Keys loaded from a remote source are refreshed, when an unknown key ID is requested.
+type KeySet struct {
+	mutex           sync.RWMutex
+	keys            map[string]crypto.PublicKey
+	fetch           func(ctx context.Context) ([]byte, error)
+	refreshInterval time.Duration
+	lastRefresh     time.Time
+}
+
+// NewStaticKeySet creates a [KeySet] from already loaded keys, which is never refreshed.
+func NewStaticKeySet(keys map[string]crypto.PublicKey) *KeySet {
+	return &KeySet{ //nolint:exhaustruct
+		keys: keys,
+	}
+}
+
+// NewKeySetFromFile creates a [KeySet] from a local JSON Web Key Set file.
+// This is intended for air-gapped setups without access to the issuer.
+func NewKeySetFromFile(filename string) (*KeySet, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key set file `%s`: %w", filename, err)
+	}
+
+	keys, err := ParseKeySet(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStaticKeySet(keys), nil
+}
+
+// NewKeySetFromURL creates a [KeySet] from a remote JSON Web Key Set and loads it initially.
+func NewKeySetFromURL(
+	ctx context.Context,
+	url string,
+	client *http.Client,
+	refreshInterval time.Duration,
+) (*KeySet, error) {
+	keySet := &KeySet{ //nolint:exhaustruct
+		fetch: func(ctx context.Context) ([]byte, error) {
+			return fetchKeySet(ctx, url, client)
+		},
+		refreshInterval: refreshInterval,
+	}
+
+	err := keySet.refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return keySet, nil
+}
+
+func fetchKeySet(ctx context.Context, url string, client *http.Client) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating key set request: %w", err)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrKeySetUnavailable, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrKeySetUnavailable, response.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, maxKeySetSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading key set: %w", err)
+	}
+
+	return data, nil
+}
+
+func (ks *KeySet) refresh(ctx context.Context) error {
+	data, err := ks.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := ParseKeySet(data)
+	if err != nil {
+		return err
+	}
+
+	ks.mutex.Lock()
+	defer ks.mutex.Unlock()
+
+	ks.keys = keys
+	ks.lastRefresh = time.Now()
+
+	return nil
+}
+
+func (ks *KeySet) lookup(keyID string) (crypto.PublicKey, bool) {
+	ks.mutex.RLock()
+	defer ks.mutex.RUnlock()
+
+	key, ok := ks.keys[keyID]
+
+	return key, ok
+}
+
+func (ks *KeySet) refreshAllowed() bool {
+	ks.mutex.RLock()
+	defer ks.mutex.RUnlock()
+
+	return ks.fetch != nil && time.Since(ks.lastRefresh) >= ks.refreshInterval
+}
+
+// Key returns the public key for the key ID.
+// Remote key sets are refreshed once per refresh interval, if the key ID is unknown, to support key rotation.
+func (ks *KeySet) Key(ctx context.Context, keyID string) (crypto.PublicKey, error) { //nolint:ireturn
+	key, ok := ks.lookup(keyID)
+	if ok {
+		return key, nil
+	}
+
+	if ks.refreshAllowed() {
+		err := ks.refresh(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok = ks.lookup(keyID)
+		if ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+}
+
+// Keyfunc creates a [jwt.Keyfunc] resolving keys by the `kid` header of the token.
+func (ks *KeySet) Keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+
+		return ks.Key(ctx, keyID)
+	}
+}
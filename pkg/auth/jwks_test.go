@@ -0,0 +1,243 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testKeyID      = "test-key"
+	testOtherKeyID = "other-key"
+)
+
+// mustGenerateRSAKey creates a small RSA key for testing.
+func mustGenerateRSAKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048) //nolint:mnd
+	Ω(err).ShouldNot(HaveOccurred())
+
+	return key
+}
+
+func encodeBigInt(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+// rsaJWK converts an RSA public key to its JSON Web Key representation.
+func rsaJWK(keyID string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": keyID,
+		"use": "sig",
+		"n":   encodeBigInt(key.N),
+		"e":   encodeBigInt(big.NewInt(int64(key.E))),
+	}
+}
+
+// mustMarshalKeySet creates a JSON Web Key Set from the keys.
+func mustMarshalKeySet(keys ...map[string]string) []byte {
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	Ω(err).ShouldNot(HaveOccurred())
+
+	return data
+}
+
+var _ = Describe("JWKS", func() {
+	Describe("ParseKeySet", func() {
+		Context("with supported keys", func() {
+			It("should parse RSA, EC and Ed25519 keys", func() {
+				// Arrange
+				rsaKey := mustGenerateRSAKey()
+
+				ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				edPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				data := mustMarshalKeySet(
+					rsaJWK("rsa", &rsaKey.PublicKey),
+					map[string]string{
+						"kty": "EC",
+						"kid": "ec",
+						"crv": "P-256",
+						"x":   encodeBigInt(ecKey.X),
+						"y":   encodeBigInt(ecKey.Y),
+					},
+					map[string]string{
+						"kty": "OKP",
+						"kid": "ed",
+						"crv": "Ed25519",
+						"x":   base64.RawURLEncoding.EncodeToString(edPublicKey),
+					},
+				)
+
+				// Act
+				keys, err := auth.ParseKeySet(data)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(keys).Should(HaveLen(3))
+				Ω(keys["rsa"]).Should(Equal(&rsaKey.PublicKey))
+				Ω(keys["ec"]).Should(Equal(&ecKey.PublicKey))
+				Ω(keys["ed"]).Should(Equal(edPublicKey))
+			})
+		})
+
+		Context("with unsupported and encryption keys", func() {
+			It("should skip them", func() {
+				// Arrange
+				rsaKey := mustGenerateRSAKey()
+				encryptionKey := rsaJWK("enc", &rsaKey.PublicKey)
+				encryptionKey["use"] = "enc"
+
+				data := mustMarshalKeySet(
+					encryptionKey,
+					map[string]string{"kty": "oct", "kid": "symmetric", "k": "c2VjcmV0"},
+				)
+
+				// Act
+				keys, err := auth.ParseKeySet(data)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(keys).Should(BeEmpty())
+			})
+		})
+
+		Context("with invalid json", func() {
+			It("should return an error", func() {
+				// Act
+				_, err := auth.ParseKeySet([]byte("{"))
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("NewKeySetFromFile", func() {
+		It("should load keys from a file", func() {
+			// Arrange
+			rsaKey := mustGenerateRSAKey()
+			filename := filepath.Join(GinkgoT().TempDir(), "jwks.json")
+
+			err := os.WriteFile(filename, mustMarshalKeySet(rsaJWK(testKeyID, &rsaKey.PublicKey)), 0o600)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			keySet, err := auth.NewKeySetFromFile(filename)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			key, err := keySet.Key(context.Background(), testKeyID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(key).Should(Equal(&rsaKey.PublicKey))
+
+			_, err = keySet.Key(context.Background(), testOtherKeyID)
+			Ω(err).Should(MatchError(auth.ErrUnknownKey))
+		})
+
+		It("should fail for a missing file", func() {
+			// Act
+			_, err := auth.NewKeySetFromFile(filepath.Join(GinkgoT().TempDir(), "missing.json"))
+
+			// Assert
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Describe("NewKeySetFromURL", func() {
+		var (
+			firstKey, secondKey *rsa.PrivateKey
+			requests            atomic.Int32
+			keySetServer        *httptest.Server
+		)
+
+		BeforeEach(func() {
+			firstKey = mustGenerateRSAKey()
+			secondKey = mustGenerateRSAKey()
+			requests.Store(0)
+
+			keySetServer = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+				// the second key is rotated in after the first request.
+				keys := []map[string]string{rsaJWK(testKeyID, &firstKey.PublicKey)}
+				if requests.Add(1) > 1 {
+					keys = append(keys, rsaJWK(testOtherKeyID, &secondKey.PublicKey))
+				}
+
+				_, _ = writer.Write(mustMarshalKeySet(keys...))
+			}))
+		})
+
+		AfterEach(func() {
+			keySetServer.Close()
+		})
+
+		Context("with rotated keys", func() {
+			It("should refresh the key set for unknown key IDs", func() {
+				// Arrange
+				keySet, err := auth.NewKeySetFromURL(context.Background(), keySetServer.URL, keySetServer.Client(), 0)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				// Act
+				key, err := keySet.Key(context.Background(), testOtherKeyID)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(key).Should(Equal(&secondKey.PublicKey))
+				Ω(requests.Load()).Should(BeEquivalentTo(2))
+			})
+		})
+
+		Context("within the refresh interval", func() {
+			It("should not refresh the key set", func() {
+				// Arrange
+				keySet, err := auth.NewKeySetFromURL(
+					context.Background(),
+					keySetServer.URL,
+					keySetServer.Client(),
+					time.Hour,
+				)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				// Act
+				_, err = keySet.Key(context.Background(), testOtherKeyID)
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrUnknownKey))
+				Ω(requests.Load()).Should(BeEquivalentTo(1))
+			})
+		})
+
+		Context("with unavailable key set", func() {
+			It("should return ErrKeySetUnavailable", func() {
+				// Arrange
+				keySetServer.Config.Handler = http.NotFoundHandler()
+
+				// Act
+				_, err := auth.NewKeySetFromURL(context.Background(), keySetServer.URL, keySetServer.Client(), 0)
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrKeySetUnavailable))
+			})
+		})
+	})
+})
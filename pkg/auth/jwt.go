@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// validSigningMethods lists all asymmetric signing algorithms accepted for tokens.
+// Symmetric algorithms are never accepted, as the key set only holds public keys.
+var validSigningMethods = []string{ //nolint:gochecknoglobals
+	jwt.SigningMethodRS256.Alg(), jwt.SigningMethodRS384.Alg(), jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodPS256.Alg(), jwt.SigningMethodPS384.Alg(), jwt.SigningMethodPS512.Alg(),
+	jwt.SigningMethodES256.Alg(), jwt.SigningMethodES384.Alg(), jwt.SigningMethodES512.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
+// JWTAuthenticator verifies JWT bearer tokens against an issuer, audience and key set.
+type JWTAuthenticator struct {
+	keySet      *KeySet
+	scopeMapper *ClaimScopeMapper
+	parser      *jwt.Parser
+}
+
+// NewJWTAuthenticator creates a new [JWTAuthenticator].
+// The scopes of the principal are read from the token claims by the scope mapper.
+func NewJWTAuthenticator(
+	issuer string,
+	audience string,
+	keySet *KeySet,
+	scopeMapper *ClaimScopeMapper,
+) *JWTAuthenticator {
+	return &JWTAuthenticator{
+		keySet:      keySet,
+		scopeMapper: scopeMapper,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(validSigningMethods),
+			jwt.WithIssuer(issuer),
+			jwt.WithAudience(audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+		),
+	}
+}
+
+// Authenticate verifies the token and returns the principal described by its claims.
+func (ja *JWTAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := ja.parser.ParseWithClaims(token, claims, ja.keySet.Keyfunc(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return &Principal{
+		Subject: subject,
+		Scopes:  ja.scopeMapper.Scopes(claims),
+		Claims:  claims,
+	}, nil
+}
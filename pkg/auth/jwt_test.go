@@ -0,0 +1,149 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "status-page-api"
+	testSubject  = "operator"
+)
+
+// validClaims creates claims accepted by an authenticator configured with the test issuer and audience.
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss": testIssuer,
+		"aud": testAudience,
+		"sub": testSubject,
+		"iat": time.Now().Add(-time.Minute).Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// mustSignToken signs the claims with RS256 and sets the key ID header.
+func mustSignToken(key *rsa.PrivateKey, keyID string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = keyID
+
+	signed, err := token.SignedString(key)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	return signed
+}
+
+var _ = Describe("JWTAuthenticator", func() {
+	var (
+		signingKey    *rsa.PrivateKey
+		authenticator *auth.JWTAuthenticator
+	)
+
+	BeforeEach(func() {
+		signingKey = mustGenerateRSAKey()
+		keySet := auth.NewStaticKeySet(map[string]crypto.PublicKey{testKeyID: &signingKey.PublicKey})
+		authenticator = auth.NewJWTAuthenticator(testIssuer, testAudience, keySet, &auth.ClaimScopeMapper{
+			Claim:   "roles",
+			Mapping: map[string]auth.Scope{"status-admins": auth.ScopeAdmin},
+		})
+	})
+
+	Describe("Authenticate", func() {
+		Context("with valid token", func() {
+			It("should return the principal", func() {
+				// Arrange
+				token := mustSignToken(signingKey, testKeyID, validClaims())
+
+				// Act
+				principal, err := authenticator.Authenticate(context.Background(), token)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(principal.Subject).Should(Equal(testSubject))
+				Ω(principal.Claims).Should(HaveKeyWithValue("iss", testIssuer))
+				Ω(principal.Scopes).Should(BeEmpty())
+			})
+		})
+
+		Context("with roles claim", func() {
+			It("should map the roles to scopes", func() {
+				// Arrange
+				claims := validClaims()
+				claims["roles"] = []string{"status-admins", "read", "unrelated"}
+				token := mustSignToken(signingKey, testKeyID, claims)
+
+				// Act
+				principal, err := authenticator.Authenticate(context.Background(), token)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(principal.Scopes).Should(ConsistOf(auth.ScopeAdmin, auth.ScopeRead))
+			})
+		})
+
+		DescribeTable("with invalid token",
+			func(modify func(claims jwt.MapClaims) string) {
+				// Arrange
+				claims := validClaims()
+				token := modify(claims)
+
+				// Act
+				principal, err := authenticator.Authenticate(context.Background(), token)
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrInvalidToken))
+				Ω(principal).Should(BeNil())
+			},
+			Entry("wrong issuer", func(claims jwt.MapClaims) string {
+				claims["iss"] = "https://evil.example.com"
+
+				return mustSignToken(signingKey, testKeyID, claims)
+			}),
+			Entry("wrong audience", func(claims jwt.MapClaims) string {
+				claims["aud"] = "other-api"
+
+				return mustSignToken(signingKey, testKeyID, claims)
+			}),
+			Entry("expired", func(claims jwt.MapClaims) string {
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+
+				return mustSignToken(signingKey, testKeyID, claims)
+			}),
+			Entry("without expiration", func(claims jwt.MapClaims) string {
+				delete(claims, "exp")
+
+				return mustSignToken(signingKey, testKeyID, claims)
+			}),
+			Entry("without subject", func(claims jwt.MapClaims) string {
+				delete(claims, "sub")
+
+				return mustSignToken(signingKey, testKeyID, claims)
+			}),
+			Entry("unknown key", func(claims jwt.MapClaims) string {
+				return mustSignToken(signingKey, testOtherKeyID, claims)
+			}),
+			Entry("foreign signature", func(claims jwt.MapClaims) string {
+				return mustSignToken(mustGenerateRSAKey(), testKeyID, claims)
+			}),
+			Entry("symmetric algorithm", func(claims jwt.MapClaims) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+				token.Header["kid"] = testKeyID
+
+				signed, err := token.SignedString([]byte("secret"))
+				Ω(err).ShouldNot(HaveOccurred())
+
+				return signed
+			}),
+			Entry("garbage", func(_ jwt.MapClaims) string {
+				return "not.a.token"
+			}),
+		)
+	})
+})
@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// bearerPrefix is the authentication scheme prefix of the authorization header.
+	bearerPrefix = "Bearer "
+
+	// HeaderAPIKey is the header carrying an API key as alternative to the authorization header.
+	HeaderAPIKey = "X-API-Key"
+)
+
+// Authenticator verifies a credential and returns the authenticated principal.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (*Principal, error)
+}
+
+// MiddlewareConfig holds the configuration of the authentication middleware.
+type MiddlewareConfig struct {
+	// Skipper defines a function to skip the middleware entirely, e.g. for documentation endpoints.
+	Skipper middleware.Skipper
+	// Authenticator verifies JWT bearer tokens.
+	Authenticator Authenticator
+	// APIKeyAuthenticator verifies API keys, given as bearer token or by the [HeaderAPIKey] header.
+	// API keys are rejected, if not set.
+	APIKeyAuthenticator Authenticator
+	// Policy declares the scope required for every route.
+	Policy *Policy
+	// PublicRead grants the read scope to every caller, including unauthenticated ones.
+	PublicRead bool
+	// Realm is reported in the `WWW-Authenticate` header.
+	Realm string
+}
+
+// IsSafeMethod reports, if the HTTP method is read only.
+func IsSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// bearerToken extracts the bearer token from the authorization header.
+func bearerToken(request *http.Request) (string, error) {
+	header := request.Header.Get(echo.HeaderAuthorization)
+	if header == "" {
+		return "", ErrNoCredentials
+	}
+
+	if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", fmt.Errorf("%w: unsupported authorization scheme", ErrInvalidToken)
+	}
+
+	token := strings.TrimSpace(header[len(bearerPrefix):])
+	if token == "" {
+		return "", ErrNoCredentials
+	}
+
+	return token, nil
+}
+
+// Unauthorized builds the 401 response error and sets the `WWW-Authenticate` header as defined by RFC 6750.
+func Unauthorized(ctx echo.Context, realm string, err error) error {
+	challenge := fmt.Sprintf("Bearer realm=%q", realm)
+	if !errors.Is(err, ErrNoCredentials) {
+		challenge += `, error="invalid_token"`
+	}
+
+	ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, challenge)
+
+	return echo.ErrUnauthorized
+}
+
+// Forbidden builds the 403 response error and sets the `WWW-Authenticate` header as defined by RFC 6750.
+func Forbidden(ctx echo.Context, realm string, required Scope) error {
+	ctx.Response().Header().Set(
+		echo.HeaderWWWAuthenticate,
+		fmt.Sprintf("Bearer realm=%q, error=\"insufficient_scope\", scope=%q", realm, required),
+	)
+
+	return echo.ErrForbidden
+}
+
+// isRegisteredRoute reports, if the route is registered on the echo server.
+// Requests to other routes are left to echo, to respond with 404 or 405.
+func isRegisteredRoute(ctx echo.Context) bool {
+	return slices.ContainsFunc(ctx.Echo().Routes(), func(route *echo.Route) bool {
+		return route.Method == ctx.Request().Method && route.Path == ctx.Path()
+	})
+}
+
+// authenticateAPIKey verifies an API key.
+func (config *MiddlewareConfig) authenticateAPIKey(ctx context.Context, key string) (*Principal, error) {
+	if config.APIKeyAuthenticator == nil {
+		return nil, fmt.Errorf("%w: API keys are not supported", ErrInvalidToken)
+	}
+
+	return config.APIKeyAuthenticator.Authenticate(ctx, key) //nolint:wrapcheck
+}
+
+// authenticate verifies the credentials of the request.
+// Returns a nil principal without error, if the request carries no credentials.
+func (config *MiddlewareConfig) authenticate(request *http.Request) (*Principal, error) {
+	if key := request.Header.Get(HeaderAPIKey); key != "" {
+		return config.authenticateAPIKey(request.Context(), key)
+	}
+
+	token, err := bearerToken(request)
+	if errors.Is(err, ErrNoCredentials) {
+		return nil, nil //nolint:nilnil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if IsAPIKey(token) {
+		return config.authenticateAPIKey(request.Context(), token)
+	}
+
+	return config.Authenticator.Authenticate(request.Context(), token) //nolint:wrapcheck
+}
+
+// isAllowed reports, if the principal is granted the required scope.
+func (config *MiddlewareConfig) isAllowed(principal *Principal, required Scope) bool {
+	if config.PublicRead && ScopeRead.Includes(required) {
+		return true
+	}
+
+	return principal.HasScope(required)
+}
+
+// Middleware creates an echo middleware authenticating and authorizing every request, that is not skipped.
+// Requests without credentials are unauthenticated, and only allowed if the operation needs no scope.
+// Missing or invalid credentials are answered with 401, missing scopes with 403.
+// Authenticated requests carry the [Principal], which can be retrieved by [PrincipalFromContext].
+// Further scopes of the caller can be checked by [IsAllowed].
+func Middleware(config MiddlewareConfig, logger *zerolog.Logger) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			request := ctx.Request()
+			authLogger := logger.With().Str("method", request.Method).Str("path", ctx.Path()).Logger()
+
+			principal, err := config.authenticate(request)
+			if err != nil {
+				if !errors.Is(err, ErrInvalidToken) {
+					authLogger.Error().Err(err).Msg("error authenticating request")
+
+					return echo.ErrInternalServerError
+				}
+
+				authLogger.Warn().Err(err).Msg("authentication failed")
+
+				return Unauthorized(ctx, config.Realm, err)
+			}
+
+			if principal != nil {
+				authLogger = authLogger.With().Str("subject", principal.Subject).Logger()
+
+				SetPrincipal(ctx, principal)
+			}
+
+			setAuthorizer(ctx, func(required Scope) bool {
+				return config.isAllowed(principal, required)
+			})
+
+			operationID, required, err := config.Policy.RequiredScope(request.Method, ctx.Path())
+			if err != nil {
+				if !isRegisteredRoute(ctx) {
+					return next(ctx)
+				}
+
+				authLogger.Error().Err(err).Msg("denying operation without declared scope")
+
+				return echo.ErrForbidden
+			}
+
+			if !config.isAllowed(principal, required) {
+				if principal == nil {
+					authLogger.Warn().Str("operation", operationID).Msg("request not authenticated")
+
+					return Unauthorized(ctx, config.Realm, ErrNoCredentials)
+				}
+
+				authLogger.Warn().
+					Str("operation", operationID).
+					Str("required", string(required)).
+					Interface("scopes", principal.Scopes).
+					Msg("insufficient scope")
+
+				return Forbidden(ctx, config.Realm, required)
+			}
+
+			authLogger.Debug().Str("operation", operationID).Msg("authorized")
+
+			return next(ctx)
+		}
+	}
+}
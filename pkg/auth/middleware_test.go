@@ -0,0 +1,338 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+const (
+	noScopeToken = "no-scope-token"
+	editorToken  = "editor-token"
+	adminToken   = "admin-token"
+	testRealm    = "test"
+
+	editorAPIKey = auth.APIKeyPrefix + "editor"
+	brokenAPIKey = auth.APIKeyPrefix + "broken"
+)
+
+// tokenAuthenticator accepts fixed tokens with fixed scopes.
+type tokenAuthenticator struct{}
+
+func (tokenAuthenticator) Authenticate(_ context.Context, token string) (*auth.Principal, error) {
+	switch token {
+	case noScopeToken:
+		return &auth.Principal{Subject: testSubject}, nil
+	case editorToken:
+		return &auth.Principal{Subject: testSubject, Scopes: []auth.Scope{auth.ScopeEditor}}, nil
+	case adminToken:
+		return &auth.Principal{Subject: testSubject, Scopes: []auth.Scope{auth.ScopeAdmin}}, nil
+	default:
+		return nil, auth.ErrInvalidToken
+	}
+}
+
+// apiKeyAuthenticator accepts a fixed API key and fails for a broken one.
+type apiKeyAuthenticator struct{}
+
+func (apiKeyAuthenticator) Authenticate(_ context.Context, key string) (*auth.Principal, error) {
+	switch key {
+	case editorAPIKey:
+		return &auth.Principal{Subject: "apikey:test", Scopes: []auth.Scope{auth.ScopeEditor}}, nil
+	case brokenAPIKey:
+		return nil, test.ErrTestError
+	default:
+		return nil, auth.ErrInvalidToken
+	}
+}
+
+var _ = Describe("Middleware", func() {
+	var (
+		_, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		echoServer *echo.Echo
+		subject    string
+		admin      bool
+	)
+
+	setupServer := func(publicRead bool) {
+		policy, err := auth.NewPolicy(map[auth.Route]string{
+			{Method: http.MethodGet, Path: "/resources"}:    "getResources",
+			{Method: http.MethodPost, Path: "/resources"}:   "createResource",
+			{Method: http.MethodDelete, Path: "/resources"}: "deleteResources",
+		}, map[string]auth.Scope{
+			"getResources":    auth.ScopeRead,
+			"createResource":  auth.ScopeEditor,
+			"deleteResources": auth.ScopeAdmin,
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		echoServer = echo.New()
+		echoServer.Use(auth.Middleware(auth.MiddlewareConfig{
+			Skipper: func(ctx echo.Context) bool {
+				return ctx.Path() == "/public"
+			},
+			Authenticator:       tokenAuthenticator{},
+			APIKeyAuthenticator: apiKeyAuthenticator{},
+			Policy:              policy,
+			PublicRead:          publicRead,
+			Realm:               testRealm,
+		}, handlerLogger))
+
+		handler := func(ctx echo.Context) error {
+			subject = ""
+			if principal := auth.PrincipalFromContext(ctx); principal != nil {
+				subject = principal.Subject
+			}
+
+			admin = auth.IsAllowed(ctx, auth.ScopeAdmin)
+
+			return ctx.NoContent(http.StatusNoContent)
+		}
+
+		echoServer.GET("/resources", handler)
+		echoServer.POST("/resources", handler)
+		echoServer.DELETE("/resources", handler)
+		echoServer.POST("/unmapped", handler)
+		echoServer.POST("/public", handler)
+	}
+
+	serve := func(method string, target string, token string) *httptest.ResponseRecorder {
+		req, res := test.MustCreateRequestAndResponseWriter(method, target, nil)
+		if token != "" {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+
+		echoServer.ServeHTTP(res, req)
+
+		return res
+	}
+
+	BeforeEach(func() {
+		subject = "unset"
+		admin = false
+	})
+
+	It("should allow all scopes without middleware", func() {
+		// Arrange
+		ctx := echo.New().NewContext(test.MustCreateRequestAndResponseWriter(http.MethodGet, "/resources", nil))
+
+		// Act & Assert
+		Ω(auth.IsAllowed(ctx, auth.ScopeAdmin)).Should(BeTrue())
+	})
+
+	Context("with public read enabled", func() {
+		BeforeEach(func() {
+			setupServer(true)
+		})
+
+		It("should allow anonymous reads", func() {
+			// Act
+			res := serve(http.MethodGet, "/resources", "")
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(subject).Should(BeEmpty())
+		})
+
+		It("should allow reads without read scope", func() {
+			// Act
+			res := serve(http.MethodGet, "/resources", noScopeToken)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(subject).Should(Equal(testSubject))
+		})
+
+		It("should reject reads carrying an invalid token", func() {
+			// Act
+			res := serve(http.MethodGet, "/resources", "invalid")
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusUnauthorized))
+		})
+
+		It("should reject anonymous writes with 401", func() {
+			// Act
+			res := serve(http.MethodPost, "/resources", "")
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusUnauthorized))
+			Ω(res.Header().Get(echo.HeaderWWWAuthenticate)).Should(Equal(`Bearer realm="test"`))
+		})
+
+		It("should allow writes with sufficient scope", func() {
+			// Act
+			res := serve(http.MethodPost, "/resources", editorToken)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(subject).Should(Equal(testSubject))
+		})
+
+		It("should allow writes with a higher scope", func() {
+			// Act
+			res := serve(http.MethodPost, "/resources", adminToken)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+		})
+
+		It("should check further scopes of the caller", func() {
+			// Act
+			anonymous := serve(http.MethodGet, "/resources", "")
+			anonymousIsAdmin := admin
+			res := serve(http.MethodGet, "/resources", adminToken)
+
+			// Assert
+			Ω(anonymous.Code).Should(Equal(http.StatusNoContent))
+			Ω(anonymousIsAdmin).Should(BeFalse())
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(admin).Should(BeTrue())
+		})
+
+		It("should reject writes with insufficient scope with 403", func() {
+			// Act
+			res := serve(http.MethodDelete, "/resources", editorToken)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusForbidden))
+			Ω(res.Header().Get(echo.HeaderWWWAuthenticate)).
+				Should(Equal(`Bearer realm="test", error="insufficient_scope", scope="admin"`))
+		})
+
+		It("should deny registered routes without declared scope", func() {
+			// Act
+			res := serve(http.MethodPost, "/unmapped", adminToken)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusForbidden))
+		})
+
+		It("should leave unknown routes to the router", func() {
+			// Act
+			res := serve(http.MethodGet, "/unknown", "")
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNotFound))
+		})
+
+		It("should skip configured paths", func() {
+			// Act
+			res := serve(http.MethodPost, "/public", "")
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+		})
+	})
+
+	Context("with public read disabled", func() {
+		BeforeEach(func() {
+			setupServer(false)
+		})
+
+		It("should reject anonymous reads with 401", func() {
+			// Act
+			res := serve(http.MethodGet, "/resources", "")
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusUnauthorized))
+		})
+
+		It("should reject reads without read scope with 403", func() {
+			// Act
+			res := serve(http.MethodGet, "/resources", noScopeToken)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusForbidden))
+		})
+
+		It("should allow reads with a higher scope", func() {
+			// Act
+			res := serve(http.MethodGet, "/resources", editorToken)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+		})
+
+		It("should reject invalid tokens with an invalid_token challenge", func() {
+			// Act
+			res := serve(http.MethodPost, "/resources", "invalid")
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusUnauthorized))
+			Ω(res.Header().Get(echo.HeaderWWWAuthenticate)).Should(Equal(`Bearer realm="test", error="invalid_token"`))
+		})
+
+		It("should reject other authorization schemes", func() {
+			// Arrange
+			req, res := test.MustCreateRequestAndResponseWriter(http.MethodGet, "/resources", nil)
+			req.Header.Set(echo.HeaderAuthorization, "Basic dXNlcjpwYXNz")
+
+			// Act
+			echoServer.ServeHTTP(res, req)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Context("with API keys", func() {
+		BeforeEach(func() {
+			setupServer(false)
+		})
+
+		It("should accept API keys as bearer token", func() {
+			// Act
+			res := serve(http.MethodPost, "/resources", editorAPIKey)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(subject).Should(Equal("apikey:test"))
+		})
+
+		It("should accept API keys in the API key header", func() {
+			// Arrange
+			req, res := test.MustCreateRequestAndResponseWriter(http.MethodPost, "/resources", nil)
+			req.Header.Set(auth.HeaderAPIKey, editorAPIKey)
+
+			// Act
+			echoServer.ServeHTTP(res, req)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(subject).Should(Equal("apikey:test"))
+		})
+
+		It("should enforce the scopes of API keys", func() {
+			// Act
+			res := serve(http.MethodDelete, "/resources", editorAPIKey)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusForbidden))
+		})
+
+		It("should reject unknown API keys with 401", func() {
+			// Act
+			res := serve(http.MethodGet, "/resources", auth.APIKeyPrefix+"unknown")
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusUnauthorized))
+		})
+
+		It("should respond with 500 if API keys can not be verified", func() {
+			// Act
+			res := serve(http.MethodGet, "/resources", brokenAPIKey)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusInternalServerError))
+		})
+	})
+})
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Route identifies a registered route by its HTTP method and echo path, e.g. `GET /components/:componentId`.
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Policy declares the operation behind every route and the scope required to call each operation.
+type Policy struct {
+	operations map[Route]string
+	scopes     map[string]Scope
+}
+
+// NewPolicy creates a new [Policy].
+// Every operation must have a required scope, otherwise [ErrUnmappedOperation] is returned,
+// so new operations can't be left open by accident.
+func NewPolicy(operations map[Route]string, scopes map[string]Scope) (*Policy, error) {
+	var unmapped []string
+
+	for _, operationID := range operations {
+		scope, ok := scopes[operationID]
+		if !ok || !scope.IsValid() {
+			unmapped = append(unmapped, operationID)
+		}
+	}
+
+	if len(unmapped) != 0 {
+		slices.Sort(unmapped)
+
+		return nil, fmt.Errorf("%w: %v", ErrUnmappedOperation, unmapped)
+	}
+
+	return &Policy{
+		operations: operations,
+		scopes:     scopes,
+	}, nil
+}
+
+// RequiredScope returns the operation ID and required scope of the route.
+func (p *Policy) RequiredScope(method string, path string) (string, Scope, error) {
+	operationID, ok := p.operations[Route{Method: method, Path: path}]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s %s", ErrUnmappedOperation, method, path)
+	}
+
+	return operationID, p.scopes[operationID], nil
+}
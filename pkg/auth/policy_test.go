@@ -0,0 +1,71 @@
+package auth_test
+
+import (
+	"net/http"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy", func() {
+	var route = auth.Route{Method: http.MethodGet, Path: "/resources/:id"}
+
+	Describe("NewPolicy", func() {
+		Context("with operation without scope", func() {
+			It("should return ErrUnmappedOperation", func() {
+				// Act
+				_, err := auth.NewPolicy(map[auth.Route]string{route: "getResource"}, map[string]auth.Scope{})
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrUnmappedOperation))
+				Ω(err.Error()).Should(ContainSubstring("getResource"))
+			})
+		})
+
+		Context("with operation with invalid scope", func() {
+			It("should return ErrUnmappedOperation", func() {
+				// Act
+				_, err := auth.NewPolicy(
+					map[auth.Route]string{route: "getResource"},
+					map[string]auth.Scope{"getResource": "root"},
+				)
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrUnmappedOperation))
+			})
+		})
+	})
+
+	Describe("RequiredScope", func() {
+		var policy *auth.Policy
+
+		BeforeEach(func() {
+			var err error
+
+			policy, err = auth.NewPolicy(
+				map[auth.Route]string{route: "getResource"},
+				map[string]auth.Scope{"getResource": auth.ScopeRead},
+			)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return operation and scope of known routes", func() {
+			// Act
+			operationID, scope, err := policy.RequiredScope(http.MethodGet, "/resources/:id")
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(operationID).Should(Equal("getResource"))
+			Ω(scope).Should(Equal(auth.ScopeRead))
+		})
+
+		It("should return ErrUnmappedOperation for unknown routes", func() {
+			// Act
+			_, _, err := policy.RequiredScope(http.MethodDelete, "/resources/:id")
+
+			// Assert
+			Ω(err).Should(MatchError(auth.ErrUnmappedOperation))
+		})
+	})
+})
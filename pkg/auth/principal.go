@@ -0,0 +1,56 @@
+package auth
+
+import "github.com/labstack/echo/v4"
+
+const (
+	// principalContextKey is the key used to store the [Principal] in the echo context.
+	principalContextKey = "auth.principal"
+	// authorizerContextKey is the key used to store the authorizer of the request in the echo context.
+	authorizerContextKey = "auth.authorizer"
+)
+
+// authorizer reports, if the caller of a request is granted the required scope.
+type authorizer func(required Scope) bool
+
+// Principal describes the authenticated caller of a request.
+type Principal struct {
+	// Subject identifies the caller, e.g. the `sub` claim of a token.
+	Subject string
+	// Scopes holds all scopes granted to the caller.
+	Scopes []Scope
+	// Claims holds all claims of the verified token.
+	Claims map[string]interface{}
+}
+
+// SetPrincipal stores the principal in the echo context.
+func SetPrincipal(ctx echo.Context, principal *Principal) {
+	ctx.Set(principalContextKey, principal)
+}
+
+// PrincipalFromContext retrieves the principal from the echo context.
+// Returns nil, if the request is not authenticated.
+func PrincipalFromContext(ctx echo.Context) *Principal {
+	principal, ok := ctx.Get(principalContextKey).(*Principal)
+	if !ok {
+		return nil
+	}
+
+	return principal
+}
+
+// setAuthorizer stores the authorizer of the caller in the echo context.
+func setAuthorizer(ctx echo.Context, isAllowed authorizer) {
+	ctx.Set(authorizerContextKey, isAllowed)
+}
+
+// IsAllowed reports, if the caller of the request is granted the required scope,
+// e.g. for parameters requiring a higher scope than the operation.
+// Without authentication middleware, all callers are allowed.
+func IsAllowed(ctx echo.Context, required Scope) bool {
+	isAllowed, ok := ctx.Get(authorizerContextKey).(authorizer)
+	if !ok {
+		return true
+	}
+
+	return isAllowed(required)
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Scope is a permission level granted to a [Principal].
+// Scopes are hierarchical, a higher scope includes all lower scopes.
+type Scope string
+
+const (
+	// ScopeRead allows reading all resources.
+	ScopeRead Scope = "read"
+	// ScopeEditor allows managing incidents and incident updates.
+	ScopeEditor Scope = "editor"
+	// ScopeAdmin allows managing all resources, e.g. components, impact types, severities and phases.
+	ScopeAdmin Scope = "admin"
+)
+
+// level orders the scopes, unknown scopes have no level.
+func (s Scope) level() int {
+	switch s {
+	case ScopeRead:
+		return 1
+	case ScopeEditor:
+		return 2 //nolint:mnd
+	case ScopeAdmin:
+		return 3 //nolint:mnd
+	default:
+		return 0
+	}
+}
+
+// IsValid reports, if the scope is known.
+func (s Scope) IsValid() bool {
+	return s.level() > 0
+}
+
+// Includes reports, if the scope grants the required scope.
+func (s Scope) Includes(required Scope) bool {
+	return s.IsValid() && s.level() >= required.level()
+}
+
+// ParseScope parses a scope by its name.
+func ParseScope(name string) (Scope, error) {
+	scope := Scope(strings.TrimSpace(name))
+	if !scope.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrUnknownScope, name)
+	}
+
+	return scope, nil
+}
+
+// ParseScopes parses a list of scope names.
+func ParseScopes(names []string) ([]Scope, error) {
+	scopes := make([]Scope, len(names))
+
+	for nameIndex, name := range names {
+		scope, err := ParseScope(name)
+		if err != nil {
+			return nil, err
+		}
+
+		scopes[nameIndex] = scope
+	}
+
+	return scopes, nil
+}
+
+// HasScope reports, if any scope of the principal grants the required scope.
+// An unauthenticated (nil) principal has no scopes.
+func (p *Principal) HasScope(required Scope) bool {
+	if p == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(p.Scopes, func(scope Scope) bool {
+		return scope.Includes(required)
+	})
+}
+
+// ClaimScopeMapper maps the values of a token claim to scopes.
+type ClaimScopeMapper struct {
+	// Claim is the name of the claim holding roles or scopes.
+	// Nested claims are separated by dots, e.g. `realm_access.roles`.
+	Claim string
+	// Mapping maps claim values to scopes.
+	// Values not listed are used as scope, if they name a scope.
+	Mapping map[string]Scope
+}
+
+// claimValues reads the claim as list of strings.
+// Space separated strings, like the OAuth `scope` claim, and string arrays are supported.
+func (m *ClaimScopeMapper) claimValues(claims map[string]interface{}) []string {
+	var value interface{} = claims
+
+	for _, part := range strings.Split(m.Claim, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		value = object[part]
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		return strings.Fields(typedValue)
+	case []interface{}:
+		values := make([]string, 0, len(typedValue))
+
+		for _, element := range typedValue {
+			if stringElement, ok := element.(string); ok {
+				values = append(values, stringElement)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
+
+// Scopes returns all scopes granted by the claims.
+func (m *ClaimScopeMapper) Scopes(claims map[string]interface{}) []Scope {
+	var scopes []Scope
+
+	for _, value := range m.claimValues(claims) {
+		scope, ok := m.Mapping[value]
+		if !ok {
+			scope = Scope(value)
+		}
+
+		if scope.IsValid() && !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
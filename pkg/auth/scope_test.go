@@ -0,0 +1,98 @@
+package auth_test
+
+import (
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scope", func() {
+	DescribeTable("Includes",
+		func(scope auth.Scope, required auth.Scope, expected bool) {
+			Ω(scope.Includes(required)).Should(Equal(expected))
+		},
+		Entry("read includes read", auth.ScopeRead, auth.ScopeRead, true),
+		Entry("read excludes editor", auth.ScopeRead, auth.ScopeEditor, false),
+		Entry("editor includes read", auth.ScopeEditor, auth.ScopeRead, true),
+		Entry("editor excludes admin", auth.ScopeEditor, auth.ScopeAdmin, false),
+		Entry("admin includes editor", auth.ScopeAdmin, auth.ScopeEditor, true),
+		Entry("unknown excludes read", auth.Scope("root"), auth.ScopeRead, false),
+	)
+
+	Describe("ParseScopes", func() {
+		It("should parse known scopes", func() {
+			// Act
+			scopes, err := auth.ParseScopes([]string{"read", " admin "})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(scopes).Should(Equal([]auth.Scope{auth.ScopeRead, auth.ScopeAdmin}))
+		})
+
+		It("should reject unknown scopes", func() {
+			// Act
+			_, err := auth.ParseScopes([]string{"read", "root"})
+
+			// Assert
+			Ω(err).Should(MatchError(auth.ErrUnknownScope))
+		})
+	})
+
+	Describe("HasScope", func() {
+		It("should be false for unauthenticated principals", func() {
+			// Arrange
+			var principal *auth.Principal
+
+			// Act and Assert
+			Ω(principal.HasScope(auth.ScopeRead)).Should(BeFalse())
+		})
+	})
+
+	Describe("ClaimScopeMapper", func() {
+		Context("with space separated scope claim", func() {
+			It("should return known scopes", func() {
+				// Arrange
+				mapper := auth.ClaimScopeMapper{Claim: "scope"}
+
+				// Act
+				scopes := mapper.Scopes(map[string]interface{}{"scope": "openid editor profile editor"})
+
+				// Assert
+				Ω(scopes).Should(Equal([]auth.Scope{auth.ScopeEditor}))
+			})
+		})
+
+		Context("with nested role claim and mapping", func() {
+			It("should return mapped scopes", func() {
+				// Arrange
+				mapper := auth.ClaimScopeMapper{
+					Claim:   "realm_access.roles",
+					Mapping: map[string]auth.Scope{"oncall": auth.ScopeEditor, "read": auth.ScopeAdmin},
+				}
+
+				// Act
+				scopes := mapper.Scopes(map[string]interface{}{
+					"realm_access": map[string]interface{}{
+						"roles": []interface{}{"oncall", "read", 42},
+					},
+				})
+
+				// Assert
+				Ω(scopes).Should(Equal([]auth.Scope{auth.ScopeEditor, auth.ScopeAdmin}))
+			})
+		})
+
+		Context("with missing claim", func() {
+			It("should return no scopes", func() {
+				// Arrange
+				mapper := auth.ClaimScopeMapper{Claim: "realm_access.roles"}
+
+				// Act
+				scopes := mapper.Scopes(map[string]interface{}{"realm_access": "admin"})
+
+				// Assert
+				Ω(scopes).Should(BeEmpty())
+			})
+		})
+	})
+})
@@ -0,0 +1,253 @@
+// Package availability computes the share of time components spent impacted, in buckets of a period.
+package availability
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+)
+
+// MaxBuckets is the highest number of buckets of a report.
+const MaxBuckets = 1000
+
+var (
+	// ErrInvalidGranularity is an error, raised when a granularity is unknown.
+	ErrInvalidGranularity = errors.New("invalid granularity")
+	// ErrInvalidPeriod is an error, raised when a period does not end after its start.
+	ErrInvalidPeriod = errors.New("period ends before it starts")
+	// ErrTooManyBuckets is an error, raised when a period splits into more than [MaxBuckets] buckets.
+	ErrTooManyBuckets = errors.New("too many buckets")
+)
+
+// Granularity is the length of the buckets of a period.
+type Granularity string
+
+const (
+	// Hour splits periods in hours.
+	Hour Granularity = "hour"
+	// Day splits periods in days.
+	Day Granularity = "day"
+	// Week splits periods in weeks.
+	Week Granularity = "week"
+	// Month splits periods in months.
+	Month Granularity = "month"
+)
+
+// next returns the start of the bucket after the bucket starting at the time.
+func (g Granularity) next(start time.Time) (time.Time, error) {
+	switch g {
+	case Hour:
+		return start.Add(time.Hour), nil
+	case Day:
+		return start.AddDate(0, 0, 1), nil
+	case Week:
+		return start.AddDate(0, 0, 7), nil //nolint:mnd
+	case Month:
+		return start.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidGranularity, g)
+	}
+}
+
+// Impact is a time, a component spent at a severity.
+type Impact struct {
+	Start time.Time
+	// End is nil for ongoing impacts.
+	End      *time.Time
+	Severity int
+}
+
+// Severity names a range of severity values, from above the value of the next lower severity up to its value.
+type Severity struct {
+	Name  string
+	Value int
+}
+
+// Period is the time to compute the availability for.
+type Period struct {
+	Start time.Time
+	End   time.Time
+	// Granularity splits the period in buckets, starting at the start of the period.
+	// Buckets keep the location of the start, so days start at midnight of its time zone.
+	Granularity Granularity
+	// ExcludeMaintenance does not consider times, a component only spent in maintenance.
+	ExcludeMaintenance bool
+	// Now ends the considered time, the future is not known.
+	Now time.Time
+}
+
+// Bucket is the availability of a component in a part of a period.
+type Bucket struct {
+	Start time.Time
+	End   time.Time
+	// Considered is the duration, the percentages refer to.
+	Considered time.Duration
+	// Available is the duration without impacts.
+	Available time.Duration
+	// AtOrAbove is the duration spent at or above each severity, by name.
+	AtOrAbove map[string]time.Duration
+}
+
+// Availability is the percentage of the considered time without impacts, nil if no time was considered.
+func (b *Bucket) Availability() *float64 {
+	return b.percentage(b.Available)
+}
+
+// Severities is the percentage of the considered time spent at or above each severity, nil if no time was considered.
+func (b *Bucket) Severities() map[string]float64 {
+	if b.Considered <= 0 {
+		return nil
+	}
+
+	percentages := make(map[string]float64, len(b.AtOrAbove))
+	for name, duration := range b.AtOrAbove {
+		percentages[name] = *b.percentage(duration)
+	}
+
+	return percentages
+}
+
+func (b *Bucket) percentage(duration time.Duration) *float64 {
+	if b.Considered <= 0 {
+		return nil
+	}
+
+	percentage := 100 * float64(duration) / float64(b.Considered) //nolint:mnd
+
+	return &percentage
+}
+
+// add accumulates the durations of the other bucket.
+func (b *Bucket) add(other Bucket) {
+	b.Considered += other.Considered
+	b.Available += other.Available
+
+	for name, duration := range other.AtOrAbove {
+		b.AtOrAbove[name] += duration
+	}
+}
+
+// Report is the availability of a component in a period.
+type Report struct {
+	// Total covers the whole period.
+	Total   Bucket
+	Buckets []Bucket
+}
+
+// Compute splits the period in buckets and computes the availability of a component with the impacts in each.
+// At any time, the component is rated by the highest severity of its impacts.
+// Severities must be sorted by value.
+func Compute(impacts []Impact, severities []Severity, period Period) (*Report, error) {
+	if !period.End.After(period.Start) {
+		return nil, ErrInvalidPeriod
+	}
+
+	report := Report{
+		Total:   newBucket(period.Start, period.End, severities),
+		Buckets: nil,
+	}
+
+	for start := period.Start; start.Before(period.End); {
+		if len(report.Buckets) == MaxBuckets {
+			return nil, fmt.Errorf("%w: more than %d", ErrTooManyBuckets, MaxBuckets)
+		}
+
+		end, err := period.Granularity.next(start)
+		if err != nil {
+			return nil, err
+		}
+
+		if end.After(period.End) {
+			end = period.End
+		}
+
+		bucket := newBucket(start, end, severities)
+		bucket.evaluate(impacts, severities, period)
+
+		report.Total.add(bucket)
+		report.Buckets = append(report.Buckets, bucket)
+
+		start = end
+	}
+
+	return &report, nil
+}
+
+func newBucket(start, end time.Time, severities []Severity) Bucket {
+	atOrAbove := make(map[string]time.Duration, len(severities))
+	for _, severity := range severities {
+		atOrAbove[severity.Name] = 0
+	}
+
+	return Bucket{Start: start, End: end, Considered: 0, Available: 0, AtOrAbove: atOrAbove}
+}
+
+// evaluate splits the bucket up to now at the bounds of the impacts and rates each part by its highest severity.
+func (b *Bucket) evaluate(impacts []Impact, severities []Severity, period Period) {
+	end := b.End
+	if period.Now.Before(end) {
+		end = period.Now
+	}
+
+	if !end.After(b.Start) {
+		return
+	}
+
+	bounds := []time.Time{b.Start, end}
+
+	for _, impact := range impacts {
+		bounds = append(bounds, impact.Start)
+		if impact.End != nil {
+			bounds = append(bounds, *impact.End)
+		}
+	}
+
+	bounds = slices.DeleteFunc(bounds, func(bound time.Time) bool {
+		return bound.Before(b.Start) || bound.After(end)
+	})
+	slices.SortFunc(bounds, func(a, b time.Time) int { return a.Compare(b) })
+	bounds = slices.CompactFunc(bounds, func(a, b time.Time) bool { return a.Equal(b) })
+
+	for index := 1; index < len(bounds); index++ {
+		partStart, partEnd := bounds[index-1], bounds[index]
+		duration := partEnd.Sub(partStart)
+
+		highest, impacted := highestSeverity(impacts, partStart, partEnd)
+
+		switch {
+		case !impacted:
+			b.Considered += duration
+			b.Available += duration
+		case highest == api.MaintenanceSeverity && period.ExcludeMaintenance:
+			// maintenance windows count neither as available nor as impacted.
+		default:
+			b.Considered += duration
+
+			for severityIndex, severity := range severities {
+				if severityIndex == 0 || highest > severities[severityIndex-1].Value {
+					b.AtOrAbove[severity.Name] += duration
+				}
+			}
+		}
+	}
+}
+
+// highestSeverity returns the highest severity of the impacts covering the time from start to end.
+func highestSeverity(impacts []Impact, start, end time.Time) (int, bool) {
+	highest, impacted := 0, false
+
+	for _, impact := range impacts {
+		if impact.Start.After(start) || (impact.End != nil && impact.End.Before(end)) {
+			continue
+		}
+
+		if !impacted || impact.Severity > highest {
+			highest, impacted = impact.Severity, true
+		}
+	}
+
+	return highest, impacted
+}
@@ -0,0 +1,168 @@
+package availability_test
+
+import (
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/availability"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Availability", func() {
+	var (
+		start = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+		severities = []availability.Severity{
+			{Name: "operational", Value: 33},
+			{Name: "limited", Value: 66},
+			{Name: "broken", Value: 100},
+		}
+
+		period availability.Period
+	)
+
+	BeforeEach(func() {
+		period = availability.Period{
+			Start:              start,
+			End:                start.AddDate(0, 0, 2),
+			Granularity:        availability.Day,
+			ExcludeMaintenance: false,
+			Now:                start.AddDate(1, 0, 0),
+		}
+	})
+
+	Describe("Compute", func() {
+		Context("without impacts", func() {
+			It("should report full availability", func() {
+				// Act
+				report, err := availability.Compute(nil, severities, period)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(report.Buckets).Should(HaveLen(2))
+				Ω(report.Buckets[1].Start).Should(Equal(start.AddDate(0, 0, 1)))
+				Ω(report.Total.Availability()).Should(HaveValue(BeNumerically("==", 100)))
+				Ω(report.Total.Severities()).Should(Equal(map[string]float64{
+					"operational": 0, "limited": 0, "broken": 0,
+				}))
+			})
+		})
+
+		Context("with overlapping impacts", func() {
+			It("should rate the time by the highest severity", func() {
+				// Arrange
+				impacts := []availability.Impact{
+					// 6 hours limited, of which 3 hours broken.
+					{Start: start.Add(6 * time.Hour), End: test.Ptr(start.Add(12 * time.Hour)), Severity: 50},
+					{Start: start.Add(9 * time.Hour), End: test.Ptr(start.Add(12 * time.Hour)), Severity: 100},
+					// ongoing from the second day on, 12 hours operational.
+					{Start: start.Add(36 * time.Hour), End: nil, Severity: 10},
+				}
+
+				// Act
+				report, err := availability.Compute(impacts, severities, period)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(report.Buckets[0].Availability()).Should(HaveValue(BeNumerically("~", 75)))
+				Ω(report.Buckets[0].Severities()).Should(Equal(map[string]float64{
+					"operational": 25, "limited": 25, "broken": 12.5,
+				}))
+				Ω(report.Buckets[1].Availability()).Should(HaveValue(BeNumerically("~", 50)))
+				Ω(report.Buckets[1].Severities()).Should(HaveKeyWithValue("limited", BeZero()))
+				Ω(report.Total.Availability()).Should(HaveValue(BeNumerically("~", 62.5)))
+			})
+		})
+
+		Context("with maintenance", func() {
+			var impacts []availability.Impact
+
+			BeforeEach(func() {
+				period.End = start.AddDate(0, 0, 1)
+				impacts = []availability.Impact{
+					{Start: start, End: test.Ptr(start.Add(12 * time.Hour)), Severity: 0},
+					{Start: start.Add(12 * time.Hour), End: test.Ptr(start.Add(18 * time.Hour)), Severity: 100},
+				}
+			})
+
+			It("should count maintenance as impacted time", func() {
+				// Act
+				report, err := availability.Compute(impacts, severities, period)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(report.Total.Availability()).Should(HaveValue(BeNumerically("~", 25)))
+				Ω(report.Total.Severities()).Should(HaveKeyWithValue("operational", BeNumerically("~", 75)))
+			})
+
+			It("should not consider maintenance, when excluded", func() {
+				// Arrange
+				period.ExcludeMaintenance = true
+
+				// Act
+				report, err := availability.Compute(impacts, severities, period)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(report.Total.Considered).Should(Equal(12 * time.Hour))
+				Ω(report.Total.Availability()).Should(HaveValue(BeNumerically("~", 50)))
+				Ω(report.Total.Severities()).Should(HaveKeyWithValue("broken", BeNumerically("~", 50)))
+			})
+		})
+
+		Context("with period reaching into the future", func() {
+			It("should only consider the time up to now", func() {
+				// Arrange
+				period.Now = start.Add(12 * time.Hour)
+				impacts := []availability.Impact{{Start: start.Add(6 * time.Hour), End: nil, Severity: 100}}
+
+				// Act
+				report, err := availability.Compute(impacts, severities, period)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(report.Buckets[0].Considered).Should(Equal(12 * time.Hour))
+				Ω(report.Buckets[0].Availability()).Should(HaveValue(BeNumerically("~", 50)))
+				Ω(report.Buckets[1].Availability()).Should(BeNil())
+				Ω(report.Buckets[1].Severities()).Should(BeNil())
+			})
+		})
+
+		Context("with months", func() {
+			It("should split at the start of each month", func() {
+				// Arrange
+				period.End = start.AddDate(0, 3, 0)
+				period.Granularity = availability.Month
+
+				// Act
+				report, err := availability.Compute(nil, severities, period)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(report.Buckets).Should(HaveLen(3))
+				Ω(report.Buckets[1].Start).Should(Equal(time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)))
+				Ω(report.Buckets[1].End).Should(Equal(time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)))
+			})
+		})
+
+		DescribeTable("with invalid period",
+			func(modify func(period *availability.Period), expectedErr error) {
+				// Arrange
+				modify(&period)
+
+				// Act
+				_, err := availability.Compute(nil, severities, period)
+
+				// Assert
+				Ω(err).Should(MatchError(expectedErr))
+			},
+			Entry("with end before start",
+				func(period *availability.Period) { period.End = start.Add(-time.Hour) }, availability.ErrInvalidPeriod),
+			Entry("with unknown granularity",
+				func(period *availability.Period) { period.Granularity = "year" }, availability.ErrInvalidGranularity),
+			Entry("with too many buckets",
+				func(period *availability.Period) { period.End = start.AddDate(5, 0, 0) }, availability.ErrTooManyBuckets),
+		)
+	})
+})
@@ -0,0 +1,140 @@
+// Package calendar renders iCalendar documents as defined by RFC 5545.
+package calendar
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// ContentType is the media type of iCalendar documents.
+	ContentType = "text/calendar; charset=utf-8"
+
+	// lineLimit is the maximum length of a content line in octets, excluding the line break.
+	lineLimit = 75
+	// dateTimeFormat is the format of UTC date-time values.
+	dateTimeFormat = "20060102T150405Z"
+)
+
+// Calendar is a published iCalendar object.
+type Calendar struct {
+	// ProductID identifies the product, that created the calendar.
+	ProductID string
+	// Name is the display name of the calendar.
+	Name string
+	// Events are the components of the calendar.
+	Events []Event
+}
+
+// Event is a VEVENT component.
+type Event struct {
+	// UID identifies the event globally and permanently.
+	UID string
+	// Sequence is the revision of the event, it increases with every significant change.
+	Sequence int
+	// Start is the inclusive beginning of the event.
+	Start time.Time
+	// End is the exclusive end of the event.
+	End time.Time
+	// LastModified is the time of the latest revision.
+	LastModified time.Time
+	// Summary is the title of the event.
+	Summary string
+	// Description is the plain text description of the event.
+	Description string
+	// URL links to the resource described by the event.
+	URL string
+	// Categories tag the event.
+	Categories []string
+}
+
+// Render renders the calendar with CRLF line breaks and folded lines.
+func (c *Calendar) Render() []byte {
+	var builder strings.Builder
+
+	writeLine(&builder, "BEGIN:VCALENDAR")
+	writeLine(&builder, "VERSION:2.0")
+	writeLine(&builder, "PRODID:"+escapeText(c.ProductID))
+	writeLine(&builder, "CALSCALE:GREGORIAN")
+	writeLine(&builder, "METHOD:PUBLISH")
+
+	if c.Name != "" {
+		writeLine(&builder, "NAME:"+escapeText(c.Name))
+		writeLine(&builder, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		writeLine(&builder, "BEGIN:VEVENT")
+		writeLine(&builder, "UID:"+escapeText(event.UID))
+		writeLine(&builder, "SEQUENCE:"+strconv.Itoa(event.Sequence))
+		// The calendar is no scheduling message, the stamp is the time of the latest revision.
+		writeLine(&builder, "DTSTAMP:"+formatDateTime(event.LastModified))
+		writeLine(&builder, "LAST-MODIFIED:"+formatDateTime(event.LastModified))
+		writeLine(&builder, "DTSTART:"+formatDateTime(event.Start))
+		writeLine(&builder, "DTEND:"+formatDateTime(event.End))
+		writeLine(&builder, "SUMMARY:"+escapeText(event.Summary))
+
+		if event.Description != "" {
+			writeLine(&builder, "DESCRIPTION:"+escapeText(event.Description))
+		}
+
+		if event.URL != "" {
+			writeLine(&builder, "URL:"+event.URL)
+		}
+
+		if len(event.Categories) > 0 {
+			categories := make([]string, len(event.Categories))
+			for categoryIndex, category := range event.Categories {
+				categories[categoryIndex] = escapeText(category)
+			}
+
+			writeLine(&builder, "CATEGORIES:"+strings.Join(categories, ","))
+		}
+
+		writeLine(&builder, "TRANSP:TRANSPARENT")
+		writeLine(&builder, "END:VEVENT")
+	}
+
+	writeLine(&builder, "END:VCALENDAR")
+
+	return []byte(builder.String())
+}
+
+func formatDateTime(value time.Time) string {
+	return value.UTC().Format(dateTimeFormat)
+}
+
+// escapeText escapes a TEXT value.
+func escapeText(value string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(value)
+}
+
+// writeLine writes a content line, folded after 75 octets without splitting UTF-8 characters.
+func writeLine(builder *strings.Builder, line string) {
+	limit := lineLimit
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		builder.WriteString(line[:cut])
+		builder.WriteString("\r\n ")
+
+		line = line[cut:]
+		// the leading space of continuation lines counts towards the limit
+		limit = lineLimit - 1
+	}
+
+	builder.WriteString(line)
+	builder.WriteString("\r\n")
+}
@@ -0,0 +1,63 @@
+package calendar_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/calendar"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Calendar", func() {
+	Describe("Render", func() {
+		var (
+			start = time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+			testCalendar = calendar.Calendar{
+				ProductID: "-//Status Page//Maintenances//EN",
+				Name:      "Maintenances",
+				Events: []calendar.Event{
+					{
+						UID:          "4bcd4e61-b1d5-4c8b-9ec4-54e0d7d0e0d1",
+						Sequence:     2,
+						Start:        start,
+						End:          start.Add(2 * time.Hour),
+						LastModified: start.Add(time.Hour),
+						Summary:      "Storage upgrade; part 1, west",
+						Description:  "Affected components: " + strings.Repeat("Storage ", 10) + "\nExpect short outages.",
+						URL:          "https://status.example.com/incidents/4bcd4e61-b1d5-4c8b-9ec4-54e0d7d0e0d1",
+						Categories:   []string{"maintenance"},
+					},
+				},
+			}
+		)
+
+		It("should render a valid calendar", func() {
+			// Act
+			document := string(testCalendar.Render())
+
+			// Assert
+			Ω(document).Should(HavePrefix("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+			Ω(document).Should(HaveSuffix("END:VEVENT\r\nEND:VCALENDAR\r\n"))
+			Ω(document).Should(ContainSubstring("\r\nSEQUENCE:2\r\n"))
+			Ω(document).Should(ContainSubstring("\r\nDTSTART:20240301T100000Z\r\nDTEND:20240301T120000Z\r\n"))
+			Ω(document).Should(ContainSubstring("\r\nDTSTAMP:20240301T110000Z\r\n"))
+			Ω(document).Should(ContainSubstring(`SUMMARY:Storage upgrade\; part 1\, west`))
+		})
+
+		It("should fold long lines", func() {
+			// Act
+			document := string(testCalendar.Render())
+
+			// Assert
+			for _, line := range strings.Split(strings.TrimSuffix(document, "\r\n"), "\r\n") {
+				Ω(len(line)).Should(BeNumerically("<=", 75))
+			}
+
+			Ω(strings.ReplaceAll(document, "\r\n ", "")).Should(ContainSubstring(
+				"DESCRIPTION:Affected components: " + strings.Repeat("Storage ", 10) + `\nExpect short outages.`,
+			))
+		})
+	})
+})
@@ -0,0 +1,27 @@
+package db
+
+import "time"
+
+// AlertIncident links an alert of Alertmanager by its fingerprint to the [Incident] opened for it.
+// An alert firing again after it was resolved opens a new incident, so only links without resolution are active.
+// The partial unique index ensures a single active link per alert, even with concurrent notifications.
+type AlertIncident struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	Fingerprint string    `gorm:"not null;uniqueIndex:idx_alert_incidents_active,where:resolved_at IS NULL"`
+	IncidentID  ID        `gorm:"type:uuid;not null;index"`
+	Incident    *Incident `gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time `gorm:"not null"`
+	ResolvedAt  *time.Time
+}
+
+// NewAlertIncident creates an active [AlertIncident].
+func NewAlertIncident(fingerprint string, incidentID ID, now time.Time) *AlertIncident {
+	return &AlertIncident{
+		ID:          0,
+		Fingerprint: fingerprint,
+		IncidentID:  incidentID,
+		Incident:    nil,
+		CreatedAt:   now,
+		ResolvedAt:  nil,
+	}
+}
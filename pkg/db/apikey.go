@@ -0,0 +1,135 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+)
+
+// APIKeyScopes are the names of the scopes granted to an [APIKey].
+type APIKeyScopes []string
+
+// Scan implements the [database/sql.Scanner] interface to correctly read data.
+func (s *APIKeyScopes) Scan(value interface{}) error {
+	var data []byte
+
+	switch typedValue := value.(type) {
+	case []byte:
+		data = typedValue
+	case string:
+		data = []byte(typedValue)
+	default:
+		return fmt.Errorf("%w: %v", ErrInvalidScopeData, value)
+	}
+
+	return json.Unmarshal(data, s) //nolint:wrapcheck
+}
+
+// Value implements the [database/sql/driver.Valuer] interface to correctly write data.
+func (s APIKeyScopes) Value() (driver.Value, error) {
+	data, err := json.Marshal([]string(s))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding scopes: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// APIKey is a static credential for automation, stored by the hash of its secret.
+type APIKey struct {
+	Name       *string       `gorm:"not null"             json:"name"`
+	Prefix     *string       `gorm:"not null"             json:"prefix"`
+	SecretHash *string       `gorm:"not null;uniqueIndex" json:"-"`
+	Scopes     *APIKeyScopes `gorm:"type:jsonb;not null"  json:"scopes"`
+	CreatedAt  *time.Time    `json:"createdAt"`
+	ExpiresAt  *time.Time    `json:"expiresAt"`
+	LastUsedAt *time.Time    `json:"lastUsedAt"`
+	RevokedAt  *time.Time    `json:"revokedAt"`
+	Model      `gorm:"embedded"`
+}
+
+// ToAPIResponse converts to API response.
+func (ak *APIKey) ToAPIResponse() api.APIKeyResponseData {
+	response := api.APIKeyResponseData{ //nolint:exhaustruct
+		ID:         ak.ID,
+		Name:       *ak.Name,
+		Prefix:     *ak.Prefix,
+		Scopes:     []string{},
+		ExpiresAt:  ak.ExpiresAt,
+		LastUsedAt: ak.LastUsedAt,
+		RevokedAt:  ak.RevokedAt,
+	}
+
+	if ak.Scopes != nil {
+		response.Scopes = *ak.Scopes
+	}
+
+	if ak.CreatedAt != nil {
+		response.CreatedAt = *ak.CreatedAt
+	}
+
+	return response
+}
+
+// ToStoredAPIKey converts to the representation needed for authentication.
+func (ak *APIKey) ToStoredAPIKey() (*auth.StoredAPIKey, error) {
+	var scopes []auth.Scope
+
+	if ak.Scopes != nil {
+		var err error
+
+		scopes, err = auth.ParseScopes(*ak.Scopes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing scopes of API key: %w", err)
+		}
+	}
+
+	return &auth.StoredAPIKey{
+		ID:         ak.ID.String(),
+		Name:       *ak.Name,
+		Scopes:     scopes,
+		ExpiresAt:  ak.ExpiresAt,
+		LastUsedAt: ak.LastUsedAt,
+		RevokedAt:  ak.RevokedAt,
+	}, nil
+}
+
+// APIKeyFromAPI creates an [APIKey] from an API request and the generated key.
+func APIKeyFromAPI(apiKeyRequest *api.APIKeyRequest, generatedKey *auth.GeneratedAPIKey) (*APIKey, error) {
+	if apiKeyRequest == nil || apiKeyRequest.Name == nil || *apiKeyRequest.Name == "" {
+		return nil, ErrEmptyValue
+	}
+
+	if len(apiKeyRequest.Scopes) == 0 {
+		return nil, NewFieldError("scopes", ErrEmptyValue)
+	}
+
+	scopes, err := auth.ParseScopes(apiKeyRequest.Scopes)
+	if err != nil {
+		return nil, NewFieldError("scopes", err)
+	}
+
+	now := time.Now()
+
+	if apiKeyRequest.ExpiresAt != nil && !apiKeyRequest.ExpiresAt.After(now) {
+		return nil, NewFieldError("expiresAt", ErrExpiresInPast)
+	}
+
+	scopeNames := make(APIKeyScopes, len(scopes))
+	for scopeIndex, scope := range scopes {
+		scopeNames[scopeIndex] = string(scope)
+	}
+
+	return &APIKey{ //nolint:exhaustruct
+		Name:       apiKeyRequest.Name,
+		Prefix:     &generatedKey.Prefix,
+		SecretHash: &generatedKey.Hash,
+		Scopes:     &scopeNames,
+		CreatedAt:  &now,
+		ExpiresAt:  apiKeyRequest.ExpiresAt,
+	}, nil
+}
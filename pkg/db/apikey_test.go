@@ -0,0 +1,183 @@
+package db_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("APIKey", func() {
+	const apiKeyID = "4f9c3e2b-1d6a-4b8e-9f0a-2c5d7e8f9a1b"
+
+	var generatedKey = &auth.GeneratedAPIKey{
+		Key:    "spk_secret",
+		Prefix: "spk_sec",
+		Hash:   auth.HashAPIKey("spk_secret"),
+	}
+
+	Describe("APIKeyScopes", func() {
+		Context("with valid data", func() {
+			It("should round trip as json", func() {
+				// Arrange
+				scopes := db.APIKeyScopes{"read", "editor"}
+				result := db.APIKeyScopes{}
+
+				// Act
+				value, err := scopes.Value()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = result.Scan(value)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(value).Should(Equal(`["read","editor"]`))
+				Ω(result).Should(Equal(scopes))
+			})
+		})
+
+		Context("with invalid data", func() {
+			It("should return ErrInvalidScopeData", func() {
+				// Arrange
+				scopes := db.APIKeyScopes{}
+
+				// Act
+				err := scopes.Scan(842376)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(errors.Unwrap(err)).Should(Equal(db.ErrInvalidScopeData))
+			})
+		})
+	})
+
+	Describe("APIKeyFromAPI", func() {
+		Context("with valid data", func() {
+			It("should return the API key storing only the hash", func() {
+				// Arrange
+				expiresAt := time.Now().Add(time.Hour)
+				request := &api.APIKeyRequest{
+					Name:      test.Ptr("ci"),
+					Scopes:    []string{"editor"},
+					ExpiresAt: &expiresAt,
+				}
+
+				// Act
+				apiKey, err := db.APIKeyFromAPI(request, generatedKey)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(*apiKey.Name).Should(Equal("ci"))
+				Ω(*apiKey.Prefix).Should(Equal(generatedKey.Prefix))
+				Ω(*apiKey.SecretHash).Should(Equal(generatedKey.Hash))
+				Ω(*apiKey.Scopes).Should(Equal(db.APIKeyScopes{"editor"}))
+				Ω(apiKey.ExpiresAt).Should(Equal(&expiresAt))
+				Ω(apiKey.CreatedAt).ShouldNot(BeNil())
+			})
+		})
+
+		Context("without name", func() {
+			It("should return ErrEmptyValue", func() {
+				// Act
+				apiKey, err := db.APIKeyFromAPI(&api.APIKeyRequest{Scopes: []string{"read"}}, generatedKey)
+
+				// Assert
+				Ω(err).Should(MatchError(db.ErrEmptyValue))
+				Ω(apiKey).Should(BeNil())
+			})
+		})
+
+		Context("without scopes", func() {
+			It("should return ErrEmptyValue", func() {
+				// Act
+				apiKey, err := db.APIKeyFromAPI(&api.APIKeyRequest{Name: test.Ptr("ci")}, generatedKey)
+
+				// Assert
+				Ω(err).Should(MatchError(db.ErrEmptyValue))
+				Ω(apiKey).Should(BeNil())
+			})
+		})
+
+		Context("with unknown scope", func() {
+			It("should return ErrUnknownScope", func() {
+				// Act
+				apiKey, err := db.APIKeyFromAPI(
+					&api.APIKeyRequest{Name: test.Ptr("ci"), Scopes: []string{"root"}},
+					generatedKey,
+				)
+
+				// Assert
+				Ω(err).Should(MatchError(auth.ErrUnknownScope))
+				Ω(apiKey).Should(BeNil())
+			})
+		})
+
+		Context("with expiry in the past", func() {
+			It("should return ErrExpiresInPast", func() {
+				// Act
+				apiKey, err := db.APIKeyFromAPI(&api.APIKeyRequest{
+					Name:      test.Ptr("ci"),
+					Scopes:    []string{"read"},
+					ExpiresAt: test.Ptr(time.Now().Add(-time.Hour)),
+				}, generatedKey)
+
+				// Assert
+				Ω(err).Should(MatchError(db.ErrExpiresInPast))
+				Ω(apiKey).Should(BeNil())
+			})
+		})
+	})
+
+	Describe("ToStoredAPIKey", func() {
+		It("should parse the scopes", func() {
+			// Arrange
+			apiKey := db.APIKey{
+				Name:   test.Ptr("ci"),
+				Scopes: &db.APIKeyScopes{"admin"},
+				Model:  db.Model{ID: uuid.MustParse(apiKeyID)},
+			}
+
+			// Act
+			storedKey, err := apiKey.ToStoredAPIKey()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(storedKey.ID).Should(Equal(apiKeyID))
+			Ω(storedKey.Name).Should(Equal("ci"))
+			Ω(storedKey.Scopes).Should(Equal([]auth.Scope{auth.ScopeAdmin}))
+		})
+	})
+
+	Describe("ToAPIResponse", func() {
+		It("should not contain the secret hash", func() {
+			// Arrange
+			createdAt := time.Now()
+			apiKey := db.APIKey{
+				Name:       test.Ptr("ci"),
+				Prefix:     &generatedKey.Prefix,
+				SecretHash: &generatedKey.Hash,
+				Scopes:     &db.APIKeyScopes{"read"},
+				CreatedAt:  &createdAt,
+				Model:      db.Model{ID: uuid.MustParse(apiKeyID)},
+			}
+
+			// Act
+			response := apiKey.ToAPIResponse()
+
+			// Assert
+			Ω(response).Should(Equal(api.APIKeyResponseData{
+				ID:        uuid.MustParse(apiKeyID),
+				Name:      "ci",
+				Prefix:    generatedKey.Prefix,
+				Scopes:    []string{"read"},
+				CreatedAt: createdAt,
+			}))
+		})
+	})
+})
@@ -0,0 +1,237 @@
+package db
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+)
+
+// AuditOperation is the kind of write operation recorded by an [AuditEntry].
+type AuditOperation string
+
+const (
+	// AuditOperationCreate records the creation of a resource.
+	AuditOperationCreate AuditOperation = "create"
+	// AuditOperationUpdate records the update of a resource.
+	AuditOperationUpdate AuditOperation = "update"
+	// AuditOperationDelete records the deletion of a resource.
+	AuditOperationDelete AuditOperation = "delete"
+	// AuditOperationRestore records the restoration of a deleted resource.
+	AuditOperationRestore AuditOperation = "restore"
+)
+
+// IsValid reports, if the operation is known.
+func (o AuditOperation) IsValid() bool {
+	switch o {
+	case AuditOperationCreate, AuditOperationUpdate, AuditOperationDelete, AuditOperationRestore:
+		return true
+	default:
+		return false
+	}
+}
+
+// Types of resources recorded by an [AuditEntry].
+const (
+	AuditTargetComponent            = "component"
+	AuditTargetComponentDependency  = "component_dependency"
+	AuditTargetComponentGroup       = "component_group"
+	AuditTargetComponentGroupMember = "component_group_member"
+	AuditTargetIncident             = "incident"
+	AuditTargetIncidentUpdate       = "incident_update"
+	AuditTargetImpactType           = "impact_type"
+	AuditTargetSeverity             = "severity"
+	AuditTargetPhaseList            = "phase_list"
+	AuditTargetAPIKey               = "api_key"
+	AuditTargetWebhook              = "webhook"
+)
+
+// JSONDocument is an arbitrary JSON document.
+type JSONDocument json.RawMessage
+
+// Scan implements the [database/sql.Scanner] interface to correctly read data.
+func (d *JSONDocument) Scan(value interface{}) error {
+	switch typedValue := value.(type) {
+	case nil:
+		*d = nil
+	case []byte:
+		*d = slices.Clone(typedValue)
+	case string:
+		*d = JSONDocument(typedValue)
+	default:
+		return fmt.Errorf("%w: %v", ErrInvalidJSONData, value)
+	}
+
+	return nil
+}
+
+// Value implements the [database/sql/driver.Valuer] interface to correctly write data.
+func (d JSONDocument) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	return string(d), nil
+}
+
+// AuditEntry records a single write operation.
+type AuditEntry struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement"`
+	CreatedAt  time.Time      `gorm:"not null;index"`
+	Actor      string         `gorm:"not null;index"`
+	Operation  AuditOperation `gorm:"not null"`
+	TargetType string         `gorm:"not null;index:idx_audit_entries_target"`
+	TargetID   string         `gorm:"not null;index:idx_audit_entries_target"`
+	Before     JSONDocument   `gorm:"type:jsonb"`
+	After      JSONDocument   `gorm:"type:jsonb"`
+}
+
+// NewAuditEntry creates an [AuditEntry] with JSON snapshots of the resource before and after the operation.
+// Snapshots are omitted, if nil, e.g. the state before a creation.
+func NewAuditEntry(
+	actor string,
+	operation AuditOperation,
+	targetType string,
+	targetID string,
+	before interface{},
+	after interface{},
+) (*AuditEntry, error) {
+	beforeDocument, err := newJSONDocument(before)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding state before operation: %w", err)
+	}
+
+	afterDocument, err := newJSONDocument(after)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding state after operation: %w", err)
+	}
+
+	return &AuditEntry{
+		ID:         0,
+		CreatedAt:  time.Now(),
+		Actor:      actor,
+		Operation:  operation,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     beforeDocument,
+		After:      afterDocument,
+	}, nil
+}
+
+func newJSONDocument(value interface{}) (JSONDocument, error) {
+	if value == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return data, nil
+}
+
+// ToAPIResponse converts to API response.
+func (ae *AuditEntry) ToAPIResponse() api.AuditEntryResponseData {
+	return api.AuditEntryResponseData{
+		ID:         ae.ID,
+		CreatedAt:  ae.CreatedAt,
+		Actor:      ae.Actor,
+		Operation:  string(ae.Operation),
+		TargetType: ae.TargetType,
+		TargetID:   ae.TargetID,
+		Before:     json.RawMessage(ae.Before),
+		After:      json.RawMessage(ae.After),
+		Changes:    ae.Changes(),
+	}
+}
+
+// Changes compares the top level fields of the snapshots and lists the changed ones, sorted by field name.
+// Snapshots, which are no JSON objects, are compared as a whole with an empty field name.
+func (ae *AuditEntry) Changes() []api.AuditChange {
+	beforeFields, beforeIsObject := jsonFields(ae.Before)
+	afterFields, afterIsObject := jsonFields(ae.After)
+
+	if !beforeIsObject || !afterIsObject {
+		if jsonEqual(ae.Before, ae.After) {
+			return []api.AuditChange{}
+		}
+
+		return []api.AuditChange{{
+			Field:  "",
+			Before: json.RawMessage(ae.Before),
+			After:  json.RawMessage(ae.After),
+		}}
+	}
+
+	fields := make([]string, 0, len(beforeFields)+len(afterFields))
+	for field := range beforeFields {
+		fields = append(fields, field)
+	}
+
+	for field := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+
+	slices.Sort(fields)
+
+	changes := []api.AuditChange{}
+
+	for _, field := range fields {
+		before, after := beforeFields[field], afterFields[field]
+		if jsonEqual(before, after) {
+			continue
+		}
+
+		changes = append(changes, api.AuditChange{
+			Field:  field,
+			Before: before,
+			After:  after,
+		})
+	}
+
+	return changes
+}
+
+// jsonFields splits a JSON object into its fields.
+// Missing documents are treated as objects without fields.
+func jsonFields(document JSONDocument) (map[string]json.RawMessage, bool) {
+	fields := map[string]json.RawMessage{}
+
+	if document == nil {
+		return fields, true
+	}
+
+	err := json.Unmarshal(document, &fields)
+	if err != nil {
+		return nil, false
+	}
+
+	return fields, true
+}
+
+// jsonEqual compares two JSON values independent of formatting. Null and missing values are equal.
+func jsonEqual(first []byte, second []byte) bool {
+	return bytes.Equal(compactJSON(first), compactJSON(second))
+}
+
+func compactJSON(value []byte) []byte {
+	if len(value) == 0 {
+		return []byte("null")
+	}
+
+	var buffer bytes.Buffer
+
+	err := json.Compact(&buffer, value)
+	if err != nil {
+		return value
+	}
+
+	return buffer.Bytes()
+}
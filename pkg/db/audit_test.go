@@ -0,0 +1,164 @@
+package db_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audit", func() {
+	Describe("JSONDocument", func() {
+		Context("with valid data", func() {
+			It("should round trip", func() {
+				// Arrange
+				document := db.JSONDocument(`{"displayName":"Storage"}`)
+				result := db.JSONDocument{}
+
+				// Act
+				value, err := document.Value()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = result.Scan([]byte(value.(string)))
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(value).Should(Equal(`{"displayName":"Storage"}`))
+				Ω(result).Should(Equal(document))
+			})
+		})
+
+		Context("without data", func() {
+			It("should be stored as null", func() {
+				// Arrange
+				var document db.JSONDocument
+
+				// Act
+				value, err := document.Value()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = document.Scan(nil)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(value).Should(BeNil())
+				Ω(document).Should(BeNil())
+			})
+		})
+
+		Context("with invalid data", func() {
+			It("should return ErrInvalidJSONData", func() {
+				// Arrange
+				document := db.JSONDocument{}
+
+				// Act
+				err := document.Scan(842376)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(errors.Unwrap(err)).Should(Equal(db.ErrInvalidJSONData))
+			})
+		})
+	})
+
+	Describe("NewAuditEntry", func() {
+		Context("with creation", func() {
+			It("should only store the state after the operation", func() {
+				// Arrange
+				component := db.Component{
+					DisplayName: test.Ptr("Storage"),
+				}
+
+				// Act
+				auditEntry, err := db.NewAuditEntry(
+					"alice", db.AuditOperationCreate, db.AuditTargetComponent, "1", nil, component,
+				)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(auditEntry.Actor).Should(Equal("alice"))
+				Ω(auditEntry.Before).Should(BeNil())
+				Ω(string(auditEntry.After)).Should(ContainSubstring(`"displayName":"Storage"`))
+			})
+		})
+
+		Context("with value not encodable as JSON", func() {
+			It("should return an error", func() {
+				// Act
+				_, err := db.NewAuditEntry(
+					"alice", db.AuditOperationUpdate, db.AuditTargetComponent, "1", nil, make(chan int),
+				)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Changes", func() {
+		Context("with update", func() {
+			It("should list changed fields only", func() {
+				// Arrange
+				auditEntry := db.AuditEntry{ //nolint:exhaustruct
+					Before: db.JSONDocument(`{"displayName":"Storage","labels":{"a":"b"},"id":"1"}`),
+					After:  db.JSONDocument(`{"id":"1","labels":{ "a": "b" },"displayName":"Network","value":3}`),
+				}
+
+				// Act
+				changes := auditEntry.Changes()
+
+				// Assert
+				Ω(changes).Should(Equal([]api.AuditChange{
+					{
+						Field:  "displayName",
+						Before: json.RawMessage(`"Storage"`),
+						After:  json.RawMessage(`"Network"`),
+					},
+					{
+						Field:  "value",
+						Before: nil,
+						After:  json.RawMessage(`3`),
+					},
+				}))
+			})
+		})
+
+		Context("with deletion", func() {
+			It("should list all fields as removed", func() {
+				// Arrange
+				auditEntry := db.AuditEntry{ //nolint:exhaustruct
+					Before: db.JSONDocument(`{"displayName":"Storage"}`),
+				}
+
+				// Act
+				changes := auditEntry.Changes()
+
+				// Assert
+				Ω(changes).Should(HaveLen(1))
+				Ω(changes[0].Field).Should(Equal("displayName"))
+				Ω(changes[0].After).Should(BeNil())
+			})
+		})
+
+		Context("with snapshots, which are no objects", func() {
+			It("should compare them as a whole", func() {
+				// Arrange
+				auditEntry := db.AuditEntry{ //nolint:exhaustruct
+					Before: db.JSONDocument(`["Phase 1"]`),
+					After:  db.JSONDocument(`["Phase 1","Phase 2"]`),
+				}
+
+				// Act
+				changes := auditEntry.Changes()
+
+				// Assert
+				Ω(changes).Should(HaveLen(1))
+				Ω(changes[0].Field).Should(BeEmpty())
+			})
+		})
+	})
+})
@@ -1,12 +1,19 @@
 package db
 
-import apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+import (
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"gorm.io/gorm"
+)
 
 // Component represents a single component that could be affected by many [Incident].
 type Component struct {
-	DisplayName        *apiServerDefinition.DisplayName `yaml:"displayname"`
-	Labels             *Labels                          `gorm:"type:jsonb"             yaml:"labels"`
-	ActivelyAffectedBy *[]Impact                        `gorm:"foreignKey:ComponentID"`
+	DisplayName        *apiServerDefinition.DisplayName `json:"displayName"            yaml:"displayname"`
+	Labels             *Labels                          `json:"labels"                 yaml:"labels"`
+	ActivelyAffectedBy *[]Impact                        `gorm:"foreignKey:ComponentID" json:"-"`
+	Version            *int                             `gorm:"not null;default:1"      json:"-"                yaml:"-"`
+	DeletedAt          gorm.DeletedAt                   `gorm:"index"                   json:"-"                yaml:"-"`
+	DeletedBy          *string                          `json:"-"                       yaml:"-"`
 	Model              `gorm:"embedded"`
 }
 
@@ -20,6 +27,14 @@ func (c *Component) ToAPIResponse() apiServerDefinition.ComponentResponseData {
 	}
 }
 
+// ToDeletableAPIResponse converts to API response, marked if the component is deleted.
+func (c *Component) ToDeletableAPIResponse() api.DeletableComponentResponseData {
+	return api.DeletableComponentResponseData{
+		ComponentResponseData: c.ToAPIResponse(),
+		Deletion:              NewDeletion(c.DeletedAt, c.DeletedBy),
+	}
+}
+
 // GetImpactIncidentList converts the impact list.
 func (c *Component) GetImpactIncidentList() *apiServerDefinition.ImpactIncidentList {
 	impacts := make(apiServerDefinition.ImpactIncidentList, len(*c.ActivelyAffectedBy))
@@ -10,7 +10,7 @@ type ID = uuid.UUID
 
 // Model sets the basic Data for all true database resources.
 type Model struct {
-	ID ID `gorm:"primaryKey;type:uuid;"`
+	ID ID `gorm:"primaryKey;type:uuid;" json:"id"`
 }
 
 // BeforeCreate is a gorm hook to fill the ID field with a new UUID,
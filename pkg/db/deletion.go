@@ -0,0 +1,15 @@
+package db
+
+import (
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"gorm.io/gorm"
+)
+
+// NewDeletion creates the [api.Deletion] mark of a resource, empty if it is not deleted.
+func NewDeletion(deletedAt gorm.DeletedAt, deletedBy *string) api.Deletion {
+	if !deletedAt.Valid {
+		return api.Deletion{DeletedAt: nil, DeletedBy: nil}
+	}
+
+	return api.Deletion{DeletedAt: &deletedAt.Time, DeletedBy: deletedBy}
+}
@@ -0,0 +1,11 @@
+package db
+
+// ComponentDependency is the dependency of a [Component] on another one.
+// Impacts on the dependency propagate to the dependent component.
+type ComponentDependency struct {
+	Component *Component `gorm:"foreignKey:ComponentID;constraint:OnDelete:CASCADE" json:"-"`
+	DependsOn *Component `gorm:"foreignKey:DependsOnID;constraint:OnDelete:CASCADE" json:"-"`
+
+	ComponentID *ID `gorm:"primaryKey" json:"componentId"`
+	DependsOnID *ID `gorm:"primaryKey" json:"dependsOnId"`
+}
@@ -1,6 +1,10 @@
 package db
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+)
 
 var (
 	// ErrInvalidLabelData Data is of invalid type.
@@ -13,4 +17,77 @@ var (
 	ErrMaintenanceNeedsEnd = errors.New("maintenance event needs a end")
 	// ErrEndsBeforeStart An incident ends before it has started.
 	ErrEndsBeforeStart = errors.New("incidents end before it starts")
+	// ErrInvalidScopeData Data is of invalid type.
+	ErrInvalidScopeData = errors.New("scope data is invalid")
+	// ErrExpiresInPast An API key would already be expired.
+	ErrExpiresInPast = errors.New("expiry is in the past")
+	// ErrInvalidJSONData Data is of invalid type.
+	ErrInvalidJSONData = errors.New("json data is invalid")
+	// ErrInvalidEventTypeData Data is of invalid type.
+	ErrInvalidEventTypeData = errors.New("event type data is invalid")
+	// ErrUnknownEventType An event type is not known.
+	ErrUnknownEventType = errors.New("unknown event type")
+	// ErrInvalidLabelSelector A label selector is malformed.
+	ErrInvalidLabelSelector = errors.New("label selector is invalid")
+	// ErrInvalidComponentData Data is of invalid type.
+	ErrInvalidComponentData = errors.New("component data is invalid")
+	// ErrInvalidEmail An email address is invalid or carries a display name.
+	ErrInvalidEmail = errors.New("email address is invalid")
+	// ErrInvalidTargetURL A webhook target is no absolute HTTP(S) URL.
+	ErrInvalidTargetURL = errors.New("target url is invalid")
 )
+
+// FieldError is an error caused by a single field of an API request.
+type FieldError struct {
+	// Field is the path of the field in the request body, e.g. `affects[0].severity`.
+	Field string
+	// Err is the reason, why the field is invalid.
+	Err error
+}
+
+// NewFieldError creates a [FieldError] for the field at path.
+func NewFieldError(path string, err error) *FieldError {
+	return &FieldError{
+		Field: path,
+		Err:   err,
+	}
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the reason of the error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// problemTypes assigns the errors caused by API requests their problem type.
+// The names are part of the API and must not change.
+var problemTypes = []struct { //nolint:gochecknoglobals
+	err  error
+	name string
+}{
+	{ErrEmptyValue, "empty-value"},
+	{ErrSeverityValueOutOfRange, "severity-value-out-of-range"},
+	{ErrMaintenanceNeedsEnd, "maintenance-needs-end"},
+	{ErrEndsBeforeStart, "ends-before-start"},
+	{ErrExpiresInPast, "expires-in-past"},
+	{ErrUnknownEventType, "unknown-event-type"},
+	{ErrInvalidLabelSelector, "invalid-label-selector"},
+	{ErrInvalidEmail, "invalid-email"},
+	{ErrInvalidTargetURL, "invalid-target-url"},
+}
+
+// ProblemType returns the problem type URI of an error caused by an API request.
+// It reports false, if the error has no problem type.
+func ProblemType(err error) (string, bool) {
+	for _, problemType := range problemTypes {
+		if errors.Is(err, problemType.err) {
+			return api.ProblemTypeBase + problemType.name, true
+		}
+	}
+
+	return "", false
+}
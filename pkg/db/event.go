@@ -0,0 +1,90 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+)
+
+// EventType is the kind of change notified by an [Event].
+type EventType string
+
+const (
+	// EventIncidentCreated notifies about a new incident.
+	EventIncidentCreated EventType = "incident.created"
+	// EventIncidentUpdated notifies about a changed incident.
+	EventIncidentUpdated EventType = "incident.updated"
+	// EventIncidentResolved notifies about an incident, that got an end.
+	EventIncidentResolved EventType = "incident.resolved"
+	// EventIncidentDeleted notifies about a deleted incident.
+	EventIncidentDeleted EventType = "incident.deleted"
+	// EventIncidentRestored notifies about a deleted incident, that got restored.
+	EventIncidentRestored EventType = "incident.restored"
+	// EventIncidentUpdateCreated notifies about a new update of an incident.
+	EventIncidentUpdateCreated EventType = "incident_update.created"
+	// EventIncidentUpdateUpdated notifies about a changed update of an incident.
+	EventIncidentUpdateUpdated EventType = "incident_update.updated"
+	// EventIncidentUpdateDeleted notifies about a deleted update of an incident.
+	EventIncidentUpdateDeleted EventType = "incident_update.deleted"
+	// EventComponentChanged notifies about a created, updated or deleted component.
+	EventComponentChanged EventType = "component.changed"
+)
+
+// IsValid reports, if the event type is known.
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventIncidentCreated,
+		EventIncidentUpdated,
+		EventIncidentResolved,
+		EventIncidentDeleted,
+		EventIncidentRestored,
+		EventIncidentUpdateCreated,
+		EventIncidentUpdateUpdated,
+		EventIncidentUpdateDeleted,
+		EventComponentChanged:
+		return true
+	default:
+		return false
+	}
+}
+
+// Event notifies about a change.
+// Events are written in the transaction of the change, so they are committed together with it (outbox pattern).
+// The ID orders all events.
+type Event struct {
+	ID           uint64       `gorm:"primaryKey;autoIncrement"`
+	CreatedAt    time.Time    `gorm:"not null"`
+	Type         EventType    `gorm:"not null"`
+	Payload      JSONDocument `gorm:"type:jsonb"`
+	DispatchedAt *time.Time   `gorm:"index"`
+	NotifiedAt   *time.Time   `gorm:"index"`
+}
+
+// NewEvent creates an [Event] with the JSON encoded payload.
+func NewEvent(eventType EventType, payload interface{}) (*Event, error) {
+	payloadDocument, err := newJSONDocument(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding event payload: %w", err)
+	}
+
+	return &Event{
+		ID:           0,
+		CreatedAt:    time.Now(),
+		Type:         eventType,
+		Payload:      payloadDocument,
+		DispatchedAt: nil,
+		NotifiedAt:   nil,
+	}, nil
+}
+
+// ToAPIResponse converts to API response.
+func (e *Event) ToAPIResponse() api.EventResponseData {
+	return api.EventResponseData{
+		ID:        e.ID,
+		Type:      string(e.Type),
+		CreatedAt: e.CreatedAt,
+		Data:      json.RawMessage(e.Payload),
+	}
+}
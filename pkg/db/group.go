@@ -0,0 +1,73 @@
+package db
+
+import (
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+)
+
+// ComponentGroup groups [Component]s and nested groups, e.g. to render collapsible sections of a status page.
+// Groups are sorted by their order among the groups of the same parent.
+type ComponentGroup struct {
+	DisplayName *apiServerDefinition.DisplayName `json:"displayName"                                     yaml:"displayname"`
+	Description *apiServerDefinition.Description `json:"description"                                     yaml:"description"`
+	ParentID    *ID                              `json:"parentId"`
+	Order       *int                             `gorm:"not null"                                        json:"order"`
+	Members     *[]ComponentGroupMember          `gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE" json:"members"`
+	Model       `gorm:"embedded"`
+}
+
+// ComponentGroupMember is the membership of a [Component] in a [ComponentGroup].
+type ComponentGroupMember struct {
+	Group     *ComponentGroup `gorm:"foreignKey:GroupID"                                json:"-"`
+	Component *Component      `gorm:"foreignKey:ComponentID;constraint:OnDelete:CASCADE" json:"-"`
+
+	GroupID     *ID `gorm:"primaryKey" json:"groupId"`
+	ComponentID *ID `gorm:"primaryKey" json:"componentId"`
+}
+
+// ComponentIDs returns the IDs of the member components.
+func (g *ComponentGroup) ComponentIDs() []ID {
+	ids := []ID{}
+
+	if g.Members != nil {
+		for _, member := range *g.Members {
+			if member.ComponentID != nil {
+				ids = append(ids, *member.ComponentID)
+			}
+		}
+	}
+
+	return ids
+}
+
+// ToAPIResponse converts to API response.
+func (g *ComponentGroup) ToAPIResponse() api.ComponentGroupResponseData {
+	response := api.ComponentGroupResponseData{
+		ID:          g.ID,
+		DisplayName: g.DisplayName,
+		Description: g.Description,
+		ParentID:    g.ParentID,
+		Order:       0,
+		Components:  g.ComponentIDs(),
+	}
+
+	if g.Order != nil {
+		response.Order = *g.Order
+	}
+
+	return response
+}
+
+// ComponentGroupFromAPI creates a [ComponentGroup] from an API request.
+func ComponentGroupFromAPI(groupRequest *api.ComponentGroupRequest) (*ComponentGroup, error) {
+	if groupRequest == nil {
+		return nil, ErrEmptyValue
+	}
+
+	return &ComponentGroup{ //nolint:exhaustruct
+		DisplayName: groupRequest.DisplayName,
+		Description: groupRequest.Description,
+		ParentID:    groupRequest.ParentID.Value,
+		Order:       groupRequest.Order,
+	}, nil
+}
@@ -1,14 +1,17 @@
 package db
 
 import (
+	"fmt"
+
 	"github.com/SovereignCloudStack/status-page-api/pkg/api"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 )
 
 // ImpactType represents the type of impact.
 type ImpactType struct {
-	DisplayName *apiServerDefinition.DisplayName `gorm:"not null"    yaml:"displayname"`
-	Description *apiServerDefinition.Description `yaml:"description"`
+	DisplayName *apiServerDefinition.DisplayName `gorm:"not null"    json:"displayName" yaml:"displayname"`
+	Description *apiServerDefinition.Description `json:"description" yaml:"description"`
+	Version     *int                             `gorm:"not null;default:1" json:"-" yaml:"-"`
 	Model       `gorm:"embedded"`
 }
 
@@ -35,15 +38,15 @@ func ImpactTypeFromAPI(impactTypeRequest *apiServerDefinition.ImpactType) (*Impa
 
 // Impact connect a [Incident] with a [Component] and [ImpactType].
 type Impact struct {
-	Incident   *Incident   `gorm:"foreignKey:IncidentID"`
-	Component  *Component  `gorm:"foreignKey:ComponentID"`
-	ImpactType *ImpactType `gorm:"foreignKey:ImpactTypeID"`
+	Incident   *Incident   `gorm:"foreignKey:IncidentID"   json:"-"`
+	Component  *Component  `gorm:"foreignKey:ComponentID"  json:"-"`
+	ImpactType *ImpactType `gorm:"foreignKey:ImpactTypeID" json:"-"`
 
-	IncidentID   *ID `gorm:"primaryKey"`
-	ComponentID  *ID `gorm:"primaryKey"`
-	ImpactTypeID *ID `gorm:"primaryKey"`
+	IncidentID   *ID `gorm:"primaryKey" json:"incidentId"`
+	ComponentID  *ID `gorm:"primaryKey" json:"componentId"`
+	ImpactTypeID *ID `gorm:"primaryKey" json:"impactTypeId"`
 
-	Severity *apiServerDefinition.SeverityValue `gorm:"type:smallint"`
+	Severity *apiServerDefinition.SeverityValue `gorm:"type:smallint" json:"severity"`
 }
 
 // AffectsFromImpactComponentList parses a [apiServerDefinition.ImpactComponentList] to an [Impact] list.
@@ -56,7 +59,7 @@ func AffectsFromImpactComponentList(componentImpacts *apiServerDefinition.Impact
 
 	for impactIndex, impact := range *componentImpacts {
 		if impact.Severity != nil && (*impact.Severity < api.MaintenanceSeverity || *impact.Severity > api.MaxSeverity) {
-			return nil, ErrSeverityValueOutOfRange
+			return nil, NewFieldError(fmt.Sprintf("affects[%d].severity", impactIndex), ErrSeverityValueOutOfRange)
 		}
 
 		impacts[impactIndex].ComponentID = impact.Reference
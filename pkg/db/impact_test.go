@@ -2,6 +2,7 @@ package db_test
 
 import (
 	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
 	"github.com/SovereignCloudStack/status-page-api/pkg/db"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/google/uuid"
@@ -129,5 +130,23 @@ var _ = Describe("Impact", func() {
 				Ω(res).Should(BeNil())
 			})
 		})
+
+		Context("with severity out of range", func() {
+			It("should return the path of the severity", func() {
+				// Arrange
+				componentImpacts := &apiServerDefinition.ImpactComponentList{
+					{Severity: test.Ptr(50)},
+					{Severity: test.Ptr(api.MaxSeverity + 1)},
+				}
+
+				// Act
+				res, err := db.AffectsFromImpactComponentList(componentImpacts)
+
+				// Assert
+				Ω(err).Should(MatchError(db.ErrSeverityValueOutOfRange))
+				Ω(err).Should(HaveField("Field", "affects[1].severity"))
+				Ω(res).Should(BeNil())
+			})
+		})
 	})
 })
@@ -7,19 +7,23 @@ import (
 	"github.com/SovereignCloudStack/status-page-api/pkg/api"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Incident represents an incident happening to one or more [Component].
 type Incident struct {
-	DisplayName     *apiServerDefinition.DisplayName
-	Description     *apiServerDefinition.Description
-	Affects         *[]Impact `gorm:"foreignKey:IncidentID;constraint:OnDelete:CASCADE"`
-	BeganAt         *apiServerDefinition.Date
-	EndedAt         *apiServerDefinition.Date
-	PhaseGeneration *apiServerDefinition.Incremental
-	PhaseOrder      *apiServerDefinition.Incremental
-	Phase           *Phase            `gorm:"foreignKey:PhaseGeneration,PhaseOrder;References:Generation,Order"`
-	Updates         *[]IncidentUpdate `gorm:"foreignKey:IncidentID;constraint:OnDelete:CASCADE"`
+	DisplayName     *apiServerDefinition.DisplayName `json:"displayName"`
+	Description     *apiServerDefinition.Description `json:"description"`
+	Affects         *[]Impact                        `gorm:"foreignKey:IncidentID;constraint:OnDelete:CASCADE"                 json:"affects"`
+	BeganAt         *apiServerDefinition.Date        `json:"beganAt"`
+	EndedAt         *apiServerDefinition.Date        `json:"endedAt"`
+	PhaseGeneration *apiServerDefinition.Incremental `json:"phaseGeneration"`
+	PhaseOrder      *apiServerDefinition.Incremental `json:"phaseOrder"`
+	Phase           *Phase                           `gorm:"foreignKey:PhaseGeneration,PhaseOrder;References:Generation,Order" json:"-"`
+	Updates         *[]IncidentUpdate                `gorm:"foreignKey:IncidentID;constraint:OnDelete:CASCADE"                 json:"-"`
+	Version         *int                             `gorm:"not null;default:1"                                                json:"-"`
+	DeletedAt       gorm.DeletedAt                   `gorm:"index"                                                             json:"-"`
+	DeletedBy       *string                          `json:"-"`
 	Model           `gorm:"embedded"`
 }
 
@@ -40,6 +44,14 @@ func (i *Incident) ToAPIResponse() apiServerDefinition.IncidentResponseData {
 	}
 }
 
+// ToDeletableAPIResponse converts to API response, marked if the incident is deleted.
+func (i *Incident) ToDeletableAPIResponse() api.DeletableIncidentResponseData {
+	return api.DeletableIncidentResponseData{
+		IncidentResponseData: i.ToAPIResponse(),
+		Deletion:             NewDeletion(i.DeletedAt, i.DeletedBy),
+	}
+}
+
 // GetImpactComponentList converts the Affects list to an [apiServerDefinition.ImpactComponentList].
 func (i *Incident) GetImpactComponentList() *apiServerDefinition.ImpactComponentList {
 	impacts := make(apiServerDefinition.ImpactComponentList, len(*i.Affects))
@@ -64,6 +76,11 @@ func (i *Incident) GetIncidentUpdates() *apiServerDefinition.IncrementalList {
 	return &updates
 }
 
+// IsMaintenance reports, if the incident is a maintenance, i.e. has an impact of [api.MaintenanceSeverity].
+func (i *Incident) IsMaintenance() bool {
+	return isMaintenance(i.Affects)
+}
+
 func isMaintenance(impacts *[]Impact) bool {
 	if impacts == nil {
 		return false
@@ -91,7 +108,7 @@ func IncidentFromAPI(incidentRequest *apiServerDefinition.Incident) (*Incident,
 	if incidentRequest.BeganAt != nil &&
 		incidentRequest.EndedAt != nil &&
 		incidentRequest.EndedAt.Before(*incidentRequest.BeganAt) {
-		return nil, ErrEndsBeforeStart
+		return nil, NewFieldError("endedAt", ErrEndsBeforeStart)
 	}
 
 	affects, err := AffectsFromImpactComponentList(incidentRequest.Affects)
@@ -102,7 +119,7 @@ func IncidentFromAPI(incidentRequest *apiServerDefinition.Incident) (*Incident,
 	}
 
 	if isMaintenance(affects) && incidentRequest.EndedAt == nil {
-		return nil, ErrMaintenanceNeedsEnd
+		return nil, NewFieldError("endedAt", ErrMaintenanceNeedsEnd)
 	}
 
 	phase, err := PhaseReferenceFromAPI(incidentRequest.Phase)
@@ -124,11 +141,12 @@ func IncidentFromAPI(incidentRequest *apiServerDefinition.Incident) (*Incident,
 
 // IncidentUpdate describes a action that changes the incident.
 type IncidentUpdate struct {
-	IncidentID  *ID                              `gorm:"primaryKey"`
-	Order       *apiServerDefinition.Incremental `gorm:"primaryKey"`
-	DisplayName *apiServerDefinition.DisplayName
-	Description *apiServerDefinition.Description
-	CreatedAt   *apiServerDefinition.Date
+	IncidentID  *ID                              `gorm:"primaryKey"  json:"incidentId"`
+	Order       *apiServerDefinition.Incremental `gorm:"primaryKey"  json:"order"`
+	DisplayName *apiServerDefinition.DisplayName `json:"displayName"`
+	Description *apiServerDefinition.Description `json:"description"`
+	CreatedAt   *apiServerDefinition.Date        `json:"createdAt"`
+	Version     *int                             `gorm:"not null;default:1" json:"-"`
 }
 
 // ToAPIResponse converts to API response.
@@ -157,6 +175,7 @@ func IncidentUpdateFromAPI(
 		DisplayName: incidentUpdateRequest.DisplayName,
 		Description: incidentUpdateRequest.Description,
 		CreatedAt:   incidentUpdateRequest.CreatedAt,
+		Version:     nil,
 	}
 
 	return &incidentUpdate, nil
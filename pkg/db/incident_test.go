@@ -230,7 +230,8 @@ var _ = Describe("Incident", func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(db.ErrMaintenanceNeedsEnd))
+				Ω(err).Should(MatchError(db.ErrMaintenanceNeedsEnd))
+				Ω(err).Should(HaveField("Field", "endedAt"))
 				Ω(res).Should(BeNil())
 			})
 		})
@@ -257,7 +258,8 @@ var _ = Describe("Incident", func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(db.ErrEndsBeforeStart))
+				Ω(err).Should(MatchError(db.ErrEndsBeforeStart))
+				Ω(err).Should(HaveField("Field", "endedAt"))
 				Ω(res).Should(BeNil())
 			})
 		})
@@ -1,10 +1,15 @@
 package db
 
 import (
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
 // Labels are metadata for components.
@@ -12,10 +17,73 @@ type Labels apiServerDefinition.Labels
 
 // Scan implements the [database/sql.Scanner] interface to correctly read data.
 func (l *Labels) Scan(value interface{}) error {
-	data, ok := value.([]byte)
-	if !ok {
+	var data []byte
+
+	switch typedValue := value.(type) {
+	case []byte:
+		data = typedValue
+	case string:
+		data = []byte(typedValue)
+	default:
 		return fmt.Errorf("%w: %v", ErrInvalidLabelData, value)
 	}
 
 	return json.Unmarshal(data, l) //nolint:wrapcheck
 }
+
+// Value implements the [database/sql/driver.Valuer] interface to correctly write data.
+func (l Labels) Value() (driver.Value, error) {
+	data, err := json.Marshal(map[string]string(l))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding labels: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// GormDBDataType implements [gorm.io/gorm/migrator.GormDataTypeInterface], labels are stored as JSON document.
+func (Labels) GormDBDataType(dbCon *gorm.DB, _ *schema.Field) string {
+	if dbCon.Dialector.Name() == DialectSQLite {
+		return "text"
+	}
+
+	return "jsonb"
+}
+
+// ContainsLabels is a condition matching rows, whose labels in the column carry all of the labels.
+func ContainsLabels(dbCon *gorm.DB, column string, labels Labels) (clause.Expression, error) {
+	labelDocument, err := json.Marshal(labels)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding labels: %w", err)
+	}
+
+	if dbCon.Dialector.Name() == DialectSQLite {
+		// SQLite lacks JSON containment, no label may differ instead.
+		return clause.Expr{ //nolint:exhaustruct
+			SQL: "NOT EXISTS (SELECT 1 FROM json_each(?) AS selector " +
+				"WHERE json_extract(?, '$.\"' || selector.key || '\"') IS NOT selector.value)",
+			Vars: []interface{}{string(labelDocument), clause.Column{Name: column, Raw: true}}, //nolint:exhaustruct
+		}, nil
+	}
+
+	return clause.Expr{ //nolint:exhaustruct
+		SQL:  "? @> ?",
+		Vars: []interface{}{clause.Column{Name: column, Raw: true}, string(labelDocument)}, //nolint:exhaustruct
+	}, nil
+}
+
+// LabelsFromSelectors parses `key:value` selectors to the [Labels] a component must carry to match all of them.
+func LabelsFromSelectors(selectors []string) (Labels, error) {
+	labels := make(Labels, len(selectors))
+
+	for _, selector := range selectors {
+		key, value, found := strings.Cut(selector, ":")
+		if !found || key == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidLabelSelector, selector)
+		}
+
+		labels[key] = value
+	}
+
+	return labels, nil
+}
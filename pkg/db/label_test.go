@@ -10,6 +10,20 @@ import (
 )
 
 var _ = Describe("Label", func() {
+	Describe("Value", func() {
+		It("should encode json", func() {
+			// Arrange
+			labels := db.Labels{"location": "west"}
+
+			// Act
+			value, err := labels.Value()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(value).Should(Equal(`{"location":"west"}`))
+		})
+	})
+
 	Describe("Scan", func() {
 		Context("with valid data", func() {
 			It("should parse json", func() {
@@ -42,4 +56,36 @@ var _ = Describe("Label", func() {
 			})
 		})
 	})
+
+	Describe("LabelsFromSelectors", func() {
+		Context("with valid selectors", func() {
+			It("should return the labels", func() {
+				// Arrange
+				selectors := []string{"region:datacenter-west", "tier:", "url:https://example.com"}
+
+				// Act
+				labels, err := db.LabelsFromSelectors(selectors)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(labels).Should(Equal(db.Labels{
+					"region": "datacenter-west",
+					"tier":   "",
+					"url":    "https://example.com",
+				}))
+			})
+		})
+
+		DescribeTable("with invalid selector",
+			func(selector string) {
+				// Act
+				_, err := db.LabelsFromSelectors([]string{selector})
+
+				// Assert
+				Ω(err).Should(MatchError(db.ErrInvalidLabelSelector))
+			},
+			Entry("without separator", "region"),
+			Entry("without key", ":datacenter-west"),
+		)
+	})
 })
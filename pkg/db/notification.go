@@ -0,0 +1,61 @@
+package db
+
+import "time"
+
+// NotificationStatus is the state of a [Notification].
+type NotificationStatus string
+
+const (
+	// NotificationPending is a notification, which is not yet sent and will be attempted again.
+	NotificationPending NotificationStatus = "pending"
+	// NotificationSent is a notification accepted by the mail server.
+	NotificationSent NotificationStatus = "sent"
+	// NotificationDead is a notification, which failed too often and is not attempted again.
+	NotificationDead NotificationStatus = "dead"
+)
+
+// Notification is a queued email to a [Subscriber].
+// It is rendered when queued, so retries send the same content.
+type Notification struct {
+	ID             uint64             `gorm:"primaryKey;autoIncrement"`
+	SubscriberID   ID                 `gorm:"type:uuid;not null;index"`
+	Subscriber     *Subscriber        `gorm:"constraint:OnDelete:CASCADE"`
+	Recipient      string             `gorm:"not null"`
+	Subject        string             `gorm:"not null"`
+	TextBody       string             `gorm:"not null"`
+	HTMLBody       string             `gorm:"not null"`
+	UnsubscribeURL string             `gorm:"not null"`
+	Status         NotificationStatus `gorm:"not null;index:idx_notifications_due"`
+	Attempts       int                `gorm:"not null"`
+	NextAttemptAt  time.Time          `gorm:"not null;index:idx_notifications_due"`
+	LastError      *string
+	CreatedAt      time.Time `gorm:"not null"`
+	SentAt         *time.Time
+}
+
+// NewNotification creates a pending [Notification] to the [Subscriber], due immediately.
+func NewNotification(
+	subscriber *Subscriber,
+	subject string,
+	textBody string,
+	htmlBody string,
+	unsubscribeURL string,
+	now time.Time,
+) *Notification {
+	return &Notification{
+		ID:             0,
+		SubscriberID:   subscriber.ID,
+		Subscriber:     nil,
+		Recipient:      *subscriber.Email,
+		Subject:        subject,
+		TextBody:       textBody,
+		HTMLBody:       htmlBody,
+		UnsubscribeURL: unsubscribeURL,
+		Status:         NotificationPending,
+		Attempts:       0,
+		NextAttemptAt:  now,
+		LastError:      nil,
+		CreatedAt:      now,
+		SentAt:         nil,
+	}
+}
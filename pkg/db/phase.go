@@ -4,9 +4,9 @@ import apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/p
 
 // Phase represents a state of an incident on a moving scale to resolution of the incident.
 type Phase struct {
-	Name       *apiServerDefinition.Phase       `gorm:"not null"   yaml:"name"`
-	Generation *apiServerDefinition.Incremental `gorm:"primaryKey"`
-	Order      *apiServerDefinition.Incremental `gorm:"primaryKey"`
+	Name       *apiServerDefinition.Phase       `gorm:"not null"   json:"name"       yaml:"name"`
+	Generation *apiServerDefinition.Incremental `gorm:"primaryKey" json:"generation"`
+	Order      *apiServerDefinition.Incremental `gorm:"primaryKey" json:"order"`
 }
 
 // PhaseReferenceFromAPI creates a [Phase] from an API request.
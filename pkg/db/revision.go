@@ -0,0 +1,133 @@
+package db
+
+import (
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+)
+
+// IncidentRevision is the immutable state of an [Incident] with its impacts after a change.
+// Revisions of an incident are numbered from 1, a revision is valid until the next one was recorded.
+type IncidentRevision struct {
+	IncidentID      *ID                              `gorm:"primaryKey"                                                             json:"incidentId"`
+	Revision        *int                             `gorm:"primaryKey;autoIncrement:false"                                         json:"revision"`
+	DisplayName     *apiServerDefinition.DisplayName `json:"displayName"`
+	Description     *apiServerDefinition.Description `json:"description"`
+	BeganAt         *apiServerDefinition.Date        `json:"beganAt"`
+	EndedAt         *apiServerDefinition.Date        `json:"endedAt"`
+	PhaseGeneration *apiServerDefinition.Incremental `json:"phaseGeneration"`
+	PhaseOrder      *apiServerDefinition.Incremental `json:"phaseOrder"`
+	Affects         *[]IncidentRevisionImpact        `gorm:"foreignKey:IncidentID,Revision;references:IncidentID,Revision" json:"affects"`
+	RecordedAt      *time.Time                       `gorm:"not null"                                                               json:"recordedAt"`
+}
+
+// IncidentRevisionImpact is an [Impact] of an [IncidentRevision].
+// Impacts of revisions keep their references, when the component or impact type is deleted.
+type IncidentRevisionImpact struct {
+	IncidentID   *ID  `gorm:"primaryKey"                     json:"incidentId"`
+	Revision     *int `gorm:"primaryKey;autoIncrement:false" json:"revision"`
+	ComponentID  *ID  `gorm:"primaryKey"                     json:"componentId"`
+	ImpactTypeID *ID  `gorm:"primaryKey"                     json:"impactTypeId"`
+
+	Severity *apiServerDefinition.SeverityValue `gorm:"type:smallint" json:"severity"`
+}
+
+// NewIncidentRevision records the state of the incident with its impacts as revision.
+func NewIncidentRevision(incident *Incident, revision int, recordedAt time.Time) *IncidentRevision {
+	impacts := []IncidentRevisionImpact{}
+
+	if incident.Affects != nil {
+		for _, impact := range *incident.Affects {
+			impacts = append(impacts, IncidentRevisionImpact{
+				IncidentID:   &incident.ID,
+				Revision:     &revision,
+				ComponentID:  impact.ComponentID,
+				ImpactTypeID: impact.ImpactTypeID,
+				Severity:     impact.Severity,
+			})
+		}
+	}
+
+	return &IncidentRevision{
+		IncidentID:      &incident.ID,
+		Revision:        &revision,
+		DisplayName:     incident.DisplayName,
+		Description:     incident.Description,
+		BeganAt:         incident.BeganAt,
+		EndedAt:         incident.EndedAt,
+		PhaseGeneration: incident.PhaseGeneration,
+		PhaseOrder:      incident.PhaseOrder,
+		Affects:         &impacts,
+		RecordedAt:      &recordedAt,
+	}
+}
+
+// ToIncident restores the incident with its impacts as recorded in the revision.
+// Updates of incidents are not revised, they are left unset.
+func (r *IncidentRevision) ToIncident() *Incident {
+	impacts := []Impact{}
+
+	if r.Affects != nil {
+		for _, impact := range *r.Affects {
+			impacts = append(impacts, impact.ToImpact())
+		}
+	}
+
+	incident := Incident{ //nolint:exhaustruct
+		DisplayName:     r.DisplayName,
+		Description:     r.Description,
+		Affects:         &impacts,
+		BeganAt:         r.BeganAt,
+		EndedAt:         r.EndedAt,
+		PhaseGeneration: r.PhaseGeneration,
+		PhaseOrder:      r.PhaseOrder,
+	}
+
+	if r.IncidentID != nil {
+		incident.ID = *r.IncidentID
+	}
+
+	return &incident
+}
+
+// ToAPIResponse converts to API response.
+func (r *IncidentRevision) ToAPIResponse() api.IncidentRevision {
+	revision := api.IncidentRevision{
+		IncidentID:  *r.IncidentID,
+		Revision:    *r.Revision,
+		RecordedAt:  *r.RecordedAt,
+		DisplayName: r.DisplayName,
+		Description: r.Description,
+		BeganAt:     r.BeganAt,
+		EndedAt:     r.EndedAt,
+		Phase:       nil,
+		Affects:     []api.IncidentRevisionImpact{},
+	}
+
+	if r.PhaseGeneration != nil && r.PhaseOrder != nil {
+		revision.Phase = &api.IncidentRevisionPhase{Generation: *r.PhaseGeneration, Order: *r.PhaseOrder}
+	}
+
+	if r.Affects != nil {
+		for _, impact := range *r.Affects {
+			revision.Affects = append(revision.Affects, api.IncidentRevisionImpact{
+				Reference: impact.ComponentID,
+				Type:      impact.ImpactTypeID,
+				Severity:  impact.Severity,
+			})
+		}
+	}
+
+	return revision
+}
+
+// ToImpact converts to the [Impact] of the restored incident.
+func (ri *IncidentRevisionImpact) ToImpact() Impact {
+	return Impact{ //nolint:exhaustruct
+		IncidentID:   ri.IncidentID,
+		ComponentID:  ri.ComponentID,
+		ImpactTypeID: ri.ImpactTypeID,
+		Severity:     ri.Severity,
+	}
+}
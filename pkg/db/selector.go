@@ -0,0 +1,288 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SelectorOperator is the operator of a [Requirement].
+type SelectorOperator string
+
+const (
+	// SelectorEquals requires the label to carry the value.
+	SelectorEquals SelectorOperator = "="
+	// SelectorNotEquals requires the label to be missing or carry another value.
+	SelectorNotEquals SelectorOperator = "!="
+	// SelectorIn requires the label to carry one of the values.
+	SelectorIn SelectorOperator = "in"
+	// SelectorNotIn requires the label to be missing or carry none of the values.
+	SelectorNotIn SelectorOperator = "notin"
+	// SelectorExists requires the label to be set.
+	SelectorExists SelectorOperator = "exists"
+	// SelectorDoesNotExist requires the label to be missing.
+	SelectorDoesNotExist SelectorOperator = "!"
+)
+
+var (
+	// `<key> in (<values>)` or `<key> notin (<values>)`.
+	setRequirementPattern = regexp.MustCompile(`^(\S+?)\s+(in|notin)\s*\(([^()]*)\)$`)
+	// `<key>=<value>`, `<key>==<value>` or `<key>!=<value>`.
+	comparisonRequirementPattern = regexp.MustCompile(`^([^\s=!]+)\s*(==|=|!=)\s*(\S*)$`)
+	// `<key>` or `!<key>`.
+	existenceRequirementPattern = regexp.MustCompile(`^(!?)\s*(\S+)$`)
+	// Label keys are optionally prefixed names, like `example.com/name`.
+	labelKeyPattern = regexp.MustCompile(
+		`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`,
+	)
+	// Label values are not restricted beyond the syntax of selectors.
+	labelValuePattern = regexp.MustCompile(`^[^\s,()]*$`)
+)
+
+// Requirement is a condition on one label of a [Selector].
+type Requirement struct {
+	Key      string
+	Operator SelectorOperator
+	// Values are compared to the label, empty for [SelectorExists] and [SelectorDoesNotExist].
+	Values []string
+}
+
+// Matches checks, if the labels fulfill the requirement.
+func (r Requirement) Matches(labels Labels) bool {
+	value, found := labels[r.Key]
+
+	switch r.Operator {
+	case SelectorEquals, SelectorIn:
+		return found && slices.Contains(r.Values, value)
+	case SelectorNotEquals, SelectorNotIn:
+		return !found || !slices.Contains(r.Values, value)
+	case SelectorExists:
+		return found
+	case SelectorDoesNotExist:
+		return !found
+	default:
+		return false
+	}
+}
+
+// Selector selects components by their labels, following the syntax of Kubernetes label selectors.
+// Components must fulfill all requirements, the empty selector selects all components.
+type Selector []Requirement
+
+// ParseSelector parses a comma separated list of requirements,
+// e.g. `region=datacenter-west,az in (1,2),!deprecated`.
+func ParseSelector(selector string) (Selector, error) {
+	if strings.TrimSpace(selector) == "" {
+		return nil, nil
+	}
+
+	rawRequirements, err := splitRequirements(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements := make(Selector, 0, len(rawRequirements))
+
+	for _, rawRequirement := range rawRequirements {
+		requirement, err := parseRequirement(strings.TrimSpace(rawRequirement))
+		if err != nil {
+			return nil, err
+		}
+
+		requirements = append(requirements, requirement)
+	}
+
+	return requirements, nil
+}
+
+// splitRequirements splits the selector at commas outside of value sets.
+func splitRequirements(selector string) ([]string, error) {
+	var (
+		requirements []string
+		inSet        bool
+		start        int
+	)
+
+	for index, char := range selector {
+		switch char {
+		case '(':
+			if inSet {
+				return nil, fmt.Errorf("%w: nested parenthesis in %s", ErrInvalidLabelSelector, selector)
+			}
+
+			inSet = true
+		case ')':
+			if !inSet {
+				return nil, fmt.Errorf("%w: unopened parenthesis in %s", ErrInvalidLabelSelector, selector)
+			}
+
+			inSet = false
+		case ',':
+			if !inSet {
+				requirements = append(requirements, selector[start:index])
+				start = index + 1
+			}
+		}
+	}
+
+	if inSet {
+		return nil, fmt.Errorf("%w: unclosed parenthesis in %s", ErrInvalidLabelSelector, selector)
+	}
+
+	return append(requirements, selector[start:]), nil
+}
+
+func parseRequirement(requirement string) (Requirement, error) {
+	var parsed Requirement
+
+	if matches := setRequirementPattern.FindStringSubmatch(requirement); matches != nil {
+		parsed = Requirement{Key: matches[1], Operator: SelectorOperator(matches[2]), Values: nil}
+
+		if strings.TrimSpace(matches[3]) == "" {
+			return Requirement{}, fmt.Errorf("%w: empty set in %s", ErrInvalidLabelSelector, requirement) //nolint:exhaustruct
+		}
+
+		for _, value := range strings.Split(matches[3], ",") {
+			parsed.Values = append(parsed.Values, strings.TrimSpace(value))
+		}
+	} else if matches := comparisonRequirementPattern.FindStringSubmatch(requirement); matches != nil {
+		parsed = Requirement{Key: matches[1], Operator: SelectorEquals, Values: []string{matches[3]}}
+
+		if matches[2] == string(SelectorNotEquals) {
+			parsed.Operator = SelectorNotEquals
+		}
+	} else if matches := existenceRequirementPattern.FindStringSubmatch(requirement); matches != nil {
+		parsed = Requirement{Key: matches[2], Operator: SelectorExists, Values: nil}
+
+		if matches[1] == string(SelectorDoesNotExist) {
+			parsed.Operator = SelectorDoesNotExist
+		}
+	} else {
+		return Requirement{}, fmt.Errorf("%w: %s", ErrInvalidLabelSelector, requirement) //nolint:exhaustruct
+	}
+
+	if !labelKeyPattern.MatchString(parsed.Key) {
+		return Requirement{}, fmt.Errorf("%w: invalid key in %s", ErrInvalidLabelSelector, requirement) //nolint:exhaustruct
+	}
+
+	for _, value := range parsed.Values {
+		if !labelValuePattern.MatchString(value) {
+			return Requirement{}, //nolint:exhaustruct
+				fmt.Errorf("%w: invalid value in %s", ErrInvalidLabelSelector, requirement)
+		}
+	}
+
+	return parsed, nil
+}
+
+// Matches checks, if the labels fulfill all requirements of the selector.
+func (s Selector) Matches(labels Labels) bool {
+	for _, requirement := range s {
+		if !requirement.Matches(labels) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MatchesSelector is a condition matching rows, whose labels in the column fulfill all requirements of the selector.
+// On PostgreSQL, the conditions use the containment and key existence operators supported by GIN indexes.
+func MatchesSelector(dbCon *gorm.DB, column string, selector Selector) (clause.Expression, error) {
+	conditions := make([]string, 0, len(selector))
+	vars := []interface{}{}
+
+	for _, requirement := range selector {
+		var (
+			condition     string
+			conditionVars []interface{}
+			err           error
+		)
+
+		if dbCon.Dialector.Name() == DialectSQLite {
+			condition, conditionVars = sqliteRequirement(column, requirement)
+		} else {
+			condition, conditionVars, err = postgresRequirement(column, requirement)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		conditions = append(conditions, condition)
+		vars = append(vars, conditionVars...)
+	}
+
+	// a single expression, as gorm would join alternatives of a requirement with the other conditions of a query.
+	return clause.Expr{SQL: strings.Join(conditions, " AND "), Vars: vars}, nil //nolint:exhaustruct
+}
+
+// postgresRequirement matches labels with a document of each value, negations also match rows without labels.
+func postgresRequirement(column string, requirement Requirement) (string, []interface{}, error) {
+	columnExpr := clause.Column{Name: column, Raw: true} //nolint:exhaustruct
+
+	condition := "?"
+	vars := []interface{}{jsonbHasKey{column: columnExpr, key: requirement.Key}}
+
+	if len(requirement.Values) > 0 {
+		alternatives := make([]string, 0, len(requirement.Values))
+		vars = make([]interface{}, 0, 2*len(requirement.Values)) //nolint:mnd
+
+		for _, value := range requirement.Values {
+			labelDocument, err := json.Marshal(Labels{requirement.Key: value})
+			if err != nil {
+				return "", nil, fmt.Errorf("error encoding labels: %w", err)
+			}
+
+			alternatives = append(alternatives, "? @> ?")
+			vars = append(vars, columnExpr, string(labelDocument))
+		}
+
+		condition = strings.Join(alternatives, " OR ")
+		if len(alternatives) > 1 {
+			condition = "(" + condition + ")"
+		}
+	}
+
+	switch requirement.Operator {
+	case SelectorNotEquals, SelectorNotIn, SelectorDoesNotExist:
+		return "NOT COALESCE(" + condition + ", FALSE)", vars, nil
+	default:
+		return condition, vars, nil
+	}
+}
+
+// jsonbHasKey is the condition of a JSON document having the key.
+// It is written directly, as gorm would take the `?` operator of PostgreSQL for a placeholder.
+type jsonbHasKey struct {
+	column clause.Column
+	key    string
+}
+
+// Build implements [clause.Expression].
+func (h jsonbHasKey) Build(builder clause.Builder) {
+	builder.WriteQuoted(h.column)
+	builder.WriteString(" ? ")
+	builder.AddVar(builder, h.key)
+}
+
+// sqliteRequirement matches labels extracted by their JSON path, missing labels are extracted as NULL.
+func sqliteRequirement(column string, requirement Requirement) (string, []interface{}) {
+	columnExpr := clause.Column{Name: column, Raw: true} //nolint:exhaustruct
+	path := `$."` + requirement.Key + `"`
+
+	switch requirement.Operator {
+	case SelectorEquals, SelectorIn:
+		return "json_extract(?, ?) IN (?)", []interface{}{columnExpr, path, requirement.Values}
+	case SelectorNotEquals, SelectorNotIn:
+		return "COALESCE(json_extract(?, ?) NOT IN (?), TRUE)", []interface{}{columnExpr, path, requirement.Values}
+	case SelectorDoesNotExist:
+		return "json_type(?, ?) IS NULL", []interface{}{columnExpr, path}
+	default:
+		return "json_type(?, ?) IS NOT NULL", []interface{}{columnExpr, path}
+	}
+}
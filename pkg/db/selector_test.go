@@ -0,0 +1,146 @@
+package db_test
+
+import (
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Selector", func() {
+	Describe("ParseSelector", func() {
+		Context("with valid selector", func() {
+			It("should return the requirements", func() {
+				// Arrange
+				selector := "region=datacenter-west, tier==gold,az in (1, 2),example.com/zone notin (a),env != prod," +
+					"!deprecated,team"
+
+				// Act
+				requirements, err := db.ParseSelector(selector)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(requirements).Should(Equal(db.Selector{
+					{Key: "region", Operator: db.SelectorEquals, Values: []string{"datacenter-west"}},
+					{Key: "tier", Operator: db.SelectorEquals, Values: []string{"gold"}},
+					{Key: "az", Operator: db.SelectorIn, Values: []string{"1", "2"}},
+					{Key: "example.com/zone", Operator: db.SelectorNotIn, Values: []string{"a"}},
+					{Key: "env", Operator: db.SelectorNotEquals, Values: []string{"prod"}},
+					{Key: "deprecated", Operator: db.SelectorDoesNotExist, Values: nil},
+					{Key: "team", Operator: db.SelectorExists, Values: nil},
+				}))
+			})
+		})
+
+		Context("with empty selector", func() {
+			It("should select everything", func() {
+				// Act
+				requirements, err := db.ParseSelector(" ")
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(requirements).Should(BeEmpty())
+				Ω(requirements.Matches(db.Labels{"region": "datacenter-west"})).Should(BeTrue())
+			})
+		})
+
+		DescribeTable("with invalid selector",
+			func(selector string) {
+				// Act
+				_, err := db.ParseSelector(selector)
+
+				// Assert
+				Ω(err).Should(MatchError(db.ErrInvalidLabelSelector))
+			},
+			Entry("with empty requirement", "region=datacenter-west,"),
+			Entry("with empty set", "az in ()"),
+			Entry("with unclosed set", "az in (1,2"),
+			Entry("with unopened set", "az in 1,2)"),
+			Entry("with nested set", "az in ((1),2)"),
+			Entry("with invalid key", "-region=datacenter-west"),
+			Entry("with whitespace in value", "region=datacenter west"),
+			Entry("with unknown operator", "az > 1"),
+			Entry("with negated comparison", "!region=datacenter-west"),
+		)
+	})
+
+	DescribeTable("Matches",
+		func(selector string, labels db.Labels, expected bool) {
+			// Arrange
+			requirements, err := db.ParseSelector(selector)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			matches := requirements.Matches(labels)
+
+			// Assert
+			Ω(matches).Should(Equal(expected))
+		},
+		Entry("with equal value", "az=1", db.Labels{"az": "1"}, true),
+		Entry("with other value", "az=1", db.Labels{"az": "2"}, false),
+		Entry("with missing label for equality", "az=1", db.Labels{}, false),
+		Entry("with other value for inequality", "az!=1", db.Labels{"az": "2"}, true),
+		Entry("with missing label for inequality", "az!=1", nil, true),
+		Entry("with value in set", "az in (1,2)", db.Labels{"az": "2"}, true),
+		Entry("with value outside of set", "az in (1,2)", db.Labels{"az": "3"}, false),
+		Entry("with value in excluded set", "az notin (1,2)", db.Labels{"az": "1"}, false),
+		Entry("with missing label for excluded set", "az notin (1,2)", db.Labels{}, true),
+		Entry("with existing label", "az", db.Labels{"az": ""}, true),
+		Entry("with missing label", "az", db.Labels{}, false),
+		Entry("with excluded label", "!deprecated", db.Labels{"deprecated": "true"}, false),
+		Entry("with all requirements", "region=west,!deprecated", db.Labels{"region": "west"}, true),
+		Entry("with one failed requirement", "region=west,!deprecated",
+			db.Labels{"region": "west", "deprecated": ""}, false),
+	)
+
+	DescribeTableSubtree("MatchesSelector", func(dialect test.Dialect) {
+		var (
+			// sub loggers
+			_, gormLogger, _ = test.MustSetupLogging(zerolog.TraceLevel)
+
+			// sql mocking
+			sqlDB   *sql.DB
+			sqlMock sqlmock.Sqlmock
+			gormDB  *gorm.DB
+
+			expectedConditions = map[test.Dialect]string{
+				test.Postgres: `labels @> '{"region":"west"}' AND (labels @> '{"az":"1"}' OR labels @> '{"az":"2"}') ` +
+					`AND NOT COALESCE(labels ? 'deprecated', FALSE)`,
+				test.SQLite: `json_extract(labels, "$.""region""") IN ("west") ` +
+					`AND json_extract(labels, "$.""az""") IN ("1","2") ` +
+					`AND json_type(labels, "$.""deprecated""") IS NULL`,
+			}
+		)
+
+		BeforeEach(func() {
+			// setup database and mock before each test
+			sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		})
+
+		AfterEach(func() {
+			// check every expectation after each test and close database
+			Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+			sqlDB.Close()
+		})
+
+		It("should translate the requirements", func() {
+			// Arrange
+			requirements, err := db.ParseSelector("region=west,az in (1,2),!deprecated")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			condition, err := db.MatchesSelector(gormDB, "labels", requirements)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(gormDB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return tx.Unscoped().Where(condition).Find(&[]db.Component{})
+			})).Should(HaveSuffix(" WHERE " + expectedConditions[dialect]))
+		})
+	}, test.DialectEntries())
+})
@@ -7,8 +7,9 @@ import (
 
 // Severity represents a severity of a incident affecting a component.
 type Severity struct {
-	DisplayName *apiServerDefinition.DisplayName   `yaml:"name"`
-	Value       *apiServerDefinition.SeverityValue `gorm:"type:smallint;unique" yaml:"value"`
+	DisplayName *apiServerDefinition.DisplayName   `json:"displayName"          yaml:"name"`
+	Value       *apiServerDefinition.SeverityValue `gorm:"type:smallint;unique" json:"value" yaml:"value"`
+	Version     *int                               `gorm:"not null;default:1"   json:"-"     yaml:"-"`
 }
 
 // ToAPIResponse converts to API response.
@@ -28,6 +29,7 @@ func SeverityFromAPI(severityRequest *apiServerDefinition.SeverityRequest) (*Sev
 	return &Severity{
 		DisplayName: severityRequest.DisplayName,
 		Value:       severityRequest.Value,
+		Version:     nil,
 	}, nil
 }
 
@@ -43,5 +45,6 @@ func NewSeverity(
 	return &Severity{
 		DisplayName: &displayName,
 		Value:       &value,
+		Version:     nil,
 	}, nil
 }
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+)
+
+// SubscriberComponents are the IDs of the components, a [Subscriber] is notified about.
+type SubscriberComponents []ID
+
+// Scan implements the [database/sql.Scanner] interface to correctly read data.
+func (c *SubscriberComponents) Scan(value interface{}) error {
+	var data []byte
+
+	switch typedValue := value.(type) {
+	case []byte:
+		data = typedValue
+	case string:
+		data = []byte(typedValue)
+	default:
+		return fmt.Errorf("%w: %v", ErrInvalidComponentData, value)
+	}
+
+	return json.Unmarshal(data, c) //nolint:wrapcheck
+}
+
+// Value implements the [database/sql/driver.Valuer] interface to correctly write data.
+func (c SubscriberComponents) Value() (driver.Value, error) {
+	data, err := json.Marshal([]ID(c))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding components: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// Subscriber is an email address notified about incidents after confirming the subscription (double opt-in).
+// Without components and labels, the subscriber is notified about all incidents.
+type Subscriber struct {
+	Email       *string               `gorm:"not null;uniqueIndex" json:"email"`
+	Components  *SubscriberComponents `gorm:"type:jsonb"           json:"components"`
+	Labels      *Labels               `json:"labels"`
+	CreatedAt   *time.Time            `json:"createdAt"`
+	ConfirmedAt *time.Time            `json:"confirmedAt"`
+	Model       `gorm:"embedded"`
+}
+
+// Matches reports, if the subscriber is notified about the incident.
+// Components of the impacts need to be loaded to match labels.
+func (s *Subscriber) Matches(incident *Incident) bool {
+	noComponents := s.Components == nil || len(*s.Components) == 0
+	noLabels := s.Labels == nil || len(*s.Labels) == 0
+
+	if noComponents && noLabels {
+		return true
+	}
+
+	if incident.Affects == nil {
+		return false
+	}
+
+	for _, impact := range *incident.Affects {
+		if !noComponents && impact.ComponentID != nil && slices.Contains(*s.Components, *impact.ComponentID) {
+			return true
+		}
+
+		if !noLabels && impact.Component != nil && impact.Component.Labels != nil &&
+			hasLabels(*impact.Component.Labels, *s.Labels) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasLabels(labels Labels, required Labels) bool {
+	for key, value := range required {
+		actual, ok := labels[key]
+		if !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ToAPIResponse converts to API response.
+func (s *Subscriber) ToAPIResponse() api.SubscriberResponseData {
+	response := api.SubscriberResponseData{ //nolint:exhaustruct
+		ID:          s.ID,
+		Components:  []ID{},
+		Labels:      map[string]string{},
+		ConfirmedAt: s.ConfirmedAt,
+	}
+
+	if s.Email != nil {
+		response.Email = *s.Email
+	}
+
+	if s.Components != nil {
+		response.Components = *s.Components
+	}
+
+	if s.Labels != nil {
+		response.Labels = *s.Labels
+	}
+
+	if s.CreatedAt != nil {
+		response.CreatedAt = *s.CreatedAt
+	}
+
+	return response
+}
+
+// SubscriberFromAPI creates an unconfirmed [Subscriber] from an API request.
+func SubscriberFromAPI(subscriberRequest *api.SubscriberRequest) (*Subscriber, error) {
+	if subscriberRequest == nil || subscriberRequest.Email == nil {
+		return nil, ErrEmptyValue
+	}
+
+	address, err := mail.ParseAddress(*subscriberRequest.Email)
+	if err != nil || address.Name != "" {
+		return nil, NewFieldError("email", fmt.Errorf("%w: %s", ErrInvalidEmail, *subscriberRequest.Email))
+	}
+
+	email := strings.ToLower(address.Address)
+
+	components := SubscriberComponents{}
+
+	for _, componentID := range subscriberRequest.Components {
+		if !slices.Contains(components, componentID) {
+			components = append(components, componentID)
+		}
+	}
+
+	labels, err := LabelsFromSelectors(subscriberRequest.Labels)
+	if err != nil {
+		return nil, NewFieldError("labels", err)
+	}
+
+	now := time.Now()
+
+	return &Subscriber{ //nolint:exhaustruct
+		Email:      &email,
+		Components: &components,
+		Labels:     &labels,
+		CreatedAt:  &now,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package db_test
+
+import (
+	"errors"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Subscriber", func() {
+	var (
+		componentID      = uuid.MustParse("8f8b9c1e-3b5e-4c55-a58c-4b6c3b7b5d0e")
+		otherComponentID = uuid.MustParse("1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a")
+	)
+
+	Describe("SubscriberComponents", func() {
+		Context("with valid data", func() {
+			It("should round trip as json", func() {
+				// Arrange
+				components := db.SubscriberComponents{componentID}
+				result := db.SubscriberComponents{}
+
+				// Act
+				value, err := components.Value()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = result.Scan(value)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(value).Should(Equal(`["` + componentID.String() + `"]`))
+				Ω(result).Should(Equal(components))
+			})
+		})
+
+		Context("with invalid data", func() {
+			It("should return ErrInvalidComponentData", func() {
+				// Arrange
+				components := db.SubscriberComponents{}
+
+				// Act
+				err := components.Scan(842376)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(errors.Unwrap(err)).Should(Equal(db.ErrInvalidComponentData))
+			})
+		})
+	})
+
+	Describe("SubscriberFromAPI", func() {
+		Context("with valid request", func() {
+			It("should create an unconfirmed subscriber with normalized email and filters", func() {
+				// Arrange
+				request := api.SubscriberRequest{
+					Email:      test.Ptr("User@Example.com"),
+					Components: []uuid.UUID{componentID, componentID},
+					Labels:     []string{"region:west"},
+				}
+
+				// Act
+				subscriber, err := db.SubscriberFromAPI(&request)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(*subscriber.Email).Should(Equal("user@example.com"))
+				Ω(*subscriber.Components).Should(Equal(db.SubscriberComponents{componentID}))
+				Ω(*subscriber.Labels).Should(Equal(db.Labels{"region": "west"}))
+				Ω(subscriber.ConfirmedAt).Should(BeNil())
+			})
+		})
+
+		DescribeTable("with invalid request",
+			func(request api.SubscriberRequest, expectedErr error) {
+				// Act
+				_, err := db.SubscriberFromAPI(&request)
+
+				// Assert
+				Ω(err).Should(MatchError(expectedErr))
+			},
+			Entry("without email", api.SubscriberRequest{Email: nil, Components: nil, Labels: nil}, db.ErrEmptyValue),
+			Entry("with invalid email",
+				api.SubscriberRequest{Email: test.Ptr("user"), Components: nil, Labels: nil}, db.ErrInvalidEmail),
+			Entry("with display name",
+				api.SubscriberRequest{Email: test.Ptr("User <user@example.com>"), Components: nil, Labels: nil},
+				db.ErrInvalidEmail),
+			Entry("with invalid label",
+				api.SubscriberRequest{Email: test.Ptr("user@example.com"), Components: nil, Labels: []string{"region"}},
+				db.ErrInvalidLabelSelector),
+		)
+	})
+
+	Describe("Matches", func() {
+		var incident db.Incident
+
+		BeforeEach(func() {
+			incident = db.Incident{ //nolint:exhaustruct
+				Affects: &[]db.Impact{{ //nolint:exhaustruct
+					ComponentID: &componentID,
+					Component: &db.Component{ //nolint:exhaustruct
+						Labels: &db.Labels{"region": "west", "tier": "storage"},
+					},
+				}},
+			}
+		})
+
+		DescribeTable("should match subscribers by component or labels",
+			func(subscriber db.Subscriber, expected bool) {
+				// Act
+				matches := subscriber.Matches(&incident)
+
+				// Assert
+				Ω(matches).Should(Equal(expected))
+			},
+			Entry("without filters", db.Subscriber{}, true), //nolint:exhaustruct
+			Entry("with affected component",
+				db.Subscriber{Components: &db.SubscriberComponents{componentID}}, true), //nolint:exhaustruct
+			Entry("with other component",
+				db.Subscriber{Components: &db.SubscriberComponents{otherComponentID}}, false), //nolint:exhaustruct
+			Entry("with labels of an affected component",
+				db.Subscriber{Labels: &db.Labels{"region": "west"}}, true), //nolint:exhaustruct
+			Entry("with partly matching labels",
+				db.Subscriber{Labels: &db.Labels{"region": "west", "tier": "compute"}}, false), //nolint:exhaustruct
+			Entry("with other component but matching labels",
+				db.Subscriber{ //nolint:exhaustruct
+					Components: &db.SubscriberComponents{otherComponentID},
+					Labels:     &db.Labels{"tier": "storage"},
+				}, true),
+		)
+	})
+})
@@ -1,22 +1,53 @@
 package db
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DialectPostgres is the name of the PostgreSQL dialect of gorm.
+	DialectPostgres = "postgres"
+	// DialectSQLite is the name of the SQLite dialect of gorm.
+	DialectSQLite = "sqlite"
 )
 
+// OrderColumn is the `order` column of phases, incident updates and component groups.
+// It is quoted by the dialect in queries, as `order` is a reserved word.
+func OrderColumn() clause.Column {
+	return clause.Column{Name: "order"} //nolint:exhaustruct
+}
+
 // GetHighestIncidentUpdateOrder retrieves the currently highest order for an incident.
 func GetHighestIncidentUpdateOrder(dbCon *gorm.DB, incidentID uuid.UUID) (int, error) {
 	var order int
 	res := dbCon.
 		Model(&IncidentUpdate{}). //nolint:exhaustruct
-		Select("COALESCE(MAX(\"order\"), -1)").
+		Select("COALESCE(MAX(?), -1)", OrderColumn()).
 		Where("incident_id = ?", incidentID).
 		Find(&order)
 
 	return order, res.Error
 }
 
+// RecordIncidentRevision records the state of the incident with its impacts as its next revision.
+func RecordIncidentRevision(dbCon *gorm.DB, incident *Incident, recordedAt time.Time) error {
+	var revision int
+	res := dbCon.
+		Model(&IncidentRevision{}). //nolint:exhaustruct
+		Select("COALESCE(MAX(revision), 0)").
+		Where("incident_id = ?", incident.ID).
+		Find(&revision)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	return dbCon.Create(NewIncidentRevision(incident, revision+1, recordedAt)).Error
+}
+
 // GetCurrentPhaseGeneration retrieves the currently highest generation.
 func GetCurrentPhaseGeneration(dbCon *gorm.DB) (int, error) {
 	var generation int
@@ -27,3 +58,22 @@ func GetCurrentPhaseGeneration(dbCon *gorm.DB) (int, error) {
 
 	return generation, res.Error
 }
+
+// GetAPIKeyByHash retrieves an API key by the hash of its secret.
+func GetAPIKeyByHash(dbCon *gorm.DB, hash string) (*APIKey, error) {
+	var apiKey APIKey
+	res := dbCon.
+		Where("secret_hash = ?", hash).
+		First(&apiKey)
+
+	return &apiKey, res.Error
+}
+
+// MarkAPIKeyUsed sets the last usage of an API key.
+func MarkAPIKeyUsed(dbCon *gorm.DB, apiKeyID uuid.UUID, usedAt time.Time) error {
+	return dbCon.
+		Model(&APIKey{}). //nolint:exhaustruct
+		Where("id = ?", apiKeyID).
+		Update("last_used_at", usedAt).
+		Error
+}
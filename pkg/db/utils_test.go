@@ -3,6 +3,7 @@ package db_test
 import (
 	"database/sql"
 	"regexp"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
@@ -135,4 +136,66 @@ var _ = Describe("Utils", func() {
 			})
 		})
 	})
+
+	Describe("GetAPIKeyByHash", func() {
+		var expectedAPIKeyQuery = regexp.
+			QuoteMeta(`SELECT * FROM "api_keys" WHERE secret_hash = $1 ORDER BY "api_keys"."id" LIMIT $2`)
+
+		Context("with valid data", func() {
+			It("should return the API key", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedAPIKeyQuery).
+					WithArgs("hash", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(incidentUUID, "ci"))
+
+				// Act
+				res, err := db.GetAPIKeyByHash(gormDB, "hash")
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.ID).Should(Equal(incidentUUID))
+				Ω(*res.Name).Should(Equal("ci"))
+			})
+		})
+
+		Context("with unknown hash", func() {
+			It("should return gorm.ErrRecordNotFound", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedAPIKeyQuery).
+					WithArgs("hash", 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+				// Act
+				_, err := db.GetAPIKeyByHash(gormDB, "hash")
+
+				// Assert
+				Ω(err).Should(Equal(gorm.ErrRecordNotFound))
+			})
+		})
+	})
+
+	Describe("MarkAPIKeyUsed", func() {
+		var expectedAPIKeyUpdate = regexp.
+			QuoteMeta(`UPDATE "api_keys" SET "last_used_at"=$1 WHERE id = $2`)
+
+		It("should update the last usage", func() {
+			// Arrange
+			usedAt := time.Now()
+
+			sqlMock.ExpectBegin()
+			sqlMock.
+				ExpectExec(expectedAPIKeyUpdate).
+				WithArgs(usedAt, incidentUUID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			sqlMock.ExpectCommit()
+
+			// Act
+			err := db.MarkAPIKeyUsed(gormDB, incidentUUID, usedAt)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,197 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/google/uuid"
+)
+
+// WebhookEventTypes are the types of events, a [WebhookSubscription] is notified about.
+type WebhookEventTypes []EventType
+
+// Scan implements the [database/sql.Scanner] interface to correctly read data.
+func (t *WebhookEventTypes) Scan(value interface{}) error {
+	var data []byte
+
+	switch typedValue := value.(type) {
+	case []byte:
+		data = typedValue
+	case string:
+		data = []byte(typedValue)
+	default:
+		return fmt.Errorf("%w: %v", ErrInvalidEventTypeData, value)
+	}
+
+	return json.Unmarshal(data, t) //nolint:wrapcheck
+}
+
+// Value implements the [database/sql/driver.Valuer] interface to correctly write data.
+func (t WebhookEventTypes) Value() (driver.Value, error) {
+	data, err := json.Marshal([]EventType(t))
+	if err != nil {
+		return nil, fmt.Errorf("error encoding event types: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// WebhookSubscription is an endpoint notified about events by signed HTTP requests.
+type WebhookSubscription struct {
+	TargetURL *string            `gorm:"not null"            json:"targetUrl"`
+	Events    *WebhookEventTypes `gorm:"type:jsonb;not null" json:"events"`
+	Secret    *string            `gorm:"not null"            json:"-"`
+	CreatedAt *time.Time         `json:"createdAt"`
+	Model     `gorm:"embedded"`
+}
+
+// Subscribes reports, if the subscription is notified about events of the type.
+func (ws *WebhookSubscription) Subscribes(eventType EventType) bool {
+	return ws.Events != nil && slices.Contains(*ws.Events, eventType)
+}
+
+// ToAPIResponse converts to API response.
+func (ws *WebhookSubscription) ToAPIResponse() api.WebhookResponseData {
+	response := api.WebhookResponseData{ //nolint:exhaustruct
+		ID:     ws.ID,
+		Events: []string{},
+	}
+
+	if ws.TargetURL != nil {
+		response.TargetURL = *ws.TargetURL
+	}
+
+	if ws.Events != nil {
+		for _, eventType := range *ws.Events {
+			response.Events = append(response.Events, string(eventType))
+		}
+	}
+
+	if ws.CreatedAt != nil {
+		response.CreatedAt = *ws.CreatedAt
+	}
+
+	return response
+}
+
+// WebhookSubscriptionFromAPI creates a [WebhookSubscription] from an API request and the signing secret.
+func WebhookSubscriptionFromAPI(webhookRequest *api.WebhookRequest, secret string) (*WebhookSubscription, error) {
+	if webhookRequest == nil || webhookRequest.TargetURL == nil {
+		return nil, ErrEmptyValue
+	}
+
+	targetURL, err := url.Parse(*webhookRequest.TargetURL)
+	if err != nil || (targetURL.Scheme != "http" && targetURL.Scheme != "https") || targetURL.Host == "" {
+		return nil, NewFieldError("targetUrl", fmt.Errorf("%w: %s", ErrInvalidTargetURL, *webhookRequest.TargetURL))
+	}
+
+	if len(webhookRequest.Events) == 0 {
+		return nil, NewFieldError("events", ErrEmptyValue)
+	}
+
+	if secret == "" {
+		return nil, fmt.Errorf("%w: secret", ErrEmptyValue)
+	}
+
+	eventTypes := make(WebhookEventTypes, 0, len(webhookRequest.Events))
+
+	for eventIndex, eventName := range webhookRequest.Events {
+		eventType := EventType(eventName)
+		if !eventType.IsValid() {
+			return nil, NewFieldError(fmt.Sprintf("events[%d]", eventIndex), fmt.Errorf("%w: %s", ErrUnknownEventType, eventName))
+		}
+
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	now := time.Now()
+
+	return &WebhookSubscription{ //nolint:exhaustruct
+		TargetURL: webhookRequest.TargetURL,
+		Events:    &eventTypes,
+		Secret:    &secret,
+		CreatedAt: &now,
+	}, nil
+}
+
+// WebhookDeliveryStatus is the state of a [WebhookDelivery].
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending is a delivery, which is not yet successful and will be attempted again.
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered is a delivery acknowledged by the target.
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead is a delivery, which failed too often and is not attempted again (dead letter).
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// IsValid reports, if the delivery status is known.
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryDead:
+		return true
+	default:
+		return false
+	}
+}
+
+// WebhookDelivery is the delivery of an [Event] to a [WebhookSubscription].
+// Each event is delivered at most once per subscription, which is guarded by a unique index.
+type WebhookDelivery struct {
+	ID             uint64                `gorm:"primaryKey;autoIncrement"`
+	EventID        uint64                `gorm:"not null;uniqueIndex:idx_webhook_deliveries_event_subscription"`
+	Event          *Event                `gorm:"constraint:OnDelete:CASCADE"`
+	SubscriptionID uuid.UUID             `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_event_subscription"`
+	Subscription   *WebhookSubscription  `gorm:"constraint:OnDelete:CASCADE"`
+	Status         WebhookDeliveryStatus `gorm:"not null;index:idx_webhook_deliveries_due"`
+	Attempts       int                   `gorm:"not null"`
+	NextAttemptAt  time.Time             `gorm:"not null;index:idx_webhook_deliveries_due"`
+	LastError      *string
+	CreatedAt      time.Time `gorm:"not null"`
+	DeliveredAt    *time.Time
+}
+
+// NewWebhookDelivery creates a pending [WebhookDelivery], due immediately.
+func NewWebhookDelivery(eventID uint64, subscriptionID uuid.UUID, now time.Time) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:             0,
+		EventID:        eventID,
+		Event:          nil,
+		SubscriptionID: subscriptionID,
+		Subscription:   nil,
+		Status:         WebhookDeliveryPending,
+		Attempts:       0,
+		NextAttemptAt:  now,
+		LastError:      nil,
+		CreatedAt:      now,
+		DeliveredAt:    nil,
+	}
+}
+
+// ToAPIResponse converts to API response.
+func (wd *WebhookDelivery) ToAPIResponse() api.WebhookDeliveryResponseData {
+	response := api.WebhookDeliveryResponseData{ //nolint:exhaustruct
+		ID:            wd.ID,
+		EventID:       wd.EventID,
+		Status:        string(wd.Status),
+		Attempts:      wd.Attempts,
+		NextAttemptAt: wd.NextAttemptAt,
+		LastError:     wd.LastError,
+		CreatedAt:     wd.CreatedAt,
+		DeliveredAt:   wd.DeliveredAt,
+	}
+
+	if wd.Event != nil {
+		response.EventType = string(wd.Event.Type)
+	}
+
+	return response
+}
@@ -0,0 +1,110 @@
+package db_test
+
+import (
+	"errors"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhook", func() {
+	Describe("WebhookEventTypes", func() {
+		Context("with valid data", func() {
+			It("should round trip as json", func() {
+				// Arrange
+				eventTypes := db.WebhookEventTypes{db.EventIncidentCreated, db.EventComponentChanged}
+				result := db.WebhookEventTypes{}
+
+				// Act
+				value, err := eventTypes.Value()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				err = result.Scan(value)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(value).Should(Equal(`["incident.created","component.changed"]`))
+				Ω(result).Should(Equal(eventTypes))
+			})
+		})
+
+		Context("with invalid data", func() {
+			It("should return ErrInvalidEventTypeData", func() {
+				// Arrange
+				eventTypes := db.WebhookEventTypes{}
+
+				// Act
+				err := eventTypes.Scan(842376)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(errors.Unwrap(err)).Should(Equal(db.ErrInvalidEventTypeData))
+			})
+		})
+	})
+
+	Describe("WebhookSubscriptionFromAPI", func() {
+		Context("with valid request", func() {
+			It("should create a subscription without duplicate events", func() {
+				// Arrange
+				request := api.WebhookRequest{ //nolint:exhaustruct
+					TargetURL: test.Ptr("https://chat.example.com/hooks"),
+					Events:    []string{"incident.created", "incident.created", "incident.resolved"},
+				}
+
+				// Act
+				subscription, err := db.WebhookSubscriptionFromAPI(&request, "secret")
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(*subscription.Events).Should(Equal(db.WebhookEventTypes{
+					db.EventIncidentCreated,
+					db.EventIncidentResolved,
+				}))
+				Ω(*subscription.Secret).Should(Equal("secret"))
+				Ω(subscription.Subscribes(db.EventIncidentResolved)).Should(BeTrue())
+				Ω(subscription.Subscribes(db.EventComponentChanged)).Should(BeFalse())
+			})
+		})
+
+		DescribeTable("with invalid request",
+			func(request *api.WebhookRequest, expected error) {
+				// Act
+				_, err := db.WebhookSubscriptionFromAPI(request, "secret")
+
+				// Assert
+				Ω(err).Should(MatchError(expected))
+			},
+			Entry("without request", nil, db.ErrEmptyValue),
+			Entry("without target", &api.WebhookRequest{ //nolint:exhaustruct
+				Events: []string{"incident.created"},
+			}, db.ErrEmptyValue),
+			Entry("with relative target", &api.WebhookRequest{ //nolint:exhaustruct
+				TargetURL: test.Ptr("/hooks"),
+				Events:    []string{"incident.created"},
+			}, db.ErrInvalidTargetURL),
+			Entry("without events", &api.WebhookRequest{ //nolint:exhaustruct
+				TargetURL: test.Ptr("https://chat.example.com/hooks"),
+			}, db.ErrEmptyValue),
+			Entry("with unknown event", &api.WebhookRequest{ //nolint:exhaustruct
+				TargetURL: test.Ptr("https://chat.example.com/hooks"),
+				Events:    []string{"incident.archived"},
+			}, db.ErrUnknownEventType),
+		)
+	})
+
+	Describe("NewEvent", func() {
+		It("should encode the payload", func() {
+			// Act
+			event, err := db.NewEvent(db.EventComponentChanged, map[string]string{"displayName": "Storage"})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(event.DispatchedAt).Should(BeNil())
+			Ω(string(event.ToAPIResponse().Data)).Should(Equal(`{"displayName":"Storage"}`))
+		})
+	})
+})
@@ -0,0 +1,118 @@
+// Package dependency resolves the dependencies between components, to detect cycles and propagate impacts.
+package dependency
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+)
+
+// ErrCycle is an error, raised when a dependency would make a component depend on itself.
+var ErrCycle = errors.New("dependency cycle")
+
+// Graph holds the direct dependencies of each component.
+type Graph struct {
+	dependencies map[DbDef.ID][]DbDef.ID
+	dependents   map[DbDef.ID][]DbDef.ID
+}
+
+// NewGraph creates the graph of the dependencies, dependencies without components are skipped.
+func NewGraph(dependencies []*DbDef.ComponentDependency) *Graph {
+	graph := Graph{
+		dependencies: map[DbDef.ID][]DbDef.ID{},
+		dependents:   map[DbDef.ID][]DbDef.ID{},
+	}
+
+	for _, dependency := range dependencies {
+		if dependency.ComponentID == nil || dependency.DependsOnID == nil {
+			continue
+		}
+
+		graph.add(*dependency.ComponentID, *dependency.DependsOnID)
+	}
+
+	return &graph
+}
+
+func (g *Graph) add(componentID, dependsOnID DbDef.ID) {
+	if !slices.Contains(g.dependencies[componentID], dependsOnID) {
+		g.dependencies[componentID] = append(g.dependencies[componentID], dependsOnID)
+		g.dependents[dependsOnID] = append(g.dependents[dependsOnID], componentID)
+	}
+}
+
+// Check returns [ErrCycle], if the component would depend on itself with the dependency.
+func (g *Graph) Check(componentID, dependsOnID DbDef.ID) error {
+	if componentID == dependsOnID || slices.Contains(g.AllDependencies(dependsOnID), componentID) {
+		return fmt.Errorf("%w: %s already depends on %s", ErrCycle, dependsOnID, componentID)
+	}
+
+	return nil
+}
+
+// Dependencies returns the components, the component directly depends on.
+func (g *Graph) Dependencies(componentID DbDef.ID) []DbDef.ID {
+	return slices.Clone(g.dependencies[componentID])
+}
+
+// Dependents returns the components directly depending on the component.
+func (g *Graph) Dependents(componentID DbDef.ID) []DbDef.ID {
+	return slices.Clone(g.dependents[componentID])
+}
+
+// AllDependencies returns the components, the component depends on directly or indirectly, nearest first.
+func (g *Graph) AllDependencies(componentID DbDef.ID) []DbDef.ID {
+	return walk(g.dependencies, componentID)
+}
+
+// AllDependents returns the components depending on the component directly or indirectly, nearest first.
+func (g *Graph) AllDependents(componentID DbDef.ID) []DbDef.ID {
+	return walk(g.dependents, componentID)
+}
+
+// IndirectImpact is an impact on a dependency, propagated to the components depending on it.
+type IndirectImpact struct {
+	Impact DbDef.Impact
+	// Origin is the dependency directly affected by the impact.
+	Origin DbDef.ID
+}
+
+// IndirectImpacts returns the impacts on the direct and indirect dependencies of the component, nearest first.
+// The impacts are looked up by the ID of the affected component.
+func (g *Graph) IndirectImpacts(componentID DbDef.ID, impacts map[DbDef.ID][]DbDef.Impact) []IndirectImpact {
+	indirect := []IndirectImpact{}
+
+	for _, dependencyID := range g.AllDependencies(componentID) {
+		for _, impact := range impacts[dependencyID] {
+			indirect = append(indirect, IndirectImpact{Impact: impact, Origin: dependencyID})
+		}
+	}
+
+	return indirect
+}
+
+// walk visits the edges breadth first from the start, each component once and the start never.
+func walk(edges map[DbDef.ID][]DbDef.ID, start DbDef.ID) []DbDef.ID {
+	visited := map[DbDef.ID]bool{start: true}
+	reached := []DbDef.ID{}
+	queue := []DbDef.ID{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, next := range edges[current] {
+			if visited[next] {
+				continue
+			}
+
+			visited[next] = true
+			reached = append(reached, next)
+			queue = append(queue, next)
+		}
+	}
+
+	return reached
+}
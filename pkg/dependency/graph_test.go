@@ -0,0 +1,83 @@
+package dependency_test
+
+import (
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/dependency"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Graph", func() {
+	var (
+		dbaas   = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+		storage = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+		network = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+		backup  = uuid.MustParse("00000000-0000-0000-0000-000000000004")
+
+		graph *dependency.Graph
+	)
+
+	BeforeEach(func() {
+		// dbaas depends on storage and network, storage depends on network, backup depends on storage.
+		graph = dependency.NewGraph([]*db.ComponentDependency{
+			{ComponentID: &dbaas, DependsOnID: &storage},
+			{ComponentID: &dbaas, DependsOnID: &network},
+			{ComponentID: &storage, DependsOnID: &network},
+			{ComponentID: &backup, DependsOnID: &storage},
+		})
+	})
+
+	It("should resolve direct and indirect dependencies nearest first", func() {
+		// Assert
+		Ω(graph.Dependencies(backup)).Should(Equal([]uuid.UUID{storage}))
+		Ω(graph.AllDependencies(backup)).Should(Equal([]uuid.UUID{storage, network}))
+		Ω(graph.AllDependencies(dbaas)).Should(Equal([]uuid.UUID{storage, network}))
+		Ω(graph.AllDependencies(network)).Should(BeEmpty())
+	})
+
+	It("should resolve direct and indirect dependents", func() {
+		// Assert
+		Ω(graph.Dependents(network)).Should(ConsistOf(dbaas, storage))
+		Ω(graph.AllDependents(network)).Should(ConsistOf(dbaas, storage, backup))
+		Ω(graph.AllDependents(dbaas)).Should(BeEmpty())
+	})
+
+	DescribeTable("Check",
+		func(componentID, dependsOnID uuid.UUID, expectCycle bool) {
+			// Act
+			err := graph.Check(componentID, dependsOnID)
+
+			// Assert
+			if expectCycle {
+				Ω(err).Should(MatchError(dependency.ErrCycle))
+			} else {
+				Ω(err).ShouldNot(HaveOccurred())
+			}
+		},
+		Entry("with new dependency", backup, network, false),
+		Entry("with existing dependency", dbaas, storage, false),
+		Entry("with self dependency", network, network, true),
+		Entry("with reversed dependency", storage, dbaas, true),
+		Entry("with indirectly reversed dependency", network, backup, true),
+	)
+
+	It("should propagate impacts of all dependencies", func() {
+		// Arrange
+		incidentID := uuid.New()
+		impacts := map[db.ID][]db.Impact{
+			network: {{IncidentID: &incidentID, ComponentID: &network, Severity: test.Ptr(100)}},
+			dbaas:   {{IncidentID: &incidentID, ComponentID: &dbaas, Severity: test.Ptr(50)}},
+		}
+
+		// Act
+		indirect := graph.IndirectImpacts(backup, impacts)
+
+		// Assert
+		Ω(indirect).Should(HaveLen(1))
+		Ω(indirect[0].Origin).Should(Equal(network))
+		Ω(indirect[0].Impact.IncidentID).Should(Equal(&incidentID))
+		Ω(graph.IndirectImpacts(network, impacts)).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,78 @@
+// Package events fans out notifications about published events to the streams of all API instances.
+package events
+
+import "sync"
+
+// Broker wakes up subscribers after new events were published.
+// It carries no events itself, subscribers read all events after the last one they have seen from the database.
+type Broker struct {
+	mutex       sync.Mutex
+	subscribers map[chan struct{}]struct{}
+	closed      bool
+}
+
+// NewBroker creates a new broker without subscribers.
+func NewBroker() *Broker {
+	return &Broker{
+		mutex:       sync.Mutex{},
+		subscribers: make(map[chan struct{}]struct{}),
+		closed:      false,
+	}
+}
+
+// Subscribe registers a new subscriber.
+// The returned channel is signaled after new events were published and closed with the broker.
+// Signals are coalesced, one signal may stand for many events.
+// The returned function removes the subscriber and must be called when done.
+func (b *Broker) Subscribe() (<-chan struct{}, func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	notifications := make(chan struct{}, 1)
+
+	if b.closed {
+		close(notifications)
+
+		return notifications, func() {}
+	}
+
+	b.subscribers[notifications] = struct{}{}
+
+	return notifications, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		delete(b.subscribers, notifications)
+	}
+}
+
+// Notify signals all subscribers without blocking.
+func (b *Broker) Notify() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for notifications := range b.subscribers {
+		select {
+		case notifications <- struct{}{}:
+		default:
+			// a signal is already pending
+		}
+	}
+}
+
+// Close closes the channels of all subscribers and rejects new ones.
+func (b *Broker) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.closed = true
+
+	for notifications := range b.subscribers {
+		close(notifications)
+		delete(b.subscribers, notifications)
+	}
+}
@@ -0,0 +1,83 @@
+package events_test
+
+import (
+	"github.com/SovereignCloudStack/status-page-api/pkg/events"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Broker", func() {
+	var broker *events.Broker
+
+	BeforeEach(func() {
+		broker = events.NewBroker()
+	})
+
+	Describe("Notify", func() {
+		Context("with subscribers", func() {
+			It("should signal every subscriber once per pending signal", func() {
+				// Arrange
+				first, unsubscribeFirst := broker.Subscribe()
+				defer unsubscribeFirst()
+
+				second, unsubscribeSecond := broker.Subscribe()
+				defer unsubscribeSecond()
+
+				// Act
+				broker.Notify()
+				broker.Notify()
+
+				// Assert
+				Ω(first).Should(Receive())
+				Ω(first).ShouldNot(Receive())
+				Ω(second).Should(Receive())
+				Ω(second).ShouldNot(Receive())
+			})
+		})
+
+		Context("with removed subscriber", func() {
+			It("should not signal the subscriber", func() {
+				// Arrange
+				notifications, unsubscribe := broker.Subscribe()
+				unsubscribe()
+
+				// Act
+				broker.Notify()
+
+				// Assert
+				Ω(notifications).ShouldNot(Receive())
+			})
+		})
+	})
+
+	Describe("Close", func() {
+		Context("with subscribers", func() {
+			It("should close the channels of all subscribers", func() {
+				// Arrange
+				notifications, unsubscribe := broker.Subscribe()
+				defer unsubscribe()
+
+				// Act
+				broker.Close()
+				broker.Close()
+
+				// Assert
+				Ω(notifications).Should(BeClosed())
+			})
+		})
+
+		Context("with later subscriber", func() {
+			It("should return a closed channel", func() {
+				// Arrange
+				broker.Close()
+
+				// Act
+				notifications, unsubscribe := broker.Subscribe()
+				defer unsubscribe()
+
+				// Assert
+				Ω(notifications).Should(BeClosed())
+			})
+		})
+	})
+})
@@ -0,0 +1,161 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// Channel is the PostgreSQL notification channel, notified with the ID of each published event.
+	Channel = "status_page_events"
+	// SequenceLock is the key of the transaction level advisory lock held while publishing an event.
+	// It serializes publishers, so event IDs become visible in increasing order and readers never skip an event.
+	// As it is held until commit, transactions publishing events commit one at a time across all instances.
+	SequenceLock = 0x5354415455530001
+
+	// reconnectDelay is the wait before listening again after the connection was lost.
+	reconnectDelay = 5 * time.Second
+)
+
+// Listener listens for notifications on [Channel] and forwards them to a [Broker].
+// Every API instance runs its own listener, so events published by any instance reach the subscribers of all.
+// Without notifications, like on SQLite, the listener polls instead, see [NewPoller].
+type Listener struct {
+	connectionString string
+	pollInterval     time.Duration
+	broker           *Broker
+	logger           *zerolog.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewListener creates a new listener with its own connection to the database.
+func NewListener(connectionString string, broker *Broker, logger *zerolog.Logger) *Listener {
+	return &Listener{
+		connectionString: connectionString,
+		pollInterval:     0,
+		broker:           broker,
+		logger:           logger,
+		stop:             make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+}
+
+// NewPoller creates a new listener, which signals the broker every interval instead of listening for notifications.
+// Subscribers find new events when reading after the last one they have seen.
+func NewPoller(broker *Broker, interval time.Duration, logger *zerolog.Logger) *Listener {
+	return &Listener{
+		connectionString: "",
+		pollInterval:     interval,
+		broker:           broker,
+		logger:           logger,
+		stop:             make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+}
+
+// Start listens until the listener is shut down, reconnecting after errors.
+func (l *Listener) Start() error {
+	defer close(l.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-l.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if l.pollInterval > 0 {
+		l.logger.Log().Dur("interval", l.pollInterval).Msg("event poller started")
+
+		l.poll(ctx)
+
+		return nil
+	}
+
+	l.logger.Log().Str("channel", Channel).Msg("event listener started")
+
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		l.logger.Warn().Err(err).Dur("delay", reconnectDelay).Msg("error listening for events, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// Shutdown stops listening and closes the broker, which ends all subscriptions.
+func (l *Listener) Shutdown(ctx context.Context) error {
+	close(l.stop)
+	l.broker.Close()
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error shutting down event listener: %w", ctx.Err())
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.connectionString)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+
+	defer func() {
+		err := conn.Close(context.Background())
+		if err != nil {
+			l.logger.Warn().Err(err).Msg("error closing listener connection")
+		}
+	}()
+
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{Channel}.Sanitize())
+	if err != nil {
+		return fmt.Errorf("error listening on channel: %w", err)
+	}
+
+	// Events may have been published while not listening.
+	l.broker.Notify()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("error waiting for notification: %w", err)
+		}
+
+		l.logger.Trace().Str("event", notification.Payload).Msg("event notification")
+
+		l.broker.Notify()
+	}
+}
+
+func (l *Listener) poll(ctx context.Context) {
+	ticker := time.NewTicker(l.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.broker.Notify()
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package events_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/events"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Poller", func() {
+	It("should signal subscribers every interval until shut down", func() {
+		// Arrange
+		_, logger, _ := test.MustSetupLogging(zerolog.TraceLevel)
+		broker := events.NewBroker()
+		poller := events.NewPoller(broker, 10*time.Millisecond, logger)
+
+		notifications, unsubscribe := broker.Subscribe()
+		defer unsubscribe()
+
+		started := make(chan error, 1)
+
+		// Act
+		go func() {
+			started <- poller.Start()
+		}()
+
+		// Assert
+		Eventually(notifications).Should(Receive())
+
+		Ω(poller.Shutdown(context.Background())).Should(Succeed())
+		Ω(<-started).ShouldNot(HaveOccurred())
+		Eventually(notifications).Should(BeClosed())
+	})
+})
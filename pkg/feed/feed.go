@@ -0,0 +1,191 @@
+// Package feed renders Atom and RSS feeds.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+const (
+	// AtomContentType is the media type of Atom feeds.
+	AtomContentType = "application/atom+xml; charset=utf-8"
+	// RSSContentType is the media type of RSS feeds.
+	RSSContentType = "application/rss+xml; charset=utf-8"
+
+	// atomNamespace is the XML namespace of Atom documents.
+	atomNamespace = "http://www.w3.org/2005/Atom"
+	// rssVersion is the rendered RSS version.
+	rssVersion = "2.0"
+)
+
+// Feed is the format independent description of a feed.
+type Feed struct {
+	// ID identifies the feed, it should be the URL of the feed.
+	ID string
+	// Title names the feed.
+	Title string
+	// Author is the name of the publisher.
+	Author string
+	// Link is the URL of the feed itself.
+	Link string
+	// Updated is the time of the latest change of the feed.
+	Updated time.Time
+	// Entries are the items of the feed, newest first.
+	Entries []Entry
+}
+
+// Entry is a single item of a [Feed].
+type Entry struct {
+	// ID uniquely and permanently identifies the entry, it is no URL.
+	ID string
+	// Title names the entry.
+	Title string
+	// Summary is the plain text content of the entry.
+	Summary string
+	// Link is the URL of the resource described by the entry.
+	Link string
+	// Published is the time of the first publication.
+	Published time.Time
+	// Updated is the time of the latest change.
+	Updated time.Time
+	// Categories tag the entry.
+	Categories []string
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Summary    string         `xml:"summary"`
+	Link       *atomLink      `xml:"link,omitempty"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+}
+
+// Atom renders the feed as Atom 1.0 document.
+func (f *Feed) Atom() ([]byte, error) {
+	feed := atomFeed{
+		XMLName: xml.Name{Space: "", Local: "feed"},
+		Xmlns:   atomNamespace,
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: f.Author},
+		Link:    atomLink{Href: f.Link, Rel: "self"},
+		Entries: make([]atomEntry, len(f.Entries)),
+	}
+
+	for entryIndex, entry := range f.Entries {
+		var link *atomLink
+		if entry.Link != "" {
+			link = &atomLink{Href: entry.Link, Rel: ""}
+		}
+
+		categories := make([]atomCategory, len(entry.Categories))
+		for categoryIndex, category := range entry.Categories {
+			categories[categoryIndex].Term = category
+		}
+
+		feed.Entries[entryIndex] = atomEntry{
+			ID:         entry.ID,
+			Title:      entry.Title,
+			Summary:    entry.Summary,
+			Link:       link,
+			Published:  entry.Published.UTC().Format(time.RFC3339),
+			Updated:    entry.Updated.UTC().Format(time.RFC3339),
+			Categories: categories,
+		}
+	}
+
+	return marshal(feed)
+}
+
+// RSS renders the feed as RSS 2.0 document.
+func (f *Feed) RSS() ([]byte, error) {
+	feed := rssFeed{
+		XMLName: xml.Name{Space: "", Local: "rss"},
+		Version: rssVersion,
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Title,
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, len(f.Entries)),
+		},
+	}
+
+	for entryIndex, entry := range f.Entries {
+		feed.Channel.Items[entryIndex] = rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			Description: entry.Summary,
+			GUID:        rssGUID{Value: entry.ID, IsPermaLink: false},
+			PubDate:     entry.Updated.UTC().Format(time.RFC1123Z),
+			Categories:  entry.Categories,
+		}
+	}
+
+	return marshal(feed)
+}
+
+func marshal(document interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding feed: %w", err)
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
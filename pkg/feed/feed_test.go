@@ -0,0 +1,70 @@
+package feed_test
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/feed"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Feed", func() {
+	var (
+		updated = time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+		testFeed = feed.Feed{
+			ID:      "https://status.example.com/feeds/incidents.atom",
+			Title:   "Incidents",
+			Author:  "Status Page",
+			Link:    "https://status.example.com/feeds/incidents.atom",
+			Updated: updated,
+			Entries: []feed.Entry{
+				{
+					ID:         "urn:uuid:0d8fdc4f-37a4-4b3c-9f5e-6a4c0a6f1a2e",
+					Title:      "Disk impact: Investigating",
+					Summary:    "Disks are <slow> & failing.",
+					Link:       "https://status.example.com/incidents/0d8fdc4f-37a4-4b3c-9f5e-6a4c0a6f1a2e",
+					Published:  updated.Add(-time.Hour),
+					Updated:    updated,
+					Categories: []string{"maintenance"},
+				},
+			},
+		}
+	)
+
+	Describe("Atom", func() {
+		It("should render a well formed Atom document", func() {
+			// Act
+			document, err := testFeed.Atom()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(xml.Unmarshal(document, new(interface{}))).Should(Succeed())
+			Ω(string(document)).Should(HavePrefix(xml.Header))
+			Ω(string(document)).Should(ContainSubstring(`<feed xmlns="http://www.w3.org/2005/Atom">`))
+			Ω(string(document)).Should(ContainSubstring(`<updated>2024-03-01T12:30:00Z</updated>`))
+			Ω(string(document)).Should(ContainSubstring(`<id>urn:uuid:0d8fdc4f-37a4-4b3c-9f5e-6a4c0a6f1a2e</id>`))
+			Ω(string(document)).Should(ContainSubstring(`<published>2024-03-01T11:30:00Z</published>`))
+			Ω(string(document)).Should(ContainSubstring(`<summary>Disks are &lt;slow&gt; &amp; failing.</summary>`))
+			Ω(string(document)).Should(ContainSubstring(`<category term="maintenance"></category>`))
+		})
+	})
+
+	Describe("RSS", func() {
+		It("should render a well formed RSS document", func() {
+			// Act
+			document, err := testFeed.RSS()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(xml.Unmarshal(document, new(interface{}))).Should(Succeed())
+			Ω(string(document)).Should(ContainSubstring(`<rss version="2.0">`))
+			Ω(string(document)).Should(ContainSubstring(`<lastBuildDate>Fri, 01 Mar 2024 12:30:00 +0000</lastBuildDate>`))
+			Ω(string(document)).Should(ContainSubstring(
+				`<guid isPermaLink="false">urn:uuid:0d8fdc4f-37a4-4b3c-9f5e-6a4c0a6f1a2e</guid>`,
+			))
+			Ω(string(document)).Should(ContainSubstring(`<category>maintenance</category>`))
+		})
+	})
+})
@@ -0,0 +1,126 @@
+// Package mail renders and sends emails.
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// messageIDLength is the number of random bytes of a generated message ID.
+const messageIDLength = 16
+
+// Message is an email with a plain text and a HTML alternative.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+	// UnsubscribeURL is announced in the `List-Unsubscribe` header, if set.
+	UnsubscribeURL string
+}
+
+// Bytes renders the message in the internet message format with CRLF line endings.
+func (m *Message) Bytes(now time.Time) ([]byte, error) {
+	var body bytes.Buffer
+
+	parts := multipart.NewWriter(&body)
+
+	for _, alternative := range []struct {
+		contentType string
+		content     string
+	}{
+		// The preferred alternative comes last.
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {alternative.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error creating part: %w", err)
+		}
+
+		encoder := quotedprintable.NewWriter(part)
+
+		_, err = encoder.Write([]byte(strings.ReplaceAll(alternative.content, "\n", "\r\n")))
+		if err != nil {
+			return nil, fmt.Errorf("error encoding part: %w", err)
+		}
+
+		err = encoder.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error encoding part: %w", err)
+		}
+	}
+
+	err := parts.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error closing parts: %w", err)
+	}
+
+	messageID, err := newMessageID(m.From)
+	if err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+
+	headers := [][2]string{
+		{"From", m.From},
+		{"To", m.To},
+		{"Subject", mime.QEncoding.Encode("utf-8", m.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + parts.Boundary()},
+	}
+
+	if m.UnsubscribeURL != "" {
+		headers = append(headers,
+			[2]string{"List-Unsubscribe", "<" + m.UnsubscribeURL + ">"},
+			[2]string{"List-Unsubscribe-Post", "List-Unsubscribe=One-Click"},
+		)
+	}
+
+	for _, header := range headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", header[0], header[1])
+	}
+
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// newMessageID generates a unique message ID in the domain of the sender.
+func newMessageID(from string) (string, error) {
+	domain := "localhost"
+
+	address, err := mail.ParseAddress(from)
+	if err == nil {
+		_, addressDomain, found := strings.Cut(address.Address, "@")
+		if found {
+			domain = addressDomain
+		}
+	}
+
+	random := make([]byte, messageIDLength)
+
+	_, err = rand.Read(random)
+	if err != nil {
+		return "", fmt.Errorf("error generating message ID: %w", err)
+	}
+
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">", nil
+}
@@ -0,0 +1,88 @@
+package mail_test
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"time"
+
+	mailDef "github.com/SovereignCloudStack/status-page-api/pkg/mail"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Message", func() {
+	Describe("Bytes", func() {
+		It("should render text and HTML alternatives", func() {
+			// Arrange
+			message := mailDef.Message{
+				From:           "Status Page <status@example.com>",
+				To:             "user@example.com",
+				Subject:        "Störung: Storage",
+				Text:           "Storage is degraded.\nSee details.",
+				HTML:           "<p>Storage is degraded.</p>",
+				UnsubscribeURL: "https://status.example.com/subscribers/unsubscribe?token=abc",
+			}
+
+			// Act
+			data, err := message.Bytes(time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC))
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			parsed, err := mail.ReadMessage(bytes.NewReader(data))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(subject).Should(Equal("Störung: Storage"))
+			Ω(parsed.Header.Get("Date")).Should(Equal("Fri, 01 Mar 2024 10:00:00 +0000"))
+			Ω(parsed.Header.Get("Message-ID")).Should(MatchRegexp(`^<[0-9a-f]{32}@example\.com>$`))
+			Ω(parsed.Header.Get("List-Unsubscribe")).Should(Equal("<" + message.UnsubscribeURL + ">"))
+			Ω(parsed.Header.Get("List-Unsubscribe-Post")).Should(Equal("List-Unsubscribe=One-Click"))
+
+			mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(mediaType).Should(Equal("multipart/alternative"))
+
+			parts := multipart.NewReader(parsed.Body, params["boundary"])
+
+			textPart, err := parts.NextPart()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(textPart.Header.Get("Content-Type")).Should(Equal("text/plain; charset=utf-8"))
+
+			text, err := io.ReadAll(textPart)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(text)).Should(Equal("Storage is degraded.\r\nSee details."))
+
+			htmlPart, err := parts.NextPart()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(htmlPart.Header.Get("Content-Type")).Should(Equal("text/html; charset=utf-8"))
+
+			html, err := io.ReadAll(htmlPart)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(html)).Should(Equal("<p>Storage is degraded.</p>"))
+		})
+
+		It("should omit unsubscribe headers without URL", func() {
+			// Arrange
+			message := mailDef.Message{
+				From:           "status@example.com",
+				To:             "user@example.com",
+				Subject:        "Confirm",
+				Text:           "Confirm",
+				HTML:           "<p>Confirm</p>",
+				UnsubscribeURL: "",
+			}
+
+			// Act
+			data, err := message.Bytes(time.Now())
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(data)).ShouldNot(ContainSubstring("List-Unsubscribe"))
+		})
+	})
+})
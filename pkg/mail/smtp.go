@@ -0,0 +1,139 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// Sender sends messages.
+type Sender interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// SMTPConfig holds the settings of the [SMTPSender].
+type SMTPConfig struct {
+	// Address of the SMTP server as `host:port`.
+	Address string
+	// Username for PLAIN authentication, authentication is skipped without.
+	Username string
+	// Password for PLAIN authentication.
+	Password string
+	// Timeout limits the duration of sending a single message.
+	Timeout time.Duration
+}
+
+// SMTPSender sends messages to a SMTP server, using STARTTLS if offered.
+type SMTPSender struct {
+	conf SMTPConfig
+}
+
+// NewSMTPSender creates a new SMTP sender.
+func NewSMTPSender(conf SMTPConfig) *SMTPSender {
+	return &SMTPSender{conf: conf}
+}
+
+// Send delivers the message to the SMTP server with one connection per message.
+func (s *SMTPSender) Send(ctx context.Context, message *Message) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return fmt.Errorf("error parsing sender: %w", err)
+	}
+
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("error parsing recipient: %w", err)
+	}
+
+	data, err := message.Bytes(time.Now())
+	if err != nil {
+		return fmt.Errorf("error rendering message: %w", err)
+	}
+
+	host, _, err := net.SplitHostPort(s.conf.Address)
+	if err != nil {
+		return fmt.Errorf("error parsing server address: %w", err)
+	}
+
+	dialer := net.Dialer{Timeout: s.conf.Timeout} //nolint:exhaustruct
+
+	conn, err := dialer.DialContext(ctx, "tcp", s.conf.Address)
+	if err != nil {
+		return fmt.Errorf("error connecting to server: %w", err)
+	}
+
+	err = conn.SetDeadline(time.Now().Add(s.conf.Timeout))
+	if err != nil {
+		conn.Close()
+
+		return fmt.Errorf("error setting deadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+
+		return fmt.Errorf("error greeting server: %w", err)
+	}
+	defer client.Close()
+
+	err = s.transmit(client, host, from.Address, to.Address, data)
+	if err != nil {
+		return err
+	}
+
+	err = client.Quit()
+	if err != nil {
+		return fmt.Errorf("error closing session: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SMTPSender) transmit(client *smtp.Client, host string, from string, to string, data []byte) error {
+	startTLS, _ := client.Extension("STARTTLS")
+	if startTLS {
+		err := client.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}) //nolint:exhaustruct
+		if err != nil {
+			return fmt.Errorf("error starting TLS: %w", err)
+		}
+	}
+
+	if s.conf.Username != "" {
+		err := client.Auth(smtp.PlainAuth("", s.conf.Username, s.conf.Password, host))
+		if err != nil {
+			return fmt.Errorf("error authenticating: %w", err)
+		}
+	}
+
+	err := client.Mail(from)
+	if err != nil {
+		return fmt.Errorf("error setting sender: %w", err)
+	}
+
+	err = client.Rcpt(to)
+	if err != nil {
+		return fmt.Errorf("error setting recipient: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error starting data: %w", err)
+	}
+
+	_, err = writer.Write(data)
+	if err != nil {
+		return fmt.Errorf("error writing data: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("error finishing data: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package mail_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	mailDef "github.com/SovereignCloudStack/status-page-api/pkg/mail"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SMTPSender", func() {
+	var (
+		server *test.SMTPServer
+		sender *mailDef.SMTPSender
+
+		message = mailDef.Message{
+			From:           "Status Page <status@example.com>",
+			To:             "user@example.com",
+			Subject:        "Storage degraded",
+			Text:           "Storage is degraded.",
+			HTML:           "<p>Storage is degraded.</p>",
+			UnsubscribeURL: "",
+		}
+	)
+
+	BeforeEach(func() {
+		server = test.MustStartSMTPServer()
+		sender = mailDef.NewSMTPSender(mailDef.SMTPConfig{
+			Address:  server.Address,
+			Username: "",
+			Password: "",
+			Timeout:  time.Second,
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Send", func() {
+		It("should deliver the message to the server", func() {
+			// Act
+			err := sender.Send(context.Background(), &message)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			messages := server.Messages()
+			Ω(messages).Should(HaveLen(1))
+			Ω(messages[0].From).Should(Equal("status@example.com"))
+			Ω(messages[0].To).Should(Equal([]string{"user@example.com"}))
+			Ω(messages[0].Data).Should(ContainSubstring("Subject: Storage degraded\n"))
+		})
+
+		It("should fail on rejected recipient", func() {
+			// Arrange
+			server.SetRejecting(true)
+
+			// Act
+			err := sender.Send(context.Background(), &message)
+
+			// Assert
+			Ω(err).Should(HaveOccurred())
+			Ω(server.Messages()).Should(BeEmpty())
+		})
+
+		It("should fail on unreachable server", func() {
+			// Arrange
+			server.Close()
+
+			// Act
+			err := sender.Send(context.Background(), &message)
+
+			// Assert
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
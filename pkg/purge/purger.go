@@ -0,0 +1,122 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/rs/zerolog"
+)
+
+// Config holds the settings of the [Purger].
+type Config struct {
+	// Interval is the time between purges.
+	Interval time.Duration
+	// Retention is the time deleted incidents and components are kept, before they are purged.
+	Retention time.Duration
+	// EventRetention is the time published events are kept, so event streams can resume within it.
+	EventRetention time.Duration
+}
+
+// Purger permanently removes incidents and components, which were deleted longer than the retention ago,
+// and events published longer than the event retention ago.
+// Purges of multiple instances remove the same resources, so they can run concurrently.
+type Purger struct {
+	store  storage.Storage
+	conf   Config
+	logger *zerolog.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates a new purger.
+func New(store storage.Storage, conf Config, logger *zerolog.Logger) *Purger {
+	return &Purger{
+		store:  store,
+		conf:   conf,
+		logger: logger,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start purges periodically until the purger is shut down.
+func (p *Purger) Start() error {
+	defer close(p.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-p.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	p.logger.Log().
+		Dur("interval", p.conf.Interval).
+		Dur("retention", p.conf.Retention).
+		Dur("eventRetention", p.conf.EventRetention).
+		Msg("purger started")
+
+	ticker := time.NewTicker(p.conf.Interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.Purge(ctx, time.Now())
+		if err != nil {
+			p.logger.Error().Err(err).Msg("error purging deleted resources and events")
+		} else if purged > 0 {
+			p.logger.Info().Int("purged", purged).Msg("purged deleted resources and events")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown stops the purger and waits for the running purge to finish.
+func (p *Purger) Shutdown(ctx context.Context) error {
+	close(p.stop)
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error shutting down purger: %w", ctx.Err())
+	}
+}
+
+// Purge removes the resources deleted longer than the retention before now
+// and the events published longer than the event retention before now and returns their number.
+func (p *Purger) Purge(ctx context.Context, now time.Time) (int, error) {
+	var purged int
+
+	err := p.store.Transaction(ctx, func(repo storage.Repository) error {
+		purgedResources, err := repo.PurgeDeleted(now.Add(-p.conf.Retention))
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		purgedEvents, err := repo.PurgeEvents(now.Add(-p.conf.EventRetention))
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		purged = purgedResources + purgedEvents
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error purging: %w", err)
+	}
+
+	return purged, nil
+}
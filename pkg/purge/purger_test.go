@@ -0,0 +1,224 @@
+package purge_test
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	appDB "github.com/SovereignCloudStack/status-page-api/internal/app/db"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/purge"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Purger", func() {
+	var (
+		// sub loggers
+		_, _, purgerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// storage of the purged resources
+		store *storage.Memory
+		repo  storage.Repository
+
+		// actual functions under test
+		purger *purge.Purger
+
+		componentID db.ID
+	)
+
+	BeforeEach(func() {
+		store = storage.NewMemory()
+		repo = store.WithContext(context.Background())
+		purger = purge.New(store, purge.Config{
+			Interval:       time.Hour,
+			Retention:      time.Hour,
+			EventRetention: 24 * time.Hour,
+		}, purgerLogger)
+
+		component := &db.Component{DisplayName: test.Ptr("Network")}
+		Ω(repo.CreateComponent(component)).Should(Succeed())
+		componentID = component.ID
+
+		_, err := repo.DeleteComponent(componentID, "test")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Describe("Purge", func() {
+		It("should keep resources deleted within the retention", func() {
+			// Act
+			purged, err := purger.Purge(context.Background(), time.Now())
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(purged).Should(Equal(0))
+
+			_, err = repo.IncludeDeleted().GetComponent(componentID, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should remove resources deleted before the retention", func() {
+			// Act
+			purged, err := purger.Purge(context.Background(), time.Now().Add(2*time.Hour))
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(purged).Should(Equal(1))
+
+			_, err = repo.IncludeDeleted().GetComponent(componentID, nil)
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+		})
+
+		It("should remove events published before the event retention", func() {
+			// Arrange
+			event, err := db.NewEvent(db.EventComponentChanged, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.PublishEvent(event)).Should(Succeed())
+
+			// Act
+			withinRetention, errWithin := purger.Purge(context.Background(), time.Now().Add(2*time.Hour))
+			afterRetention, errAfter := purger.Purge(context.Background(), time.Now().Add(25*time.Hour))
+
+			// Assert
+			Ω(errWithin).ShouldNot(HaveOccurred())
+			Ω(withinRetention).Should(Equal(1), "only the deleted component is purged")
+			Ω(errAfter).ShouldNot(HaveOccurred())
+			Ω(afterRetention).Should(Equal(1))
+			Ω(store.Events()).Should(BeEmpty())
+		})
+	})
+
+	Describe("Shutdown", func() {
+		It("should stop the purger", func() {
+			// Arrange
+			done := make(chan error, 1)
+
+			go func() {
+				done <- purger.Start()
+			}()
+
+			// Act
+			err := purger.Shutdown(context.Background())
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Eventually(done).Should(Receive(BeNil()))
+		})
+	})
+})
+
+var _ = Describe("Purger with database", func() {
+	var (
+		// sub loggers
+		_, gormLogger, purgerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// database of the purged events
+		gormDB *gorm.DB
+
+		// actual functions under test
+		purger *purge.Purger
+
+		publish = func(age time.Duration, status ...db.WebhookDeliveryStatus) *db.Event {
+			event, err := db.NewEvent(db.EventComponentChanged, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			event.CreatedAt = time.Now().Add(-age)
+			Ω(storage.NewGorm(gormDB).PublishEvent(event)).Should(Succeed())
+
+			for _, deliveryStatus := range status {
+				subscription := &db.WebhookSubscription{
+					TargetURL: test.Ptr("https://example.com/hook"),
+					Events:    &db.WebhookEventTypes{db.EventComponentChanged},
+					Secret:    test.Ptr("secret"),
+				}
+				Ω(gormDB.Create(subscription).Error).Should(Succeed())
+
+				delivery := db.NewWebhookDelivery(event.ID, subscription.ID, time.Now())
+				delivery.Status = deliveryStatus
+				Ω(gormDB.Create(delivery).Error).Should(Succeed())
+			}
+
+			return event
+		}
+	)
+
+	BeforeEach(func() {
+		database, err := appDB.New(appDB.SQLitePrefix+filepath.Join(GinkgoT().TempDir(), "status.db"), gormLogger)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = database.MigrateUp()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		gormDB = database.GetDBCon()
+		purger = purge.New(storage.NewGorm(gormDB), purge.Config{
+			Interval:       time.Hour,
+			Retention:      time.Hour,
+			EventRetention: 24 * time.Hour,
+		}, purgerLogger)
+	})
+
+	It("should keep recent events and events with pending or dead deliveries", func() {
+		// Arrange
+		publish(48*time.Hour, db.WebhookDeliveryDelivered)
+		recent := publish(time.Hour)
+		pending := publish(48*time.Hour, db.WebhookDeliveryDelivered, db.WebhookDeliveryPending)
+		dead := publish(48*time.Hour, db.WebhookDeliveryDead)
+
+		// Act
+		purged, err := purger.Purge(context.Background(), time.Now())
+
+		// Assert
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(purged).Should(Equal(1))
+
+		var eventIDs []uint64
+		Ω(gormDB.Model(&db.Event{}).Order("id").Pluck("id", &eventIDs).Error).Should(Succeed())
+		Ω(eventIDs).Should(Equal([]uint64{recent.ID, pending.ID, dead.ID}))
+
+		var deliveries int64
+		Ω(gormDB.Model(&db.WebhookDelivery{}).Count(&deliveries).Error).Should(Succeed())
+		Ω(deliveries).Should(BeEquivalentTo(3))
+	})
+
+	It("should keep dependencies and memberships of deleted components, until they are purged", func() {
+		// Arrange
+		repo := storage.NewGorm(gormDB).WithContext(context.Background())
+
+		network := &db.Component{DisplayName: test.Ptr("Network")}
+		Ω(repo.CreateComponent(network)).Should(Succeed())
+		router := &db.Component{DisplayName: test.Ptr("Router")}
+		Ω(repo.CreateComponent(router)).Should(Succeed())
+		Ω(repo.CreateComponentDependency(&db.ComponentDependency{ComponentID: &network.ID, DependsOnID: &router.ID})).
+			Should(Succeed())
+
+		group := &db.ComponentGroup{
+			DisplayName: test.Ptr("Infrastructure"),
+			Order:       test.Ptr(0),
+			Members:     &[]db.ComponentGroupMember{{ComponentID: &router.ID}},
+		}
+		Ω(repo.CreateComponentGroup(group)).Should(Succeed())
+
+		_, err := repo.DeleteComponent(router.ID, "admin")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(repo.ListComponentDependencies()).Should(BeEmpty())
+		Ω(repo.IncludeDeleted().ListComponentDependencies()).Should(HaveLen(1))
+		Ω(repo.GetComponentGroup(group.ID)).Should(HaveField("Members", HaveValue(BeEmpty())))
+
+		// Act
+		purged, err := purger.Purge(context.Background(), time.Now().Add(2*time.Hour))
+
+		// Assert
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(purged).Should(Equal(1))
+
+		var dependencies, members int64
+		Ω(gormDB.Model(&db.ComponentDependency{}).Count(&dependencies).Error).Should(Succeed())
+		Ω(gormDB.Model(&db.ComponentGroupMember{}).Count(&members).Error).Should(Succeed())
+		Ω(dependencies).Should(BeZero())
+		Ω(members).Should(BeZero())
+	})
+})
@@ -0,0 +1,494 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/alertmanager"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Actions taken for a single alert, see [api.AlertResult].
+const (
+	alertActionCreated   = "created"
+	alertActionUpdated   = "updated"
+	alertActionUnchanged = "unchanged"
+	alertActionResolved  = "resolved"
+	alertActionSkipped   = "skipped"
+)
+
+// ReceiveAlerts handles notifications of Alertmanager.
+// Firing alerts open an incident, changes of the affected components or severity append an incident update,
+// and resolved alerts end the incident. Alerts are deduplicated by their fingerprint.
+// All alerts of a notification are handled in one transaction, so Alertmanager retries the whole notification on errors.
+// Incidents are changed through the [storage.Repository] like by the incident handlers, so their version changes.
+func (i *Implementation) ReceiveAlerts(ctx echo.Context) error {
+	var request api.AlertmanagerRequest
+
+	logger := i.logger.With().Str("handler", "ReceiveAlerts").Logger()
+
+	if i.alerts == nil {
+		logger.Warn().Msg("alertmanager receiver is disabled")
+
+		return echo.ErrNotFound
+	}
+
+	err := ctx.Bind(&request)
+	if err != nil {
+		logger.Error().Err(err).Msg("error binding request")
+
+		return echo.ErrInternalServerError
+	}
+
+	if len(request.Alerts) == 0 {
+		logger.Warn().Msg("empty request")
+
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
+	}
+
+	logger.Debug().Str("groupKey", request.GroupKey).Int("alerts", len(request.Alerts)).Send()
+
+	results := make([]api.AlertResult, len(request.Alerts))
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
+		var components []*DbDef.Component
+
+		transactionErr := dbTx.Find(&components).Error
+		if transactionErr != nil {
+			return fmt.Errorf("error loading components: %w", transactionErr)
+		}
+
+		for alertIndex := range request.Alerts {
+			alert := &request.Alerts[alertIndex]
+
+			results[alertIndex], transactionErr = i.receiveAlert(ctx, dbTx, alert, components)
+			if transactionErr != nil {
+				return fmt.Errorf("error handling alert %s: %w", alert.Fingerprint, transactionErr)
+			}
+
+			logger.Debug().Interface("result", results[alertIndex]).Send()
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("error in transaction")
+
+		return echo.ErrInternalServerError
+	}
+
+	return ctx.JSON(http.StatusOK, api.AlertmanagerResponse{ //nolint:wrapcheck
+		Data: results,
+	})
+}
+
+// receiveAlert handles a single alert in the transaction of the notification.
+func (i *Implementation) receiveAlert(
+	ctx echo.Context,
+	dbTx *gorm.DB,
+	alert *api.Alert,
+	components []*DbDef.Component,
+) (api.AlertResult, error) {
+	result := api.AlertResult{ //nolint:exhaustruct
+		Fingerprint: alert.Fingerprint,
+		Action:      alertActionSkipped,
+	}
+
+	if alert.Fingerprint == "" {
+		result.Reason = "missing fingerprint"
+
+		return result, nil
+	}
+
+	var alertIncidents []*DbDef.AlertIncident
+
+	// Alerts of deleted incidents open new incidents, when firing again.
+	err := dbTx.
+		Where("fingerprint = ?", alert.Fingerprint).
+		Where("resolved_at IS NULL").
+		Where("incident_id IN (?)", dbTx.Model(&DbDef.Incident{}).Select("id")). //nolint:exhaustruct
+		Limit(1).
+		Find(&alertIncidents).
+		Error
+	if err != nil {
+		return result, fmt.Errorf("error loading incident of alert: %w", err)
+	}
+
+	var alertIncident *DbDef.AlertIncident
+	if len(alertIncidents) != 0 {
+		alertIncident = alertIncidents[0]
+		result.IncidentID = &alertIncident.IncidentID
+	}
+
+	switch alert.Status {
+	case api.AlertFiring:
+		return i.fireAlert(ctx, dbTx, alert, alertIncident, components, result)
+	case api.AlertResolved:
+		if alertIncident == nil {
+			result.Reason = "no open incident"
+
+			return result, nil
+		}
+
+		return resolveAlert(ctx, dbTx, alert, alertIncident, result)
+	default:
+		result.Reason = "unknown status"
+
+		return result, nil
+	}
+}
+
+// fireAlert opens an incident for a new alert or updates the impacts of the incident of a known alert.
+func (i *Implementation) fireAlert(
+	ctx echo.Context,
+	dbTx *gorm.DB,
+	alert *api.Alert,
+	alertIncident *DbDef.AlertIncident,
+	components []*DbDef.Component,
+	result api.AlertResult,
+) (api.AlertResult, error) {
+	affected := i.alerts.Components(alert, components)
+	if len(affected) == 0 {
+		result.Reason = "no matching component"
+
+		return result, nil
+	}
+
+	impactTypeName := i.alerts.ImpactType(alert)
+
+	var impactTypes []*DbDef.ImpactType
+
+	err := dbTx.Where("display_name = ?", impactTypeName).Limit(1).Find(&impactTypes).Error
+	if err != nil {
+		return result, fmt.Errorf("error loading impact type: %w", err)
+	}
+
+	if len(impactTypes) == 0 {
+		result.Reason = "unknown impact type " + impactTypeName
+
+		return result, nil
+	}
+
+	severity := i.alerts.Severity(alert)
+	impacts := make([]DbDef.Impact, len(affected))
+
+	for componentIndex, component := range affected {
+		impacts[componentIndex] = DbDef.Impact{ //nolint:exhaustruct
+			ComponentID:  &component.ID,
+			ImpactTypeID: &impactTypes[0].ID,
+			Severity:     &severity,
+		}
+	}
+
+	// The references are checked like the impacts of incidents created through the API, so alerts don't bypass them.
+	invalidErrs, err := i.invalidReferences(storage.NewGorm(dbTx), &DbDef.Incident{Affects: &impacts}) //nolint:exhaustruct
+	if err != nil {
+		return result, err
+	}
+
+	if len(invalidErrs) != 0 {
+		i.logger.Warn().Str("fingerprint", alert.Fingerprint).Errs("errors", invalidErrs).Msg("skipping alert")
+
+		result.Reason = errors.Unwrap(invalidErrs[0]).Error()
+
+		return result, nil
+	}
+
+	if alertIncident == nil {
+		incident, err := openAlertIncident(ctx, dbTx, alert, impacts)
+		if err != nil {
+			return result, err
+		}
+
+		result.Action = alertActionCreated
+		result.IncidentID = &incident.ID
+
+		return result, nil
+	}
+
+	changed, err := updateAlertImpacts(ctx, dbTx, alertIncident.IncidentID, impacts,
+		alertmanager.ChangeDescription(affected, severity))
+	if err != nil {
+		return result, err
+	}
+
+	result.Action = alertActionUnchanged
+	if changed {
+		result.Action = alertActionUpdated
+	}
+
+	return result, nil
+}
+
+// openAlertIncident creates the incident of an alert in the first phase of the current generation.
+func openAlertIncident(
+	ctx echo.Context,
+	dbTx *gorm.DB,
+	alert *api.Alert,
+	impacts []DbDef.Impact,
+) (*DbDef.Incident, error) {
+	repo := storage.NewGorm(dbTx)
+	now := time.Now()
+
+	beganAt := alert.StartsAt
+	if beganAt.IsZero() {
+		beganAt = now
+	}
+
+	generation, err := repo.CurrentPhaseGeneration()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current phase generation: %w", err)
+	}
+
+	incident := &DbDef.Incident{ //nolint:exhaustruct
+		DisplayName: stringPtr(alertmanager.Title(alert)),
+		Description: stringPtr(alertmanager.Description(alert)),
+		BeganAt:     &beganAt,
+		Affects:     &impacts,
+	}
+
+	if generation > 0 {
+		firstPhase := 0
+
+		incident.PhaseGeneration = &generation
+		incident.PhaseOrder = &firstPhase
+	}
+
+	err = repo.CreateIncident(incident)
+	if err != nil {
+		return nil, fmt.Errorf("error creating incident: %w", err)
+	}
+
+	err = dbTx.Create(DbDef.NewAlertIncident(alert.Fingerprint, incident.ID, now)).Error
+	if err != nil {
+		return nil, fmt.Errorf("error linking alert to incident: %w", err)
+	}
+
+	err = recordChange(ctx, repo, change{
+		operation:  DbDef.AuditOperationCreate,
+		targetType: DbDef.AuditTargetIncident,
+		targetID:   incident.ID.String(),
+		before:     nil,
+		after:      incident,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = publishEvent(repo, DbDef.EventIncidentCreated, incident)
+	if err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// updateAlertImpacts replaces the impacts of the incident of an alert, if they changed, and appends an update.
+func updateAlertImpacts(
+	ctx echo.Context,
+	dbTx *gorm.DB,
+	incidentID DbDef.ID,
+	impacts []DbDef.Impact,
+	description string,
+) (bool, error) {
+	var currentIncident DbDef.Incident
+
+	err := dbTx.Preload("Affects").Where("id = ?", incidentID).First(&currentIncident).Error
+	if err != nil {
+		return false, fmt.Errorf("error loading incident: %w", err)
+	}
+
+	if currentIncident.Affects != nil && sameImpacts(*currentIncident.Affects, impacts) {
+		return false, nil
+	}
+
+	repo := storage.NewGorm(dbTx)
+
+	incident := &DbDef.Incident{Affects: &impacts} //nolint:exhaustruct
+	incident.ID = incidentID
+
+	dbIncident, updatedIncident, err := repo.UpdateIncident(incident)
+	if err != nil {
+		return false, fmt.Errorf("error updating impacts: %w", err)
+	}
+
+	err = recordChange(ctx, repo, change{
+		operation:  DbDef.AuditOperationUpdate,
+		targetType: DbDef.AuditTargetIncident,
+		targetID:   incidentID.String(),
+		before:     dbIncident,
+		after:      updatedIncident,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	err = publishEvent(repo, DbDef.EventIncidentUpdated, updatedIncident)
+	if err != nil {
+		return false, err
+	}
+
+	err = appendIncidentUpdate(ctx, repo, incidentID, "Alert changed", description)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// resolveAlert ends the incident of a resolved alert, unless it already ended, and appends an update.
+func resolveAlert(
+	ctx echo.Context,
+	dbTx *gorm.DB,
+	alert *api.Alert,
+	alertIncident *DbDef.AlertIncident,
+	result api.AlertResult,
+) (api.AlertResult, error) {
+	repo := storage.NewGorm(dbTx)
+	now := time.Now()
+
+	err := dbTx.
+		Model(&DbDef.AlertIncident{}). //nolint:exhaustruct
+		Where("id = ?", alertIncident.ID).
+		Update("resolved_at", now).
+		Error
+	if err != nil {
+		return result, fmt.Errorf("error resolving alert: %w", err)
+	}
+
+	var currentIncident DbDef.Incident
+
+	err = dbTx.Preload("Affects").Where("id = ?", alertIncident.IncidentID).First(&currentIncident).Error
+	if err != nil {
+		return result, fmt.Errorf("error loading incident: %w", err)
+	}
+
+	if currentIncident.EndedAt != nil {
+		result.Reason = "incident already ended"
+
+		return result, nil
+	}
+
+	endedAt := alert.EndsAt
+	if endedAt.IsZero() || endedAt.After(now) ||
+		(currentIncident.BeganAt != nil && endedAt.Before(*currentIncident.BeganAt)) {
+		endedAt = now
+	}
+
+	incident := &DbDef.Incident{EndedAt: &endedAt} //nolint:exhaustruct
+	incident.ID = alertIncident.IncidentID
+
+	dbIncident, updatedIncident, err := repo.UpdateIncident(incident)
+	if err != nil {
+		return result, fmt.Errorf("error ending incident: %w", err)
+	}
+
+	err = recordChange(ctx, repo, change{
+		operation:  DbDef.AuditOperationUpdate,
+		targetType: DbDef.AuditTargetIncident,
+		targetID:   alertIncident.IncidentID.String(),
+		before:     dbIncident,
+		after:      updatedIncident,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = publishEvent(repo, DbDef.EventIncidentResolved, updatedIncident)
+	if err != nil {
+		return result, err
+	}
+
+	err = appendIncidentUpdate(ctx, repo, alertIncident.IncidentID, "Resolved", "The alert is resolved.")
+	if err != nil {
+		return result, err
+	}
+
+	result.Action = alertActionResolved
+
+	return result, nil
+}
+
+// appendIncidentUpdate creates the next update of an incident.
+func appendIncidentUpdate(
+	ctx echo.Context,
+	repo storage.Repository,
+	incidentID DbDef.ID,
+	displayName string,
+	description string,
+) error {
+	order, err := repo.HighestIncidentUpdateOrder(incidentID)
+	if err != nil {
+		return fmt.Errorf("error getting current highest order of incident: %w", err)
+	}
+
+	order++
+	now := time.Now()
+
+	incidentUpdate := DbDef.IncidentUpdate{
+		IncidentID:  &incidentID,
+		Order:       &order,
+		DisplayName: &displayName,
+		Description: &description,
+		CreatedAt:   &now,
+		Version:     nil,
+	}
+
+	err = repo.CreateIncidentUpdate(&incidentUpdate)
+	if err != nil {
+		return fmt.Errorf("error creating incident update: %w", err)
+	}
+
+	err = recordChange(ctx, repo, change{
+		operation:  DbDef.AuditOperationCreate,
+		targetType: DbDef.AuditTargetIncidentUpdate,
+		targetID:   incidentUpdateTargetID(incidentID, order),
+		before:     nil,
+		after:      incidentUpdate,
+	})
+	if err != nil {
+		return err
+	}
+
+	return publishEvent(repo, DbDef.EventIncidentUpdateCreated, incidentUpdate)
+}
+
+// sameImpacts reports, if both lists contain the same components, impact types and severities.
+func sameImpacts(current []DbDef.Impact, expected []DbDef.Impact) bool {
+	if len(current) != len(expected) {
+		return false
+	}
+
+	for _, impact := range expected {
+		if !slices.ContainsFunc(current, func(currentImpact DbDef.Impact) bool {
+			return equalID(currentImpact.ComponentID, impact.ComponentID) &&
+				equalID(currentImpact.ImpactTypeID, impact.ImpactTypeID) &&
+				currentImpact.Severity != nil && impact.Severity != nil &&
+				*currentImpact.Severity == *impact.Severity
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalID(a *DbDef.ID, b *DbDef.ID) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,445 @@
+package server_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	appDB "github.com/SovereignCloudStack/status-page-api/internal/app/db"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/alertmanager"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = DescribeTableSubtree("Alertmanager", func(dialect test.Dialect) {
+	const (
+		alertmanagerEndpoint = "/alertmanager"
+		fingerprint          = "5b2e2f6d9c1a7e40"
+		componentID          = "4a7c2d8e-1f3b-4c6a-9e5d-7b8a9c0d1e2f"
+		impactTypeID         = "6d0f8b2a-3c4e-4a5b-8c7d-9e0f1a2b3c4d"
+	)
+
+	var (
+		// sub loggers
+		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// mocked sql rows
+		componentRows     *sqlmock.Rows
+		alertIncidentRows *sqlmock.Rows
+		impactTypeRows    *sqlmock.Rows
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		// expected SQL
+		expectedComponentsQuery = regexp.
+					QuoteMeta(`SELECT * FROM "components"`)
+		expectedAlertIncidentQuery = regexp.
+						QuoteMeta(`SELECT * FROM "alert_incidents" WHERE fingerprint = $1 AND resolved_at IS NULL AND incident_id IN (SELECT "id" FROM "incidents" WHERE "incidents"."deleted_at" IS NULL) LIMIT $2`) //nolint:lll
+		expectedImpactTypeQuery = regexp.
+					QuoteMeta(`SELECT * FROM "impact_types" WHERE display_name = $1 LIMIT $2`)
+		expectedComponentQuery = regexp.
+					QuoteMeta(`SELECT * FROM "components" WHERE id = $1 AND "components"."deleted_at" IS NULL ORDER BY "components"."id" LIMIT $2`) //nolint:lll
+		expectedActiveImpactQuery = `SELECT .+
+		FROM "impacts"
+		LEFT JOIN "incidents" "Incident" ON "impacts"\."incident_id" = "Incident"\."id" AND "Incident"\."deleted_at" IS NULL
+		WHERE impacts\.incident_id IN \(SELECT id FROM incidents WHERE deleted_at IS NULL\)
+		AND ended_at IS NULL
+		AND "impacts"\."component_id" = \$1`
+		expectedImpactTypeByIDQuery = regexp.
+						QuoteMeta(`SELECT * FROM "impact_types" WHERE id = $1 ORDER BY "impact_types"."id" LIMIT $2`)
+		expectedPhaseGenerationQuery = regexp.
+						QuoteMeta(`SELECT COALESCE(MAX(generation), 0) FROM "phases"`)
+		expectedUsableComponentsCount = regexp.
+						QuoteMeta(`SELECT count(*) FROM "components" WHERE id IN ($1) AND deleted_at IS NULL`)
+		expectedIncidentInsert = regexp.
+					QuoteMeta(`INSERT INTO "incidents"`)
+		expectedImpactInsert = regexp.
+					QuoteMeta(`INSERT INTO "impacts"`)
+		expectedRevisionImpactInsert = regexp.
+						QuoteMeta(`INSERT INTO "incident_revision_impacts"`)
+		expectedAlertIncidentInsert = regexp.
+						QuoteMeta(`INSERT INTO "alert_incidents" ("fingerprint","incident_id","created_at","resolved_at") VALUES ($1,$2,$3,$4) RETURNING "id"`) //nolint:lll
+		expectedIncidentQuery = regexp.
+					QuoteMeta(`SELECT * FROM "incidents" WHERE id = $1 AND "incidents"."deleted_at" IS NULL ORDER BY "incidents"."id" LIMIT $2`)
+		expectedImpactQuery = regexp.
+					QuoteMeta(`SELECT * FROM "impacts" WHERE "impacts"."incident_id" = $1`)
+
+		mapper = alertmanager.NewMapper(alertmanager.Config{
+			ComponentLabel:    "component",
+			SeverityLabel:     "severity",
+			Severities:        map[string]int{"critical": 100},
+			DefaultSeverity:   66,
+			DefaultImpactType: "Unknown",
+		})
+
+		startsAt = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+		// the references of the impacts are checked like the ones of incidents created through the API
+		expectReferences = func() {
+			sqlMock.
+				ExpectQuery(expectedComponentQuery).
+				WithArgs(componentID, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "display_name"}).AddRow(componentID, "Storage"))
+			sqlMock.ExpectQuery(expectedActiveImpactQuery).WithArgs(componentID).WillReturnRows(sqlmock.NewRows(nil))
+			sqlMock.
+				ExpectQuery(expectedImpactTypeByIDQuery).
+				WithArgs(impactTypeID, 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "display_name"}).AddRow(impactTypeID, "Unknown"))
+		}
+
+		alertRequest = func(status api.AlertStatus) api.AlertmanagerRequest {
+			return api.AlertmanagerRequest{
+				Version: "4",
+				Status:  status,
+				Alerts: []api.Alert{
+					{
+						Status:      status,
+						Labels:      map[string]string{"alertname": "DiskFull", "component": "Storage", "severity": "critical"},
+						Annotations: map[string]string{"summary": "Disk is full"},
+						StartsAt:    startsAt,
+						Fingerprint: fingerprint,
+					},
+				},
+			}
+		}
+	)
+
+	BeforeEach(func() {
+		// setup database and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, mapper, handlerLogger)
+
+		// create mock rows before each test
+		componentRows = sqlmock.
+			NewRows([]string{"id", "display_name", "labels"}).
+			AddRow(componentID, "Storage", []byte(`{}`))
+		alertIncidentRows = sqlmock.
+			NewRows([]string{"id", "fingerprint", "incident_id", "created_at", "resolved_at"})
+		impactTypeRows = sqlmock.
+			NewRows([]string{"id", "display_name", "description"}).
+			AddRow(impactTypeID, "Unknown", nil)
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+	})
+
+	Describe("ReceiveAlerts", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		Context("with a new firing alert", func() {
+			It("should open an incident", func() {
+				// Arrange
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger, http.MethodPost, alertmanagerEndpoint, alertRequest(api.AlertFiring),
+				)
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedComponentsQuery).WillReturnRows(componentRows)
+				sqlMock.ExpectQuery(expectedAlertIncidentQuery).WithArgs(fingerprint, 1).WillReturnRows(alertIncidentRows)
+				sqlMock.ExpectQuery(expectedImpactTypeQuery).WithArgs("Unknown", 1).WillReturnRows(impactTypeRows)
+				expectReferences()
+				sqlMock.ExpectQuery(expectedPhaseGenerationQuery).WillReturnRows(sqlmock.NewRows([]string{"generation"}).AddRow(1))
+				sqlMock.
+					ExpectQuery(expectedUsableComponentsCount).
+					WithArgs(componentID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				sqlMock.ExpectExec(expectedIncidentInsert).WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectExec(expectedImpactInsert).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectRevision(sqlMock, sqlmock.AnyArg(), 0)
+				sqlMock.ExpectExec(expectedRevisionImpactInsert).WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.
+					ExpectQuery(expectedAlertIncidentInsert).
+					WithArgs(fingerprint, sqlmock.AnyArg(), sqlmock.AnyArg(), nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				expectAuditEntry(sqlMock, db.AuditOperationCreate, db.AuditTargetIncident, sqlmock.AnyArg())
+				expectEvent(dialect, sqlMock, db.EventIncidentCreated)
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.ReceiveAlerts(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+
+				var response api.AlertmanagerResponse
+				Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+				Ω(response.Data).Should(HaveLen(1))
+				Ω(response.Data[0].Action).Should(Equal("created"))
+				Ω(response.Data[0].IncidentID).ShouldNot(BeNil())
+			})
+		})
+
+		Context("with a repeated firing alert", func() {
+			It("should not change the incident", func() {
+				// Arrange
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger, http.MethodPost, alertmanagerEndpoint, alertRequest(api.AlertFiring),
+				)
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedComponentsQuery).WillReturnRows(componentRows)
+				sqlMock.
+					ExpectQuery(expectedAlertIncidentQuery).
+					WithArgs(fingerprint, 1).
+					WillReturnRows(alertIncidentRows.AddRow(1, fingerprint, incidentID, startsAt, nil))
+				sqlMock.ExpectQuery(expectedImpactTypeQuery).WithArgs("Unknown", 1).WillReturnRows(impactTypeRows)
+				expectReferences()
+				sqlMock.
+					ExpectQuery(expectedIncidentQuery).
+					WithArgs(incidentID, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "display_name", "began_at"}).
+						AddRow(incidentID, "Disk is full", startsAt))
+				sqlMock.
+					ExpectQuery(expectedImpactQuery).
+					WithArgs(incidentID).
+					WillReturnRows(sqlmock.NewRows([]string{"incident_id", "component_id", "impact_type_id", "severity"}).
+						AddRow(incidentID, componentID, impactTypeID, 100))
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.ReceiveAlerts(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+
+				var response api.AlertmanagerResponse
+				Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+				Ω(response.Data[0].Action).Should(Equal("unchanged"))
+				Ω(response.Data[0].IncidentID).Should(Equal(test.Ptr(uuid.MustParse(incidentID))))
+			})
+		})
+
+		Context("with a resolved alert without incident", func() {
+			It("should skip the alert", func() {
+				// Arrange
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger, http.MethodPost, alertmanagerEndpoint, alertRequest(api.AlertResolved),
+				)
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedComponentsQuery).WillReturnRows(componentRows)
+				sqlMock.ExpectQuery(expectedAlertIncidentQuery).WithArgs(fingerprint, 1).WillReturnRows(alertIncidentRows)
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.ReceiveAlerts(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+
+				var response api.AlertmanagerResponse
+				Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+				Ω(response.Data[0].Action).Should(Equal("skipped"))
+				Ω(response.Data[0].Reason).Should(Equal("no open incident"))
+			})
+		})
+
+		Context("without alerts", func() {
+			It("should return 400 bad request", func() {
+				// Arrange
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger, http.MethodPost, alertmanagerEndpoint, api.AlertmanagerRequest{Version: "4"},
+				)
+
+				// Act
+				err := handlers.ReceiveAlerts(ctx)
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger, http.MethodPost, alertmanagerEndpoint, alertRequest(api.AlertFiring),
+				)
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedComponentsQuery).WillReturnError(test.ErrTestError)
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.ReceiveAlerts(ctx)
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+
+		Context("with disabled receiver", func() {
+			It("should return 404 not found", func() {
+				// Arrange
+				handlers = server.New(nil, nil, nil, nil, handlerLogger)
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger, http.MethodPost, alertmanagerEndpoint, alertRequest(api.AlertFiring),
+				)
+
+				// Act
+				err := handlers.ReceiveAlerts(ctx)
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrNotFound))
+			})
+		})
+	})
+}, test.DialectEntries())
+
+var _ = Describe("Alertmanager with database", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		gormLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		repo storage.Repository
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		receive = func(status api.AlertStatus) api.AlertResult {
+			ctx, res := test.MustCreateEchoContextAndResponseWriter(
+				echoLogger, http.MethodPost, "/alertmanager", api.AlertmanagerRequest{
+					Version: "4",
+					Status:  status,
+					Alerts: []api.Alert{
+						{
+							Status:      status,
+							Labels:      map[string]string{"alertname": "DiskFull", "component": "Storage"},
+							StartsAt:    time.Now().Add(-time.Hour),
+							Fingerprint: "5b2e2f6d9c1a7e40",
+						},
+					},
+				},
+			)
+			Ω(handlers.ReceiveAlerts(ctx)).Should(Succeed())
+
+			var response api.AlertmanagerResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(response.Data).Should(HaveLen(1))
+
+			return response.Data[0]
+		}
+	)
+
+	BeforeEach(func() {
+		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		database, err := appDB.New(appDB.SQLitePrefix+filepath.Join(GinkgoT().TempDir(), "status.db"), gormLogger)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = database.MigrateUp()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		store := storage.NewGorm(database.GetDBCon())
+		repo = store.WithContext(context.Background())
+		handlers = server.New(store, nil, nil, alertmanager.NewMapper(alertmanager.Config{
+			ComponentLabel:    "component",
+			SeverityLabel:     "severity",
+			DefaultSeverity:   66,
+			DefaultImpactType: "Unknown",
+		}), handlerLogger)
+
+		Ω(repo.CreatePhases([]db.Phase{
+			{Name: test.Ptr("Investigation"), Generation: test.Ptr(1), Order: test.Ptr(0)},
+		})).Should(Succeed())
+		Ω(repo.CreateComponent(&db.Component{DisplayName: test.Ptr("Storage")})).Should(Succeed())
+		Ω(repo.CreateImpactType(&db.ImpactType{DisplayName: test.Ptr("Unknown")})).Should(Succeed())
+	})
+
+	It("should reject changes based on the incident before the alert resolved it", func() {
+		// Arrange
+		created := receive(api.AlertFiring)
+		Ω(created.Action).Should(Equal("created"))
+
+		incidentID := *created.IncidentID
+
+		ctx, res := test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/", nil)
+		Ω(handlers.GetIncident(ctx, incidentID)).Should(Succeed())
+
+		etag := res.Header().Get("ETag")
+
+		Ω(receive(api.AlertResolved).Action).Should(Equal("resolved"))
+
+		ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+			echoLogger, http.MethodPatch, "/", apiServerDefinition.Incident{EndedAt: test.Ptr(time.Now().Add(time.Hour))},
+		)
+		ctx.Request().Header.Set("If-Match", etag)
+
+		// Act
+		err := handlers.UpdateIncident(ctx, incidentID)
+
+		// Assert
+		Ω(err).Should(HaveField("Code", http.StatusPreconditionFailed))
+
+		incident, err := repo.GetIncident(incidentID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(incident.EndedAt).ShouldNot(BeNil())
+		Ω(incident.EndedAt.After(time.Now())).Should(BeFalse())
+		Ω(incident.Version).Should(HaveValue(Equal(2)))
+	})
+
+	It("should open a new incident, when an alert fires again after its incident was deleted", func() {
+		// Arrange
+		created := receive(api.AlertFiring)
+		Ω(created.Action).Should(Equal("created"))
+
+		_, err := repo.DeleteIncident(*created.IncidentID, "admin")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		// Act
+		result := receive(api.AlertFiring)
+
+		// Assert
+		Ω(result.Action).Should(Equal("created"))
+		Ω(result.IncidentID).ShouldNot(HaveValue(Equal(*created.IncidentID)))
+		Ω(receive(api.AlertResolved).IncidentID).Should(Equal(result.IncidentID))
+	})
+
+	It("should skip alerts with severities outside the severity bands, if they are required", func() {
+		// Arrange
+		handlers.RequireSeverityBands(true)
+		Ω(repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr("degraded"), Value: test.Ptr(50)})).Should(Succeed())
+
+		// Act
+		result := receive(api.AlertFiring)
+
+		// Assert
+		Ω(result.Action).Should(Equal("skipped"))
+		Ω(result.Reason).Should(Equal(server.ErrSeverityOutsideBands.Error()))
+		Ω(result.IncidentID).Should(BeNil())
+	})
+})
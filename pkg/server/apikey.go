@@ -0,0 +1,170 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetAPIKeys retrieves a list of all API keys without their secrets.
+func (i *Implementation) GetAPIKeys(ctx echo.Context) error {
+	var apiKeys []*DbDef.APIKey
+
+	logger := i.logger.With().Str("handler", "GetAPIKeys").Logger()
+	logger.Debug().Send()
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	res := dbSession.Order("created_at").Find(&apiKeys)
+	if res.Error != nil {
+		logger.Error().Err(res.Error).Msg("error loading API keys")
+
+		return echo.ErrInternalServerError
+	}
+
+	data := make([]api.APIKeyResponseData, len(apiKeys))
+	for apiKeyIndex, apiKey := range apiKeys {
+		data[apiKeyIndex] = apiKey.ToAPIResponse()
+	}
+
+	return ctx.JSON(http.StatusOK, api.APIKeyListResponse{ //nolint:wrapcheck
+		Data: data,
+	})
+}
+
+// CreateAPIKey handles creation of API keys.
+// The plaintext key is only part of this response, as only its hash is stored.
+func (i *Implementation) CreateAPIKey(ctx echo.Context) error {
+	var request api.APIKeyRequest
+
+	logger := i.logger.With().Str("handler", "CreateAPIKey").Logger()
+
+	err := ctx.Bind(&request)
+	if err != nil {
+		logger.Error().Err(err).Msg("error binding request")
+
+		return echo.ErrInternalServerError
+	}
+
+	logger.Debug().Interface("request", request).Send()
+
+	generatedKey, err := auth.GenerateAPIKey()
+	if err != nil {
+		logger.Error().Err(err).Msg("error generating API key")
+
+		return echo.ErrInternalServerError
+	}
+
+	apiKey, err := DbDef.APIKeyFromAPI(&request, generatedKey)
+	if err != nil {
+		logger.Warn().Err(err).Msg("error parsing request")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
+		transactionErr := dbTx.Create(&apiKey).Error
+		if transactionErr != nil {
+			return fmt.Errorf("error creating API key: %w", transactionErr)
+		}
+
+		return recordChange(ctx, storage.NewGorm(dbTx), change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetAPIKey,
+			targetID:   apiKey.ID.String(),
+			before:     nil,
+			after:      apiKey,
+		})
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("error in transaction")
+
+		return echo.ErrInternalServerError
+	}
+
+	return ctx.JSON(http.StatusCreated, api.APIKeyCreatedResponse{ //nolint:wrapcheck
+		Data: api.APIKeyCreatedResponseData{
+			APIKeyResponseData: apiKey.ToAPIResponse(),
+			Key:                generatedKey.Key,
+		},
+	})
+}
+
+// RevokeAPIKey revokes an API key. Revoked keys are kept to be listed.
+func (i *Implementation) RevokeAPIKey(ctx echo.Context, apiKeyID uuid.UUID) error {
+	logger := i.logger.With().Str("handler", "RevokeAPIKey").Str("id", apiKeyID.String()).Logger()
+	logger.Debug().Send()
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
+		var dbAPIKey DbDef.APIKey
+
+		transactionErr := dbTx.Where("id = ? AND revoked_at IS NULL", apiKeyID).First(&dbAPIKey).Error
+		if errors.Is(transactionErr, gorm.ErrRecordNotFound) {
+			logger.Warn().Msg("API key not found")
+
+			return echo.ErrNotFound
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error loading API key from database")
+
+			return echo.ErrInternalServerError
+		}
+
+		revokedAt := time.Now()
+
+		transactionErr = dbTx.
+			Model(&DbDef.APIKey{}). //nolint:exhaustruct
+			Where("id = ?", apiKeyID).
+			Update("revoked_at", revokedAt).
+			Error
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error revoking API key")
+
+			return echo.ErrInternalServerError
+		}
+
+		revokedAPIKey := dbAPIKey
+		revokedAPIKey.RevokedAt = &revokedAt
+
+		transactionErr = recordChange(ctx, storage.NewGorm(dbTx), change{
+			operation:  DbDef.AuditOperationUpdate,
+			targetType: DbDef.AuditTargetAPIKey,
+			targetID:   apiKeyID.String(),
+			before:     dbAPIKey,
+			after:      revokedAPIKey,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording revocation")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
@@ -0,0 +1,348 @@
+package server_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = DescribeTableSubtree("APIKey", func(dialect test.Dialect) {
+	const apiKeyID = "4f9c3e2b-1d6a-4b8e-9f0a-2c5d7e8f9a1b"
+
+	var (
+		// sub loggers
+		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// mocked sql rows
+		apiKeyRows *sqlmock.Rows
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		// expected SQL
+		expectedAPIKeysQuery = regexp.
+					QuoteMeta(`SELECT * FROM "api_keys" ORDER BY created_at`)
+		expectedAPIKeyInsert = regexp.
+					QuoteMeta(`INSERT INTO "api_keys" ("name","prefix","secret_hash","scopes","created_at","expires_at","last_used_at","revoked_at","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`) //nolint:lll
+		expectedAPIKeyQuery = regexp.
+					QuoteMeta(`SELECT * FROM "api_keys" WHERE id = $1 AND revoked_at IS NULL ORDER BY "api_keys"."id" LIMIT $2`)
+		expectedAPIKeyRevoke = regexp.
+					QuoteMeta(`UPDATE "api_keys" SET "revoked_at"=$1 WHERE id = $2`)
+
+		apiKeyUUID = uuid.MustParse(apiKeyID)
+		createdAt  = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+		// filled test API key
+		apiKey = db.APIKey{
+			Name:       test.Ptr("ci"),
+			Prefix:     test.Ptr("spk_abcdefgh"),
+			SecretHash: test.Ptr(auth.HashAPIKey("spk_abcdefgh")),
+			Scopes:     &db.APIKeyScopes{"editor"},
+			CreatedAt:  &createdAt,
+			Model:      db.Model{ID: apiKeyUUID},
+		}
+	)
+
+	BeforeEach(func() {
+		// setup database and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
+
+		// create mock rows before each test
+		apiKeyRows = sqlmock.
+			NewRows([]string{"id", "name", "prefix", "secret_hash", "scopes", "created_at"})
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+	})
+
+	Describe("GetAPIKeys", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/apikeys", nil)
+		})
+
+		Context("with valid data", func() {
+			It("should return a list of API keys without secrets", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedAPIKeysQuery).
+					WillReturnRows(apiKeyRows.AddRow(
+						apiKeyUUID, apiKey.Name, apiKey.Prefix, apiKey.SecretHash, `["editor"]`, createdAt,
+					))
+
+				expectedResult, _ := json.Marshal(api.APIKeyListResponse{
+					Data: []api.APIKeyResponseData{
+						apiKey.ToAPIResponse(),
+					},
+				})
+
+				// Act
+				err := handlers.GetAPIKeys(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(strings.Trim(res.Body.String(), "\n")).Should(Equal(string(expectedResult)))
+				Ω(res.Body.String()).ShouldNot(ContainSubstring(*apiKey.SecretHash))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedAPIKeysQuery).
+					WillReturnError(test.ErrTestError)
+
+				// Act
+				err := handlers.GetAPIKeys(ctx)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+
+	Describe("CreateAPIKey", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodPost,
+				"/apikeys",
+				api.APIKeyRequest{
+					Name:   test.Ptr("ci"),
+					Scopes: []string{"editor"},
+				},
+			)
+		})
+
+		Context("with valid request", func() {
+			It("should return the plaintext key once and only store its hash", func() {
+				// Arrange
+				var (
+					storedHash string
+					response   api.APIKeyCreatedResponse
+				)
+
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectExec(expectedAPIKeyInsert).
+					WithArgs(
+						"ci",
+						sqlmock.AnyArg(),
+						hashCapture{target: &storedHash},
+						`["editor"]`,
+						sqlmock.AnyArg(),
+						nil,
+						nil,
+						nil,
+						sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationCreate, db.AuditTargetAPIKey, sqlmock.AnyArg())
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.CreateAPIKey(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusCreated))
+				Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+				Ω(auth.IsAPIKey(response.Data.Key)).Should(BeTrue())
+				Ω(response.Data.Key).Should(HavePrefix(response.Data.Prefix))
+				Ω(response.Data.Scopes).Should(Equal([]string{"editor"}))
+				Ω(storedHash).Should(Equal(auth.HashAPIKey(response.Data.Key)))
+			})
+		})
+
+		Context("with invalid scope", func() {
+			It("should return 400 bad request", func() {
+				// Arrange
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodPost,
+					"/apikeys",
+					api.APIKeyRequest{
+						Name:   test.Ptr("ci"),
+						Scopes: []string{"root"},
+					},
+				)
+
+				// Act
+				err := handlers.CreateAPIKey(ctx)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+			})
+		})
+
+		Context("with empty request", func() {
+			It("should return 400 bad request", func() {
+				// Arrange
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodPost, "/apikeys", nil)
+
+				// Act
+				err := handlers.CreateAPIKey(ctx)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(expectedAPIKeyInsert).WillReturnError(test.ErrTestError)
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.CreateAPIKey(ctx)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+
+	Describe("RevokeAPIKey", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodDelete,
+				"/apikeys/"+apiKeyID,
+				nil,
+			)
+		})
+
+		Context("with active API key", func() {
+			It("should return 204 no content", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedAPIKeyQuery).
+					WithArgs(apiKeyUUID, 1).
+					WillReturnRows(apiKeyRows.AddRow(
+						apiKeyID, *apiKey.Name, *apiKey.Prefix, *apiKey.SecretHash, `["editor"]`, createdAt,
+					))
+				sqlMock.
+					ExpectExec(expectedAPIKeyRevoke).
+					WithArgs(sqlmock.AnyArg(), apiKeyUUID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationUpdate, db.AuditTargetAPIKey, apiKeyID)
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.RevokeAPIKey(ctx, apiKeyUUID)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("without active API key", func() {
+			It("should return 404 not found", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedAPIKeyQuery).WithArgs(apiKeyUUID, 1).WillReturnRows(apiKeyRows)
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.RevokeAPIKey(ctx, apiKeyUUID)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrNotFound))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedAPIKeyQuery).
+					WithArgs(apiKeyUUID, 1).
+					WillReturnRows(apiKeyRows.AddRow(
+						apiKeyID, *apiKey.Name, *apiKey.Prefix, *apiKey.SecretHash, `["editor"]`, createdAt,
+					))
+				sqlMock.
+					ExpectExec(expectedAPIKeyRevoke).
+					WithArgs(sqlmock.AnyArg(), apiKeyUUID).
+					WillReturnError(test.ErrTestError)
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.RevokeAPIKey(ctx, apiKeyUUID)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+}, test.DialectEntries())
+
+// hashCapture matches any string argument and stores it.
+type hashCapture struct {
+	target *string
+}
+
+func (c hashCapture) Match(value driver.Value) bool {
+	hash, ok := value.(string)
+	*c.target = hash
+
+	return ok
+}
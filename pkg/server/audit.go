@@ -0,0 +1,142 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/labstack/echo/v4"
+)
+
+// anonymousActor is recorded for changes of unauthenticated requests, e.g. with authentication disabled.
+const anonymousActor = "anonymous"
+
+// change describes a write operation on a single resource.
+type change struct {
+	operation  DbDef.AuditOperation
+	targetType string
+	targetID   string
+	// before is the state of the resource before the operation, nil on creation and restoration.
+	before interface{}
+	// after is the state of the resource after the operation, nil on deletion.
+	after interface{}
+}
+
+// actor returns the subject of the authenticated principal of the request.
+func actor(ctx echo.Context) string {
+	principal := auth.PrincipalFromContext(ctx)
+	if principal == nil {
+		return anonymousActor
+	}
+
+	return principal.Subject
+}
+
+// recordChange records the change in the audit log.
+// It must be called with the transaction of the change, so the change and its record are only committed together.
+func recordChange(ctx echo.Context, repo storage.Repository, change change) error {
+	auditEntry, err := DbDef.NewAuditEntry(
+		actor(ctx),
+		change.operation,
+		change.targetType,
+		change.targetID,
+		change.before,
+		change.after,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating audit entry: %w", err)
+	}
+
+	return repo.RecordAudit(auditEntry) //nolint:wrapcheck
+}
+
+// GetAuditEntries retrieves a filtered page of audit entries, newest first.
+// The audit log is only kept in the database, so it is read without the repository,
+// but paged like the other listings, by entry ID as unique key.
+func (i *Implementation) GetAuditEntries(ctx echo.Context, params api.GetAuditEntriesParams) error { //nolint:cyclop
+	var auditEntries []*DbDef.AuditEntry
+
+	logger := i.logger.With().Str("handler", "GetAuditEntries").Logger()
+	logger.Debug().Interface("params", params).Send()
+
+	page, err := pageFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid page parameters")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	if params.Operation != nil && !DbDef.AuditOperation(*params.Operation).IsValid() {
+		logger.Warn().Str("operation", *params.Operation).Msg("unknown operation")
+
+		return echo.ErrBadRequest
+	}
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	// One more entry is loaded to tell, if another page follows.
+	query := dbSession.Order("id desc").Limit(page.Limit + 1)
+
+	if params.Actor != nil {
+		query = query.Where("actor = ?", *params.Actor)
+	}
+
+	if params.Operation != nil {
+		query = query.Where("operation = ?", *params.Operation)
+	}
+
+	if params.TargetType != nil {
+		query = query.Where("target_type = ?", *params.TargetType)
+	}
+
+	if params.TargetID != nil {
+		query = query.Where("target_id = ?", *params.TargetID)
+	}
+
+	if params.Since != nil {
+		query = query.Where("created_at >= ?", *params.Since)
+	}
+
+	if params.Until != nil {
+		query = query.Where("created_at < ?", *params.Until)
+	}
+
+	if page.After != nil {
+		afterID, parseErr := strconv.ParseUint(page.After.Key, 10, 64)
+		if parseErr != nil {
+			err = fmt.Errorf("%w: %w", storage.ErrInvalidCursor, parseErr)
+			logger.Warn().Err(err).Msg("invalid cursor")
+
+			return echo.ErrBadRequest.WithInternal(err)
+		}
+
+		query = query.Where("id < ?", afterID)
+	}
+
+	res := query.Find(&auditEntries)
+	if res.Error != nil {
+		logger.Error().Err(res.Error).Msg("error loading audit entries")
+
+		return echo.ErrInternalServerError
+	}
+
+	var next *storage.Cursor
+
+	if len(auditEntries) > page.Limit {
+		auditEntries = auditEntries[:page.Limit]
+		next = &storage.Cursor{Key: strconv.FormatUint(auditEntries[page.Limit-1].ID, 10), ID: ""}
+	}
+
+	data := make([]api.AuditEntryResponseData, len(auditEntries))
+	for auditEntryIndex, auditEntry := range auditEntries {
+		data[auditEntryIndex] = auditEntry.ToAPIResponse()
+	}
+
+	return respondPage(ctx, data, next)
+}
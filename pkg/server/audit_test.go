@@ -0,0 +1,272 @@
+package server_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+// expectedAuditInsert is the SQL recording an audit entry.
+var expectedAuditInsert = regexp. //nolint:gochecknoglobals
+					QuoteMeta(`INSERT INTO "audit_entries" ("created_at","actor","operation","target_type","target_id","before","after") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`) //nolint:lll
+
+// expectAuditEntry expects an audit entry of an anonymous request to be recorded.
+func expectAuditEntry(sqlMock sqlmock.Sqlmock, operation db.AuditOperation, targetType string, targetID driver.Value) {
+	sqlMock.
+		ExpectQuery(expectedAuditInsert).
+		WithArgs(sqlmock.AnyArg(), "anonymous", string(operation), targetType, targetID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+var _ = DescribeTableSubtree("Audit", func(dialect test.Dialect) {
+	var (
+		// sub loggers
+		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// mocked sql rows
+		auditEntryRows *sqlmock.Rows
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		// expected SQL
+		expectedAuditEntriesQuery = regexp.
+						QuoteMeta(`SELECT * FROM "audit_entries" ORDER BY id desc LIMIT $1`)
+		expectedFilteredAuditEntriesQuery = regexp.
+							QuoteMeta(`SELECT * FROM "audit_entries" WHERE actor = $1 AND target_type = $2 ORDER BY id desc LIMIT $3`) //nolint:lll
+		expectedAuditEntriesQueryWithCursor = regexp.
+							QuoteMeta(`SELECT * FROM "audit_entries" WHERE id < $1 ORDER BY id desc LIMIT $2`)
+
+		createdAt = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+		// filled test audit entry
+		auditEntry = db.AuditEntry{
+			ID:         42,
+			CreatedAt:  createdAt,
+			Actor:      "alice",
+			Operation:  db.AuditOperationUpdate,
+			TargetType: db.AuditTargetComponent,
+			TargetID:   "7c5c5e2f-5ec4-4a4b-8a66-bc2f8b9ad0a1",
+			Before:     db.JSONDocument(`{"displayName":"Storage"}`),
+			After:      db.JSONDocument(`{"displayName":"Network"}`),
+		}
+	)
+
+	BeforeEach(func() {
+		// setup database and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
+
+		// create mock rows before each test
+		auditEntryRows = sqlmock.
+			NewRows([]string{"id", "created_at", "actor", "operation", "target_type", "target_id", "before", "after"})
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+	})
+
+	Describe("GetAuditEntries", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/audit", nil)
+		})
+
+		Context("with valid data", func() {
+			It("should return a list of audit entries with their changes", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedAuditEntriesQuery).
+					WithArgs(101).
+					WillReturnRows(auditEntryRows.AddRow(
+						auditEntry.ID,
+						auditEntry.CreatedAt,
+						auditEntry.Actor,
+						auditEntry.Operation,
+						auditEntry.TargetType,
+						auditEntry.TargetID,
+						[]byte(auditEntry.Before),
+						[]byte(auditEntry.After),
+					))
+
+				expectedResult, _ := json.Marshal(api.PageResponse[api.AuditEntryResponseData]{
+					Data: []api.AuditEntryResponseData{
+						auditEntry.ToAPIResponse(),
+					},
+					Next: nil,
+				})
+
+				// Act
+				err := handlers.GetAuditEntries(ctx, api.GetAuditEntriesParams{}) //nolint:exhaustruct
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(strings.Trim(res.Body.String(), "\n")).Should(Equal(string(expectedResult)))
+				Ω(res.Body.String()).Should(ContainSubstring(`"field":"displayName"`))
+			})
+		})
+
+		Context("with filters", func() {
+			It("should filter the audit entries", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedFilteredAuditEntriesQuery).
+					WithArgs("alice", db.AuditTargetComponent, 101).
+					WillReturnRows(auditEntryRows)
+
+				// Act
+				err := handlers.GetAuditEntries(ctx, api.GetAuditEntriesParams{ //nolint:exhaustruct
+					Actor:      test.Ptr("alice"),
+					TargetType: test.Ptr(db.AuditTargetComponent),
+				})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(strings.Trim(res.Body.String(), "\n")).Should(Equal(`{"data":[]}`))
+			})
+		})
+
+		Context("with limit param", func() {
+			It("should link the next page", func() {
+				// Arrange
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/audit?limit=1", nil)
+				sqlMock.
+					ExpectQuery(expectedAuditEntriesQuery).
+					WithArgs(2).
+					WillReturnRows(
+						auditEntryRows.
+							AddRow(
+								auditEntry.ID,
+								auditEntry.CreatedAt,
+								auditEntry.Actor,
+								auditEntry.Operation,
+								auditEntry.TargetType,
+								auditEntry.TargetID,
+								[]byte(auditEntry.Before),
+								[]byte(auditEntry.After),
+							).
+							AddRow(41, createdAt, "bob", db.AuditOperationCreate, db.AuditTargetComponent, auditEntry.TargetID, nil, nil),
+					)
+
+				cursor := storage.Cursor{Key: "42", ID: ""}
+				nextLink := "/audit?cursor=" + cursor.String() + "&limit=1"
+
+				// Act
+				err := handlers.GetAuditEntries(ctx, api.GetAuditEntriesParams{}) //nolint:exhaustruct
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Header().Get("Link")).Should(Equal("<" + nextLink + `>; rel="next"`))
+				Ω(res.Body.String()).Should(ContainSubstring(`"id":42`))
+				Ω(res.Body.String()).ShouldNot(ContainSubstring(`"id":41`))
+			})
+		})
+
+		Context("with cursor param", func() {
+			It("should continue after the cursor", func() {
+				// Arrange
+				cursor := storage.Cursor{Key: "43", ID: ""}
+
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodGet,
+					"/audit?cursor="+cursor.String(),
+					nil,
+				)
+				sqlMock.
+					ExpectQuery(expectedAuditEntriesQueryWithCursor).
+					WithArgs(43, 101).
+					WillReturnRows(auditEntryRows)
+
+				// Act
+				err := handlers.GetAuditEntries(ctx, api.GetAuditEntriesParams{}) //nolint:exhaustruct
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Header().Get("Link")).Should(BeEmpty())
+			})
+		})
+
+		Context("with invalid page params", func() {
+			DescribeTable("should return 400 bad request",
+				func(query string) {
+					// Arrange
+					ctx, _ = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/audit?"+query, nil)
+
+					// Act
+					err := handlers.GetAuditEntries(ctx, api.GetAuditEntriesParams{}) //nolint:exhaustruct
+
+					// Assert
+					Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				},
+				Entry("with zero limit", "limit=0"),
+				Entry("with limit above maximum", "limit=1001"),
+				Entry("with malformed cursor", "cursor=not-a-cursor"),
+				Entry("with cursor of another listing", "cursor="+storage.Cursor{Key: "Storage", ID: "0"}.String()),
+			)
+		})
+
+		Context("with unknown operation", func() {
+			It("should return 400 bad request", func() {
+				// Act
+				err := handlers.GetAuditEntries(ctx, api.GetAuditEntriesParams{ //nolint:exhaustruct
+					Operation: test.Ptr("rename"),
+				})
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrBadRequest))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedAuditEntriesQuery).
+					WillReturnError(test.ErrTestError)
+
+				// Act
+				err := handlers.GetAuditEntries(ctx, api.GetAuditEntriesParams{}) //nolint:exhaustruct
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+}, test.DialectEntries())
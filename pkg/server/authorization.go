@@ -0,0 +1,99 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+)
+
+// OperationScopes declares the scope required for every operation of the OpenAPI spec by its operation ID.
+// Operations missing here are denied, see [auth.NewPolicy]. Extension routes declare their scope themselves.
+var OperationScopes = map[string]auth.Scope{ //nolint:gochecknoglobals
+	// components
+	"GetComponents":   auth.ScopeRead,
+	"GetComponent":    auth.ScopeRead,
+	"CreateComponent": auth.ScopeAdmin,
+	"UpdateComponent": auth.ScopeAdmin,
+	"DeleteComponent": auth.ScopeAdmin,
+
+	// impact types
+	"GetImpactTypes":   auth.ScopeRead,
+	"GetImpactType":    auth.ScopeRead,
+	"CreateImpactType": auth.ScopeAdmin,
+	"UpdateImpactType": auth.ScopeAdmin,
+	"DeleteImpactType": auth.ScopeAdmin,
+
+	// incidents
+	"GetIncidents":   auth.ScopeRead,
+	"GetIncident":    auth.ScopeRead,
+	"CreateIncident": auth.ScopeEditor,
+	"UpdateIncident": auth.ScopeEditor,
+	"DeleteIncident": auth.ScopeEditor,
+
+	// incident updates
+	"GetIncidentUpdates":   auth.ScopeRead,
+	"GetIncidentUpdate":    auth.ScopeRead,
+	"CreateIncidentUpdate": auth.ScopeEditor,
+	"UpdateIncidentUpdate": auth.ScopeEditor,
+	"DeleteIncidentUpdate": auth.ScopeEditor,
+
+	// phases
+	"GetPhaseList":    auth.ScopeRead,
+	"CreatePhaseList": auth.ScopeAdmin,
+
+	// severities
+	"GetSeverities":  auth.ScopeRead,
+	"GetSeverity":    auth.ScopeRead,
+	"CreateSeverity": auth.ScopeAdmin,
+	"UpdateSeverity": auth.ScopeAdmin,
+	"DeleteSeverity": auth.ScopeAdmin,
+}
+
+// pathParameterPattern matches OpenAPI path parameters like `{componentId}`.
+var pathParameterPattern = regexp.MustCompile(`{([^}]+)}`) //nolint:gochecknoglobals
+
+// Operations returns the operation ID of every route defined in the OpenAPI spec and of every extension route.
+// Paths are converted to the echo format, e.g. `/components/:componentId`.
+func Operations() (map[auth.Route]string, error) {
+	swagger, err := apiServerDefinition.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("error loading OpenAPI spec: %w", err)
+	}
+
+	operations := map[auth.Route]string{}
+
+	for path, pathItem := range swagger.Paths.Map() {
+		echoPath := pathParameterPattern.ReplaceAllString(path, ":$1")
+
+		for method, operation := range pathItem.Operations() {
+			operations[auth.Route{Method: method, Path: echoPath}] = operation.OperationID
+		}
+	}
+
+	for _, route := range extensionRoutes {
+		operations[auth.Route{Method: route.method, Path: route.path}] = route.operationID
+	}
+
+	return operations, nil
+}
+
+// NewAuthorizationPolicy creates the [auth.Policy] for all operations served by the [Implementation].
+func NewAuthorizationPolicy() (*auth.Policy, error) {
+	operations, err := Operations()
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make(map[string]auth.Scope, len(OperationScopes)+len(extensionRoutes))
+	for operationID, scope := range OperationScopes {
+		scopes[operationID] = scope
+	}
+
+	for _, route := range extensionRoutes {
+		scopes[route.operationID] = route.scope
+	}
+
+	return auth.NewPolicy(operations, scopes) //nolint:wrapcheck
+}
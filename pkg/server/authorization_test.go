@@ -0,0 +1,76 @@
+package server_test
+
+import (
+	"net/http"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authorization", func() {
+	Describe("Operations", func() {
+		It("should convert spec paths to echo paths", func() {
+			// Act
+			operations, err := server.Operations()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(operations).Should(HaveKeyWithValue(
+				auth.Route{Method: http.MethodPatch, Path: "/incidents/:incidentId/updates/:updateOrder"},
+				"UpdateIncidentUpdate",
+			))
+		})
+	})
+
+	Describe("NewAuthorizationPolicy", func() {
+		It("should declare a scope for every operation", func() {
+			// Act
+			_, err := server.NewAuthorizationPolicy()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		DescribeTable("should require",
+			func(method string, path string, expected auth.Scope) {
+				// Arrange
+				policy, err := server.NewAuthorizationPolicy()
+				Ω(err).ShouldNot(HaveOccurred())
+
+				// Act
+				_, scope, err := policy.RequiredScope(method, path)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(scope).Should(Equal(expected))
+			},
+			Entry("read for listing incidents", http.MethodGet, "/incidents", auth.ScopeRead),
+			Entry("editor for creating incidents", http.MethodPost, "/incidents", auth.ScopeEditor),
+			Entry("editor for creating incident updates", http.MethodPost, "/incidents/:incidentId/updates", auth.ScopeEditor),
+			Entry("admin for changing components", http.MethodPatch, "/components/:componentId", auth.ScopeAdmin),
+			Entry("admin for restoring components", http.MethodPost, "/components/:componentId/restore", auth.ScopeAdmin),
+			Entry("editor for restoring incidents", http.MethodPost, "/incidents/:incidentId/restore", auth.ScopeEditor),
+			Entry("editor for receiving alerts", http.MethodPost, "/alertmanager", auth.ScopeEditor),
+			Entry("admin for creating impact types", http.MethodPost, "/impacttypes", auth.ScopeAdmin),
+			Entry("admin for deleting severities", http.MethodDelete, "/severities/:severityName", auth.ScopeAdmin),
+			Entry("admin for creating phase lists", http.MethodPost, "/phases", auth.ScopeAdmin),
+			Entry("admin for listing API keys", http.MethodGet, "/apikeys", auth.ScopeAdmin),
+			Entry("admin for revoking API keys", http.MethodDelete, "/apikeys/:apiKeyId", auth.ScopeAdmin),
+			Entry("admin for reading the audit log", http.MethodGet, "/audit", auth.ScopeAdmin),
+			Entry("read for streaming events", http.MethodGet, "/events", auth.ScopeRead),
+			Entry("read for the Atom feed", http.MethodGet, "/feeds/incidents.atom", auth.ScopeRead),
+			Entry("read for the maintenance calendar", http.MethodGet, "/maintenances.ics", auth.ScopeRead),
+			Entry("read for subscribing", http.MethodPost, "/subscribers", auth.ScopeRead),
+			Entry("read for unsubscribing", http.MethodPost, "/subscribers/unsubscribe", auth.ScopeRead),
+			Entry("admin for creating webhooks", http.MethodPost, "/webhooks", auth.ScopeAdmin),
+			Entry(
+				"admin for retrying webhook deliveries",
+				http.MethodPost,
+				"/webhooks/:webhookId/deliveries/:deliveryId/retry",
+				auth.ScopeAdmin,
+			),
+		)
+	})
+})
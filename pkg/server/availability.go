@@ -0,0 +1,146 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/availability"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// GetComponentAvailability computes the availability of a component in the requested period, in total and per bucket.
+// The component is rated by the highest severity of the impacts of its incidents, at any time up to now.
+func (i *Implementation) GetComponentAvailability(
+	ctx echo.Context,
+	componentID uuid.UUID,
+	params api.GetAvailabilityParams,
+) error {
+	logger := i.logger.With().Str("handler", "GetComponentAvailability").Interface("id", componentID).Logger()
+	logger.Debug().Interface("params", params).Send()
+
+	if params.Start == nil || params.End == nil {
+		logger.Warn().Msg("start and end are required")
+
+		return echo.ErrBadRequest
+	}
+
+	period := availability.Period{
+		Start:              *params.Start,
+		End:                *params.End,
+		Granularity:        api.DefaultAvailabilityGranularity,
+		ExcludeMaintenance: params.ExcludeMaintenance != nil && *params.ExcludeMaintenance,
+		Now:                time.Now(),
+	}
+
+	if params.Granularity != nil {
+		period.Granularity = availability.Granularity(*params.Granularity)
+	}
+
+	repo := i.storage.WithContext(ctx.Request().Context())
+
+	_, err := repo.GetComponent(componentID, nil)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.Warn().Msg("component not found")
+
+			return echo.ErrNotFound
+		}
+
+		logger.Error().Err(err).Msg("error loading component")
+
+		return echo.ErrInternalServerError
+	}
+
+	impacts, err := repo.ListComponentImpacts(componentID, period.Start, period.End)
+	if err != nil {
+		logger.Error().Err(err).Msg("error loading impacts")
+
+		return echo.ErrInternalServerError
+	}
+
+	severities, _, err := repo.ListSeverities(storage.Page{Limit: 0, After: nil})
+	if err != nil {
+		logger.Error().Err(err).Msg("error loading severities")
+
+		return echo.ErrInternalServerError
+	}
+
+	report, err := availability.Compute(availabilityImpacts(impacts), availabilitySeverities(severities), period)
+	if err != nil {
+		if errors.Is(err, availability.ErrInvalidGranularity) ||
+			errors.Is(err, availability.ErrInvalidPeriod) ||
+			errors.Is(err, availability.ErrTooManyBuckets) {
+			logger.Warn().Err(err).Msg("invalid period")
+
+			return echo.ErrBadRequest.WithInternal(err)
+		}
+
+		logger.Error().Err(err).Msg("error computing availability")
+
+		return echo.ErrInternalServerError
+	}
+
+	response := api.AvailabilityResponse{
+		ComponentID:        componentID,
+		Start:              period.Start,
+		End:                period.End,
+		Granularity:        string(period.Granularity),
+		ExcludeMaintenance: period.ExcludeMaintenance,
+		Total:              availabilityBucket(&report.Total),
+		Buckets:            make([]api.AvailabilityBucket, 0, len(report.Buckets)),
+	}
+
+	for index := range report.Buckets {
+		response.Buckets = append(response.Buckets, availabilityBucket(&report.Buckets[index]))
+	}
+
+	return ctx.JSON(http.StatusOK, response) //nolint:wrapcheck
+}
+
+// availabilityImpacts converts the impacts by the times of their incidents, impacts without severity are skipped.
+func availabilityImpacts(impacts []*DbDef.Impact) []availability.Impact {
+	converted := make([]availability.Impact, 0, len(impacts))
+
+	for _, impact := range impacts {
+		if impact.Severity == nil || impact.Incident == nil || impact.Incident.BeganAt == nil {
+			continue
+		}
+
+		converted = append(converted, availability.Impact{
+			Start:    *impact.Incident.BeganAt,
+			End:      impact.Incident.EndedAt,
+			Severity: *impact.Severity,
+		})
+	}
+
+	return converted
+}
+
+// availabilitySeverities converts the severities, keeping their order by value.
+func availabilitySeverities(severities []*DbDef.Severity) []availability.Severity {
+	converted := make([]availability.Severity, 0, len(severities))
+
+	for _, severity := range severities {
+		if severity.DisplayName == nil || severity.Value == nil {
+			continue
+		}
+
+		converted = append(converted, availability.Severity{Name: *severity.DisplayName, Value: *severity.Value})
+	}
+
+	return converted
+}
+
+func availabilityBucket(bucket *availability.Bucket) api.AvailabilityBucket {
+	return api.AvailabilityBucket{
+		Start:        bucket.Start,
+		End:          bucket.End,
+		Availability: bucket.Availability(),
+		Severities:   bucket.Severities(),
+	}
+}
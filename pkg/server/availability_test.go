@@ -0,0 +1,151 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Availability", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		store *storage.Memory
+		repo  storage.Repository
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		start = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+		end   = start.AddDate(0, 0, 2)
+
+		component  *db.Component
+		impactType *db.ImpactType
+
+		createIncident = func(began time.Time, ended *time.Time, severity int) {
+			Ω(repo.CreateIncident(&db.Incident{
+				DisplayName: test.Ptr("Incident"),
+				BeganAt:     &began,
+				EndedAt:     ended,
+				Phase:       &db.Phase{Generation: test.Ptr(1), Order: test.Ptr(0)},
+				Affects: &[]db.Impact{{
+					ComponentID:  &component.ID,
+					ImpactTypeID: &impactType.ID,
+					Severity:     test.Ptr(severity),
+				}},
+			})).Should(Succeed())
+		}
+
+		getAvailability = func(componentID uuid.UUID, params api.GetAvailabilityParams) api.AvailabilityResponse {
+			ctx, res := test.MustCreateEchoContextAndResponseWriter(
+				echoLogger, http.MethodGet, "/components/"+componentID.String()+"/availability", nil,
+			)
+
+			err := handlers.GetComponentAvailability(ctx, componentID, params)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusOK))
+
+			var response api.AvailabilityResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+
+			return response
+		}
+	)
+
+	BeforeEach(func() {
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store = storage.NewMemory()
+		repo = store.WithContext(context.Background())
+		handlers = server.New(store, nil, nil, nil, handlerLogger)
+
+		component = &db.Component{DisplayName: test.Ptr("Storage")}
+		impactType = &db.ImpactType{DisplayName: test.Ptr("Performance degration")}
+
+		Ω(repo.CreateComponent(component)).Should(Succeed())
+		Ω(repo.CreateImpactType(impactType)).Should(Succeed())
+		Ω(repo.CreatePhases([]db.Phase{
+			{Name: test.Ptr("Investigation"), Generation: test.Ptr(1), Order: test.Ptr(0)},
+		})).Should(Succeed())
+
+		for name, value := range map[string]int{"operational": 33, "limited": 66, "broken": 100} {
+			Ω(repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr(name), Value: test.Ptr(value)})).Should(Succeed())
+		}
+	})
+
+	Context("with impacts", func() {
+		BeforeEach(func() {
+			// 12 hours broken on the first day, ongoing maintenance from the second day on.
+			createIncident(start.Add(6*time.Hour), test.Ptr(start.Add(18*time.Hour)), 100)
+			createIncident(start.Add(36*time.Hour), nil, 0)
+			// outside of the period.
+			createIncident(start.AddDate(0, 0, -2), test.Ptr(start.AddDate(0, 0, -1)), 100)
+		})
+
+		It("should report the availability per day", func() {
+			// Act
+			response := getAvailability(component.ID, api.GetAvailabilityParams{Start: &start, End: &end})
+
+			// Assert
+			Ω(response.ComponentID).Should(Equal(component.ID))
+			Ω(response.Granularity).Should(Equal("day"))
+			Ω(response.Buckets).Should(HaveLen(2))
+			Ω(response.Buckets[0].Availability).Should(HaveValue(BeNumerically("~", 50)))
+			Ω(response.Buckets[0].Severities).Should(Equal(map[string]float64{
+				"operational": 50, "limited": 50, "broken": 50,
+			}))
+			Ω(response.Buckets[1].Availability).Should(HaveValue(BeNumerically("~", 50)))
+			Ω(response.Buckets[1].Severities).Should(HaveKeyWithValue("limited", BeZero()))
+			Ω(response.Total.Availability).Should(HaveValue(BeNumerically("~", 50)))
+		})
+
+		It("should exclude maintenance, if requested", func() {
+			// Act
+			response := getAvailability(component.ID, api.GetAvailabilityParams{
+				Start: &start, End: &end, Granularity: test.Ptr("week"), ExcludeMaintenance: test.Ptr(true),
+			})
+
+			// Assert
+			Ω(response.ExcludeMaintenance).Should(BeTrue())
+			Ω(response.Buckets).Should(HaveLen(1))
+			Ω(response.Total.Availability).Should(HaveValue(BeNumerically("~", 100.0*24/36)))
+		})
+	})
+
+	DescribeTable("with invalid request",
+		func(params api.GetAvailabilityParams, componentExists bool, expectedCode int) {
+			// Arrange
+			componentID := uuid.New()
+			if componentExists {
+				componentID = component.ID
+			}
+
+			ctx, _ := test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/", nil)
+
+			// Act
+			err := handlers.GetComponentAvailability(ctx, componentID, params)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", expectedCode))
+		},
+		Entry("without start", api.GetAvailabilityParams{End: &end}, true, http.StatusBadRequest),
+		Entry("with end before start", api.GetAvailabilityParams{Start: &end, End: &start}, true, http.StatusBadRequest),
+		Entry("with unknown granularity",
+			api.GetAvailabilityParams{Start: &start, End: &end, Granularity: test.Ptr("year")}, true, http.StatusBadRequest),
+		Entry("with unknown component", api.GetAvailabilityParams{Start: &start, End: &end}, false, http.StatusNotFound),
+	)
+})
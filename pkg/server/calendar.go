@@ -0,0 +1,161 @@
+package server
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/calendar"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// calendarHistory is the time ended maintenances stay in the calendar.
+	calendarHistory = 30 * 24 * time.Hour
+	// calendarProductID identifies the calendars created by the API.
+	calendarProductID = "-//SovereignCloudStack//Status Page API//EN"
+	// calendarName names the calendar of maintenances.
+	calendarName = "Status page maintenances"
+	// calendarUpdateTimeFormat formats the time of updates in descriptions.
+	calendarUpdateTimeFormat = "2006-01-02 15:04 MST"
+)
+
+// GetMaintenancesICS renders upcoming and recently ended maintenances as iCalendar.
+func (i *Implementation) GetMaintenancesICS(ctx echo.Context, params api.GetFeedParams) error {
+	var maintenances []*DbDef.Incident
+
+	logger := i.logger.With().Str("handler", "GetMaintenancesICS").Logger()
+	logger.Debug().Interface("params", params).Send()
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	query, err := filterIncidents(dbSession, params)
+	if err != nil {
+		logger.Warn().Err(err).Msg("error parsing filter")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	res := query.
+		Preload("Affects.Component").
+		Preload("Updates").
+		Where("id IN (SELECT incident_id FROM impacts WHERE severity = ?)", api.MaintenanceSeverity).
+		Where("ended_at >= ?", time.Now().Add(-calendarHistory)).
+		Order("began_at").
+		Find(&maintenances)
+	if res.Error != nil {
+		logger.Error().Err(res.Error).Msg("error loading maintenances")
+
+		return echo.ErrInternalServerError
+	}
+
+	baseURL := ctx.Scheme() + "://" + ctx.Request().Host
+	maintenanceCalendar, lastModified := maintenancesToCalendar(maintenances, baseURL)
+
+	return sendCacheable(ctx, calendar.ContentType, maintenanceCalendar.Render(), lastModified)
+}
+
+// maintenancesToCalendar creates a calendar with one event per maintenance
+// and returns it with the time of the latest revision.
+// Each update of a maintenance is a revision of its event.
+func maintenancesToCalendar(maintenances []*DbDef.Incident, baseURL string) (*calendar.Calendar, time.Time) {
+	var lastModified time.Time
+
+	maintenanceCalendar := calendar.Calendar{
+		ProductID: calendarProductID,
+		Name:      calendarName,
+		Events:    make([]calendar.Event, 0, len(maintenances)),
+	}
+
+	for _, maintenance := range maintenances {
+		if maintenance.BeganAt == nil || maintenance.EndedAt == nil {
+			continue
+		}
+
+		var updates []DbDef.IncidentUpdate
+		if maintenance.Updates != nil {
+			updates = slices.Clone(*maintenance.Updates)
+			slices.SortFunc(updates, func(a, b DbDef.IncidentUpdate) int {
+				return *a.Order - *b.Order
+			})
+		}
+
+		// Without updates, the maintenance was not revised since its announcement.
+		revisedAt := *maintenance.BeganAt
+
+		for _, update := range updates {
+			if update.CreatedAt != nil && update.CreatedAt.After(revisedAt) {
+				revisedAt = *update.CreatedAt
+			}
+		}
+
+		if revisedAt.After(lastModified) {
+			lastModified = revisedAt
+		}
+
+		maintenanceCalendar.Events = append(maintenanceCalendar.Events, calendar.Event{
+			UID:          maintenance.ID.String(),
+			Sequence:     len(updates),
+			Start:        *maintenance.BeganAt,
+			End:          *maintenance.EndedAt,
+			LastModified: revisedAt,
+			Summary:      valueOrEmpty(maintenance.DisplayName),
+			Description:  maintenanceDescription(maintenance, updates),
+			URL:          baseURL + "/incidents/" + maintenance.ID.String(),
+			Categories:   []string{feedMaintenanceCategory},
+		})
+	}
+
+	return &maintenanceCalendar, lastModified
+}
+
+// maintenanceDescription describes the maintenance, its affected components and its updates.
+func maintenanceDescription(maintenance *DbDef.Incident, updates []DbDef.IncidentUpdate) string {
+	paragraphs := []string{}
+
+	if maintenance.Description != nil && *maintenance.Description != "" {
+		paragraphs = append(paragraphs, *maintenance.Description)
+	}
+
+	components := []string{}
+
+	if maintenance.Affects != nil {
+		for _, impact := range *maintenance.Affects {
+			if impact.Component != nil && impact.Component.DisplayName != nil {
+				components = append(components, *impact.Component.DisplayName)
+			}
+		}
+	}
+
+	if len(components) > 0 {
+		slices.Sort(components)
+		paragraphs = append(paragraphs, "Affected components: "+strings.Join(slices.Compact(components), ", "))
+	}
+
+	if len(updates) > 0 {
+		lines := make([]string, len(updates))
+
+		for updateIndex, update := range updates {
+			var line string
+			if update.CreatedAt != nil {
+				line = update.CreatedAt.UTC().Format(calendarUpdateTimeFormat) + " "
+			}
+
+			line += valueOrEmpty(update.DisplayName)
+			if update.Description != nil && *update.Description != "" {
+				line += ": " + *update.Description
+			}
+
+			lines[updateIndex] = line
+		}
+
+		paragraphs = append(paragraphs, "Updates:\n"+strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
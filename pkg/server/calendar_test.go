@@ -0,0 +1,181 @@
+package server_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/calendar"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = DescribeTableSubtree("Calendar", func(dialect test.Dialect) {
+	const (
+		maintenanceID          = "4bcd4e61-b1d5-4c8b-9ec4-54e0d7d0e0d1"
+		maintenanceComponentID = "8f8b9c1e-3b5e-4c55-a58c-4b6c3b7b5d0e"
+		maintenanceImpactType  = "c3fc130d-e6c4-4f94-86ac-b9f7d7f8d6a9"
+	)
+
+	var (
+		// sub loggers
+		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		// expected SQL
+		expectedMaintenancesQuery = regexp.
+						QuoteMeta(`SELECT * FROM "incidents" WHERE id IN (SELECT incident_id FROM impacts WHERE severity = $1) AND ended_at >= $2 AND "incidents"."deleted_at" IS NULL ORDER BY began_at`) //nolint:lll
+		expectedFilteredMaintenancesQuery = regexp.
+							QuoteMeta(`SELECT * FROM "incidents" WHERE id IN (SELECT incident_id FROM impacts WHERE component_id = $1) AND id IN (SELECT incident_id FROM impacts WHERE severity = $2) AND ended_at >= $3 AND "incidents"."deleted_at" IS NULL ORDER BY began_at`) //nolint:lll
+		expectedMaintenanceImpactQuery = regexp.
+						QuoteMeta(`SELECT * FROM "impacts" WHERE "impacts"."incident_id" = $1`)
+		expectedMaintenanceComponentQuery = regexp.
+							QuoteMeta(`SELECT * FROM "components" WHERE "components"."id" = $1`)
+		expectedMaintenanceUpdateQuery = regexp.
+						QuoteMeta(`SELECT * FROM "incident_updates" WHERE "incident_updates"."incident_id" = $1`)
+
+		beganAt = time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		// setup database and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+	})
+
+	Describe("GetMaintenancesICS", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/maintenances.ics", nil)
+		})
+
+		Context("with valid data", func() {
+			It("should return an event per maintenance with updates as revisions", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedMaintenancesQuery).
+					WithArgs(0, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "display_name", "description", "began_at", "ended_at"}).
+						AddRow(maintenanceID, "Storage upgrade", "Planned upgrade", beganAt, beganAt.Add(4*time.Hour)),
+					)
+				sqlMock.
+					ExpectQuery(expectedMaintenanceImpactQuery).
+					WithArgs(maintenanceID).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"incident_id", "component_id", "impact_type_id", "severity"}).
+						AddRow(maintenanceID, maintenanceComponentID, maintenanceImpactType, 0),
+					)
+				sqlMock.
+					ExpectQuery(expectedMaintenanceComponentQuery).
+					WithArgs(maintenanceComponentID).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "display_name"}).
+						AddRow(maintenanceComponentID, "Storage"),
+					)
+				sqlMock.
+					ExpectQuery(expectedMaintenanceUpdateQuery).
+					WithArgs(maintenanceID).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"incident_id", "order", "display_name", "description", "created_at"}).
+						AddRow(maintenanceID, 1, "Extended", "The upgrade takes longer.", beganAt.Add(2*time.Hour)).
+						AddRow(maintenanceID, 0, "Started", "", beganAt),
+					)
+
+				// Act
+				err := handlers.GetMaintenancesICS(ctx, api.GetFeedParams{Component: nil, Label: nil})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Header().Get(echo.HeaderContentType)).Should(Equal(calendar.ContentType))
+				Ω(res.Header().Get(echo.HeaderLastModified)).Should(Equal("Fri, 01 Mar 2024 12:00:00 GMT"))
+
+				document := strings.ReplaceAll(res.Body.String(), "\r\n ", "")
+				Ω(document).Should(ContainSubstring("\r\nUID:" + maintenanceID + "\r\nSEQUENCE:2\r\n"))
+				Ω(document).Should(ContainSubstring("\r\nDTSTART:20240301T100000Z\r\nDTEND:20240301T140000Z\r\n"))
+				Ω(document).Should(ContainSubstring(
+					`DESCRIPTION:Planned upgrade\n\nAffected components: Storage\n\nUpdates:\n` +
+						`2024-03-01 10:00 UTC Started\n2024-03-01 12:00 UTC Extended: The upgrade takes longer.` + "\r\n",
+				))
+			})
+		})
+
+		Context("with component filter", func() {
+			It("should only query maintenances affecting the component", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedFilteredMaintenancesQuery).
+					WithArgs(maintenanceComponentID, 0, sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				// Act
+				err := handlers.GetMaintenancesICS(ctx, api.GetFeedParams{
+					Component: test.Ptr(uuid.MustParse(maintenanceComponentID)),
+					Label:     nil,
+				})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Body.String()).ShouldNot(ContainSubstring("BEGIN:VEVENT"))
+			})
+		})
+
+		Context("with invalid label", func() {
+			It("should return 400 bad request", func() {
+				// Act
+				err := handlers.GetMaintenancesICS(ctx, api.GetFeedParams{Component: nil, Label: []string{"region"}})
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedMaintenancesQuery).
+					WillReturnError(test.ErrTestError)
+
+				// Act
+				err := handlers.GetMaintenancesICS(ctx, api.GetFeedParams{Component: nil, Label: nil})
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+}, test.DialectEntries())
@@ -3,53 +3,86 @@ package server
 import (
 	"errors"
 	"net/http"
-	"time"
 
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
 	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
-func incidentJoin(at *time.Time) func(db *gorm.DB) *gorm.DB {
-	return func(db *gorm.DB) *gorm.DB {
-		join := db.Joins("Incident")
+// GetComponents retrieves a page of the components matching the label selector, sorted by name.
+// On request, the active impacts include the impacts on the dependencies of the components.
+// Admins may request deleted components, which are marked as such.
+func (i *Implementation) GetComponents(ctx echo.Context, params apiServerDefinition.GetComponentsParams) error {
+	logger := i.logger.With().Str("handler", "GetComponents").Logger()
+	logger.Debug().Interface("at", params.At).Send()
 
-		if at != nil {
-			join = join.Where("began_at < ? AND ended_at > ?", at, at).Or("began_at < ? AND ended_at IS NULL", at)
-		} else {
-			join = join.Where("ended_at IS NULL")
-		}
+	includeIndirect, err := includeIndirectFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid dependency parameters")
 
-		return join
+		return echo.ErrBadRequest.WithInternal(err)
 	}
-}
 
-// GetComponents retrieves a list of all components.
-func (i *Implementation) GetComponents(ctx echo.Context, params apiServerDefinition.GetComponentsParams) error {
-	var components []*DbDef.Component
+	selector, err := selectorFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid label selector")
 
-	logger := i.logger.With().Str("handler", "GetComponents").Logger()
-	logger.Debug().Interface("at", params.At).Send()
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	page, err := pageFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid page parameters")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	repo, includeDeleted, err := i.readRepository(ctx, &logger)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
 
-	res := dbSession.Preload("ActivelyAffectedBy", incidentJoin(params.At)).Find(&components)
+	components, next, err := repo.ListComponents(params.At, selector, page)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		logger.Warn().Err(err).Msg("invalid cursor")
 
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error loading components")
+		return echo.ErrBadRequest.WithInternal(err)
+	} else if err != nil {
+		logger.Error().Err(err).Msg("error loading components")
 
 		return echo.ErrInternalServerError
 	}
 
+	if includeIndirect {
+		var data []api.ComponentResponseData
+
+		data, err = withIndirectImpacts(repo, components, params.At)
+		if err != nil {
+			logger.Error().Err(err).Msg("error resolving indirect impacts")
+
+			return echo.ErrInternalServerError
+		}
+
+		return respondPage(ctx, data, next)
+	}
+
+	if includeDeleted {
+		data := make([]api.DeletableComponentResponseData, len(components))
+		for componentIndex, component := range components {
+			data[componentIndex] = component.ToDeletableAPIResponse()
+		}
+
+		return respondPage(ctx, data, next)
+	}
+
 	data := make([]apiServerDefinition.ComponentResponseData, len(components))
 	for componentIndex, component := range components {
 		data[componentIndex] = component.ToAPIResponse()
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.ComponentListResponse{ //nolint:wrapcheck
-		Data: data,
-	})
+	return respondPage(ctx, data, next)
 }
 
 // CreateComponent handles creation of components.
@@ -68,7 +101,7 @@ func (i *Implementation) CreateComponent(ctx echo.Context) error { //nolint:dupl
 	if request == (apiServerDefinition.CreateComponentJSONRequestBody{}) { //nolint: exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
 	logger.Debug().Interface("request", request).Send()
@@ -77,14 +110,33 @@ func (i *Implementation) CreateComponent(ctx echo.Context) error { //nolint:dupl
 	if err != nil {
 		logger.Warn().Err(err).Msg("error parsing request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(err)
 	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		transactionErr := repo.CreateComponent(component)
+		if transactionErr != nil {
+			return transactionErr //nolint:wrapcheck
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetComponent,
+			targetID:   component.ID.String(),
+			before:     nil,
+			after:      component,
+		})
+		if transactionErr != nil {
+			return transactionErr
+		}
 
-	res := dbSession.Create(&component)
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error creating component")
+		return publishEvent(repo, DbDef.EventComponentChanged, componentChange{
+			Operation: DbDef.AuditOperationCreate,
+			Component: component,
+		})
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("error in transaction")
 
 		return echo.ErrInternalServerError
 	}
@@ -102,55 +154,137 @@ func (i *Implementation) DeleteComponent(
 	logger := i.logger.With().Str("handler", "DeleteComponent").Interface("id", componentID).Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionErr := checkIfMatch(ctx, &logger, componentID.String(), componentVersion(repo, componentID))
+		if transactionErr != nil {
+			return transactionErr
+		}
 
-	res := dbSession.Where("id = ?", componentID).Delete(&DbDef.Component{}) //nolint: exhaustruct
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error deleting component")
+		dbComponent, transactionErr := repo.DeleteComponent(componentID, actor(ctx))
+		if transactionErr == nil && !ifMatch.holds(dbComponent.Version) {
+			transactionErr = storage.ErrConflict
+		}
 
-		return echo.ErrInternalServerError
-	}
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("component not found")
+
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrReferenced) {
+			logger.Warn().Msg("component impacted by incidents")
+
+			return echo.NewHTTPError(http.StatusConflict, "component impacted by incidents")
+		} else if errors.Is(transactionErr, storage.ErrConflict) {
+			logger.Warn().Msg("component changed concurrently")
+
+			return ifMatch.conflict()
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error deleting component")
+
+			return echo.ErrInternalServerError
+		}
 
-	if res.RowsAffected == 0 {
-		logger.Warn().Msg("component not found")
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationDelete,
+			targetType: DbDef.AuditTargetComponent,
+			targetID:   componentID.String(),
+			before:     dbComponent,
+			after:      nil,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording deletion")
 
-		return echo.ErrNotFound
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = publishEvent(repo, DbDef.EventComponentChanged, componentChange{
+			Operation: DbDef.AuditOperationDelete,
+			Component: dbComponent,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error publishing deletion")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
 
 	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
 }
 
-// GetComponent retrieves a specific component by ID.
+// GetComponent retrieves a specific component by ID, admins may request it, if it is deleted.
 func (i *Implementation) GetComponent(
 	ctx echo.Context,
 	componentID apiServerDefinition.ComponentIdPathParameter,
 	params apiServerDefinition.GetComponentParams,
 ) error {
-	var component DbDef.Component
-
 	logger := i.logger.With().Str("handler", "GetComponent").Interface("id", componentID).Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	includeIndirect, err := includeIndirectFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid dependency parameters")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
 
-	res := dbSession.Preload("ActivelyAffectedBy", incidentJoin(params.At)).Where("id = ?", componentID).First(&component)
-	if res.Error != nil {
-		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+	repo, includeDeleted, err := i.readRepository(ctx, &logger)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	component, err := repo.GetComponent(componentID, params.At)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
 			logger.Warn().Msg("component not found")
 
 			return echo.ErrNotFound
 		}
 
-		logger.Error().Err(res.Error).Msg("error loading component")
+		logger.Error().Err(err).Msg("error loading component")
 
 		return echo.ErrInternalServerError
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.ComponentResponse{ //nolint:wrapcheck
+	if includeIndirect {
+		var data []api.ComponentResponseData
+
+		data, err = withIndirectImpacts(repo, []*DbDef.Component{component}, params.At)
+		if err != nil {
+			logger.Error().Err(err).Msg("error resolving indirect impacts")
+
+			return echo.ErrInternalServerError
+		}
+
+		return sendTagged(ctx, componentID.String(), component.Version, api.ComponentResponse{Data: data[0]})
+	}
+
+	if includeDeleted {
+		return sendTagged(ctx, componentID.String(), component.Version, api.DeletableComponentResponse{
+			Data: component.ToDeletableAPIResponse(),
+		})
+	}
+
+	return sendTagged(ctx, componentID.String(), component.Version, apiServerDefinition.ComponentResponse{
 		Data: component.ToAPIResponse(),
 	})
 }
 
+// componentVersion loads the version of a component, for conditional changes.
+func componentVersion(repo storage.Repository, componentID DbDef.ID) func() (*int, error) {
+	return func() (*int, error) {
+		component, err := repo.GetComponent(componentID, nil)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return component.Version, nil
+	}
+}
+
 // UpdateComponent handles updates of components.
 func (i *Implementation) UpdateComponent( //nolint: funlen
 	ctx echo.Context,
@@ -170,7 +304,7 @@ func (i *Implementation) UpdateComponent( //nolint: funlen
 	if request == (apiServerDefinition.UpdateComponentJSONRequestBody{}) { //nolint:exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
 	logger.Debug().Interface("request", request).Send()
@@ -179,36 +313,55 @@ func (i *Implementation) UpdateComponent( //nolint: funlen
 	if err != nil {
 		logger.Warn().Err(err).Msg("error parsing request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(err)
 	}
 
 	component.ID = componentID
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
-
-	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
-		// Use echo or http errors, wrapped errors may cause echo to behave strangely.
-		var (
-			dbComponent      DbDef.Component
-			transactionError error
-		)
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionError := checkIfMatch(ctx, &logger, componentID.String(), componentVersion(repo, componentID))
+		if transactionError != nil {
+			return transactionError
+		}
 
-		dbComponent.ID = component.ID
+		dbComponent, updatedComponent, transactionError := repo.UpdateComponent(component)
+		if transactionError == nil && !ifMatch.holds(dbComponent.Version) {
+			transactionError = storage.ErrConflict
+		}
 
-		transactionError = dbTx.First(&dbComponent).Error
-		if errors.Is(transactionError, gorm.ErrRecordNotFound) {
+		if errors.Is(transactionError, storage.ErrNotFound) {
 			logger.Warn().Msg("component not found")
 
 			return echo.ErrNotFound
+		} else if errors.Is(transactionError, storage.ErrConflict) {
+			logger.Warn().Msg("component changed concurrently")
+
+			return ifMatch.conflict()
 		} else if transactionError != nil {
-			logger.Error().Err(transactionError).Msg("error loading component from database")
+			logger.Error().Err(transactionError).Msg("error updating component")
 
 			return echo.ErrInternalServerError
 		}
 
-		transactionError = dbTx.Updates(component).Error
+		transactionError = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationUpdate,
+			targetType: DbDef.AuditTargetComponent,
+			targetID:   component.ID.String(),
+			before:     dbComponent,
+			after:      updatedComponent,
+		})
 		if transactionError != nil {
-			logger.Error().Err(transactionError).Msg("error updating component")
+			logger.Error().Err(transactionError).Msg("error recording update")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionError = publishEvent(repo, DbDef.EventComponentChanged, componentChange{
+			Operation: DbDef.AuditOperationUpdate,
+			Component: updatedComponent,
+		})
+		if transactionError != nil {
+			logger.Error().Err(transactionError).Msg("error publishing update")
 
 			return echo.ErrInternalServerError
 		}
@@ -216,7 +369,6 @@ func (i *Implementation) UpdateComponent( //nolint: funlen
 		return nil
 	})
 	if err != nil {
-		// Don't wrap the echo errors.
 		return err //nolint:wrapcheck
 	}
 
@@ -2,17 +2,21 @@ package server_test
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
 	"github.com/SovereignCloudStack/status-page-api/pkg/db"
 	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -22,7 +26,7 @@ import (
 	"gorm.io/gorm"
 )
 
-var _ = Describe("Component", Ordered, func() {
+var _ = DescribeTableSubtree("Component", Ordered, func(dialect test.Dialect) {
 	const (
 		componentID        = "7fecf595-6352-4906-a0d8-b3243ee62ec8"
 		componentsEndpoint = "/components"
@@ -48,38 +52,120 @@ var _ = Describe("Component", Ordered, func() {
 		handlers *server.Implementation
 
 		// expected SQL
-		expectedComponentsQuery = regexp.QuoteMeta(`SELECT * FROM "components"`)
-		expectedComponentQuery  = regexp.QuoteMeta(
+		expectedComponentsQuery = regexp.QuoteMeta(
 			`SELECT *
 			FROM "components"
-			WHERE id = $1
+			WHERE "components"."deleted_at" IS NULL
+			ORDER BY COALESCE(display_name, '') ASC, id ASC
+			LIMIT $1`,
+		)
+		expectedComponentsQueryWithCursor = regexp.QuoteMeta(
+			`SELECT *
+			FROM "components"
+			WHERE (COALESCE(display_name, '') > $1 OR (COALESCE(display_name, '') = $2 AND id > $3))
+			AND "components"."deleted_at" IS NULL
+			ORDER BY COALESCE(display_name, '') ASC, id ASC
+			LIMIT $4`,
+		)
+		expectedComponentsQueryWithSelector = map[test.Dialect]string{
+			test.Postgres: regexp.QuoteMeta(
+				`SELECT *
+				FROM "components"
+				WHERE (components.labels @> $1 AND NOT COALESCE(components.labels ? $2, FALSE))
+				AND "components"."deleted_at" IS NULL
+				ORDER BY COALESCE(display_name, '') ASC, id ASC
+				LIMIT $3`,
+			),
+			test.SQLite: regexp.QuoteMeta(
+				`SELECT *
+				FROM "components"
+				WHERE (json_extract(components.labels, $1) IN ($2) AND json_type(components.labels, $3) IS NULL)
+				AND "components"."deleted_at" IS NULL
+				ORDER BY COALESCE(display_name, '') ASC, id ASC
+				LIMIT $4`,
+			),
+		}
+		expectedSelectorArgs = map[test.Dialect][]driver.Value{
+			test.Postgres: {`{"region":"datacenter-west"}`, "deprecated", 101},
+			test.SQLite:   {`$."region"`, "datacenter-west", `$."deprecated"`, 101},
+		}
+		expectedComponentQuery = regexp.QuoteMeta(
+			`SELECT *
+			FROM "components"
+			WHERE id = $1 AND "components"."deleted_at" IS NULL
 			ORDER BY "components"."id"
 			LIMIT $2`,
 		)
 		expectedComponentQueryWithTable = regexp.QuoteMeta(
 			`SELECT *
 			FROM "components"
-			WHERE "components"."id" = $1
+			WHERE "components"."deleted_at" IS NULL AND "components"."id" = $1
 			ORDER BY "components"."id"
 			LIMIT $2`,
 		)
 		expectedComponentInsert = regexp.QuoteMeta(
-			`INSERT INTO "components" ("display_name","labels","id")
-			VALUES ($1,$2,$3)`,
+			`INSERT INTO "components" ("display_name","labels","version","deleted_at","deleted_by","id")
+			VALUES ($1,$2,$3,$4,$5,$6)`,
+		)
+		expectedComponentImpactCount = regexp.QuoteMeta(
+			`SELECT count(*)
+			FROM "impacts"
+			WHERE component_id = $1 AND incident_id IN (SELECT id FROM incidents WHERE deleted_at IS NULL)`,
+		)
+		expectedComponentDelete = regexp.QuoteMeta(
+			`UPDATE "components" SET "deleted_at"=$1,"deleted_by"=$2,"version"=$3 WHERE id = $4 AND version = $5`,
 		)
-		expectedComponentDelete = regexp.QuoteMeta(`DELETE FROM "components" WHERE id = $1`)
-		expectedComponentUpdate = regexp.QuoteMeta(`UPDATE "components" SET "display_name"=$1 WHERE "id" = $2`)
+		expectedComponentUpdate = regexp.QuoteMeta(`UPDATE "components" SET "display_name"=$1,"version"=$2 WHERE version = $3 AND "components"."deleted_at" IS NULL AND "id" = $4`) //nolint:lll
 		expectedImpactQuery     = `SELECT .+
 		FROM "impacts"
-		LEFT JOIN "incidents" "Incident" ON "impacts"\."incident_id" = "Incident"\."id"
-		WHERE ended_at IS NULL
+		LEFT JOIN "incidents" "Incident" ON "impacts"\."incident_id" = "Incident"\."id" AND "Incident"\."deleted_at" IS NULL
+		WHERE impacts\.incident_id IN \(SELECT id FROM incidents WHERE deleted_at IS NULL\)
+		AND ended_at IS NULL
 		AND "impacts"\."component_id" = \$1`
-		expectedImpactQueryWithAt = `SELECT .+
+		expectedImpactsQuery = `SELECT .+
 		FROM "impacts"
-		LEFT JOIN "incidents" "Incident" ON "impacts"\."incident_id" = "Incident"\."id"
-		WHERE \(began_at < \$1 AND ended_at > \$2\)
-		OR \(began_at < \$3 AND ended_at IS NULL\)
-		AND "impacts"\."component_id" = \$4`
+		LEFT JOIN "incidents" "Incident" ON "impacts"\."incident_id" = "Incident"\."id" AND "Incident"\."deleted_at" IS NULL
+		WHERE impacts\.incident_id IN \(SELECT id FROM incidents WHERE deleted_at IS NULL\)
+		AND ended_at IS NULL
+		AND "impacts"\."component_id" IN \(\$1,\$2\)`
+		expectedImpactQueryWithAt = `SELECT incident_revision_impacts\.\*
+		FROM "incident_revision_impacts"
+		JOIN incident_revisions .+
+		WHERE \(incident_revisions\.revision = COALESCE\(.+ recorded\.recorded_at <= \$1\), .+\)\)
+		AND incident_revisions\.began_at < \$2
+		AND \(incident_revisions\.ended_at > \$3 OR incident_revisions\.ended_at IS NULL\)
+		AND incident_revision_impacts\.component_id IN \(\$4\)`
+		expectedComponentImpactsQuery = `SELECT .+
+		FROM "impacts"
+		LEFT JOIN "incidents" "Incident" ON "impacts"\."incident_id" = "Incident"\."id" AND "Incident"\."deleted_at" IS NULL
+		WHERE impacts\.component_id = \$1
+		AND impacts\.incident_id IN \(SELECT id FROM incidents WHERE deleted_at IS NULL\)
+		AND began_at < \$2
+		AND \(ended_at > \$3 OR ended_at IS NULL\)
+		ORDER BY began_at`
+		expectedSeveritiesQuery   = regexp.QuoteMeta(`SELECT * FROM "severities" ORDER BY "value" ASC`)
+		expectedDependenciesQuery = regexp.QuoteMeta(
+			`SELECT * FROM "component_dependencies"
+			WHERE component_id IN (SELECT "id" FROM "components") AND depends_on_id IN (SELECT "id" FROM "components")
+			ORDER BY component_id,depends_on_id`,
+		)
+		expectedUsableComponentsCount = regexp.QuoteMeta(`SELECT count(*) FROM "components" WHERE id IN (`) +
+			`.+` + regexp.QuoteMeta(`) AND deleted_at IS NULL`)
+		expectedDependencyInsert = regexp.QuoteMeta(
+			`INSERT INTO "component_dependencies" ("component_id","depends_on_id") VALUES ($1,$2)`,
+		)
+		expectedComponentGroupQuery = regexp.QuoteMeta(
+			`SELECT * FROM "component_groups" WHERE id = $1 ORDER BY "component_groups"."id" LIMIT $2`,
+		)
+		expectedComponentGroupMembersQuery = regexp.QuoteMeta(
+			`SELECT * FROM "component_group_members"
+			WHERE component_id IN (SELECT "id" FROM "components" WHERE "components"."deleted_at" IS NULL)
+			AND "component_group_members"."group_id" = $1
+			ORDER BY component_id`,
+		)
+		expectedComponentGroupMemberInsert = regexp.QuoteMeta(
+			`INSERT INTO "component_group_members" ("group_id","component_id") VALUES ($1,$2)`,
+		)
 
 		// UUID of the test component
 		componentUUID = uuid.MustParse(componentID)
@@ -103,8 +189,8 @@ var _ = Describe("Component", Ordered, func() {
 		// setup database and mock before each test
 		var gormDB *gorm.DB
 
-		sqlDB, sqlMock, gormDB = test.MustMockGorm(gormLogger)
-		handlers = server.New(gormDB, handlerLogger)
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
 
 		// create mock rows before each test
 		componentRows = sqlmock.
@@ -147,7 +233,7 @@ var _ = Describe("Component", Ordered, func() {
 		Context("without data", func() {
 			It("should return an empty list of components", func() {
 				// Arrange
-				sqlMock.ExpectQuery(expectedComponentsQuery).WillReturnRows(componentRows)
+				sqlMock.ExpectQuery(expectedComponentsQuery).WithArgs(101).WillReturnRows(componentRows)
 
 				expectedResult, _ := json.Marshal(apiServerDefinition.ComponentListResponse{
 					Data: []apiServerDefinition.ComponentResponseData{},
@@ -169,6 +255,7 @@ var _ = Describe("Component", Ordered, func() {
 					// Arrange
 					sqlMock.
 						ExpectQuery(expectedComponentsQuery).
+						WithArgs(101).
 						WillReturnRows(
 							componentRows.AddRow(component.ID, component.DisplayName, component.Labels),
 						)
@@ -199,13 +286,14 @@ var _ = Describe("Component", Ordered, func() {
 					params.At = &now
 					sqlMock.
 						ExpectQuery(expectedComponentsQuery).
+						WithArgs(101).
 						WillReturnRows(
 							componentRows.AddRow(component.ID, component.DisplayName, component.Labels),
 						)
 					sqlMock.
 						ExpectQuery(expectedImpactQueryWithAt).
 						WithArgs(now, now, now, componentID).
-						WillReturnRows(impactRows, incidentRows)
+						WillReturnRows(impactRows)
 
 					expectedResult, _ := json.Marshal(apiServerDefinition.ComponentListResponse{
 						Data: []apiServerDefinition.ComponentResponseData{
@@ -224,10 +312,144 @@ var _ = Describe("Component", Ordered, func() {
 			})
 		})
 
+		Context("with limit param", func() {
+			It("should return the first page and link the next one", func() {
+				// Arrange
+				const nextComponentID = "b1f2c4d6-8e9a-4b3c-9d5e-7f6a8b9c0d1e"
+
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodGet,
+					componentsEndpoint+"?limit=1",
+					nil,
+				)
+				sqlMock.
+					ExpectQuery(expectedComponentsQuery).
+					WithArgs(2).
+					WillReturnRows(
+						componentRows.
+							AddRow(component.ID, component.DisplayName, component.Labels).
+							AddRow(nextComponentID, "Storage", nil),
+					)
+				sqlMock.
+					ExpectQuery(expectedImpactsQuery).
+					WithArgs(componentID, nextComponentID).
+					WillReturnRows(impactRows, incidentRows)
+
+				cursor := storage.Cursor{Key: "Storage", ID: componentID}
+				nextLink := componentsEndpoint + "?cursor=" + cursor.String() + "&limit=1"
+
+				expectedResult, _ := json.Marshal(api.PageResponse[apiServerDefinition.ComponentResponseData]{
+					Data: []apiServerDefinition.ComponentResponseData{
+						component.ToAPIResponse(),
+					},
+					Next: &nextLink,
+				})
+
+				// Act
+				err := handlers.GetComponents(ctx, params)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Header().Get("Link")).Should(Equal("<" + nextLink + `>; rel="next"`))
+				Ω(strings.Trim(res.Body.String(), "\n")).Should(Equal(string(expectedResult)))
+			})
+		})
+
+		Context("with cursor param", func() {
+			It("should continue after the cursor", func() {
+				// Arrange
+				cursor := storage.Cursor{Key: "Storage", ID: componentID}
+
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodGet,
+					componentsEndpoint+"?cursor="+cursor.String(),
+					nil,
+				)
+				sqlMock.
+					ExpectQuery(expectedComponentsQueryWithCursor).
+					WithArgs("Storage", "Storage", componentUUID, 101).
+					WillReturnRows(componentRows)
+
+				// Act
+				err := handlers.GetComponents(ctx, params)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Header().Get("Link")).Should(BeEmpty())
+			})
+		})
+
+		Context("with label selector", func() {
+			It("should return the matching components", func() {
+				// Arrange
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodGet,
+					componentsEndpoint+"?labelSelector="+url.QueryEscape("region=datacenter-west,!deprecated"),
+					nil,
+				)
+				sqlMock.
+					ExpectQuery(expectedComponentsQueryWithSelector[dialect]).
+					WithArgs(expectedSelectorArgs[dialect]...).
+					WillReturnRows(componentRows)
+
+				// Act
+				err := handlers.GetComponents(ctx, params)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+			})
+
+			It("should return 400 bad request for invalid selectors", func() {
+				// Arrange
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodGet,
+					componentsEndpoint+"?labelSelector="+url.QueryEscape("az in (1,2"),
+					nil,
+				)
+
+				// Act
+				err := handlers.GetComponents(ctx, params)
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+			})
+		})
+
+		Context("with invalid page params", func() {
+			DescribeTable("should return 400 bad request",
+				func(query string) {
+					// Arrange
+					ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+						echoLogger,
+						http.MethodGet,
+						componentsEndpoint+"?"+query,
+						nil,
+					)
+
+					// Act
+					err := handlers.GetComponents(ctx, params)
+
+					// Assert
+					Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				},
+				Entry("with zero limit", "limit=0"),
+				Entry("with limit above maximum", "limit=1001"),
+				Entry("with malformed cursor", "cursor=not-a-cursor"),
+				Entry("with cursor of another listing", "cursor="+storage.Cursor{Key: "Storage", ID: "0"}.String()),
+			)
+		})
+
 		Context("with database error", func() {
 			It("should return 500 internal server error", func() {
 				// Arrange
-				sqlMock.ExpectQuery(expectedComponentsQuery).WillReturnError(test.ErrTestError)
+				sqlMock.ExpectQuery(expectedComponentsQuery).WithArgs(101).WillReturnError(test.ErrTestError)
 
 				// Act
 				err := handlers.GetComponents(ctx, params)
@@ -262,6 +484,8 @@ var _ = Describe("Component", Ordered, func() {
 				// Arrange
 				sqlMock.ExpectBegin()
 				sqlMock.ExpectExec(expectedComponentInsert).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationCreate, db.AuditTargetComponent, sqlmock.AnyArg())
+				expectEvent(dialect, sqlMock, db.EventComponentChanged)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -289,7 +513,7 @@ var _ = Describe("Component", Ordered, func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
 			})
 		})
 
@@ -316,6 +540,14 @@ var _ = Describe("Component", Ordered, func() {
 			res *httptest.ResponseRecorder
 		)
 
+		// expectComponentReleased expects the check for impacts and the removal of dependencies and memberships.
+		expectComponentReleased := func() {
+			sqlMock.
+				ExpectQuery(expectedComponentImpactCount).
+				WithArgs(componentID).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		}
+
 		BeforeEach(func() {
 			// setup context and response before every test
 			ctx, res = test.MustCreateEchoContextAndResponseWriter(
@@ -330,7 +562,17 @@ var _ = Describe("Component", Ordered, func() {
 			It("should return 204 no content", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.ExpectExec(expectedComponentDelete).WithArgs(componentID).WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.
+					ExpectQuery(expectedComponentQuery).
+					WithArgs(componentID, 1).
+					WillReturnRows(componentRows.AddRow(componentID, component.DisplayName, nil))
+				expectComponentReleased()
+				sqlMock.
+					ExpectExec(expectedComponentDelete).
+					WithArgs(sqlmock.AnyArg(), "anonymous", 2, componentID, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationDelete, db.AuditTargetComponent, componentID)
+				expectEvent(dialect, sqlMock, db.EventComponentChanged)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -343,12 +585,35 @@ var _ = Describe("Component", Ordered, func() {
 			})
 		})
 
-		Context("without affected row", func() {
+		Context("with impacts of incidents", func() {
+			It("should return 409 conflict", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedComponentQuery).
+					WithArgs(componentID, 1).
+					WillReturnRows(componentRows.AddRow(componentID, component.DisplayName, nil))
+				sqlMock.
+					ExpectQuery(expectedComponentImpactCount).
+					WithArgs(componentID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.DeleteComponent(ctx, componentUUID)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(HaveField("Code", http.StatusConflict))
+			})
+		})
+
+		Context("without existing component", func() {
 			It("should return 404 not found", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.ExpectExec(expectedComponentDelete).WithArgs(componentID).WillReturnResult(sqlmock.NewResult(0, 0))
-				sqlMock.ExpectCommit()
+				sqlMock.ExpectQuery(expectedComponentQuery).WithArgs(componentID, 1).WillReturnRows(componentRows)
+				sqlMock.ExpectRollback()
 
 				// Act
 				err := handlers.DeleteComponent(ctx, componentUUID)
@@ -363,7 +628,15 @@ var _ = Describe("Component", Ordered, func() {
 			It("should return 500 internal server error", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.ExpectExec(expectedComponentDelete).WithArgs(componentID).WillReturnError(test.ErrTestError)
+				sqlMock.
+					ExpectQuery(expectedComponentQuery).
+					WithArgs(componentID, 1).
+					WillReturnRows(componentRows.AddRow(componentID, component.DisplayName, nil))
+				expectComponentReleased()
+				sqlMock.
+					ExpectExec(expectedComponentDelete).
+					WithArgs(sqlmock.AnyArg(), "anonymous", 2, componentID, 1).
+					WillReturnError(test.ErrTestError)
 				sqlMock.ExpectRollback()
 
 				// Act
@@ -440,7 +713,7 @@ var _ = Describe("Component", Ordered, func() {
 					sqlMock.
 						ExpectQuery(expectedImpactQueryWithAt).
 						WithArgs(now, now, now, componentID).
-						WillReturnRows(impactRows, incidentRows)
+						WillReturnRows(impactRows)
 
 					expectedResult, _ := json.Marshal(apiServerDefinition.ComponentResponse{
 						Data: component.ToAPIResponse(),
@@ -486,6 +759,251 @@ var _ = Describe("Component", Ordered, func() {
 		})
 	})
 
+	Describe("GetComponentAvailability", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+
+			start  = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+			end    = start.AddDate(0, 0, 1)
+			params api.GetAvailabilityParams
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodGet,
+				componentEndpoint+"/availability",
+				nil,
+			)
+
+			params = api.GetAvailabilityParams{Start: &start, End: &end}
+		})
+
+		Context("with valid data", func() {
+			It("should return the availability", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedComponentQuery).
+					WithArgs(componentID, 1).
+					WillReturnRows(componentRows.AddRow(component.ID, component.DisplayName, component.Labels))
+				sqlMock.ExpectQuery(expectedImpactQuery).WithArgs(componentID).WillReturnRows(impactRows)
+				sqlMock.
+					ExpectQuery(expectedComponentImpactsQuery).
+					WithArgs(componentID, end, start).
+					WillReturnRows(sqlmock.NewRows([]string{"incident_id", "component_id", "impact_type_id"}))
+				sqlMock.
+					ExpectQuery(expectedSeveritiesQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"display_name", "value"}).AddRow("broken", 100))
+
+				// Act
+				err := handlers.GetComponentAvailability(ctx, componentUUID, params)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+
+				var response api.AvailabilityResponse
+				Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+				Ω(response.Buckets).Should(HaveLen(1))
+				Ω(response.Total.Availability).Should(HaveValue(BeNumerically("==", 100)))
+				Ω(response.Total.Severities).Should(Equal(map[string]float64{"broken": 0}))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedComponentQuery).
+					WithArgs(componentID, 1).
+					WillReturnRows(componentRows.AddRow(component.ID, component.DisplayName, component.Labels))
+				sqlMock.ExpectQuery(expectedImpactQuery).WithArgs(componentID).WillReturnRows(impactRows)
+				sqlMock.ExpectQuery(expectedComponentImpactsQuery).WillReturnError(test.ErrTestError)
+
+				// Act
+				err := handlers.GetComponentAvailability(ctx, componentUUID, params)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+
+	Describe("CreateComponentDependency", func() {
+		const dependencyID = "a4f6b1c5-3f2e-4d8b-9c7a-1e2d3c4b5a69"
+
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+
+			dependencyUUID = uuid.MustParse(dependencyID)
+
+			// expectDependencyLock expects the lock of the dependencies, which SQLite doesn't need.
+			expectDependencyLock = func() {
+				if dialect == test.Postgres {
+					sqlMock.
+						ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+						WithArgs(sqlmock.AnyArg()).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			}
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodPut,
+				componentEndpoint+"/dependencies/"+dependencyID,
+				nil,
+			)
+		})
+
+		Context("without cycle", func() {
+			It("should return 204 no content", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				expectDependencyLock()
+				sqlMock.
+					ExpectQuery(expectedDependenciesQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"component_id", "depends_on_id"}))
+				sqlMock.
+					ExpectQuery(expectedUsableComponentsCount).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				sqlMock.
+					ExpectExec(expectedDependencyInsert).
+					WithArgs(componentID, dependencyID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(
+					sqlMock, db.AuditOperationCreate, db.AuditTargetComponentDependency, componentID+"/"+dependencyID,
+				)
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.CreateComponentDependency(ctx, componentUUID, dependencyUUID)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("with cycle", func() {
+			It("should return 409 conflict", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				expectDependencyLock()
+				sqlMock.
+					ExpectQuery(expectedDependenciesQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"component_id", "depends_on_id"}).AddRow(dependencyID, componentID))
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.CreateComponentDependency(ctx, componentUUID, dependencyUUID)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(HaveField("Code", http.StatusConflict))
+			})
+		})
+	})
+
+	Describe("AddComponentGroupMember", func() {
+		const groupID = "0d7c3b5e-9f1a-4c2b-8e6d-5a4b3c2d1e0f"
+
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+
+			groupUUID = uuid.MustParse(groupID)
+			groupRows *sqlmock.Rows
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodPut,
+				"/componentgroups/"+groupID+"/components/"+componentID,
+				nil,
+			)
+			groupRows = sqlmock.
+				NewRows([]string{"id", "display_name", "description", "parent_id", "order"}).
+				AddRow(groupID, "Infrastructure", nil, nil, 0)
+		})
+
+		Context("with new member", func() {
+			It("should return 204 no content", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedComponentGroupQuery).WithArgs(groupID, 1).WillReturnRows(groupRows)
+				sqlMock.
+					ExpectQuery(expectedComponentGroupMembersQuery).
+					WithArgs(groupID).
+					WillReturnRows(sqlmock.NewRows([]string{"group_id", "component_id"}))
+				sqlMock.
+					ExpectQuery(expectedUsableComponentsCount).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				sqlMock.
+					ExpectExec(expectedComponentGroupMemberInsert).
+					WithArgs(groupID, componentID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(
+					sqlMock, db.AuditOperationCreate, db.AuditTargetComponentGroupMember, groupID+"/"+componentID,
+				)
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.AddComponentGroupMember(ctx, groupUUID, componentUUID)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("with existing member", func() {
+			It("should return 204 no content without changes", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedComponentGroupQuery).WithArgs(groupID, 1).WillReturnRows(groupRows)
+				sqlMock.
+					ExpectQuery(expectedComponentGroupMembersQuery).
+					WithArgs(groupID).
+					WillReturnRows(sqlmock.NewRows([]string{"group_id", "component_id"}).AddRow(groupID, componentID))
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.AddComponentGroupMember(ctx, groupUUID, componentUUID)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("with unknown group", func() {
+			It("should return 404 not found", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedComponentGroupQuery).
+					WithArgs(groupID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.AddComponentGroupMember(ctx, groupUUID, componentUUID)
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrNotFound))
+			})
+		})
+	})
+
 	Describe("UpdateComponent", func() {
 		var (
 			ctx echo.Context
@@ -520,8 +1038,17 @@ var _ = Describe("Component", Ordered, func() {
 					)
 				sqlMock.
 					ExpectExec(expectedComponentUpdate).
-					WithArgs("Network", componentID).
+					WithArgs("Network", 2, 1, componentID).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.
+					ExpectQuery(expectedComponentQueryWithTable).
+					WithArgs(componentID, 1).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "display_name", "labels"}).
+						AddRow(component.ID, "Network", component.Labels),
+					)
+				expectAuditEntry(sqlMock, db.AuditOperationUpdate, db.AuditTargetComponent, componentID)
+				expectEvent(dialect, sqlMock, db.EventComponentChanged)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -549,7 +1076,7 @@ var _ = Describe("Component", Ordered, func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
 			})
 		})
 
@@ -592,4 +1119,81 @@ var _ = Describe("Component", Ordered, func() {
 			})
 		})
 	})
+}, test.DialectEntries())
+
+var _ = Describe("Component with memory storage", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		store *storage.Memory
+
+		// actual functions under test
+		handlers *server.Implementation
+	)
+
+	BeforeEach(func() {
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store = storage.NewMemory()
+		handlers = server.New(store, nil, nil, nil, handlerLogger)
+	})
+
+	It("should create, read and delete a component", func() {
+		// Arrange
+		ctx, res := test.MustCreateEchoContextAndResponseWriter(
+			echoLogger,
+			http.MethodPost,
+			"/components",
+			apiServerDefinition.Component{
+				DisplayName: test.Ptr("Storage"),
+			},
+		)
+
+		// Act
+		err := handlers.CreateComponent(ctx)
+
+		// Assert
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Code).Should(Equal(http.StatusCreated))
+
+		var created apiServerDefinition.IdResponse
+		Ω(json.Unmarshal(res.Body.Bytes(), &created)).Should(Succeed())
+
+		// Act
+		ctx, res = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/components", nil)
+		err = handlers.GetComponent(ctx, created.Id, apiServerDefinition.GetComponentParams{})
+
+		// Assert
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Code).Should(Equal(http.StatusOK))
+		Ω(res.Body.String()).Should(ContainSubstring(`"displayName":"Storage"`))
+
+		// Act
+		ctx, res = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodDelete, "/components", nil)
+		err = handlers.DeleteComponent(ctx, created.Id)
+
+		// Assert
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Code).Should(Equal(http.StatusNoContent))
+		Ω(store.AuditEntries()).Should(HaveLen(2))
+		Ω(store.Events()).Should(HaveLen(2))
+
+		ctx, _ = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/components", nil)
+		err = handlers.GetComponent(ctx, created.Id, apiServerDefinition.GetComponentParams{})
+		Ω(err).Should(Equal(echo.ErrNotFound))
+	})
+
+	It("should not serve routes requiring a database", func() {
+		// Arrange
+		ctx, _ := test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/audit", nil)
+
+		// Act
+		err := handlers.GetAuditEntries(ctx, api.GetAuditEntriesParams{})
+
+		// Assert
+		Ω(err).Should(Equal(echo.ErrNotFound))
+	})
 })
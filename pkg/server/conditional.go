@@ -0,0 +1,199 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// headerETag is the validator of the response representation.
+	headerETag = "ETag"
+	// headerIfNoneMatch lists the validators of representations cached by the client.
+	headerIfNoneMatch = "If-None-Match"
+	// headerIfMatch lists the validators of representations a change is based on.
+	headerIfMatch = "If-Match"
+
+	// cacheMaxAge is the time clients may use a cacheable response without revalidating it.
+	cacheMaxAge = "max-age=60"
+)
+
+// entityTag derives a strong entity tag from the representation.
+func entityTag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matchesEntityTag reports, if the etag is part of an `If-None-Match` header value, using weak comparison.
+func matchesEntityTag(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesVersion reports, if the `If-Match` header value lists an entity tag of the version of the resource,
+// as derived by [versionedEntityTag], using strong comparison. Weak entity tags never match.
+func matchesVersion(ifMatch string, resourceID string, version int) bool {
+	prefix := fmt.Sprintf(`"%s-%d-`, resourceID, version)
+
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || (strings.HasPrefix(candidate, prefix) && strings.HasSuffix(candidate, `"`)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// versionedEntityTag derives the strong entity tag of a representation of a resource from its ID and version.
+// The digest of the representation distinguishes representations of the same version, e.g. with query parameters
+// or impacts of incidents, so they are revalidated by `If-None-Match`, but it is ignored by `If-Match`.
+func versionedEntityTag(resourceID string, version *int, body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return fmt.Sprintf(`"%s-%d-%s"`, resourceID, versionOf(version), hex.EncodeToString(sum[:8]))
+}
+
+// sendCacheable sends the body with `ETag` and `Last-Modified` validators
+// and answers conditional requests of clients with an up-to-date copy with 304 Not Modified.
+// A zero lastModified omits the header.
+func sendCacheable(ctx echo.Context, contentType string, body []byte, lastModified time.Time) error {
+	etag := entityTag(body)
+
+	header := ctx.Response().Header()
+	header.Set(headerETag, etag)
+	header.Set(echo.HeaderCacheControl, cacheMaxAge)
+
+	// A modification in the future can't be reported, i.e. for announced changes.
+	now := time.Now()
+	if lastModified.After(now) {
+		lastModified = now
+	}
+
+	if !lastModified.IsZero() {
+		header.Set(echo.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	request := ctx.Request()
+	ifNoneMatch := request.Header.Get(headerIfNoneMatch)
+
+	// If-Modified-Since is ignored, if If-None-Match is present.
+	if ifNoneMatch != "" {
+		if matchesEntityTag(ifNoneMatch, etag) {
+			return ctx.NoContent(http.StatusNotModified) //nolint:wrapcheck
+		}
+	} else if !lastModified.IsZero() {
+		ifModifiedSince, err := http.ParseTime(request.Header.Get(echo.HeaderIfModifiedSince))
+		if err == nil && !lastModified.Truncate(time.Second).After(ifModifiedSince) {
+			return ctx.NoContent(http.StatusNotModified) //nolint:wrapcheck
+		}
+	}
+
+	return ctx.Blob(http.StatusOK, contentType, body) //nolint:wrapcheck
+}
+
+// sendTagged sends the response as JSON with an `ETag` validator derived from the version of the resource
+// and answers clients with an up-to-date copy with 304 Not Modified.
+// Changes of the resource can be made conditional on the validator by `If-Match`, see [checkIfMatch].
+func sendTagged(ctx echo.Context, resourceID string, version *int, response interface{}) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("error encoding response: %w", err)
+	}
+
+	etag := versionedEntityTag(resourceID, version, body)
+	ctx.Response().Header().Set(headerETag, etag)
+
+	ifNoneMatch := ctx.Request().Header.Get(headerIfNoneMatch)
+	if ifNoneMatch != "" && matchesEntityTag(ifNoneMatch, etag) {
+		return ctx.NoContent(http.StatusNotModified) //nolint:wrapcheck
+	}
+
+	return ctx.JSON(http.StatusOK, response) //nolint:wrapcheck
+}
+
+// precondition is the state of a resource, a conditional change is based on.
+type precondition struct {
+	// conditional is set, if the request has an `If-Match` header.
+	conditional bool
+	// version is the version of the resource matching the header.
+	version int
+}
+
+// checkIfMatch compares the `If-Match` header of the request with the current version of the resource,
+// as tagged by [sendTagged], so the tags of every representation of the version match.
+// The load function returns the version of the resource, it is only called with header.
+// The returned echo errors are 404 Not Found for missing resources and 412 Precondition Failed on mismatch.
+func checkIfMatch(
+	ctx echo.Context,
+	logger *zerolog.Logger,
+	resourceID string,
+	load func() (*int, error),
+) (precondition, error) {
+	var unconditional precondition
+
+	ifMatch := ctx.Request().Header.Get(headerIfMatch)
+	if ifMatch == "" {
+		return unconditional, nil
+	}
+
+	version, err := load()
+	if errors.Is(err, storage.ErrNotFound) {
+		logger.Warn().Msg("resource not found")
+
+		return unconditional, echo.ErrNotFound
+	} else if err != nil {
+		logger.Error().Err(err).Msg("error loading resource")
+
+		return unconditional, echo.ErrInternalServerError
+	}
+
+	if !matchesVersion(ifMatch, resourceID, versionOf(version)) {
+		logger.Warn().Str("ifMatch", ifMatch).Msg("precondition failed")
+
+		return unconditional, echo.NewHTTPError(http.StatusPreconditionFailed)
+	}
+
+	return precondition{conditional: true, version: versionOf(version)}, nil
+}
+
+// holds reports, if the resource changed has still the version matched by the request.
+// Unconditional requests hold for any version.
+func (p precondition) holds(version *int) bool {
+	return !p.conditional || p.version == versionOf(version)
+}
+
+// conflict returns the echo error for a resource changed concurrently,
+// 412 Precondition Failed for conditional requests and 409 Conflict otherwise.
+func (p precondition) conflict() error {
+	if p.conditional {
+		return echo.NewHTTPError(http.StatusPreconditionFailed)
+	}
+
+	return echo.NewHTTPError(http.StatusConflict)
+}
+
+// versionOf returns the version of a resource, unset versions count as the initial version 1.
+func versionOf(version *int) int {
+	if version == nil {
+		return 1
+	}
+
+	return *version
+}
@@ -0,0 +1,291 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Conditional requests", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		repo storage.Repository
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		network *db.Component
+
+		newContext = func(
+			method string, body interface{}, header, value string,
+		) (echo.Context, *httptest.ResponseRecorder) {
+			ctx, res := test.MustCreateEchoContextAndResponseWriter(echoLogger, method, "/", body)
+			if header != "" {
+				ctx.Request().Header.Set(header, value)
+			}
+
+			return ctx, res
+		}
+
+		createImpactingIncident = func() {
+			impactType := &db.ImpactType{DisplayName: test.Ptr("Connectivity issues")}
+			Ω(repo.CreateImpactType(impactType)).Should(Succeed())
+			Ω(repo.CreateIncident(&db.Incident{
+				DisplayName: test.Ptr("Router failure"),
+				BeganAt:     test.Ptr(time.Now().Add(-time.Hour)),
+				Affects: &[]db.Impact{
+					{ComponentID: &network.ID, ImpactTypeID: &impactType.ID, Severity: test.Ptr(50)},
+				},
+			})).Should(Succeed())
+		}
+
+		currentETag = func() string {
+			ctx, res := newContext(http.MethodGet, nil, "", "")
+			Ω(handlers.GetComponent(ctx, network.ID, apiServerDefinition.GetComponentParams{})).Should(Succeed())
+
+			return res.Header().Get("ETag")
+		}
+	)
+
+	BeforeEach(func() {
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store := storage.NewMemory()
+		repo = store.WithContext(context.Background())
+		handlers = server.New(store, nil, nil, nil, handlerLogger)
+
+		network = &db.Component{DisplayName: test.Ptr("Network")}
+		Ω(repo.CreateComponent(network)).Should(Succeed())
+	})
+
+	Describe("GET", func() {
+		It("should send an entity tag", func() {
+			// Act
+			etag := currentETag()
+
+			// Assert
+			Ω(etag).Should(MatchRegexp(`^"` + network.ID.String() + `-1-[0-9a-f]{16}"$`))
+		})
+
+		It("should answer with not modified for a matching If-None-Match", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, nil, "If-None-Match", currentETag())
+
+			// Act
+			err := handlers.GetComponent(ctx, network.ID, apiServerDefinition.GetComponentParams{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusNotModified))
+			Ω(res.Body.String()).Should(BeEmpty())
+		})
+
+		It("should send the representation for outdated entity tags", func() {
+			// Arrange
+			etag := currentETag()
+
+			_, _, err := repo.UpdateComponent(&db.Component{
+				Model:       db.Model{ID: network.ID},
+				DisplayName: test.Ptr("Backbone"),
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			ctx, res := newContext(http.MethodGet, nil, "If-None-Match", etag)
+
+			// Act
+			err = handlers.GetComponent(ctx, network.ID, apiServerDefinition.GetComponentParams{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(res.Header().Get("ETag")).ShouldNot(Equal(etag))
+		})
+
+		It("should send the representation, if impacts of incidents changed", func() {
+			// Arrange
+			etag := currentETag()
+
+			createImpactingIncident()
+
+			ctx, res := newContext(http.MethodGet, nil, "If-None-Match", etag)
+
+			// Act
+			err := handlers.GetComponent(ctx, network.ID, apiServerDefinition.GetComponentParams{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("PATCH", func() {
+		It("should apply changes with a matching If-Match", func() {
+			// Arrange
+			ctx, res := newContext(
+				http.MethodPatch, apiServerDefinition.Component{DisplayName: test.Ptr("Backbone")}, "If-Match", currentETag(),
+			)
+
+			// Act
+			err := handlers.UpdateComponent(ctx, network.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+
+			component, err := repo.GetComponent(network.ID, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*component.DisplayName).Should(Equal("Backbone"))
+			Ω(*component.Version).Should(Equal(2))
+		})
+
+		It("should refuse changes based on an outdated representation", func() {
+			// Arrange
+			etag := currentETag()
+
+			first, _ := newContext(
+				http.MethodPatch, apiServerDefinition.Component{DisplayName: test.Ptr("Backbone")}, "If-Match", etag,
+			)
+			Ω(handlers.UpdateComponent(first, network.ID)).Should(Succeed())
+
+			second, _ := newContext(
+				http.MethodPatch, apiServerDefinition.Component{DisplayName: test.Ptr("Uplink")}, "If-Match", etag,
+			)
+
+			// Act
+			err := handlers.UpdateComponent(second, network.ID)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusPreconditionFailed))
+
+			component, err := repo.GetComponent(network.ID, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*component.DisplayName).Should(Equal("Backbone"))
+		})
+
+		It("should apply changes with the entity tag of a representation with query parameters", func() {
+			// Arrange
+			get, res := newContext(http.MethodGet, nil, "", "")
+			Ω(handlers.GetComponent(get, network.ID, apiServerDefinition.GetComponentParams{
+				At: test.Ptr(time.Now()),
+			})).Should(Succeed())
+
+			ctx, _ := newContext(
+				http.MethodPatch,
+				apiServerDefinition.Component{DisplayName: test.Ptr("Backbone")},
+				"If-Match",
+				res.Header().Get("ETag"),
+			)
+
+			// Act
+			err := handlers.UpdateComponent(ctx, network.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should apply changes, if only impacts of incidents changed", func() {
+			// Arrange
+			etag := currentETag()
+
+			createImpactingIncident()
+
+			ctx, _ := newContext(
+				http.MethodPatch, apiServerDefinition.Component{DisplayName: test.Ptr("Backbone")}, "If-Match", etag,
+			)
+
+			// Act
+			err := handlers.UpdateComponent(ctx, network.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should refuse entity tags of other resources", func() {
+			// Arrange
+			storage := &db.Component{DisplayName: test.Ptr("Storage")}
+			Ω(repo.CreateComponent(storage)).Should(Succeed())
+
+			get, res := newContext(http.MethodGet, nil, "", "")
+			Ω(handlers.GetComponent(get, storage.ID, apiServerDefinition.GetComponentParams{})).Should(Succeed())
+
+			ctx, _ := newContext(
+				http.MethodPatch,
+				apiServerDefinition.Component{DisplayName: test.Ptr("Backbone")},
+				"If-Match",
+				res.Header().Get("ETag"),
+			)
+
+			// Act
+			err := handlers.UpdateComponent(ctx, network.ID)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusPreconditionFailed))
+		})
+
+		It("should apply changes with a wildcard If-Match", func() {
+			// Arrange
+			ctx, _ := newContext(
+				http.MethodPatch, apiServerDefinition.Component{DisplayName: test.Ptr("Backbone")}, "If-Match", "*",
+			)
+
+			// Act
+			err := handlers.UpdateComponent(ctx, network.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("DELETE", func() {
+		It("should refuse weak entity tags", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodDelete, nil, "If-Match", "W/"+currentETag())
+
+			// Act
+			err := handlers.DeleteComponent(ctx, network.ID)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusPreconditionFailed))
+			Ω(repo.GetComponent(network.ID, nil)).ShouldNot(BeNil())
+		})
+
+		It("should delete with a matching If-Match", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodDelete, nil, "If-Match", currentETag())
+
+			// Act
+			err := handlers.DeleteComponent(ctx, network.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+		})
+
+		It("should return not found for unknown resources", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodDelete, nil, "If-Match", "*")
+
+			// Act
+			err := handlers.DeleteComponent(ctx, uuid.New())
+
+			// Assert
+			Ω(err).Should(Equal(echo.ErrNotFound))
+		})
+	})
+})
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// readRepository opens the repository for reads of incidents and components.
+// It includes the deleted ones, if requested by the [api.DeletionParams] of an admin.
+// The parameters are not part of the OpenAPI spec, so they are bound separately.
+func (i *Implementation) readRepository(
+	ctx echo.Context,
+	logger *zerolog.Logger,
+) (storage.Repository, bool, error) { //nolint:ireturn
+	var params api.DeletionParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid deletion parameters")
+
+		return nil, false, echo.ErrBadRequest
+	}
+
+	repo := i.storage.WithContext(ctx.Request().Context())
+
+	if params.IncludeDeleted == nil || !*params.IncludeDeleted {
+		return repo, false, nil
+	}
+
+	if !auth.IsAllowed(ctx, auth.ScopeAdmin) {
+		logger.Warn().Msg("deleted resources requested without admin scope")
+
+		return nil, false, echo.ErrForbidden
+	}
+
+	return repo.IncludeDeleted(), true, nil
+}
+
+// RestoreComponent restores a deleted component, which is not purged yet.
+// Its dependencies and group memberships are kept with its deletion and found again.
+func (i *Implementation) RestoreComponent(ctx echo.Context, componentID uuid.UUID) error {
+	var component *DbDef.Component
+
+	logger := i.logger.With().Str("handler", "RestoreComponent").Interface("id", componentID).Logger()
+	logger.Debug().Send()
+
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		var transactionErr error
+
+		component, transactionErr = repo.RestoreComponent(componentID)
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("deleted component not found")
+
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrConflict) {
+			logger.Warn().Msg("component changed concurrently")
+
+			return echo.ErrConflict
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error restoring component")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationRestore,
+			targetType: DbDef.AuditTargetComponent,
+			targetID:   componentID.String(),
+			before:     nil,
+			after:      component,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording restoration")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = publishEvent(repo, DbDef.EventComponentChanged, componentChange{
+			Operation: DbDef.AuditOperationRestore,
+			Component: component,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error publishing restoration")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.JSON(http.StatusOK, apiServerDefinition.ComponentResponse{ //nolint:wrapcheck
+		Data: component.ToAPIResponse(),
+	})
+}
+
+// RestoreIncident restores a deleted incident, which is not purged yet, with its impacts, updates and revisions.
+// Incidents impacting deleted components are not restored, until the components are restored.
+func (i *Implementation) RestoreIncident(ctx echo.Context, incidentID uuid.UUID) error {
+	var incident *DbDef.Incident
+
+	logger := i.logger.With().Str("handler", "RestoreIncident").Interface("id", incidentID).Logger()
+	logger.Debug().Send()
+
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		var transactionErr error
+
+		incident, transactionErr = repo.RestoreIncident(incidentID)
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("deleted incident not found")
+
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrReferenced) {
+			logger.Warn().Msg("incident impacts deleted components")
+
+			return echo.NewHTTPError(http.StatusConflict, "incident impacts deleted components")
+		} else if errors.Is(transactionErr, storage.ErrConflict) {
+			logger.Warn().Msg("incident changed concurrently")
+
+			return echo.ErrConflict
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error restoring incident")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationRestore,
+			targetType: DbDef.AuditTargetIncident,
+			targetID:   incidentID.String(),
+			before:     nil,
+			after:      incident,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording restoration")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = publishEvent(repo, DbDef.EventIncidentRestored, incident)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error publishing restoration")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.JSON(http.StatusOK, apiServerDefinition.IncidentResponse{ //nolint:wrapcheck
+		Data: incident.ToAPIResponse(),
+	})
+}
@@ -0,0 +1,206 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Deletion", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		repo storage.Repository
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		componentID uuid.UUID
+		incidentID  uuid.UUID
+
+		newContext = func(method, url string) (echo.Context, *httptest.ResponseRecorder) {
+			return test.MustCreateEchoContextAndResponseWriter(echoLogger, method, url, nil)
+		}
+	)
+
+	BeforeEach(func() {
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store := storage.NewMemory()
+		repo = store.WithContext(context.Background())
+		handlers = server.New(store, nil, nil, nil, handlerLogger)
+
+		network := &db.Component{DisplayName: test.Ptr("Network")}
+		Ω(repo.CreateComponent(network)).Should(Succeed())
+		componentID = network.ID
+
+		impactType := &db.ImpactType{DisplayName: test.Ptr("Connectivity issues")}
+		Ω(repo.CreateImpactType(impactType)).Should(Succeed())
+		Ω(repo.CreatePhases([]db.Phase{
+			{Name: test.Ptr("Investigation"), Generation: test.Ptr(1), Order: test.Ptr(0)},
+		})).Should(Succeed())
+
+		beganAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+		incident := &db.Incident{
+			DisplayName: test.Ptr("Switch failure"),
+			BeganAt:     &beganAt,
+			Phase:       &db.Phase{Generation: test.Ptr(1), Order: test.Ptr(0)},
+			Affects: &[]db.Impact{
+				{ComponentID: &network.ID, ImpactTypeID: &impactType.ID, Severity: test.Ptr(50)},
+			},
+		}
+		Ω(repo.CreateIncident(incident)).Should(Succeed())
+		incidentID = incident.ID
+	})
+
+	Describe("DeleteIncident", func() {
+		BeforeEach(func() {
+			ctx, _ := newContext(http.MethodDelete, "/incidents/"+incidentID.String())
+			Ω(handlers.DeleteIncident(ctx, incidentID)).Should(Succeed())
+		})
+
+		It("should hide the deleted incident", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodGet, "/incidents/"+incidentID.String())
+
+			// Act
+			err := handlers.GetIncident(ctx, incidentID)
+
+			// Assert
+			Ω(err).Should(Equal(echo.ErrNotFound))
+		})
+
+		It("should return the deleted incident, if deleted ones are included", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, "/incidents/"+incidentID.String()+"?includeDeleted=true")
+
+			// Act
+			err := handlers.GetIncident(ctx, incidentID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusOK))
+
+			var response api.DeletableIncidentResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(response.Data.DeletedAt).ShouldNot(BeNil())
+			Ω(response.Data.DeletedBy).Should(HaveValue(Equal("anonymous")))
+		})
+
+		It("should no longer impact the component", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, "/components/"+componentID.String())
+
+			// Act
+			err := handlers.GetComponent(ctx, componentID, apiServerDefinition.GetComponentParams{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var response apiServerDefinition.ComponentResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(*response.Data.ActivelyAffectedBy).Should(BeEmpty())
+		})
+	})
+
+	Describe("RestoreIncident", func() {
+		It("should restore the deleted incident", func() {
+			// Arrange
+			_, err := repo.DeleteIncident(incidentID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			ctx, res := newContext(http.MethodPost, "/incidents/"+incidentID.String()+"/restore")
+
+			// Act
+			err = handlers.RestoreIncident(ctx, incidentID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusOK))
+
+			_, err = repo.GetIncident(incidentID)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return not found for incidents not deleted", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodPost, "/incidents/"+incidentID.String()+"/restore")
+
+			// Act
+			err := handlers.RestoreIncident(ctx, incidentID)
+
+			// Assert
+			Ω(err).Should(Equal(echo.ErrNotFound))
+		})
+
+		It("should refuse incidents impacting deleted components", func() {
+			// Arrange
+			_, err := repo.DeleteIncident(incidentID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = repo.DeleteComponent(componentID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			ctx, _ := newContext(http.MethodPost, "/incidents/"+incidentID.String()+"/restore")
+
+			// Act
+			err = handlers.RestoreIncident(ctx, incidentID)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusConflict))
+		})
+	})
+
+	Describe("DeleteComponent", func() {
+		It("should refuse components impacted by incidents", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodDelete, "/components/"+componentID.String())
+
+			// Act
+			err := handlers.DeleteComponent(ctx, componentID)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusConflict))
+		})
+	})
+
+	Describe("RestoreComponent", func() {
+		It("should restore the deleted component", func() {
+			// Arrange
+			_, err := repo.DeleteIncident(incidentID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = repo.DeleteComponent(componentID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			ctx, res := newContext(http.MethodPost, "/components/"+componentID.String()+"/restore")
+
+			// Act
+			err = handlers.RestoreComponent(ctx, componentID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusOK))
+
+			var response apiServerDefinition.ComponentResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(*response.Data.DisplayName).Should(Equal("Network"))
+		})
+	})
+})
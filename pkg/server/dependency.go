@@ -0,0 +1,305 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/dependency"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// includeIndirectFromRequest reads from the [api.DependencyParams] of the request, if indirect impacts are requested.
+// The parameters are not part of the OpenAPI spec, so they are bound separately.
+func includeIndirectFromRequest(ctx echo.Context) (bool, error) {
+	var params api.DependencyParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return false, fmt.Errorf("error binding dependency parameters: %w", err)
+	}
+
+	return params.IncludeIndirect != nil && *params.IncludeIndirect, nil
+}
+
+// withIndirectImpacts converts the components with their impacts active at the time,
+// including the impacts on their dependencies.
+func withIndirectImpacts(
+	repo storage.Repository,
+	components []*DbDef.Component,
+	at *time.Time,
+) ([]api.ComponentResponseData, error) {
+	dependencies, err := repo.ListComponentDependencies()
+	if err != nil {
+		return nil, fmt.Errorf("error loading dependencies: %w", err)
+	}
+
+	impacts := map[DbDef.ID][]DbDef.Impact{}
+
+	if len(dependencies) > 0 {
+		var allComponents []*DbDef.Component
+
+		allComponents, _, err = repo.ListComponents(at, nil, storage.Page{Limit: 0, After: nil})
+		if err != nil {
+			return nil, fmt.Errorf("error loading components: %w", err)
+		}
+
+		for _, component := range allComponents {
+			if component.ActivelyAffectedBy != nil {
+				impacts[component.ID] = *component.ActivelyAffectedBy
+			}
+		}
+	}
+
+	graph := dependency.NewGraph(dependencies)
+	data := make([]api.ComponentResponseData, len(components))
+
+	for componentIndex, component := range components {
+		data[componentIndex] = componentResponseData(component, graph.IndirectImpacts(component.ID, impacts))
+	}
+
+	return data, nil
+}
+
+// componentResponseData converts the component with its direct and the indirect impacts.
+func componentResponseData(component *DbDef.Component, indirect []dependency.IndirectImpact) api.ComponentResponseData {
+	data := api.ComponentResponseData{
+		ID:                 component.ID,
+		DisplayName:        component.DisplayName,
+		Labels:             (*map[string]string)(component.Labels),
+		ActivelyAffectedBy: []api.ComponentImpact{},
+		Deletion:           DbDef.NewDeletion(component.DeletedAt, component.DeletedBy),
+	}
+
+	if component.ActivelyAffectedBy != nil {
+		for _, impact := range *component.ActivelyAffectedBy {
+			data.ActivelyAffectedBy = append(data.ActivelyAffectedBy, api.ComponentImpact{
+				Reference: impact.IncidentID,
+				Type:      impact.ImpactTypeID,
+				Severity:  impact.Severity,
+				Indirect:  false,
+				Origin:    nil,
+			})
+		}
+	}
+
+	for _, impact := range indirect {
+		data.ActivelyAffectedBy = append(data.ActivelyAffectedBy, api.ComponentImpact{
+			Reference: impact.Impact.IncidentID,
+			Type:      impact.Impact.ImpactTypeID,
+			Severity:  impact.Impact.Severity,
+			Indirect:  true,
+			Origin:    &impact.Origin,
+		})
+	}
+
+	return data
+}
+
+// GetDependencies lists all dependencies between components.
+func (i *Implementation) GetDependencies(ctx echo.Context) error {
+	logger := i.logger.With().Str("handler", "GetDependencies").Logger()
+	logger.Debug().Send()
+
+	dependencies, err := i.storage.WithContext(ctx.Request().Context()).ListComponentDependencies()
+	if err != nil {
+		logger.Error().Err(err).Msg("error loading dependencies")
+
+		return echo.ErrInternalServerError
+	}
+
+	response := api.DependencyListResponse{Data: make([]api.Dependency, 0, len(dependencies))}
+
+	for _, dependency := range dependencies {
+		response.Data = append(response.Data, api.Dependency{
+			ComponentID: *dependency.ComponentID,
+			DependsOnID: *dependency.DependsOnID,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, response) //nolint:wrapcheck
+}
+
+// GetComponentDependencies inspects the dependencies of a component and the components depending on it.
+func (i *Implementation) GetComponentDependencies(ctx echo.Context, componentID uuid.UUID) error {
+	logger := i.logger.With().Str("handler", "GetComponentDependencies").Interface("id", componentID).Logger()
+	logger.Debug().Send()
+
+	repo := i.storage.WithContext(ctx.Request().Context())
+
+	_, err := repo.GetComponent(componentID, nil)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.Warn().Msg("component not found")
+
+			return echo.ErrNotFound
+		}
+
+		logger.Error().Err(err).Msg("error loading component")
+
+		return echo.ErrInternalServerError
+	}
+
+	dependencies, err := repo.ListComponentDependencies()
+	if err != nil {
+		logger.Error().Err(err).Msg("error loading dependencies")
+
+		return echo.ErrInternalServerError
+	}
+
+	graph := dependency.NewGraph(dependencies)
+
+	return ctx.JSON(http.StatusOK, api.ComponentDependencies{ //nolint:wrapcheck
+		ComponentID:     componentID,
+		Dependencies:    orEmpty(graph.Dependencies(componentID)),
+		Dependents:      orEmpty(graph.Dependents(componentID)),
+		AllDependencies: graph.AllDependencies(componentID),
+		AllDependents:   graph.AllDependents(componentID),
+	})
+}
+
+// CreateComponentDependency makes the component depend on another one, unless it would depend on itself.
+// Creating an existing dependency succeeds without changes.
+func (i *Implementation) CreateComponentDependency( //nolint:funlen
+	ctx echo.Context,
+	componentID, dependsOnID uuid.UUID,
+) error {
+	logger := i.logger.With().
+		Str("handler", "CreateComponentDependency").
+		Interface("id", componentID).
+		Interface("dependsOn", dependsOnID).
+		Logger()
+	logger.Debug().Send()
+
+	dependencyToCreate := &DbDef.ComponentDependency{
+		Component:   nil,
+		DependsOn:   nil,
+		ComponentID: &componentID,
+		DependsOnID: &dependsOnID,
+	}
+
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		// Concurrent requests for opposite dependencies would both pass the check of the same dependencies.
+		transactionErr := repo.LockComponentDependencies()
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error locking dependencies")
+
+			return echo.ErrInternalServerError
+		}
+
+		// Dependencies of deleted components are included, so restoring them never closes a cycle.
+		dependencies, transactionErr := repo.IncludeDeleted().ListComponentDependencies()
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error loading dependencies")
+
+			return echo.ErrInternalServerError
+		}
+
+		graph := dependency.NewGraph(dependencies)
+		if slices.Contains(graph.Dependencies(componentID), dependsOnID) {
+			logger.Debug().Msg("dependency exists")
+
+			return nil
+		}
+
+		transactionErr = graph.Check(componentID, dependsOnID)
+		if transactionErr != nil {
+			logger.Warn().Err(transactionErr).Msg("dependency cycle")
+
+			return echo.NewHTTPError(http.StatusConflict, transactionErr.Error())
+		}
+
+		transactionErr = repo.CreateComponentDependency(dependencyToCreate)
+		if errors.Is(transactionErr, storage.ErrReferenced) {
+			logger.Warn().Msg("component not found")
+
+			return echo.ErrNotFound
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error creating dependency")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetComponentDependency,
+			targetID:   dependencyTargetID(componentID, dependsOnID),
+			before:     nil,
+			after:      dependencyToCreate,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording creation")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
+
+// DeleteComponentDependency removes the dependency of the component on another one.
+func (i *Implementation) DeleteComponentDependency(ctx echo.Context, componentID, dependsOnID uuid.UUID) error {
+	logger := i.logger.With().
+		Str("handler", "DeleteComponentDependency").
+		Interface("id", componentID).
+		Interface("dependsOn", dependsOnID).
+		Logger()
+	logger.Debug().Send()
+
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		dbDependency, transactionErr := repo.DeleteComponentDependency(componentID, dependsOnID)
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("dependency not found")
+
+			return echo.ErrNotFound
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error deleting dependency")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationDelete,
+			targetType: DbDef.AuditTargetComponentDependency,
+			targetID:   dependencyTargetID(componentID, dependsOnID),
+			before:     dbDependency,
+			after:      nil,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording deletion")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
+
+// dependencyTargetID identifies a dependency in the audit log.
+func dependencyTargetID(componentID, dependsOnID uuid.UUID) string {
+	return componentID.String() + "/" + dependsOnID.String()
+}
+
+func orEmpty(ids []uuid.UUID) []uuid.UUID {
+	if ids == nil {
+		return []uuid.UUID{}
+	}
+
+	return ids
+}
@@ -0,0 +1,238 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Dependency", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		store *storage.Memory
+		repo  storage.Repository
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		dbaas, storageComponent, network *db.Component
+		incidentID                       uuid.UUID
+
+		newContext = func(method, url string) (echo.Context, *httptest.ResponseRecorder) {
+			return test.MustCreateEchoContextAndResponseWriter(echoLogger, method, url, nil)
+		}
+
+		dependOn = func(component, dependency *db.Component) error {
+			ctx, _ := newContext(http.MethodPut, "/components/"+component.ID.String()+"/dependencies/"+dependency.ID.String())
+
+			return handlers.CreateComponentDependency(ctx, component.ID, dependency.ID)
+		}
+	)
+
+	BeforeEach(func() {
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store = storage.NewMemory()
+		repo = store.WithContext(context.Background())
+		handlers = server.New(store, nil, nil, nil, handlerLogger)
+
+		dbaas = &db.Component{DisplayName: test.Ptr("DBaaS")}
+		storageComponent = &db.Component{DisplayName: test.Ptr("Storage")}
+		network = &db.Component{DisplayName: test.Ptr("Network")}
+
+		for _, component := range []*db.Component{dbaas, storageComponent, network} {
+			Ω(repo.CreateComponent(component)).Should(Succeed())
+		}
+
+		Ω(dependOn(dbaas, storageComponent)).Should(Succeed())
+		Ω(dependOn(storageComponent, network)).Should(Succeed())
+
+		impactType := &db.ImpactType{DisplayName: test.Ptr("Performance degration")}
+		Ω(repo.CreateImpactType(impactType)).Should(Succeed())
+		Ω(repo.CreatePhases([]db.Phase{
+			{Name: test.Ptr("Investigation"), Generation: test.Ptr(1), Order: test.Ptr(0)},
+		})).Should(Succeed())
+
+		incident := &db.Incident{
+			DisplayName: test.Ptr("Switch failure"),
+			BeganAt:     test.Ptr(time.Now().Add(-time.Hour)),
+			Phase:       &db.Phase{Generation: test.Ptr(1), Order: test.Ptr(0)},
+			Affects: &[]db.Impact{
+				{ComponentID: &network.ID, ImpactTypeID: &impactType.ID, Severity: test.Ptr(80)},
+			},
+		}
+		Ω(repo.CreateIncident(incident)).Should(Succeed())
+		incidentID = incident.ID
+	})
+
+	Describe("CreateComponentDependency", func() {
+		It("should succeed for existing dependencies", func() {
+			// Act
+			err := dependOn(dbaas, storageComponent)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.ListComponentDependencies()).Should(HaveLen(2))
+		})
+
+		It("should refuse cycles", func() {
+			// Act
+			err := dependOn(network, dbaas)
+
+			// Assert
+			Ω(err).Should(HaveOccurred())
+			Ω(err).Should(HaveField("Code", http.StatusConflict))
+			Ω(dependOn(network, network)).Should(HaveOccurred())
+		})
+
+		It("should refuse cycles through deleted components", func() {
+			// Arrange
+			_, err := repo.DeleteComponent(storageComponent.ID, "admin")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			err = dependOn(network, dbaas)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusConflict))
+		})
+
+		It("should return not found for unknown components", func() {
+			// Act
+			err := dependOn(dbaas, &db.Component{Model: db.Model{ID: uuid.New()}})
+
+			// Assert
+			Ω(err).Should(Equal(echo.ErrNotFound))
+		})
+	})
+
+	Describe("DeleteComponentDependency", func() {
+		It("should delete the dependency", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodDelete, "/")
+
+			// Act
+			err := handlers.DeleteComponentDependency(ctx, dbaas.ID, storageComponent.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(handlers.DeleteComponentDependency(ctx, dbaas.ID, storageComponent.ID)).Should(Equal(echo.ErrNotFound))
+		})
+	})
+
+	Describe("GetComponentDependencies", func() {
+		It("should return direct and indirect dependencies and dependents", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, "/")
+
+			// Act
+			err := handlers.GetComponentDependencies(ctx, storageComponent.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var response api.ComponentDependencies
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(response).Should(Equal(api.ComponentDependencies{
+				ComponentID:     storageComponent.ID,
+				Dependencies:    []uuid.UUID{network.ID},
+				Dependents:      []uuid.UUID{dbaas.ID},
+				AllDependencies: []uuid.UUID{network.ID},
+				AllDependents:   []uuid.UUID{dbaas.ID},
+			}))
+		})
+
+		It("should return not found for unknown components", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodGet, "/")
+
+			// Act
+			err := handlers.GetComponentDependencies(ctx, uuid.New())
+
+			// Assert
+			Ω(err).Should(Equal(echo.ErrNotFound))
+		})
+	})
+
+	Describe("GetComponents", func() {
+		It("should include indirect impacts, if requested", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, "/components?includeIndirect=true")
+
+			// Act
+			err := handlers.GetComponents(ctx, apiServerDefinition.GetComponentsParams{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var response api.PageResponse[api.ComponentResponseData]
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(response.Data).Should(HaveLen(3))
+
+			Ω(response.Data[0].ID).Should(Equal(dbaas.ID))
+			Ω(response.Data[0].ActivelyAffectedBy).Should(Equal([]api.ComponentImpact{{
+				Reference: &incidentID,
+				Type:      response.Data[0].ActivelyAffectedBy[0].Type,
+				Severity:  test.Ptr(80),
+				Indirect:  true,
+				Origin:    &network.ID,
+			}}))
+
+			Ω(response.Data[1].ID).Should(Equal(network.ID))
+			Ω(response.Data[1].ActivelyAffectedBy).Should(HaveLen(1))
+			Ω(response.Data[1].ActivelyAffectedBy[0].Indirect).Should(BeFalse())
+		})
+
+		It("should only include direct impacts by default", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, "/components")
+
+			// Act
+			err := handlers.GetComponents(ctx, apiServerDefinition.GetComponentsParams{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var response api.PageResponse[apiServerDefinition.ComponentResponseData]
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(*response.Data[0].ActivelyAffectedBy).Should(BeEmpty())
+		})
+	})
+
+	Describe("GetComponent", func() {
+		It("should include indirect impacts, if requested", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, "/components/"+dbaas.ID.String()+"?includeIndirect=true")
+
+			// Act
+			err := handlers.GetComponent(ctx, dbaas.ID, apiServerDefinition.GetComponentParams{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var response api.ComponentResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(response.Data.ActivelyAffectedBy).Should(HaveLen(1))
+			Ω(response.Data.ActivelyAffectedBy[0].Origin).Should(Equal(&network.ID))
+		})
+	})
+})
@@ -1,6 +1,12 @@
 package server
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+)
 
 var (
 	// ErrInvalidPhaseGeneration means the given generation is invalid.
@@ -10,4 +16,61 @@ var (
 	// ErrPhaseGenerationNotFound means the given generation was not found.
 	// this can be seen as 404 - Not found.
 	ErrPhaseGenerationNotFound = errors.New("phase generation not found")
+
+	// ErrEmptyRequest means the request body carries no data.
+	// This can be seen as 400 - Bad request.
+	ErrEmptyRequest = errors.New("request is empty")
+
+	// ErrUnknownPhase means the referenced phase does not exist.
+	// This can be seen as 400 - Bad request.
+	ErrUnknownPhase = errors.New("unknown phase")
+
+	// ErrInvalidRequest means the request violates the OpenAPI spec.
+	// This can be seen as 400 - Bad request.
+	ErrInvalidRequest = errors.New("request violates the OpenAPI spec")
+
+	// ErrUnknownComponent means the component referenced by an impact does not exist.
+	// This can be seen as 400 - Bad request.
+	ErrUnknownComponent = errors.New("unknown component")
+
+	// ErrUnknownImpactType means the impact type referenced by an impact does not exist.
+	// This can be seen as 400 - Bad request.
+	ErrUnknownImpactType = errors.New("unknown impact type")
+
+	// ErrSeverityOutsideBands means the severity value of an impact falls into no defined severity.
+	// This can be seen as 400 - Bad request.
+	ErrSeverityOutsideBands = errors.New("severity value outside of the defined severities")
 )
+
+// problemTypes assigns the errors of the handlers their problem type.
+// The names are part of the API and must not change.
+var problemTypes = []struct { //nolint:gochecknoglobals
+	err  error
+	name string
+}{
+	{ErrInvalidPhaseGeneration, "invalid-phase-generation"},
+	{ErrPhaseGenerationNotFound, "phase-generation-not-found"},
+	{ErrEmptyRequest, "empty-request"},
+	{ErrUnknownPhase, "unknown-phase"},
+	{ErrInvalidRequest, "invalid-request"},
+	{ErrUnknownComponent, "unknown-component"},
+	{ErrUnknownImpactType, "unknown-impact-type"},
+	{ErrSeverityOutsideBands, "severity-outside-bands"},
+	{auth.ErrUnknownScope, "unknown-scope"},
+}
+
+// problemType returns the problem type URI of an error returned by a handler.
+// Errors without a problem type are described by their HTTP status only.
+func problemType(err error) string {
+	for _, problemType := range problemTypes {
+		if errors.Is(err, problemType.err) {
+			return api.ProblemTypeBase + problemType.name
+		}
+	}
+
+	if problemType, ok := DbDef.ProblemType(err); ok {
+		return problemType
+	}
+
+	return api.ProblemTypeGeneric
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	// eventStreamBatchSize limits the number of events loaded from the database at once.
+	eventStreamBatchSize = 100
+	// eventStreamHeartbeat is the interval of comments keeping idle streams open through proxies.
+	eventStreamHeartbeat = 15 * time.Second
+)
+
+// componentChange is the payload of [DbDef.EventComponentChanged].
+type componentChange struct {
+	Operation DbDef.AuditOperation `json:"operation"`
+	Component *DbDef.Component     `json:"component"`
+}
+
+// publishEvent publishes the event to the event streams and webhooks.
+// It must be called with the transaction of the change, so the event is only published if the change is committed.
+func publishEvent(repo storage.Repository, eventType DbDef.EventType, payload interface{}) error {
+	event, err := DbDef.NewEvent(eventType, payload)
+	if err != nil {
+		return fmt.Errorf("error creating event: %w", err)
+	}
+
+	return repo.PublishEvent(event) //nolint:wrapcheck
+}
+
+// GetEvents streams events as server-sent events.
+// Without a last event ID, the stream starts with the next published event.
+func (i *Implementation) GetEvents(ctx echo.Context, params api.GetEventsParams) error {
+	var lastEventID uint64
+
+	logger := i.logger.With().Str("handler", "GetEvents").Logger()
+	logger.Debug().Interface("lastEventId", params.LastEventID).Send()
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	// Subscribe before reading, so no event published in between is missed.
+	notifications, unsubscribe := i.eventBroker.Subscribe()
+	defer unsubscribe()
+
+	if params.LastEventID != nil {
+		lastEventID = *params.LastEventID
+	} else {
+		res := dbSession.Model(&DbDef.Event{}).Select("COALESCE(MAX(id), 0)").Scan(&lastEventID) //nolint:exhaustruct
+		if res.Error != nil {
+			logger.Error().Err(res.Error).Msg("error loading last event")
+
+			return echo.ErrInternalServerError
+		}
+	}
+
+	response := ctx.Response()
+	response.Header().Set(echo.HeaderContentType, "text/event-stream")
+	response.Header().Set(echo.HeaderCacheControl, "no-cache")
+	response.Header().Set("X-Accel-Buffering", "no")
+	response.WriteHeader(http.StatusOK)
+	response.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	// Errors can't be returned to the client after the stream started, it is ended instead.
+	for {
+		var err error
+
+		lastEventID, err = sendEvents(dbSession, response, lastEventID)
+		if err != nil {
+			logger.Warn().Err(err).Msg("error streaming events")
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Request().Context().Done():
+			return nil
+		case _, open := <-notifications:
+			if !open {
+				logger.Debug().Msg("event stream closed")
+
+				return nil
+			}
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(response, ": heartbeat\n\n")
+			if err != nil {
+				logger.Warn().Err(err).Msg("error sending heartbeat")
+
+				return nil
+			}
+
+			response.Flush()
+		}
+	}
+}
+
+// sendEvents writes all events after lastEventID to the stream and returns the ID of the last one written.
+func sendEvents(dbSession *gorm.DB, response *echo.Response, lastEventID uint64) (uint64, error) {
+	for {
+		var dbEvents []*DbDef.Event
+
+		res := dbSession.Where("id > ?", lastEventID).Order("id").Limit(eventStreamBatchSize).Find(&dbEvents)
+		if res.Error != nil {
+			return lastEventID, fmt.Errorf("error loading events: %w", res.Error)
+		}
+
+		for _, event := range dbEvents {
+			data, err := json.Marshal(event.ToAPIResponse())
+			if err != nil {
+				return lastEventID, fmt.Errorf("error encoding event: %w", err)
+			}
+
+			_, err = fmt.Fprintf(response, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			if err != nil {
+				return lastEventID, fmt.Errorf("error sending event: %w", err)
+			}
+
+			lastEventID = event.ID
+		}
+
+		response.Flush()
+
+		if len(dbEvents) < eventStreamBatchSize {
+			return lastEventID, nil
+		}
+	}
+}
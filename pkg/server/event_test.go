@@ -0,0 +1,203 @@
+package server_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/events"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+// expected SQL publishing an event to the outbox.
+//
+//nolint:gochecknoglobals
+var (
+	expectedEventLock = regexp.
+				QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)
+	expectedEventInsert = regexp.
+				QuoteMeta(`INSERT INTO "events" ("created_at","type","payload","dispatched_at","notified_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`) //nolint:lll
+	expectedEventNotify = regexp.
+				QuoteMeta(`SELECT pg_notify($1, $2)`)
+)
+
+// expectEvent expects an event to be published.
+// SQLite serializes writers by itself and is polled instead of notifying listeners.
+func expectEvent(dialect test.Dialect, sqlMock sqlmock.Sqlmock, eventType db.EventType) {
+	if dialect == test.SQLite {
+		sqlMock.
+			ExpectQuery(expectedEventInsert).
+			WithArgs(sqlmock.AnyArg(), string(eventType), sqlmock.AnyArg(), nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		return
+	}
+
+	sqlMock.
+		ExpectExec(expectedEventLock).
+		WithArgs(events.SequenceLock).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.
+		ExpectQuery(expectedEventInsert).
+		WithArgs(sqlmock.AnyArg(), string(eventType), sqlmock.AnyArg(), nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	sqlMock.
+		ExpectExec(expectedEventNotify).
+		WithArgs(events.Channel, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+var _ = DescribeTableSubtree("Event", func(dialect test.Dialect) {
+	var (
+		// sub loggers
+		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// actual functions under test
+		broker   *events.Broker
+		handlers *server.Implementation
+
+		// expected SQL
+		expectedLastEventQuery = regexp.
+					QuoteMeta(`SELECT COALESCE(MAX(id), 0) FROM "events"`)
+		expectedEventsQuery = regexp.
+					QuoteMeta(`SELECT * FROM "events" WHERE id > $1 ORDER BY id LIMIT $2`)
+
+		createdAt = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+		eventRows = func(ids ...uint64) *sqlmock.Rows {
+			rows := sqlmock.NewRows([]string{"id", "created_at", "type", "payload"})
+			for _, id := range ids {
+				rows.AddRow(id, createdAt, db.EventIncidentCreated, []byte(`{"displayName":"Disk impact"}`))
+			}
+
+			return rows
+		}
+	)
+
+	BeforeEach(func() {
+		// setup database and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		broker = events.NewBroker()
+		handlers = server.New(storage.NewGorm(gormDB), broker, nil, nil, handlerLogger)
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+	})
+
+	Describe("GetEvents", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/events", nil)
+		})
+
+		Context("with last event ID", func() {
+			It("should stream the missed events until the broker is closed", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedEventsQuery).
+					WithArgs(40, 100).
+					WillReturnRows(eventRows(41, 42))
+
+				broker.Close()
+
+				// Act
+				err := handlers.GetEvents(ctx, api.GetEventsParams{LastEventID: test.Ptr(uint64(40))})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Header().Get(echo.HeaderContentType)).Should(Equal("text/event-stream"))
+				Ω(res.Body.String()).Should(Equal(
+					"id: 41\nevent: incident.created\n" +
+						`data: {"id":41,"type":"incident.created","createdAt":"2024-03-01T12:00:00Z",` +
+						`"data":{"displayName":"Disk impact"}}` + "\n\n" +
+						"id: 42\nevent: incident.created\n" +
+						`data: {"id":42,"type":"incident.created","createdAt":"2024-03-01T12:00:00Z",` +
+						`"data":{"displayName":"Disk impact"}}` + "\n\n",
+				))
+			})
+		})
+
+		Context("without last event ID", func() {
+			It("should stream events published after subscribing", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedLastEventQuery).
+					WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(42))
+				sqlMock.
+					ExpectQuery(expectedEventsQuery).
+					WithArgs(42, 100).
+					WillReturnRows(eventRows())
+
+				done := make(chan struct{})
+
+				// Act
+				go func() {
+					defer GinkgoRecover()
+					defer close(done)
+
+					err := handlers.GetEvents(ctx, api.GetEventsParams{LastEventID: nil})
+					Ω(err).ShouldNot(HaveOccurred())
+				}()
+
+				Eventually(sqlMock.ExpectationsWereMet).ShouldNot(HaveOccurred())
+
+				sqlMock.
+					ExpectQuery(expectedEventsQuery).
+					WithArgs(42, 100).
+					WillReturnRows(eventRows(43))
+
+				broker.Notify()
+
+				Eventually(sqlMock.ExpectationsWereMet).ShouldNot(HaveOccurred())
+
+				broker.Close()
+
+				// Assert
+				Eventually(done).Should(BeClosed())
+				Ω(res.Body.String()).Should(HavePrefix("id: 43\nevent: incident.created\n"))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedLastEventQuery).
+					WillReturnError(test.ErrTestError)
+
+				// Act
+				err := handlers.GetEvents(ctx, api.GetEventsParams{LastEventID: nil})
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+}, test.DialectEntries())
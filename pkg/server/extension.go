@@ -0,0 +1,713 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/auth"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/runtime"
+)
+
+// lastEventIDHeader is the header of server-sent events requests resuming a stream.
+const lastEventIDHeader = "Last-Event-ID"
+
+// ExtensionInterface represents all handlers served in addition to the OpenAPI spec.
+type ExtensionInterface interface {
+	// Receive notifications of Alertmanager.
+	// (POST /alertmanager)
+	ReceiveAlerts(ctx echo.Context) error
+	// Get a list of API keys.
+	// (GET /apikeys)
+	GetAPIKeys(ctx echo.Context) error
+	// Create a new API key.
+	// (POST /apikeys)
+	CreateAPIKey(ctx echo.Context) error
+	// Revoke an API key.
+	// (DELETE /apikeys/{apiKeyId})
+	RevokeAPIKey(ctx echo.Context, apiKeyID uuid.UUID) error
+	// Get a filtered list of audit entries.
+	// (GET /audit)
+	GetAuditEntries(ctx echo.Context, params api.GetAuditEntriesParams) error
+	// Get a list of component groups.
+	// (GET /componentgroups)
+	GetComponentGroups(ctx echo.Context) error
+	// Create a new component group.
+	// (POST /componentgroups)
+	CreateComponentGroup(ctx echo.Context) error
+	// Delete a component group.
+	// (DELETE /componentgroups/{groupId})
+	DeleteComponentGroup(ctx echo.Context, groupID uuid.UUID) error
+	// Get a specific component group by ID.
+	// (GET /componentgroups/{groupId})
+	GetComponentGroup(ctx echo.Context, groupID uuid.UUID) error
+	// Update a specific component group by ID.
+	// (PATCH /componentgroups/{groupId})
+	UpdateComponentGroup(ctx echo.Context, groupID uuid.UUID) error
+	// Add a component to a component group.
+	// (PUT /componentgroups/{groupId}/components/{componentId})
+	AddComponentGroupMember(ctx echo.Context, groupID, componentID uuid.UUID) error
+	// Remove a component from a component group.
+	// (DELETE /componentgroups/{groupId}/components/{componentId})
+	RemoveComponentGroupMember(ctx echo.Context, groupID, componentID uuid.UUID) error
+	// Get the availability of a component in a period.
+	// (GET /components/{componentId}/availability)
+	GetComponentAvailability(ctx echo.Context, componentID uuid.UUID, params api.GetAvailabilityParams) error
+	// Inspect the dependencies of a component.
+	// (GET /components/{componentId}/dependencies)
+	GetComponentDependencies(ctx echo.Context, componentID uuid.UUID) error
+	// Make a component depend on another one.
+	// (PUT /components/{componentId}/dependencies/{dependencyId})
+	CreateComponentDependency(ctx echo.Context, componentID, dependencyID uuid.UUID) error
+	// Remove the dependency of a component on another one.
+	// (DELETE /components/{componentId}/dependencies/{dependencyId})
+	DeleteComponentDependency(ctx echo.Context, componentID, dependencyID uuid.UUID) error
+	// Restore a deleted component.
+	// (POST /components/{componentId}/restore)
+	RestoreComponent(ctx echo.Context, componentID uuid.UUID) error
+	// Get all dependencies between components.
+	// (GET /dependencies)
+	GetDependencies(ctx echo.Context) error
+	// Stream events as server-sent events.
+	// (GET /events)
+	GetEvents(ctx echo.Context, params api.GetEventsParams) error
+	// Get the latest incidents as Atom feed.
+	// (GET /feeds/incidents.atom)
+	GetIncidentsAtom(ctx echo.Context, params api.GetFeedParams) error
+	// Get the latest incidents as RSS feed.
+	// (GET /feeds/incidents.rss)
+	GetIncidentsRSS(ctx echo.Context, params api.GetFeedParams) error
+	// Get the revisions of an incident.
+	// (GET /incidents/{incidentId}/revisions)
+	GetIncidentRevisions(ctx echo.Context, incidentID uuid.UUID) error
+	// Restore a deleted incident.
+	// (POST /incidents/{incidentId}/restore)
+	RestoreIncident(ctx echo.Context, incidentID uuid.UUID) error
+	// Get the maintenances as iCalendar.
+	// (GET /maintenances.ics)
+	GetMaintenancesICS(ctx echo.Context, params api.GetFeedParams) error
+	// Get the status summary of all components.
+	// (GET /status)
+	GetStatus(ctx echo.Context, params api.GetStatusParams) error
+	// Subscribe to email notifications.
+	// (POST /subscribers)
+	CreateSubscriber(ctx echo.Context) error
+	// Confirm a subscription to email notifications.
+	// (GET /subscribers/confirm)
+	ConfirmSubscriber(ctx echo.Context, params api.SubscriberTokenParams) error
+	// Cancel a subscription to email notifications.
+	// (GET /subscribers/unsubscribe)
+	// (POST /subscribers/unsubscribe)
+	DeleteSubscriber(ctx echo.Context, params api.SubscriberTokenParams) error
+	// Get a list of webhook subscriptions.
+	// (GET /webhooks)
+	GetWebhooks(ctx echo.Context) error
+	// Create a new webhook subscription.
+	// (POST /webhooks)
+	CreateWebhook(ctx echo.Context) error
+	// Delete a webhook subscription.
+	// (DELETE /webhooks/{webhookId})
+	DeleteWebhook(ctx echo.Context, webhookID uuid.UUID) error
+	// Get the deliveries of a webhook subscription.
+	// (GET /webhooks/{webhookId}/deliveries)
+	GetWebhookDeliveries(ctx echo.Context, webhookID uuid.UUID, params api.GetWebhookDeliveriesParams) error
+	// Retry a dead delivery of a webhook subscription.
+	// (POST /webhooks/{webhookId}/deliveries/{deliveryId}/retry)
+	RetryWebhookDelivery(ctx echo.Context, webhookID uuid.UUID, deliveryID uint64) error
+}
+
+// ExtensionInterfaceWrapper converts echo contexts to parameters.
+type ExtensionInterfaceWrapper struct {
+	Handler ExtensionInterface
+}
+
+// bindPathParameter binds a required path parameter like the generated [apiServerDefinition.ServerInterfaceWrapper].
+func bindPathParameter(ctx echo.Context, name string, target interface{}) error {
+	err := runtime.BindStyledParameterWithOptions("simple", name, ctx.Param(name), target,
+		runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter %s: %s", name, err))
+	}
+
+	return nil
+}
+
+// ReceiveAlerts converts echo context to params.
+func (w *ExtensionInterfaceWrapper) ReceiveAlerts(ctx echo.Context) error {
+	return w.Handler.ReceiveAlerts(ctx) //nolint:wrapcheck
+}
+
+// GetAPIKeys converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetAPIKeys(ctx echo.Context) error {
+	return w.Handler.GetAPIKeys(ctx) //nolint:wrapcheck
+}
+
+// CreateAPIKey converts echo context to params.
+func (w *ExtensionInterfaceWrapper) CreateAPIKey(ctx echo.Context) error {
+	return w.Handler.CreateAPIKey(ctx) //nolint:wrapcheck
+}
+
+// RevokeAPIKey converts echo context to params.
+func (w *ExtensionInterfaceWrapper) RevokeAPIKey(ctx echo.Context) error {
+	var apiKeyID uuid.UUID
+
+	err := bindPathParameter(ctx, "apiKeyId", &apiKeyID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.RevokeAPIKey(ctx, apiKeyID) //nolint:wrapcheck
+}
+
+// GetAuditEntries converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetAuditEntries(ctx echo.Context) error {
+	var params api.GetAuditEntriesParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	return w.Handler.GetAuditEntries(ctx, params) //nolint:wrapcheck
+}
+
+// GetComponentGroups converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetComponentGroups(ctx echo.Context) error {
+	return w.Handler.GetComponentGroups(ctx) //nolint:wrapcheck
+}
+
+// CreateComponentGroup converts echo context to params.
+func (w *ExtensionInterfaceWrapper) CreateComponentGroup(ctx echo.Context) error {
+	return w.Handler.CreateComponentGroup(ctx) //nolint:wrapcheck
+}
+
+// DeleteComponentGroup converts echo context to params.
+func (w *ExtensionInterfaceWrapper) DeleteComponentGroup(ctx echo.Context) error {
+	var groupID uuid.UUID
+
+	err := bindPathParameter(ctx, "groupId", &groupID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.DeleteComponentGroup(ctx, groupID) //nolint:wrapcheck
+}
+
+// GetComponentGroup converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetComponentGroup(ctx echo.Context) error {
+	var groupID uuid.UUID
+
+	err := bindPathParameter(ctx, "groupId", &groupID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.GetComponentGroup(ctx, groupID) //nolint:wrapcheck
+}
+
+// UpdateComponentGroup converts echo context to params.
+func (w *ExtensionInterfaceWrapper) UpdateComponentGroup(ctx echo.Context) error {
+	var groupID uuid.UUID
+
+	err := bindPathParameter(ctx, "groupId", &groupID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.UpdateComponentGroup(ctx, groupID) //nolint:wrapcheck
+}
+
+// AddComponentGroupMember converts echo context to params.
+func (w *ExtensionInterfaceWrapper) AddComponentGroupMember(ctx echo.Context) error {
+	groupID, componentID, err := bindGroupMemberPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.AddComponentGroupMember(ctx, groupID, componentID) //nolint:wrapcheck
+}
+
+// RemoveComponentGroupMember converts echo context to params.
+func (w *ExtensionInterfaceWrapper) RemoveComponentGroupMember(ctx echo.Context) error {
+	groupID, componentID, err := bindGroupMemberPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.RemoveComponentGroupMember(ctx, groupID, componentID) //nolint:wrapcheck
+}
+
+// bindGroupMemberPath binds the path parameters identifying a group membership.
+func bindGroupMemberPath(ctx echo.Context) (uuid.UUID, uuid.UUID, error) {
+	var groupID, componentID uuid.UUID
+
+	err := bindPathParameter(ctx, "groupId", &groupID)
+	if err != nil {
+		return groupID, componentID, err
+	}
+
+	err = bindPathParameter(ctx, "componentId", &componentID)
+
+	return groupID, componentID, err
+}
+
+// GetComponentAvailability converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetComponentAvailability(ctx echo.Context) error {
+	var (
+		componentID uuid.UUID
+		params      api.GetAvailabilityParams
+	)
+
+	err := bindPathParameter(ctx, "componentId", &componentID)
+	if err != nil {
+		return err
+	}
+
+	err = (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	return w.Handler.GetComponentAvailability(ctx, componentID, params) //nolint:wrapcheck
+}
+
+// GetComponentDependencies converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetComponentDependencies(ctx echo.Context) error {
+	var componentID uuid.UUID
+
+	err := bindPathParameter(ctx, "componentId", &componentID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.GetComponentDependencies(ctx, componentID) //nolint:wrapcheck
+}
+
+// CreateComponentDependency converts echo context to params.
+func (w *ExtensionInterfaceWrapper) CreateComponentDependency(ctx echo.Context) error {
+	componentID, dependencyID, err := bindDependencyPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.CreateComponentDependency(ctx, componentID, dependencyID) //nolint:wrapcheck
+}
+
+// DeleteComponentDependency converts echo context to params.
+func (w *ExtensionInterfaceWrapper) DeleteComponentDependency(ctx echo.Context) error {
+	componentID, dependencyID, err := bindDependencyPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.DeleteComponentDependency(ctx, componentID, dependencyID) //nolint:wrapcheck
+}
+
+// bindDependencyPath binds the path parameters identifying a dependency.
+func bindDependencyPath(ctx echo.Context) (uuid.UUID, uuid.UUID, error) {
+	var componentID, dependencyID uuid.UUID
+
+	err := bindPathParameter(ctx, "componentId", &componentID)
+	if err != nil {
+		return componentID, dependencyID, err
+	}
+
+	err = bindPathParameter(ctx, "dependencyId", &dependencyID)
+
+	return componentID, dependencyID, err
+}
+
+// RestoreComponent converts echo context to params.
+func (w *ExtensionInterfaceWrapper) RestoreComponent(ctx echo.Context) error {
+	var componentID uuid.UUID
+
+	err := bindPathParameter(ctx, "componentId", &componentID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.RestoreComponent(ctx, componentID) //nolint:wrapcheck
+}
+
+// GetDependencies converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetDependencies(ctx echo.Context) error {
+	return w.Handler.GetDependencies(ctx) //nolint:wrapcheck
+}
+
+// GetEvents converts echo context to params.
+// The `Last-Event-ID` header, sent by browsers on reconnects, takes precedence over the query parameter.
+func (w *ExtensionInterfaceWrapper) GetEvents(ctx echo.Context) error {
+	var params api.GetEventsParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	header := ctx.Request().Header.Get(lastEventIDHeader)
+	if header != "" {
+		lastEventID, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Sprintf("Invalid format for header %s: %s", lastEventIDHeader, err))
+		}
+
+		params.LastEventID = &lastEventID
+	}
+
+	return w.Handler.GetEvents(ctx, params) //nolint:wrapcheck
+}
+
+// GetIncidentsAtom converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetIncidentsAtom(ctx echo.Context) error {
+	var params api.GetFeedParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	return w.Handler.GetIncidentsAtom(ctx, params) //nolint:wrapcheck
+}
+
+// GetIncidentsRSS converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetIncidentsRSS(ctx echo.Context) error {
+	var params api.GetFeedParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	return w.Handler.GetIncidentsRSS(ctx, params) //nolint:wrapcheck
+}
+
+// GetIncidentRevisions converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetIncidentRevisions(ctx echo.Context) error {
+	var incidentID uuid.UUID
+
+	err := bindPathParameter(ctx, "incidentId", &incidentID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.GetIncidentRevisions(ctx, incidentID) //nolint:wrapcheck
+}
+
+// RestoreIncident converts echo context to params.
+func (w *ExtensionInterfaceWrapper) RestoreIncident(ctx echo.Context) error {
+	var incidentID uuid.UUID
+
+	err := bindPathParameter(ctx, "incidentId", &incidentID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.RestoreIncident(ctx, incidentID) //nolint:wrapcheck
+}
+
+// GetMaintenancesICS converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetMaintenancesICS(ctx echo.Context) error {
+	var params api.GetFeedParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	return w.Handler.GetMaintenancesICS(ctx, params) //nolint:wrapcheck
+}
+
+// GetStatus converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetStatus(ctx echo.Context) error {
+	var params api.GetStatusParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	return w.Handler.GetStatus(ctx, params) //nolint:wrapcheck
+}
+
+// CreateSubscriber converts echo context to params.
+func (w *ExtensionInterfaceWrapper) CreateSubscriber(ctx echo.Context) error {
+	return w.Handler.CreateSubscriber(ctx) //nolint:wrapcheck
+}
+
+// ConfirmSubscriber converts echo context to params.
+func (w *ExtensionInterfaceWrapper) ConfirmSubscriber(ctx echo.Context) error {
+	var params api.SubscriberTokenParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	return w.Handler.ConfirmSubscriber(ctx, params) //nolint:wrapcheck
+}
+
+// DeleteSubscriber converts echo context to params.
+func (w *ExtensionInterfaceWrapper) DeleteSubscriber(ctx echo.Context) error {
+	var params api.SubscriberTokenParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	return w.Handler.DeleteSubscriber(ctx, params) //nolint:wrapcheck
+}
+
+// GetWebhooks converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetWebhooks(ctx echo.Context) error {
+	return w.Handler.GetWebhooks(ctx) //nolint:wrapcheck
+}
+
+// CreateWebhook converts echo context to params.
+func (w *ExtensionInterfaceWrapper) CreateWebhook(ctx echo.Context) error {
+	return w.Handler.CreateWebhook(ctx) //nolint:wrapcheck
+}
+
+// DeleteWebhook converts echo context to params.
+func (w *ExtensionInterfaceWrapper) DeleteWebhook(ctx echo.Context) error {
+	var webhookID uuid.UUID
+
+	err := bindPathParameter(ctx, "webhookId", &webhookID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.DeleteWebhook(ctx, webhookID) //nolint:wrapcheck
+}
+
+// GetWebhookDeliveries converts echo context to params.
+func (w *ExtensionInterfaceWrapper) GetWebhookDeliveries(ctx echo.Context) error {
+	var (
+		webhookID uuid.UUID
+		params    api.GetWebhookDeliveriesParams
+	)
+
+	err := bindPathParameter(ctx, "webhookId", &webhookID)
+	if err != nil {
+		return err
+	}
+
+	err = (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for query parameters: %s", err))
+	}
+
+	return w.Handler.GetWebhookDeliveries(ctx, webhookID, params) //nolint:wrapcheck
+}
+
+// RetryWebhookDelivery converts echo context to params.
+func (w *ExtensionInterfaceWrapper) RetryWebhookDelivery(ctx echo.Context) error {
+	var (
+		webhookID  uuid.UUID
+		deliveryID uint64
+	)
+
+	err := bindPathParameter(ctx, "webhookId", &webhookID)
+	if err != nil {
+		return err
+	}
+
+	err = bindPathParameter(ctx, "deliveryId", &deliveryID)
+	if err != nil {
+		return err
+	}
+
+	return w.Handler.RetryWebhookDelivery(ctx, webhookID, deliveryID) //nolint:wrapcheck
+}
+
+// extensionRoute declares a route served in addition to the OpenAPI spec.
+type extensionRoute struct {
+	method      string
+	path        string
+	operationID string
+	scope       auth.Scope
+	handler     func(wrapper *ExtensionInterfaceWrapper) echo.HandlerFunc
+}
+
+// extensionRoutes lists all routes served in addition to the OpenAPI spec with their required scope.
+var extensionRoutes = []extensionRoute{ //nolint:gochecknoglobals
+	{
+		method: http.MethodPost, path: "/alertmanager", operationID: "ReceiveAlerts", scope: auth.ScopeEditor,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.ReceiveAlerts },
+	},
+	{
+		method: http.MethodGet, path: "/apikeys", operationID: "GetAPIKeys", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetAPIKeys },
+	},
+	{
+		method: http.MethodPost, path: "/apikeys", operationID: "CreateAPIKey", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.CreateAPIKey },
+	},
+	{
+		method: http.MethodDelete, path: "/apikeys/:apiKeyId", operationID: "RevokeAPIKey", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.RevokeAPIKey },
+	},
+	{
+		method: http.MethodGet, path: "/audit", operationID: "GetAuditEntries", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetAuditEntries },
+	},
+	{
+		method: http.MethodGet, path: "/componentgroups", operationID: "GetComponentGroups", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetComponentGroups },
+	},
+	{
+		method: http.MethodPost, path: "/componentgroups", operationID: "CreateComponentGroup", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.CreateComponentGroup },
+	},
+	{
+		method: http.MethodDelete, path: "/componentgroups/:groupId", operationID: "DeleteComponentGroup",
+		scope:   auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.DeleteComponentGroup },
+	},
+	{
+		method: http.MethodGet, path: "/componentgroups/:groupId", operationID: "GetComponentGroup",
+		scope:   auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetComponentGroup },
+	},
+	{
+		method: http.MethodPatch, path: "/componentgroups/:groupId", operationID: "UpdateComponentGroup",
+		scope:   auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.UpdateComponentGroup },
+	},
+	{
+		method: http.MethodPut, path: "/componentgroups/:groupId/components/:componentId",
+		operationID: "AddComponentGroupMember", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.AddComponentGroupMember },
+	},
+	{
+		method: http.MethodDelete, path: "/componentgroups/:groupId/components/:componentId",
+		operationID: "RemoveComponentGroupMember", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.RemoveComponentGroupMember },
+	},
+	{
+		method: http.MethodGet, path: "/components/:componentId/availability", operationID: "GetComponentAvailability",
+		scope:   auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetComponentAvailability },
+	},
+	{
+		method: http.MethodGet, path: "/components/:componentId/dependencies", operationID: "GetComponentDependencies",
+		scope:   auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetComponentDependencies },
+	},
+	{
+		method: http.MethodPut, path: "/components/:componentId/dependencies/:dependencyId",
+		operationID: "CreateComponentDependency", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.CreateComponentDependency },
+	},
+	{
+		method: http.MethodDelete, path: "/components/:componentId/dependencies/:dependencyId",
+		operationID: "DeleteComponentDependency", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.DeleteComponentDependency },
+	},
+	{
+		method: http.MethodPost, path: "/components/:componentId/restore", operationID: "RestoreComponent",
+		scope:   auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.RestoreComponent },
+	},
+	{
+		method: http.MethodGet, path: "/dependencies", operationID: "GetDependencies", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetDependencies },
+	},
+	{
+		method: http.MethodGet, path: "/events", operationID: "GetEvents", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetEvents },
+	},
+	{
+		method: http.MethodGet, path: "/feeds/incidents.atom", operationID: "GetIncidentsAtom", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetIncidentsAtom },
+	},
+	{
+		method: http.MethodGet, path: "/feeds/incidents.rss", operationID: "GetIncidentsRSS", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetIncidentsRSS },
+	},
+	{
+		method: http.MethodGet, path: "/incidents/:incidentId/revisions", operationID: "GetIncidentRevisions",
+		scope:   auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetIncidentRevisions },
+	},
+	{
+		method: http.MethodPost, path: "/incidents/:incidentId/restore", operationID: "RestoreIncident",
+		scope:   auth.ScopeEditor,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.RestoreIncident },
+	},
+	{
+		method: http.MethodGet, path: "/maintenances.ics", operationID: "GetMaintenancesICS", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetMaintenancesICS },
+	},
+	{
+		method: http.MethodGet, path: "/status", operationID: "GetStatus", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetStatus },
+	},
+	{
+		method: http.MethodPost, path: "/subscribers", operationID: "CreateSubscriber", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.CreateSubscriber },
+	},
+	{
+		method: http.MethodGet, path: "/subscribers/confirm", operationID: "ConfirmSubscriber", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.ConfirmSubscriber },
+	},
+	{
+		method: http.MethodGet, path: "/subscribers/unsubscribe", operationID: "DeleteSubscriber", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.DeleteSubscriber },
+	},
+	{
+		method: http.MethodPost, path: "/subscribers/unsubscribe", operationID: "DeleteSubscriber", scope: auth.ScopeRead,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.DeleteSubscriber },
+	},
+	{
+		method: http.MethodGet, path: "/webhooks", operationID: "GetWebhooks", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetWebhooks },
+	},
+	{
+		method: http.MethodPost, path: "/webhooks", operationID: "CreateWebhook", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.CreateWebhook },
+	},
+	{
+		method: http.MethodDelete, path: "/webhooks/:webhookId", operationID: "DeleteWebhook", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.DeleteWebhook },
+	},
+	{
+		method: http.MethodGet, path: "/webhooks/:webhookId/deliveries", operationID: "GetWebhookDeliveries",
+		scope:   auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.GetWebhookDeliveries },
+	},
+	{
+		method: http.MethodPost, path: "/webhooks/:webhookId/deliveries/:deliveryId/retry",
+		operationID: "RetryWebhookDelivery", scope: auth.ScopeAdmin,
+		handler: func(w *ExtensionInterfaceWrapper) echo.HandlerFunc { return w.RetryWebhookDelivery },
+	},
+}
+
+// RegisterExtensionHandlers adds each extension route to the EchoRouter.
+func RegisterExtensionHandlers(router apiServerDefinition.EchoRouter, si ExtensionInterface) {
+	wrapper := ExtensionInterfaceWrapper{
+		Handler: si,
+	}
+
+	for _, route := range extensionRoutes {
+		handler := route.handler(&wrapper)
+
+		switch route.method {
+		case http.MethodGet:
+			router.GET(route.path, handler)
+		case http.MethodPost:
+			router.POST(route.path, handler)
+		case http.MethodPut:
+			router.PUT(route.path, handler)
+		case http.MethodPatch:
+			router.PATCH(route.path, handler)
+		case http.MethodDelete:
+			router.DELETE(route.path, handler)
+		}
+	}
+}
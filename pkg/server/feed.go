@@ -0,0 +1,192 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/feed"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	// feedIncidentLimit is the number of latest incidents rendered to a feed.
+	feedIncidentLimit = 50
+	// feedTitle names the feeds of incidents.
+	feedTitle = "Status page incidents"
+	// feedAuthor names the publisher of the feeds.
+	feedAuthor = "Status Page"
+	// feedMaintenanceCategory tags entries of maintenances.
+	feedMaintenanceCategory = "maintenance"
+)
+
+// GetIncidentsAtom renders the latest incidents and their updates as Atom feed.
+func (i *Implementation) GetIncidentsAtom(ctx echo.Context, params api.GetFeedParams) error {
+	return i.sendIncidentFeed(ctx, params, "GetIncidentsAtom", feed.AtomContentType, (*feed.Feed).Atom)
+}
+
+// GetIncidentsRSS renders the latest incidents and their updates as RSS feed.
+func (i *Implementation) GetIncidentsRSS(ctx echo.Context, params api.GetFeedParams) error {
+	return i.sendIncidentFeed(ctx, params, "GetIncidentsRSS", feed.RSSContentType, (*feed.Feed).RSS)
+}
+
+func (i *Implementation) sendIncidentFeed(
+	ctx echo.Context,
+	params api.GetFeedParams,
+	handler string,
+	contentType string,
+	render func(*feed.Feed) ([]byte, error),
+) error {
+	var incidents []*DbDef.Incident
+
+	logger := i.logger.With().Str("handler", handler).Logger()
+	logger.Debug().Interface("params", params).Send()
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	query, err := filterIncidents(dbSession, params)
+	if err != nil {
+		logger.Warn().Err(err).Msg("error parsing filter")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	res := query.
+		Preload("Affects").
+		Preload("Updates").
+		Order("began_at desc").
+		Limit(feedIncidentLimit).
+		Find(&incidents)
+	if res.Error != nil {
+		logger.Error().Err(res.Error).Msg("error loading incidents")
+
+		return echo.ErrInternalServerError
+	}
+
+	baseURL := ctx.Scheme() + "://" + ctx.Request().Host
+	incidentFeed := incidentsToFeed(incidents, baseURL, baseURL+ctx.Request().URL.RequestURI())
+
+	body, err := render(incidentFeed)
+	if err != nil {
+		logger.Error().Err(err).Msg("error rendering feed")
+
+		return echo.ErrInternalServerError
+	}
+
+	return sendCacheable(ctx, contentType, body, incidentFeed.Updated)
+}
+
+// filterIncidents restricts the query to incidents affecting the component or components with the labels.
+func filterIncidents(dbSession *gorm.DB, params api.GetFeedParams) (*gorm.DB, error) {
+	query := dbSession
+
+	if params.Component != nil {
+		query = query.Where("id IN (SELECT incident_id FROM impacts WHERE component_id = ?)", *params.Component)
+	}
+
+	if len(params.Label) > 0 {
+		labels, err := DbDef.LabelsFromSelectors(params.Label)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing labels: %w", err)
+		}
+
+		containsLabels, err := DbDef.ContainsLabels(dbSession, "components.labels", labels)
+		if err != nil {
+			return nil, fmt.Errorf("error matching labels: %w", err)
+		}
+
+		query = query.Where(
+			"id IN (SELECT impacts.incident_id FROM impacts JOIN components ON components.id = impacts.component_id "+
+				"WHERE ?)",
+			containsLabels,
+		)
+	}
+
+	return query, nil
+}
+
+// incidentsToFeed creates a feed with one entry for the beginning of each incident and one per update, newest first.
+func incidentsToFeed(incidents []*DbDef.Incident, baseURL string, feedURL string) *feed.Feed {
+	incidentFeed := feed.Feed{
+		ID:      feedURL,
+		Title:   feedTitle,
+		Author:  feedAuthor,
+		Link:    feedURL,
+		Updated: time.Time{},
+		Entries: []feed.Entry{},
+	}
+
+	for _, incident := range incidents {
+		incidentURL := baseURL + "/incidents/" + incident.ID.String()
+		incidentTitle := valueOrEmpty(incident.DisplayName)
+
+		var categories []string
+		if incident.IsMaintenance() {
+			categories = []string{feedMaintenanceCategory}
+		}
+
+		if incident.BeganAt != nil {
+			incidentFeed.Entries = append(incidentFeed.Entries, feed.Entry{
+				ID:         "urn:uuid:" + incident.ID.String(),
+				Title:      incidentTitle,
+				Summary:    valueOrEmpty(incident.Description),
+				Link:       incidentURL,
+				Published:  *incident.BeganAt,
+				Updated:    *incident.BeganAt,
+				Categories: categories,
+			})
+		}
+
+		if incident.Updates == nil {
+			continue
+		}
+
+		for _, update := range *incident.Updates {
+			var createdAt time.Time
+			if update.CreatedAt != nil {
+				createdAt = *update.CreatedAt
+			}
+
+			incidentFeed.Entries = append(incidentFeed.Entries, feed.Entry{
+				ID:         "urn:uuid:" + incidentUpdateGUID(incident.ID, *update.Order).String(),
+				Title:      incidentTitle + ": " + valueOrEmpty(update.DisplayName),
+				Summary:    valueOrEmpty(update.Description),
+				Link:       incidentURL + "/updates/" + strconv.Itoa(*update.Order),
+				Published:  createdAt,
+				Updated:    createdAt,
+				Categories: categories,
+			})
+		}
+	}
+
+	slices.SortStableFunc(incidentFeed.Entries, func(a, b feed.Entry) int {
+		return b.Updated.Compare(a.Updated)
+	})
+
+	if len(incidentFeed.Entries) > 0 {
+		incidentFeed.Updated = incidentFeed.Entries[0].Updated
+	}
+
+	return &incidentFeed
+}
+
+// incidentUpdateGUID derives a stable UUID of an incident update from the incident ID and the update order.
+func incidentUpdateGUID(incidentID uuid.UUID, order int) uuid.UUID {
+	return uuid.NewSHA1(incidentID, []byte(strconv.Itoa(order)))
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
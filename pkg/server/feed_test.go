@@ -0,0 +1,241 @@
+package server_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/feed"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = DescribeTableSubtree("Feed", func(dialect test.Dialect) {
+	const (
+		feedIncidentID  = "4bcd4e61-b1d5-4c8b-9ec4-54e0d7d0e0d1"
+		feedComponentID = "8f8b9c1e-3b5e-4c55-a58c-4b6c3b7b5d0e"
+		feedImpactType  = "c3fc130d-e6c4-4f94-86ac-b9f7d7f8d6a9"
+	)
+
+	var (
+		// sub loggers
+		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		// expected SQL
+		expectedFeedIncidentsQuery = regexp.
+						QuoteMeta(`SELECT * FROM "incidents" WHERE "incidents"."deleted_at" IS NULL ORDER BY began_at desc LIMIT $1`)
+		expectedLabelCondition = map[test.Dialect]string{
+			test.Postgres: `components.labels @> $2`,
+			test.SQLite:   `NOT EXISTS (SELECT 1 FROM json_each($2) AS selector WHERE json_extract(components.labels, '$."' || selector.key || '"') IS NOT selector.value)`, //nolint:lll
+		}
+		expectedFilteredFeedIncidentsQuery = regexp.
+							QuoteMeta(`SELECT * FROM "incidents" WHERE id IN (SELECT incident_id FROM impacts WHERE component_id = $1) AND id IN (SELECT impacts.incident_id FROM impacts JOIN components ON components.id = impacts.component_id WHERE ` + expectedLabelCondition[dialect] + `) AND "incidents"."deleted_at" IS NULL ORDER BY began_at desc LIMIT $3`) //nolint:lll
+		expectedFeedImpactQuery = regexp.
+					QuoteMeta(`SELECT * FROM "impacts" WHERE "impacts"."incident_id" = $1`)
+		expectedFeedIncidentUpdateQuery = regexp.
+						QuoteMeta(`SELECT * FROM "incident_updates" WHERE "incident_updates"."incident_id" = $1`)
+
+		beganAt   = time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+		updatedAt = time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)
+	)
+
+	expectIncidents := func() {
+		sqlMock.
+			ExpectQuery(expectedFeedIncidentsQuery).
+			WithArgs(50).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"id", "display_name", "description", "began_at", "ended_at"}).
+				AddRow(feedIncidentID, "Storage upgrade", "Planned upgrade", beganAt, beganAt.Add(4*time.Hour)),
+			)
+		sqlMock.
+			ExpectQuery(expectedFeedImpactQuery).
+			WithArgs(feedIncidentID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"incident_id", "component_id", "impact_type_id", "severity"}).
+				AddRow(feedIncidentID, feedComponentID, feedImpactType, 0),
+			)
+		sqlMock.
+			ExpectQuery(expectedFeedIncidentUpdateQuery).
+			WithArgs(feedIncidentID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"incident_id", "order", "display_name", "description", "created_at"}).
+				AddRow(feedIncidentID, 0, "Started", "The upgrade started.", updatedAt),
+			)
+	}
+
+	BeforeEach(func() {
+		// setup database and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+	})
+
+	Describe("GetIncidentsAtom", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger, http.MethodGet, "/feeds/incidents.atom", nil,
+			)
+		})
+
+		Context("with valid data", func() {
+			It("should return an entry per incident and update", func() {
+				// Arrange
+				expectIncidents()
+
+				updateGUID := uuid.NewSHA1(uuid.MustParse(feedIncidentID), []byte("0"))
+
+				// Act
+				err := handlers.GetIncidentsAtom(ctx, api.GetFeedParams{Component: nil, Label: nil})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Header().Get(echo.HeaderContentType)).Should(Equal(feed.AtomContentType))
+				Ω(res.Header().Get(echo.HeaderLastModified)).Should(Equal("Fri, 01 Mar 2024 12:30:00 GMT"))
+				Ω(res.Header().Get("ETag")).Should(MatchRegexp(`^"[0-9a-f]{32}"$`))
+				Ω(res.Body.String()).Should(ContainSubstring("<id>urn:uuid:" + feedIncidentID + "</id>"))
+				Ω(res.Body.String()).Should(ContainSubstring("<id>urn:uuid:" + updateGUID.String() + "</id>"))
+				Ω(res.Body.String()).Should(ContainSubstring("<title>Storage upgrade: Started</title>"))
+				Ω(res.Body.String()).Should(ContainSubstring(`<category term="maintenance"></category>`))
+				Ω(res.Body.String()).Should(ContainSubstring(
+					`<link href="http://example.com/incidents/` + feedIncidentID + `/updates/0"></link>`,
+				))
+			})
+		})
+
+		Context("with matching entity tag", func() {
+			It("should return 304 not modified", func() {
+				// Arrange
+				expectIncidents()
+				expectIncidents()
+
+				err := handlers.GetIncidentsAtom(ctx, api.GetFeedParams{Component: nil, Label: nil})
+				Ω(err).ShouldNot(HaveOccurred())
+
+				etag := res.Header().Get("ETag")
+
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger, http.MethodGet, "/feeds/incidents.atom", nil,
+				)
+				ctx.Request().Header.Set("If-None-Match", `"other", `+etag)
+
+				// Act
+				err = handlers.GetIncidentsAtom(ctx, api.GetFeedParams{Component: nil, Label: nil})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNotModified))
+				Ω(res.Body.String()).Should(BeEmpty())
+			})
+		})
+
+		Context("with unmodified feed since the last request", func() {
+			It("should return 304 not modified", func() {
+				// Arrange
+				expectIncidents()
+
+				ctx.Request().Header.Set(echo.HeaderIfModifiedSince, "Fri, 01 Mar 2024 12:30:00 GMT")
+
+				// Act
+				err := handlers.GetIncidentsAtom(ctx, api.GetFeedParams{Component: nil, Label: nil})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNotModified))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedFeedIncidentsQuery).
+					WillReturnError(test.ErrTestError)
+
+				// Act
+				err := handlers.GetIncidentsAtom(ctx, api.GetFeedParams{Component: nil, Label: nil})
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+
+	Describe("GetIncidentsRSS", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger, http.MethodGet, "/feeds/incidents.rss", nil,
+			)
+		})
+
+		Context("with component and label filter", func() {
+			It("should return the filtered feed", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedFilteredFeedIncidentsQuery).
+					WithArgs(feedComponentID, `{"region":"datacenter-west"}`, 50).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+				// Act
+				err := handlers.GetIncidentsRSS(ctx, api.GetFeedParams{
+					Component: test.Ptr(uuid.MustParse(feedComponentID)),
+					Label:     []string{"region:datacenter-west"},
+				})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Header().Get(echo.HeaderContentType)).Should(Equal(feed.RSSContentType))
+				Ω(res.Header().Get(echo.HeaderLastModified)).Should(BeEmpty())
+				Ω(res.Body.String()).Should(ContainSubstring(`<rss version="2.0">`))
+			})
+		})
+
+		Context("with invalid label", func() {
+			It("should return 400 bad request", func() {
+				// Act
+				err := handlers.GetIncidentsRSS(ctx, api.GetFeedParams{Component: nil, Label: []string{"region"}})
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+			})
+		})
+	})
+}, test.DialectEntries())
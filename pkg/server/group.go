@@ -0,0 +1,451 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// errGroupCycle is returned, when a group would be nested in itself or one of its nested groups.
+var errGroupCycle = errors.New("component group would be nested in itself")
+
+// GetComponentGroups lists all component groups by order.
+func (i *Implementation) GetComponentGroups(ctx echo.Context) error {
+	logger := i.logger.With().Str("handler", "GetComponentGroups").Logger()
+	logger.Debug().Send()
+
+	groups, err := i.storage.WithContext(ctx.Request().Context()).ListComponentGroups()
+	if err != nil {
+		logger.Error().Err(err).Msg("error loading component groups")
+
+		return echo.ErrInternalServerError
+	}
+
+	response := api.ComponentGroupListResponse{Data: make([]api.ComponentGroupResponseData, 0, len(groups))}
+
+	for _, group := range groups {
+		response.Data = append(response.Data, group.ToAPIResponse())
+	}
+
+	return ctx.JSON(http.StatusOK, response) //nolint:wrapcheck
+}
+
+// GetComponentGroup retrieves a specific component group by ID.
+func (i *Implementation) GetComponentGroup(ctx echo.Context, groupID uuid.UUID) error {
+	logger := i.logger.With().Str("handler", "GetComponentGroup").Interface("id", groupID).Logger()
+	logger.Debug().Send()
+
+	group, err := i.storage.WithContext(ctx.Request().Context()).GetComponentGroup(groupID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.Warn().Msg("component group not found")
+
+			return echo.ErrNotFound
+		}
+
+		logger.Error().Err(err).Msg("error loading component group")
+
+		return echo.ErrInternalServerError
+	}
+
+	return ctx.JSON(http.StatusOK, api.ComponentGroupResponse{Data: group.ToAPIResponse()}) //nolint:wrapcheck
+}
+
+// CreateComponentGroup handles creation of component groups.
+// Groups without order are placed after the other groups of their parent.
+func (i *Implementation) CreateComponentGroup(ctx echo.Context) error { //nolint:funlen
+	var request api.ComponentGroupRequest
+
+	logger := i.logger.With().Str("handler", "CreateComponentGroup").Logger()
+
+	err := ctx.Bind(&request)
+	if err != nil {
+		logger.Warn().Err(err).Msg("error binding request")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	if request.DisplayName == nil {
+		logger.Warn().Msg("missing display name")
+
+		return echo.ErrBadRequest.WithInternal(DbDef.NewFieldError("displayName", DbDef.ErrEmptyValue))
+	}
+
+	logger.Debug().Interface("request", request).Send()
+
+	group, err := DbDef.ComponentGroupFromAPI(&request)
+	if err != nil {
+		logger.Warn().Err(err).Msg("error parsing request")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		groups, transactionErr := repo.ListComponentGroups()
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error loading component groups")
+
+			return echo.ErrInternalServerError
+		}
+
+		if group.ParentID != nil && findGroup(groups, *group.ParentID) == nil {
+			logger.Warn().Msg("parent group not found")
+
+			return echo.NewHTTPError(http.StatusBadRequest, "parent group not found")
+		}
+
+		if group.Order == nil {
+			group.Order = nextGroupOrder(groups, group.ParentID)
+		}
+
+		transactionErr = repo.CreateComponentGroup(group)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error creating component group")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetComponentGroup,
+			targetID:   group.ID.String(),
+			before:     nil,
+			after:      group,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording creation")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.JSON(http.StatusCreated, apiServerDefinition.IdResponse{ //nolint:wrapcheck
+		Id: group.ID,
+	})
+}
+
+// UpdateComponentGroup handles updates of component groups.
+// A group cannot be nested in itself or one of its nested groups.
+func (i *Implementation) UpdateComponentGroup(ctx echo.Context, groupID uuid.UUID) error { //nolint:funlen
+	var request api.ComponentGroupRequest
+
+	logger := i.logger.With().Str("handler", "UpdateComponentGroup").Interface("id", groupID).Logger()
+
+	err := ctx.Bind(&request)
+	if err != nil {
+		logger.Warn().Err(err).Msg("error binding request")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	if request == (api.ComponentGroupRequest{}) { //nolint:exhaustruct
+		logger.Warn().Msg("empty request")
+
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
+	}
+
+	logger.Debug().Interface("request", request).Send()
+
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		groups, transactionErr := repo.ListComponentGroups()
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error loading component groups")
+
+			return echo.ErrInternalServerError
+		}
+
+		group := findGroup(groups, groupID)
+		if group == nil {
+			logger.Warn().Msg("component group not found")
+
+			return echo.ErrNotFound
+		}
+
+		transactionErr = mergeGroupRequest(group, &request, groups)
+		if errors.Is(transactionErr, storage.ErrReferenced) {
+			logger.Warn().Msg("parent group not found")
+
+			return echo.NewHTTPError(http.StatusBadRequest, "parent group not found")
+		} else if transactionErr != nil {
+			logger.Warn().Err(transactionErr).Msg("component group cycle")
+
+			return echo.NewHTTPError(http.StatusConflict, transactionErr.Error())
+		}
+
+		dbGroup, updatedGroup, transactionErr := repo.UpdateComponentGroup(group)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error updating component group")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationUpdate,
+			targetType: DbDef.AuditTargetComponentGroup,
+			targetID:   groupID.String(),
+			before:     dbGroup,
+			after:      updatedGroup,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording update")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
+
+// DeleteComponentGroup handles deletion of component groups without nested groups.
+// The member components are not deleted.
+func (i *Implementation) DeleteComponentGroup(ctx echo.Context, groupID uuid.UUID) error {
+	logger := i.logger.With().Str("handler", "DeleteComponentGroup").Interface("id", groupID).Logger()
+	logger.Debug().Send()
+
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		dbGroup, transactionErr := repo.DeleteComponentGroup(groupID)
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("component group not found")
+
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrReferenced) {
+			logger.Warn().Msg("component group has nested groups")
+
+			return echo.NewHTTPError(http.StatusConflict, "component group has nested groups")
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error deleting component group")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationDelete,
+			targetType: DbDef.AuditTargetComponentGroup,
+			targetID:   groupID.String(),
+			before:     dbGroup,
+			after:      nil,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording deletion")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
+
+// AddComponentGroupMember adds the component to the group.
+// Adding an existing member succeeds without changes.
+func (i *Implementation) AddComponentGroupMember( //nolint:funlen
+	ctx echo.Context,
+	groupID, componentID uuid.UUID,
+) error {
+	logger := i.logger.With().
+		Str("handler", "AddComponentGroupMember").
+		Interface("id", groupID).
+		Interface("component", componentID).
+		Logger()
+	logger.Debug().Send()
+
+	member := &DbDef.ComponentGroupMember{
+		Group:       nil,
+		Component:   nil,
+		GroupID:     &groupID,
+		ComponentID: &componentID,
+	}
+
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		group, transactionErr := repo.GetComponentGroup(groupID)
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("component group not found")
+
+			return echo.ErrNotFound
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error loading component group")
+
+			return echo.ErrInternalServerError
+		}
+
+		if slices.Contains(group.ComponentIDs(), componentID) {
+			logger.Debug().Msg("member exists")
+
+			return nil
+		}
+
+		transactionErr = repo.AddComponentGroupMember(member)
+		if errors.Is(transactionErr, storage.ErrReferenced) {
+			logger.Warn().Msg("component not found")
+
+			return echo.ErrNotFound
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error adding member")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetComponentGroupMember,
+			targetID:   groupMemberTargetID(groupID, componentID),
+			before:     nil,
+			after:      member,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording creation")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
+
+// RemoveComponentGroupMember removes the component from the group.
+func (i *Implementation) RemoveComponentGroupMember(ctx echo.Context, groupID, componentID uuid.UUID) error {
+	logger := i.logger.With().
+		Str("handler", "RemoveComponentGroupMember").
+		Interface("id", groupID).
+		Interface("component", componentID).
+		Logger()
+	logger.Debug().Send()
+
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		dbMember, transactionErr := repo.RemoveComponentGroupMember(groupID, componentID)
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("member not found")
+
+			return echo.ErrNotFound
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error removing member")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationDelete,
+			targetType: DbDef.AuditTargetComponentGroupMember,
+			targetID:   groupMemberTargetID(groupID, componentID),
+			before:     dbMember,
+			after:      nil,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording deletion")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
+
+// mergeGroupRequest applies the set fields of the request to the group.
+// It fails with [storage.ErrReferenced] for unknown parents and with [errGroupCycle] for parents nested in the group.
+func mergeGroupRequest(
+	group *DbDef.ComponentGroup,
+	request *api.ComponentGroupRequest,
+	groups []*DbDef.ComponentGroup,
+) error {
+	if request.DisplayName != nil {
+		group.DisplayName = request.DisplayName
+	}
+
+	if request.Description != nil {
+		group.Description = request.Description
+	}
+
+	if request.Order != nil {
+		group.Order = request.Order
+	}
+
+	if !request.ParentID.Set {
+		return nil
+	}
+
+	group.ParentID = request.ParentID.Value
+
+	for parentID := group.ParentID; parentID != nil; {
+		if *parentID == group.ID {
+			return errGroupCycle
+		}
+
+		parent := findGroup(groups, *parentID)
+		if parent == nil {
+			return storage.ErrReferenced
+		}
+
+		parentID = parent.ParentID
+	}
+
+	return nil
+}
+
+// findGroup returns the group with the ID, nil if it is not listed.
+func findGroup(groups []*DbDef.ComponentGroup, groupID uuid.UUID) *DbDef.ComponentGroup {
+	for _, group := range groups {
+		if group.ID == groupID {
+			return group
+		}
+	}
+
+	return nil
+}
+
+// nextGroupOrder returns the order after the highest order among the groups of the parent.
+func nextGroupOrder(groups []*DbDef.ComponentGroup, parentID *uuid.UUID) *int {
+	order := 0
+
+	for _, group := range groups {
+		if sameParent(group.ParentID, parentID) && group.Order != nil && *group.Order >= order {
+			order = *group.Order + 1
+		}
+	}
+
+	return &order
+}
+
+// sameParent reports, if both parents are the same group or both are the top level.
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// groupMemberTargetID identifies a group membership in the audit log.
+func groupMemberTargetID(groupID, componentID uuid.UUID) string {
+	return groupID.String() + "/" + componentID.String()
+}
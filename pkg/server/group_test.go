@@ -0,0 +1,255 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("ComponentGroup", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		store *storage.Memory
+		repo  storage.Repository
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		network         *db.Component
+		infrastructure  *db.ComponentGroup
+		datacenterWest  *db.ComponentGroup
+		groupComponents = func(groupID uuid.UUID) []uuid.UUID {
+			group, err := repo.GetComponentGroup(groupID)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			return group.ComponentIDs()
+		}
+
+		newContext = func(method, url string, body interface{}) (echo.Context, *httptest.ResponseRecorder) {
+			return test.MustCreateEchoContextAndResponseWriter(echoLogger, method, url, body)
+		}
+	)
+
+	BeforeEach(func() {
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store = storage.NewMemory()
+		repo = store.WithContext(context.Background())
+		handlers = server.New(store, nil, nil, nil, handlerLogger)
+
+		network = &db.Component{DisplayName: test.Ptr("Network")}
+		Ω(repo.CreateComponent(network)).Should(Succeed())
+
+		infrastructure = &db.ComponentGroup{DisplayName: test.Ptr("Infrastructure"), Order: test.Ptr(0)}
+		Ω(repo.CreateComponentGroup(infrastructure)).Should(Succeed())
+
+		datacenterWest = &db.ComponentGroup{
+			DisplayName: test.Ptr("Datacenter West"),
+			ParentID:    &infrastructure.ID,
+			Order:       test.Ptr(0),
+		}
+		Ω(repo.CreateComponentGroup(datacenterWest)).Should(Succeed())
+	})
+
+	Describe("CreateComponentGroup", func() {
+		It("should place groups without order last", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodPost, "/componentgroups", api.ComponentGroupRequest{
+				DisplayName: test.Ptr("Datacenter East"),
+				ParentID:    api.Nullable[uuid.UUID]{Set: true, Value: &infrastructure.ID},
+			})
+
+			// Act
+			err := handlers.CreateComponentGroup(ctx)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusCreated))
+
+			var response apiServerDefinition.IdResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+
+			group, err := repo.GetComponentGroup(response.Id)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*group.ParentID).Should(Equal(infrastructure.ID))
+			Ω(*group.Order).Should(Equal(1))
+			Ω(store.AuditEntries()).Should(ContainElement(HaveField("TargetType", db.AuditTargetComponentGroup)))
+		})
+
+		It("should refuse unknown parents", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodPost, "/componentgroups", api.ComponentGroupRequest{
+				DisplayName: test.Ptr("Orphan"),
+				ParentID:    api.Nullable[uuid.UUID]{Set: true, Value: test.Ptr(uuid.New())},
+			})
+
+			// Act
+			err := handlers.CreateComponentGroup(ctx)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+		})
+
+		It("should require a display name", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodPost, "/componentgroups", map[string]interface{}{"order": 1})
+
+			// Act
+			err := handlers.CreateComponentGroup(ctx)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+		})
+	})
+
+	Describe("UpdateComponentGroup", func() {
+		It("should move groups to the top level", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodPatch, "/", map[string]interface{}{"parentId": nil, "order": 1})
+
+			// Act
+			err := handlers.UpdateComponentGroup(ctx, datacenterWest.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+
+			group, err := repo.GetComponentGroup(datacenterWest.ID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(group.ParentID).Should(BeNil())
+			Ω(*group.Order).Should(Equal(1))
+			Ω(*group.DisplayName).Should(Equal("Datacenter West"))
+		})
+
+		It("should refuse to nest groups in themselves", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodPatch, "/", map[string]interface{}{"parentId": datacenterWest.ID})
+
+			// Act
+			err := handlers.UpdateComponentGroup(ctx, infrastructure.ID)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusConflict))
+		})
+
+		It("should return not found for unknown groups", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodPatch, "/", map[string]interface{}{"order": 1})
+
+			// Act
+			err := handlers.UpdateComponentGroup(ctx, uuid.New())
+
+			// Assert
+			Ω(err).Should(Equal(echo.ErrNotFound))
+		})
+	})
+
+	Describe("DeleteComponentGroup", func() {
+		It("should refuse to delete groups with nested groups", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodDelete, "/", nil)
+
+			// Act
+			err := handlers.DeleteComponentGroup(ctx, infrastructure.ID)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusConflict))
+		})
+
+		It("should delete groups without nested groups", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodDelete, "/", nil)
+
+			// Act
+			err := handlers.DeleteComponentGroup(ctx, datacenterWest.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(repo.ListComponentGroups()).Should(HaveLen(1))
+		})
+	})
+
+	Describe("AddComponentGroupMember", func() {
+		It("should add members once", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodPut, "/", nil)
+
+			// Act
+			err := handlers.AddComponentGroupMember(ctx, infrastructure.ID, network.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(handlers.AddComponentGroupMember(ctx, infrastructure.ID, network.ID)).Should(Succeed())
+			Ω(groupComponents(infrastructure.ID)).Should(Equal([]uuid.UUID{network.ID}))
+		})
+
+		It("should return not found for unknown groups or components", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodPut, "/", nil)
+
+			// Act
+			errGroup := handlers.AddComponentGroupMember(ctx, uuid.New(), network.ID)
+			errComponent := handlers.AddComponentGroupMember(ctx, infrastructure.ID, uuid.New())
+
+			// Assert
+			Ω(errGroup).Should(Equal(echo.ErrNotFound))
+			Ω(errComponent).Should(Equal(echo.ErrNotFound))
+		})
+	})
+
+	Describe("RemoveComponentGroupMember", func() {
+		It("should remove members", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodDelete, "/", nil)
+			Ω(handlers.AddComponentGroupMember(ctx, infrastructure.ID, network.ID)).Should(Succeed())
+
+			// Act
+			err := handlers.RemoveComponentGroupMember(ctx, infrastructure.ID, network.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusNoContent))
+			Ω(groupComponents(infrastructure.ID)).Should(BeEmpty())
+			Ω(handlers.RemoveComponentGroupMember(ctx, infrastructure.ID, network.ID)).Should(Equal(echo.ErrNotFound))
+		})
+	})
+
+	Describe("GetComponentGroups", func() {
+		It("should list all groups by order", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, "/componentgroups", nil)
+
+			// Act
+			err := handlers.GetComponentGroups(ctx)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var response api.ComponentGroupListResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(response.Data).Should(HaveLen(2))
+			Ω(response.Data[0].ID).Should(Equal(datacenterWest.ID))
+			Ω(response.Data[0].ParentID).Should(Equal(&infrastructure.ID))
+			Ω(response.Data[1].ID).Should(Equal(infrastructure.ID))
+		})
+	})
+})
@@ -5,23 +5,30 @@ import (
 	"net/http"
 
 	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
-// GetImpactTypes retrieves a list of all impact types.
+// GetImpactTypes retrieves a page of the impact types, sorted by name.
 func (i *Implementation) GetImpactTypes(ctx echo.Context) error {
-	var impactTypes []*DbDef.ImpactType
-
 	logger := i.logger.With().Str("handler", "GetImpactTypes").Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	page, err := pageFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid page parameters")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	impactTypes, next, err := i.storage.WithContext(ctx.Request().Context()).ListImpactTypes(page)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		logger.Warn().Err(err).Msg("invalid cursor")
 
-	res := dbSession.Find(&impactTypes)
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error loading impact types")
+		return echo.ErrBadRequest.WithInternal(err)
+	} else if err != nil {
+		logger.Error().Err(err).Msg("error loading impact types")
 
 		return echo.ErrInternalServerError
 	}
@@ -31,9 +38,7 @@ func (i *Implementation) GetImpactTypes(ctx echo.Context) error {
 		data[impactTypeIndex] = impactType.ToAPIResponse()
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.ImpactTypeListResponse{ //nolint:wrapcheck
-		Data: data,
-	})
+	return respondPage(ctx, data, next)
 }
 
 // CreateImpactType handles creation of impact types.
@@ -52,23 +57,34 @@ func (i *Implementation) CreateImpactType(ctx echo.Context) error { //nolint:dup
 	if request == (apiServerDefinition.CreateImpactTypeJSONRequestBody{}) { //nolint: exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
 	logger.Debug().Interface("request", request).Send()
 
 	impactType, err := DbDef.ImpactTypeFromAPI(&request)
 	if err != nil {
-		logger.Error().Err(err).Msg("error parsing request")
+		logger.Warn().Err(err).Msg("error parsing request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(err)
 	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		transactionErr := repo.CreateImpactType(impactType)
+		if transactionErr != nil {
+			return transactionErr //nolint:wrapcheck
+		}
 
-	res := dbSession.Create(&impactType)
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error creating impact type")
+		return recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetImpactType,
+			targetID:   impactType.ID.String(),
+			before:     nil,
+			after:      impactType,
+		})
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("error in transaction")
 
 		return echo.ErrInternalServerError
 	}
@@ -86,19 +102,48 @@ func (i *Implementation) DeleteImpactType(
 	logger := i.logger.With().Str("handler", "DeleteImpactType").Interface("id", impactTypeID).Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionErr := checkIfMatch(ctx, &logger, impactTypeID.String(), impactTypeVersion(repo, impactTypeID))
+		if transactionErr != nil {
+			return transactionErr
+		}
 
-	res := dbSession.Where("id = ?", impactTypeID).Delete(&DbDef.ImpactType{}) //nolint: exhaustruct
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error deleting impact type")
+		dbImpactType, transactionErr := repo.DeleteImpactType(impactTypeID)
+		if transactionErr == nil && !ifMatch.holds(dbImpactType.Version) {
+			transactionErr = storage.ErrConflict
+		}
 
-		return echo.ErrInternalServerError
-	}
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("impact type not found")
+
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrConflict) {
+			logger.Warn().Msg("impact type changed concurrently")
+
+			return ifMatch.conflict()
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error deleting impact type")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationDelete,
+			targetType: DbDef.AuditTargetImpactType,
+			targetID:   impactTypeID.String(),
+			before:     dbImpactType,
+			after:      nil,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording deletion")
 
-	if res.RowsAffected == 0 {
-		logger.Warn().Msg("impact type not found")
+			return echo.ErrInternalServerError
+		}
 
-		return echo.ErrNotFound
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
 
 	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
@@ -109,31 +154,39 @@ func (i *Implementation) GetImpactType( //nolint:dupl
 	ctx echo.Context,
 	impactTypeID apiServerDefinition.ImpactTypeIdPathParameter,
 ) error {
-	var impactType DbDef.ImpactType
-
 	logger := i.logger.With().Str("handler", "GetImpactType").Interface("id", impactTypeID).Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
-
-	res := dbSession.Where("id = ?", impactTypeID).First(&impactType)
-	if res.Error != nil {
-		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+	impactType, err := i.storage.WithContext(ctx.Request().Context()).GetImpactType(impactTypeID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
 			logger.Warn().Msg("impact type not found")
 
 			return echo.ErrNotFound
 		}
 
-		logger.Error().Err(res.Error).Msg("error loading impact type")
+		logger.Error().Err(err).Msg("error loading impact type")
 
 		return echo.ErrInternalServerError
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.ImpactTypeResponse{ //nolint:wrapcheck
+	return sendTagged(ctx, impactTypeID.String(), impactType.Version, apiServerDefinition.ImpactTypeResponse{
 		Data: impactType.ToAPIResponse(),
 	})
 }
 
+// impactTypeVersion loads the version of an impact type, for conditional changes.
+func impactTypeVersion(repo storage.Repository, impactTypeID DbDef.ID) func() (*int, error) {
+	return func() (*int, error) {
+		impactType, err := repo.GetImpactType(impactTypeID)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return impactType.Version, nil
+	}
+}
+
 // UpdateImpactType handles updates of impact types.
 func (i *Implementation) UpdateImpactType( //nolint:funlen
 	ctx echo.Context,
@@ -156,45 +209,54 @@ func (i *Implementation) UpdateImpactType( //nolint:funlen
 	if request == (apiServerDefinition.CreateImpactTypeJSONRequestBody{}) { //nolint: exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
 	logger.Debug().Interface("request", request).Send()
 
 	impactType, err := DbDef.ImpactTypeFromAPI(&request)
 	if err != nil {
-		logger.Error().Err(err).Msg("error parsing request")
+		logger.Warn().Err(err).Msg("error parsing request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(err)
 	}
 
 	impactType.ID = impactTypeID
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
-
-	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
-		// Use echo or http errors, wrapped errors may cause echo to behave strangely.
-		var (
-			dbImpactType     DbDef.ImpactType
-			transactionError error
-		)
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionError := checkIfMatch(ctx, &logger, impactTypeID.String(), impactTypeVersion(repo, impactTypeID))
+		if transactionError != nil {
+			return transactionError
+		}
 
-		dbImpactType.ID = impactType.ID
+		dbImpactType, updatedImpactType, transactionError := repo.UpdateImpactType(impactType)
+		if transactionError == nil && !ifMatch.holds(dbImpactType.Version) {
+			transactionError = storage.ErrConflict
+		}
 
-		transactionError = dbTx.First(&dbImpactType).Error
-		if errors.Is(transactionError, gorm.ErrRecordNotFound) {
+		if errors.Is(transactionError, storage.ErrNotFound) {
 			logger.Warn().Msg("impact type not found")
 
 			return echo.ErrNotFound
+		} else if errors.Is(transactionError, storage.ErrConflict) {
+			logger.Warn().Msg("impact type changed concurrently")
+
+			return ifMatch.conflict()
 		} else if transactionError != nil {
-			logger.Error().Err(transactionError).Msg("error loading impact type from database")
+			logger.Error().Err(transactionError).Msg("error updating impact type")
 
 			return echo.ErrInternalServerError
 		}
 
-		transactionError = dbTx.Updates(&impactType).Error
+		transactionError = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationUpdate,
+			targetType: DbDef.AuditTargetImpactType,
+			targetID:   impactType.ID.String(),
+			before:     dbImpactType,
+			after:      updatedImpactType,
+		})
 		if transactionError != nil {
-			logger.Error().Err(transactionError).Msg("error updating impact type")
+			logger.Error().Err(transactionError).Msg("error recording update")
 
 			return echo.ErrInternalServerError
 		}
@@ -202,7 +264,6 @@ func (i *Implementation) UpdateImpactType( //nolint:funlen
 		return nil
 	})
 	if err != nil {
-		// Don't wrap the echo errors.
 		return err //nolint:wrapcheck
 	}
 
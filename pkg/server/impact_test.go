@@ -12,6 +12,7 @@ import (
 	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
 	"github.com/SovereignCloudStack/status-page-api/pkg/db"
 	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -21,7 +22,7 @@ import (
 	"gorm.io/gorm"
 )
 
-var _ = Describe("Impact", Ordered, func() {
+var _ = DescribeTableSubtree("Impact", Ordered, func(dialect test.Dialect) {
 	const (
 		impactTypeID        = "c3fc130d-e6c4-4f94-86ba-e51fbdfc5d0c"
 		impactTypesEndpoint = "/impactTypes"
@@ -48,9 +49,9 @@ var _ = Describe("Impact", Ordered, func() {
 		expectedImpactTypesQuery         = regexp.QuoteMeta(`SELECT * FROM "impact_types"`)
 		expectedImpactTypeQuery          = regexp.QuoteMeta(`SELECT * FROM "impact_types" WHERE id = $1 ORDER BY "impact_types"."id" LIMIT $2`)                  //nolint:lll
 		expectedImpactTypeQueryWithTable = regexp.QuoteMeta(`SELECT * FROM "impact_types" WHERE "impact_types"."id" = $1 ORDER BY "impact_types"."id" LIMIT $2`) //nolint:lll
-		expectedImpactTypeInsert         = regexp.QuoteMeta(`INSERT INTO "impact_types" ("display_name","description","id") VALUES ($1,$2,$3)`)                  //nolint:lll
-		expectedImpactTypeDelete         = regexp.QuoteMeta(`DELETE FROM "impact_types" WHERE id = $1`)
-		expectedImpactTypeUpdate         = regexp.QuoteMeta(`UPDATE "impact_types" SET "display_name"=$1 WHERE "id" = $2`)
+		expectedImpactTypeInsert         = regexp.QuoteMeta(`INSERT INTO "impact_types" ("display_name","description","version","id") VALUES ($1,$2,$3,$4)`)     //nolint:lll
+		expectedImpactTypeDelete         = regexp.QuoteMeta(`DELETE FROM "impact_types" WHERE id = $1 AND version = $2`)
+		expectedImpactTypeUpdate         = regexp.QuoteMeta(`UPDATE "impact_types" SET "display_name"=$1,"version"=$2 WHERE version = $3 AND "id" = $4`) //nolint:lll
 
 		// UUID of the test impact type
 		impactTypeUUID = uuid.MustParse(impactTypeID)
@@ -74,8 +75,8 @@ var _ = Describe("Impact", Ordered, func() {
 		// setup database and mock before each test
 		var gormDB *gorm.DB
 
-		sqlDB, sqlMock, gormDB = test.MustMockGorm(gormLogger)
-		handlers = server.New(gormDB, handlerLogger)
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
 
 		// create mock rows before each test
 		impactTypeRows = sqlmock.
@@ -186,6 +187,7 @@ var _ = Describe("Impact", Ordered, func() {
 				// Arrange
 				sqlMock.ExpectBegin()
 				sqlMock.ExpectExec(expectedImpactTypeInsert).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationCreate, db.AuditTargetImpactType, sqlmock.AnyArg())
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -213,7 +215,7 @@ var _ = Describe("Impact", Ordered, func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
 			})
 		})
 
@@ -254,7 +256,12 @@ var _ = Describe("Impact", Ordered, func() {
 			It("should return 204 no content", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.ExpectExec(expectedImpactTypeDelete).WithArgs(impactTypeID).WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.
+					ExpectQuery(expectedImpactTypeQuery).
+					WithArgs(impactTypeID, 1).
+					WillReturnRows(impactTypeRows.AddRow(impactType.ID, impactType.DisplayName, impactType.Description))
+				sqlMock.ExpectExec(expectedImpactTypeDelete).WithArgs(impactTypeID, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationDelete, db.AuditTargetImpactType, impactTypeID)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -267,12 +274,12 @@ var _ = Describe("Impact", Ordered, func() {
 			})
 		})
 
-		Context("without affected row", func() {
+		Context("without existing impact type", func() {
 			It("should return 404 not found", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.ExpectExec(expectedImpactTypeDelete).WithArgs(impactTypeID).WillReturnResult(sqlmock.NewResult(0, 0))
-				sqlMock.ExpectCommit()
+				sqlMock.ExpectQuery(expectedImpactTypeQuery).WithArgs(impactTypeID, 1).WillReturnRows(impactTypeRows)
+				sqlMock.ExpectRollback()
 
 				// Act
 				err := handlers.DeleteImpactType(ctx, impactTypeUUID)
@@ -287,7 +294,11 @@ var _ = Describe("Impact", Ordered, func() {
 			It("should return 500 internal server error", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.ExpectExec(expectedImpactTypeDelete).WithArgs(impactTypeID).WillReturnError(test.ErrTestError)
+				sqlMock.
+					ExpectQuery(expectedImpactTypeQuery).
+					WithArgs(impactTypeID, 1).
+					WillReturnRows(impactTypeRows.AddRow(impactType.ID, impactType.DisplayName, impactType.Description))
+				sqlMock.ExpectExec(expectedImpactTypeDelete).WithArgs(impactTypeID, 1).WillReturnError(test.ErrTestError)
 				sqlMock.ExpectRollback()
 
 				// Act
@@ -403,8 +414,16 @@ var _ = Describe("Impact", Ordered, func() {
 					)
 				sqlMock.
 					ExpectExec(expectedImpactTypeUpdate).
-					WithArgs("Connectivity problems", impactTypeID).
+					WithArgs("Connectivity problems", 2, 1, impactTypeID).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.
+					ExpectQuery(expectedImpactTypeQueryWithTable).
+					WithArgs(impactTypeID, 1).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "display_name", "description"}).
+						AddRow(impactType.ID, "Connectivity problems", impactType.Description),
+					)
+				expectAuditEntry(sqlMock, db.AuditOperationUpdate, db.AuditTargetImpactType, impactTypeID)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -432,7 +451,7 @@ var _ = Describe("Impact", Ordered, func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
 			})
 		})
 
@@ -474,4 +493,4 @@ var _ = Describe("Impact", Ordered, func() {
 			})
 		})
 	})
-})
+}, test.DialectEntries())
@@ -4,20 +4,20 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"slices"
 
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
 	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
-// GetIncidents retrieves a list of all active incidents between a start and end.
+// GetIncidents retrieves a page of the incidents active between a start and end, latest first.
+// With a label selector, only incidents affecting any matching component are retrieved.
+// With a time, the incidents are evaluated by their revisions valid at the time.
+// Admins may request deleted incidents, which are marked as such.
 func (i *Implementation) GetIncidents(ctx echo.Context, params apiServerDefinition.GetIncidentsParams) error {
-	var incidents []*DbDef.Incident
-
 	logger := i.logger.With().Str("handler", "GetIncidents").Logger()
 	logger.Debug().Time("start", params.Start).Time("end", params.End).Send()
 
@@ -30,41 +30,61 @@ func (i *Implementation) GetIncidents(ctx echo.Context, params apiServerDefiniti
 	if params.End.Before(params.Start) {
 		logger.Warn().Msg("end paramater before start parameter")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(DbDef.NewFieldError("end", DbDef.ErrEndsBeforeStart))
+	}
+
+	selector, err := selectorFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid label selector")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	page, err := pageFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid page parameters")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	at, err := atFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid at parameter")
+
+		return echo.ErrBadRequest.WithInternal(err)
 	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	repo, includeDeleted, err := i.readRepository(ctx, &logger)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
 
-	res := dbSession.
-		Preload("Affects.Component").
-		Preload(clause.Associations).
-		Where(dbSession.
-			Not(dbSession.
-				Where("began_at < ?", params.Start).
-				Where("ended_at < ?", params.Start))).
-		Where(dbSession.
-			Not(dbSession.
-				Where("began_at > ?", params.End).
-				Where("ended_at > ?", params.End))).
-		Or(dbSession.
-			Where("ended_at IS NULL").
-			Where("began_at <= ?", params.End)).
-		Find(&incidents)
+	incidents, next, err := repo.ListIncidents(params.Start, params.End, at, selector, page)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		logger.Warn().Err(err).Msg("invalid cursor")
 
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error loading incidents")
+		return echo.ErrBadRequest.WithInternal(err)
+	} else if err != nil {
+		logger.Error().Err(err).Msg("error loading incidents")
 
 		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
 
+	if includeDeleted {
+		data := make([]api.DeletableIncidentResponseData, len(incidents))
+		for incidentIndex, incident := range incidents {
+			data[incidentIndex] = incident.ToDeletableAPIResponse()
+		}
+
+		return respondPage(ctx, data, next)
+	}
+
 	data := make([]apiServerDefinition.IncidentResponseData, len(incidents))
 	for incidentIndex, incident := range incidents {
 		data[incidentIndex] = incident.ToAPIResponse()
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.IncidentListResponse{ //nolint:wrapcheck
-		Data: data,
-	})
+	return respondPage(ctx, data, next)
 }
 
 // CreateIncident handles creation of incidents.
@@ -83,46 +103,55 @@ func (i *Implementation) CreateIncident(ctx echo.Context) error { //nolint: funl
 	if request == (apiServerDefinition.CreateIncidentJSONRequestBody{}) { //nolint: exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
 	logger.Debug().Interface("request", request).Send()
 
 	incident, err := DbDef.IncidentFromAPI(&request)
 	if err != nil {
-		logger.Error().Err(err).Msg("error parsing request")
+		logger.Warn().Err(err).Msg("error parsing request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(err)
 	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		invalidErrs, transactionErr := i.invalidReferences(repo, incident)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error checking references of incident")
 
-	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
-		var (
-			transactionErr error
-			dbPhase        DbDef.Phase
-		)
+			return echo.ErrInternalServerError
+		}
 
-		// Check phase validity
-		if incident.Phase != nil {
-			dbPhase.Generation = incident.Phase.Generation
-			dbPhase.Order = incident.Phase.Order
+		if len(invalidErrs) > 0 {
+			logger.Warn().Err(errors.Join(invalidErrs...)).Msg("invalid references of incident")
 
-			transactionErr = dbTx.First(&dbPhase).Error
-			if errors.Is(transactionErr, gorm.ErrRecordNotFound) {
-				logger.Warn().Msg("invalid phase for incident")
+			return echo.ErrBadRequest.WithInternal(errors.Join(invalidErrs...))
+		}
 
-				return echo.ErrBadRequest
-			} else if transactionErr != nil {
-				logger.Error().Err(transactionErr).Msg("error loading phase from database")
+		transactionErr = repo.CreateIncident(incident)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error creating incident")
 
-				return echo.ErrInternalServerError
-			}
+			return echo.ErrInternalServerError
 		}
 
-		transactionErr = dbTx.Create(incident).Error
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetIncident,
+			targetID:   incident.ID.String(),
+			before:     nil,
+			after:      incident,
+		})
 		if transactionErr != nil {
-			logger.Error().Err(transactionErr).Msg("error creating incident")
+			logger.Error().Err(transactionErr).Msg("error recording creation")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = publishEvent(repo, DbDef.EventIncidentCreated, incident)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error publishing creation")
 
 			return echo.ErrInternalServerError
 		}
@@ -130,7 +159,6 @@ func (i *Implementation) CreateIncident(ctx echo.Context) error { //nolint: funl
 		return nil
 	})
 	if err != nil {
-		// Don't wrap the echo errors.
 		return err //nolint:wrapcheck
 	}
 
@@ -147,102 +175,109 @@ func (i *Implementation) DeleteIncident(
 	logger := i.logger.With().Str("handler", "DeleteIncident").Interface("id", incidentID).Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionErr := checkIfMatch(ctx, &logger, incidentID.String(), incidentVersion(repo, incidentID))
+		if transactionErr != nil {
+			return transactionErr
+		}
 
-	res := dbSession.Where("id = ?", incidentID).Delete(&DbDef.Incident{}) //nolint: exhaustruct
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error deleting incident")
+		dbIncident, transactionErr := repo.DeleteIncident(incidentID, actor(ctx))
+		if transactionErr == nil && !ifMatch.holds(dbIncident.Version) {
+			transactionErr = storage.ErrConflict
+		}
 
-		return echo.ErrInternalServerError
-	}
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("incident not found")
+
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrConflict) {
+			logger.Warn().Msg("incident changed concurrently")
+
+			return ifMatch.conflict()
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error deleting incident")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationDelete,
+			targetType: DbDef.AuditTargetIncident,
+			targetID:   incidentID.String(),
+			before:     dbIncident,
+			after:      nil,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording deletion")
 
-	if res.RowsAffected == 0 {
-		logger.Warn().Msg("incident not found")
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = publishEvent(repo, DbDef.EventIncidentDeleted, dbIncident)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error publishing deletion")
 
-		return echo.ErrNotFound
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
 
 	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
 }
 
-// GetIncident retrieves a specific incident by ID.
+// GetIncident retrieves a specific incident by ID, admins may request it, if it is deleted.
 func (i *Implementation) GetIncident(ctx echo.Context, incidentID apiServerDefinition.IncidentIdPathParameter) error {
-	var incident DbDef.Incident
-
 	logger := i.logger.With().Str("handler", "GetIncident").Interface("id", incidentID).Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	repo, includeDeleted, err := i.readRepository(ctx, &logger)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
 
-	res := dbSession.
-		Preload("Affects.Component").
-		Preload(clause.Associations).
-		Where("id = ?", incidentID).
-		First(&incident)
-	if res.Error != nil {
-		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+	incident, err := repo.GetIncident(incidentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
 			logger.Warn().Msg("incident not found")
 
 			return echo.ErrNotFound
 		}
 
-		logger.Error().Err(res.Error).Msg("error loading incident")
+		logger.Error().Err(err).Msg("error loading incident")
 
 		return echo.NewHTTPError(http.StatusInternalServerError)
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.IncidentResponse{ //nolint:wrapcheck
+	if includeDeleted {
+		return sendTagged(ctx, incidentID.String(), incident.Version, api.DeletableIncidentResponse{
+			Data: incident.ToDeletableAPIResponse(),
+		})
+	}
+
+	return sendTagged(ctx, incidentID.String(), incident.Version, apiServerDefinition.IncidentResponse{
 		Data: incident.ToAPIResponse(),
 	})
 }
 
-func prepareAffects(oldAffects, newAffects *[]DbDef.Impact, incidentID uuid.UUID, dbTx *gorm.DB) error {
-	// Check if any impacts need deletion.
-	if oldAffects == nil || len(*oldAffects) == 0 {
-		return nil
-	}
-
-	// Check if impacts are modified at all.
-	if newAffects == nil {
-		return nil
-	}
-
-	// Check if any impacts are left.
-	if len(*newAffects) == 0 {
-		// Delete all impacts of this incident.
-		err := dbTx.Delete(oldAffects).Where("incident_id = ?", incidentID).Error
+// incidentVersion loads the version of an incident, for conditional changes.
+func incidentVersion(repo storage.Repository, incidentID DbDef.ID) func() (*int, error) {
+	return func() (*int, error) {
+		incident, err := repo.GetIncident(incidentID)
 		if err != nil {
-			return fmt.Errorf("error deleting all incident impacts: %w", err)
+			return nil, err //nolint:wrapcheck
 		}
 
-		return nil
-	}
-
-	// Collect all impacts that are expected and give them the incident id.
-	newImpacts := make([]DbDef.Impact, len(*newAffects))
-
-	for incidentImpactIndex := range *newAffects {
-		newImpacts[incidentImpactIndex].IncidentID = &incidentID
-	}
-
-	var impactsToBeDeleted []DbDef.Impact
-
-	for _, oldImpact := range *oldAffects {
-		if !slices.ContainsFunc(newImpacts, func(newImpact DbDef.Impact) bool {
-			return newImpact.ComponentID == oldImpact.ComponentID &&
-				newImpact.ImpactTypeID == oldImpact.ImpactTypeID &&
-				newImpact.IncidentID == oldImpact.IncidentID
-		}) {
-			impactsToBeDeleted = append(impactsToBeDeleted, oldImpact)
-		}
-	}
-
-	err := dbTx.Delete(&impactsToBeDeleted).Error
-	if err != nil {
-		return fmt.Errorf("error deleting not needed impacts: %w", err)
+		return incident.Version, nil
 	}
+}
 
-	return nil
+// incidentUpdateTargetID identifies an incident update in the audit log.
+func incidentUpdateTargetID(incidentID uuid.UUID, order int) string {
+	return fmt.Sprintf("%s/%d", incidentID, order)
 }
 
 // UpdateIncident handles updates of incidents.
@@ -268,7 +303,7 @@ func (i *Implementation) UpdateIncident( //nolint: funlen
 	if request == (apiServerDefinition.UpdateIncidentJSONRequestBody{}) { //nolint:exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
 	logger.Debug().Interface("request", request).Send()
@@ -276,51 +311,78 @@ func (i *Implementation) UpdateIncident( //nolint: funlen
 	// Prepare new incident.
 	incident, err := DbDef.IncidentFromAPI(&request)
 	if err != nil {
-		logger.Error().Err(err).Msg("error parsing request")
+		logger.Warn().Err(err).Msg("error parsing request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(err)
 	}
 
 	incident.ID = incidentID
 
-	// DB connection.
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionErr := checkIfMatch(ctx, &logger, incidentID.String(), incidentVersion(repo, incidentID))
+		if transactionErr != nil {
+			return transactionErr
+		}
 
-	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
-		// Check if incident exists.
-		var (
-			dbIncident     DbDef.Incident
-			transactionErr error
-		)
+		invalidErrs, transactionErr := i.invalidReferences(repo, incident)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error checking references of incident")
+
+			return echo.ErrInternalServerError
+		}
+
+		if len(invalidErrs) > 0 {
+			logger.Warn().Err(errors.Join(invalidErrs...)).Msg("invalid references of incident")
 
-		dbIncident.ID = incident.ID
+			return echo.ErrBadRequest.WithInternal(errors.Join(invalidErrs...))
+		}
+
+		dbIncident, updatedIncident, transactionErr := repo.UpdateIncident(incident)
+		if transactionErr == nil && !ifMatch.holds(dbIncident.Version) {
+			transactionErr = storage.ErrConflict
+		}
 
-		transactionErr = dbTx.Preload("Affects").First(&dbIncident).Error
 		if transactionErr != nil {
-			if errors.Is(transactionErr, gorm.ErrRecordNotFound) {
+			if errors.Is(transactionErr, storage.ErrNotFound) {
 				logger.Warn().Msg("incident not found")
 
 				return echo.ErrNotFound
 			}
 
-			logger.Error().Err(transactionErr).Msg("error loading current incident from database")
+			if errors.Is(transactionErr, storage.ErrConflict) {
+				logger.Warn().Msg("incident changed concurrently")
+
+				return ifMatch.conflict()
+			}
+
+			logger.Error().Err(transactionErr).Msg("error updating incident")
 
 			return echo.ErrInternalServerError
 		}
 
-		// Prepare for update.
-		logger.Trace().Interface("dbIncident", dbIncident).Interface("incident", incident).Send()
+		logger.Trace().Interface("dbIncident", dbIncident).Interface("updatedIncident", updatedIncident).Send()
 
-		transactionErr = prepareAffects(dbIncident.Affects, incident.Affects, incident.ID, dbTx)
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationUpdate,
+			targetType: DbDef.AuditTargetIncident,
+			targetID:   incident.ID.String(),
+			before:     dbIncident,
+			after:      updatedIncident,
+		})
 		if transactionErr != nil {
-			logger.Error().Err(transactionErr).Msg("error updating affected components")
+			logger.Error().Err(transactionErr).Msg("error recording update")
 
 			return echo.ErrInternalServerError
 		}
 
-		transactionErr = dbTx.Updates(&incident).Error
+		eventType := DbDef.EventIncidentUpdated
+		if dbIncident.EndedAt == nil && updatedIncident.EndedAt != nil {
+			eventType = DbDef.EventIncidentResolved
+		}
+
+		transactionErr = publishEvent(repo, eventType, updatedIncident)
 		if transactionErr != nil {
-			logger.Error().Err(transactionErr).Msg("error updating incident")
+			logger.Error().Err(transactionErr).Msg("error publishing update")
 
 			return echo.ErrInternalServerError
 		}
@@ -328,28 +390,34 @@ func (i *Implementation) UpdateIncident( //nolint: funlen
 		return nil
 	})
 	if err != nil {
-		// Don't wrap the echo errors.
 		return err //nolint:wrapcheck
 	}
 
 	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
 }
 
-// GetIncidentUpdates retrieves a list of all updates for one incident.
+// GetIncidentUpdates retrieves a page of the updates for one incident, sorted by order.
 func (i *Implementation) GetIncidentUpdates(
 	ctx echo.Context,
 	incidentID apiServerDefinition.IncidentIdPathParameter,
 ) error {
-	var incidentUpdates []DbDef.IncidentUpdate
-
 	logger := i.logger.With().Str("handler", "GetIncidentUpdates").Interface("id", incidentID).Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	page, err := pageFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid page parameters")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	incidentUpdates, next, err := i.storage.WithContext(ctx.Request().Context()).ListIncidentUpdates(incidentID, page)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		logger.Warn().Err(err).Msg("invalid cursor")
 
-	res := dbSession.Where("incident_id = ?", incidentID).Find(&incidentUpdates)
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error loading incident updates")
+		return echo.ErrBadRequest.WithInternal(err)
+	} else if err != nil {
+		logger.Error().Err(err).Msg("error loading incident updates")
 
 		return echo.ErrInternalServerError
 	}
@@ -359,9 +427,7 @@ func (i *Implementation) GetIncidentUpdates(
 		data[incidentUpdateIndex] = incidentUpdate.ToAPIResponse()
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.IncidentUpdateListResponse{ //nolint:wrapcheck
-		Data: data,
-	})
+	return respondPage(ctx, data, next)
 }
 
 // CreateIncidentUpdate handles updates to an update for one incident.
@@ -386,20 +452,20 @@ func (i *Implementation) CreateIncidentUpdate(
 	if request == (apiServerDefinition.CreateIncidentUpdateJSONRequestBody{}) { //nolint: exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
-
-	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
 		var (
 			incidentUpdate *DbDef.IncidentUpdate
 			transactionErr error
 		)
 
-		order, transactionErr = DbDef.GetHighestIncidentUpdateOrder(dbTx, incidentID)
+		order, transactionErr = repo.HighestIncidentUpdateOrder(incidentID)
 		if transactionErr != nil {
-			return fmt.Errorf("error getting current highest order of incident: %w", transactionErr)
+			logger.Error().Err(transactionErr).Msg("error getting current highest order of incident")
+
+			return echo.ErrInternalServerError
 		}
 
 		order++
@@ -408,20 +474,50 @@ func (i *Implementation) CreateIncidentUpdate(
 
 		incidentUpdate, transactionErr = DbDef.IncidentUpdateFromAPI(&request, incidentID, order)
 		if transactionErr != nil {
-			return fmt.Errorf("error parsing request: %w", transactionErr)
+			logger.Warn().Err(transactionErr).Msg("error parsing request")
+
+			return echo.ErrBadRequest.WithInternal(transactionErr)
+		}
+
+		transactionErr = repo.CreateIncidentUpdate(incidentUpdate)
+		if errors.Is(transactionErr, storage.ErrReferenced) {
+			logger.Warn().Msg("incident not found")
+
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrDuplicate) {
+			logger.Warn().Msg("incident update created concurrently")
+
+			return echo.NewHTTPError(http.StatusConflict)
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error creating incident update")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetIncidentUpdate,
+			targetID:   incidentUpdateTargetID(incidentID, order),
+			before:     nil,
+			after:      incidentUpdate,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording creation")
+
+			return echo.ErrInternalServerError
 		}
 
-		res := dbTx.Create(&incidentUpdate)
-		if res.Error != nil {
-			return fmt.Errorf("error creating incident update: %w", res.Error)
+		transactionErr = publishEvent(repo, DbDef.EventIncidentUpdateCreated, incidentUpdate)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error publishing creation")
+
+			return echo.ErrInternalServerError
 		}
 
 		return nil
 	})
 	if err != nil {
-		logger.Error().Err(err).Msg("error in transaction")
-
-		return echo.ErrInternalServerError
+		return err //nolint:wrapcheck
 	}
 
 	return ctx.JSON(http.StatusCreated, apiServerDefinition.OrderResponse{ //nolint:wrapcheck
@@ -430,7 +526,7 @@ func (i *Implementation) CreateIncidentUpdate(
 }
 
 // DeleteIncidentUpdate handles deletion of an update for one incident.
-func (i *Implementation) DeleteIncidentUpdate(
+func (i *Implementation) DeleteIncidentUpdate( //nolint:funlen
 	ctx echo.Context,
 	incidentID apiServerDefinition.IncidentIdPathParameter,
 	incidentUpdateOrder apiServerDefinition.IncidentUpdateOrderPathParameter,
@@ -442,22 +538,58 @@ func (i *Implementation) DeleteIncidentUpdate(
 		Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionErr := checkIfMatch(
+			ctx, &logger, incidentUpdateTargetID(incidentID, incidentUpdateOrder),
+			incidentUpdateVersion(repo, incidentID, incidentUpdateOrder),
+		)
+		if transactionErr != nil {
+			return transactionErr
+		}
 
-	res := dbSession.
-		Where("incident_id = ?", incidentID).
-		Where("\"order\" = ?", incidentUpdateOrder).
-		Delete(&DbDef.IncidentUpdate{}) //nolint: exhaustruct
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error deleting incident update")
+		dbIncidentUpdate, transactionErr := repo.DeleteIncidentUpdate(incidentID, incidentUpdateOrder)
+		if transactionErr == nil && !ifMatch.holds(dbIncidentUpdate.Version) {
+			transactionErr = storage.ErrConflict
+		}
 
-		return echo.ErrInternalServerError
-	}
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("incident update not found")
 
-	if res.RowsAffected == 0 {
-		logger.Warn().Msg("incident update not found")
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrConflict) {
+			logger.Warn().Msg("incident update changed concurrently")
+
+			return ifMatch.conflict()
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error deleting incident update")
+
+			return echo.ErrInternalServerError
+		}
 
-		return echo.ErrNotFound
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationDelete,
+			targetType: DbDef.AuditTargetIncidentUpdate,
+			targetID:   incidentUpdateTargetID(incidentID, incidentUpdateOrder),
+			before:     dbIncidentUpdate,
+			after:      nil,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording deletion")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = publishEvent(repo, DbDef.EventIncidentUpdateDeleted, dbIncidentUpdate)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error publishing deletion")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
 
 	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
@@ -469,8 +601,6 @@ func (i *Implementation) GetIncidentUpdate(
 	incidentID apiServerDefinition.IncidentIdPathParameter,
 	incidentUpdateOrder apiServerDefinition.IncidentUpdateOrderPathParameter,
 ) error {
-	var incidentUpdate DbDef.IncidentUpdate
-
 	logger := i.logger.With().
 		Str("handler", "GetIncidentUpdate").
 		Interface("id", incidentID).
@@ -478,31 +608,41 @@ func (i *Implementation) GetIncidentUpdate(
 		Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
-
-	res := dbSession.
-		Where("incident_id = ?", incidentID).
-		Where("\"order\" = ?", incidentUpdateOrder).
-		First(&incidentUpdate)
-	if res.Error != nil {
-		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+	incidentUpdate, err := i.storage.
+		WithContext(ctx.Request().Context()).
+		GetIncidentUpdate(incidentID, incidentUpdateOrder)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
 			logger.Warn().Msg("incident update not found")
 
 			return echo.ErrNotFound
 		}
 
-		logger.Error().Err(res.Error).Msg("error loading incident update")
+		logger.Error().Err(err).Msg("error loading incident update")
 
 		return echo.ErrInternalServerError
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.IncidentUpdateResponse{ //nolint:wrapcheck
-		Data: incidentUpdate.ToAPIResponse(),
-	})
+	return sendTagged(ctx, incidentUpdateTargetID(incidentID, incidentUpdateOrder), incidentUpdate.Version,
+		apiServerDefinition.IncidentUpdateResponse{
+			Data: incidentUpdate.ToAPIResponse(),
+		})
+}
+
+// incidentUpdateVersion loads the version of an incident update, for conditional changes.
+func incidentUpdateVersion(repo storage.Repository, incidentID DbDef.ID, order int) func() (*int, error) {
+	return func() (*int, error) {
+		incidentUpdate, err := repo.GetIncidentUpdate(incidentID, order)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return incidentUpdate.Version, nil
+	}
 }
 
 // UpdateIncidentUpdate handles updates of updates for one incident.
-func (i *Implementation) UpdateIncidentUpdate(
+func (i *Implementation) UpdateIncidentUpdate( //nolint:funlen
 	ctx echo.Context,
 	incidentID apiServerDefinition.IncidentIdPathParameter,
 	incidentUpdateOrder apiServerDefinition.IncidentUpdateOrderPathParameter,
@@ -525,7 +665,7 @@ func (i *Implementation) UpdateIncidentUpdate(
 	if request == (apiServerDefinition.CreateIncidentUpdateJSONRequestBody{}) { //nolint: exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
 	logger.Debug().
@@ -534,24 +674,63 @@ func (i *Implementation) UpdateIncidentUpdate(
 
 	incidentUpdate, err := DbDef.IncidentUpdateFromAPI(&request, incidentID, incidentUpdateOrder)
 	if err != nil {
-		logger.Error().Err(err).Msg("error parsing request")
+		logger.Warn().Err(err).Msg("error parsing request")
 
-		return echo.ErrInternalServerError
+		return echo.ErrBadRequest.WithInternal(err)
 	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionErr := checkIfMatch(
+			ctx, &logger, incidentUpdateTargetID(incidentID, incidentUpdateOrder),
+			incidentUpdateVersion(repo, incidentID, incidentUpdateOrder),
+		)
+		if transactionErr != nil {
+			return transactionErr
+		}
 
-	res := dbSession.Updates(&incidentUpdate)
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error updating incident update")
+		dbIncidentUpdate, updatedIncidentUpdate, transactionErr := repo.UpdateIncidentUpdate(incidentUpdate)
+		if transactionErr == nil && !ifMatch.holds(dbIncidentUpdate.Version) {
+			transactionErr = storage.ErrConflict
+		}
 
-		return echo.ErrInternalServerError
-	}
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("incident update not found")
 
-	if res.RowsAffected == 0 {
-		logger.Warn().Msg("incident update not found")
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrConflict) {
+			logger.Warn().Msg("incident update changed concurrently")
+
+			return ifMatch.conflict()
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error updating incident update")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationUpdate,
+			targetType: DbDef.AuditTargetIncidentUpdate,
+			targetID:   incidentUpdateTargetID(incidentID, incidentUpdateOrder),
+			before:     dbIncidentUpdate,
+			after:      updatedIncidentUpdate,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording update")
 
-		return echo.ErrNotFound
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = publishEvent(repo, DbDef.EventIncidentUpdateUpdated, updatedIncidentUpdate)
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error publishing update")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
 
 	return ctx.NoContent(http.StatusNoContent) //nolint: wrapcheck
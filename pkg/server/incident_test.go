@@ -2,6 +2,7 @@ package server_test
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -14,6 +15,7 @@ import (
 	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
 	"github.com/SovereignCloudStack/status-page-api/pkg/db"
 	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -47,7 +49,31 @@ var (
 	incidentUUID = uuid.MustParse(incidentID)
 )
 
-var _ = Describe("Incident", func() {
+// expected SQL recording a revision of an incident.
+//
+//nolint:gochecknoglobals
+var (
+	expectedRevisionQuery = regexp.
+				QuoteMeta(`SELECT COALESCE(MAX(revision), 0) FROM "incident_revisions" WHERE incident_id = $1`)
+	expectedRevisionInsert = regexp.
+				QuoteMeta(`INSERT INTO "incident_revisions" ("incident_id","revision","display_name","description","began_at","ended_at","phase_generation","phase_order","recorded_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`) //nolint:lll
+)
+
+// expectRevision expects the revision following the latest one to be recorded for an incident.
+// The impacts of the revision are inserted afterwards.
+func expectRevision(sqlMock sqlmock.Sqlmock, incidentID driver.Value, latest int) {
+	sqlMock.
+		ExpectQuery(expectedRevisionQuery).
+		WithArgs(incidentID).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(latest))
+	sqlMock.
+		ExpectExec(expectedRevisionInsert).
+		WithArgs(incidentID, latest+1, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+var _ = DescribeTableSubtree("Incident", func(dialect test.Dialect) {
 	var (
 		// mocked sql rows
 		incidentRows       *sqlmock.Rows
@@ -58,15 +84,25 @@ var _ = Describe("Incident", func() {
 
 		// expected SQL
 		expectedIncidentsQuery = regexp.
-					QuoteMeta(`SELECT * FROM "incidents" WHERE NOT (began_at < $1 AND ended_at < $2) AND NOT (began_at > $3 AND ended_at > $4) OR (ended_at IS NULL AND began_at <= $5)`) //nolint:lll
+					QuoteMeta(`SELECT * FROM "incidents" WHERE (NOT (began_at < $1 AND ended_at < $2) AND NOT (began_at > $3 AND ended_at > $4) OR (ended_at IS NULL AND began_at <= $5)) AND "incidents"."deleted_at" IS NULL ORDER BY "began_at" DESC, id DESC LIMIT $6`) //nolint:lll
+		expectedIncidentsQueryWithSelector = map[test.Dialect]string{
+			test.Postgres: regexp.QuoteMeta(`SELECT * FROM "incidents" WHERE id IN (SELECT impacts.incident_id FROM impacts JOIN components ON components.id = impacts.component_id WHERE components.labels @> $1) AND (NOT (began_at < $2 AND ended_at < $3) AND NOT (began_at > $4 AND ended_at > $5) OR (ended_at IS NULL AND began_at <= $6)) AND "incidents"."deleted_at" IS NULL ORDER BY "began_at" DESC, id DESC LIMIT $7`),                     //nolint:lll
+			test.SQLite:   regexp.QuoteMeta(`SELECT * FROM "incidents" WHERE id IN (SELECT impacts.incident_id FROM impacts JOIN components ON components.id = impacts.component_id WHERE json_extract(components.labels, $1) IN ($2)) AND (NOT (began_at < $3 AND ended_at < $4) AND NOT (began_at > $5 AND ended_at > $6) OR (ended_at IS NULL AND began_at <= $7)) AND "incidents"."deleted_at" IS NULL ORDER BY "began_at" DESC, id DESC LIMIT $8`), //nolint:lll
+		}
+		expectedSelectorArgs = map[test.Dialect][]driver.Value{
+			test.Postgres: {`{"region":"datacenter-west"}`},
+			test.SQLite:   {`$."region"`, "datacenter-west"},
+		}
 		expectedIncidentQuery = regexp.
-					QuoteMeta(`SELECT * FROM "incidents" WHERE id = $1 ORDER BY "incidents"."id" LIMIT $2`)
+					QuoteMeta(`SELECT * FROM "incidents" WHERE id = $1 AND "incidents"."deleted_at" IS NULL ORDER BY "incidents"."id" LIMIT $2`)
 		expectedIncidentInsert = regexp.
-					QuoteMeta(`INSERT INTO "incidents" ("display_name","description","began_at","ended_at","phase_generation","phase_order","id") VALUES ($1,$2,$3,$4,$5,$6,$7)`) //nolint:lll
+					QuoteMeta(`INSERT INTO "incidents" ("display_name","description","began_at","ended_at","phase_generation","phase_order","version","deleted_at","deleted_by","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`) //nolint:lll
 		expectedIncidentDelete = regexp.
-					QuoteMeta(`DELETE FROM "incidents" WHERE id = $1`)
+					QuoteMeta(`UPDATE "incidents" SET "deleted_at"=$1,"deleted_by"=$2,"version"=$3 WHERE id = $4 AND version = $5`)
+		expectedAlertIncidentResolve = regexp.
+						QuoteMeta(`UPDATE "alert_incidents" SET "resolved_at"=$1 WHERE incident_id = $2 AND resolved_at IS NULL`)
 		expectedIncidentUpdate = regexp.
-					QuoteMeta(`UPDATE "incidents" SET "display_name"=$1 WHERE "id" = $2`)
+					QuoteMeta(`UPDATE "incidents" SET "display_name"=$1,"version"=$2 WHERE version = $3 AND "incidents"."deleted_at" IS NULL AND "id" = $4`)
 		expectedImpactQuery = regexp.
 					QuoteMeta(`SELECT * FROM "impacts" WHERE "impacts"."incident_id" = $1`)
 		expectedPhaseQuery = regexp.
@@ -98,8 +134,8 @@ var _ = Describe("Incident", func() {
 
 		gormLogger = test.Ptr(gormLogger.Level(zerolog.TraceLevel))
 
-		sqlDB, sqlMock, gormDB = test.MustMockGorm(gormLogger)
-		handlers = server.New(gormDB, handlerLogger)
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
 
 		// create mock rows before each test
 		incidentRows = sqlmock.
@@ -151,7 +187,7 @@ var _ = Describe("Incident", func() {
 				// Arrange
 				sqlMock.
 					ExpectQuery(expectedIncidentsQuery).
-					WithArgs(startTime, startTime, endTime, endTime, endTime).
+					WithArgs(startTime, startTime, endTime, endTime, endTime, 101).
 					WillReturnRows(incidentRows)
 
 				expectedResult, _ := json.Marshal(apiServerDefinition.IncidentListResponse{
@@ -173,7 +209,7 @@ var _ = Describe("Incident", func() {
 				// Arrange
 				sqlMock.
 					ExpectQuery(expectedIncidentsQuery).
-					WithArgs(startTime, startTime, endTime, endTime, endTime).
+					WithArgs(startTime, startTime, endTime, endTime, endTime, 101).
 					WillReturnRows(
 						incidentRows.AddRow(
 							incident.ID,              // id
@@ -281,11 +317,55 @@ var _ = Describe("Incident", func() {
 
 					// Assert
 					Ω(err).Should(HaveOccurred())
-					Ω(err).Should(Equal(echo.ErrBadRequest))
+					Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+					Ω(err).Should(MatchError(db.ErrEndsBeforeStart))
 				})
 			})
 		})
 
+		Context("with label selector", func() {
+			It("should return the incidents affecting matching components", func() {
+				// Arrange
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodGet,
+					incidentsEndpoint+"?labelSelector=region%3Ddatacenter-west",
+					nil,
+				)
+				sqlMock.
+					ExpectQuery(expectedIncidentsQueryWithSelector[dialect]).
+					WithArgs(append(
+						expectedSelectorArgs[dialect],
+						startTime, startTime, endTime, endTime, endTime, 101,
+					)...).
+					WillReturnRows(incidentRows)
+
+				// Act
+				err := handlers.GetIncidents(ctx, getIncidentParams)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+			})
+
+			It("should return 400 bad request for invalid selectors", func() {
+				// Arrange
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodGet,
+					incidentsEndpoint+"?labelSelector=az+notin+1",
+					nil,
+				)
+
+				// Act
+				err := handlers.GetIncidents(ctx, getIncidentParams)
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(err).Should(MatchError(db.ErrInvalidLabelSelector))
+			})
+		})
+
 		Context("with database error", func() {
 			It("should return 500 internal server error", func() {
 				// Arrange
@@ -326,6 +406,9 @@ var _ = Describe("Incident", func() {
 				sqlMock.
 					ExpectExec(expectedIncidentInsert).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectRevision(sqlMock, sqlmock.AnyArg(), 0)
+				expectAuditEntry(sqlMock, db.AuditOperationCreate, db.AuditTargetIncident, sqlmock.AnyArg())
+				expectEvent(dialect, sqlMock, db.EventIncidentCreated)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -353,7 +436,8 @@ var _ = Describe("Incident", func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(err).Should(MatchError(server.ErrEmptyRequest))
 			})
 		})
 
@@ -394,7 +478,24 @@ var _ = Describe("Incident", func() {
 			It("should return 204 no content", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.ExpectExec(expectedIncidentDelete).WithArgs(incidentID).WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectQuery(expectedIncidentQuery).
+					WithArgs(incidentID, 1).
+					WillReturnRows(
+						incidentRows.AddRow(
+							incident.ID,
+							incident.DisplayName,
+							incident.Description,
+							incident.BeganAt,
+							incident.EndedAt,
+							incident.PhaseGeneration,
+							incident.PhaseOrder,
+						),
+					)
+				sqlMock.ExpectQuery(expectedImpactQuery).WillReturnRows(impactRows)
+				sqlMock.ExpectExec(expectedIncidentDelete).WithArgs(sqlmock.AnyArg(), "anonymous", 2, incidentID, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectExec(expectedAlertIncidentResolve).WithArgs(sqlmock.AnyArg(), incidentID).WillReturnResult(sqlmock.NewResult(0, 0))
+				expectAuditEntry(sqlMock, db.AuditOperationDelete, db.AuditTargetIncident, incidentID)
+				expectEvent(dialect, sqlMock, db.EventIncidentDeleted)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -407,12 +508,12 @@ var _ = Describe("Incident", func() {
 			})
 		})
 
-		Context("without affected row", func() {
+		Context("without existing incident", func() {
 			It("should return 404 not found", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.ExpectExec(expectedIncidentDelete).WithArgs(incidentID).WillReturnResult(sqlmock.NewResult(0, 0))
-				sqlMock.ExpectCommit()
+				sqlMock.ExpectQuery(expectedIncidentQuery).WithArgs(incidentID, 1).WillReturnRows(incidentRows)
+				sqlMock.ExpectRollback()
 
 				// Act
 				err := handlers.DeleteIncident(ctx, incidentUUID)
@@ -427,7 +528,21 @@ var _ = Describe("Incident", func() {
 			It("should return 500 internal server error", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.ExpectExec(expectedIncidentDelete).WithArgs(incidentID).WillReturnError(test.ErrTestError)
+				sqlMock.ExpectQuery(expectedIncidentQuery).
+					WithArgs(incidentID, 1).
+					WillReturnRows(
+						incidentRows.AddRow(
+							incident.ID,
+							incident.DisplayName,
+							incident.Description,
+							incident.BeganAt,
+							incident.EndedAt,
+							incident.PhaseGeneration,
+							incident.PhaseOrder,
+						),
+					)
+				sqlMock.ExpectQuery(expectedImpactQuery).WillReturnRows(impactRows)
+				sqlMock.ExpectExec(expectedIncidentDelete).WithArgs(sqlmock.AnyArg(), "anonymous", 2, incidentID, 1).WillReturnError(test.ErrTestError)
 				sqlMock.ExpectRollback()
 
 				// Act
@@ -549,7 +664,7 @@ var _ = Describe("Incident", func() {
 			)
 
 			expectedIncidentQueryWithTable = regexp.
-				QuoteMeta(`SELECT * FROM "incidents" WHERE "incidents"."id" = $1 ORDER BY "incidents"."id" LIMIT $2`)
+				QuoteMeta(`SELECT * FROM "incidents" WHERE "incidents"."deleted_at" IS NULL AND "incidents"."id" = $1 ORDER BY "incidents"."id" LIMIT $2`)
 		})
 
 		Context("with valid UUID and valid request", func() {
@@ -573,8 +688,19 @@ var _ = Describe("Incident", func() {
 
 				sqlMock.
 					ExpectExec(expectedIncidentUpdate).
-					WithArgs("Network impact", incidentID).
+					WithArgs("Network impact", 2, 1, incidentID).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectQuery(expectedIncidentQueryWithTable).
+					WithArgs(incidentID, 1).
+					WillReturnRows(
+						sqlmock.
+							NewRows([]string{"id", "display_name"}).
+							AddRow(incident.ID, "Network impact"),
+					)
+				sqlMock.ExpectQuery(expectedImpactQuery).WillReturnRows(sqlmock.NewRows([]string{"incident_id"}))
+				expectRevision(sqlMock, incidentID, 1)
+				expectAuditEntry(sqlMock, db.AuditOperationUpdate, db.AuditTargetIncident, incidentID)
+				expectEvent(dialect, sqlMock, db.EventIncidentUpdated)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -587,6 +713,59 @@ var _ = Describe("Incident", func() {
 			})
 		})
 
+		Context("with ending request", func() {
+			It("should publish the resolution of the incident", func() {
+				// Arrange
+				ctx, res = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodPatch,
+					incidentEndpoint,
+					apiServerDefinition.Incident{
+						EndedAt: &now,
+					},
+				)
+
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedIncidentQueryWithTable).
+					WithArgs(incidentID, 1).
+					WillReturnRows(
+						incidentRows.AddRow(
+							incident.ID,
+							incident.DisplayName,
+							incident.Description,
+							incident.BeganAt,
+							nil,
+							incident.PhaseGeneration,
+							incident.PhaseOrder,
+						),
+					)
+				sqlMock.ExpectQuery(expectedImpactQuery).WillReturnRows(impactRows)
+				sqlMock.
+					ExpectExec(regexp.QuoteMeta(`UPDATE "incidents" SET "ended_at"=$1,"version"=$2 WHERE version = $3 AND "incidents"."deleted_at" IS NULL AND "id" = $4`)).
+					WithArgs(sqlmock.AnyArg(), 2, 1, incidentID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectQuery(expectedIncidentQueryWithTable).
+					WithArgs(incidentID, 1).
+					WillReturnRows(
+						sqlmock.
+							NewRows([]string{"id", "began_at", "ended_at"}).
+							AddRow(incident.ID, incident.BeganAt, now),
+					)
+				sqlMock.ExpectQuery(expectedImpactQuery).WillReturnRows(sqlmock.NewRows([]string{"incident_id"}))
+				expectRevision(sqlMock, incidentID, 1)
+				expectAuditEntry(sqlMock, db.AuditOperationUpdate, db.AuditTargetIncident, incidentID)
+				expectEvent(dialect, sqlMock, db.EventIncidentResolved)
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.UpdateIncident(ctx, incidentUUID)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNoContent))
+			})
+		})
+
 		Context("with empty request", func() {
 			It("should return 400 bad request", func() {
 				// Arrange
@@ -602,7 +781,8 @@ var _ = Describe("Incident", func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(err).Should(MatchError(server.ErrEmptyRequest))
 			})
 		})
 
@@ -644,9 +824,9 @@ var _ = Describe("Incident", func() {
 			})
 		})
 	})
-})
+}, test.DialectEntries())
 
-var _ = Describe("IncidentUpdate", func() {
+var _ = DescribeTableSubtree("IncidentUpdate", func(dialect test.Dialect) {
 	const (
 		incidentUpdateOrder     = 0
 		incidentUpdatesEndpoint = incidentEndpoint + "/updates"
@@ -661,13 +841,19 @@ var _ = Describe("IncidentUpdate", func() {
 		expectedIncidentUpdatesQuery = regexp.
 						QuoteMeta(`SELECT * FROM "incident_updates" WHERE incident_id = $1`)
 		expectedIncidentUpdateQuery = regexp.
-						QuoteMeta(`SELECT * FROM "incident_updates" WHERE incident_id = $1 AND "order" = $2 ORDER BY "incident_updates"."incident_id" LIMIT $3`) //nolint:lll
+						QuoteMeta(`SELECT * FROM "incident_updates" WHERE incident_id = $1 AND incident_id IN (SELECT "id" FROM "incidents" WHERE "incidents"."deleted_at" IS NULL) AND "order" = $2 ORDER BY "incident_updates"."incident_id" LIMIT $3`) //nolint:lll
 		expectedIncidentUpdateInsert = regexp.
-						QuoteMeta(`INSERT INTO "incident_updates" ("incident_id","order","display_name","description","created_at") VALUES ($1,$2,$3,$4,$5)`) //nolint:lll
+						QuoteMeta(`INSERT INTO "incident_updates" ("incident_id","order","display_name","description","created_at","version") VALUES ($1,$2,$3,$4,$5,$6)`) //nolint:lll
 		expectedIncidentUpdateDelete = regexp.
-						QuoteMeta(`DELETE FROM "incident_updates" WHERE incident_id = $1 AND "order" = $2`)
+						QuoteMeta(`DELETE FROM "incident_updates" WHERE incident_id = $1 AND "order" = $2 AND version = $3`)
 		expectedIncidentUpdateUpdate = regexp.
-						QuoteMeta(`UPDATE "incident_updates" SET "description"=$1 WHERE "incident_id" = $2 AND "order" = $3`)
+						QuoteMeta(`UPDATE "incident_updates" SET "description"=$1,"version"=$2 WHERE version = $3 AND "incident_id" = $4 AND "order" = $5`) //nolint:lll
+		expectedIncidentUpdateQueryWithTable = regexp.
+							QuoteMeta(`SELECT * FROM "incident_updates" WHERE incident_id IN (SELECT "id" FROM "incidents" WHERE "incidents"."deleted_at" IS NULL) AND "incident_updates"."incident_id" = $1 AND "incident_updates"."order" = $2 ORDER BY "incident_updates"."incident_id" LIMIT $3`) //nolint:lll
+		expectedUpdatedIncidentUpdateQuery = regexp.
+							QuoteMeta(`SELECT * FROM "incident_updates" WHERE "incident_updates"."incident_id" = $1 AND "incident_updates"."order" = $2 ORDER BY "incident_updates"."incident_id" LIMIT $3`) //nolint:lll
+		expectedVisibleIncidentCount = regexp.
+						QuoteMeta(`SELECT COUNT("id") FROM "incidents" WHERE id = $1 AND "incidents"."deleted_at" IS NULL`)
 		expectedHighestIncidentUpdateOrderQuery = regexp.
 							QuoteMeta(`SELECT COALESCE(MAX("order"), -1) FROM "incident_updates" WHERE incident_id = $1`)
 
@@ -687,8 +873,8 @@ var _ = Describe("IncidentUpdate", func() {
 		// setup database and mock before each test
 		var gormDB *gorm.DB
 
-		sqlDB, sqlMock, gormDB = test.MustMockGorm(gormLogger)
-		handlers = server.New(gormDB, handlerLogger)
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
 
 		// create mock rows before each test
 		incidentUpdateRows = sqlmock.
@@ -813,9 +999,15 @@ var _ = Describe("IncidentUpdate", func() {
 					ExpectQuery(expectedHighestIncidentUpdateOrderQuery).
 					WithArgs(incidentID).
 					WillReturnRows(highestIncidentUpdateOrderRows.AddRow(highestOrder))
+				sqlMock.
+					ExpectQuery(expectedVisibleIncidentCount).
+					WithArgs(incidentID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 				sqlMock.
 					ExpectExec(expectedIncidentUpdateInsert).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationCreate, db.AuditTargetIncidentUpdate, incidentID+"/0")
+				expectEvent(dialect, sqlMock, db.EventIncidentUpdateCreated)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -850,7 +1042,31 @@ var _ = Describe("IncidentUpdate", func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(err).Should(MatchError(server.ErrEmptyRequest))
+			})
+		})
+
+		Context("without existing incident", func() {
+			It("should return 404 not found", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedHighestIncidentUpdateOrderQuery).
+					WithArgs(incidentID).
+					WillReturnRows(highestIncidentUpdateOrderRows.AddRow(-1))
+				sqlMock.
+					ExpectQuery(expectedVisibleIncidentCount).
+					WithArgs(incidentID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.CreateIncidentUpdate(ctx, incidentUUID)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrNotFound))
 			})
 		})
 
@@ -884,6 +1100,10 @@ var _ = Describe("IncidentUpdate", func() {
 						ExpectQuery(expectedHighestIncidentUpdateOrderQuery).
 						WithArgs(incidentID).
 						WillReturnRows(highestIncidentUpdateOrderRows.AddRow(highestOrder))
+					sqlMock.
+						ExpectQuery(expectedVisibleIncidentCount).
+						WithArgs(incidentID).
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 					sqlMock.
 						ExpectExec(expectedIncidentUpdateInsert).
 						WillReturnError(test.ErrTestError)
@@ -920,9 +1140,23 @@ var _ = Describe("IncidentUpdate", func() {
 			It("should return 204 no content", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedIncidentUpdateQuery).
+					WithArgs(incidentID, incidentUpdateOrder, 1).
+					WillReturnRows(
+						incidentUpdateRows.AddRow(
+							incidentUpdate.IncidentID,  // incident_id
+							incidentUpdate.Order,       // order
+							incidentUpdate.DisplayName, // display_name
+							incidentUpdate.Description, // description
+							incidentUpdate.CreatedAt,   // created_at
+						),
+					)
 				sqlMock.ExpectExec(expectedIncidentUpdateDelete).
-					WithArgs(incidentID, incidentUpdateOrder).
+					WithArgs(incidentID, incidentUpdateOrder, 1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationDelete, db.AuditTargetIncidentUpdate, incidentID+"/"+strconv.Itoa(incidentUpdateOrder))
+				expectEvent(dialect, sqlMock, db.EventIncidentUpdateDeleted)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -935,15 +1169,15 @@ var _ = Describe("IncidentUpdate", func() {
 			})
 		})
 
-		Context("without affected row", func() {
+		Context("without existing incident update", func() {
 			It("should return 404 not found", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
 				sqlMock.
-					ExpectExec(expectedIncidentUpdateDelete).
-					WithArgs(incidentID, incidentUpdateOrder).
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				sqlMock.ExpectCommit()
+					ExpectQuery(expectedIncidentUpdateQuery).
+					WithArgs(incidentID, incidentUpdateOrder, 1).
+					WillReturnRows(incidentUpdateRows)
+				sqlMock.ExpectRollback()
 
 				// Act
 				err := handlers.DeleteIncidentUpdate(ctx, incidentUUID, incidentUpdateOrder)
@@ -958,9 +1192,21 @@ var _ = Describe("IncidentUpdate", func() {
 			It("should return 500 internal server error", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedIncidentUpdateQuery).
+					WithArgs(incidentID, incidentUpdateOrder, 1).
+					WillReturnRows(
+						incidentUpdateRows.AddRow(
+							incidentUpdate.IncidentID,  // incident_id
+							incidentUpdate.Order,       // order
+							incidentUpdate.DisplayName, // display_name
+							incidentUpdate.Description, // description
+							incidentUpdate.CreatedAt,   // created_at
+						),
+					)
 				sqlMock.
 					ExpectExec(expectedIncidentUpdateDelete).
-					WithArgs(incidentID, incidentUpdateOrder).
+					WithArgs(incidentID, incidentUpdateOrder, 1).
 					WillReturnError(test.ErrTestError)
 				sqlMock.ExpectRollback()
 
@@ -1074,10 +1320,32 @@ var _ = Describe("IncidentUpdate", func() {
 			It("should return 204 no conntent", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedIncidentUpdateQueryWithTable).
+					WithArgs(incidentID, incidentUpdateOrder, 1).
+					WillReturnRows(
+						incidentUpdateRows.AddRow(
+							incidentUpdate.IncidentID,  // incident_id
+							incidentUpdate.Order,       // order
+							incidentUpdate.DisplayName, // display_name
+							incidentUpdate.Description, // description
+							incidentUpdate.CreatedAt,   // created_at
+						),
+					)
 				sqlMock.
 					ExpectExec(expectedIncidentUpdateUpdate).
-					WithArgs("NIC was down", incidentID, incidentUpdateOrder).
+					WithArgs("NIC was down", 2, 1, incidentID, incidentUpdateOrder).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.
+					ExpectQuery(expectedUpdatedIncidentUpdateQuery).
+					WithArgs(incidentID, incidentUpdateOrder, 1).
+					WillReturnRows(
+						sqlmock.
+							NewRows([]string{"incident_id", "order", "description"}).
+							AddRow(incidentID, incidentUpdateOrder, "NIC was down"),
+					)
+				expectAuditEntry(sqlMock, db.AuditOperationUpdate, db.AuditTargetIncidentUpdate, incidentID+"/"+strconv.Itoa(incidentUpdateOrder))
+				expectEvent(dialect, sqlMock, db.EventIncidentUpdateUpdated)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -1105,7 +1373,8 @@ var _ = Describe("IncidentUpdate", func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(err).Should(MatchError(server.ErrEmptyRequest))
 			})
 		})
 
@@ -1113,9 +1382,21 @@ var _ = Describe("IncidentUpdate", func() {
 			It("should return 500 internal server error", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedIncidentUpdateQueryWithTable).
+					WithArgs(incidentID, incidentUpdateOrder, 1).
+					WillReturnRows(
+						incidentUpdateRows.AddRow(
+							incidentUpdate.IncidentID,  // incident_id
+							incidentUpdate.Order,       // order
+							incidentUpdate.DisplayName, // display_name
+							incidentUpdate.Description, // description
+							incidentUpdate.CreatedAt,   // created_at
+						),
+					)
 				sqlMock.
 					ExpectExec(expectedIncidentUpdateUpdate).
-					WithArgs("NIC was down", incidentID, incidentUpdateOrder).
+					WithArgs("NIC was down", 2, 1, incidentID, incidentUpdateOrder).
 					WillReturnError(test.ErrTestError)
 				sqlMock.ExpectRollback()
 
@@ -1128,15 +1409,15 @@ var _ = Describe("IncidentUpdate", func() {
 			})
 		})
 
-		Context("without affected rows", func() {
+		Context("without existing incident update", func() {
 			It("should return 404 not found", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
 				sqlMock.
-					ExpectExec(expectedIncidentUpdateUpdate).
-					WithArgs("NIC was down", incidentID, incidentUpdateOrder).
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				sqlMock.ExpectCommit()
+					ExpectQuery(expectedIncidentUpdateQueryWithTable).
+					WithArgs(incidentID, incidentUpdateOrder, 1).
+					WillReturnRows(incidentUpdateRows)
+				sqlMock.ExpectRollback()
 
 				// Act
 				err := handlers.UpdateIncidentUpdate(ctx, incidentUUID, incidentUpdateOrder)
@@ -1147,4 +1428,4 @@ var _ = Describe("IncidentUpdate", func() {
 			})
 		})
 	})
-})
+}, test.DialectEntries())
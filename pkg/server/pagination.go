@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// defaultPageLimit is the number of items on a page, if not limited by the request.
+	defaultPageLimit = 100
+
+	// maxPageLimit is the highest number of items on a single page.
+	maxPageLimit = 1000
+)
+
+// pageFromRequest reads the page of a listing from the [api.PageParams] of the request.
+// The parameters are not part of the OpenAPI spec, so they are bound separately.
+func pageFromRequest(ctx echo.Context) (storage.Page, error) {
+	var params api.PageParams
+
+	page := storage.Page{Limit: defaultPageLimit, After: nil}
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return page, fmt.Errorf("error binding page parameters: %w", err)
+	}
+
+	if params.Limit != nil {
+		if *params.Limit < 1 || *params.Limit > maxPageLimit {
+			return page, fmt.Errorf("%w: limit %d out of range", echo.ErrBadRequest, *params.Limit)
+		}
+
+		page.Limit = *params.Limit
+	}
+
+	if params.Cursor != nil {
+		page.After, err = storage.ParseCursor(*params.Cursor)
+		if err != nil {
+			return page, fmt.Errorf("error parsing cursor: %w", err)
+		}
+	}
+
+	return page, nil
+}
+
+// respondPage responds with a page of a listing.
+// The next page is linked in the response and by the `Link` header, with the parameters of the request.
+func respondPage[T any](ctx echo.Context, data []T, next *storage.Cursor) error {
+	response := api.PageResponse[T]{Data: data, Next: nil}
+
+	if next != nil {
+		nextURL := *ctx.Request().URL
+		query := nextURL.Query()
+		query.Set("cursor", next.String())
+		nextURL.RawQuery = query.Encode()
+
+		link := nextURL.RequestURI()
+		response.Next = &link
+
+		ctx.Response().Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, link))
+	}
+
+	return ctx.JSON(http.StatusOK, response) //nolint:wrapcheck
+}
@@ -4,11 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
 // GetPhaseList retrieves a list of all phases.
@@ -23,12 +24,10 @@ func (i *Implementation) GetPhaseList( //nolint:funlen
 
 	logger := i.logger.With().Str("handler", "GetPhaseList").Logger()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
-
-	err := dbSession.Transaction(func(dbTx *gorm.DB) error {
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
 		var transactionErr error
 
-		generation, transactionErr = DbDef.GetCurrentPhaseGeneration(dbTx)
+		generation, transactionErr = repo.CurrentPhaseGeneration()
 		if transactionErr != nil {
 			return fmt.Errorf("error getting current generation: %w", transactionErr)
 		}
@@ -47,11 +46,9 @@ func (i *Implementation) GetPhaseList( //nolint:funlen
 
 		logger.Debug().Int("generation", generation).Send()
 
-		var phases []*DbDef.Phase
-
-		res := dbTx.Where("generation = ?", generation).Order("\"order\" asc").Find(&phases)
-		if res.Error != nil {
-			return fmt.Errorf("error loading phase list: %w", res.Error)
+		phases, transactionErr := repo.ListPhases(generation)
+		if transactionErr != nil {
+			return fmt.Errorf("error loading phase list: %w", transactionErr)
 		}
 
 		data = make([]apiServerDefinition.Phase, len(phases))
@@ -66,11 +63,11 @@ func (i *Implementation) GetPhaseList( //nolint:funlen
 		case errors.Is(err, ErrInvalidPhaseGeneration):
 			logger.Warn().Err(err).Send()
 
-			return echo.ErrBadRequest
+			return echo.ErrBadRequest.WithInternal(err)
 		case errors.Is(err, ErrPhaseGenerationNotFound):
 			logger.Warn().Err(err).Send()
 
-			return echo.ErrNotFound
+			return echo.ErrNotFound.WithInternal(err)
 		}
 
 		logger.Error().Err(err).Msg("error in database transaction")
@@ -105,15 +102,13 @@ func (i *Implementation) CreatePhaseList(ctx echo.Context) error { //nolint:funl
 	if len(request.Phases) == 0 {
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
-
-	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
 		var transactionErr error
 
-		generation, transactionErr = DbDef.GetCurrentPhaseGeneration(dbTx)
+		generation, transactionErr = repo.CurrentPhaseGeneration()
 		if transactionErr != nil {
 			return fmt.Errorf("error getting current generation: %w", transactionErr)
 		}
@@ -135,12 +130,21 @@ func (i *Implementation) CreatePhaseList(ctx echo.Context) error { //nolint:funl
 			}
 		}
 
-		res := dbTx.Create(phases)
-		if res.Error != nil {
-			return fmt.Errorf("error creating phase list: %w", res.Error)
+		transactionErr = repo.CreatePhases(phases)
+		if transactionErr != nil {
+			return fmt.Errorf("error creating phase list: %w", transactionErr)
 		}
 
-		return nil
+		return recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetPhaseList,
+			targetID:   strconv.Itoa(generation),
+			before:     nil,
+			after: apiServerDefinition.PhaseListResponseData{
+				Generation: generation,
+				Phases:     request.Phases,
+			},
+		})
 	})
 	if err != nil {
 		logger.Error().Err(err).Msg("error in transaction")
@@ -6,12 +6,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
 	"github.com/SovereignCloudStack/status-page-api/pkg/db"
 	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/labstack/echo/v4"
 	. "github.com/onsi/ginkgo/v2"
@@ -20,7 +22,7 @@ import (
 	"gorm.io/gorm"
 )
 
-var _ = Describe("Phase", Ordered, func() {
+var _ = DescribeTableSubtree("Phase", Ordered, func(dialect test.Dialect) {
 	var (
 		// sub loggers
 		echoLogger    *zerolog.Logger
@@ -40,7 +42,7 @@ var _ = Describe("Phase", Ordered, func() {
 
 		// expected SQL
 		expectedPhaseListQuery = regexp.
-					QuoteMeta(`SELECT * FROM "phases" WHERE generation = $1 ORDER BY "order" asc`)
+					QuoteMeta(`SELECT * FROM "phases" WHERE generation = $1 ORDER BY "order"`)
 		expectedLastPhaseGenerationQuery = regexp.
 							QuoteMeta(`SELECT COALESCE(MAX(generation), 0) FROM "phases"`)
 		expectedPhaseListInsert = regexp.
@@ -83,8 +85,8 @@ var _ = Describe("Phase", Ordered, func() {
 		// setup database and mock before each test
 		var gormDB *gorm.DB
 
-		sqlDB, sqlMock, gormDB = test.MustMockGorm(gormLogger)
-		handlers = server.New(gormDB, handlerLogger)
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
 
 		// create mock rows before each test
 		phaseRows = sqlmock.
@@ -242,7 +244,7 @@ var _ = Describe("Phase", Ordered, func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
 			})
 		})
 
@@ -264,7 +266,7 @@ var _ = Describe("Phase", Ordered, func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrNotFound))
+				Ω(err).Should(HaveField("Code", http.StatusNotFound))
 			})
 		})
 
@@ -336,6 +338,7 @@ var _ = Describe("Phase", Ordered, func() {
 				sqlMock.ExpectExec(expectedPhaseListInsert).
 					WithArgs("Phase 1", nextPhaseGeneration, 0, "Phase 2", nextPhaseGeneration, 1, "Phase 3", nextPhaseGeneration, 2).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationCreate, db.AuditTargetPhaseList, strconv.Itoa(nextPhaseGeneration))
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -366,7 +369,7 @@ var _ = Describe("Phase", Ordered, func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
 			})
 		})
 
@@ -411,4 +414,4 @@ var _ = Describe("Phase", Ordered, func() {
 			})
 		})
 	})
-})
+}, test.DialectEntries())
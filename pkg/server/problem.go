@@ -0,0 +1,98 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// NewErrorHandler creates the error handler of the echo server, which responds to every error with an [api.Problem].
+// Handlers return an [echo.HTTPError] unwrapped, as its code is the status of the response, and attach the domain
+// error as internal error, which selects the problem type and the invalid params. Other errors are answered with
+// an internal server error.
+func NewErrorHandler(logger *zerolog.Logger) echo.HTTPErrorHandler {
+	return func(err error, ctx echo.Context) {
+		if ctx.Response().Committed {
+			return
+		}
+
+		problem := ProblemFromError(err)
+		problem.Instance = ctx.Request().URL.Path
+		problem.RequestID = ctx.Response().Header().Get(echo.HeaderXRequestID)
+
+		ctx.Response().Header().Set(echo.HeaderContentType, api.ProblemContentType)
+
+		if ctx.Request().Method == http.MethodHead {
+			err = ctx.NoContent(problem.Status)
+		} else {
+			err = ctx.JSON(problem.Status, problem)
+		}
+
+		if err != nil {
+			logger.Error().Err(err).Msg("error sending problem response")
+		}
+	}
+}
+
+// ProblemFromError describes an error returned by a handler or middleware.
+// Errors, which are no [echo.HTTPError], are internal server errors.
+// The causes of server errors are never exposed.
+func ProblemFromError(err error) api.Problem {
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		httpErr = echo.ErrInternalServerError
+	}
+
+	problem := api.Problem{ //nolint:exhaustruct
+		Type:   api.ProblemTypeGeneric,
+		Title:  http.StatusText(httpErr.Code),
+		Status: httpErr.Code,
+	}
+
+	if message := fmt.Sprint(httpErr.Message); message != problem.Title {
+		problem.Detail = message
+	}
+
+	if httpErr.Code >= http.StatusInternalServerError || httpErr.Internal == nil {
+		return problem
+	}
+
+	problem.Type = problemType(httpErr.Internal)
+	problem.InvalidParams = invalidParams(httpErr.Internal)
+
+	if problem.Detail == "" {
+		var causeErr *echo.HTTPError
+		if errors.As(httpErr.Internal, &causeErr) {
+			problem.Detail = fmt.Sprint(causeErr.Message)
+		} else {
+			problem.Detail = httpErr.Internal.Error()
+		}
+	}
+
+	return problem
+}
+
+// invalidParams collects the fields of all [DbDef.FieldError] in the tree of err.
+func invalidParams(err error) []api.InvalidParam {
+	switch typedErr := err.(type) { //nolint:errorlint
+	case *DbDef.FieldError:
+		return []api.InvalidParam{{Name: typedErr.Field, Reason: typedErr.Err.Error()}}
+	case interface{ Unwrap() []error }:
+		var params []api.InvalidParam
+
+		for _, childErr := range typedErr.Unwrap() {
+			params = append(params, invalidParams(childErr)...)
+		}
+
+		return params
+	case interface{ Unwrap() error }:
+		return invalidParams(typedErr.Unwrap())
+	default:
+		return nil
+	}
+}
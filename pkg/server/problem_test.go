@@ -0,0 +1,142 @@
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Problem", func() {
+	var (
+		// sub loggers
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// actual function under test
+		errorHandler = server.NewErrorHandler(handlerLogger)
+	)
+
+	Describe("ProblemFromError", func() {
+		It("should describe domain errors by their type", func() {
+			// Act
+			problem := server.ProblemFromError(echo.ErrBadRequest.WithInternal(db.ErrEndsBeforeStart))
+
+			// Assert
+			Ω(problem.Type).Should(Equal(api.ProblemTypeBase + "ends-before-start"))
+			Ω(problem.Title).Should(Equal("Bad Request"))
+			Ω(problem.Status).Should(Equal(http.StatusBadRequest))
+			Ω(problem.Detail).Should(Equal(db.ErrEndsBeforeStart.Error()))
+			Ω(problem.InvalidParams).Should(BeEmpty())
+		})
+
+		It("should list the invalid fields", func() {
+			// Arrange
+			err := fmt.Errorf("error parsing affects: %w", db.NewFieldError("affects[1].severity", db.ErrSeverityValueOutOfRange))
+
+			// Act
+			problem := server.ProblemFromError(echo.ErrBadRequest.WithInternal(err))
+
+			// Assert
+			Ω(problem.Type).Should(Equal(api.ProblemTypeBase + "severity-value-out-of-range"))
+			Ω(problem.InvalidParams).Should(ConsistOf(api.InvalidParam{
+				Name:   "affects[1].severity",
+				Reason: db.ErrSeverityValueOutOfRange.Error(),
+			}))
+		})
+
+		It("should list the invalid fields of joined errors", func() {
+			// Arrange
+			err := errors.Join(
+				db.NewFieldError("phase", server.ErrUnknownPhase),
+				db.NewFieldError("endedAt", db.ErrEndsBeforeStart),
+			)
+
+			// Act
+			problem := server.ProblemFromError(echo.ErrBadRequest.WithInternal(err))
+
+			// Assert
+			Ω(problem.Type).Should(Equal(api.ProblemTypeBase + "unknown-phase"))
+			Ω(problem.InvalidParams).Should(HaveLen(2))
+		})
+
+		It("should describe errors without type by their status", func() {
+			// Act
+			problem := server.ProblemFromError(echo.ErrNotFound)
+
+			// Assert
+			Ω(problem.Type).Should(Equal(api.ProblemTypeGeneric))
+			Ω(problem.Status).Should(Equal(http.StatusNotFound))
+			Ω(problem.Detail).Should(BeEmpty())
+		})
+
+		It("should keep the message of the HTTP error", func() {
+			// Act
+			problem := server.ProblemFromError(echo.NewHTTPError(http.StatusConflict, "component impacted by incidents"))
+
+			// Assert
+			Ω(problem.Status).Should(Equal(http.StatusConflict))
+			Ω(problem.Detail).Should(Equal("component impacted by incidents"))
+		})
+
+		It("should hide the cause of server errors", func() {
+			// Act
+			problem := server.ProblemFromError(echo.ErrInternalServerError.WithInternal(test.ErrTestError))
+
+			// Assert
+			Ω(problem.Type).Should(Equal(api.ProblemTypeGeneric))
+			Ω(problem.Status).Should(Equal(http.StatusInternalServerError))
+			Ω(problem.Detail).Should(BeEmpty())
+		})
+
+		It("should treat unknown errors as server errors", func() {
+			// Act
+			problem := server.ProblemFromError(test.ErrTestError)
+
+			// Assert
+			Ω(problem.Status).Should(Equal(http.StatusInternalServerError))
+			Ω(problem.Detail).Should(BeEmpty())
+		})
+	})
+
+	Describe("NewErrorHandler", func() {
+		It("should respond with problem details", func() {
+			// Arrange
+			ctx, res := test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodPost, "/incidents", nil)
+			ctx.Response().Header().Set(echo.HeaderXRequestID, "request-1")
+
+			// Act
+			errorHandler(echo.ErrBadRequest.WithInternal(server.ErrEmptyRequest), ctx)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusBadRequest))
+			Ω(res.Header().Get(echo.HeaderContentType)).Should(Equal(api.ProblemContentType))
+
+			var problem api.Problem
+			Ω(json.Unmarshal(res.Body.Bytes(), &problem)).Should(Succeed())
+			Ω(problem.Type).Should(Equal(api.ProblemTypeBase + "empty-request"))
+			Ω(problem.Instance).Should(Equal("/incidents"))
+			Ω(problem.RequestID).Should(Equal("request-1"))
+		})
+
+		It("should respond without body to HEAD requests", func() {
+			// Arrange
+			ctx, res := test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodHead, "/incidents", nil)
+
+			// Act
+			errorHandler(echo.ErrNotFound, ctx)
+
+			// Assert
+			Ω(res.Code).Should(Equal(http.StatusNotFound))
+			Ω(res.Body.Len()).Should(BeZero())
+		})
+	})
+})
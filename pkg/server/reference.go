@@ -0,0 +1,145 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+)
+
+// RequireSeverityBands sets, whether the severity values of impacts must fall into the band of a defined severity.
+// A severity covers the values above the next lower severity up to its own value, so values above the highest
+// severity are rejected. The maintenance severity is always accepted.
+func (i *Implementation) RequireSeverityBands(required bool) *Implementation {
+	i.requireSeverityBands = required
+
+	return i
+}
+
+// invalidReferences checks the phase of the incident and the components, impact types and severities of its impacts.
+// Every invalid reference is returned as [DbDef.FieldError], the error is only set on failures of the repository.
+func (i *Implementation) invalidReferences(repo storage.Repository, incident *DbDef.Incident) ([]error, error) {
+	var invalidErrs []error
+
+	if incident.Phase != nil {
+		_, err := repo.GetPhase(*incident.Phase.Generation, *incident.Phase.Order)
+		if errors.Is(err, storage.ErrNotFound) {
+			invalidErrs = append(invalidErrs, DbDef.NewFieldError("phase", ErrUnknownPhase))
+		} else if err != nil {
+			return nil, fmt.Errorf("error loading phase: %w", err)
+		}
+	}
+
+	if incident.Affects == nil {
+		return invalidErrs, nil
+	}
+
+	checker := referenceChecker{
+		repo:        repo,
+		components:  make(map[DbDef.ID]bool),
+		impactTypes: make(map[DbDef.ID]bool),
+		severities:  nil,
+	}
+
+	for impactIndex, impact := range *incident.Affects {
+		field := fmt.Sprintf("affects[%d]", impactIndex)
+
+		found, err := checker.componentExists(impact.ComponentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			invalidErrs = append(invalidErrs, DbDef.NewFieldError(field+".reference", ErrUnknownComponent))
+		}
+
+		found, err = checker.impactTypeExists(impact.ImpactTypeID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			invalidErrs = append(invalidErrs, DbDef.NewFieldError(field+".type", ErrUnknownImpactType))
+		}
+
+		if !i.requireSeverityBands || impact.Severity == nil {
+			continue
+		}
+
+		found, err = checker.severityBandExists(*impact.Severity)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			invalidErrs = append(invalidErrs, DbDef.NewFieldError(field+".severity", ErrSeverityOutsideBands))
+		}
+	}
+
+	return invalidErrs, nil
+}
+
+// referenceChecker remembers the checked references, so impacts referencing the same resources load them once.
+type referenceChecker struct {
+	repo        storage.Repository
+	components  map[DbDef.ID]bool
+	impactTypes map[DbDef.ID]bool
+	severities  []*DbDef.Severity
+}
+
+// componentExists tells, whether the component exists and is not deleted.
+func (c *referenceChecker) componentExists(componentID *DbDef.ID) (bool, error) {
+	if componentID == nil {
+		return false, nil
+	}
+
+	if found, checked := c.components[*componentID]; checked {
+		return found, nil
+	}
+
+	_, err := c.repo.GetComponent(*componentID, nil)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return false, fmt.Errorf("error loading component: %w", err)
+	}
+
+	c.components[*componentID] = err == nil
+
+	return err == nil, nil
+}
+
+// impactTypeExists tells, whether the impact type exists.
+func (c *referenceChecker) impactTypeExists(impactTypeID *DbDef.ID) (bool, error) {
+	if impactTypeID == nil {
+		return false, nil
+	}
+
+	if found, checked := c.impactTypes[*impactTypeID]; checked {
+		return found, nil
+	}
+
+	_, err := c.repo.GetImpactType(*impactTypeID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return false, fmt.Errorf("error loading impact type: %w", err)
+	}
+
+	c.impactTypes[*impactTypeID] = err == nil
+
+	return err == nil, nil
+}
+
+// severityBandExists tells, whether the severity value falls into the band of a defined severity.
+func (c *referenceChecker) severityBandExists(severity int) (bool, error) {
+	if c.severities == nil {
+		severities, _, err := c.repo.ListSeverities(storage.Page{Limit: 0, After: nil})
+		if err != nil {
+			return false, fmt.Errorf("error loading severities: %w", err)
+		}
+
+		c.severities = severities
+	}
+
+	status := statusOf(&severity, c.severities)
+
+	return status.Maintenance || status.DisplayName != nil, nil
+}
@@ -0,0 +1,230 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("References", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		store storage.Storage
+		repo  storage.Repository
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		componentID  uuid.UUID
+		impactTypeID uuid.UUID
+		incidentID   uuid.UUID
+
+		newIncident = func(affects ...apiServerDefinition.Impact) apiServerDefinition.Incident {
+			return apiServerDefinition.Incident{
+				DisplayName: test.Ptr("Switch failure"),
+				BeganAt:     test.Ptr(time.Now().Add(-time.Hour).UTC()),
+				Affects:     &affects,
+			}
+		}
+
+		create = func(incident apiServerDefinition.Incident) (*httptest.ResponseRecorder, error) {
+			ctx, res := test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodPost, "/incidents", incident)
+
+			return res, handlers.CreateIncident(ctx)
+		}
+
+		update = func(incident apiServerDefinition.Incident) error {
+			ctx, _ := test.MustCreateEchoContextAndResponseWriter(
+				echoLogger, http.MethodPatch, "/incidents/"+incidentID.String(), incident,
+			)
+
+			return handlers.UpdateIncident(ctx, incidentID)
+		}
+	)
+
+	BeforeEach(func() {
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store = storage.NewMemory()
+		repo = store.WithContext(context.Background())
+		handlers = server.New(store, nil, nil, nil, handlerLogger)
+
+		component := &db.Component{DisplayName: test.Ptr("Network")}
+		Ω(repo.CreateComponent(component)).Should(Succeed())
+		componentID = component.ID
+
+		impactType := &db.ImpactType{DisplayName: test.Ptr("Connectivity issues")}
+		Ω(repo.CreateImpactType(impactType)).Should(Succeed())
+		impactTypeID = impactType.ID
+
+		Ω(repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr("degraded"), Value: test.Ptr(50)})).Should(Succeed())
+		Ω(repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr("limited"), Value: test.Ptr(75)})).Should(Succeed())
+
+		incident := &db.Incident{
+			DisplayName: test.Ptr("Router failure"),
+			BeganAt:     test.Ptr(time.Now().Add(-2 * time.Hour).UTC()),
+		}
+		Ω(repo.CreateIncident(incident)).Should(Succeed())
+		incidentID = incident.ID
+	})
+
+	Describe("CreateIncident", func() {
+		Context("with valid references", func() {
+			It("should create the incident", func() {
+				// Act
+				res, err := create(newIncident(
+					apiServerDefinition.Impact{Reference: &componentID, Type: &impactTypeID, Severity: test.Ptr(100)},
+				))
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusCreated))
+			})
+		})
+
+		Context("with unknown references", func() {
+			It("should list every invalid reference", func() {
+				// Arrange
+				unknownID := uuid.New()
+				incident := newIncident(
+					apiServerDefinition.Impact{Reference: &unknownID, Type: &impactTypeID, Severity: test.Ptr(50)},
+					apiServerDefinition.Impact{Reference: &componentID, Type: &unknownID, Severity: test.Ptr(50)},
+				)
+				incident.Phase = &apiServerDefinition.PhaseReference{Generation: 1, Order: 0}
+
+				// Act
+				_, err := create(incident)
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(err).Should(MatchError(server.ErrUnknownComponent))
+				Ω(err).Should(MatchError(server.ErrUnknownImpactType))
+				Ω(err).Should(MatchError(server.ErrUnknownPhase))
+
+				problem := server.ProblemFromError(err)
+				Ω(problem.InvalidParams).Should(ConsistOf(
+					HaveField("Name", "phase"),
+					HaveField("Name", "affects[0].reference"),
+					HaveField("Name", "affects[1].type"),
+				))
+			})
+
+			It("should reject deleted components", func() {
+				// Arrange
+				_, err := repo.DeleteComponent(componentID, "admin")
+				Ω(err).ShouldNot(HaveOccurred())
+
+				// Act
+				_, err = create(newIncident(
+					apiServerDefinition.Impact{Reference: &componentID, Type: &impactTypeID, Severity: test.Ptr(50)},
+				))
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(err).Should(MatchError(server.ErrUnknownComponent))
+			})
+		})
+
+		Context("with severity outside the severity bands", func() {
+			It("should accept the severity by default", func() {
+				// Act
+				_, err := create(newIncident(
+					apiServerDefinition.Impact{Reference: &componentID, Type: &impactTypeID, Severity: test.Ptr(100)},
+				))
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("should reject the severity, if severity bands are required", func() {
+				// Arrange
+				handlers.RequireSeverityBands(true)
+
+				// Act
+				_, err := create(newIncident(
+					apiServerDefinition.Impact{Reference: &componentID, Type: &impactTypeID, Severity: test.Ptr(60)},
+					apiServerDefinition.Impact{Reference: &componentID, Type: &impactTypeID, Severity: test.Ptr(100)},
+				))
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(err).Should(MatchError(server.ErrSeverityOutsideBands))
+				Ω(server.ProblemFromError(err).InvalidParams).Should(ConsistOf(
+					HaveField("Name", "affects[1].severity"),
+				))
+			})
+
+			It("should accept maintenance, if severity bands are required", func() {
+				// Arrange
+				handlers.RequireSeverityBands(true)
+
+				incident := newIncident(
+					apiServerDefinition.Impact{Reference: &componentID, Type: &impactTypeID, Severity: test.Ptr(0)},
+				)
+				incident.EndedAt = test.Ptr(time.Now().Add(time.Hour).UTC())
+
+				// Act
+				_, err := create(incident)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("UpdateIncident", func() {
+		Context("with unknown references", func() {
+			It("should list every invalid reference", func() {
+				// Arrange
+				unknownID := uuid.New()
+
+				// Act
+				err := update(newIncident(
+					apiServerDefinition.Impact{Reference: &unknownID, Type: &unknownID, Severity: test.Ptr(50)},
+				))
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(server.ProblemFromError(err).InvalidParams).Should(ConsistOf(
+					HaveField("Name", "affects[0].reference"),
+					HaveField("Name", "affects[0].type"),
+				))
+
+				incident, err := repo.GetIncident(incidentID)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(incident.DisplayName).Should(HaveValue(Equal("Router failure")))
+			})
+		})
+
+		Context("with severity outside the severity bands", func() {
+			It("should reject the severity, if severity bands are required", func() {
+				// Arrange
+				handlers.RequireSeverityBands(true)
+
+				// Act
+				err := update(newIncident(
+					apiServerDefinition.Impact{Reference: &componentID, Type: &impactTypeID, Severity: test.Ptr(80)},
+				))
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+				Ω(err).Should(MatchError(server.ErrSeverityOutsideBands))
+			})
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// atFromRequest reads from the [api.RevisionParams] of the request, at which time incidents are evaluated.
+// The parameters are not part of the OpenAPI spec, so they are bound separately.
+func atFromRequest(ctx echo.Context) (*time.Time, error) {
+	var params api.RevisionParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("error binding revision parameters: %w", err)
+	}
+
+	return params.At, nil
+}
+
+// GetIncidentRevisions lists the revisions of an incident, oldest first.
+func (i *Implementation) GetIncidentRevisions(ctx echo.Context, incidentID uuid.UUID) error {
+	logger := i.logger.With().Str("handler", "GetIncidentRevisions").Interface("id", incidentID).Logger()
+	logger.Debug().Send()
+
+	revisions, err := i.storage.WithContext(ctx.Request().Context()).ListIncidentRevisions(incidentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			logger.Warn().Msg("incident not found")
+
+			return echo.ErrNotFound
+		}
+
+		logger.Error().Err(err).Msg("error loading incident revisions")
+
+		return echo.ErrInternalServerError
+	}
+
+	data := make([]api.IncidentRevision, len(revisions))
+	for revisionIndex, revision := range revisions {
+		data[revisionIndex] = revision.ToAPIResponse()
+	}
+
+	return ctx.JSON(http.StatusOK, api.IncidentRevisionListResponse{Data: data}) //nolint:wrapcheck
+}
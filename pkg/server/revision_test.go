@@ -0,0 +1,154 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Revision", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		repo storage.Repository
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		incidentID uuid.UUID
+		beganAt    time.Time
+		between    time.Time
+
+		newContext = func(method, url string) (echo.Context, *httptest.ResponseRecorder) {
+			return test.MustCreateEchoContextAndResponseWriter(echoLogger, method, url, nil)
+		}
+	)
+
+	BeforeEach(func() {
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store := storage.NewMemory()
+		repo = store.WithContext(context.Background())
+		handlers = server.New(store, nil, nil, nil, handlerLogger)
+
+		network := &db.Component{DisplayName: test.Ptr("Network")}
+		Ω(repo.CreateComponent(network)).Should(Succeed())
+
+		impactType := &db.ImpactType{DisplayName: test.Ptr("Connectivity issues")}
+		Ω(repo.CreateImpactType(impactType)).Should(Succeed())
+		Ω(repo.CreatePhases([]db.Phase{
+			{Name: test.Ptr("Investigation"), Generation: test.Ptr(1), Order: test.Ptr(0)},
+		})).Should(Succeed())
+
+		beganAt = time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+		incident := &db.Incident{
+			DisplayName: test.Ptr("Switch failure"),
+			BeganAt:     &beganAt,
+			Phase:       &db.Phase{Generation: test.Ptr(1), Order: test.Ptr(0)},
+			Affects: &[]db.Impact{
+				{ComponentID: &network.ID, ImpactTypeID: &impactType.ID, Severity: test.Ptr(50)},
+			},
+		}
+		Ω(repo.CreateIncident(incident)).Should(Succeed())
+		incidentID = incident.ID
+
+		between = time.Now()
+
+		_, _, err := repo.UpdateIncident(&db.Incident{
+			Model:       db.Model{ID: incidentID},
+			DisplayName: test.Ptr("Router failure"),
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	Describe("GetIncidentRevisions", func() {
+		It("should list the revisions oldest first", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, "/incidents/"+incidentID.String()+"/revisions")
+
+			// Act
+			err := handlers.GetIncidentRevisions(ctx, incidentID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusOK))
+
+			var response api.IncidentRevisionListResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(response.Data).Should(HaveLen(2))
+			Ω(response.Data[0].Revision).Should(Equal(1))
+			Ω(*response.Data[0].DisplayName).Should(Equal("Switch failure"))
+			Ω(response.Data[0].Affects).Should(HaveLen(1))
+			Ω(*response.Data[0].Phase).Should(Equal(api.IncidentRevisionPhase{Generation: 1, Order: 0}))
+			Ω(response.Data[1].Revision).Should(Equal(2))
+			Ω(*response.Data[1].DisplayName).Should(Equal("Router failure"))
+		})
+
+		It("should return not found for unknown incidents", func() {
+			// Arrange
+			unknownID := uuid.New()
+			ctx, _ := newContext(http.MethodGet, "/incidents/"+unknownID.String()+"/revisions")
+
+			// Act
+			err := handlers.GetIncidentRevisions(ctx, unknownID)
+
+			// Assert
+			Ω(err).Should(Equal(echo.ErrNotFound))
+		})
+	})
+
+	Describe("GetIncidents", func() {
+		var params apiServerDefinition.GetIncidentsParams
+
+		BeforeEach(func() {
+			params = apiServerDefinition.GetIncidentsParams{Start: beganAt, End: time.Now()}
+		})
+
+		It("should list incidents by their revisions valid at the time", func() {
+			// Arrange
+			ctx, res := newContext(http.MethodGet, "/incidents?at="+url.QueryEscape(between.Format(time.RFC3339Nano)))
+
+			// Act
+			err := handlers.GetIncidents(ctx, params)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusOK))
+
+			var response apiServerDefinition.IncidentListResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+			Ω(response.Data).Should(HaveLen(1))
+			Ω(*response.Data[0].DisplayName).Should(Equal("Switch failure"))
+		})
+
+		It("should refuse invalid times", func() {
+			// Arrange
+			ctx, _ := newContext(http.MethodGet, "/incidents?at=yesterday")
+
+			// Act
+			err := handlers.GetIncidents(ctx, params)
+
+			// Assert
+			Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+		})
+	})
+})
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/labstack/echo/v4"
+)
+
+// selectorFromRequest reads the label selector from the [api.SelectorParams] of the request, nil selects everything.
+// The parameters are not part of the OpenAPI spec, so they are bound separately.
+func selectorFromRequest(ctx echo.Context) (DbDef.Selector, error) {
+	var params api.SelectorParams
+
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("error binding selector parameters: %w", err)
+	}
+
+	if params.LabelSelector == nil {
+		return nil, nil
+	}
+
+	selector, err := DbDef.ParseSelector(*params.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing label selector: %w", err)
+	}
+
+	return selector, nil
+}
@@ -1,20 +1,67 @@
 package server
 
 import (
+	"github.com/SovereignCloudStack/status-page-api/pkg/alertmanager"
+	"github.com/SovereignCloudStack/status-page-api/pkg/events"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/SovereignCloudStack/status-page-api/pkg/subscription"
+	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 )
 
 // Implementation holds all functions definded by the [api.ServerInterface] and other needed components.
 type Implementation struct {
-	dbCon  *gorm.DB
-	logger *zerolog.Logger
+	storage       storage.Storage
+	dbCon         *gorm.DB
+	eventBroker   *events.Broker
+	subscriptions *subscription.Composer
+	alerts        *alertmanager.Mapper
+	logger        *zerolog.Logger
+
+	// requireSeverityBands rejects impacts, whose severity falls into no defined severity.
+	requireSeverityBands bool
 }
 
-// New creates a new [Implementation] Object with the setted dbCon.
-func New(dbCon *gorm.DB, logger *zerolog.Logger) *Implementation {
+// New creates a new [Implementation] Object with the setted storage.
+// The handlers of the OpenAPI spec work on any storage, the extension handlers need the database
+// of a [storage.Gorm] and respond with not found on other storages.
+// The eventBroker wakes up event streams and is only needed to serve them.
+// Without subscriptions composer, the subscriber endpoints respond with not found.
+// Without alerts mapper, the Alertmanager receiver responds with not found.
+func New(
+	store storage.Storage,
+	eventBroker *events.Broker,
+	subscriptions *subscription.Composer,
+	alerts *alertmanager.Mapper,
+	logger *zerolog.Logger,
+) *Implementation {
+	var dbCon *gorm.DB
+
+	if gormStorage, ok := store.(*storage.Gorm); ok {
+		dbCon = gormStorage.DB()
+	}
+
 	return &Implementation{
-		dbCon:  dbCon,
-		logger: logger,
+		storage:       store,
+		dbCon:         dbCon,
+		eventBroker:   eventBroker,
+		subscriptions: subscriptions,
+		alerts:        alerts,
+		logger:        logger,
+
+		requireSeverityBands: false,
 	}
 }
+
+// databaseSession returns a session on the database for the handlers beyond the [storage.Repository].
+// Without database, i.e. on other storages than [storage.Gorm], they respond with not found.
+func (i *Implementation) databaseSession(ctx echo.Context, logger *zerolog.Logger) (*gorm.DB, error) {
+	if i.dbCon == nil {
+		logger.Warn().Msg("not available without database")
+
+		return nil, echo.ErrNotFound
+	}
+
+	return i.dbCon.WithContext(ctx.Request().Context()), nil
+}
@@ -5,23 +5,30 @@ import (
 	"net/http"
 
 	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
-// GetSeverities retrieves a list of all severities.
+// GetSeverities retrieves a page of the severities, sorted by value.
 func (i *Implementation) GetSeverities(ctx echo.Context) error {
-	var severities []*DbDef.Severity
-
 	logger := i.logger.With().Str("handler", "GetSeverities").Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	page, err := pageFromRequest(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid page parameters")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	severities, next, err := i.storage.WithContext(ctx.Request().Context()).ListSeverities(page)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		logger.Warn().Err(err).Msg("invalid cursor")
 
-	res := dbSession.Find(&severities)
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error loading severites")
+		return echo.ErrBadRequest.WithInternal(err)
+	} else if err != nil {
+		logger.Error().Err(err).Msg("error loading severites")
 
 		return echo.ErrInternalServerError
 	}
@@ -31,9 +38,7 @@ func (i *Implementation) GetSeverities(ctx echo.Context) error {
 		data[severityIndex] = severity.ToAPIResponse()
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.SeverityListResponse{ //nolint:wrapcheck
-		Data: data,
-	})
+	return respondPage(ctx, data, next)
 }
 
 // CreateSeverity handles creation of severities.
@@ -52,25 +57,42 @@ func (i *Implementation) CreateSeverity(ctx echo.Context) error {
 	if request == (apiServerDefinition.CreateSeverityJSONRequestBody{}) { //nolint: exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
 	logger.Debug().Interface("request", request).Send()
 
 	severity, err := DbDef.SeverityFromAPI(&request)
 	if err != nil {
-		logger.Error().Err(err).Msg("error parsing request")
+		logger.Warn().Err(err).Msg("error parsing request")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
 
-		return echo.ErrBadRequest
+	// The display name identifies the severity.
+	var severityName string
+	if severity.DisplayName != nil {
+		severityName = *severity.DisplayName
 	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		transactionErr := repo.CreateSeverity(severity)
+		if transactionErr != nil {
+			return transactionErr //nolint:wrapcheck
+		}
 
-	res := dbSession.Create(&severity)
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error creating severity")
+		return recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetSeverity,
+			targetID:   severityName,
+			before:     nil,
+			after:      severity,
+		})
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("error in transaction")
 
-		if errors.Is(res.Error, gorm.ErrDuplicatedKey) {
+		if errors.Is(err, storage.ErrDuplicate) {
 			return echo.ErrBadRequest
 		}
 
@@ -88,19 +110,48 @@ func (i *Implementation) DeleteSeverity(
 	logger := i.logger.With().Str("handler", "DeleteSeverity").Str("name", severityName).Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err := i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionErr := checkIfMatch(ctx, &logger, severityName, severityVersion(repo, severityName))
+		if transactionErr != nil {
+			return transactionErr
+		}
 
-	res := dbSession.Where("display_name = ?", severityName).Delete(&DbDef.Severity{}) //nolint: exhaustruct
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error deleting severity")
+		dbSeverity, transactionErr := repo.DeleteSeverity(severityName)
+		if transactionErr == nil && !ifMatch.holds(dbSeverity.Version) {
+			transactionErr = storage.ErrConflict
+		}
 
-		return echo.ErrInternalServerError
-	}
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("severity not found")
 
-	if res.RowsAffected == 0 {
-		logger.Warn().Msg("severity not found")
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrConflict) {
+			logger.Warn().Msg("severity changed concurrently")
+
+			return ifMatch.conflict()
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error deleting severity")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationDelete,
+			targetType: DbDef.AuditTargetSeverity,
+			targetID:   severityName,
+			before:     dbSeverity,
+			after:      nil,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording deletion")
+
+			return echo.ErrInternalServerError
+		}
 
-		return echo.ErrNotFound
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
 
 	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
@@ -111,33 +162,41 @@ func (i *Implementation) GetSeverity( //nolint:dupl
 	ctx echo.Context,
 	severityName apiServerDefinition.SeverityNamePathParameter,
 ) error {
-	var severity DbDef.Severity
-
 	logger := i.logger.With().Str("handler", "GetSeverity").Str("name", severityName).Logger()
 	logger.Debug().Send()
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
-
-	res := dbSession.Where("display_name = ?", severityName).First(&severity)
-	if res.Error != nil {
-		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+	severity, err := i.storage.WithContext(ctx.Request().Context()).GetSeverity(severityName)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
 			logger.Warn().Msg("severity not found")
 
 			return echo.ErrNotFound
 		}
 
-		logger.Error().Err(res.Error).Msg("error loading severity")
+		logger.Error().Err(err).Msg("error loading severity")
 
 		return echo.ErrInternalServerError
 	}
 
-	return ctx.JSON(http.StatusOK, apiServerDefinition.SeverityResponse{ //nolint:wrapcheck
+	return sendTagged(ctx, severityName, severity.Version, apiServerDefinition.SeverityResponse{
 		Data: severity.ToAPIResponse(),
 	})
 }
 
+// severityVersion loads the version of a severity, for conditional changes.
+func severityVersion(repo storage.Repository, severityName string) func() (*int, error) {
+	return func() (*int, error) {
+		severity, err := repo.GetSeverity(severityName)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return severity.Version, nil
+	}
+}
+
 // UpdateSeverity handles updates of severities.
-func (i *Implementation) UpdateSeverity(
+func (i *Implementation) UpdateSeverity( //nolint:funlen
 	ctx echo.Context,
 	severityName apiServerDefinition.SeverityNamePathParameter,
 ) error {
@@ -155,7 +214,7 @@ func (i *Implementation) UpdateSeverity(
 	if request == (apiServerDefinition.UpdateSeverityJSONRequestBody{}) { //nolint:exhaustruct
 		logger.Warn().Msg("empty request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(ErrEmptyRequest)
 	}
 
 	logger.Debug().Interface("request", request).Send()
@@ -164,22 +223,51 @@ func (i *Implementation) UpdateSeverity(
 	if err != nil {
 		logger.Warn().Err(err).Msg("error parsing request")
 
-		return echo.ErrBadRequest
+		return echo.ErrBadRequest.WithInternal(err)
 	}
 
-	dbSession := i.dbCon.WithContext(ctx.Request().Context())
+	err = i.storage.Transaction(ctx.Request().Context(), func(repo storage.Repository) error {
+		ifMatch, transactionErr := checkIfMatch(ctx, &logger, severityName, severityVersion(repo, severityName))
+		if transactionErr != nil {
+			return transactionErr
+		}
 
-	res := dbSession.Where("display_name = ?", severityName).Updates(severity)
-	if res.Error != nil {
-		logger.Error().Err(res.Error).Msg("error updating severity")
+		dbSeverity, updatedSeverity, transactionErr := repo.UpdateSeverity(severityName, severity)
+		if transactionErr == nil && !ifMatch.holds(dbSeverity.Version) {
+			transactionErr = storage.ErrConflict
+		}
 
-		return echo.ErrInternalServerError
-	}
+		if errors.Is(transactionErr, storage.ErrNotFound) {
+			logger.Warn().Msg("severity not found")
 
-	if res.RowsAffected == 0 {
-		logger.Warn().Msg("severity not found")
+			return echo.ErrNotFound
+		} else if errors.Is(transactionErr, storage.ErrConflict) {
+			logger.Warn().Msg("severity changed concurrently")
+
+			return ifMatch.conflict()
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error updating severity")
 
-		return echo.ErrNotFound
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, repo, change{
+			operation:  DbDef.AuditOperationUpdate,
+			targetType: DbDef.AuditTargetSeverity,
+			targetID:   severityName,
+			before:     dbSeverity,
+			after:      updatedSeverity,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording update")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
 
 	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
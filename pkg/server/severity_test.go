@@ -12,6 +12,7 @@ import (
 	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
 	"github.com/SovereignCloudStack/status-page-api/pkg/db"
 	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
 	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
 	"github.com/labstack/echo/v4"
 	. "github.com/onsi/ginkgo/v2"
@@ -20,7 +21,7 @@ import (
 	"gorm.io/gorm"
 )
 
-var _ = Describe("Severity", func() {
+var _ = DescribeTableSubtree("Severity", func(dialect test.Dialect) {
 	var (
 		// sub loggers
 		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
@@ -41,11 +42,11 @@ var _ = Describe("Severity", func() {
 		expectedSeverityQuery = regexp.
 					QuoteMeta(`SELECT * FROM "severities" WHERE display_name = $1 ORDER BY "severities"."display_name" LIMIT $2`)
 		expectedSeverityInsert = regexp.
-					QuoteMeta(`INSERT INTO "severities" ("display_name","value") VALUES ($1,$2)`)
+					QuoteMeta(`INSERT INTO "severities" ("display_name","value","version") VALUES ($1,$2,$3)`)
 		expectedSeverityDelete = regexp.
-					QuoteMeta(`DELETE FROM "severities" WHERE display_name = $1`)
+					QuoteMeta(`DELETE FROM "severities" WHERE display_name = $1 AND version = $2`)
 		expectedSeverityUpdate = regexp.
-					QuoteMeta(`UPDATE "severities" SET "display_name"=$1 WHERE display_name = $2`)
+					QuoteMeta(`UPDATE "severities" SET "display_name"=$1,"version"=$2 WHERE display_name = $3 AND version = $4`)
 
 		// filled test severity
 		severity = db.Severity{
@@ -60,8 +61,8 @@ var _ = Describe("Severity", func() {
 		// setup database and mock before each test
 		var gormDB *gorm.DB
 
-		sqlDB, sqlMock, gormDB = test.MustMockGorm(gormLogger)
-		handlers = server.New(gormDB, handlerLogger)
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
 
 		// create mock rows before each test
 		severityRows = sqlmock.
@@ -176,6 +177,7 @@ var _ = Describe("Severity", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
 				sqlMock.ExpectExec(expectedSeverityInsert).WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationCreate, db.AuditTargetSeverity, *severity.DisplayName)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -197,7 +199,7 @@ var _ = Describe("Severity", func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
 			})
 		})
 
@@ -238,10 +240,15 @@ var _ = Describe("Severity", func() {
 			It("should return 204 no content", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedSeverityQuery).
+					WithArgs(severity.DisplayName, 1).
+					WillReturnRows(severityRows.AddRow(severity.DisplayName, severity.Value))
 				sqlMock.
 					ExpectExec(expectedSeverityDelete).
-					WithArgs(severity.DisplayName).
+					WithArgs(severity.DisplayName, 1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationDelete, db.AuditTargetSeverity, *severity.DisplayName)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -254,15 +261,12 @@ var _ = Describe("Severity", func() {
 			})
 		})
 
-		Context("without affected row", func() {
+		Context("without existing severity", func() {
 			It("should return 404 not found", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.
-					ExpectExec(expectedSeverityDelete).
-					WithArgs(severity.DisplayName).
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				sqlMock.ExpectCommit()
+				sqlMock.ExpectQuery(expectedSeverityQuery).WithArgs(severity.DisplayName, 1).WillReturnRows(severityRows)
+				sqlMock.ExpectRollback()
 
 				// Act
 				err := handlers.DeleteSeverity(ctx, *severity.DisplayName)
@@ -277,9 +281,13 @@ var _ = Describe("Severity", func() {
 			It("should return 500 internal server error", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedSeverityQuery).
+					WithArgs(severity.DisplayName, 1).
+					WillReturnRows(severityRows.AddRow(severity.DisplayName, severity.Value))
 				sqlMock.
 					ExpectExec(expectedSeverityDelete).
-					WithArgs(severity.DisplayName).
+					WithArgs(severity.DisplayName, 1).
 					WillReturnError(test.ErrTestError)
 				sqlMock.ExpectRollback()
 
@@ -384,10 +392,22 @@ var _ = Describe("Severity", func() {
 			It("should return 204 no conntent", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedSeverityQuery).
+					WithArgs(severity.DisplayName, 1).
+					WillReturnRows(severityRows.AddRow(severity.DisplayName, severity.Value))
 				sqlMock.
 					ExpectExec(expectedSeverityUpdate).
-					WithArgs("impacted", severity.DisplayName).
+					WithArgs("impacted", 2, severity.DisplayName, 1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.
+					ExpectQuery(expectedSeverityQuery).
+					WithArgs("impacted", 1).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"display_name", "value"}).
+						AddRow("impacted", severity.Value),
+					)
+				expectAuditEntry(sqlMock, db.AuditOperationUpdate, db.AuditTargetSeverity, *severity.DisplayName)
 				sqlMock.ExpectCommit()
 
 				// Act
@@ -400,6 +420,28 @@ var _ = Describe("Severity", func() {
 			})
 		})
 
+		Context("with concurrent change", func() {
+			It("should return 409 conflict", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedSeverityQuery).
+					WithArgs(severity.DisplayName, 1).
+					WillReturnRows(severityRows.AddRow(severity.DisplayName, severity.Value))
+				sqlMock.
+					ExpectExec(expectedSeverityUpdate).
+					WithArgs("impacted", 2, severity.DisplayName, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.UpdateSeverity(ctx, *severity.DisplayName)
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusConflict))
+			})
+		})
+
 		Context("with empty request", func() {
 			It("should return 400 bad request", func() {
 				// Arrange
@@ -415,7 +457,7 @@ var _ = Describe("Severity", func() {
 
 				// Assert
 				Ω(err).Should(HaveOccurred())
-				Ω(err).Should(Equal(echo.ErrBadRequest))
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
 			})
 		})
 
@@ -423,9 +465,13 @@ var _ = Describe("Severity", func() {
 			It("should return 500 internal server error", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedSeverityQuery).
+					WithArgs(severity.DisplayName, 1).
+					WillReturnRows(severityRows.AddRow(severity.DisplayName, severity.Value))
 				sqlMock.
 					ExpectExec(expectedSeverityUpdate).
-					WithArgs("impacted", severity.DisplayName).
+					WithArgs("impacted", 2, severity.DisplayName, 1).
 					WillReturnError(test.ErrTestError)
 				sqlMock.ExpectRollback()
 
@@ -438,15 +484,12 @@ var _ = Describe("Severity", func() {
 			})
 		})
 
-		Context("without affected rows", func() {
+		Context("without existing severity", func() {
 			It("should return 404 not found", func() {
 				// Arrange
 				sqlMock.ExpectBegin()
-				sqlMock.
-					ExpectExec(expectedSeverityUpdate).
-					WithArgs("impacted", severity.DisplayName).
-					WillReturnResult(sqlmock.NewResult(0, 0))
-				sqlMock.ExpectCommit()
+				sqlMock.ExpectQuery(expectedSeverityQuery).WithArgs(severity.DisplayName, 1).WillReturnRows(severityRows)
+				sqlMock.ExpectRollback()
 
 				// Act
 				err := handlers.UpdateSeverity(ctx, *severity.DisplayName)
@@ -457,4 +500,4 @@ var _ = Describe("Severity", func() {
 			})
 		})
 	})
-})
+}, test.DialectEntries())
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// GetStatus summarizes the status of all components, overall and per label value.
+// The status is derived from the impacts active at the requested time, or the ongoing impacts.
+func (i *Implementation) GetStatus(ctx echo.Context, params api.GetStatusParams) error {
+	logger := i.logger.With().Str("handler", "GetStatus").Logger()
+	logger.Debug().Interface("at", params.At).Send()
+
+	repo := i.storage.WithContext(ctx.Request().Context())
+	allItems := storage.Page{Limit: 0, After: nil}
+
+	components, _, err := repo.ListComponents(params.At, nil, allItems)
+	if err != nil {
+		logger.Error().Err(err).Msg("error loading components")
+
+		return echo.ErrInternalServerError
+	}
+
+	severities, _, err := repo.ListSeverities(allItems)
+	if err != nil {
+		logger.Error().Err(err).Msg("error loading severities")
+
+		return echo.ErrInternalServerError
+	}
+
+	groups, err := repo.ListComponentGroups()
+	if err != nil {
+		logger.Error().Err(err).Msg("error loading component groups")
+
+		return echo.ErrInternalServerError
+	}
+
+	return ctx.JSON(http.StatusOK, summarizeStatus(components, severities, groups, params)) //nolint:wrapcheck
+}
+
+// summarizeStatus rates each component by the highest severity of its active impacts.
+// The overall status and the status of a label value or a group are the worst status of the components they cover.
+func summarizeStatus(
+	components []*DbDef.Component,
+	severities []*DbDef.Severity,
+	groups []*DbDef.ComponentGroup,
+	params api.GetStatusParams,
+) api.StatusResponse {
+	var overall *int
+
+	response := api.StatusResponse{
+		At:         params.At,
+		Overall:    api.Status{}, //nolint:exhaustruct
+		Components: make([]api.ComponentStatus, 0, len(components)),
+		Labels:     map[string]map[string]api.Status{},
+		Groups:     nil,
+	}
+
+	labelSeverities := map[string]map[string]*int{}
+	componentSeverities := make(map[uuid.UUID]*int, len(components))
+
+	for _, component := range components {
+		var impacts []DbDef.Impact
+		if component.ActivelyAffectedBy != nil {
+			impacts = *component.ActivelyAffectedBy
+		}
+
+		incidents := make([]uuid.UUID, 0, len(impacts))
+
+		var highest *int
+
+		for _, impact := range impacts {
+			highest = higherSeverity(highest, impact.Severity)
+
+			if impact.IncidentID != nil {
+				incidents = append(incidents, *impact.IncidentID)
+			}
+		}
+
+		response.Components = append(response.Components, api.ComponentStatus{
+			ID:          component.ID,
+			DisplayName: component.DisplayName,
+			Status:      statusOf(highest, severities),
+			Incidents:   incidents,
+		})
+
+		overall = higherSeverity(overall, highest)
+		componentSeverities[component.ID] = highest
+
+		if component.Labels == nil {
+			continue
+		}
+
+		for key, value := range *component.Labels {
+			if labelSeverities[key] == nil {
+				labelSeverities[key] = map[string]*int{}
+			}
+
+			labelSeverities[key][value] = higherSeverity(labelSeverities[key][value], highest)
+		}
+	}
+
+	response.Overall = statusOf(overall, severities)
+
+	for key, values := range labelSeverities {
+		response.Labels[key] = make(map[string]api.Status, len(values))
+
+		for value, severity := range values {
+			response.Labels[key][value] = statusOf(severity, severities)
+		}
+	}
+
+	response.Groups, _ = summarizeGroups(nil, groups, componentSeverities, severities)
+
+	return response
+}
+
+// summarizeGroups rates the groups nested in the parent, or the top level groups if nil, in the order of the list.
+// It returns their status and the highest severity among them.
+func summarizeGroups(
+	parentID *uuid.UUID,
+	groups []*DbDef.ComponentGroup,
+	componentSeverities map[uuid.UUID]*int,
+	severities []*DbDef.Severity,
+) ([]api.GroupStatus, *int) {
+	var highest *int
+
+	statuses := []api.GroupStatus{}
+
+	for _, group := range groups {
+		if !sameParent(group.ParentID, parentID) {
+			continue
+		}
+
+		nested, groupSeverity := summarizeGroups(&group.ID, groups, componentSeverities, severities)
+		components := group.ComponentIDs()
+
+		for _, componentID := range components {
+			groupSeverity = higherSeverity(groupSeverity, componentSeverities[componentID])
+		}
+
+		statuses = append(statuses, api.GroupStatus{
+			ID:          group.ID,
+			DisplayName: group.DisplayName,
+			Description: group.Description,
+			Status:      statusOf(groupSeverity, severities),
+			Components:  components,
+			Groups:      nested,
+		})
+
+		highest = higherSeverity(highest, groupSeverity)
+	}
+
+	return statuses, highest
+}
+
+// higherSeverity returns the higher of both severities, nil is lower than any severity.
+func higherSeverity(a, b *int) *int {
+	if a == nil || (b != nil && *b > *a) {
+		return b
+	}
+
+	return a
+}
+
+// statusOf names the severity by the severity, whose value range contains it.
+// Severities are sorted by value, each covering the values above the previous one up to its own.
+func statusOf(severity *int, severities []*DbDef.Severity) api.Status {
+	status := api.Status{Severity: severity, DisplayName: nil, Maintenance: false}
+	if severity == nil {
+		return status
+	}
+
+	status.Maintenance = *severity == api.MaintenanceSeverity
+
+	for _, candidate := range severities {
+		if candidate.Value != nil && *candidate.Value >= *severity {
+			status.DisplayName = candidate.DisplayName
+
+			break
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,204 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Status", func() {
+	var (
+		// sub loggers
+		echoLogger    *zerolog.Logger
+		handlerLogger *zerolog.Logger
+
+		// storage under test
+		store *storage.Memory
+		repo  storage.Repository
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		now = time.Now().UTC().Truncate(time.Second)
+
+		west, east, network *db.Component
+		impactType          *db.ImpactType
+
+		createIncident = func(began time.Time, ended *time.Time, impacts map[*db.Component]int) {
+			affects := []db.Impact{}
+			for component, severity := range impacts {
+				affects = append(affects, db.Impact{
+					ComponentID:  &component.ID,
+					ImpactTypeID: &impactType.ID,
+					Severity:     test.Ptr(severity),
+				})
+			}
+
+			Ω(repo.CreateIncident(&db.Incident{
+				DisplayName: test.Ptr("Incident"),
+				BeganAt:     &began,
+				EndedAt:     ended,
+				Phase:       &db.Phase{Generation: test.Ptr(1), Order: test.Ptr(0)},
+				Affects:     &affects,
+			})).Should(Succeed())
+		}
+
+		getStatus = func(params api.GetStatusParams) api.StatusResponse {
+			ctx, res := test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/status", nil)
+
+			err := handlers.GetStatus(ctx, params)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Code).Should(Equal(http.StatusOK))
+
+			var response api.StatusResponse
+			Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+
+			return response
+		}
+
+		componentStatus = func(response api.StatusResponse, component *db.Component) api.ComponentStatus {
+			for _, status := range response.Components {
+				if status.ID == component.ID {
+					return status
+				}
+			}
+
+			Fail("component missing in status")
+
+			return api.ComponentStatus{}
+		}
+	)
+
+	BeforeEach(func() {
+		echoLogger, _, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		store = storage.NewMemory()
+		repo = store.WithContext(context.Background())
+		handlers = server.New(store, nil, nil, nil, handlerLogger)
+
+		west = &db.Component{DisplayName: test.Ptr("hypervisor-1"), Labels: &db.Labels{"region": "west"}}
+		east = &db.Component{DisplayName: test.Ptr("hypervisor-2"), Labels: &db.Labels{"region": "east"}}
+		network = &db.Component{DisplayName: test.Ptr("Network")}
+		impactType = &db.ImpactType{DisplayName: test.Ptr("Performance degration")}
+
+		for _, component := range []*db.Component{west, east, network} {
+			Ω(repo.CreateComponent(component)).Should(Succeed())
+		}
+
+		Ω(repo.CreateImpactType(impactType)).Should(Succeed())
+		Ω(repo.CreatePhases([]db.Phase{
+			{Name: test.Ptr("Investigation"), Generation: test.Ptr(1), Order: test.Ptr(0)},
+		})).Should(Succeed())
+
+		for name, value := range map[string]int{"operational": 33, "limited": 66, "broken": 100} {
+			Ω(repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr(name), Value: test.Ptr(value)})).Should(Succeed())
+		}
+	})
+
+	Context("without impacts", func() {
+		It("should report no severity", func() {
+			// Act
+			response := getStatus(api.GetStatusParams{})
+
+			// Assert
+			Ω(response.Overall).Should(Equal(api.Status{}))
+			Ω(response.Components).Should(HaveLen(3))
+			Ω(componentStatus(response, network).Incidents).Should(BeEmpty())
+			Ω(response.Labels).Should(Equal(map[string]map[string]api.Status{
+				"region": {"west": {}, "east": {}},
+			}))
+		})
+	})
+
+	Context("with ongoing impacts", func() {
+		It("should report the highest severity by the severity containing it", func() {
+			// Arrange
+			createIncident(now.Add(-time.Hour), nil, map[*db.Component]int{west: 40, network: 0})
+			createIncident(now.Add(-time.Hour), nil, map[*db.Component]int{west: 20})
+			createIncident(now.Add(-2*time.Hour), test.Ptr(now.Add(-time.Hour)), map[*db.Component]int{east: 100})
+
+			// Act
+			response := getStatus(api.GetStatusParams{})
+
+			// Assert
+			Ω(response.At).Should(BeNil())
+			Ω(response.Overall).Should(Equal(api.Status{Severity: test.Ptr(40), DisplayName: test.Ptr("limited")}))
+
+			Ω(componentStatus(response, west).Status).
+				Should(Equal(api.Status{Severity: test.Ptr(40), DisplayName: test.Ptr("limited")}))
+			Ω(componentStatus(response, west).Incidents).Should(HaveLen(2))
+			Ω(componentStatus(response, east).Status).Should(Equal(api.Status{}))
+			Ω(componentStatus(response, network).Status).Should(Equal(api.Status{
+				Severity: test.Ptr(0), DisplayName: test.Ptr("operational"), Maintenance: true,
+			}))
+
+			Ω(response.Labels["region"]).Should(Equal(map[string]api.Status{
+				"west": {Severity: test.Ptr(40), DisplayName: test.Ptr("limited")},
+				"east": {},
+			}))
+		})
+	})
+
+	Context("with component groups", func() {
+		It("should roll up the worst status of the members and nested groups", func() {
+			// Arrange
+			compute := &db.ComponentGroup{
+				DisplayName: test.Ptr("Compute"),
+				Order:       test.Ptr(0),
+				Members:     &[]db.ComponentGroupMember{{ComponentID: &network.ID}},
+			}
+			Ω(repo.CreateComponentGroup(compute)).Should(Succeed())
+
+			for order, component := range []*db.Component{west, east} {
+				Ω(repo.CreateComponentGroup(&db.ComponentGroup{
+					DisplayName: component.DisplayName,
+					ParentID:    &compute.ID,
+					Order:       test.Ptr(order),
+					Members:     &[]db.ComponentGroupMember{{ComponentID: &component.ID}},
+				})).Should(Succeed())
+			}
+
+			createIncident(now.Add(-time.Hour), nil, map[*db.Component]int{east: 70, network: 20})
+
+			// Act
+			response := getStatus(api.GetStatusParams{})
+
+			// Assert
+			Ω(response.Groups).Should(HaveLen(1))
+			Ω(response.Groups[0].Status).Should(Equal(api.Status{Severity: test.Ptr(70), DisplayName: test.Ptr("broken")}))
+			Ω(response.Groups[0].Components).Should(Equal([]uuid.UUID{network.ID}))
+			Ω(response.Groups[0].Groups).Should(HaveLen(2))
+			Ω(response.Groups[0].Groups[0].DisplayName).Should(Equal(west.DisplayName))
+			Ω(response.Groups[0].Groups[0].Status).Should(Equal(api.Status{}))
+			Ω(response.Groups[0].Groups[1].Status).Should(Equal(response.Groups[0].Status))
+		})
+	})
+
+	Context("with at param", func() {
+		It("should report the impacts active at the time", func() {
+			// Arrange
+			at := now.Add(-90 * time.Minute)
+			createIncident(now.Add(-2*time.Hour), test.Ptr(now.Add(-time.Hour)), map[*db.Component]int{east: 100})
+
+			// Act
+			response := getStatus(api.GetStatusParams{At: &at})
+
+			// Assert
+			Ω(response.At).Should(HaveValue(BeTemporally("==", at)))
+			Ω(response.Overall).Should(Equal(api.Status{Severity: test.Ptr(100), DisplayName: test.Ptr("broken")}))
+			Ω(response.Labels["region"]["east"]).Should(Equal(response.Overall))
+			Ω(response.Labels["region"]["west"]).Should(Equal(api.Status{}))
+		})
+	})
+})
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// CreateSubscriber handles subscriptions to email notifications and queues the confirmation email.
+// Subscribing an unconfirmed address again keeps its filters and only sends another confirmation,
+// when no confirmation with a valid link is queued or sent already.
+// The response is the same for new, unconfirmed and confirmed addresses, so subscribers can't be enumerated.
+func (i *Implementation) CreateSubscriber(ctx echo.Context) error {
+	var request api.SubscriberRequest
+
+	logger := i.logger.With().Str("handler", "CreateSubscriber").Logger()
+
+	if i.subscriptions == nil {
+		logger.Warn().Msg("subscriptions are disabled")
+
+		return echo.ErrNotFound
+	}
+
+	err := ctx.Bind(&request)
+	if err != nil {
+		logger.Error().Err(err).Msg("error binding request")
+
+		return echo.ErrInternalServerError
+	}
+
+	subscriber, err := DbDef.SubscriberFromAPI(&request)
+	if err != nil {
+		logger.Warn().Err(err).Msg("error parsing request")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
+		var dbSubscribers []*DbDef.Subscriber
+
+		transactionErr := dbTx.Where("email = ?", *subscriber.Email).Find(&dbSubscribers).Error
+		if transactionErr != nil {
+			return fmt.Errorf("error loading subscriber: %w", transactionErr)
+		}
+
+		switch {
+		case len(dbSubscribers) == 0:
+			transactionErr = dbTx.Create(subscriber).Error
+			if transactionErr != nil {
+				return fmt.Errorf("error creating subscriber: %w", transactionErr)
+			}
+		case dbSubscribers[0].ConfirmedAt != nil:
+			logger.Debug().Msg("subscriber already confirmed")
+
+			return nil
+		default:
+			var confirmations int64
+
+			transactionErr = dbTx.
+				Model(&DbDef.Notification{}). //nolint:exhaustruct
+				Where("subscriber_id = ?", dbSubscribers[0].ID).
+				Where("status <> ?", DbDef.NotificationDead).
+				Where("created_at > ?", i.subscriptions.ConfirmationsValidSince(now)).
+				Count(&confirmations).Error
+			if transactionErr != nil {
+				return fmt.Errorf("error loading confirmations: %w", transactionErr)
+			}
+
+			if confirmations > 0 {
+				logger.Debug().Msg("confirmation still pending")
+
+				return nil
+			}
+
+			// Filters are only set by the first subscription, so nobody else can change them before confirming.
+			subscriber = dbSubscribers[0]
+		}
+
+		notification, transactionErr := i.subscriptions.Confirmation(subscriber, now)
+		if transactionErr != nil {
+			return fmt.Errorf("error composing confirmation: %w", transactionErr)
+		}
+
+		transactionErr = dbTx.Create(notification).Error
+		if transactionErr != nil {
+			return fmt.Errorf("error queuing confirmation: %w", transactionErr)
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("error in transaction")
+
+		return echo.ErrInternalServerError
+	}
+
+	return ctx.NoContent(http.StatusAccepted) //nolint:wrapcheck
+}
+
+// ConfirmSubscriber confirms a subscription with the token of the confirmation email.
+// Confirming twice is not an error.
+func (i *Implementation) ConfirmSubscriber(ctx echo.Context, params api.SubscriberTokenParams) error {
+	logger := i.logger.With().Str("handler", "ConfirmSubscriber").Logger()
+
+	if i.subscriptions == nil {
+		logger.Warn().Msg("subscriptions are disabled")
+
+		return echo.ErrNotFound
+	}
+
+	if params.Token == nil {
+		logger.Warn().Msg("missing token")
+
+		return echo.ErrBadRequest
+	}
+
+	now := time.Now()
+
+	subscriberID, err := i.subscriptions.VerifyConfirmation(*params.Token, now)
+	if err != nil {
+		logger.Warn().Err(err).Msg("error verifying token")
+
+		return echo.ErrBadRequest
+	}
+
+	logger = logger.With().Str("id", subscriberID.String()).Logger()
+	logger.Debug().Send()
+
+	var subscriber DbDef.Subscriber
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	res := dbSession.Where("id = ?", subscriberID).First(&subscriber)
+	if errors.Is(res.Error, gorm.ErrRecordNotFound) {
+		logger.Warn().Msg("subscriber not found")
+
+		return echo.ErrNotFound
+	} else if res.Error != nil {
+		logger.Error().Err(res.Error).Msg("error loading subscriber")
+
+		return echo.ErrInternalServerError
+	}
+
+	if subscriber.ConfirmedAt == nil {
+		res = dbSession.
+			Model(&DbDef.Subscriber{Model: subscriber.Model}). //nolint:exhaustruct
+			Update("confirmed_at", now)
+		if res.Error != nil {
+			logger.Error().Err(res.Error).Msg("error confirming subscriber")
+
+			return echo.ErrInternalServerError
+		}
+
+		subscriber.ConfirmedAt = &now
+	}
+
+	return ctx.JSON(http.StatusOK, api.SubscriberResponse{ //nolint:wrapcheck
+		Data: subscriber.ToAPIResponse(),
+	})
+}
+
+// DeleteSubscriber cancels a subscription with the token of the unsubscribe link.
+// Queued notifications of the subscriber are deleted as well.
+func (i *Implementation) DeleteSubscriber(ctx echo.Context, params api.SubscriberTokenParams) error {
+	logger := i.logger.With().Str("handler", "DeleteSubscriber").Logger()
+
+	if i.subscriptions == nil {
+		logger.Warn().Msg("subscriptions are disabled")
+
+		return echo.ErrNotFound
+	}
+
+	if params.Token == nil {
+		logger.Warn().Msg("missing token")
+
+		return echo.ErrBadRequest
+	}
+
+	subscriberID, err := i.subscriptions.VerifyUnsubscribe(*params.Token, time.Now())
+	if err != nil {
+		logger.Warn().Err(err).Msg("error verifying token")
+
+		return echo.ErrBadRequest
+	}
+
+	logger = logger.With().Str("id", subscriberID.String()).Logger()
+	logger.Debug().Send()
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	res := dbSession.Where("id = ?", subscriberID).Delete(&DbDef.Subscriber{}) //nolint:exhaustruct
+	if res.Error != nil {
+		logger.Error().Err(res.Error).Msg("error deleting subscriber")
+
+		return echo.ErrInternalServerError
+	}
+
+	if res.RowsAffected == 0 {
+		logger.Warn().Msg("subscriber not found")
+
+		return echo.ErrNotFound
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
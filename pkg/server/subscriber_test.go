@@ -0,0 +1,403 @@
+package server_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/SovereignCloudStack/status-page-api/pkg/subscription"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = DescribeTableSubtree("Subscriber", func(dialect test.Dialect) {
+	const (
+		subscriberID     = "9e1b3c2a-5d4f-4a8b-8c7d-6e5f4a3b2c1d"
+		subscriberEmail  = "user@example.com"
+		subscriberSecret = "0123456789abcdef0123456789abcdef"
+	)
+
+	var (
+		// sub loggers
+		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		// token signing
+		signer = subscription.NewSigner(subscriberSecret)
+
+		// expected SQL
+		expectedSubscriberByEmailQuery = regexp.
+						QuoteMeta(`SELECT * FROM "subscribers" WHERE email = $1`)
+		expectedSubscriberQuery = regexp.
+					QuoteMeta(`SELECT * FROM "subscribers" WHERE id = $1 ORDER BY "subscribers"."id" LIMIT $2`)
+		expectedSubscriberInsert = regexp.
+						QuoteMeta(`INSERT INTO "subscribers" ("email","components","labels","created_at","confirmed_at","id") VALUES ($1,$2,$3,$4,$5,$6)`) //nolint:lll
+		expectedConfirmationCount = regexp.
+						QuoteMeta(`SELECT count(*) FROM "notifications" WHERE subscriber_id = $1 AND status <> $2 AND created_at > $3`) //nolint:lll
+		expectedSubscriberConfirm = regexp.
+						QuoteMeta(`UPDATE "subscribers" SET "confirmed_at"=$1 WHERE "id" = $2`)
+		expectedSubscriberDelete = regexp.
+						QuoteMeta(`DELETE FROM "subscribers" WHERE id = $1`)
+		expectedNotificationInsert = regexp.
+						QuoteMeta(`INSERT INTO "notifications" ("subscriber_id","recipient","subject","text_body","html_body","unsubscribe_url","status","attempts","next_attempt_at","last_error","created_at","sent_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) RETURNING "id"`) //nolint:lll
+
+		subscriberUUID = uuid.MustParse(subscriberID)
+		createdAt      = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		// setup database and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, subscription.NewComposer(signer, subscription.ComposerConfig{
+			PublicURL:  "https://status.example.com",
+			ConfirmTTL: time.Hour,
+		}), nil, handlerLogger)
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+	})
+
+	Describe("CreateSubscriber", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodPost,
+				"/subscribers",
+				api.SubscriberRequest{
+					Email:      test.Ptr("User@Example.com"),
+					Components: nil,
+					Labels:     []string{"region:west"},
+				},
+			)
+		})
+
+		Context("with new address", func() {
+			It("should create the subscriber and queue a confirmation", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedSubscriberByEmailQuery).
+					WithArgs(subscriberEmail).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				sqlMock.
+					ExpectExec(expectedSubscriberInsert).
+					WithArgs(subscriberEmail, `[]`, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.
+					ExpectQuery(expectedNotificationInsert).
+					WithArgs(
+						sqlmock.AnyArg(), subscriberEmail, "Confirm your status page subscription",
+						sqlmock.AnyArg(), sqlmock.AnyArg(), "",
+						"pending", 0, sqlmock.AnyArg(), nil, sqlmock.AnyArg(), nil,
+					).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.CreateSubscriber(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusAccepted))
+			})
+		})
+
+		Context("with unconfirmed address", func() {
+			It("should keep the filters and queue another confirmation", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedSubscriberByEmailQuery).
+					WithArgs(subscriberEmail).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "email", "components", "labels", "created_at", "confirmed_at"}).
+						AddRow(subscriberID, subscriberEmail, `[]`, `{}`, createdAt, nil),
+					)
+				sqlMock.
+					ExpectQuery(expectedConfirmationCount).
+					WithArgs(subscriberID, "dead", sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				sqlMock.
+					ExpectQuery(expectedNotificationInsert).
+					WithArgs(
+						subscriberID, subscriberEmail, sqlmock.AnyArg(),
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						"pending", 0, sqlmock.AnyArg(), nil, sqlmock.AnyArg(), nil,
+					).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.CreateSubscriber(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusAccepted))
+			})
+
+			Context("with pending confirmation", func() {
+				It("should respond the same without sending another confirmation", func() {
+					// Arrange
+					sqlMock.ExpectBegin()
+					sqlMock.
+						ExpectQuery(expectedSubscriberByEmailQuery).
+						WithArgs(subscriberEmail).
+						WillReturnRows(sqlmock.
+							NewRows([]string{"id", "email", "created_at", "confirmed_at"}).
+							AddRow(subscriberID, subscriberEmail, createdAt, nil),
+						)
+					sqlMock.
+						ExpectQuery(expectedConfirmationCount).
+						WithArgs(subscriberID, "dead", sqlmock.AnyArg()).
+						WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+					sqlMock.ExpectCommit()
+
+					// Act
+					err := handlers.CreateSubscriber(ctx)
+
+					// Assert
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(res.Code).Should(Equal(http.StatusAccepted))
+				})
+			})
+		})
+
+		Context("with confirmed address", func() {
+			It("should respond the same without changes", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedSubscriberByEmailQuery).
+					WithArgs(subscriberEmail).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "email", "created_at", "confirmed_at"}).
+						AddRow(subscriberID, subscriberEmail, createdAt, createdAt),
+					)
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.CreateSubscriber(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusAccepted))
+			})
+		})
+
+		Context("with invalid email", func() {
+			It("should return 400 bad request", func() {
+				// Arrange
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodPost,
+					"/subscribers",
+					api.SubscriberRequest{Email: test.Ptr("user"), Components: nil, Labels: nil},
+				)
+
+				// Act
+				err := handlers.CreateSubscriber(ctx)
+
+				// Assert
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+			})
+		})
+
+		Context("with disabled subscriptions", func() {
+			It("should return 404 not found", func() {
+				// Arrange
+				handlers = server.New(nil, nil, nil, nil, handlerLogger)
+
+				// Act
+				err := handlers.CreateSubscriber(ctx)
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrNotFound))
+			})
+		})
+	})
+
+	Describe("ConfirmSubscriber", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger, http.MethodGet, "/subscribers/confirm", nil,
+			)
+		})
+
+		Context("with valid token", func() {
+			It("should confirm the subscriber", func() {
+				// Arrange
+				var response api.SubscriberResponse
+
+				token := signer.Sign(subscription.PurposeConfirm, subscriberUUID, time.Now().Add(time.Hour))
+
+				sqlMock.
+					ExpectQuery(expectedSubscriberQuery).
+					WithArgs(subscriberID, 1).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "email", "created_at", "confirmed_at"}).
+						AddRow(subscriberID, subscriberEmail, createdAt, nil),
+					)
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectExec(expectedSubscriberConfirm).
+					WithArgs(sqlmock.AnyArg(), subscriberID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.ConfirmSubscriber(ctx, api.SubscriberTokenParams{Token: &token})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+				Ω(response.Data.Email).Should(Equal(subscriberEmail))
+				Ω(response.Data.ConfirmedAt).ShouldNot(BeNil())
+			})
+		})
+
+		Context("with expired token", func() {
+			It("should return 400 bad request", func() {
+				// Arrange
+				token := signer.Sign(subscription.PurposeConfirm, subscriberUUID, time.Now().Add(-time.Hour))
+
+				// Act
+				err := handlers.ConfirmSubscriber(ctx, api.SubscriberTokenParams{Token: &token})
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrBadRequest))
+			})
+		})
+
+		Context("with unsubscribe token", func() {
+			It("should return 400 bad request", func() {
+				// Arrange
+				token := signer.Sign(subscription.PurposeUnsubscribe, subscriberUUID, time.Time{})
+
+				// Act
+				err := handlers.ConfirmSubscriber(ctx, api.SubscriberTokenParams{Token: &token})
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrBadRequest))
+			})
+		})
+
+		Context("with deleted subscriber", func() {
+			It("should return 404 not found", func() {
+				// Arrange
+				token := signer.Sign(subscription.PurposeConfirm, subscriberUUID, time.Now().Add(time.Hour))
+
+				sqlMock.
+					ExpectQuery(expectedSubscriberQuery).
+					WithArgs(subscriberID, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+
+				// Act
+				err := handlers.ConfirmSubscriber(ctx, api.SubscriberTokenParams{Token: &token})
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrNotFound))
+			})
+		})
+	})
+
+	Describe("DeleteSubscriber", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger, http.MethodPost, "/subscribers/unsubscribe", nil,
+			)
+		})
+
+		Context("with valid token", func() {
+			It("should delete the subscriber", func() {
+				// Arrange
+				token := signer.Sign(subscription.PurposeUnsubscribe, subscriberUUID, time.Time{})
+
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectExec(expectedSubscriberDelete).
+					WithArgs(subscriberID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.DeleteSubscriber(ctx, api.SubscriberTokenParams{Token: &token})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("with deleted subscriber", func() {
+			It("should return 404 not found", func() {
+				// Arrange
+				token := signer.Sign(subscription.PurposeUnsubscribe, subscriberUUID, time.Time{})
+
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectExec(expectedSubscriberDelete).
+					WithArgs(subscriberID).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.DeleteSubscriber(ctx, api.SubscriberTokenParams{Token: &token})
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrNotFound))
+			})
+		})
+
+		Context("without token", func() {
+			It("should return 400 bad request", func() {
+				// Act
+				err := handlers.DeleteSubscriber(ctx, api.SubscriberTokenParams{Token: nil})
+
+				// Assert
+				Ω(err).Should(Equal(echo.ErrBadRequest))
+			})
+		})
+	})
+}, test.DialectEntries())
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/SovereignCloudStack/status-page-api/pkg/webhook"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// maxWebhookDeliveries is the highest number of deliveries returned by a single request.
+const maxWebhookDeliveries = 1000
+
+// GetWebhooks retrieves a list of all webhook subscriptions without their secrets.
+func (i *Implementation) GetWebhooks(ctx echo.Context) error {
+	var subscriptions []*DbDef.WebhookSubscription
+
+	logger := i.logger.With().Str("handler", "GetWebhooks").Logger()
+	logger.Debug().Send()
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	res := dbSession.Order("created_at").Find(&subscriptions)
+	if res.Error != nil {
+		logger.Error().Err(res.Error).Msg("error loading webhook subscriptions")
+
+		return echo.ErrInternalServerError
+	}
+
+	data := make([]api.WebhookResponseData, len(subscriptions))
+	for subscriptionIndex, subscription := range subscriptions {
+		data[subscriptionIndex] = subscription.ToAPIResponse()
+	}
+
+	return ctx.JSON(http.StatusOK, api.WebhookListResponse{ //nolint:wrapcheck
+		Data: data,
+	})
+}
+
+// CreateWebhook handles creation of webhook subscriptions.
+// The secret is only part of this response, a random one is generated if none is requested.
+func (i *Implementation) CreateWebhook(ctx echo.Context) error {
+	var request api.WebhookRequest
+
+	logger := i.logger.With().Str("handler", "CreateWebhook").Logger()
+
+	err := ctx.Bind(&request)
+	if err != nil {
+		logger.Error().Err(err).Msg("error binding request")
+
+		return echo.ErrInternalServerError
+	}
+
+	logger.Debug().Interface("targetUrl", request.TargetURL).Strs("events", request.Events).Send()
+
+	var secret string
+
+	if request.Secret != nil {
+		secret = *request.Secret
+	} else {
+		secret, err = webhook.GenerateSecret()
+		if err != nil {
+			logger.Error().Err(err).Msg("error generating secret")
+
+			return echo.ErrInternalServerError
+		}
+	}
+
+	subscription, err := DbDef.WebhookSubscriptionFromAPI(&request, secret)
+	if err != nil {
+		logger.Warn().Err(err).Msg("error parsing request")
+
+		return echo.ErrBadRequest.WithInternal(err)
+	}
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
+		transactionErr := dbTx.Create(&subscription).Error
+		if transactionErr != nil {
+			return fmt.Errorf("error creating webhook subscription: %w", transactionErr)
+		}
+
+		return recordChange(ctx, storage.NewGorm(dbTx), change{
+			operation:  DbDef.AuditOperationCreate,
+			targetType: DbDef.AuditTargetWebhook,
+			targetID:   subscription.ID.String(),
+			before:     nil,
+			after:      subscription,
+		})
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("error in transaction")
+
+		return echo.ErrInternalServerError
+	}
+
+	return ctx.JSON(http.StatusCreated, api.WebhookCreatedResponse{ //nolint:wrapcheck
+		Data: api.WebhookCreatedResponseData{
+			WebhookResponseData: subscription.ToAPIResponse(),
+			Secret:              secret,
+		},
+	})
+}
+
+// DeleteWebhook handles deletion of webhook subscriptions. Their deliveries are deleted as well.
+func (i *Implementation) DeleteWebhook(ctx echo.Context, webhookID uuid.UUID) error {
+	logger := i.logger.With().Str("handler", "DeleteWebhook").Str("id", webhookID.String()).Logger()
+	logger.Debug().Send()
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	err = dbSession.Transaction(func(dbTx *gorm.DB) error {
+		var dbSubscription DbDef.WebhookSubscription
+
+		transactionErr := dbTx.Where("id = ?", webhookID).First(&dbSubscription).Error
+		if errors.Is(transactionErr, gorm.ErrRecordNotFound) {
+			logger.Warn().Msg("webhook subscription not found")
+
+			return echo.ErrNotFound
+		} else if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error loading webhook subscription from database")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = dbTx.Where("id = ?", webhookID).Delete(&DbDef.WebhookSubscription{}).Error //nolint:exhaustruct
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error deleting webhook subscription")
+
+			return echo.ErrInternalServerError
+		}
+
+		transactionErr = recordChange(ctx, storage.NewGorm(dbTx), change{
+			operation:  DbDef.AuditOperationDelete,
+			targetType: DbDef.AuditTargetWebhook,
+			targetID:   webhookID.String(),
+			before:     dbSubscription,
+			after:      nil,
+		})
+		if transactionErr != nil {
+			logger.Error().Err(transactionErr).Msg("error recording deletion")
+
+			return echo.ErrInternalServerError
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
+
+// GetWebhookDeliveries retrieves the deliveries of a webhook subscription, newest first.
+func (i *Implementation) GetWebhookDeliveries(
+	ctx echo.Context,
+	webhookID uuid.UUID,
+	params api.GetWebhookDeliveriesParams,
+) error {
+	var deliveries []*DbDef.WebhookDelivery
+
+	logger := i.logger.With().Str("handler", "GetWebhookDeliveries").Str("id", webhookID.String()).Logger()
+	logger.Debug().Interface("params", params).Send()
+
+	if params.Status != nil && !DbDef.WebhookDeliveryStatus(*params.Status).IsValid() {
+		logger.Warn().Str("status", *params.Status).Msg("unknown delivery status")
+
+		return echo.ErrBadRequest
+	}
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	query := dbSession.Preload("Event").Where("subscription_id = ?", webhookID)
+
+	if params.Status != nil {
+		query = query.Where("status = ?", *params.Status)
+	}
+
+	res := query.Order("id desc").Limit(maxWebhookDeliveries).Find(&deliveries)
+	if res.Error != nil {
+		logger.Error().Err(res.Error).Msg("error loading webhook deliveries")
+
+		return echo.ErrInternalServerError
+	}
+
+	data := make([]api.WebhookDeliveryResponseData, len(deliveries))
+	for deliveryIndex, delivery := range deliveries {
+		data[deliveryIndex] = delivery.ToAPIResponse()
+	}
+
+	return ctx.JSON(http.StatusOK, api.WebhookDeliveryListResponse{ //nolint:wrapcheck
+		Data: data,
+	})
+}
+
+// RetryWebhookDelivery schedules a dead delivery for immediate delivery with a fresh set of attempts.
+func (i *Implementation) RetryWebhookDelivery(ctx echo.Context, webhookID uuid.UUID, deliveryID uint64) error {
+	logger := i.logger.With().
+		Str("handler", "RetryWebhookDelivery").
+		Str("id", webhookID.String()).
+		Uint64("delivery", deliveryID).
+		Logger()
+	logger.Debug().Send()
+
+	dbSession, err := i.databaseSession(ctx, &logger)
+	if err != nil {
+		return err
+	}
+
+	res := dbSession.
+		Model(&DbDef.WebhookDelivery{}). //nolint:exhaustruct
+		Where("id = ? AND subscription_id = ? AND status = ?", deliveryID, webhookID, DbDef.WebhookDeliveryDead).
+		Updates(map[string]interface{}{
+			"status":          DbDef.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if res.Error != nil {
+		logger.Error().Err(res.Error).Msg("error scheduling webhook delivery")
+
+		return echo.ErrInternalServerError
+	}
+
+	if res.RowsAffected == 0 {
+		logger.Warn().Msg("dead webhook delivery not found")
+
+		return echo.ErrNotFound
+	}
+
+	return ctx.NoContent(http.StatusNoContent) //nolint:wrapcheck
+}
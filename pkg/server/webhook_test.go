@@ -0,0 +1,430 @@
+package server_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/api"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/server"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = DescribeTableSubtree("Webhook", func(dialect test.Dialect) {
+	const (
+		webhookID  = "0d8fdc4f-37a4-4b3c-9f5e-6a4c0a6f1a2e"
+		targetURL  = "https://chat.example.com/hooks/status"
+		deliveryID = uint64(7)
+	)
+
+	var (
+		// sub loggers
+		echoLogger, gormLogger, handlerLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// mocked sql rows
+		webhookRows *sqlmock.Rows
+
+		// actual functions under test
+		handlers *server.Implementation
+
+		// expected SQL
+		expectedWebhooksQuery = regexp.
+					QuoteMeta(`SELECT * FROM "webhook_subscriptions" ORDER BY created_at`)
+		expectedWebhookQuery = regexp.
+					QuoteMeta(`SELECT * FROM "webhook_subscriptions" WHERE id = $1 ORDER BY "webhook_subscriptions"."id" LIMIT $2`) //nolint:lll
+		expectedWebhookInsert = regexp.
+					QuoteMeta(`INSERT INTO "webhook_subscriptions" ("target_url","events","secret","created_at","id") VALUES ($1,$2,$3,$4,$5)`) //nolint:lll
+		expectedWebhookDelete = regexp.
+					QuoteMeta(`DELETE FROM "webhook_subscriptions" WHERE id = $1`)
+		expectedWebhookDeliveriesQuery = regexp.
+						QuoteMeta(`SELECT * FROM "webhook_deliveries" WHERE subscription_id = $1 AND status = $2 ORDER BY id desc LIMIT $3`) //nolint:lll
+		expectedDeliveryEventsQuery = regexp.
+						QuoteMeta(`SELECT * FROM "events" WHERE "events"."id" = $1`)
+		expectedWebhookDeliveryRetry = regexp.
+						QuoteMeta(`UPDATE "webhook_deliveries" SET "attempts"=$1,"next_attempt_at"=$2,"status"=$3 WHERE id = $4 AND subscription_id = $5 AND status = $6`) //nolint:lll
+
+		webhookUUID = uuid.MustParse(webhookID)
+		createdAt   = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+		// filled test webhook subscription
+		subscription = db.WebhookSubscription{
+			TargetURL: test.Ptr(targetURL),
+			Events:    &db.WebhookEventTypes{db.EventIncidentCreated},
+			Secret:    test.Ptr("secret"),
+			CreatedAt: &createdAt,
+			Model:     db.Model{ID: webhookUUID},
+		}
+	)
+
+	BeforeEach(func() {
+		// setup database and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGormWithDialect(dialect, gormLogger)
+		handlers = server.New(storage.NewGorm(gormDB), nil, nil, nil, handlerLogger)
+
+		// create mock rows before each test
+		webhookRows = sqlmock.
+			NewRows([]string{"id", "target_url", "events", "secret", "created_at"})
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+	})
+
+	Describe("GetWebhooks", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(echoLogger, http.MethodGet, "/webhooks", nil)
+		})
+
+		Context("with valid data", func() {
+			It("should return a list of webhook subscriptions without secrets", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedWebhooksQuery).
+					WillReturnRows(webhookRows.AddRow(
+						webhookUUID, targetURL, `["incident.created"]`, "secret", createdAt,
+					))
+
+				expectedResult, _ := json.Marshal(api.WebhookListResponse{
+					Data: []api.WebhookResponseData{
+						subscription.ToAPIResponse(),
+					},
+				})
+
+				// Act
+				err := handlers.GetWebhooks(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(strings.Trim(res.Body.String(), "\n")).Should(Equal(string(expectedResult)))
+				Ω(res.Body.String()).ShouldNot(ContainSubstring("secret"))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedWebhooksQuery).
+					WillReturnError(test.ErrTestError)
+
+				// Act
+				err := handlers.GetWebhooks(ctx)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+
+	Describe("CreateWebhook", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodPost,
+				"/webhooks",
+				api.WebhookRequest{
+					TargetURL: test.Ptr(targetURL),
+					Events:    []string{"incident.created", "incident.resolved"},
+				},
+			)
+		})
+
+		Context("with valid request", func() {
+			It("should return the generated secret once", func() {
+				// Arrange
+				var response api.WebhookCreatedResponse
+
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectExec(expectedWebhookInsert).
+					WithArgs(
+						targetURL,
+						`["incident.created","incident.resolved"]`,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationCreate, db.AuditTargetWebhook, sqlmock.AnyArg())
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.CreateWebhook(ctx)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusCreated))
+				Ω(json.Unmarshal(res.Body.Bytes(), &response)).Should(Succeed())
+				Ω(response.Data.Secret).Should(HaveLen(64))
+				Ω(response.Data.Events).Should(Equal([]string{"incident.created", "incident.resolved"}))
+			})
+		})
+
+		Context("with unknown event", func() {
+			It("should return 400 bad request", func() {
+				// Arrange
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodPost,
+					"/webhooks",
+					api.WebhookRequest{
+						TargetURL: test.Ptr(targetURL),
+						Events:    []string{"incident.exploded"},
+					},
+				)
+
+				// Act
+				err := handlers.CreateWebhook(ctx)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+			})
+		})
+
+		Context("with invalid target URL", func() {
+			It("should return 400 bad request", func() {
+				// Arrange
+				ctx, _ = test.MustCreateEchoContextAndResponseWriter(
+					echoLogger,
+					http.MethodPost,
+					"/webhooks",
+					api.WebhookRequest{
+						TargetURL: test.Ptr("ftp://chat.example.com"),
+						Events:    []string{"incident.created"},
+					},
+				)
+
+				// Act
+				err := handlers.CreateWebhook(ctx)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(HaveField("Code", http.StatusBadRequest))
+			})
+		})
+
+		Context("with database error", func() {
+			It("should return 500 internal server error", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectExec(expectedWebhookInsert).WillReturnError(test.ErrTestError)
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.CreateWebhook(ctx)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrInternalServerError))
+			})
+		})
+	})
+
+	Describe("DeleteWebhook", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodDelete,
+				"/webhooks/"+webhookID,
+				nil,
+			)
+		})
+
+		Context("with existing webhook subscription", func() {
+			It("should return 204 no content", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedWebhookQuery).
+					WithArgs(webhookUUID, 1).
+					WillReturnRows(webhookRows.AddRow(
+						webhookUUID, targetURL, `["incident.created"]`, "secret", createdAt,
+					))
+				sqlMock.
+					ExpectExec(expectedWebhookDelete).
+					WithArgs(webhookUUID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				expectAuditEntry(sqlMock, db.AuditOperationDelete, db.AuditTargetWebhook, webhookID)
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.DeleteWebhook(ctx, webhookUUID)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("without existing webhook subscription", func() {
+			It("should return 404 not found", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedWebhookQuery).WithArgs(webhookUUID, 1).WillReturnRows(webhookRows)
+				sqlMock.ExpectRollback()
+
+				// Act
+				err := handlers.DeleteWebhook(ctx, webhookUUID)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrNotFound))
+			})
+		})
+	})
+
+	Describe("GetWebhookDeliveries", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodGet,
+				"/webhooks/"+webhookID+"/deliveries?status=dead",
+				nil,
+			)
+		})
+
+		Context("with dead deliveries", func() {
+			It("should return the deliveries with their event type", func() {
+				// Arrange
+				sqlMock.
+					ExpectQuery(expectedWebhookDeliveriesQuery).
+					WithArgs(webhookUUID, "dead", 1000).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "event_id", "subscription_id", "status", "attempts", "last_error"}).
+						AddRow(deliveryID, 1, webhookUUID, "dead", 8, "unexpected response status: 500"),
+					)
+				sqlMock.
+					ExpectQuery(expectedDeliveryEventsQuery).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "type"}).AddRow(1, db.EventIncidentCreated))
+
+				// Act
+				err := handlers.GetWebhookDeliveries(ctx, webhookUUID, api.GetWebhookDeliveriesParams{
+					Status: test.Ptr("dead"),
+				})
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusOK))
+				Ω(res.Body.String()).Should(ContainSubstring(`"eventType":"incident.created"`))
+				Ω(res.Body.String()).Should(ContainSubstring(`"lastError":"unexpected response status: 500"`))
+			})
+		})
+
+		Context("with unknown status", func() {
+			It("should return 400 bad request", func() {
+				// Act
+				err := handlers.GetWebhookDeliveries(ctx, webhookUUID, api.GetWebhookDeliveriesParams{
+					Status: test.Ptr("lost"),
+				})
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrBadRequest))
+			})
+		})
+	})
+
+	Describe("RetryWebhookDelivery", func() {
+		var (
+			ctx echo.Context
+			res *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			// setup context and response before every test
+			ctx, res = test.MustCreateEchoContextAndResponseWriter(
+				echoLogger,
+				http.MethodPost,
+				"/webhooks/"+webhookID+"/deliveries/7/retry",
+				nil,
+			)
+		})
+
+		Context("with dead delivery", func() {
+			It("should return 204 no content", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectExec(expectedWebhookDeliveryRetry).
+					WithArgs(0, sqlmock.AnyArg(), "pending", deliveryID, webhookUUID, "dead").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.RetryWebhookDelivery(ctx, webhookUUID, deliveryID)
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(res.Code).Should(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("without dead delivery", func() {
+			It("should return 404 not found", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectExec(expectedWebhookDeliveryRetry).
+					WithArgs(0, sqlmock.AnyArg(), "pending", deliveryID, webhookUUID, "dead").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectCommit()
+
+				// Act
+				err := handlers.RetryWebhookDelivery(ctx, webhookUUID, deliveryID)
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(err).Should(Equal(echo.ErrNotFound))
+			})
+		})
+	})
+}, test.DialectEntries())
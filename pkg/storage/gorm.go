@@ -0,0 +1,1486 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/events"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Gorm stores the resources in PostgreSQL or SQLite.
+// With PostgreSQL, events are published to the event streams of all instances on commit.
+// SQLite is served by a single instance, which polls for published events.
+type Gorm struct {
+	db *gorm.DB
+}
+
+// NewGorm creates a storage on the database connection.
+func NewGorm(conn *gorm.DB) *Gorm {
+	return &Gorm{
+		db: conn,
+	}
+}
+
+// DB returns the database connection of the storage, for features beyond the [Repository].
+func (g *Gorm) DB() *gorm.DB {
+	return g.db
+}
+
+// WithContext implements [Storage].
+func (g *Gorm) WithContext(ctx context.Context) Repository { //nolint:ireturn
+	return NewGorm(g.db.WithContext(ctx))
+}
+
+// Transaction implements [Storage].
+func (g *Gorm) Transaction(ctx context.Context, fn func(repo Repository) error) error {
+	return g.db.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error { //nolint:wrapcheck
+		return fn(NewGorm(dbTx))
+	})
+}
+
+// IncludeDeleted implements [Repository].
+func (g *Gorm) IncludeDeleted() Repository { //nolint:ireturn
+	return NewGorm(g.db.Unscoped().Session(&gorm.Session{})) //nolint:exhaustruct
+}
+
+// translateError wraps the errors of gorm with the errors of the storage.
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return fmt.Errorf("%w: %w", ErrDuplicate, err)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return fmt.Errorf("%w: %w", ErrReferenced, err)
+	default:
+		return err
+	}
+}
+
+// checkWritten returns [ErrConflict], if a versioned write affected no rows.
+func checkWritten(result *gorm.DB) error {
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrConflict
+	}
+
+	return nil
+}
+
+// keyset sorts a listing by a key and the ID to break ties, pages continue after the key and ID of their cursor.
+type keyset struct {
+	// key is the [clause.Column] or [clause.Expr] sorted by.
+	key interface{}
+	// unique keys need no ID to break ties.
+	unique bool
+	// descending sorts the highest key first.
+	descending bool
+}
+
+// apply sorts the query and restricts it to the page, parse reads the key of the cursor.
+func (k keyset) apply(query *gorm.DB, page Page, parse func(cursor Cursor) (interface{}, error)) (*gorm.DB, error) {
+	direction, operator := "ASC", ">"
+	if k.descending {
+		direction, operator = "DESC", "<"
+	}
+
+	order := "? " + direction
+	if !k.unique {
+		order += ", id " + direction
+	}
+
+	query = query.
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: order, Vars: []interface{}{k.key}}}). //nolint:exhaustruct
+		Limit(fetchLimit(page))
+
+	if page.After == nil {
+		return query, nil
+	}
+
+	key, err := parse(*page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	if k.unique {
+		return query.Where("? "+operator+" ?", k.key, key), nil
+	}
+
+	id, err := page.After.uuid()
+	if err != nil {
+		return nil, err
+	}
+
+	return query.Where("? "+operator+" ? OR (? = ? AND id "+operator+" ?)", k.key, key, k.key, key, id), nil
+}
+
+func stringKey(cursor Cursor) (interface{}, error) {
+	return cursor.Key, nil
+}
+
+func intKey(cursor Cursor) (interface{}, error) {
+	return cursor.intKey()
+}
+
+func timeKey(cursor Cursor) (interface{}, error) {
+	return cursor.timeKey()
+}
+
+// undeletedIncidents selects the IDs of the incidents not deleted, even if deleted ones are included.
+const undeletedIncidents = "SELECT id FROM incidents WHERE deleted_at IS NULL"
+
+// visibleIncidents selects the IDs of the incidents found by the repository.
+func (g *Gorm) visibleIncidents() *gorm.DB {
+	return g.db.Model(&DbDef.Incident{}).Select("id") //nolint:exhaustruct
+}
+
+// visibleComponents selects the IDs of the components found by the repository.
+func (g *Gorm) visibleComponents() *gorm.DB {
+	return g.db.Model(&DbDef.Component{}).Select("id") //nolint:exhaustruct
+}
+
+// checkComponents returns [ErrReferenced], if any of the components does not exist or is deleted.
+func (g *Gorm) checkComponents(componentIDs []DbDef.ID) error {
+	componentIDs = slices.Compact(slices.SortedFunc(slices.Values(componentIDs), compareIDs))
+	if len(componentIDs) == 0 {
+		return nil
+	}
+
+	var found int64
+
+	// Deleted components are never usable, even if deleted ones are included.
+	err := g.db.
+		Unscoped().
+		Model(&DbDef.Component{}). //nolint:exhaustruct
+		Where("id IN ?", componentIDs).
+		Where("deleted_at IS NULL").
+		Count(&found).
+		Error
+	if err != nil {
+		return fmt.Errorf("error checking components: %w", translateError(err))
+	}
+
+	if int(found) != len(componentIDs) {
+		return ErrReferenced
+	}
+
+	return nil
+}
+
+// setDeletion marks the incident or component as deleted by the actor at the time, or unmarks it with nil.
+// The deletion is versioned like an update.
+func (g *Gorm) setDeletion(model interface{}, id DbDef.ID, version *int, deletedAt *time.Time, deletedBy *string) error {
+	return checkWritten(g.db.
+		Unscoped().
+		Model(model).
+		Where("id = ?", id).
+		Where("version = ?", versionOf(version)).
+		Updates(map[string]interface{}{
+			"deleted_at": deletedAt,
+			"deleted_by": deletedBy,
+			"version":    nextVersion(version),
+		}))
+}
+
+// activeIncidentJoin joins the incidents of impacts, restricted to the currently active ones.
+// Impacts of deleted incidents are never active, so they are excluded explicitly
+// instead of leaving the join empty.
+func activeIncidentJoin(db *gorm.DB) *gorm.DB {
+	return db.
+		Joins("Incident").
+		Where("impacts.incident_id IN (" + undeletedIncidents + ")").
+		Where("ended_at IS NULL")
+}
+
+// validRevision restricts the incident revisions to the ones valid at the time.
+func validRevision(db *gorm.DB, at time.Time) *gorm.DB {
+	return db.Where(
+		"incident_revisions.revision = COALESCE("+
+			"(SELECT MAX(recorded.revision) FROM incident_revisions AS recorded "+
+			"WHERE recorded.incident_id = incident_revisions.incident_id AND recorded.recorded_at <= ?), "+
+			"(SELECT MIN(earliest.revision) FROM incident_revisions AS earliest "+
+			"WHERE earliest.incident_id = incident_revisions.incident_id))",
+		at,
+	)
+}
+
+// activeBetween restricts incidents to the ones active at any time between start and end.
+// Incidents without end are active from their beginning on.
+func activeBetween(db *gorm.DB, start, end time.Time) *gorm.DB {
+	return db.
+		Where(db.
+			Not(db.
+				Where("began_at < ?", start).
+				Where("ended_at < ?", start))).
+		Where(db.
+			Not(db.
+				Where("began_at > ?", end).
+				Where("ended_at > ?", end))).
+		Or(db.
+			Where("ended_at IS NULL").
+			Where("began_at <= ?", end))
+}
+
+// resolveImpactsAt sets the impacts of the components by the incident revisions valid and active at the time.
+func (g *Gorm) resolveImpactsAt(components []*DbDef.Component, at time.Time) error {
+	if len(components) == 0 {
+		return nil
+	}
+
+	componentIDs := make([]DbDef.ID, len(components))
+	for componentIndex, component := range components {
+		componentIDs[componentIndex] = component.ID
+	}
+
+	var impacts []*DbDef.IncidentRevisionImpact
+
+	err := validRevision(g.db.
+		Select("incident_revision_impacts.*").
+		Joins("JOIN incident_revisions "+
+			"ON incident_revisions.incident_id = incident_revision_impacts.incident_id "+
+			"AND incident_revisions.revision = incident_revision_impacts.revision"), at).
+		Where("incident_revisions.began_at < ?", at).
+		Where("incident_revisions.ended_at > ? OR incident_revisions.ended_at IS NULL", at).
+		Where("incident_revision_impacts.component_id IN ?", componentIDs).
+		Where("incident_revisions.incident_id IN (" + undeletedIncidents + ")").
+		Find(&impacts).
+		Error
+	if err != nil {
+		return fmt.Errorf("error loading impacts: %w", translateError(err))
+	}
+
+	for _, component := range components {
+		affectedBy := []DbDef.Impact{}
+
+		for _, impact := range impacts {
+			if valueOf(impact.ComponentID) == component.ID {
+				affectedBy = append(affectedBy, impact.ToImpact())
+			}
+		}
+
+		component.ActivelyAffectedBy = &affectedBy
+	}
+
+	return nil
+}
+
+// ListComponents implements [Repository].
+func (g *Gorm) ListComponents(
+	at *time.Time,
+	selector DbDef.Selector,
+	page Page,
+) ([]*DbDef.Component, *Cursor, error) {
+	var components []*DbDef.Component
+
+	query, err := keyset{
+		key:        clause.Expr{SQL: "COALESCE(display_name, '')"}, //nolint:exhaustruct
+		unique:     false,
+		descending: false,
+	}.apply(g.db, page, stringKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(selector) > 0 {
+		selectorCondition, err := DbDef.MatchesSelector(g.db, "components.labels", selector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error matching selector: %w", err)
+		}
+
+		query = query.Where(selectorCondition)
+	}
+
+	if at == nil {
+		query = query.Preload("ActivelyAffectedBy", activeIncidentJoin)
+	}
+
+	err = query.Find(&components).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading components: %w", translateError(err))
+	}
+
+	components, next := paginate(components, page, componentCursor)
+
+	if at != nil {
+		err = g.resolveImpactsAt(components, *at)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return components, next, nil
+}
+
+// GetComponent implements [Repository].
+func (g *Gorm) GetComponent(componentID DbDef.ID, at *time.Time) (*DbDef.Component, error) {
+	var component DbDef.Component
+
+	query := g.db
+	if at == nil {
+		query = query.Preload("ActivelyAffectedBy", activeIncidentJoin)
+	}
+
+	err := query.Where("id = ?", componentID).First(&component).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading component: %w", translateError(err))
+	}
+
+	if at != nil {
+		err = g.resolveImpactsAt([]*DbDef.Component{&component}, *at)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &component, nil
+}
+
+// CreateComponent implements [Repository].
+func (g *Gorm) CreateComponent(component *DbDef.Component) error {
+	err := g.db.Create(component).Error
+	if err != nil {
+		return fmt.Errorf("error creating component: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// UpdateComponent implements [Repository].
+func (g *Gorm) UpdateComponent(component *DbDef.Component) (*DbDef.Component, *DbDef.Component, error) {
+	var dbComponent, updatedComponent DbDef.Component
+
+	dbComponent.ID = component.ID
+
+	err := g.db.First(&dbComponent).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading component: %w", translateError(err))
+	}
+
+	component.Version = nextVersion(dbComponent.Version)
+
+	err = checkWritten(g.db.Where("version = ?", versionOf(dbComponent.Version)).Updates(component))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating component: %w", translateError(err))
+	}
+
+	updatedComponent.ID = component.ID
+
+	err = g.db.First(&updatedComponent).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading updated component: %w", translateError(err))
+	}
+
+	return &dbComponent, &updatedComponent, nil
+}
+
+// DeleteComponent implements [Repository].
+func (g *Gorm) DeleteComponent(componentID DbDef.ID, deletedBy string) (*DbDef.Component, error) {
+	var (
+		dbComponent DbDef.Component
+		impacts     int64
+	)
+
+	err := g.db.Where("id = ?", componentID).First(&dbComponent).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading component: %w", translateError(err))
+	}
+
+	err = g.db.
+		Model(&DbDef.Impact{}). //nolint:exhaustruct
+		Where("component_id = ?", componentID).
+		Where("incident_id IN (" + undeletedIncidents + ")").
+		Count(&impacts).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading impacts: %w", translateError(err))
+	}
+
+	if impacts > 0 {
+		return nil, fmt.Errorf("error deleting component: %w", ErrReferenced)
+	}
+
+	now := time.Now()
+
+	err = g.setDeletion(&DbDef.Component{}, componentID, dbComponent.Version, &now, &deletedBy) //nolint:exhaustruct
+	if err != nil {
+		return nil, fmt.Errorf("error deleting component: %w", err)
+	}
+
+	return &dbComponent, nil
+}
+
+// RestoreComponent implements [Repository].
+func (g *Gorm) RestoreComponent(componentID DbDef.ID) (*DbDef.Component, error) {
+	var dbComponent DbDef.Component
+
+	err := g.db.Unscoped().Where("id = ?", componentID).Where("deleted_at IS NOT NULL").First(&dbComponent).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading deleted component: %w", translateError(err))
+	}
+
+	err = g.setDeletion(&DbDef.Component{}, componentID, dbComponent.Version, nil, nil) //nolint:exhaustruct
+	if err != nil {
+		return nil, fmt.Errorf("error restoring component: %w", err)
+	}
+
+	return g.GetComponent(componentID, nil)
+}
+
+// ListComponentImpacts implements [Repository].
+func (g *Gorm) ListComponentImpacts(componentID DbDef.ID, start, end time.Time) ([]*DbDef.Impact, error) {
+	var impacts []*DbDef.Impact
+
+	err := g.db.
+		Joins("Incident").
+		Where("impacts.component_id = ?", componentID).
+		Where("impacts.incident_id IN ("+undeletedIncidents+")").
+		Where("began_at < ?", end).
+		Where(g.db.Where("ended_at > ?", start).Or("ended_at IS NULL")).
+		Order("began_at").
+		Find(&impacts).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading impacts: %w", translateError(err))
+	}
+
+	return impacts, nil
+}
+
+// dependencyLock is the key of the transaction level advisory lock held while changing dependencies.
+const dependencyLock = 0x5354415455530002
+
+// LockComponentDependencies implements [Repository].
+func (g *Gorm) LockComponentDependencies() error {
+	// SQLite serializes write transactions itself.
+	if g.db.Dialector.Name() == DbDef.DialectSQLite {
+		return nil
+	}
+
+	err := g.db.Exec("SELECT pg_advisory_xact_lock(?)", dependencyLock).Error
+	if err != nil {
+		return fmt.Errorf("error locking dependencies: %w", err)
+	}
+
+	return nil
+}
+
+// ListComponentDependencies implements [Repository].
+func (g *Gorm) ListComponentDependencies() ([]*DbDef.ComponentDependency, error) {
+	var dependencies []*DbDef.ComponentDependency
+
+	err := g.db.
+		Where("component_id IN (?)", g.visibleComponents()).
+		Where("depends_on_id IN (?)", g.visibleComponents()).
+		Order("component_id").
+		Order("depends_on_id").
+		Find(&dependencies).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading dependencies: %w", translateError(err))
+	}
+
+	return dependencies, nil
+}
+
+// CreateComponentDependency implements [Repository].
+func (g *Gorm) CreateComponentDependency(dependency *DbDef.ComponentDependency) error {
+	err := g.checkComponents([]DbDef.ID{valueOf(dependency.ComponentID), valueOf(dependency.DependsOnID)})
+	if err != nil {
+		return err
+	}
+
+	err = g.db.Omit("Component", "DependsOn").Create(dependency).Error
+	if err != nil {
+		return fmt.Errorf("error creating dependency: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// DeleteComponentDependency implements [Repository].
+func (g *Gorm) DeleteComponentDependency(componentID, dependsOnID DbDef.ID) (*DbDef.ComponentDependency, error) {
+	var dependency DbDef.ComponentDependency
+
+	err := g.db.
+		Where("component_id = ? AND depends_on_id = ?", componentID, dependsOnID).
+		First(&dependency).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading dependency: %w", translateError(err))
+	}
+
+	err = g.db.
+		Where("component_id = ? AND depends_on_id = ?", componentID, dependsOnID).
+		Delete(&DbDef.ComponentDependency{}). //nolint:exhaustruct
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error deleting dependency: %w", translateError(err))
+	}
+
+	return &dependency, nil
+}
+
+// orderedMembers loads the members of groups, which are found by the repository, ordered by component.
+func (g *Gorm) orderedMembers(db *gorm.DB) *gorm.DB {
+	return db.Where("component_id IN (?)", g.visibleComponents()).Order("component_id")
+}
+
+// ListComponentGroups implements [Repository].
+func (g *Gorm) ListComponentGroups() ([]*DbDef.ComponentGroup, error) {
+	var groups []*DbDef.ComponentGroup
+
+	err := g.db.
+		Preload("Members", g.orderedMembers).
+		Order(clause.OrderByColumn{Column: DbDef.OrderColumn()}). //nolint:exhaustruct
+		Order("COALESCE(display_name, '')").
+		Order("id").
+		Find(&groups).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading component groups: %w", translateError(err))
+	}
+
+	return groups, nil
+}
+
+// GetComponentGroup implements [Repository].
+func (g *Gorm) GetComponentGroup(groupID DbDef.ID) (*DbDef.ComponentGroup, error) {
+	var group DbDef.ComponentGroup
+
+	err := g.db.Preload("Members", g.orderedMembers).Where("id = ?", groupID).First(&group).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading component group: %w", translateError(err))
+	}
+
+	return &group, nil
+}
+
+// CreateComponentGroup implements [Repository].
+func (g *Gorm) CreateComponentGroup(group *DbDef.ComponentGroup) error {
+	err := g.checkComponents(group.ComponentIDs())
+	if err != nil {
+		return err
+	}
+
+	err = g.db.Omit("Members.Group", "Members.Component").Create(group).Error
+	if err != nil {
+		return fmt.Errorf("error creating component group: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// UpdateComponentGroup implements [Repository].
+func (g *Gorm) UpdateComponentGroup(
+	group *DbDef.ComponentGroup,
+) (*DbDef.ComponentGroup, *DbDef.ComponentGroup, error) {
+	dbGroup, err := g.GetComponentGroup(group.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = g.db.
+		Model(&DbDef.ComponentGroup{Model: DbDef.Model{ID: group.ID}}). //nolint:exhaustruct
+		Select("DisplayName", "Description", "ParentID", "Order").
+		Updates(group).
+		Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating component group: %w", translateError(err))
+	}
+
+	updatedGroup, err := g.GetComponentGroup(group.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dbGroup, updatedGroup, nil
+}
+
+// DeleteComponentGroup implements [Repository].
+func (g *Gorm) DeleteComponentGroup(groupID DbDef.ID) (*DbDef.ComponentGroup, error) {
+	dbGroup, err := g.GetComponentGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.db.Where("id = ?", groupID).Delete(&DbDef.ComponentGroup{}).Error //nolint:exhaustruct
+	if err != nil {
+		return nil, fmt.Errorf("error deleting component group: %w", translateError(err))
+	}
+
+	return dbGroup, nil
+}
+
+// AddComponentGroupMember implements [Repository].
+func (g *Gorm) AddComponentGroupMember(member *DbDef.ComponentGroupMember) error {
+	err := g.checkComponents([]DbDef.ID{valueOf(member.ComponentID)})
+	if err != nil {
+		return err
+	}
+
+	err = g.db.Omit("Group", "Component").Create(member).Error
+	if err != nil {
+		return fmt.Errorf("error creating component group member: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// RemoveComponentGroupMember implements [Repository].
+func (g *Gorm) RemoveComponentGroupMember(groupID, componentID DbDef.ID) (*DbDef.ComponentGroupMember, error) {
+	var member DbDef.ComponentGroupMember
+
+	err := g.db.Where("group_id = ? AND component_id = ?", groupID, componentID).First(&member).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading component group member: %w", translateError(err))
+	}
+
+	err = g.db.
+		Where("group_id = ? AND component_id = ?", groupID, componentID).
+		Delete(&DbDef.ComponentGroupMember{}). //nolint:exhaustruct
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error deleting component group member: %w", translateError(err))
+	}
+
+	return &member, nil
+}
+
+// ListIncidents implements [Repository].
+func (g *Gorm) ListIncidents(
+	start, end time.Time,
+	at *time.Time,
+	selector DbDef.Selector,
+	page Page,
+) ([]*DbDef.Incident, *Cursor, error) {
+	if at != nil {
+		return g.listIncidentsAt(start, end, *at, selector, page)
+	}
+
+	var incidents []*DbDef.Incident
+
+	query, err := keyset{
+		key:        clause.Column{Name: "began_at"}, //nolint:exhaustruct
+		unique:     false,
+		descending: true,
+	}.apply(g.db, page, timeKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(selector) > 0 {
+		selectorCondition, err := DbDef.MatchesSelector(g.db, "components.labels", selector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error matching selector: %w", err)
+		}
+
+		query = query.Where(
+			"id IN (SELECT impacts.incident_id FROM impacts JOIN components ON components.id = impacts.component_id "+
+				"WHERE ?)",
+			selectorCondition,
+		)
+	}
+
+	err = query.
+		Preload("Affects.Component").
+		Preload(clause.Associations).
+		Where(activeBetween(g.db, start, end)).
+		Find(&incidents).
+		Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading incidents: %w", translateError(err))
+	}
+
+	incidents, next := paginate(incidents, page, incidentCursor)
+
+	return incidents, next, nil
+}
+
+// listIncidentsAt lists the incidents like [Gorm.ListIncidents], evaluated by their revisions valid at the time.
+// The revisions are selected as incidents with an id, so they are sorted and paged like incidents.
+func (g *Gorm) listIncidentsAt(
+	start, end, at time.Time,
+	selector DbDef.Selector,
+	page Page,
+) ([]*DbDef.Incident, *Cursor, error) {
+	var revisions []*DbDef.IncidentRevision
+
+	validRevisions := validRevision(g.db.
+		Model(&DbDef.IncidentRevision{}). //nolint:exhaustruct
+		Select("incident_revisions.*, incident_revisions.incident_id AS id"), at)
+
+	query, err := keyset{
+		key:        clause.Column{Name: "began_at"}, //nolint:exhaustruct
+		unique:     false,
+		descending: true,
+	}.apply(g.db.Table("(?) AS incident_revisions", validRevisions), page, timeKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(selector) > 0 {
+		selectorCondition, err := DbDef.MatchesSelector(g.db, "components.labels", selector)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error matching selector: %w", err)
+		}
+
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM incident_revision_impacts "+
+				"JOIN components ON components.id = incident_revision_impacts.component_id "+
+				"WHERE incident_revision_impacts.incident_id = incident_revisions.incident_id "+
+				"AND incident_revision_impacts.revision = incident_revisions.revision AND ?)",
+			selectorCondition,
+		)
+	}
+
+	err = query.
+		Preload("Affects").
+		Where("incident_revisions.incident_id IN (?)", g.visibleIncidents()).
+		Where(activeBetween(g.db, start, end)).
+		Find(&revisions).
+		Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading incident revisions: %w", translateError(err))
+	}
+
+	incidents := make([]*DbDef.Incident, len(revisions))
+	for revisionIndex, revision := range revisions {
+		incidents[revisionIndex] = revision.ToIncident()
+	}
+
+	incidents, next := paginate(incidents, page, incidentCursor)
+
+	err = g.resolveUpdates(incidents)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return incidents, next, nil
+}
+
+// resolveUpdates sets the current updates of the incidents, as updates are not revised.
+func (g *Gorm) resolveUpdates(incidents []*DbDef.Incident) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+
+	incidentIDs := make([]DbDef.ID, len(incidents))
+	for incidentIndex, incident := range incidents {
+		incidentIDs[incidentIndex] = incident.ID
+	}
+
+	var incidentUpdates []*DbDef.IncidentUpdate
+
+	err := g.db.
+		Where("incident_id IN ?", incidentIDs).
+		Order(clause.OrderByColumn{Column: DbDef.OrderColumn()}). //nolint:exhaustruct
+		Find(&incidentUpdates).
+		Error
+	if err != nil {
+		return fmt.Errorf("error loading incident updates: %w", translateError(err))
+	}
+
+	for _, incident := range incidents {
+		updates := []DbDef.IncidentUpdate{}
+
+		for _, incidentUpdate := range incidentUpdates {
+			if valueOf(incidentUpdate.IncidentID) == incident.ID {
+				updates = append(updates, *incidentUpdate)
+			}
+		}
+
+		incident.Updates = &updates
+	}
+
+	return nil
+}
+
+// GetIncident implements [Repository].
+func (g *Gorm) GetIncident(incidentID DbDef.ID) (*DbDef.Incident, error) {
+	var incident DbDef.Incident
+
+	err := g.db.
+		Preload("Affects.Component").
+		Preload(clause.Associations).
+		Where("id = ?", incidentID).
+		First(&incident).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading incident: %w", translateError(err))
+	}
+
+	return &incident, nil
+}
+
+// CreateIncident implements [Repository].
+func (g *Gorm) CreateIncident(incident *DbDef.Incident) error {
+	referencePhase(incident)
+
+	err := g.checkComponents(affectedComponents(incident.Affects))
+	if err != nil {
+		return err
+	}
+
+	err = g.db.Omit("Phase").Create(incident).Error
+	if err != nil {
+		return fmt.Errorf("error creating incident: %w", translateError(err))
+	}
+
+	err = DbDef.RecordIncidentRevision(g.db, incident, time.Now())
+	if err != nil {
+		return fmt.Errorf("error recording incident revision: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// affectedComponents returns the IDs of the components of the impacts.
+func affectedComponents(affects *[]DbDef.Impact) []DbDef.ID {
+	if affects == nil {
+		return nil
+	}
+
+	componentIDs := make([]DbDef.ID, 0, len(*affects))
+	for _, impact := range *affects {
+		componentIDs = append(componentIDs, valueOf(impact.ComponentID))
+	}
+
+	return componentIDs
+}
+
+// referencePhase sets the reference to the phase of the incident.
+// Phases are only referenced, saving the association would insert them without name.
+func referencePhase(incident *DbDef.Incident) {
+	if incident.Phase == nil {
+		return
+	}
+
+	incident.PhaseGeneration = incident.Phase.Generation
+	incident.PhaseOrder = incident.Phase.Order
+}
+
+func prepareAffects(oldAffects, newAffects *[]DbDef.Impact, incidentID uuid.UUID, dbTx *gorm.DB) error {
+	// Check if any impacts need deletion.
+	if oldAffects == nil || len(*oldAffects) == 0 {
+		return nil
+	}
+
+	// Check if impacts are modified at all.
+	if newAffects == nil {
+		return nil
+	}
+
+	// Check if any impacts are left.
+	if len(*newAffects) == 0 {
+		// Delete all impacts of this incident.
+		err := dbTx.Delete(oldAffects).Where("incident_id = ?", incidentID).Error
+		if err != nil {
+			return fmt.Errorf("error deleting all incident impacts: %w", err)
+		}
+
+		return nil
+	}
+
+	// Collect all impacts that are expected and give them the incident id.
+	newImpacts := make([]DbDef.Impact, len(*newAffects))
+
+	for incidentImpactIndex := range *newAffects {
+		newImpacts[incidentImpactIndex].IncidentID = &incidentID
+	}
+
+	var impactsToBeDeleted []DbDef.Impact
+
+	for _, oldImpact := range *oldAffects {
+		if !slices.ContainsFunc(newImpacts, func(newImpact DbDef.Impact) bool {
+			return newImpact.ComponentID == oldImpact.ComponentID &&
+				newImpact.ImpactTypeID == oldImpact.ImpactTypeID &&
+				newImpact.IncidentID == oldImpact.IncidentID
+		}) {
+			impactsToBeDeleted = append(impactsToBeDeleted, oldImpact)
+		}
+	}
+
+	err := dbTx.Delete(&impactsToBeDeleted).Error
+	if err != nil {
+		return fmt.Errorf("error deleting not needed impacts: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateIncident implements [Repository].
+func (g *Gorm) UpdateIncident(incident *DbDef.Incident) (*DbDef.Incident, *DbDef.Incident, error) {
+	var dbIncident, updatedIncident DbDef.Incident
+
+	dbIncident.ID = incident.ID
+
+	err := g.db.Preload("Affects").First(&dbIncident).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading incident: %w", translateError(err))
+	}
+
+	err = g.checkComponents(affectedComponents(incident.Affects))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = prepareAffects(dbIncident.Affects, incident.Affects, incident.ID, g.db)
+	if err != nil {
+		return nil, nil, translateError(err)
+	}
+
+	referencePhase(incident)
+
+	incident.Version = nextVersion(dbIncident.Version)
+
+	err = checkWritten(g.db.Omit("Phase").Where("version = ?", versionOf(dbIncident.Version)).Updates(incident))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating incident: %w", translateError(err))
+	}
+
+	updatedIncident.ID = incident.ID
+
+	err = g.db.Preload("Affects").First(&updatedIncident).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading updated incident: %w", translateError(err))
+	}
+
+	err = DbDef.RecordIncidentRevision(g.db, &updatedIncident, time.Now())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error recording incident revision: %w", translateError(err))
+	}
+
+	return &dbIncident, &updatedIncident, nil
+}
+
+// DeleteIncident implements [Repository].
+func (g *Gorm) DeleteIncident(incidentID DbDef.ID, deletedBy string) (*DbDef.Incident, error) {
+	var dbIncident DbDef.Incident
+
+	err := g.db.Preload("Affects").Where("id = ?", incidentID).First(&dbIncident).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading incident: %w", translateError(err))
+	}
+
+	now := time.Now()
+
+	err = g.setDeletion(&DbDef.Incident{}, incidentID, dbIncident.Version, &now, &deletedBy) //nolint:exhaustruct
+	if err != nil {
+		return nil, fmt.Errorf("error deleting incident: %w", err)
+	}
+
+	// Alerts firing again open a new incident, which requires the active link of the fingerprint to be resolved.
+	err = g.db.
+		Model(&DbDef.AlertIncident{}). //nolint:exhaustruct
+		Where("incident_id = ?", incidentID).
+		Where("resolved_at IS NULL").
+		Update("resolved_at", now).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error resolving alerts of incident: %w", translateError(err))
+	}
+
+	return &dbIncident, nil
+}
+
+// RestoreIncident implements [Repository].
+func (g *Gorm) RestoreIncident(incidentID DbDef.ID) (*DbDef.Incident, error) {
+	var dbIncident DbDef.Incident
+
+	err := g.db.
+		Unscoped().
+		Preload("Affects").
+		Where("id = ?", incidentID).
+		Where("deleted_at IS NOT NULL").
+		First(&dbIncident).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading deleted incident: %w", translateError(err))
+	}
+
+	err = g.checkComponents(affectedComponents(dbIncident.Affects))
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.setDeletion(&DbDef.Incident{}, incidentID, dbIncident.Version, nil, nil) //nolint:exhaustruct
+	if err != nil {
+		return nil, fmt.Errorf("error restoring incident: %w", err)
+	}
+
+	return g.GetIncident(incidentID)
+}
+
+// ListIncidentRevisions implements [Repository].
+func (g *Gorm) ListIncidentRevisions(incidentID DbDef.ID) ([]*DbDef.IncidentRevision, error) {
+	var revisions []*DbDef.IncidentRevision
+
+	err := g.db.
+		Preload("Affects").
+		Where("incident_id = ?", incidentID).
+		Where("incident_id IN (?)", g.visibleIncidents()).
+		Order("revision").
+		Find(&revisions).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading incident revisions: %w", translateError(err))
+	}
+
+	// Every incident has a revision, recorded with its creation.
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("error loading incident revisions: %w", ErrNotFound)
+	}
+
+	return revisions, nil
+}
+
+// ListIncidentUpdates implements [Repository].
+func (g *Gorm) ListIncidentUpdates(incidentID DbDef.ID, page Page) ([]*DbDef.IncidentUpdate, *Cursor, error) {
+	var incidentUpdates []*DbDef.IncidentUpdate
+
+	query, err := keyset{key: DbDef.OrderColumn(), unique: true, descending: false}.apply(g.db, page, intKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = query.
+		Where("incident_id = ?", incidentID).
+		Where("incident_id IN (?)", g.visibleIncidents()).
+		Find(&incidentUpdates).
+		Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading incident updates: %w", translateError(err))
+	}
+
+	incidentUpdates, next := paginate(incidentUpdates, page, incidentUpdateCursor)
+
+	return incidentUpdates, next, nil
+}
+
+// GetIncidentUpdate implements [Repository].
+func (g *Gorm) GetIncidentUpdate(incidentID DbDef.ID, order int) (*DbDef.IncidentUpdate, error) {
+	var incidentUpdate DbDef.IncidentUpdate
+
+	err := g.db.
+		Where("incident_id = ?", incidentID).
+		Where("incident_id IN (?)", g.visibleIncidents()).
+		Where("? = ?", DbDef.OrderColumn(), order).
+		First(&incidentUpdate).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading incident update: %w", translateError(err))
+	}
+
+	return &incidentUpdate, nil
+}
+
+// HighestIncidentUpdateOrder implements [Repository].
+func (g *Gorm) HighestIncidentUpdateOrder(incidentID DbDef.ID) (int, error) {
+	order, err := DbDef.GetHighestIncidentUpdateOrder(g.db, incidentID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting highest order of incident updates: %w", translateError(err))
+	}
+
+	return order, nil
+}
+
+// CreateIncidentUpdate implements [Repository].
+func (g *Gorm) CreateIncidentUpdate(incidentUpdate *DbDef.IncidentUpdate) error {
+	var incidents int64
+
+	err := g.visibleIncidents().Where("id = ?", incidentUpdate.IncidentID).Count(&incidents).Error
+	if err != nil {
+		return fmt.Errorf("error loading incident: %w", translateError(err))
+	}
+
+	if incidents == 0 {
+		return fmt.Errorf("error creating incident update: %w", ErrReferenced)
+	}
+
+	err = g.db.Create(incidentUpdate).Error
+	if err != nil {
+		return fmt.Errorf("error creating incident update: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// UpdateIncidentUpdate implements [Repository].
+func (g *Gorm) UpdateIncidentUpdate(
+	incidentUpdate *DbDef.IncidentUpdate,
+) (*DbDef.IncidentUpdate, *DbDef.IncidentUpdate, error) {
+	var dbIncidentUpdate, updatedIncidentUpdate DbDef.IncidentUpdate
+
+	dbIncidentUpdate.IncidentID = incidentUpdate.IncidentID
+	dbIncidentUpdate.Order = incidentUpdate.Order
+
+	err := g.db.Where("incident_id IN (?)", g.visibleIncidents()).First(&dbIncidentUpdate).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading incident update: %w", translateError(err))
+	}
+
+	incidentUpdate.Version = nextVersion(dbIncidentUpdate.Version)
+
+	err = checkWritten(g.db.Where("version = ?", versionOf(dbIncidentUpdate.Version)).Updates(incidentUpdate))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating incident update: %w", translateError(err))
+	}
+
+	updatedIncidentUpdate.IncidentID = incidentUpdate.IncidentID
+	updatedIncidentUpdate.Order = incidentUpdate.Order
+
+	err = g.db.First(&updatedIncidentUpdate).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading updated incident update: %w", translateError(err))
+	}
+
+	return &dbIncidentUpdate, &updatedIncidentUpdate, nil
+}
+
+// DeleteIncidentUpdate implements [Repository].
+func (g *Gorm) DeleteIncidentUpdate(incidentID DbDef.ID, order int) (*DbDef.IncidentUpdate, error) {
+	dbIncidentUpdate, err := g.GetIncidentUpdate(incidentID, order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkWritten(g.db.
+		Where("incident_id = ?", incidentID).
+		Where("? = ?", DbDef.OrderColumn(), order).
+		Where("version = ?", versionOf(dbIncidentUpdate.Version)).
+		Delete(&DbDef.IncidentUpdate{})) //nolint: exhaustruct
+	if err != nil {
+		return nil, fmt.Errorf("error deleting incident update: %w", translateError(err))
+	}
+
+	return dbIncidentUpdate, nil
+}
+
+// PurgeDeleted implements [Repository].
+func (g *Gorm) PurgeDeleted(deletedBefore time.Time) (int, error) {
+	// impacts, updates and revisions are removed with their incidents,
+	// dependencies and group memberships with their components by the database.
+	incidents := g.db.Unscoped().Where("deleted_at < ?", deletedBefore).Delete(&DbDef.Incident{}) //nolint:exhaustruct
+	if incidents.Error != nil {
+		return 0, fmt.Errorf("error purging incidents: %w", translateError(incidents.Error))
+	}
+
+	components := g.db.
+		Unscoped().
+		Where("deleted_at < ?", deletedBefore).
+		Where("id NOT IN (SELECT component_id FROM impacts)").
+		Delete(&DbDef.Component{}) //nolint:exhaustruct
+	if components.Error != nil {
+		return 0, fmt.Errorf("error purging components: %w", translateError(components.Error))
+	}
+
+	return int(incidents.RowsAffected + components.RowsAffected), nil
+}
+
+// PurgeEvents implements [Repository].
+func (g *Gorm) PurgeEvents(publishedBefore time.Time) (int, error) {
+	// Deliveries are removed with their events by the database.
+	res := g.db.
+		Where("created_at < ?", publishedBefore).
+		Where(
+			"id NOT IN (SELECT event_id FROM webhook_deliveries WHERE status IN ?)",
+			[]DbDef.WebhookDeliveryStatus{DbDef.WebhookDeliveryPending, DbDef.WebhookDeliveryDead},
+		).
+		Delete(&DbDef.Event{}) //nolint:exhaustruct
+	if res.Error != nil {
+		return 0, fmt.Errorf("error purging events: %w", translateError(res.Error))
+	}
+
+	return int(res.RowsAffected), nil
+}
+
+// ListImpactTypes implements [Repository].
+func (g *Gorm) ListImpactTypes(page Page) ([]*DbDef.ImpactType, *Cursor, error) {
+	var impactTypes []*DbDef.ImpactType
+
+	query, err := keyset{
+		key:        clause.Column{Name: "display_name"}, //nolint:exhaustruct
+		unique:     false,
+		descending: false,
+	}.apply(g.db, page, stringKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = query.Find(&impactTypes).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading impact types: %w", translateError(err))
+	}
+
+	impactTypes, next := paginate(impactTypes, page, impactTypeCursor)
+
+	return impactTypes, next, nil
+}
+
+// GetImpactType implements [Repository].
+func (g *Gorm) GetImpactType(impactTypeID DbDef.ID) (*DbDef.ImpactType, error) {
+	var impactType DbDef.ImpactType
+
+	err := g.db.Where("id = ?", impactTypeID).First(&impactType).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading impact type: %w", translateError(err))
+	}
+
+	return &impactType, nil
+}
+
+// CreateImpactType implements [Repository].
+func (g *Gorm) CreateImpactType(impactType *DbDef.ImpactType) error {
+	err := g.db.Create(impactType).Error
+	if err != nil {
+		return fmt.Errorf("error creating impact type: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// UpdateImpactType implements [Repository].
+func (g *Gorm) UpdateImpactType(impactType *DbDef.ImpactType) (*DbDef.ImpactType, *DbDef.ImpactType, error) {
+	var dbImpactType, updatedImpactType DbDef.ImpactType
+
+	dbImpactType.ID = impactType.ID
+
+	err := g.db.First(&dbImpactType).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading impact type: %w", translateError(err))
+	}
+
+	impactType.Version = nextVersion(dbImpactType.Version)
+
+	err = checkWritten(g.db.Where("version = ?", versionOf(dbImpactType.Version)).Updates(impactType))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating impact type: %w", translateError(err))
+	}
+
+	updatedImpactType.ID = impactType.ID
+
+	err = g.db.First(&updatedImpactType).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading updated impact type: %w", translateError(err))
+	}
+
+	return &dbImpactType, &updatedImpactType, nil
+}
+
+// DeleteImpactType implements [Repository].
+func (g *Gorm) DeleteImpactType(impactTypeID DbDef.ID) (*DbDef.ImpactType, error) {
+	dbImpactType, err := g.GetImpactType(impactTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkWritten(g.db.
+		Where("id = ?", impactTypeID).
+		Where("version = ?", versionOf(dbImpactType.Version)).
+		Delete(&DbDef.ImpactType{})) //nolint: exhaustruct
+	if err != nil {
+		return nil, fmt.Errorf("error deleting impact type: %w", translateError(err))
+	}
+
+	return dbImpactType, nil
+}
+
+// ListSeverities implements [Repository].
+func (g *Gorm) ListSeverities(page Page) ([]*DbDef.Severity, *Cursor, error) {
+	var severities []*DbDef.Severity
+
+	query, err := keyset{
+		key:        clause.Column{Name: "value"}, //nolint:exhaustruct
+		unique:     true,
+		descending: false,
+	}.apply(g.db, page, intKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = query.Find(&severities).Error
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading severities: %w", translateError(err))
+	}
+
+	severities, next := paginate(severities, page, severityCursor)
+
+	return severities, next, nil
+}
+
+// GetSeverity implements [Repository].
+func (g *Gorm) GetSeverity(name string) (*DbDef.Severity, error) {
+	var severity DbDef.Severity
+
+	err := g.db.Where("display_name = ?", name).First(&severity).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading severity: %w", translateError(err))
+	}
+
+	return &severity, nil
+}
+
+// CreateSeverity implements [Repository].
+func (g *Gorm) CreateSeverity(severity *DbDef.Severity) error {
+	err := g.db.Create(severity).Error
+	if err != nil {
+		return fmt.Errorf("error creating severity: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// UpdateSeverity implements [Repository].
+func (g *Gorm) UpdateSeverity(name string, severity *DbDef.Severity) (*DbDef.Severity, *DbDef.Severity, error) {
+	dbSeverity, err := g.GetSeverity(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	severity.Version = nextVersion(dbSeverity.Version)
+
+	err = checkWritten(g.db.
+		Where("display_name = ?", name).
+		Where("version = ?", versionOf(dbSeverity.Version)).
+		Updates(severity))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error updating severity: %w", translateError(err))
+	}
+
+	// The display name identifies the severity and may have been changed.
+	updatedName := name
+	if severity.DisplayName != nil {
+		updatedName = *severity.DisplayName
+	}
+
+	updatedSeverity, err := g.GetSeverity(updatedName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dbSeverity, updatedSeverity, nil
+}
+
+// DeleteSeverity implements [Repository].
+func (g *Gorm) DeleteSeverity(name string) (*DbDef.Severity, error) {
+	dbSeverity, err := g.GetSeverity(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkWritten(g.db.
+		Where("display_name = ?", name).
+		Where("version = ?", versionOf(dbSeverity.Version)).
+		Delete(&DbDef.Severity{})) //nolint: exhaustruct
+	if err != nil {
+		return nil, fmt.Errorf("error deleting severity: %w", translateError(err))
+	}
+
+	return dbSeverity, nil
+}
+
+// CurrentPhaseGeneration implements [Repository].
+func (g *Gorm) CurrentPhaseGeneration() (int, error) {
+	generation, err := DbDef.GetCurrentPhaseGeneration(g.db)
+	if err != nil {
+		return 0, fmt.Errorf("error getting current phase generation: %w", translateError(err))
+	}
+
+	return generation, nil
+}
+
+// GetPhase implements [Repository].
+func (g *Gorm) GetPhase(generation, order int) (*DbDef.Phase, error) {
+	var phase DbDef.Phase
+
+	phase.Generation = &generation
+	phase.Order = &order
+
+	err := g.db.First(&phase).Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading phase: %w", translateError(err))
+	}
+
+	return &phase, nil
+}
+
+// ListPhases implements [Repository].
+func (g *Gorm) ListPhases(generation int) ([]*DbDef.Phase, error) {
+	var phases []*DbDef.Phase
+
+	err := g.db.
+		Where("generation = ?", generation).
+		Order(clause.OrderByColumn{Column: DbDef.OrderColumn()}). //nolint:exhaustruct
+		Find(&phases).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("error loading phases: %w", translateError(err))
+	}
+
+	return phases, nil
+}
+
+// CreatePhases implements [Repository].
+func (g *Gorm) CreatePhases(phases []DbDef.Phase) error {
+	err := g.db.Create(phases).Error
+	if err != nil {
+		return fmt.Errorf("error creating phases: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// RecordAudit implements [Repository].
+func (g *Gorm) RecordAudit(auditEntry *DbDef.AuditEntry) error {
+	err := g.db.Create(auditEntry).Error
+	if err != nil {
+		return fmt.Errorf("error recording audit entry: %w", translateError(err))
+	}
+
+	return nil
+}
+
+// PublishEvent implements [Repository].
+// It writes the event to the outbox and notifies the event streams of all instances.
+func (g *Gorm) PublishEvent(event *DbDef.Event) error {
+	// SQLite serializes write transactions itself and has no notifications.
+	if g.db.Dialector.Name() == DbDef.DialectSQLite {
+		err := g.db.Create(event).Error
+		if err != nil {
+			return fmt.Errorf("error publishing event: %w", translateError(err))
+		}
+
+		return nil
+	}
+
+	// Held until the end of the transaction, so event IDs are committed in order.
+	// This serializes all transactions publishing events after their first one, which is why handlers publish last.
+	err := g.db.Exec("SELECT pg_advisory_xact_lock(?)", events.SequenceLock).Error
+	if err != nil {
+		return fmt.Errorf("error locking event sequence: %w", err)
+	}
+
+	err = g.db.Create(event).Error
+	if err != nil {
+		return fmt.Errorf("error publishing event: %w", translateError(err))
+	}
+
+	// Notifications are delivered on commit.
+	err = g.db.Exec("SELECT pg_notify(?, ?)", events.Channel, strconv.FormatUint(event.ID, 10)).Error
+	if err != nil {
+		return fmt.Errorf("error notifying event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,1975 @@
+package storage
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// phaseKey identifies a [DbDef.Phase].
+type phaseKey struct {
+	generation int
+	order      int
+}
+
+// incidentUpdateKey identifies a [DbDef.IncidentUpdate].
+type incidentUpdateKey struct {
+	incidentID DbDef.ID
+	order      int
+}
+
+// incidentRevisionKey identifies a [DbDef.IncidentRevision].
+type incidentRevisionKey struct {
+	incidentID DbDef.ID
+	revision   int
+}
+
+// dependencyKey identifies a [DbDef.ComponentDependency].
+type dependencyKey struct {
+	componentID DbDef.ID
+	dependsOnID DbDef.ID
+}
+
+// groupMemberKey identifies a [DbDef.ComponentGroupMember].
+type groupMemberKey struct {
+	groupID     DbDef.ID
+	componentID DbDef.ID
+}
+
+// memoryData holds all resources of a [Memory] storage.
+// Stored values are replaced and never changed in place, so a shallow copy of the maps is a snapshot.
+// Associations are not stored but resolved, when reading.
+type memoryData struct {
+	components        map[DbDef.ID]DbDef.Component
+	incidents         map[DbDef.ID]DbDef.Incident
+	impacts           map[DbDef.ID][]DbDef.Impact
+	incidentRevisions map[incidentRevisionKey]DbDef.IncidentRevision
+	dependencies      map[dependencyKey]DbDef.ComponentDependency
+	groups            map[DbDef.ID]DbDef.ComponentGroup
+	groupMembers      map[groupMemberKey]DbDef.ComponentGroupMember
+	incidentUpdates   map[incidentUpdateKey]DbDef.IncidentUpdate
+	impactTypes       map[DbDef.ID]DbDef.ImpactType
+	severities        map[string]DbDef.Severity
+	phases            map[phaseKey]DbDef.Phase
+	auditEntries      []DbDef.AuditEntry
+	events            []DbDef.Event
+	lastEventID       uint64
+}
+
+// snapshot copies the data, appended audit entries and events are cut off by the lengths of the copied slices.
+func (d *memoryData) snapshot() memoryData {
+	return memoryData{
+		components:        maps.Clone(d.components),
+		incidents:         maps.Clone(d.incidents),
+		impacts:           maps.Clone(d.impacts),
+		incidentRevisions: maps.Clone(d.incidentRevisions),
+		dependencies:      maps.Clone(d.dependencies),
+		groups:            maps.Clone(d.groups),
+		groupMembers:      maps.Clone(d.groupMembers),
+		incidentUpdates:   maps.Clone(d.incidentUpdates),
+		impactTypes:       maps.Clone(d.impactTypes),
+		severities:        maps.Clone(d.severities),
+		phases:            maps.Clone(d.phases),
+		auditEntries:      d.auditEntries,
+		events:            d.events,
+		lastEventID:       d.lastEventID,
+	}
+}
+
+// Memory keeps the resources in memory, they are lost on shutdown.
+// References between resources are enforced like in the database.
+// Transactions are serialized and restore the previous state on errors.
+type Memory struct {
+	mutex sync.RWMutex
+	data  memoryData
+}
+
+// NewMemory creates an empty storage.
+func NewMemory() *Memory {
+	return &Memory{
+		mutex: sync.RWMutex{},
+		data: memoryData{
+			components:        map[DbDef.ID]DbDef.Component{},
+			incidents:         map[DbDef.ID]DbDef.Incident{},
+			impacts:           map[DbDef.ID][]DbDef.Impact{},
+			incidentRevisions: map[incidentRevisionKey]DbDef.IncidentRevision{},
+			dependencies:      map[dependencyKey]DbDef.ComponentDependency{},
+			groups:            map[DbDef.ID]DbDef.ComponentGroup{},
+			groupMembers:      map[groupMemberKey]DbDef.ComponentGroupMember{},
+			incidentUpdates:   map[incidentUpdateKey]DbDef.IncidentUpdate{},
+			impactTypes:       map[DbDef.ID]DbDef.ImpactType{},
+			severities:        map[string]DbDef.Severity{},
+			phases:            map[phaseKey]DbDef.Phase{},
+			auditEntries:      nil,
+			events:            nil,
+			lastEventID:       0,
+		},
+	}
+}
+
+// WithContext implements [Storage].
+func (m *Memory) WithContext(_ context.Context) Repository { //nolint:ireturn
+	return &memoryRepository{memory: m, transaction: false, includeDeleted: false}
+}
+
+// Transaction implements [Storage].
+func (m *Memory) Transaction(_ context.Context, fn func(repo Repository) error) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	committed := false
+	snapshot := m.data.snapshot()
+
+	// also rolls back on panics.
+	defer func() {
+		if !committed {
+			m.data = snapshot
+		}
+	}()
+
+	err := fn(&memoryRepository{memory: m, transaction: true, includeDeleted: false})
+	if err != nil {
+		return err
+	}
+
+	committed = true
+
+	return nil
+}
+
+// AuditEntries returns all recorded audit entries, oldest first.
+func (m *Memory) AuditEntries() []DbDef.AuditEntry {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return slices.Clone(m.data.auditEntries)
+}
+
+// Events returns all published events, oldest first.
+func (m *Memory) Events() []DbDef.Event {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return slices.Clone(m.data.events)
+}
+
+// memoryRepository implements [Repository] on a [Memory] storage.
+// Outside of transactions, each call locks the storage on its own.
+type memoryRepository struct {
+	memory         *Memory
+	transaction    bool
+	includeDeleted bool
+}
+
+// IncludeDeleted implements [Repository].
+func (r *memoryRepository) IncludeDeleted() Repository { //nolint:ireturn
+	return &memoryRepository{memory: r.memory, transaction: r.transaction, includeDeleted: true}
+}
+
+// isVisible reports, if a resource with the deletion is found by the repository.
+func (r *memoryRepository) isVisible(deletedAt gorm.DeletedAt) bool {
+	return r.includeDeleted || !deletedAt.Valid
+}
+
+// isComponentVisible reports, if the component is found by the repository,
+// so its dependencies and group memberships are found.
+func (r *memoryRepository) isComponentVisible(componentID DbDef.ID) bool {
+	component, found := r.memory.data.components[componentID]
+
+	return found && r.isVisible(component.DeletedAt)
+}
+
+func (r *memoryRepository) read() func() {
+	if r.transaction {
+		return func() {}
+	}
+
+	r.memory.mutex.RLock()
+
+	return r.memory.mutex.RUnlock
+}
+
+func (r *memoryRepository) write() func() {
+	if r.transaction {
+		return func() {}
+	}
+
+	r.memory.mutex.Lock()
+
+	return r.memory.mutex.Unlock
+}
+
+func clonePointer[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+
+	cloned := *value
+
+	return &cloned
+}
+
+func cloneComponent(component *DbDef.Component) DbDef.Component {
+	var labels *DbDef.Labels
+
+	if component.Labels != nil {
+		clonedLabels := maps.Clone(*component.Labels)
+		labels = &clonedLabels
+	}
+
+	return DbDef.Component{
+		DisplayName:        clonePointer(component.DisplayName),
+		Labels:             labels,
+		ActivelyAffectedBy: nil,
+		Version:            clonePointer(component.Version),
+		DeletedAt:          component.DeletedAt,
+		DeletedBy:          clonePointer(component.DeletedBy),
+		Model:              component.Model,
+	}
+}
+
+func cloneImpact(impact *DbDef.Impact) DbDef.Impact {
+	return DbDef.Impact{
+		Incident:     nil,
+		Component:    nil,
+		ImpactType:   nil,
+		IncidentID:   clonePointer(impact.IncidentID),
+		ComponentID:  clonePointer(impact.ComponentID),
+		ImpactTypeID: clonePointer(impact.ImpactTypeID),
+		Severity:     clonePointer(impact.Severity),
+	}
+}
+
+func cloneDependency(dependency *DbDef.ComponentDependency) DbDef.ComponentDependency {
+	return DbDef.ComponentDependency{
+		Component:   nil,
+		DependsOn:   nil,
+		ComponentID: clonePointer(dependency.ComponentID),
+		DependsOnID: clonePointer(dependency.DependsOnID),
+	}
+}
+
+func cloneGroup(group *DbDef.ComponentGroup) DbDef.ComponentGroup {
+	return DbDef.ComponentGroup{
+		DisplayName: clonePointer(group.DisplayName),
+		Description: clonePointer(group.Description),
+		ParentID:    clonePointer(group.ParentID),
+		Order:       clonePointer(group.Order),
+		Members:     nil,
+		Model:       group.Model,
+	}
+}
+
+func cloneGroupMember(member *DbDef.ComponentGroupMember) DbDef.ComponentGroupMember {
+	return DbDef.ComponentGroupMember{
+		Group:       nil,
+		Component:   nil,
+		GroupID:     clonePointer(member.GroupID),
+		ComponentID: clonePointer(member.ComponentID),
+	}
+}
+
+func cloneIncident(incident *DbDef.Incident) DbDef.Incident {
+	return DbDef.Incident{
+		DisplayName:     clonePointer(incident.DisplayName),
+		Description:     clonePointer(incident.Description),
+		Affects:         nil,
+		BeganAt:         clonePointer(incident.BeganAt),
+		EndedAt:         clonePointer(incident.EndedAt),
+		PhaseGeneration: clonePointer(incident.PhaseGeneration),
+		PhaseOrder:      clonePointer(incident.PhaseOrder),
+		Phase:           nil,
+		Updates:         nil,
+		Version:         clonePointer(incident.Version),
+		DeletedAt:       incident.DeletedAt,
+		DeletedBy:       clonePointer(incident.DeletedBy),
+		Model:           incident.Model,
+	}
+}
+
+func cloneIncidentRevision(revision *DbDef.IncidentRevision) DbDef.IncidentRevision {
+	var affects *[]DbDef.IncidentRevisionImpact
+
+	if revision.Affects != nil {
+		impacts := make([]DbDef.IncidentRevisionImpact, len(*revision.Affects))
+
+		for impactIndex, impact := range *revision.Affects {
+			impacts[impactIndex] = DbDef.IncidentRevisionImpact{
+				IncidentID:   clonePointer(impact.IncidentID),
+				Revision:     clonePointer(impact.Revision),
+				ComponentID:  clonePointer(impact.ComponentID),
+				ImpactTypeID: clonePointer(impact.ImpactTypeID),
+				Severity:     clonePointer(impact.Severity),
+			}
+		}
+
+		affects = &impacts
+	}
+
+	return DbDef.IncidentRevision{
+		IncidentID:      clonePointer(revision.IncidentID),
+		Revision:        clonePointer(revision.Revision),
+		DisplayName:     clonePointer(revision.DisplayName),
+		Description:     clonePointer(revision.Description),
+		BeganAt:         clonePointer(revision.BeganAt),
+		EndedAt:         clonePointer(revision.EndedAt),
+		PhaseGeneration: clonePointer(revision.PhaseGeneration),
+		PhaseOrder:      clonePointer(revision.PhaseOrder),
+		Affects:         affects,
+		RecordedAt:      clonePointer(revision.RecordedAt),
+	}
+}
+
+func cloneIncidentUpdate(incidentUpdate *DbDef.IncidentUpdate) DbDef.IncidentUpdate {
+	return DbDef.IncidentUpdate{
+		IncidentID:  clonePointer(incidentUpdate.IncidentID),
+		Order:       clonePointer(incidentUpdate.Order),
+		DisplayName: clonePointer(incidentUpdate.DisplayName),
+		Description: clonePointer(incidentUpdate.Description),
+		CreatedAt:   clonePointer(incidentUpdate.CreatedAt),
+		Version:     clonePointer(incidentUpdate.Version),
+	}
+}
+
+func cloneImpactType(impactType *DbDef.ImpactType) DbDef.ImpactType {
+	return DbDef.ImpactType{
+		DisplayName: clonePointer(impactType.DisplayName),
+		Description: clonePointer(impactType.Description),
+		Version:     clonePointer(impactType.Version),
+		Model:       impactType.Model,
+	}
+}
+
+func cloneSeverity(severity *DbDef.Severity) DbDef.Severity {
+	return DbDef.Severity{
+		DisplayName: clonePointer(severity.DisplayName),
+		Value:       clonePointer(severity.Value),
+		Version:     clonePointer(severity.Version),
+	}
+}
+
+func clonePhase(phase *DbDef.Phase) DbDef.Phase {
+	return DbDef.Phase{
+		Name:       clonePointer(phase.Name),
+		Generation: clonePointer(phase.Generation),
+		Order:      clonePointer(phase.Order),
+	}
+}
+
+func valueOf[T any](value *T) T {
+	var zero T
+
+	if value == nil {
+		return zero
+	}
+
+	return *value
+}
+
+// initialVersion returns the version of a created resource, like the column default.
+func initialVersion(version *int) *int {
+	if version != nil {
+		return clonePointer(version)
+	}
+
+	initial := 1
+
+	return &initial
+}
+
+// sortedValues returns the values of the map ordered by the compare function, ties are ordered by key.
+func sortedValues[K cmp.Ordered, V any](values map[K]V, compare func(a, b V) int) []V {
+	keys := slices.Sorted(maps.Keys(values))
+	sorted := make([]V, 0, len(keys))
+
+	for _, key := range keys {
+		sorted = append(sorted, values[key])
+	}
+
+	slices.SortStableFunc(sorted, compare)
+
+	return sorted
+}
+
+// byID adapts the map to ordered keys.
+func byID[V any](values map[DbDef.ID]V) map[string]V {
+	converted := make(map[string]V, len(values))
+
+	for id, value := range values {
+		converted[id.String()] = value
+	}
+
+	return converted
+}
+
+// isActiveAt reports, if the incident is active at the time, or currently if nil.
+func isActiveAt(incident *DbDef.Incident, at *time.Time) bool {
+	if at == nil {
+		return incident.EndedAt == nil
+	}
+
+	if incident.BeganAt == nil || !incident.BeganAt.Before(*at) {
+		return false
+	}
+
+	return incident.EndedAt == nil || incident.EndedAt.After(*at)
+}
+
+// matchesSelector reports, if the labels of the component match the selector.
+func matchesSelector(component *DbDef.Component, selector DbDef.Selector) bool {
+	var labels DbDef.Labels
+	if component.Labels != nil {
+		labels = *component.Labels
+	}
+
+	return selector.Matches(labels)
+}
+
+// affectsSelected reports, if the impacts affect any component matching the selector.
+func (d *memoryData) affectsSelected(impacts *[]DbDef.Impact, selector DbDef.Selector) bool {
+	if len(selector) == 0 {
+		return true
+	}
+
+	if impacts == nil {
+		return false
+	}
+
+	for _, impact := range *impacts {
+		component, found := d.components[valueOf(impact.ComponentID)]
+		if found && matchesSelector(&component, selector) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isActiveBetween reports, if the incident is active at any time between start and end.
+// Incidents without end are active from their beginning on.
+func isActiveBetween(incident *DbDef.Incident, start, end time.Time) bool {
+	if incident.BeganAt == nil {
+		return false
+	}
+
+	if incident.EndedAt == nil {
+		return !incident.BeganAt.After(end)
+	}
+
+	endsBefore := incident.BeganAt.Before(start) && incident.EndedAt.Before(start)
+	beginsAfter := incident.BeganAt.After(end) && incident.EndedAt.After(end)
+
+	return !endsBefore && !beginsAfter
+}
+
+// revisionAt returns the revision of the incident valid at the time,
+// the latest one recorded until then, or the first one for incidents recorded afterwards.
+func (d *memoryData) revisionAt(incidentID DbDef.ID, at time.Time) (DbDef.IncidentRevision, bool) {
+	var valid, earliest DbDef.IncidentRevision
+
+	for key, revision := range d.incidentRevisions {
+		if key.incidentID != incidentID {
+			continue
+		}
+
+		if earliest.Revision == nil || key.revision < *earliest.Revision {
+			earliest = revision
+		}
+
+		if !revision.RecordedAt.After(at) && (valid.Revision == nil || key.revision > *valid.Revision) {
+			valid = revision
+		}
+	}
+
+	if valid.Revision != nil {
+		return cloneIncidentRevision(&valid), true
+	}
+
+	if earliest.Revision != nil {
+		return cloneIncidentRevision(&earliest), true
+	}
+
+	return earliest, false
+}
+
+// recordRevision records the stored incident with its impacts as its next revision.
+func (d *memoryData) recordRevision(stored *DbDef.Incident) {
+	revision := 1
+
+	for key := range d.incidentRevisions {
+		if key.incidentID == stored.ID && key.revision >= revision {
+			revision = key.revision + 1
+		}
+	}
+
+	d.incidentRevisions[incidentRevisionKey{stored.ID, revision}] = *DbDef.NewIncidentRevision(
+		d.incidentWithImpacts(stored), revision, time.Now(),
+	)
+}
+
+// incidentsAt resolves the incidents with their impacts, latest first.
+// At a time, the incidents are restored from their revisions valid at the time, otherwise they are current.
+// Deleted incidents are only resolved, if they are included.
+func (d *memoryData) incidentsAt(at *time.Time, includeDeleted bool) []DbDef.Incident {
+	incidents := make([]DbDef.Incident, 0, len(d.incidents))
+
+	for _, stored := range d.incidents {
+		if stored.DeletedAt.Valid && !includeDeleted {
+			continue
+		}
+
+		if at == nil {
+			incidents = append(incidents, *d.incidentWithImpacts(&stored))
+
+			continue
+		}
+
+		if revision, found := d.revisionAt(stored.ID, *at); found {
+			incidents = append(incidents, *revision.ToIncident())
+		}
+	}
+
+	slices.SortFunc(incidents, compareIncidents)
+
+	return incidents
+}
+
+// componentWithImpacts resolves the impacts of the component by undeleted incidents active at the time.
+func (d *memoryData) componentWithImpacts(stored *DbDef.Component, at *time.Time) *DbDef.Component {
+	component := cloneComponent(stored)
+	impacts := []DbDef.Impact{}
+
+	for _, incident := range d.incidentsAt(at, false) {
+		if !isActiveAt(&incident, at) {
+			continue
+		}
+
+		for _, impact := range *incident.Affects {
+			if valueOf(impact.ComponentID) == component.ID {
+				impacts = append(impacts, cloneImpact(&impact))
+			}
+		}
+	}
+
+	component.ActivelyAffectedBy = &impacts
+
+	return &component
+}
+
+// groupWithMembers resolves the members of the group, ordered by component.
+func (d *memoryData) groupWithMembers(
+	stored *DbDef.ComponentGroup,
+	isComponentVisible func(componentID DbDef.ID) bool,
+) *DbDef.ComponentGroup {
+	group := cloneGroup(stored)
+	members := []DbDef.ComponentGroupMember{}
+
+	for key, member := range d.groupMembers {
+		if key.groupID == group.ID && isComponentVisible(key.componentID) {
+			members = append(members, cloneGroupMember(&member))
+		}
+	}
+
+	slices.SortFunc(members, func(a, b DbDef.ComponentGroupMember) int {
+		return compareIDs(valueOf(a.ComponentID), valueOf(b.ComponentID))
+	})
+
+	group.Members = &members
+
+	return &group
+}
+
+// incidentWithImpacts resolves the impacts of the incident.
+func (d *memoryData) incidentWithImpacts(stored *DbDef.Incident) *DbDef.Incident {
+	incident := cloneIncident(stored)
+	impacts := make([]DbDef.Impact, 0, len(d.impacts[incident.ID]))
+
+	for _, impact := range d.impacts[incident.ID] {
+		impacts = append(impacts, cloneImpact(&impact))
+	}
+
+	incident.Affects = &impacts
+
+	return &incident
+}
+
+// incidentWithAssociations resolves the impacts with their components, the phase and the updates of the incident.
+func (d *memoryData) incidentWithAssociations(stored *DbDef.Incident) *DbDef.Incident {
+	return d.resolveAssociations(d.incidentWithImpacts(stored))
+}
+
+// resolveAssociations resolves the components of the impacts, the phase and the updates of the incident.
+func (d *memoryData) resolveAssociations(incident *DbDef.Incident) *DbDef.Incident {
+	for impactIndex, impact := range *incident.Affects {
+		if component, found := d.components[valueOf(impact.ComponentID)]; found {
+			clonedComponent := cloneComponent(&component)
+			(*incident.Affects)[impactIndex].Component = &clonedComponent
+		}
+	}
+
+	if incident.PhaseGeneration != nil && incident.PhaseOrder != nil {
+		if phase, found := d.phases[phaseKey{*incident.PhaseGeneration, *incident.PhaseOrder}]; found {
+			clonedPhase := clonePhase(&phase)
+			incident.Phase = &clonedPhase
+		}
+	}
+
+	updates := []DbDef.IncidentUpdate{}
+
+	for _, incidentUpdate := range d.incidentUpdates {
+		if valueOf(incidentUpdate.IncidentID) == incident.ID {
+			updates = append(updates, cloneIncidentUpdate(&incidentUpdate))
+		}
+	}
+
+	slices.SortFunc(updates, compareIncidentUpdates)
+
+	incident.Updates = &updates
+
+	return incident
+}
+
+// isComponentUsable reports, if the component exists and is not deleted, so it can be referenced.
+func (d *memoryData) isComponentUsable(componentID DbDef.ID) bool {
+	component, found := d.components[componentID]
+
+	return found && !component.DeletedAt.Valid
+}
+
+// checkImpacts ensures the referenced components and impact types exist and assigns the impacts to the incident.
+// Impacts on the same component of the same type are merged, the last one wins.
+func (d *memoryData) checkImpacts(incidentID DbDef.ID, affects []DbDef.Impact) ([]DbDef.Impact, error) {
+	impacts := make([]DbDef.Impact, 0, len(affects))
+
+	for affectIndex := range affects {
+		affect := &affects[affectIndex]
+		affect.IncidentID = &incidentID
+
+		if !d.isComponentUsable(valueOf(affect.ComponentID)) {
+			return nil, ErrReferenced
+		}
+
+		if _, found := d.impactTypes[valueOf(affect.ImpactTypeID)]; !found {
+			return nil, ErrReferenced
+		}
+
+		impact := cloneImpact(affect)
+
+		existing := slices.IndexFunc(impacts, func(other DbDef.Impact) bool {
+			return *other.ComponentID == *impact.ComponentID && *other.ImpactTypeID == *impact.ImpactTypeID
+		})
+		if existing >= 0 {
+			impacts[existing] = impact
+		} else {
+			impacts = append(impacts, impact)
+		}
+	}
+
+	return impacts, nil
+}
+
+// checkPhase ensures the phase of the incident exists and sets its reference.
+func (d *memoryData) checkPhase(incident *DbDef.Incident) error {
+	if incident.Phase != nil {
+		incident.PhaseGeneration = incident.Phase.Generation
+		incident.PhaseOrder = incident.Phase.Order
+	}
+
+	if incident.PhaseGeneration == nil && incident.PhaseOrder == nil {
+		return nil
+	}
+
+	_, found := d.phases[phaseKey{valueOf(incident.PhaseGeneration), valueOf(incident.PhaseOrder)}]
+	if !found {
+		return ErrReferenced
+	}
+
+	return nil
+}
+
+// isComponentReferenced reports, if impacts reference the component, of deleted incidents only if they are included.
+func (d *memoryData) isComponentReferenced(componentID DbDef.ID, includeDeleted bool) bool {
+	for incidentID, impacts := range d.impacts {
+		if d.incidents[incidentID].DeletedAt.Valid && !includeDeleted {
+			continue
+		}
+
+		for _, impact := range impacts {
+			if valueOf(impact.ComponentID) == componentID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (d *memoryData) isImpactTypeReferenced(impactTypeID DbDef.ID) bool {
+	for _, impacts := range d.impacts {
+		for _, impact := range impacts {
+			if valueOf(impact.ImpactTypeID) == impactTypeID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// afterCursor drops the sorted items up to the item at the cursor of the page.
+// sentinel creates an item with the sort key of the cursor.
+func afterCursor[V any](
+	sorted []V,
+	page Page,
+	compare func(a, b V) int,
+	sentinel func(cursor Cursor) (V, error),
+) ([]V, error) {
+	if page.After == nil {
+		return sorted, nil
+	}
+
+	last, err := sentinel(*page.After)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(sorted, func(item V) bool {
+		return compare(item, last) <= 0
+	}), nil
+}
+
+func compareIDs(a, b DbDef.ID) int {
+	return cmp.Compare(a.String(), b.String())
+}
+
+func compareComponents(a, b DbDef.Component) int {
+	return cmp.Or(cmp.Compare(valueOf(a.DisplayName), valueOf(b.DisplayName)), compareIDs(a.ID, b.ID))
+}
+
+func compareGroups(a, b DbDef.ComponentGroup) int {
+	return cmp.Or(
+		cmp.Compare(valueOf(a.Order), valueOf(b.Order)),
+		cmp.Compare(valueOf(a.DisplayName), valueOf(b.DisplayName)),
+		compareIDs(a.ID, b.ID),
+	)
+}
+
+func componentSentinel(cursor Cursor) (DbDef.Component, error) {
+	id, err := cursor.uuid()
+
+	return DbDef.Component{Model: DbDef.Model{ID: id}, DisplayName: &cursor.Key}, err //nolint:exhaustruct
+}
+
+// compareIncidents sorts the latest incidents first.
+func compareIncidents(a, b DbDef.Incident) int {
+	return cmp.Or(valueOf(b.BeganAt).Compare(valueOf(a.BeganAt)), compareIDs(b.ID, a.ID))
+}
+
+func incidentSentinel(cursor Cursor) (DbDef.Incident, error) {
+	beganAt, err := cursor.timeKey()
+	if err != nil {
+		return DbDef.Incident{}, err //nolint:exhaustruct
+	}
+
+	id, err := cursor.uuid()
+
+	return DbDef.Incident{Model: DbDef.Model{ID: id}, BeganAt: &beganAt}, err //nolint:exhaustruct
+}
+
+func compareIncidentUpdates(a, b DbDef.IncidentUpdate) int {
+	return cmp.Compare(valueOf(a.Order), valueOf(b.Order))
+}
+
+func incidentUpdateSentinel(cursor Cursor) (DbDef.IncidentUpdate, error) {
+	order, err := cursor.intKey()
+
+	return DbDef.IncidentUpdate{Order: &order}, err //nolint:exhaustruct
+}
+
+func compareImpactTypes(a, b DbDef.ImpactType) int {
+	return cmp.Or(cmp.Compare(valueOf(a.DisplayName), valueOf(b.DisplayName)), compareIDs(a.ID, b.ID))
+}
+
+func impactTypeSentinel(cursor Cursor) (DbDef.ImpactType, error) {
+	id, err := cursor.uuid()
+
+	return DbDef.ImpactType{Model: DbDef.Model{ID: id}, DisplayName: &cursor.Key}, err //nolint:exhaustruct
+}
+
+func compareSeverities(a, b DbDef.Severity) int {
+	return cmp.Compare(valueOf(a.Value), valueOf(b.Value))
+}
+
+func severitySentinel(cursor Cursor) (DbDef.Severity, error) {
+	value, err := cursor.intKey()
+
+	return DbDef.Severity{Value: &value}, err //nolint:exhaustruct
+}
+
+// ListComponents implements [Repository].
+func (r *memoryRepository) ListComponents(
+	at *time.Time,
+	selector DbDef.Selector,
+	page Page,
+) ([]*DbDef.Component, *Cursor, error) {
+	defer r.read()()
+
+	data := &r.memory.data
+
+	sorted, err := afterCursor(
+		sortedValues(byID(data.components), compareComponents), page, compareComponents, componentSentinel,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sorted = slices.DeleteFunc(sorted, func(component DbDef.Component) bool {
+		return !r.isVisible(component.DeletedAt) || !matchesSelector(&component, selector)
+	})
+
+	sorted, next := paginate(sorted, page, func(component DbDef.Component) *Cursor {
+		return componentCursor(&component)
+	})
+
+	components := make([]*DbDef.Component, 0, len(sorted))
+
+	for _, component := range sorted {
+		components = append(components, data.componentWithImpacts(&component, at))
+	}
+
+	return components, next, nil
+}
+
+// GetComponent implements [Repository].
+func (r *memoryRepository) GetComponent(componentID DbDef.ID, at *time.Time) (*DbDef.Component, error) {
+	defer r.read()()
+
+	component, found := r.memory.data.components[componentID]
+	if !found || !r.isVisible(component.DeletedAt) {
+		return nil, ErrNotFound
+	}
+
+	return r.memory.data.componentWithImpacts(&component, at), nil
+}
+
+// CreateComponent implements [Repository].
+func (r *memoryRepository) CreateComponent(component *DbDef.Component) error {
+	defer r.write()()
+
+	if component.ID == uuid.Nil {
+		component.ID = uuid.New()
+	}
+
+	if _, found := r.memory.data.components[component.ID]; found {
+		return ErrDuplicate
+	}
+
+	component.Version = initialVersion(component.Version)
+	r.memory.data.components[component.ID] = cloneComponent(component)
+
+	return nil
+}
+
+// UpdateComponent implements [Repository].
+func (r *memoryRepository) UpdateComponent(component *DbDef.Component) (*DbDef.Component, *DbDef.Component, error) {
+	defer r.write()()
+
+	dbComponent, found := r.memory.data.components[component.ID]
+	if !found || !r.isVisible(dbComponent.DeletedAt) {
+		return nil, nil, ErrNotFound
+	}
+
+	updatedComponent := cloneComponent(&dbComponent)
+	changes := cloneComponent(component)
+
+	if changes.DisplayName != nil {
+		updatedComponent.DisplayName = changes.DisplayName
+	}
+
+	if changes.Labels != nil {
+		updatedComponent.Labels = changes.Labels
+	}
+
+	updatedComponent.Version = nextVersion(dbComponent.Version)
+	r.memory.data.components[component.ID] = updatedComponent
+
+	before := cloneComponent(&dbComponent)
+	after := cloneComponent(&updatedComponent)
+
+	return &before, &after, nil
+}
+
+// DeleteComponent implements [Repository].
+func (r *memoryRepository) DeleteComponent(componentID DbDef.ID, deletedBy string) (*DbDef.Component, error) {
+	defer r.write()()
+
+	dbComponent, found := r.memory.data.components[componentID]
+	if !found || !r.isVisible(dbComponent.DeletedAt) {
+		return nil, ErrNotFound
+	}
+
+	if r.memory.data.isComponentReferenced(componentID, false) {
+		return nil, ErrReferenced
+	}
+
+	deletedComponent := cloneComponent(&dbComponent)
+	deletedComponent.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	deletedComponent.DeletedBy = &deletedBy
+	deletedComponent.Version = nextVersion(dbComponent.Version)
+	r.memory.data.components[componentID] = deletedComponent
+
+	before := cloneComponent(&dbComponent)
+
+	return &before, nil
+}
+
+// RestoreComponent implements [Repository].
+func (r *memoryRepository) RestoreComponent(componentID DbDef.ID) (*DbDef.Component, error) {
+	defer r.write()()
+
+	dbComponent, found := r.memory.data.components[componentID]
+	if !found || !dbComponent.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
+	restoredComponent := cloneComponent(&dbComponent)
+	restoredComponent.DeletedAt = gorm.DeletedAt{} //nolint:exhaustruct
+	restoredComponent.DeletedBy = nil
+	restoredComponent.Version = nextVersion(dbComponent.Version)
+	r.memory.data.components[componentID] = restoredComponent
+
+	return r.memory.data.componentWithImpacts(&restoredComponent, nil), nil
+}
+
+// ListComponentImpacts implements [Repository].
+func (r *memoryRepository) ListComponentImpacts(componentID DbDef.ID, start, end time.Time) ([]*DbDef.Impact, error) {
+	defer r.read()()
+
+	data := &r.memory.data
+	impacts := []*DbDef.Impact{}
+
+	for _, incident := range sortedValues(byID(data.incidents), compareIncidents) {
+		if incident.DeletedAt.Valid || incident.BeganAt == nil || !incident.BeganAt.Before(end) ||
+			(incident.EndedAt != nil && !incident.EndedAt.After(start)) {
+			continue
+		}
+
+		for _, impact := range data.impacts[incident.ID] {
+			if valueOf(impact.ComponentID) != componentID {
+				continue
+			}
+
+			clonedImpact := cloneImpact(&impact)
+			clonedIncident := cloneIncident(&incident)
+			clonedImpact.Incident = &clonedIncident
+			impacts = append(impacts, &clonedImpact)
+		}
+	}
+
+	slices.SortStableFunc(impacts, func(a, b *DbDef.Impact) int {
+		return a.Incident.BeganAt.Compare(*b.Incident.BeganAt)
+	})
+
+	return impacts, nil
+}
+
+// LockComponentDependencies implements [Repository].
+// Transactions hold the lock of the memory, which already serializes them.
+func (r *memoryRepository) LockComponentDependencies() error {
+	return nil
+}
+
+// ListComponentDependencies implements [Repository].
+func (r *memoryRepository) ListComponentDependencies() ([]*DbDef.ComponentDependency, error) {
+	defer r.read()()
+
+	keys := slices.SortedFunc(maps.Keys(r.memory.data.dependencies), func(a, b dependencyKey) int {
+		return cmp.Or(compareIDs(a.componentID, b.componentID), compareIDs(a.dependsOnID, b.dependsOnID))
+	})
+
+	dependencies := make([]*DbDef.ComponentDependency, 0, len(keys))
+
+	for _, key := range keys {
+		if !r.isComponentVisible(key.componentID) || !r.isComponentVisible(key.dependsOnID) {
+			continue
+		}
+
+		dependency := r.memory.data.dependencies[key]
+		cloned := cloneDependency(&dependency)
+		dependencies = append(dependencies, &cloned)
+	}
+
+	return dependencies, nil
+}
+
+// CreateComponentDependency implements [Repository].
+func (r *memoryRepository) CreateComponentDependency(dependency *DbDef.ComponentDependency) error {
+	defer r.write()()
+
+	data := &r.memory.data
+	key := dependencyKey{valueOf(dependency.ComponentID), valueOf(dependency.DependsOnID)}
+
+	for _, componentID := range []DbDef.ID{key.componentID, key.dependsOnID} {
+		if !data.isComponentUsable(componentID) {
+			return ErrReferenced
+		}
+	}
+
+	if _, found := data.dependencies[key]; found {
+		return ErrDuplicate
+	}
+
+	data.dependencies[key] = cloneDependency(dependency)
+
+	return nil
+}
+
+// DeleteComponentDependency implements [Repository].
+func (r *memoryRepository) DeleteComponentDependency(
+	componentID, dependsOnID DbDef.ID,
+) (*DbDef.ComponentDependency, error) {
+	defer r.write()()
+
+	key := dependencyKey{componentID, dependsOnID}
+
+	dependency, found := r.memory.data.dependencies[key]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	delete(r.memory.data.dependencies, key)
+
+	before := cloneDependency(&dependency)
+
+	return &before, nil
+}
+
+// ListComponentGroups implements [Repository].
+func (r *memoryRepository) ListComponentGroups() ([]*DbDef.ComponentGroup, error) {
+	defer r.read()()
+
+	sorted := sortedValues(byID(r.memory.data.groups), compareGroups)
+	groups := make([]*DbDef.ComponentGroup, 0, len(sorted))
+
+	for _, group := range sorted {
+		groups = append(groups, r.memory.data.groupWithMembers(&group, r.isComponentVisible))
+	}
+
+	return groups, nil
+}
+
+// GetComponentGroup implements [Repository].
+func (r *memoryRepository) GetComponentGroup(groupID DbDef.ID) (*DbDef.ComponentGroup, error) {
+	defer r.read()()
+
+	group, found := r.memory.data.groups[groupID]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	return r.memory.data.groupWithMembers(&group, r.isComponentVisible), nil
+}
+
+// CreateComponentGroup implements [Repository].
+func (r *memoryRepository) CreateComponentGroup(group *DbDef.ComponentGroup) error {
+	defer r.write()()
+
+	data := &r.memory.data
+
+	if group.ID == uuid.Nil {
+		group.ID = uuid.New()
+	}
+
+	if _, found := data.groups[group.ID]; found {
+		return ErrDuplicate
+	}
+
+	if _, found := data.groups[valueOf(group.ParentID)]; group.ParentID != nil && !found {
+		return ErrReferenced
+	}
+
+	for _, componentID := range group.ComponentIDs() {
+		if !data.isComponentUsable(componentID) {
+			return ErrReferenced
+		}
+	}
+
+	data.groups[group.ID] = cloneGroup(group)
+
+	for _, componentID := range group.ComponentIDs() {
+		data.groupMembers[groupMemberKey{group.ID, componentID}] = DbDef.ComponentGroupMember{
+			Group:       nil,
+			Component:   nil,
+			GroupID:     &group.ID,
+			ComponentID: &componentID,
+		}
+	}
+
+	return nil
+}
+
+// UpdateComponentGroup implements [Repository].
+func (r *memoryRepository) UpdateComponentGroup(
+	group *DbDef.ComponentGroup,
+) (*DbDef.ComponentGroup, *DbDef.ComponentGroup, error) {
+	defer r.write()()
+
+	data := &r.memory.data
+
+	dbGroup, found := data.groups[group.ID]
+	if !found {
+		return nil, nil, ErrNotFound
+	}
+
+	if _, found = data.groups[valueOf(group.ParentID)]; group.ParentID != nil && !found {
+		return nil, nil, ErrReferenced
+	}
+
+	before := data.groupWithMembers(&dbGroup, r.isComponentVisible)
+	data.groups[group.ID] = cloneGroup(group)
+
+	return before, data.groupWithMembers(group, r.isComponentVisible), nil
+}
+
+// DeleteComponentGroup implements [Repository].
+func (r *memoryRepository) DeleteComponentGroup(groupID DbDef.ID) (*DbDef.ComponentGroup, error) {
+	defer r.write()()
+
+	data := &r.memory.data
+
+	dbGroup, found := data.groups[groupID]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	for _, group := range data.groups {
+		if valueOf(group.ParentID) == groupID {
+			return nil, ErrReferenced
+		}
+	}
+
+	before := data.groupWithMembers(&dbGroup, r.isComponentVisible)
+
+	delete(data.groups, groupID)
+
+	maps.DeleteFunc(data.groupMembers, func(key groupMemberKey, _ DbDef.ComponentGroupMember) bool {
+		return key.groupID == groupID
+	})
+
+	return before, nil
+}
+
+// AddComponentGroupMember implements [Repository].
+func (r *memoryRepository) AddComponentGroupMember(member *DbDef.ComponentGroupMember) error {
+	defer r.write()()
+
+	data := &r.memory.data
+	key := groupMemberKey{valueOf(member.GroupID), valueOf(member.ComponentID)}
+
+	if _, found := data.groups[key.groupID]; !found {
+		return ErrReferenced
+	}
+
+	if !data.isComponentUsable(key.componentID) {
+		return ErrReferenced
+	}
+
+	if _, found := data.groupMembers[key]; found {
+		return ErrDuplicate
+	}
+
+	data.groupMembers[key] = cloneGroupMember(member)
+
+	return nil
+}
+
+// RemoveComponentGroupMember implements [Repository].
+func (r *memoryRepository) RemoveComponentGroupMember(
+	groupID, componentID DbDef.ID,
+) (*DbDef.ComponentGroupMember, error) {
+	defer r.write()()
+
+	key := groupMemberKey{groupID, componentID}
+
+	member, found := r.memory.data.groupMembers[key]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	delete(r.memory.data.groupMembers, key)
+
+	before := cloneGroupMember(&member)
+
+	return &before, nil
+}
+
+// ListIncidents implements [Repository].
+func (r *memoryRepository) ListIncidents(
+	start, end time.Time,
+	at *time.Time,
+	selector DbDef.Selector,
+	page Page,
+) ([]*DbDef.Incident, *Cursor, error) {
+	defer r.read()()
+
+	data := &r.memory.data
+
+	sorted, err := afterCursor(data.incidentsAt(at, r.includeDeleted), page, compareIncidents, incidentSentinel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sorted = slices.DeleteFunc(sorted, func(incident DbDef.Incident) bool {
+		return !isActiveBetween(&incident, start, end) || !data.affectsSelected(incident.Affects, selector)
+	})
+
+	sorted, next := paginate(sorted, page, func(incident DbDef.Incident) *Cursor {
+		return incidentCursor(&incident)
+	})
+
+	incidents := make([]*DbDef.Incident, 0, len(sorted))
+
+	for _, incident := range sorted {
+		incidents = append(incidents, data.resolveAssociations(&incident))
+	}
+
+	return incidents, next, nil
+}
+
+// GetIncident implements [Repository].
+func (r *memoryRepository) GetIncident(incidentID DbDef.ID) (*DbDef.Incident, error) {
+	defer r.read()()
+
+	incident, found := r.memory.data.incidents[incidentID]
+	if !found || !r.isVisible(incident.DeletedAt) {
+		return nil, ErrNotFound
+	}
+
+	return r.memory.data.incidentWithAssociations(&incident), nil
+}
+
+// CreateIncident implements [Repository].
+func (r *memoryRepository) CreateIncident(incident *DbDef.Incident) error {
+	defer r.write()()
+
+	data := &r.memory.data
+
+	if incident.ID == uuid.Nil {
+		incident.ID = uuid.New()
+	}
+
+	if _, found := data.incidents[incident.ID]; found {
+		return ErrDuplicate
+	}
+
+	err := data.checkPhase(incident)
+	if err != nil {
+		return err
+	}
+
+	var impacts []DbDef.Impact
+
+	if incident.Affects != nil {
+		impacts, err = data.checkImpacts(incident.ID, *incident.Affects)
+		if err != nil {
+			return err
+		}
+	}
+
+	incident.Version = initialVersion(incident.Version)
+	stored := cloneIncident(incident)
+	data.incidents[incident.ID] = stored
+	data.impacts[incident.ID] = impacts
+	data.recordRevision(&stored)
+
+	return nil
+}
+
+// UpdateIncident implements [Repository].
+func (r *memoryRepository) UpdateIncident(incident *DbDef.Incident) (*DbDef.Incident, *DbDef.Incident, error) {
+	defer r.write()()
+
+	data := &r.memory.data
+
+	dbIncident, found := data.incidents[incident.ID]
+	if !found || !r.isVisible(dbIncident.DeletedAt) {
+		return nil, nil, ErrNotFound
+	}
+
+	before := data.incidentWithImpacts(&dbIncident)
+	updatedIncident := cloneIncident(&dbIncident)
+	changes := cloneIncident(incident)
+
+	if incident.Phase != nil || incident.PhaseGeneration != nil || incident.PhaseOrder != nil {
+		changes.Phase = incident.Phase
+
+		err := data.checkPhase(&changes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		updatedIncident.PhaseGeneration = changes.PhaseGeneration
+		updatedIncident.PhaseOrder = changes.PhaseOrder
+	}
+
+	if changes.DisplayName != nil {
+		updatedIncident.DisplayName = changes.DisplayName
+	}
+
+	if changes.Description != nil {
+		updatedIncident.Description = changes.Description
+	}
+
+	if changes.BeganAt != nil {
+		updatedIncident.BeganAt = changes.BeganAt
+	}
+
+	if changes.EndedAt != nil {
+		updatedIncident.EndedAt = changes.EndedAt
+	}
+
+	if incident.Affects != nil {
+		impacts, err := data.checkImpacts(incident.ID, *incident.Affects)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		data.impacts[incident.ID] = impacts
+	}
+
+	updatedIncident.Version = nextVersion(dbIncident.Version)
+	data.incidents[incident.ID] = updatedIncident
+	data.recordRevision(&updatedIncident)
+
+	return before, data.incidentWithImpacts(&updatedIncident), nil
+}
+
+// DeleteIncident implements [Repository].
+func (r *memoryRepository) DeleteIncident(incidentID DbDef.ID, deletedBy string) (*DbDef.Incident, error) {
+	defer r.write()()
+
+	data := &r.memory.data
+
+	dbIncident, found := data.incidents[incidentID]
+	if !found || !r.isVisible(dbIncident.DeletedAt) {
+		return nil, ErrNotFound
+	}
+
+	deletedIncident := cloneIncident(&dbIncident)
+	deletedIncident.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	deletedIncident.DeletedBy = &deletedBy
+	deletedIncident.Version = nextVersion(dbIncident.Version)
+	data.incidents[incidentID] = deletedIncident
+
+	return data.incidentWithImpacts(&dbIncident), nil
+}
+
+// RestoreIncident implements [Repository].
+func (r *memoryRepository) RestoreIncident(incidentID DbDef.ID) (*DbDef.Incident, error) {
+	defer r.write()()
+
+	data := &r.memory.data
+
+	dbIncident, found := data.incidents[incidentID]
+	if !found || !dbIncident.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+
+	for _, impact := range data.impacts[incidentID] {
+		if !data.isComponentUsable(valueOf(impact.ComponentID)) {
+			return nil, ErrReferenced
+		}
+	}
+
+	restoredIncident := cloneIncident(&dbIncident)
+	restoredIncident.DeletedAt = gorm.DeletedAt{} //nolint:exhaustruct
+	restoredIncident.DeletedBy = nil
+	restoredIncident.Version = nextVersion(dbIncident.Version)
+	data.incidents[incidentID] = restoredIncident
+
+	return data.incidentWithAssociations(&restoredIncident), nil
+}
+
+// isIncidentVisible reports, if the incident exists and is found by the repository.
+func (r *memoryRepository) isIncidentVisible(incidentID DbDef.ID) bool {
+	incident, found := r.memory.data.incidents[incidentID]
+
+	return found && r.isVisible(incident.DeletedAt)
+}
+
+// ListIncidentRevisions implements [Repository].
+func (r *memoryRepository) ListIncidentRevisions(incidentID DbDef.ID) ([]*DbDef.IncidentRevision, error) {
+	defer r.read()()
+
+	data := &r.memory.data
+
+	if !r.isIncidentVisible(incidentID) {
+		return nil, ErrNotFound
+	}
+
+	revisions := []*DbDef.IncidentRevision{}
+
+	for key, revision := range data.incidentRevisions {
+		if key.incidentID == incidentID {
+			cloned := cloneIncidentRevision(&revision)
+			revisions = append(revisions, &cloned)
+		}
+	}
+
+	slices.SortFunc(revisions, func(a, b *DbDef.IncidentRevision) int {
+		return cmp.Compare(valueOf(a.Revision), valueOf(b.Revision))
+	})
+
+	return revisions, nil
+}
+
+// ListIncidentUpdates implements [Repository].
+func (r *memoryRepository) ListIncidentUpdates(
+	incidentID DbDef.ID,
+	page Page,
+) ([]*DbDef.IncidentUpdate, *Cursor, error) {
+	defer r.read()()
+
+	if !r.isIncidentVisible(incidentID) {
+		return []*DbDef.IncidentUpdate{}, nil, nil
+	}
+
+	sorted := []DbDef.IncidentUpdate{}
+
+	for key, incidentUpdate := range r.memory.data.incidentUpdates {
+		if key.incidentID == incidentID {
+			sorted = append(sorted, incidentUpdate)
+		}
+	}
+
+	slices.SortFunc(sorted, compareIncidentUpdates)
+
+	sorted, err := afterCursor(sorted, page, compareIncidentUpdates, incidentUpdateSentinel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sorted, next := paginate(sorted, page, func(incidentUpdate DbDef.IncidentUpdate) *Cursor {
+		return incidentUpdateCursor(&incidentUpdate)
+	})
+
+	incidentUpdates := make([]*DbDef.IncidentUpdate, 0, len(sorted))
+
+	for _, incidentUpdate := range sorted {
+		cloned := cloneIncidentUpdate(&incidentUpdate)
+		incidentUpdates = append(incidentUpdates, &cloned)
+	}
+
+	return incidentUpdates, next, nil
+}
+
+// GetIncidentUpdate implements [Repository].
+func (r *memoryRepository) GetIncidentUpdate(incidentID DbDef.ID, order int) (*DbDef.IncidentUpdate, error) {
+	defer r.read()()
+
+	incidentUpdate, found := r.memory.data.incidentUpdates[incidentUpdateKey{incidentID, order}]
+	if !found || !r.isIncidentVisible(incidentID) {
+		return nil, ErrNotFound
+	}
+
+	cloned := cloneIncidentUpdate(&incidentUpdate)
+
+	return &cloned, nil
+}
+
+// HighestIncidentUpdateOrder implements [Repository].
+func (r *memoryRepository) HighestIncidentUpdateOrder(incidentID DbDef.ID) (int, error) {
+	defer r.read()()
+
+	order := -1
+
+	for key := range r.memory.data.incidentUpdates {
+		if key.incidentID == incidentID {
+			order = max(order, key.order)
+		}
+	}
+
+	return order, nil
+}
+
+// CreateIncidentUpdate implements [Repository].
+func (r *memoryRepository) CreateIncidentUpdate(incidentUpdate *DbDef.IncidentUpdate) error {
+	defer r.write()()
+
+	data := &r.memory.data
+	key := incidentUpdateKey{valueOf(incidentUpdate.IncidentID), valueOf(incidentUpdate.Order)}
+
+	if !r.isIncidentVisible(key.incidentID) {
+		return ErrReferenced
+	}
+
+	if _, found := data.incidentUpdates[key]; found {
+		return ErrDuplicate
+	}
+
+	incidentUpdate.Version = initialVersion(incidentUpdate.Version)
+	data.incidentUpdates[key] = cloneIncidentUpdate(incidentUpdate)
+
+	return nil
+}
+
+// UpdateIncidentUpdate implements [Repository].
+func (r *memoryRepository) UpdateIncidentUpdate(
+	incidentUpdate *DbDef.IncidentUpdate,
+) (*DbDef.IncidentUpdate, *DbDef.IncidentUpdate, error) {
+	defer r.write()()
+
+	key := incidentUpdateKey{valueOf(incidentUpdate.IncidentID), valueOf(incidentUpdate.Order)}
+
+	dbIncidentUpdate, found := r.memory.data.incidentUpdates[key]
+	if !found || !r.isIncidentVisible(key.incidentID) {
+		return nil, nil, ErrNotFound
+	}
+
+	updatedIncidentUpdate := cloneIncidentUpdate(&dbIncidentUpdate)
+	changes := cloneIncidentUpdate(incidentUpdate)
+
+	if changes.DisplayName != nil {
+		updatedIncidentUpdate.DisplayName = changes.DisplayName
+	}
+
+	if changes.Description != nil {
+		updatedIncidentUpdate.Description = changes.Description
+	}
+
+	if changes.CreatedAt != nil {
+		updatedIncidentUpdate.CreatedAt = changes.CreatedAt
+	}
+
+	updatedIncidentUpdate.Version = nextVersion(dbIncidentUpdate.Version)
+	r.memory.data.incidentUpdates[key] = updatedIncidentUpdate
+
+	before := cloneIncidentUpdate(&dbIncidentUpdate)
+	after := cloneIncidentUpdate(&updatedIncidentUpdate)
+
+	return &before, &after, nil
+}
+
+// DeleteIncidentUpdate implements [Repository].
+func (r *memoryRepository) DeleteIncidentUpdate(incidentID DbDef.ID, order int) (*DbDef.IncidentUpdate, error) {
+	defer r.write()()
+
+	key := incidentUpdateKey{incidentID, order}
+
+	dbIncidentUpdate, found := r.memory.data.incidentUpdates[key]
+	if !found || !r.isIncidentVisible(key.incidentID) {
+		return nil, ErrNotFound
+	}
+
+	delete(r.memory.data.incidentUpdates, key)
+
+	before := cloneIncidentUpdate(&dbIncidentUpdate)
+
+	return &before, nil
+}
+
+// PurgeDeleted implements [Repository].
+func (r *memoryRepository) PurgeDeleted(deletedBefore time.Time) (int, error) {
+	defer r.write()()
+
+	data := &r.memory.data
+	purged := 0
+
+	for incidentID, incident := range data.incidents {
+		if !incident.DeletedAt.Valid || !incident.DeletedAt.Time.Before(deletedBefore) {
+			continue
+		}
+
+		delete(data.incidents, incidentID)
+		delete(data.impacts, incidentID)
+		maps.DeleteFunc(data.incidentUpdates, func(key incidentUpdateKey, _ DbDef.IncidentUpdate) bool {
+			return key.incidentID == incidentID
+		})
+		maps.DeleteFunc(data.incidentRevisions, func(key incidentRevisionKey, _ DbDef.IncidentRevision) bool {
+			return key.incidentID == incidentID
+		})
+
+		purged++
+	}
+
+	for componentID, component := range data.components {
+		if !component.DeletedAt.Valid || !component.DeletedAt.Time.Before(deletedBefore) ||
+			data.isComponentReferenced(componentID, true) {
+			continue
+		}
+
+		delete(data.components, componentID)
+		maps.DeleteFunc(data.dependencies, func(key dependencyKey, _ DbDef.ComponentDependency) bool {
+			return key.componentID == componentID || key.dependsOnID == componentID
+		})
+		maps.DeleteFunc(data.groupMembers, func(key groupMemberKey, _ DbDef.ComponentGroupMember) bool {
+			return key.componentID == componentID
+		})
+
+		purged++
+	}
+
+	return purged, nil
+}
+
+// PurgeEvents implements [Repository].
+// Webhook deliveries are not kept in memory, so all events published before the time are removed.
+func (r *memoryRepository) PurgeEvents(publishedBefore time.Time) (int, error) {
+	defer r.write()()
+
+	// The kept events are copied, as snapshots share the slice.
+	kept := make([]DbDef.Event, 0, len(r.memory.data.events))
+
+	for _, event := range r.memory.data.events {
+		if !event.CreatedAt.Before(publishedBefore) {
+			kept = append(kept, event)
+		}
+	}
+
+	purged := len(r.memory.data.events) - len(kept)
+	r.memory.data.events = kept
+
+	return purged, nil
+}
+
+// ListImpactTypes implements [Repository].
+func (r *memoryRepository) ListImpactTypes(page Page) ([]*DbDef.ImpactType, *Cursor, error) {
+	defer r.read()()
+
+	sorted, err := afterCursor(
+		sortedValues(byID(r.memory.data.impactTypes), compareImpactTypes), page, compareImpactTypes, impactTypeSentinel,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sorted, next := paginate(sorted, page, func(impactType DbDef.ImpactType) *Cursor {
+		return impactTypeCursor(&impactType)
+	})
+
+	impactTypes := make([]*DbDef.ImpactType, 0, len(sorted))
+
+	for _, impactType := range sorted {
+		cloned := cloneImpactType(&impactType)
+		impactTypes = append(impactTypes, &cloned)
+	}
+
+	return impactTypes, next, nil
+}
+
+// GetImpactType implements [Repository].
+func (r *memoryRepository) GetImpactType(impactTypeID DbDef.ID) (*DbDef.ImpactType, error) {
+	defer r.read()()
+
+	impactType, found := r.memory.data.impactTypes[impactTypeID]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	cloned := cloneImpactType(&impactType)
+
+	return &cloned, nil
+}
+
+// CreateImpactType implements [Repository].
+func (r *memoryRepository) CreateImpactType(impactType *DbDef.ImpactType) error {
+	defer r.write()()
+
+	if impactType.ID == uuid.Nil {
+		impactType.ID = uuid.New()
+	}
+
+	if _, found := r.memory.data.impactTypes[impactType.ID]; found {
+		return ErrDuplicate
+	}
+
+	impactType.Version = initialVersion(impactType.Version)
+	r.memory.data.impactTypes[impactType.ID] = cloneImpactType(impactType)
+
+	return nil
+}
+
+// UpdateImpactType implements [Repository].
+func (r *memoryRepository) UpdateImpactType(
+	impactType *DbDef.ImpactType,
+) (*DbDef.ImpactType, *DbDef.ImpactType, error) {
+	defer r.write()()
+
+	dbImpactType, found := r.memory.data.impactTypes[impactType.ID]
+	if !found {
+		return nil, nil, ErrNotFound
+	}
+
+	updatedImpactType := cloneImpactType(&dbImpactType)
+	changes := cloneImpactType(impactType)
+
+	if changes.DisplayName != nil {
+		updatedImpactType.DisplayName = changes.DisplayName
+	}
+
+	if changes.Description != nil {
+		updatedImpactType.Description = changes.Description
+	}
+
+	updatedImpactType.Version = nextVersion(dbImpactType.Version)
+	r.memory.data.impactTypes[impactType.ID] = updatedImpactType
+
+	before := cloneImpactType(&dbImpactType)
+	after := cloneImpactType(&updatedImpactType)
+
+	return &before, &after, nil
+}
+
+// DeleteImpactType implements [Repository].
+func (r *memoryRepository) DeleteImpactType(impactTypeID DbDef.ID) (*DbDef.ImpactType, error) {
+	defer r.write()()
+
+	dbImpactType, found := r.memory.data.impactTypes[impactTypeID]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	if r.memory.data.isImpactTypeReferenced(impactTypeID) {
+		return nil, ErrReferenced
+	}
+
+	delete(r.memory.data.impactTypes, impactTypeID)
+
+	before := cloneImpactType(&dbImpactType)
+
+	return &before, nil
+}
+
+// ListSeverities implements [Repository].
+func (r *memoryRepository) ListSeverities(page Page) ([]*DbDef.Severity, *Cursor, error) {
+	defer r.read()()
+
+	sorted, err := afterCursor(
+		sortedValues(r.memory.data.severities, compareSeverities), page, compareSeverities, severitySentinel,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sorted, next := paginate(sorted, page, func(severity DbDef.Severity) *Cursor {
+		return severityCursor(&severity)
+	})
+
+	severities := make([]*DbDef.Severity, 0, len(sorted))
+
+	for _, severity := range sorted {
+		cloned := cloneSeverity(&severity)
+		severities = append(severities, &cloned)
+	}
+
+	return severities, next, nil
+}
+
+// GetSeverity implements [Repository].
+func (r *memoryRepository) GetSeverity(name string) (*DbDef.Severity, error) {
+	defer r.read()()
+
+	severity, found := r.memory.data.severities[name]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	cloned := cloneSeverity(&severity)
+
+	return &cloned, nil
+}
+
+// hasSeverityValue reports, if a severity other than the named one has the value.
+func (d *memoryData) hasSeverityValue(name string, value *int) bool {
+	if value == nil {
+		return false
+	}
+
+	for otherName, other := range d.severities {
+		if otherName != name && other.Value != nil && *other.Value == *value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateSeverity implements [Repository].
+func (r *memoryRepository) CreateSeverity(severity *DbDef.Severity) error {
+	defer r.write()()
+
+	name := valueOf(severity.DisplayName)
+
+	if _, found := r.memory.data.severities[name]; found {
+		return ErrDuplicate
+	}
+
+	if r.memory.data.hasSeverityValue(name, severity.Value) {
+		return ErrDuplicate
+	}
+
+	severity.Version = initialVersion(severity.Version)
+	r.memory.data.severities[name] = cloneSeverity(severity)
+
+	return nil
+}
+
+// UpdateSeverity implements [Repository].
+func (r *memoryRepository) UpdateSeverity(
+	name string,
+	severity *DbDef.Severity,
+) (*DbDef.Severity, *DbDef.Severity, error) {
+	defer r.write()()
+
+	data := &r.memory.data
+
+	dbSeverity, found := data.severities[name]
+	if !found {
+		return nil, nil, ErrNotFound
+	}
+
+	updatedSeverity := cloneSeverity(&dbSeverity)
+	changes := cloneSeverity(severity)
+
+	if changes.DisplayName != nil {
+		if _, found := data.severities[*changes.DisplayName]; found && *changes.DisplayName != name {
+			return nil, nil, ErrDuplicate
+		}
+
+		updatedSeverity.DisplayName = changes.DisplayName
+	}
+
+	if changes.Value != nil {
+		if data.hasSeverityValue(name, changes.Value) {
+			return nil, nil, ErrDuplicate
+		}
+
+		updatedSeverity.Value = changes.Value
+	}
+
+	updatedSeverity.Version = nextVersion(dbSeverity.Version)
+
+	delete(data.severities, name)
+	data.severities[valueOf(updatedSeverity.DisplayName)] = updatedSeverity
+
+	before := cloneSeverity(&dbSeverity)
+	after := cloneSeverity(&updatedSeverity)
+
+	return &before, &after, nil
+}
+
+// DeleteSeverity implements [Repository].
+func (r *memoryRepository) DeleteSeverity(name string) (*DbDef.Severity, error) {
+	defer r.write()()
+
+	dbSeverity, found := r.memory.data.severities[name]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	delete(r.memory.data.severities, name)
+
+	before := cloneSeverity(&dbSeverity)
+
+	return &before, nil
+}
+
+// CurrentPhaseGeneration implements [Repository].
+func (r *memoryRepository) CurrentPhaseGeneration() (int, error) {
+	defer r.read()()
+
+	generation := 0
+
+	for key := range r.memory.data.phases {
+		generation = max(generation, key.generation)
+	}
+
+	return generation, nil
+}
+
+// GetPhase implements [Repository].
+func (r *memoryRepository) GetPhase(generation, order int) (*DbDef.Phase, error) {
+	defer r.read()()
+
+	phase, found := r.memory.data.phases[phaseKey{generation, order}]
+	if !found {
+		return nil, ErrNotFound
+	}
+
+	cloned := clonePhase(&phase)
+
+	return &cloned, nil
+}
+
+// ListPhases implements [Repository].
+func (r *memoryRepository) ListPhases(generation int) ([]*DbDef.Phase, error) {
+	defer r.read()()
+
+	phases := []*DbDef.Phase{}
+
+	for key, phase := range r.memory.data.phases {
+		if key.generation == generation {
+			cloned := clonePhase(&phase)
+			phases = append(phases, &cloned)
+		}
+	}
+
+	slices.SortFunc(phases, func(a, b *DbDef.Phase) int {
+		return cmp.Compare(valueOf(a.Order), valueOf(b.Order))
+	})
+
+	return phases, nil
+}
+
+// CreatePhases implements [Repository].
+func (r *memoryRepository) CreatePhases(phases []DbDef.Phase) error {
+	defer r.write()()
+
+	created := make(map[phaseKey]DbDef.Phase, len(phases))
+
+	for _, phase := range phases {
+		key := phaseKey{valueOf(phase.Generation), valueOf(phase.Order)}
+
+		_, found := r.memory.data.phases[key]
+		_, createdBefore := created[key]
+
+		if found || createdBefore {
+			return ErrDuplicate
+		}
+
+		created[key] = clonePhase(&phase)
+	}
+
+	maps.Copy(r.memory.data.phases, created)
+
+	return nil
+}
+
+// RecordAudit implements [Repository].
+func (r *memoryRepository) RecordAudit(auditEntry *DbDef.AuditEntry) error {
+	defer r.write()()
+
+	auditEntry.ID = uint64(len(r.memory.data.auditEntries)) + 1
+	r.memory.data.auditEntries = append(r.memory.data.auditEntries, *auditEntry)
+
+	return nil
+}
+
+// PublishEvent implements [Repository].
+func (r *memoryRepository) PublishEvent(event *DbDef.Event) error {
+	defer r.write()()
+
+	// IDs of purged events are not reused.
+	r.memory.data.lastEventID++
+	event.ID = r.memory.data.lastEventID
+	r.memory.data.events = append(r.memory.data.events, *event)
+
+	return nil
+}
@@ -0,0 +1,824 @@
+package storage_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/storage"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Memory", func() {
+	var (
+		ctx   = context.Background()
+		store *storage.Memory
+		repo  storage.Repository
+
+		now = time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+		componentID  db.ID
+		impactTypeID db.ID
+
+		newIncident = func(began time.Time, ended *time.Time) *db.Incident {
+			return &db.Incident{
+				DisplayName: test.Ptr("Disk failure"),
+				BeganAt:     &began,
+				EndedAt:     ended,
+				Phase:       &db.Phase{Generation: test.Ptr(1), Order: test.Ptr(0)},
+				Affects: &[]db.Impact{
+					{ComponentID: &componentID, ImpactTypeID: &impactTypeID, Severity: test.Ptr(50)},
+				},
+			}
+		}
+	)
+
+	BeforeEach(func() {
+		store = storage.NewMemory()
+		repo = store.WithContext(ctx)
+
+		component := &db.Component{DisplayName: test.Ptr("Storage"), Labels: &db.Labels{"region": "west"}}
+		Ω(repo.CreateComponent(component)).Should(Succeed())
+		componentID = component.ID
+
+		impactType := &db.ImpactType{DisplayName: test.Ptr("Performance degration")}
+		Ω(repo.CreateImpactType(impactType)).Should(Succeed())
+		impactTypeID = impactType.ID
+
+		Ω(repo.CreatePhases([]db.Phase{
+			{Name: test.Ptr("Investigation"), Generation: test.Ptr(1), Order: test.Ptr(0)},
+			{Name: test.Ptr("Resolved"), Generation: test.Ptr(1), Order: test.Ptr(1)},
+		})).Should(Succeed())
+	})
+
+	Describe("Components", func() {
+		It("should assign an ID and return copies", func() {
+			// Act
+			component, err := repo.GetComponent(componentID, nil)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(component.ID).ShouldNot(Equal(uuid.Nil))
+			Ω(*component.DisplayName).Should(Equal("Storage"))
+			Ω(*component.ActivelyAffectedBy).Should(BeEmpty())
+
+			(*component.Labels)["region"] = "east"
+
+			stored, err := repo.GetComponent(componentID, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*stored.Labels).Should(HaveKeyWithValue("region", "west"))
+		})
+
+		It("should only change set fields on update", func() {
+			// Act
+			before, after, err := repo.UpdateComponent(&db.Component{
+				Model:       db.Model{ID: componentID},
+				DisplayName: test.Ptr("Object storage"),
+			})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*before.DisplayName).Should(Equal("Storage"))
+			Ω(*after.DisplayName).Should(Equal("Object storage"))
+			Ω(*after.Labels).Should(HaveKeyWithValue("region", "west"))
+		})
+
+		It("should increment the version on update", func() {
+			// Act
+			before, after, err := repo.UpdateComponent(&db.Component{
+				Model:   db.Model{ID: componentID},
+				Version: test.Ptr(7),
+			})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*before.Version).Should(Equal(1))
+			Ω(*after.Version).Should(Equal(2))
+		})
+
+		It("should resolve impacts of active incidents", func() {
+			// Arrange
+			Ω(repo.CreateIncident(newIncident(now.Add(-time.Hour), nil))).Should(Succeed())
+			Ω(repo.CreateIncident(newIncident(now.Add(-2*time.Hour), test.Ptr(now.Add(-time.Hour))))).Should(Succeed())
+
+			// Act
+			components, _, err := repo.ListComponents(nil, nil, storage.Page{})
+			Ω(err).ShouldNot(HaveOccurred())
+			past, err := repo.GetComponent(componentID, test.Ptr(now.Add(-90*time.Minute)))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Assert
+			Ω(components).Should(HaveLen(1))
+			Ω(*components[0].ActivelyAffectedBy).Should(HaveLen(1))
+			Ω(*past.ActivelyAffectedBy).Should(HaveLen(1))
+		})
+
+		It("should list components matching the selector", func() {
+			// Arrange
+			Ω(repo.CreateComponent(&db.Component{DisplayName: test.Ptr("Network")})).Should(Succeed())
+
+			selector, err := db.ParseSelector("region in (west,east)")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			components, _, err := repo.ListComponents(nil, selector, storage.Page{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(components).Should(HaveLen(1))
+			Ω(components[0].ID).Should(Equal(componentID))
+		})
+
+		It("should list impacts of incidents active in the period by beginning", func() {
+			// Arrange
+			ongoing := newIncident(now.Add(-time.Hour), nil)
+			ended := newIncident(now.Add(-3*time.Hour), test.Ptr(now.Add(-2*time.Hour)))
+			endedBefore := newIncident(now.Add(-6*time.Hour), test.Ptr(now.Add(-5*time.Hour)))
+
+			for _, incident := range []*db.Incident{ongoing, ended, endedBefore} {
+				Ω(repo.CreateIncident(incident)).Should(Succeed())
+			}
+
+			// Act
+			impacts, err := repo.ListComponentImpacts(componentID, now.Add(-4*time.Hour), now)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(impacts).Should(HaveLen(2))
+			Ω(impacts[0].Incident.ID).Should(Equal(ended.ID))
+			Ω(impacts[1].Incident.ID).Should(Equal(ongoing.ID))
+			Ω(*impacts[1].Severity).Should(Equal(50))
+		})
+
+		It("should refuse deleting referenced components", func() {
+			// Arrange
+			Ω(repo.CreateIncident(newIncident(now, nil))).Should(Succeed())
+
+			// Act
+			_, err := repo.DeleteComponent(componentID, "test")
+
+			// Assert
+			Ω(err).Should(MatchError(storage.ErrReferenced))
+		})
+
+		It("should return not found for unknown components", func() {
+			// Act
+			_, err := repo.DeleteComponent(uuid.New(), "test")
+
+			// Assert
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+		})
+	})
+
+	Describe("Dependencies", func() {
+		var otherID db.ID
+
+		BeforeEach(func() {
+			other := &db.Component{DisplayName: test.Ptr("Network")}
+			Ω(repo.CreateComponent(other)).Should(Succeed())
+			otherID = other.ID
+		})
+
+		It("should create, list and delete dependencies", func() {
+			// Act
+			err := repo.CreateComponentDependency(&db.ComponentDependency{ComponentID: &componentID, DependsOnID: &otherID})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			dependencies, err := repo.ListComponentDependencies()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(dependencies).Should(HaveLen(1))
+			Ω(*dependencies[0].DependsOnID).Should(Equal(otherID))
+
+			Ω(repo.CreateComponentDependency(&db.ComponentDependency{ComponentID: &componentID, DependsOnID: &otherID})).
+				Should(MatchError(storage.ErrDuplicate))
+
+			_, err = repo.DeleteComponentDependency(componentID, otherID)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = repo.DeleteComponentDependency(componentID, otherID)
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+		})
+
+		It("should refuse unknown components", func() {
+			// Act
+			err := repo.CreateComponentDependency(&db.ComponentDependency{
+				ComponentID: &componentID,
+				DependsOnID: test.Ptr(uuid.New()),
+			})
+
+			// Assert
+			Ω(err).Should(MatchError(storage.ErrReferenced))
+		})
+
+		It("should hide dependencies of deleted components, until they are restored", func() {
+			// Arrange
+			Ω(repo.CreateComponentDependency(&db.ComponentDependency{ComponentID: &componentID, DependsOnID: &otherID})).
+				Should(Succeed())
+
+			// Act
+			_, err := repo.DeleteComponent(otherID, "test")
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.ListComponentDependencies()).Should(BeEmpty())
+			Ω(repo.IncludeDeleted().ListComponentDependencies()).Should(HaveLen(1))
+
+			_, err = repo.RestoreComponent(otherID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.ListComponentDependencies()).Should(HaveLen(1))
+		})
+
+		It("should remove dependencies with their purged components", func() {
+			// Arrange
+			Ω(repo.CreateComponentDependency(&db.ComponentDependency{ComponentID: &componentID, DependsOnID: &otherID})).
+				Should(Succeed())
+
+			_, err := repo.DeleteComponent(otherID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			_, err = repo.PurgeDeleted(time.Now().Add(time.Minute))
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.IncludeDeleted().ListComponentDependencies()).Should(BeEmpty())
+		})
+	})
+
+	Describe("ComponentGroups", func() {
+		var parent *db.ComponentGroup
+
+		BeforeEach(func() {
+			parent = &db.ComponentGroup{
+				DisplayName: test.Ptr("Infrastructure"),
+				Order:       test.Ptr(0),
+				Members:     &[]db.ComponentGroupMember{{ComponentID: &componentID}},
+			}
+			Ω(repo.CreateComponentGroup(parent)).Should(Succeed())
+		})
+
+		It("should list groups by order with their members", func() {
+			// Arrange
+			Ω(repo.CreateComponentGroup(&db.ComponentGroup{
+				DisplayName: test.Ptr("Compute"), ParentID: &parent.ID, Order: test.Ptr(1),
+			})).Should(Succeed())
+			Ω(repo.CreateComponentGroup(&db.ComponentGroup{
+				DisplayName: test.Ptr("Network"), ParentID: &parent.ID, Order: test.Ptr(0),
+			})).Should(Succeed())
+
+			// Act
+			groups, err := repo.ListComponentGroups()
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(groups).Should(HaveLen(3))
+			Ω(*groups[0].DisplayName).Should(Equal("Infrastructure"))
+			Ω(groups[0].ComponentIDs()).Should(Equal([]db.ID{componentID}))
+			Ω(*groups[1].DisplayName).Should(Equal("Network"))
+			Ω(*groups[2].DisplayName).Should(Equal("Compute"))
+		})
+
+		It("should refuse unknown references", func() {
+			// Act
+			errParent := repo.CreateComponentGroup(&db.ComponentGroup{
+				DisplayName: test.Ptr("Orphan"), ParentID: test.Ptr(uuid.New()), Order: test.Ptr(0),
+			})
+			errMember := repo.AddComponentGroupMember(&db.ComponentGroupMember{
+				GroupID: &parent.ID, ComponentID: test.Ptr(uuid.New()),
+			})
+
+			// Assert
+			Ω(errParent).Should(MatchError(storage.ErrReferenced))
+			Ω(errMember).Should(MatchError(storage.ErrReferenced))
+		})
+
+		It("should add and remove members", func() {
+			// Arrange
+			member := &db.ComponentGroupMember{GroupID: &parent.ID, ComponentID: &componentID}
+
+			// Act
+			errDuplicate := repo.AddComponentGroupMember(member)
+			_, errRemove := repo.RemoveComponentGroupMember(parent.ID, componentID)
+			_, errMissing := repo.RemoveComponentGroupMember(parent.ID, componentID)
+
+			// Assert
+			Ω(errDuplicate).Should(MatchError(storage.ErrDuplicate))
+			Ω(errRemove).ShouldNot(HaveOccurred())
+			Ω(errMissing).Should(MatchError(storage.ErrNotFound))
+			Ω(repo.GetComponentGroup(parent.ID)).Should(HaveField("Members", HaveValue(BeEmpty())))
+		})
+
+		It("should refuse to delete groups with nested groups", func() {
+			// Arrange
+			child := &db.ComponentGroup{DisplayName: test.Ptr("Compute"), ParentID: &parent.ID, Order: test.Ptr(0)}
+			Ω(repo.CreateComponentGroup(child)).Should(Succeed())
+
+			// Act
+			_, errParent := repo.DeleteComponentGroup(parent.ID)
+			_, errChild := repo.DeleteComponentGroup(child.ID)
+
+			// Assert
+			Ω(errParent).Should(MatchError(storage.ErrReferenced))
+			Ω(errChild).ShouldNot(HaveOccurred())
+		})
+
+		It("should hide memberships of deleted components, until they are restored", func() {
+			// Act
+			_, err := repo.DeleteComponent(componentID, "test")
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.GetComponentGroup(parent.ID)).Should(HaveField("Members", HaveValue(BeEmpty())))
+			Ω(repo.IncludeDeleted().GetComponentGroup(parent.ID)).Should(HaveField("Members", HaveValue(HaveLen(1))))
+
+			_, err = repo.RestoreComponent(componentID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.GetComponentGroup(parent.ID)).Should(HaveField("Members", HaveValue(HaveLen(1))))
+		})
+	})
+
+	Describe("Incidents", func() {
+		It("should resolve impacts, phase and updates", func() {
+			// Arrange
+			incident := newIncident(now, nil)
+			Ω(repo.CreateIncident(incident)).Should(Succeed())
+			Ω(repo.CreateIncidentUpdate(&db.IncidentUpdate{
+				IncidentID: &incident.ID, Order: test.Ptr(0), DisplayName: test.Ptr("Replacing disk"),
+			})).Should(Succeed())
+
+			// Act
+			stored, err := repo.GetIncident(incident.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*stored.PhaseGeneration).Should(Equal(1))
+			Ω(*stored.Phase.Name).Should(Equal("Investigation"))
+			Ω(*stored.Affects).Should(HaveLen(1))
+			Ω(*(*stored.Affects)[0].Component.DisplayName).Should(Equal("Storage"))
+			Ω(*stored.Updates).Should(HaveLen(1))
+			Ω(stored.ToAPIResponse().Id).Should(Equal(incident.ID))
+		})
+
+		It("should refuse unknown references", func() {
+			// Arrange
+			incident := newIncident(now, nil)
+			(*incident.Affects)[0].ImpactTypeID = test.Ptr(uuid.New())
+
+			// Act
+			err := repo.CreateIncident(incident)
+
+			// Assert
+			Ω(err).Should(MatchError(storage.ErrReferenced))
+		})
+
+		It("should list incidents overlapping the time range", func() {
+			// Arrange
+			Ω(repo.CreateIncident(newIncident(now.Add(-3*time.Hour), test.Ptr(now.Add(-2*time.Hour))))).Should(Succeed())
+			Ω(repo.CreateIncident(newIncident(now.Add(-time.Hour), test.Ptr(now.Add(time.Hour))))).Should(Succeed())
+			Ω(repo.CreateIncident(newIncident(now, nil))).Should(Succeed())
+			Ω(repo.CreateIncident(newIncident(now.Add(2*time.Hour), nil))).Should(Succeed())
+
+			// Act
+			incidents, next, err := repo.ListIncidents(now.Add(-90*time.Minute), now.Add(90*time.Minute), nil, nil, storage.Page{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(next).Should(BeNil())
+			Ω(incidents).Should(HaveLen(2))
+			Ω(*incidents[0].BeganAt).Should(Equal(now))
+			Ω(*incidents[1].BeganAt).Should(Equal(now.Add(-time.Hour)))
+		})
+
+		It("should list incidents affecting components matching the selector", func() {
+			// Arrange
+			Ω(repo.CreateIncident(newIncident(now, nil))).Should(Succeed())
+
+			west, err := db.ParseSelector("region=west")
+			Ω(err).ShouldNot(HaveOccurred())
+			east, err := db.ParseSelector("region=east")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			westIncidents, _, err := repo.ListIncidents(now, now, nil, west, storage.Page{})
+			Ω(err).ShouldNot(HaveOccurred())
+			eastIncidents, _, err := repo.ListIncidents(now, now, nil, east, storage.Page{})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Assert
+			Ω(westIncidents).Should(HaveLen(1))
+			Ω(eastIncidents).Should(BeEmpty())
+		})
+
+		It("should page through incidents latest first", func() {
+			// Arrange
+			for hour := range 5 {
+				Ω(repo.CreateIncident(newIncident(now.Add(time.Duration(-hour)*time.Hour), nil))).Should(Succeed())
+			}
+
+			var beganAt []time.Time
+
+			page := storage.Page{Limit: 2}
+
+			// Act
+			for pages := 1; ; pages++ {
+				incidents, next, err := repo.ListIncidents(now.Add(-24*time.Hour), now, nil, nil, page)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(len(incidents)).Should(BeNumerically("<=", 2))
+
+				for _, incident := range incidents {
+					beganAt = append(beganAt, *incident.BeganAt)
+				}
+
+				if next == nil {
+					Ω(pages).Should(Equal(3))
+
+					break
+				}
+
+				page.After, err = storage.ParseCursor(next.String())
+				Ω(err).ShouldNot(HaveOccurred())
+			}
+
+			// Assert
+			Ω(beganAt).Should(HaveLen(5))
+			Ω(beganAt).Should(BeEquivalentTo([]time.Time{
+				now, now.Add(-time.Hour), now.Add(-2 * time.Hour), now.Add(-3 * time.Hour), now.Add(-4 * time.Hour),
+			}))
+		})
+
+		It("should refuse cursors of other listings", func() {
+			// Arrange
+			Ω(repo.CreateComponent(&db.Component{DisplayName: test.Ptr("Network")})).Should(Succeed())
+
+			_, next, err := repo.ListComponents(nil, nil, storage.Page{Limit: 1})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(next).ShouldNot(BeNil())
+
+			// Act
+			_, _, err = repo.ListIncidents(now, now, nil, nil, storage.Page{Limit: 1, After: next})
+
+			// Assert
+			Ω(err).Should(MatchError(storage.ErrInvalidCursor))
+		})
+
+		It("should replace impacts on update", func() {
+			// Arrange
+			incident := newIncident(now, nil)
+			Ω(repo.CreateIncident(incident)).Should(Succeed())
+
+			// Act
+			before, after, err := repo.UpdateIncident(&db.Incident{
+				Model:   db.Model{ID: incident.ID},
+				EndedAt: test.Ptr(now.Add(time.Hour)),
+				Affects: &[]db.Impact{},
+			})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(before.EndedAt).Should(BeNil())
+			Ω(*before.Affects).Should(HaveLen(1))
+			Ω(*after.EndedAt).Should(Equal(now.Add(time.Hour)))
+			Ω(*after.DisplayName).Should(Equal("Disk failure"))
+			Ω(*after.Affects).Should(BeEmpty())
+		})
+
+		It("should hide updates of deleted incidents", func() {
+			// Arrange
+			incident := newIncident(now, nil)
+			Ω(repo.CreateIncident(incident)).Should(Succeed())
+			Ω(repo.CreateIncidentUpdate(&db.IncidentUpdate{
+				IncidentID: &incident.ID, Order: test.Ptr(0),
+			})).Should(Succeed())
+
+			// Act
+			_, err := repo.DeleteIncident(incident.ID, "test")
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.ListIncidentUpdates(incident.ID, storage.Page{})).Should(BeEmpty())
+
+			_, err = repo.GetIncidentUpdate(incident.ID, 0)
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+
+			_, err = repo.DeleteComponent(componentID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Describe("IncidentRevisions", func() {
+		var (
+			incident *db.Incident
+			between  time.Time
+		)
+
+		BeforeEach(func() {
+			incident = newIncident(now, nil)
+			Ω(repo.CreateIncident(incident)).Should(Succeed())
+
+			between = time.Now()
+
+			_, _, err := repo.UpdateIncident(&db.Incident{
+				Model:       db.Model{ID: incident.ID},
+				DisplayName: test.Ptr("Controller failure"),
+				Affects: &[]db.Impact{
+					{ComponentID: &componentID, ImpactTypeID: &impactTypeID, Severity: test.Ptr(90)},
+				},
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should record a revision on every change", func() {
+			// Act
+			revisions, err := repo.ListIncidentRevisions(incident.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(revisions).Should(HaveLen(2))
+			Ω(*revisions[0].Revision).Should(Equal(1))
+			Ω(*revisions[0].DisplayName).Should(Equal("Disk failure"))
+			Ω(*(*revisions[0].Affects)[0].Severity).Should(Equal(50))
+			Ω(*revisions[1].Revision).Should(Equal(2))
+			Ω(*revisions[1].DisplayName).Should(Equal("Controller failure"))
+			Ω(*(*revisions[1].Affects)[0].Severity).Should(Equal(90))
+		})
+
+		It("should list incidents by their revisions valid at the time", func() {
+			// Act
+			incidents, _, err := repo.ListIncidents(now, now, &between, nil, storage.Page{})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(incidents).Should(HaveLen(1))
+			Ω(*incidents[0].DisplayName).Should(Equal("Disk failure"))
+			Ω(*(*incidents[0].Affects)[0].Severity).Should(Equal(50))
+		})
+
+		It("should resolve impacts of components by the revisions valid at the time", func() {
+			// Act
+			previous, err := repo.GetComponent(componentID, &between)
+			Ω(err).ShouldNot(HaveOccurred())
+			current, err := repo.GetComponent(componentID, test.Ptr(time.Now()))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Assert
+			Ω(*(*previous.ActivelyAffectedBy)[0].Severity).Should(Equal(50))
+			Ω(*(*current.ActivelyAffectedBy)[0].Severity).Should(Equal(90))
+		})
+
+		It("should fall back to the first revision before any was recorded", func() {
+			// Act
+			component, err := repo.GetComponent(componentID, test.Ptr(now.Add(time.Minute)))
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*component.ActivelyAffectedBy).Should(HaveLen(1))
+			Ω(*(*component.ActivelyAffectedBy)[0].Severity).Should(Equal(50))
+		})
+
+		It("should hide revisions of deleted incidents", func() {
+			// Act
+			_, err := repo.DeleteIncident(incident.ID, "test")
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = repo.ListIncidentRevisions(incident.ID)
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+		})
+	})
+
+	Describe("Deletion", func() {
+		var incident *db.Incident
+
+		BeforeEach(func() {
+			incident = newIncident(now, nil)
+			Ω(repo.CreateIncident(incident)).Should(Succeed())
+		})
+
+		It("should hide deleted incidents and their impacts, unless included", func() {
+			// Act
+			_, err := repo.DeleteIncident(incident.ID, "test")
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.ListIncidents(now, now, nil, nil, storage.Page{})).Should(BeEmpty())
+			Ω(repo.GetComponent(componentID, nil)).Should(HaveField("ActivelyAffectedBy", HaveValue(BeEmpty())))
+
+			_, err = repo.GetIncident(incident.ID)
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+
+			deleted, err := repo.IncludeDeleted().GetIncident(incident.ID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(deleted.DeletedAt.Valid).Should(BeTrue())
+			Ω(*deleted.DeletedBy).Should(Equal("test"))
+			Ω(*deleted.Version).Should(Equal(2))
+			Ω(repo.IncludeDeleted().ListIncidents(now, now, nil, nil, storage.Page{})).Should(HaveLen(1))
+		})
+
+		It("should restore deleted incidents", func() {
+			// Arrange
+			_, err := repo.DeleteIncident(incident.ID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			restored, err := repo.RestoreIncident(incident.ID)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(restored.DeletedAt.Valid).Should(BeFalse())
+			Ω(restored.DeletedBy).Should(BeNil())
+			Ω(*restored.Affects).Should(HaveLen(1))
+			Ω(repo.GetComponent(componentID, nil)).Should(HaveField("ActivelyAffectedBy", HaveValue(HaveLen(1))))
+
+			_, err = repo.RestoreIncident(incident.ID)
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+		})
+
+		It("should hide and restore deleted components", func() {
+			// Arrange
+			_, err := repo.DeleteIncident(incident.ID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			_, err = repo.DeleteComponent(componentID, "admin")
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.ListComponents(nil, nil, storage.Page{})).Should(BeEmpty())
+			Ω(repo.IncludeDeleted().ListComponents(nil, nil, storage.Page{})).Should(HaveLen(1))
+
+			_, err = repo.GetComponent(componentID, nil)
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+
+			restored, err := repo.RestoreComponent(componentID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(restored.DeletedAt.Valid).Should(BeFalse())
+			Ω(*restored.Version).Should(Equal(3))
+		})
+
+		It("should refuse references to deleted components", func() {
+			// Arrange
+			_, err := repo.DeleteIncident(incident.ID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = repo.DeleteComponent(componentID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			_, errRestore := repo.RestoreIncident(incident.ID)
+			errCreate := repo.CreateIncident(newIncident(now, nil))
+			errMember := repo.CreateComponentGroup(&db.ComponentGroup{
+				DisplayName: test.Ptr("Storage"),
+				Members:     &[]db.ComponentGroupMember{{ComponentID: &componentID}},
+			})
+
+			// Assert
+			Ω(errRestore).Should(MatchError(storage.ErrReferenced))
+			Ω(errCreate).Should(MatchError(storage.ErrReferenced))
+			Ω(errMember).Should(MatchError(storage.ErrReferenced))
+		})
+
+		It("should purge resources deleted before the time", func() {
+			// Arrange
+			_, err := repo.DeleteIncident(incident.ID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = repo.DeleteComponent(componentID, "test")
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Act
+			notYet, errNotYet := repo.PurgeDeleted(now)
+			purged, errPurged := repo.PurgeDeleted(time.Now().Add(time.Minute))
+
+			// Assert
+			Ω(errNotYet).ShouldNot(HaveOccurred())
+			Ω(notYet).Should(BeZero())
+			Ω(errPurged).ShouldNot(HaveOccurred())
+			Ω(purged).Should(Equal(2))
+
+			_, err = repo.IncludeDeleted().GetIncident(incident.ID)
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+			_, err = repo.IncludeDeleted().GetComponent(componentID, nil)
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+		})
+	})
+
+	Describe("Severities", func() {
+		It("should keep names and values unique", func() {
+			// Arrange
+			Ω(repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr("minor"), Value: test.Ptr(33)})).Should(Succeed())
+			Ω(repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr("major"), Value: test.Ptr(66)})).Should(Succeed())
+
+			// Act
+			createErr := repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr("medium"), Value: test.Ptr(33)})
+			_, _, updateErr := repo.UpdateSeverity("major", &db.Severity{DisplayName: test.Ptr("minor"), Value: nil})
+
+			// Assert
+			Ω(createErr).Should(MatchError(storage.ErrDuplicate))
+			Ω(updateErr).Should(MatchError(storage.ErrDuplicate))
+		})
+
+		It("should rename severities", func() {
+			// Arrange
+			Ω(repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr("minor"), Value: test.Ptr(33)})).Should(Succeed())
+
+			// Act
+			_, after, err := repo.UpdateSeverity("minor", &db.Severity{DisplayName: test.Ptr("low"), Value: nil})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*after.Value).Should(Equal(33))
+			Ω(repo.GetSeverity("low")).Should(Equal(after))
+
+			_, err = repo.GetSeverity("minor")
+			Ω(err).Should(MatchError(storage.ErrNotFound))
+		})
+	})
+
+	Describe("Phases", func() {
+		It("should list the phases of a generation by order", func() {
+			// Act
+			generation, err := repo.CurrentPhaseGeneration()
+			Ω(err).ShouldNot(HaveOccurred())
+			phases, err := repo.ListPhases(generation)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(generation).Should(Equal(1))
+			Ω(phases).Should(HaveLen(2))
+			Ω(*phases[1].Name).Should(Equal("Resolved"))
+		})
+	})
+
+	Describe("Events", func() {
+		It("should purge events published before the time without reusing their IDs", func() {
+			// Arrange
+			old, err := db.NewEvent(db.EventComponentChanged, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			old.CreatedAt = time.Now().Add(-time.Hour)
+			Ω(repo.PublishEvent(old)).Should(Succeed())
+
+			recent, err := db.NewEvent(db.EventComponentChanged, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.PublishEvent(recent)).Should(Succeed())
+
+			// Act
+			purged, err := repo.PurgeEvents(time.Now().Add(-time.Minute))
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(purged).Should(Equal(1))
+			Ω(store.Events()).Should(ConsistOf(HaveField("ID", recent.ID)))
+
+			next, err := db.NewEvent(db.EventComponentChanged, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(repo.PublishEvent(next)).Should(Succeed())
+			Ω(next.ID).Should(Equal(uint64(3)))
+		})
+	})
+
+	Describe("Transaction", func() {
+		It("should apply all changes on success", func() {
+			// Act
+			err := store.Transaction(ctx, func(repo storage.Repository) error {
+				event, err := db.NewEvent(db.EventComponentChanged, nil)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(repo.PublishEvent(event)).Should(Succeed())
+				Ω(event.ID).Should(Equal(uint64(1)))
+
+				return repo.CreateSeverity(&db.Severity{DisplayName: test.Ptr("minor"), Value: test.Ptr(33)})
+			})
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(store.Events()).Should(HaveLen(1))
+			severities, _, err := repo.ListSeverities(storage.Page{})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(severities).Should(HaveLen(1))
+		})
+
+		It("should restore the previous state on error", func() {
+			// Act
+			err := store.Transaction(ctx, func(repo storage.Repository) error {
+				auditEntry, err := db.NewAuditEntry("test", db.AuditOperationDelete, db.AuditTargetComponent, "", nil, nil)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(repo.RecordAudit(auditEntry)).Should(Succeed())
+
+				_, err = repo.DeleteImpactType(impactTypeID)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				return test.ErrTestError
+			})
+
+			// Assert
+			Ω(err).Should(Equal(test.ErrTestError))
+			Ω(store.AuditEntries()).Should(BeEmpty())
+			Ω(repo.GetImpactType(impactTypeID)).ShouldNot(BeNil())
+		})
+	})
+})
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is an error, raised when a cursor is malformed or does not belong to the listing.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page selects a page of a listing.
+// Listings are sorted in a stable order, so following the cursors visits every item once.
+type Page struct {
+	// Limit is the highest number of items on the page, 0 lists all items.
+	Limit int
+	// After starts the page after the item at the cursor, nil starts at the first item.
+	After *Cursor
+}
+
+// Cursor is the position of an item in the sort order of its listing.
+// It is opaque to clients, see [ParseCursor] and [Cursor.String].
+type Cursor struct {
+	// Key is the sort key of the item, e.g. the time an incident began.
+	Key string `json:"k"`
+	// ID tells apart items with equal keys, empty for listings with unique keys.
+	ID string `json:"i,omitempty"`
+}
+
+// ParseCursor decodes a cursor from its string form.
+func ParseCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	var cursor Cursor
+
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return &cursor, nil
+}
+
+// String encodes the cursor to be passed to clients.
+func (c Cursor) String() string {
+	data, _ := json.Marshal(c) //nolint:errchkjson
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (c Cursor) intKey() (int, error) {
+	key, err := strconv.Atoi(c.Key)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return key, nil
+}
+
+func (c Cursor) timeKey() (time.Time, error) {
+	key, err := time.Parse(time.RFC3339Nano, c.Key)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return key, nil
+}
+
+func (c Cursor) uuid() (uuid.UUID, error) {
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return id, nil
+}
+
+// paginate cuts the sorted items after the first page and returns the cursor of the next page, nil on the last page.
+func paginate[T any](items []T, page Page, cursor func(item T) *Cursor) ([]T, *Cursor) {
+	if page.Limit <= 0 || len(items) <= page.Limit {
+		return items, nil
+	}
+
+	items = items[:page.Limit]
+
+	return items, cursor(items[len(items)-1])
+}
+
+// fetchLimit is the number of items to fetch for a page, one more than its limit to tell, if another page follows.
+func fetchLimit(page Page) int {
+	if page.Limit <= 0 {
+		return -1
+	}
+
+	return page.Limit + 1
+}
+
+// Components are sorted by name and ID.
+func componentCursor(component *DbDef.Component) *Cursor {
+	return &Cursor{Key: valueOf(component.DisplayName), ID: component.ID.String()}
+}
+
+// Incidents are sorted by the time they began and ID, latest first.
+func incidentCursor(incident *DbDef.Incident) *Cursor {
+	return &Cursor{Key: valueOf(incident.BeganAt).UTC().Format(time.RFC3339Nano), ID: incident.ID.String()}
+}
+
+// Incident updates are sorted by order.
+func incidentUpdateCursor(incidentUpdate *DbDef.IncidentUpdate) *Cursor {
+	return &Cursor{Key: strconv.Itoa(valueOf(incidentUpdate.Order)), ID: ""}
+}
+
+// Impact types are sorted by name and ID.
+func impactTypeCursor(impactType *DbDef.ImpactType) *Cursor {
+	return &Cursor{Key: valueOf(impactType.DisplayName), ID: impactType.ID.String()}
+}
+
+// Severities are sorted by value.
+func severityCursor(severity *DbDef.Severity) *Cursor {
+	return &Cursor{Key: strconv.Itoa(valueOf(severity.Value)), ID: ""}
+}
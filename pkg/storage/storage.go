@@ -0,0 +1,189 @@
+// Package storage defines the repository of the status page resources and its implementations.
+// [Gorm] stores the resources in PostgreSQL or SQLite,
+// [Memory] keeps them in memory for tests, demos and volatile instances.
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+)
+
+var (
+	// ErrNotFound is an error, raised when a resource does not exist.
+	ErrNotFound = errors.New("resource not found")
+	// ErrDuplicate is an error, raised when a resource collides with a unique attribute of another one.
+	ErrDuplicate = errors.New("duplicate resource")
+	// ErrReferenced is an error, raised when a reference between resources would be broken.
+	// It covers references to missing resources as well as deleting resources still referenced.
+	ErrReferenced = errors.New("broken reference")
+	// ErrConflict is an error, raised when a resource was changed concurrently between loading and writing it.
+	ErrConflict = errors.New("concurrent change")
+)
+
+// Storage opens a [Repository] for a request.
+type Storage interface {
+	// WithContext returns a repository bound to the context, each call is applied on its own.
+	WithContext(ctx context.Context) Repository
+	// Transaction runs fn with a repository, whose changes are only applied, if fn returns without error.
+	// The error of fn is returned unwrapped.
+	Transaction(ctx context.Context, fn func(repo Repository) error) error
+}
+
+// Repository reads and writes the resources of the status page.
+// Lookups of missing resources return [ErrNotFound].
+// Updates and deletions return the affected resource, updates the states before and after the change.
+// Versioned resources increment their version on each update, the database increments it on updates not setting it,
+// writes fail with [ErrConflict], if the loaded version was changed concurrently.
+// The revision of an incident valid at a time is the latest one recorded until then,
+// or its first one for incidents recorded afterwards.
+// Listings return a [Page] of the resources and the cursor of the next page, nil on the last page.
+// Deleted incidents and components are kept until they are purged, but are not found, unless deleted ones are included.
+// Impacts of deleted incidents are never active.
+type Repository interface { //nolint:interfacebloat
+	// IncludeDeleted returns a repository, which also finds deleted incidents and components.
+	IncludeDeleted() Repository
+	// PurgeDeleted removes incidents and components deleted before the time and returns the number of removed ones.
+	// Deleted components are kept, until the deleted incidents referencing them are removed.
+	PurgeDeleted(deletedBefore time.Time) (int, error)
+	// PurgeEvents removes events published before the time and returns the number of removed ones.
+	// Events with pending or dead webhook deliveries are kept, until the deliveries are done or removed.
+	PurgeEvents(publishedBefore time.Time) (int, error)
+
+	// ListComponents lists components matching the selector by name with their impacts active at the time,
+	// or currently if nil. Impacts at a time are evaluated by the incident revisions valid at the time.
+	ListComponents(at *time.Time, selector DbDef.Selector, page Page) ([]*DbDef.Component, *Cursor, error)
+	// GetComponent retrieves a component with its impacts active at the time, or currently if nil.
+	// Impacts at a time are evaluated by the incident revisions valid at the time.
+	GetComponent(componentID DbDef.ID, at *time.Time) (*DbDef.Component, error)
+	// CreateComponent creates the component and sets its ID, if unset.
+	CreateComponent(component *DbDef.Component) error
+	// UpdateComponent changes the set fields of the component identified by its ID.
+	UpdateComponent(component *DbDef.Component) (*DbDef.Component, *DbDef.Component, error)
+	// DeleteComponent marks a component without impacts of undeleted incidents as deleted by the actor.
+	// Its dependencies and group memberships are kept, but not found, until it is restored or purged.
+	DeleteComponent(componentID DbDef.ID, deletedBy string) (*DbDef.Component, error)
+	// RestoreComponent unmarks a deleted component and returns it, components not deleted are not found.
+	RestoreComponent(componentID DbDef.ID) (*DbDef.Component, error)
+	// ListComponentImpacts lists the impacts on a component by incidents active between start and end,
+	// with their incident.
+	ListComponentImpacts(componentID DbDef.ID, start, end time.Time) ([]*DbDef.Impact, error)
+
+	// LockComponentDependencies serializes transactions changing dependencies until their end,
+	// so the dependencies checked for cycles don't change concurrently.
+	LockComponentDependencies() error
+	// ListComponentDependencies lists all dependencies between components found by the repository.
+	ListComponentDependencies() ([]*DbDef.ComponentDependency, error)
+	// CreateComponentDependency creates the dependency between existing components.
+	CreateComponentDependency(dependency *DbDef.ComponentDependency) error
+	// DeleteComponentDependency deletes the dependency of a component on another one.
+	DeleteComponentDependency(componentID, dependsOnID DbDef.ID) (*DbDef.ComponentDependency, error)
+
+	// ListComponentGroups lists all component groups by order with their members.
+	ListComponentGroups() ([]*DbDef.ComponentGroup, error)
+	// GetComponentGroup retrieves a component group with its members.
+	GetComponentGroup(groupID DbDef.ID) (*DbDef.ComponentGroup, error)
+	// CreateComponentGroup creates the component group with its members and sets its ID, if unset.
+	CreateComponentGroup(group *DbDef.ComponentGroup) error
+	// UpdateComponentGroup replaces the fields of the component group identified by its ID, except its members.
+	UpdateComponentGroup(group *DbDef.ComponentGroup) (*DbDef.ComponentGroup, *DbDef.ComponentGroup, error)
+	// DeleteComponentGroup deletes a component group without nested groups, its memberships are deleted with it.
+	DeleteComponentGroup(groupID DbDef.ID) (*DbDef.ComponentGroup, error)
+	// AddComponentGroupMember adds an existing component to an existing group.
+	AddComponentGroupMember(member *DbDef.ComponentGroupMember) error
+	// RemoveComponentGroupMember removes a component from a group.
+	RemoveComponentGroupMember(groupID, componentID DbDef.ID) (*DbDef.ComponentGroupMember, error)
+
+	// ListIncidents lists incidents active between start and end, latest first, with their impacts, phase and updates.
+	// Only incidents affecting any component matching the selector are listed.
+	// With a time, incidents are evaluated by their revisions valid at the time instead of their current state.
+	ListIncidents(
+		start, end time.Time,
+		at *time.Time,
+		selector DbDef.Selector,
+		page Page,
+	) ([]*DbDef.Incident, *Cursor, error)
+	// GetIncident retrieves an incident with its impacts, phase and updates.
+	GetIncident(incidentID DbDef.ID) (*DbDef.Incident, error)
+	// CreateIncident creates the incident with its impacts and sets its ID, if unset.
+	// The created incident is recorded as its first revision.
+	CreateIncident(incident *DbDef.Incident) error
+	// UpdateIncident changes the set fields of the incident identified by its ID.
+	// Set impacts replace the impacts of the incident. The updated incident is recorded as its next revision.
+	UpdateIncident(incident *DbDef.Incident) (*DbDef.Incident, *DbDef.Incident, error)
+	// DeleteIncident marks an incident as deleted by the actor, its impacts, updates and revisions are kept.
+	// Alerts linked to the incident are resolved, so they open a new incident when firing again.
+	DeleteIncident(incidentID DbDef.ID, deletedBy string) (*DbDef.Incident, error)
+	// RestoreIncident unmarks a deleted incident and returns it, incidents not deleted are not found.
+	// Incidents impacting deleted components are not restored.
+	RestoreIncident(incidentID DbDef.ID) (*DbDef.Incident, error)
+	// ListIncidentRevisions lists the revisions of an incident with their impacts, oldest first.
+	ListIncidentRevisions(incidentID DbDef.ID) ([]*DbDef.IncidentRevision, error)
+
+	// ListIncidentUpdates lists the updates of an incident by order.
+	ListIncidentUpdates(incidentID DbDef.ID, page Page) ([]*DbDef.IncidentUpdate, *Cursor, error)
+	// GetIncidentUpdate retrieves an update of an incident by its order.
+	GetIncidentUpdate(incidentID DbDef.ID, order int) (*DbDef.IncidentUpdate, error)
+	// HighestIncidentUpdateOrder returns the highest order of the updates of an incident, -1 without updates.
+	HighestIncidentUpdateOrder(incidentID DbDef.ID) (int, error)
+	// CreateIncidentUpdate creates the update of an incident.
+	CreateIncidentUpdate(incidentUpdate *DbDef.IncidentUpdate) error
+	// UpdateIncidentUpdate changes the set fields of the update identified by its incident and order.
+	UpdateIncidentUpdate(incidentUpdate *DbDef.IncidentUpdate) (*DbDef.IncidentUpdate, *DbDef.IncidentUpdate, error)
+	// DeleteIncidentUpdate deletes an update of an incident by its order.
+	DeleteIncidentUpdate(incidentID DbDef.ID, order int) (*DbDef.IncidentUpdate, error)
+
+	// ListImpactTypes lists impact types by name.
+	ListImpactTypes(page Page) ([]*DbDef.ImpactType, *Cursor, error)
+	// GetImpactType retrieves an impact type.
+	GetImpactType(impactTypeID DbDef.ID) (*DbDef.ImpactType, error)
+	// CreateImpactType creates the impact type and sets its ID, if unset.
+	CreateImpactType(impactType *DbDef.ImpactType) error
+	// UpdateImpactType changes the set fields of the impact type identified by its ID.
+	UpdateImpactType(impactType *DbDef.ImpactType) (*DbDef.ImpactType, *DbDef.ImpactType, error)
+	// DeleteImpactType deletes an impact type without impacts.
+	DeleteImpactType(impactTypeID DbDef.ID) (*DbDef.ImpactType, error)
+
+	// ListSeverities lists severities by value.
+	ListSeverities(page Page) ([]*DbDef.Severity, *Cursor, error)
+	// GetSeverity retrieves a severity by its name.
+	GetSeverity(name string) (*DbDef.Severity, error)
+	// CreateSeverity creates the severity, names and values are unique.
+	CreateSeverity(severity *DbDef.Severity) error
+	// UpdateSeverity changes the set fields of the severity identified by its name.
+	UpdateSeverity(name string, severity *DbDef.Severity) (*DbDef.Severity, *DbDef.Severity, error)
+	// DeleteSeverity deletes a severity by its name.
+	DeleteSeverity(name string) (*DbDef.Severity, error)
+
+	// CurrentPhaseGeneration returns the generation of the latest phase list, 0 without phase lists.
+	CurrentPhaseGeneration() (int, error)
+	// GetPhase retrieves a phase by its generation and order.
+	GetPhase(generation, order int) (*DbDef.Phase, error)
+	// ListPhases lists the phases of a generation by their order.
+	ListPhases(generation int) ([]*DbDef.Phase, error)
+	// CreatePhases creates the phases of a phase list.
+	CreatePhases(phases []DbDef.Phase) error
+
+	// RecordAudit records the audit entry and sets its ID.
+	RecordAudit(auditEntry *DbDef.AuditEntry) error
+	// PublishEvent publishes the event and sets its ID.
+	PublishEvent(event *DbDef.Event) error
+}
+
+// versionOf returns the stored version of a resource, unset versions count as the initial version 1.
+func versionOf(version *int) int {
+	if version == nil {
+		return 1
+	}
+
+	return *version
+}
+
+// nextVersion returns the version following the stored version of an updated resource.
+func nextVersion(version *int) *int {
+	next := versionOf(version) + 1
+
+	return &next
+}
@@ -0,0 +1,243 @@
+package subscription
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"net/url"
+	"slices"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/google/uuid"
+)
+
+const (
+	// timeFormat formats times in emails.
+	timeFormat = "2006-01-02 15:04 MST"
+	// confirmationSubject is the subject of emails confirming a subscription.
+	confirmationSubject = "Confirm your status page subscription"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+//nolint:gochecknoglobals
+var (
+	templateFuncs = map[string]any{"join": strings.Join}
+	textTemplates = textTemplate.Must(
+		textTemplate.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.txt.tmpl"),
+	)
+	htmlTemplates = htmlTemplate.Must(
+		htmlTemplate.New("").Funcs(templateFuncs).ParseFS(templateFS, "templates/*.html.tmpl"),
+	)
+)
+
+// ComposerConfig holds the settings of the [Composer].
+type ComposerConfig struct {
+	// PublicURL is the base URL of the API used in links of emails.
+	PublicURL string
+	// ConfirmTTL is the time a confirmation link is valid.
+	ConfirmTTL time.Duration
+}
+
+// Composer renders notifications to subscribers and verifies the tokens of their links.
+type Composer struct {
+	signer *Signer
+	conf   ComposerConfig
+}
+
+// NewComposer creates a new composer.
+func NewComposer(signer *Signer, conf ComposerConfig) *Composer {
+	conf.PublicURL = strings.TrimSuffix(conf.PublicURL, "/")
+
+	return &Composer{
+		signer: signer,
+		conf:   conf,
+	}
+}
+
+// confirmationData is rendered by the confirmation templates.
+type confirmationData struct {
+	ConfirmURL string
+	ExpiresAt  string
+}
+
+// incidentData is rendered by the incident templates.
+type incidentData struct {
+	Heading        string
+	Title          string
+	Description    string
+	Update         *updateData
+	BeganAt        string
+	EndedAt        string
+	Components     []string
+	IncidentURL    string
+	UnsubscribeURL string
+}
+
+// updateData describes an incident update in [incidentData].
+type updateData struct {
+	Title       string
+	Description string
+}
+
+// Confirmation renders the notification asking the subscriber to confirm the subscription.
+func (c *Composer) Confirmation(subscriber *DbDef.Subscriber, now time.Time) (*DbDef.Notification, error) {
+	expiresAt := now.Add(c.conf.ConfirmTTL)
+	token := c.signer.Sign(PurposeConfirm, subscriber.ID, expiresAt)
+
+	data := confirmationData{
+		ConfirmURL: c.conf.PublicURL + "/subscribers/confirm?token=" + url.QueryEscape(token),
+		ExpiresAt:  expiresAt.UTC().Format(timeFormat),
+	}
+
+	text, html, err := render("confirmation", data)
+	if err != nil {
+		return nil, err
+	}
+
+	return DbDef.NewNotification(subscriber, confirmationSubject, text, html, "", now), nil
+}
+
+// ConfirmationsValidSince returns the time, after which queued confirmations still link a valid token at now.
+func (c *Composer) ConfirmationsValidSince(now time.Time) time.Time {
+	return now.Add(-c.conf.ConfirmTTL)
+}
+
+// Incident renders the notification about the event of the incident.
+// The update is only set for events about incident updates.
+// Components of the impacts need to be loaded to name them.
+func (c *Composer) Incident(
+	subscriber *DbDef.Subscriber,
+	eventType DbDef.EventType,
+	incident *DbDef.Incident,
+	update *DbDef.IncidentUpdate,
+	now time.Time,
+) (*DbDef.Notification, error) {
+	unsubscribeURL := c.UnsubscribeURL(subscriber)
+
+	data := incidentData{
+		Heading:        heading(eventType, incident.IsMaintenance()),
+		Title:          valueOrEmpty(incident.DisplayName),
+		Description:    valueOrEmpty(incident.Description),
+		Update:         nil,
+		BeganAt:        formatTime(incident.BeganAt),
+		EndedAt:        formatTime(incident.EndedAt),
+		Components:     componentNames(incident),
+		IncidentURL:    c.conf.PublicURL + "/incidents/" + incident.ID.String(),
+		UnsubscribeURL: unsubscribeURL,
+	}
+
+	if update != nil {
+		data.Update = &updateData{
+			Title:       valueOrEmpty(update.DisplayName),
+			Description: valueOrEmpty(update.Description),
+		}
+	}
+
+	text, html, err := render("incident", data)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := data.Heading + ": " + data.Title
+	if data.Update != nil {
+		subject += " - " + data.Update.Title
+	}
+
+	return DbDef.NewNotification(subscriber, subject, text, html, unsubscribeURL, now), nil
+}
+
+// UnsubscribeURL creates the link cancelling the subscription. It does not expire.
+func (c *Composer) UnsubscribeURL(subscriber *DbDef.Subscriber) string {
+	token := c.signer.Sign(PurposeUnsubscribe, subscriber.ID, time.Time{})
+
+	return c.conf.PublicURL + "/subscribers/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+// VerifyConfirmation checks a confirmation token and returns the ID of the subscriber.
+func (c *Composer) VerifyConfirmation(token string, now time.Time) (uuid.UUID, error) {
+	return c.signer.Verify(token, PurposeConfirm, now)
+}
+
+// VerifyUnsubscribe checks an unsubscribe token and returns the ID of the subscriber.
+func (c *Composer) VerifyUnsubscribe(token string, now time.Time) (uuid.UUID, error) {
+	return c.signer.Verify(token, PurposeUnsubscribe, now)
+}
+
+// render executes the text and HTML template of the name with the data.
+func render(name string, data any) (string, string, error) {
+	var text, html bytes.Buffer
+
+	err := textTemplates.ExecuteTemplate(&text, name+".txt.tmpl", data)
+	if err != nil {
+		return "", "", fmt.Errorf("error rendering text body: %w", err)
+	}
+
+	err = htmlTemplates.ExecuteTemplate(&html, name+".html.tmpl", data)
+	if err != nil {
+		return "", "", fmt.Errorf("error rendering HTML body: %w", err)
+	}
+
+	return text.String(), html.String(), nil
+}
+
+// heading names the kind of the event.
+func heading(eventType DbDef.EventType, maintenance bool) string {
+	kind := "Incident"
+	if maintenance {
+		kind = "Maintenance"
+	}
+
+	switch eventType { //nolint:exhaustive
+	case DbDef.EventIncidentCreated:
+		if maintenance {
+			return "Maintenance announced"
+		}
+
+		return "New incident"
+	case DbDef.EventIncidentResolved:
+		return kind + " resolved"
+	default:
+		return kind + " updated"
+	}
+}
+
+// componentNames returns the sorted names of the affected components.
+func componentNames(incident *DbDef.Incident) []string {
+	names := []string{}
+
+	if incident.Affects == nil {
+		return names
+	}
+
+	for _, impact := range *incident.Affects {
+		if impact.Component != nil && impact.Component.DisplayName != nil {
+			names = append(names, *impact.Component.DisplayName)
+		}
+	}
+
+	slices.Sort(names)
+
+	return slices.Compact(names)
+}
+
+func formatTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+
+	return value.UTC().Format(timeFormat)
+}
+
+func valueOrEmpty[T ~string](value *T) string {
+	if value == nil {
+		return ""
+	}
+
+	return string(*value)
+}
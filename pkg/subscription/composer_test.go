@@ -0,0 +1,128 @@
+package subscription_test
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/subscription"
+	apiServerDefinition "github.com/SovereignCloudStack/status-page-openapi/pkg/api/server"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Composer", func() {
+	var (
+		now        = time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+		composer   *subscription.Composer
+		subscriber = db.Subscriber{ //nolint:exhaustruct
+			Email: test.Ptr("user@example.com"),
+			Model: db.Model{ID: uuid.MustParse("9e1b3c2a-5d4f-4a8b-8c7d-6e5f4a3b2c1d")},
+		}
+	)
+
+	BeforeEach(func() {
+		composer = subscription.NewComposer(
+			subscription.NewSigner("0123456789abcdef0123456789abcdef"),
+			subscription.ComposerConfig{PublicURL: "https://status.example.com/", ConfirmTTL: time.Hour},
+		)
+	})
+
+	Describe("Confirmation", func() {
+		It("should link a confirmation token expiring after the TTL", func() {
+			// Act
+			notification, err := composer.Confirmation(&subscriber, now)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(notification.Recipient).Should(Equal("user@example.com"))
+			Ω(notification.Status).Should(Equal(db.NotificationPending))
+			Ω(notification.UnsubscribeURL).Should(BeEmpty())
+			Ω(notification.TextBody).Should(ContainSubstring("The link expires at 2024-03-01 11:00 UTC."))
+
+			token := confirmationToken(notification.TextBody)
+
+			id, err := composer.VerifyConfirmation(token, now.Add(time.Hour))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(id).Should(Equal(subscriber.ID))
+
+			_, err = composer.VerifyConfirmation(token, now.Add(time.Hour+time.Second))
+			Ω(err).Should(MatchError(subscription.ErrExpiredToken))
+		})
+	})
+
+	Describe("ConfirmationsValidSince", func() {
+		It("should return the creation time of the oldest valid confirmation", func() {
+			// Act
+			since := composer.ConfirmationsValidSince(now)
+
+			// Assert
+			Ω(since).Should(Equal(now.Add(-time.Hour)))
+		})
+	})
+
+	Describe("Incident", func() {
+		var incident db.Incident
+
+		BeforeEach(func() {
+			incident = db.Incident{ //nolint:exhaustruct
+				DisplayName: test.Ptr("Storage <degraded>"),
+				Description: test.Ptr("Volumes are slow."),
+				BeganAt:     test.Ptr(now),
+				Affects: &[]db.Impact{{ //nolint:exhaustruct
+					Component: &db.Component{DisplayName: test.Ptr("Storage")}, //nolint:exhaustruct
+					Severity:  test.Ptr(apiServerDefinition.SeverityValue(50)),
+				}},
+				Model: db.Model{ID: uuid.MustParse("4bcd4e61-b1d5-4c8b-9ec4-54e0d7d0e0d1")},
+			}
+		})
+
+		It("should describe a new incident in text and escaped HTML", func() {
+			// Act
+			notification, err := composer.Incident(&subscriber, db.EventIncidentCreated, &incident, nil, now)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(notification.Subject).Should(Equal("New incident: Storage <degraded>"))
+			Ω(notification.TextBody).Should(ContainSubstring("Storage <degraded>\n\nVolumes are slow."))
+			Ω(notification.TextBody).Should(ContainSubstring("Affected components: Storage\n"))
+			Ω(notification.TextBody).Should(ContainSubstring(
+				"Details: https://status.example.com/incidents/4bcd4e61-b1d5-4c8b-9ec4-54e0d7d0e0d1",
+			))
+			Ω(notification.HTMLBody).Should(ContainSubstring("<h1>Storage &lt;degraded&gt;</h1>"))
+			Ω(notification.UnsubscribeURL).Should(HavePrefix("https://status.example.com/subscribers/unsubscribe?token="))
+			Ω(notification.TextBody).Should(ContainSubstring(notification.UnsubscribeURL))
+		})
+
+		It("should describe an update of a maintenance", func() {
+			// Arrange
+			(*incident.Affects)[0].Severity = test.Ptr(apiServerDefinition.SeverityValue(0))
+			update := db.IncidentUpdate{ //nolint:exhaustruct
+				DisplayName: test.Ptr("Extended"),
+				Description: test.Ptr("The upgrade takes longer."),
+			}
+
+			// Act
+			notification, err := composer.Incident(&subscriber, db.EventIncidentUpdateCreated, &incident, &update, now)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(notification.Subject).Should(Equal("Maintenance updated: Storage <degraded> - Extended"))
+			Ω(notification.TextBody).Should(ContainSubstring("Update: Extended\nThe upgrade takes longer."))
+		})
+	})
+})
+
+// confirmationToken extracts the token of the confirmation link.
+func confirmationToken(body string) string {
+	_, link, _ := strings.Cut(body, "/subscribers/confirm?token=")
+	token, _, _ := strings.Cut(link, "\n")
+
+	unescaped, err := url.QueryUnescape(token)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	return unescaped
+}
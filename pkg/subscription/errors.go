@@ -0,0 +1,10 @@
+package subscription
+
+import "errors"
+
+var (
+	// ErrInvalidToken is an error, raised when a token is malformed, not signed by the secret or for another purpose.
+	ErrInvalidToken = errors.New("token is invalid")
+	// ErrExpiredToken is an error, raised when a token is used after its expiry.
+	ErrExpiredToken = errors.New("token is expired")
+)
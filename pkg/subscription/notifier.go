@@ -0,0 +1,354 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/mail"
+	"github.com/SovereignCloudStack/status-page-api/pkg/webhook"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotifierConfig holds the settings of the [Notifier].
+type NotifierConfig struct {
+	// From is the sender address of the emails.
+	From string
+	// PollInterval is the time between checks for new events and due notifications.
+	PollInterval time.Duration
+	// MaxAttempts is the number of attempts, before a notification is dead.
+	MaxAttempts int
+	// BackoffBase is the delay after the first failed attempt, doubled with each further attempt.
+	BackoffBase time.Duration
+	// BackoffMax limits the delay between attempts.
+	BackoffMax time.Duration
+	// BatchSize limits the number of events and notifications handled per poll.
+	BatchSize int
+}
+
+// Notifier queues notifications about events of the outbox for confirmed subscribers and sends them.
+// Rows are locked with `SKIP LOCKED`, so multiple instances can notify concurrently.
+type Notifier struct {
+	dbCon    *gorm.DB
+	composer *Composer
+	sender   mail.Sender
+	conf     NotifierConfig
+	logger   *zerolog.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// incidentPayload holds the ID of the incident of incident events.
+type incidentPayload struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// NewNotifier creates a new notifier.
+func NewNotifier(
+	dbCon *gorm.DB,
+	composer *Composer,
+	sender mail.Sender,
+	conf NotifierConfig,
+	logger *zerolog.Logger,
+) *Notifier {
+	return &Notifier{
+		dbCon:    dbCon,
+		composer: composer,
+		sender:   sender,
+		conf:     conf,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start polls the outbox and the queue until the notifier is shut down.
+func (n *Notifier) Start() error {
+	defer close(n.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-n.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	n.logger.Log().Dur("pollInterval", n.conf.PollInterval).Msg("subscription notifier started")
+
+	ticker := time.NewTicker(n.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		n.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown stops the notifier and waits for the running attempt to finish.
+func (n *Notifier) Shutdown(ctx context.Context) error {
+	close(n.stop)
+
+	select {
+	case <-n.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error shutting down subscription notifier: %w", ctx.Err())
+	}
+}
+
+func (n *Notifier) poll(ctx context.Context) {
+	_, err := n.FanOut(ctx)
+	if err != nil {
+		n.logger.Error().Err(err).Msg("error fanning out events")
+	}
+
+	for range n.conf.BatchSize {
+		if ctx.Err() != nil {
+			return
+		}
+
+		sent, err := n.SendNext(ctx)
+		if err != nil {
+			n.logger.Error().Err(err).Msg("error sending notification")
+
+			return
+		}
+
+		if !sent {
+			return
+		}
+	}
+}
+
+// FanOut queues a notification for each matching subscriber of each unnotified event and marks the events as notified.
+// Both happen in one transaction, so each event is notified exactly once.
+// Subscribers are only notified about events after their confirmation.
+func (n *Notifier) FanOut(ctx context.Context) (int, error) {
+	var events []*DbDef.Event
+
+	err := n.dbCon.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		res := dbTx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}). //nolint:exhaustruct
+			Where("notified_at IS NULL").
+			Order("id").
+			Limit(n.conf.BatchSize).
+			Find(&events)
+		if res.Error != nil {
+			return fmt.Errorf("error loading events: %w", res.Error)
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		var subscribers []*DbDef.Subscriber
+
+		res = dbTx.Where("confirmed_at IS NOT NULL").Find(&subscribers)
+		if res.Error != nil {
+			return fmt.Errorf("error loading subscribers: %w", res.Error)
+		}
+
+		now := time.Now()
+		notifications := []*DbDef.Notification{}
+		eventIDs := make([]uint64, len(events))
+
+		for eventIndex, event := range events {
+			eventIDs[eventIndex] = event.ID
+
+			eventNotifications, err := n.notificationsOf(dbTx, event, subscribers, now)
+			if err != nil {
+				return err
+			}
+
+			notifications = append(notifications, eventNotifications...)
+		}
+
+		if len(notifications) > 0 {
+			res = dbTx.Create(&notifications)
+			if res.Error != nil {
+				return fmt.Errorf("error creating notifications: %w", res.Error)
+			}
+		}
+
+		res = dbTx.
+			Model(&DbDef.Event{}). //nolint:exhaustruct
+			Where("id IN ?", eventIDs).
+			Update("notified_at", now)
+		if res.Error != nil {
+			return fmt.Errorf("error marking events as notified: %w", res.Error)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error in fan out transaction: %w", err)
+	}
+
+	return len(events), nil
+}
+
+// notificationsOf renders the notifications of the event for the matching subscribers.
+// Only new and resolved incidents and new incident updates are notified.
+func (n *Notifier) notificationsOf(
+	dbTx *gorm.DB,
+	event *DbDef.Event,
+	subscribers []*DbDef.Subscriber,
+	now time.Time,
+) ([]*DbDef.Notification, error) {
+	var (
+		payload   incidentPayload
+		update    *DbDef.IncidentUpdate
+		incidents []*DbDef.Incident
+	)
+
+	switch event.Type { //nolint:exhaustive
+	case DbDef.EventIncidentCreated, DbDef.EventIncidentResolved:
+		err := json.Unmarshal(event.Payload, &payload)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding payload of event %d: %w", event.ID, err)
+		}
+	case DbDef.EventIncidentUpdateCreated:
+		err := json.Unmarshal(event.Payload, &update)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding payload of event %d: %w", event.ID, err)
+		}
+
+		if update.IncidentID == nil {
+			return nil, nil
+		}
+
+		payload.ID = *update.IncidentID
+	default:
+		return nil, nil
+	}
+
+	// The incident is loaded with its current state, it could be deleted by now.
+	res := dbTx.Preload("Affects.Component").Where("id = ?", payload.ID).Find(&incidents)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error loading incident of event %d: %w", event.ID, res.Error)
+	}
+
+	if len(incidents) == 0 {
+		return nil, nil
+	}
+
+	notifications := []*DbDef.Notification{}
+
+	for _, subscriber := range subscribers {
+		if subscriber.ConfirmedAt.After(event.CreatedAt) || !subscriber.Matches(incidents[0]) {
+			continue
+		}
+
+		notification, err := n.composer.Incident(subscriber, event.Type, incidents[0], update, now)
+		if err != nil {
+			return nil, fmt.Errorf("error composing notification of event %d: %w", event.ID, err)
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// SendNext attempts the most overdue pending notification and reports, if there was one.
+// Failed attempts are retried with exponential backoff, until the notification is dead after the maximum attempts.
+func (n *Notifier) SendNext(ctx context.Context) (bool, error) {
+	var found bool
+
+	err := n.dbCon.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		var notifications []*DbDef.Notification
+
+		res := dbTx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}). //nolint:exhaustruct
+			Where("status = ? AND next_attempt_at <= ?", DbDef.NotificationPending, time.Now()).
+			Order("next_attempt_at").
+			Limit(1).
+			Find(&notifications)
+		if res.Error != nil {
+			return fmt.Errorf("error loading due notification: %w", res.Error)
+		}
+
+		if len(notifications) == 0 {
+			return nil
+		}
+
+		found = true
+		notification := notifications[0]
+
+		sendErr := n.sender.Send(ctx, &mail.Message{
+			From:           n.conf.From,
+			To:             notification.Recipient,
+			Subject:        notification.Subject,
+			Text:           notification.TextBody,
+			HTML:           notification.HTMLBody,
+			UnsubscribeURL: notification.UnsubscribeURL,
+		})
+
+		return n.recordAttempt(dbTx, notification, sendErr)
+	})
+	if err != nil {
+		return found, fmt.Errorf("error in notification transaction: %w", err)
+	}
+
+	return found, nil
+}
+
+// recordAttempt updates the notification with the result of an attempt.
+func (n *Notifier) recordAttempt(dbTx *gorm.DB, notification *DbDef.Notification, sendErr error) error {
+	now := time.Now()
+	attempts := notification.Attempts + 1
+
+	logger := n.logger.With().
+		Uint64("notification", notification.ID).
+		Str("subscriber", notification.SubscriberID.String()).
+		Int("attempts", attempts).
+		Logger()
+
+	updates := map[string]interface{}{
+		"attempts": attempts,
+	}
+
+	switch {
+	case sendErr == nil:
+		logger.Debug().Msg("notification sent")
+
+		updates["status"] = DbDef.NotificationSent
+		updates["sent_at"] = now
+		updates["last_error"] = nil
+	case attempts >= n.conf.MaxAttempts:
+		logger.Warn().Err(sendErr).Msg("giving up notification")
+
+		updates["status"] = DbDef.NotificationDead
+		updates["last_error"] = sendErr.Error()
+	default:
+		logger.Info().Err(sendErr).Msg("notification failed, retrying")
+
+		updates["next_attempt_at"] = now.Add(webhook.Backoff(attempts, n.conf.BackoffBase, n.conf.BackoffMax))
+		updates["last_error"] = sendErr.Error()
+	}
+
+	res := dbTx.
+		Model(&DbDef.Notification{}). //nolint:exhaustruct
+		Where("id = ?", notification.ID).
+		Updates(updates)
+	if res.Error != nil {
+		return fmt.Errorf("error recording notification attempt: %w", res.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+package subscription_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/mail"
+	"github.com/SovereignCloudStack/status-page-api/pkg/subscription"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Notifier", func() {
+	const (
+		incidentID         = "4bcd4e61-b1d5-4c8b-9ec4-54e0d7d0e0d1"
+		componentID        = "8f8b9c1e-3b5e-4c55-a58c-4b6c3b7b5d0e"
+		otherComponentID   = "1d2e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a"
+		impactTypeID       = "c3fc130d-e6c4-4f94-86ac-b9f7d7f8d6a9"
+		subscriberID       = "9e1b3c2a-5d4f-4a8b-8c7d-6e5f4a3b2c1d"
+		otherSubscriberID  = "2a3b4c5d-6e7f-4a8b-9c0d-1e2f3a4b5c6d"
+		lateSubscriberID   = "7f6e5d4c-3b2a-4c1d-8e9f-0a1b2c3d4e5f"
+		subscriberEmail    = "user@example.com"
+		notificationID     = 7
+		notificationSender = "Status Page <status@example.com>"
+	)
+
+	var (
+		// sub loggers
+		_, gormLogger, notifierLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// local SMTP stand-in
+		smtpServer *test.SMTPServer
+
+		// actual functions under test
+		notifier *subscription.Notifier
+
+		// expected SQL
+		expectedEventsQuery = regexp.
+					QuoteMeta(`SELECT * FROM "events" WHERE notified_at IS NULL ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`)
+		expectedSubscribersQuery = regexp.
+						QuoteMeta(`SELECT * FROM "subscribers" WHERE confirmed_at IS NOT NULL`)
+		expectedIncidentQuery = regexp.
+					QuoteMeta(`SELECT * FROM "incidents" WHERE id = $1`)
+		expectedImpactQuery = regexp.
+					QuoteMeta(`SELECT * FROM "impacts" WHERE "impacts"."incident_id" = $1`)
+		expectedComponentQuery = regexp.
+					QuoteMeta(`SELECT * FROM "components" WHERE "components"."id" = $1`)
+		expectedNotificationsInsert = regexp.
+						QuoteMeta(`INSERT INTO "notifications" ("subscriber_id","recipient","subject","text_body","html_body","unsubscribe_url","status","attempts","next_attempt_at","last_error","created_at","sent_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) RETURNING "id"`) //nolint:lll
+		expectedEventsNotified = regexp.
+					QuoteMeta(`UPDATE "events" SET "notified_at"=$1 WHERE id IN ($2,$3)`)
+		expectedDueNotificationQuery = regexp.
+						QuoteMeta(`SELECT * FROM "notifications" WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED`) //nolint:lll
+
+		eventTime = time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+		config = subscription.NotifierConfig{
+			From:         notificationSender,
+			PollInterval: time.Second,
+			MaxAttempts:  3,
+			BackoffBase:  time.Minute,
+			BackoffMax:   time.Hour,
+			BatchSize:    10,
+		}
+	)
+
+	BeforeEach(func() {
+		// setup database, SMTP stand-in and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGorm(gormLogger)
+		smtpServer = test.MustStartSMTPServer()
+
+		composer := subscription.NewComposer(
+			subscription.NewSigner("0123456789abcdef0123456789abcdef"),
+			subscription.ComposerConfig{PublicURL: "https://status.example.com/", ConfirmTTL: time.Hour},
+		)
+		sender := mail.NewSMTPSender(mail.SMTPConfig{
+			Address:  smtpServer.Address,
+			Username: "",
+			Password: "",
+			Timeout:  time.Second,
+		})
+
+		notifier = subscription.NewNotifier(gormDB, composer, sender, config, notifierLogger)
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database and SMTP stand-in
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+		smtpServer.Close()
+	})
+
+	Describe("FanOut", func() {
+		Context("with unnotified events", func() {
+			It("should queue notifications for matching subscribers confirmed before the event", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedEventsQuery).
+					WithArgs(config.BatchSize).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "created_at", "type", "payload"}).
+						AddRow(1, eventTime, db.EventIncidentCreated, []byte(`{"id":"`+incidentID+`"}`)).
+						AddRow(2, eventTime, db.EventComponentChanged, []byte(`{}`)),
+					)
+				sqlMock.
+					ExpectQuery(expectedSubscribersQuery).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "email", "components", "labels", "confirmed_at"}).
+						AddRow(subscriberID, subscriberEmail, `["`+componentID+`"]`, []byte(`{}`), eventTime.Add(-time.Hour)).
+						AddRow(
+							otherSubscriberID, "other@example.com", `["`+otherComponentID+`"]`, []byte(`{}`),
+							eventTime.Add(-time.Hour),
+						).
+						AddRow(lateSubscriberID, "late@example.com", `[]`, []byte(`{}`), eventTime.Add(time.Hour)),
+					)
+				sqlMock.
+					ExpectQuery(expectedIncidentQuery).
+					WithArgs(incidentID).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "display_name", "description", "began_at"}).
+						AddRow(incidentID, "Storage degraded", "Volumes are slow.", eventTime),
+					)
+				sqlMock.
+					ExpectQuery(expectedImpactQuery).
+					WithArgs(incidentID).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"incident_id", "component_id", "impact_type_id", "severity"}).
+						AddRow(incidentID, componentID, impactTypeID, 50),
+					)
+				sqlMock.
+					ExpectQuery(expectedComponentQuery).
+					WithArgs(componentID).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "display_name"}).
+						AddRow(componentID, "Storage"),
+					)
+				sqlMock.
+					ExpectQuery(expectedNotificationsInsert).
+					WithArgs(
+						subscriberID, subscriberEmail, "New incident: Storage degraded",
+						sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+						"pending", 0, sqlmock.AnyArg(), nil, sqlmock.AnyArg(), nil,
+					).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(notificationID))
+				sqlMock.
+					ExpectExec(expectedEventsNotified).
+					WithArgs(sqlmock.AnyArg(), 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				sqlMock.ExpectCommit()
+
+				// Act
+				count, err := notifier.FanOut(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(count).Should(Equal(2))
+			})
+		})
+
+		Context("without unnotified events", func() {
+			It("should do nothing", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedEventsQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				sqlMock.ExpectCommit()
+
+				// Act
+				count, err := notifier.FanOut(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(count).Should(BeZero())
+			})
+		})
+
+		Context("with database error", func() {
+			It("should roll back", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedEventsQuery).WillReturnError(test.ErrTestError)
+				sqlMock.ExpectRollback()
+
+				// Act
+				_, err := notifier.FanOut(context.Background())
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("SendNext", func() {
+		expectDueNotification := func(attempts int) {
+			sqlMock.ExpectBegin()
+			sqlMock.
+				ExpectQuery(expectedDueNotificationQuery).
+				WithArgs("pending", sqlmock.AnyArg(), 1).
+				WillReturnRows(sqlmock.
+					NewRows([]string{
+						"id", "subscriber_id", "recipient", "subject", "text_body", "html_body", "unsubscribe_url", "status",
+						"attempts",
+					}).
+					AddRow(
+						notificationID, subscriberID, subscriberEmail, "New incident: Storage degraded",
+						"Storage degraded", "<p>Storage degraded</p>", "https://status.example.com/unsubscribe", "pending",
+						attempts,
+					),
+				)
+		}
+
+		Context("with accepting server", func() {
+			It("should send the email and mark the notification as sent", func() {
+				// Arrange
+				expectDueNotification(0)
+				sqlMock.
+					ExpectExec(regexp.QuoteMeta(
+						`UPDATE "notifications" SET "attempts"=$1,"last_error"=$2,"sent_at"=$3,"status"=$4 WHERE id = $5`,
+					)).
+					WithArgs(1, nil, sqlmock.AnyArg(), "sent", notificationID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				found, err := notifier.SendNext(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+
+				messages := smtpServer.Messages()
+				Ω(messages).Should(HaveLen(1))
+				Ω(messages[0].From).Should(Equal("status@example.com"))
+				Ω(messages[0].To).Should(Equal([]string{subscriberEmail}))
+				Ω(messages[0].Data).Should(ContainSubstring("List-Unsubscribe: <https://status.example.com/unsubscribe>"))
+			})
+		})
+
+		Context("with rejecting server", func() {
+			It("should schedule another attempt", func() {
+				// Arrange
+				smtpServer.SetRejecting(true)
+
+				expectDueNotification(0)
+				sqlMock.
+					ExpectExec(regexp.QuoteMeta(
+						`UPDATE "notifications" SET "attempts"=$1,"last_error"=$2,"next_attempt_at"=$3 WHERE id = $4`,
+					)).
+					WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg(), notificationID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				found, err := notifier.SendNext(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+				Ω(smtpServer.Messages()).Should(BeEmpty())
+			})
+		})
+
+		Context("with rejecting server on the last attempt", func() {
+			It("should mark the notification as dead", func() {
+				// Arrange
+				smtpServer.SetRejecting(true)
+
+				expectDueNotification(config.MaxAttempts - 1)
+				sqlMock.
+					ExpectExec(regexp.QuoteMeta(
+						`UPDATE "notifications" SET "attempts"=$1,"last_error"=$2,"status"=$3 WHERE id = $4`,
+					)).
+					WithArgs(config.MaxAttempts, sqlmock.AnyArg(), "dead", notificationID).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				found, err := notifier.SendNext(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+			})
+		})
+
+		Context("without due notification", func() {
+			It("should report nothing sent", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedDueNotificationQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				sqlMock.ExpectCommit()
+
+				// Act
+				found, err := notifier.SendNext(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,98 @@
+// Package subscription manages email subscriptions and notifies subscribers about incidents.
+package subscription
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purpose restricts a token to a single action.
+type Purpose string
+
+const (
+	// PurposeConfirm tokens confirm a subscription.
+	PurposeConfirm Purpose = "confirm"
+	// PurposeUnsubscribe tokens cancel a subscription.
+	PurposeUnsubscribe Purpose = "unsubscribe"
+)
+
+// tokenParts is the number of dot separated fields of the signed payload.
+const tokenParts = 3
+
+// Signer signs and verifies tokens, so links in emails can't be forged.
+// A token is the base64url encoded payload `<purpose>.<subscriber id>.<expiry unix time>`
+// and its HMAC-SHA256, joined by a dot.
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner creates a new signer with the secret.
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+// Sign creates a token for the subscriber. A zero expiry creates a token, which never expires.
+func (s *Signer) Sign(purpose Purpose, subscriberID uuid.UUID, expiresAt time.Time) string {
+	var expiry int64
+	if !expiresAt.IsZero() {
+		expiry = expiresAt.Unix()
+	}
+
+	payload := string(purpose) + "." + subscriberID.String() + "." + strconv.FormatInt(expiry, 10)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(s.mac([]byte(payload)))
+}
+
+// Verify checks the token and returns the ID of the subscriber.
+func (s *Signer) Verify(token string, purpose Purpose, now time.Time) (uuid.UUID, error) {
+	encodedPayload, encodedMAC, found := strings.Cut(token, ".")
+	if !found {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, s.mac(payload)) {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	fields := strings.Split(string(payload), ".")
+	if len(fields) != tokenParts || Purpose(fields[0]) != purpose {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	subscriberID, err := uuid.Parse(fields[1])
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	expiry, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+
+	if expiry != 0 && now.Unix() > expiry {
+		return uuid.Nil, fmt.Errorf("%w: %s", ErrExpiredToken, time.Unix(expiry, 0).UTC().Format(time.RFC3339))
+	}
+
+	return subscriberID, nil
+}
+
+func (s *Signer) mac(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
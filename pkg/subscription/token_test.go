@@ -0,0 +1,90 @@
+package subscription_test
+
+import (
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/subscription"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Signer", func() {
+	var (
+		signer       = subscription.NewSigner("0123456789abcdef0123456789abcdef")
+		subscriberID = uuid.MustParse("9e1b3c2a-5d4f-4a8b-8c7d-6e5f4a3b2c1d")
+		now          = time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	)
+
+	Describe("Verify", func() {
+		It("should return the subscriber of a valid token", func() {
+			// Arrange
+			token := signer.Sign(subscription.PurposeConfirm, subscriberID, now.Add(time.Hour))
+
+			// Act
+			id, err := signer.Verify(token, subscription.PurposeConfirm, now)
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(id).Should(Equal(subscriberID))
+		})
+
+		It("should accept tokens without expiry", func() {
+			// Arrange
+			token := signer.Sign(subscription.PurposeUnsubscribe, subscriberID, time.Time{})
+
+			// Act
+			id, err := signer.Verify(token, subscription.PurposeUnsubscribe, now.AddDate(10, 0, 0))
+
+			// Assert
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(id).Should(Equal(subscriberID))
+		})
+
+		It("should reject expired tokens", func() {
+			// Arrange
+			token := signer.Sign(subscription.PurposeConfirm, subscriberID, now.Add(-time.Second))
+
+			// Act
+			_, err := signer.Verify(token, subscription.PurposeConfirm, now)
+
+			// Assert
+			Ω(err).Should(MatchError(subscription.ErrExpiredToken))
+		})
+
+		It("should reject tokens of another purpose", func() {
+			// Arrange
+			token := signer.Sign(subscription.PurposeUnsubscribe, subscriberID, time.Time{})
+
+			// Act
+			_, err := signer.Verify(token, subscription.PurposeConfirm, now)
+
+			// Assert
+			Ω(err).Should(MatchError(subscription.ErrInvalidToken))
+		})
+
+		It("should reject tokens of another secret", func() {
+			// Arrange
+			token := subscription.NewSigner("other").Sign(subscription.PurposeConfirm, subscriberID, time.Time{})
+
+			// Act
+			_, err := signer.Verify(token, subscription.PurposeConfirm, now)
+
+			// Assert
+			Ω(err).Should(MatchError(subscription.ErrInvalidToken))
+		})
+
+		DescribeTable("should reject malformed tokens",
+			func(token string) {
+				// Act
+				_, err := signer.Verify(token, subscription.PurposeConfirm, now)
+
+				// Assert
+				Ω(err).Should(MatchError(subscription.ErrInvalidToken))
+			},
+			Entry("when empty", ""),
+			Entry("without signature", "Y29uZmlybQ"),
+			Entry("with invalid encoding", "!!!.!!!"),
+		)
+	})
+})
@@ -0,0 +1,376 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	DbDef "github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxResponseBodySize limits the part of the response body read to reuse connections.
+	maxResponseBodySize = 64 * 1024
+	// leaseMargin extends the lease of a claimed delivery beyond the timeout of its attempt.
+	leaseMargin = time.Minute
+)
+
+// Config holds the settings of the [Dispatcher].
+type Config struct {
+	// PollInterval is the time between checks for new events and due deliveries.
+	PollInterval time.Duration
+	// Timeout limits the duration of a single delivery attempt.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts, before a delivery is dead.
+	MaxAttempts int
+	// BackoffBase is the delay after the first failed attempt, doubled with each further attempt.
+	BackoffBase time.Duration
+	// BackoffMax limits the delay between attempts.
+	BackoffMax time.Duration
+	// BatchSize limits the number of events and deliveries handled per poll.
+	BatchSize int
+}
+
+// Dispatcher fans events out of the outbox to the subscriptions and delivers them.
+// Rows are locked with `SKIP LOCKED`, so multiple instances can dispatch concurrently.
+type Dispatcher struct {
+	dbCon  *gorm.DB
+	client *http.Client
+	conf   Config
+	logger *zerolog.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates a new dispatcher.
+func New(dbCon *gorm.DB, conf Config, logger *zerolog.Logger) *Dispatcher {
+	return &Dispatcher{
+		dbCon:  dbCon,
+		client: &http.Client{Timeout: conf.Timeout}, //nolint:exhaustruct
+		conf:   conf,
+		logger: logger,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start polls the outbox until the dispatcher is shut down.
+func (d *Dispatcher) Start() error {
+	defer close(d.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-d.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	d.logger.Log().Dur("pollInterval", d.conf.PollInterval).Msg("webhook dispatcher started")
+
+	ticker := time.NewTicker(d.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown stops the dispatcher and waits for the running attempt to finish.
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	close(d.stop)
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error shutting down webhook dispatcher: %w", ctx.Err())
+	}
+}
+
+func (d *Dispatcher) poll(ctx context.Context) {
+	_, err := d.FanOut(ctx)
+	if err != nil {
+		d.logger.Error().Err(err).Msg("error fanning out events")
+	}
+
+	for range d.conf.BatchSize {
+		if ctx.Err() != nil {
+			return
+		}
+
+		delivered, err := d.DeliverNext(ctx)
+		if err != nil {
+			d.logger.Error().Err(err).Msg("error delivering event")
+
+			return
+		}
+
+		if !delivered {
+			return
+		}
+	}
+}
+
+// FanOut creates a delivery for each subscription of each undispatched event and marks the events as dispatched.
+// Both happen in one transaction, so each event is fanned out exactly once.
+func (d *Dispatcher) FanOut(ctx context.Context) (int, error) {
+	var events []*DbDef.Event
+
+	err := d.dbCon.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		res := dbTx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}). //nolint:exhaustruct
+			Where("dispatched_at IS NULL").
+			Order("id").
+			Limit(d.conf.BatchSize).
+			Find(&events)
+		if res.Error != nil {
+			return fmt.Errorf("error loading events: %w", res.Error)
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		var subscriptions []*DbDef.WebhookSubscription
+
+		res = dbTx.Find(&subscriptions)
+		if res.Error != nil {
+			return fmt.Errorf("error loading subscriptions: %w", res.Error)
+		}
+
+		now := time.Now()
+		deliveries := []*DbDef.WebhookDelivery{}
+		eventIDs := make([]uint64, len(events))
+
+		for eventIndex, event := range events {
+			eventIDs[eventIndex] = event.ID
+
+			for _, subscription := range subscriptions {
+				if subscription.Subscribes(event.Type) {
+					deliveries = append(deliveries, DbDef.NewWebhookDelivery(event.ID, subscription.ID, now))
+				}
+			}
+		}
+
+		if len(deliveries) > 0 {
+			res = dbTx.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries) //nolint:exhaustruct
+			if res.Error != nil {
+				return fmt.Errorf("error creating deliveries: %w", res.Error)
+			}
+		}
+
+		res = dbTx.
+			Model(&DbDef.Event{}). //nolint:exhaustruct
+			Where("id IN ?", eventIDs).
+			Update("dispatched_at", now)
+		if res.Error != nil {
+			return fmt.Errorf("error marking events as dispatched: %w", res.Error)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error in fan out transaction: %w", err)
+	}
+
+	return len(events), nil
+}
+
+// DeliverNext attempts the most overdue pending delivery and reports, if there was one.
+// The delivery is claimed in a short transaction, which counts the attempt and leases the delivery
+// by moving its next attempt past the timeout, so no transaction is open while the target is called.
+// The result is recorded in a second transaction, unless the lease expired and another attempt claimed the delivery.
+// Failed attempts are retried with exponential backoff, until the delivery is dead after the maximum attempts.
+// A crash before the result is recorded leads to another attempt with the same [DeliveryHeader] after the lease.
+func (d *Dispatcher) DeliverNext(ctx context.Context) (bool, error) {
+	delivery, event, subscription, err := d.claimNext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if delivery == nil {
+		return false, nil
+	}
+
+	sendErr := d.send(ctx, delivery, event, subscription)
+
+	// The attempt is recorded, even if the dispatcher is shut down meanwhile.
+	err = d.dbCon.WithContext(context.WithoutCancel(ctx)).Transaction(func(dbTx *gorm.DB) error {
+		return d.recordAttempt(dbTx, delivery, sendErr)
+	})
+	if err != nil {
+		return true, fmt.Errorf("error in record transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// claimNext claims the most overdue pending delivery with its event and subscription, nil without due deliveries.
+func (d *Dispatcher) claimNext(
+	ctx context.Context,
+) (*DbDef.WebhookDelivery, *DbDef.Event, *DbDef.WebhookSubscription, error) {
+	var (
+		delivery     *DbDef.WebhookDelivery
+		event        DbDef.Event
+		subscription DbDef.WebhookSubscription
+	)
+
+	err := d.dbCon.WithContext(ctx).Transaction(func(dbTx *gorm.DB) error {
+		var deliveries []*DbDef.WebhookDelivery
+
+		now := time.Now()
+
+		res := dbTx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}). //nolint:exhaustruct
+			Where("status = ? AND next_attempt_at <= ?", DbDef.WebhookDeliveryPending, now).
+			Order("next_attempt_at").
+			Limit(1).
+			Find(&deliveries)
+		if res.Error != nil {
+			return fmt.Errorf("error loading due delivery: %w", res.Error)
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		res = dbTx.Where("id = ?", deliveries[0].EventID).First(&event)
+		if res.Error != nil {
+			return fmt.Errorf("error loading event of delivery: %w", res.Error)
+		}
+
+		res = dbTx.Where("id = ?", deliveries[0].SubscriptionID).First(&subscription)
+		if res.Error != nil {
+			return fmt.Errorf("error loading subscription of delivery: %w", res.Error)
+		}
+
+		deliveries[0].Attempts++
+		deliveries[0].NextAttemptAt = now.Add(d.conf.Timeout + leaseMargin)
+
+		res = dbTx.
+			Model(&DbDef.WebhookDelivery{}). //nolint:exhaustruct
+			Where("id = ?", deliveries[0].ID).
+			Updates(map[string]interface{}{
+				"attempts":        deliveries[0].Attempts,
+				"next_attempt_at": deliveries[0].NextAttemptAt,
+			})
+		if res.Error != nil {
+			return fmt.Errorf("error claiming delivery: %w", res.Error)
+		}
+
+		delivery = deliveries[0]
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error in claim transaction: %w", err)
+	}
+
+	return delivery, &event, &subscription, nil
+}
+
+// recordAttempt updates the claimed delivery with the result of its attempt.
+func (d *Dispatcher) recordAttempt(dbTx *gorm.DB, delivery *DbDef.WebhookDelivery, sendErr error) error {
+	now := time.Now()
+
+	logger := d.logger.With().
+		Uint64("delivery", delivery.ID).
+		Uint64("event", delivery.EventID).
+		Int("attempts", delivery.Attempts).
+		Logger()
+
+	updates := map[string]interface{}{}
+
+	switch {
+	case sendErr == nil:
+		logger.Debug().Msg("event delivered")
+
+		updates["status"] = DbDef.WebhookDeliveryDelivered
+		updates["delivered_at"] = now
+		updates["last_error"] = nil
+	case delivery.Attempts >= d.conf.MaxAttempts:
+		logger.Warn().Err(sendErr).Msg("giving up delivery")
+
+		updates["status"] = DbDef.WebhookDeliveryDead
+		updates["last_error"] = sendErr.Error()
+	default:
+		logger.Info().Err(sendErr).Msg("delivery failed, retrying")
+
+		updates["next_attempt_at"] = now.Add(Backoff(delivery.Attempts, d.conf.BackoffBase, d.conf.BackoffMax))
+		updates["last_error"] = sendErr.Error()
+	}
+
+	// The attempts of the claim fence the result, a later claim after the lease expired owns the delivery.
+	res := dbTx.
+		Model(&DbDef.WebhookDelivery{}). //nolint:exhaustruct
+		Where("id = ? AND attempts = ?", delivery.ID, delivery.Attempts).
+		Updates(updates)
+	if res.Error != nil {
+		return fmt.Errorf("error recording delivery attempt: %w", res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		logger.Warn().Msg("lease of delivery expired, attempt not recorded")
+	}
+
+	return nil
+}
+
+// send posts the signed event to the target of the subscription.
+func (d *Dispatcher) send(
+	ctx context.Context,
+	delivery *DbDef.WebhookDelivery,
+	event *DbDef.Event,
+	subscription *DbDef.WebhookSubscription,
+) error {
+	body, err := json.Marshal(event.ToAPIResponse())
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, *subscription.TargetURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	timestamp := time.Now()
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, string(event.Type))
+	request.Header.Set(DeliveryHeader, strconv.FormatUint(delivery.ID, 10))
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	request.Header.Set(SignatureHeader, Sign(*subscription.Secret, timestamp, body))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseBodySize))
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, response.StatusCode)
+	}
+
+	return nil
+}
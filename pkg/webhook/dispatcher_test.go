@@ -0,0 +1,333 @@
+package webhook_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SovereignCloudStack/status-page-api/internal/app/util/test"
+	"github.com/SovereignCloudStack/status-page-api/pkg/db"
+	"github.com/SovereignCloudStack/status-page-api/pkg/webhook"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+var _ = Describe("Dispatcher", func() {
+	const (
+		subscriptionID = "0d8fdc4f-37a4-4b3c-9f5e-6a4c0a6f1a2e"
+		secret         = "secret"
+	)
+
+	var (
+		// sub loggers
+		_, gormLogger, dispatcherLogger = test.MustSetupLogging(zerolog.TraceLevel)
+
+		// sql mocking
+		sqlDB   *sql.DB
+		sqlMock sqlmock.Sqlmock
+
+		// target of the deliveries
+		target        *httptest.Server
+		targetStatus  int
+		targetRequest *http.Request
+		targetBody    []byte
+
+		// actual functions under test
+		dispatcher *webhook.Dispatcher
+
+		// expected SQL
+		expectedEventsQuery = regexp.
+					QuoteMeta(`SELECT * FROM "events" WHERE dispatched_at IS NULL ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`)
+		expectedSubscriptionsQuery = regexp.
+						QuoteMeta(`SELECT * FROM "webhook_subscriptions"`)
+		expectedDeliveriesInsert = regexp.
+						QuoteMeta(`INSERT INTO "webhook_deliveries" ("event_id","subscription_id","status","attempts","next_attempt_at","last_error","created_at","delivered_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) ON CONFLICT DO NOTHING RETURNING "id"`) //nolint:lll
+		expectedEventsDispatch = regexp.
+					QuoteMeta(`UPDATE "events" SET "dispatched_at"=$1 WHERE id IN ($2,$3)`)
+		expectedDueDeliveryQuery = regexp.
+						QuoteMeta(`SELECT * FROM "webhook_deliveries" WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED`) //nolint:lll
+		expectedEventQuery = regexp.
+					QuoteMeta(`SELECT * FROM "events" WHERE id = $1 ORDER BY "events"."id" LIMIT $2`)
+		expectedSubscriptionQuery = regexp.
+						QuoteMeta(`SELECT * FROM "webhook_subscriptions" WHERE id = $1 ORDER BY "webhook_subscriptions"."id" LIMIT $2`) //nolint:lll
+
+		expectedDeliveryClaim = regexp.
+					QuoteMeta(`UPDATE "webhook_deliveries" SET "attempts"=$1,"next_attempt_at"=$2 WHERE id = $3`)
+
+		config = webhook.Config{
+			PollInterval: time.Second,
+			Timeout:      time.Second,
+			MaxAttempts:  3,
+			BackoffBase:  time.Minute,
+			BackoffMax:   time.Hour,
+			BatchSize:    10,
+		}
+	)
+
+	BeforeEach(func() {
+		// setup database, target and mock before each test
+		var gormDB *gorm.DB
+
+		sqlDB, sqlMock, gormDB = test.MustMockGorm(gormLogger)
+
+		targetStatus = http.StatusNoContent
+		targetRequest = nil
+		target = httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			targetRequest = req
+			targetBody, _ = io.ReadAll(req.Body)
+
+			res.WriteHeader(targetStatus)
+		}))
+
+		dispatcher = webhook.New(gormDB, config, dispatcherLogger)
+	})
+
+	AfterEach(func() {
+		// check every expectation after each test and close database and target
+		Ω(sqlMock.ExpectationsWereMet()).ShouldNot(HaveOccurred())
+		sqlDB.Close()
+		target.Close()
+	})
+
+	Describe("FanOut", func() {
+		Context("with undispatched events", func() {
+			It("should create deliveries for subscribed events only and mark all events dispatched", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.
+					ExpectQuery(expectedEventsQuery).
+					WithArgs(config.BatchSize).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "type"}).
+						AddRow(1, db.EventIncidentCreated).
+						AddRow(2, db.EventComponentChanged),
+					)
+				sqlMock.
+					ExpectQuery(expectedSubscriptionsQuery).
+					WillReturnRows(sqlmock.
+						NewRows([]string{"id", "target_url", "events", "secret"}).
+						AddRow(subscriptionID, target.URL, `["incident.created"]`, secret),
+					)
+				sqlMock.
+					ExpectQuery(expectedDeliveriesInsert).
+					WithArgs(1, subscriptionID, "pending", 0, sqlmock.AnyArg(), nil, sqlmock.AnyArg(), nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				sqlMock.
+					ExpectExec(expectedEventsDispatch).
+					WithArgs(sqlmock.AnyArg(), 1, 2).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				sqlMock.ExpectCommit()
+
+				// Act
+				count, err := dispatcher.FanOut(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(count).Should(Equal(2))
+			})
+		})
+
+		Context("without undispatched events", func() {
+			It("should do nothing", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedEventsQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				sqlMock.ExpectCommit()
+
+				// Act
+				count, err := dispatcher.FanOut(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(count).Should(BeZero())
+			})
+		})
+
+		Context("with database error", func() {
+			It("should roll back", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedEventsQuery).WillReturnError(test.ErrTestError)
+				sqlMock.ExpectRollback()
+
+				// Act
+				_, err := dispatcher.FanOut(context.Background())
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("DeliverNext", func() {
+		expectDueDelivery := func(attempts int) {
+			sqlMock.ExpectBegin()
+			sqlMock.
+				ExpectQuery(expectedDueDeliveryQuery).
+				WithArgs("pending", sqlmock.AnyArg(), 1).
+				WillReturnRows(sqlmock.
+					NewRows([]string{"id", "event_id", "subscription_id", "status", "attempts"}).
+					AddRow(7, 1, subscriptionID, "pending", attempts),
+				)
+			sqlMock.
+				ExpectQuery(expectedEventQuery).
+				WithArgs(1, 1).
+				WillReturnRows(sqlmock.
+					NewRows([]string{"id", "created_at", "type", "payload"}).
+					AddRow(1, time.Now(), db.EventIncidentCreated, []byte(`{"displayName":"Disk impact"}`)),
+				)
+			sqlMock.
+				ExpectQuery(expectedSubscriptionQuery).
+				WithArgs(subscriptionID, 1).
+				WillReturnRows(sqlmock.
+					NewRows([]string{"id", "target_url", "events", "secret"}).
+					AddRow(subscriptionID, target.URL, `["incident.created"]`, secret),
+				)
+			sqlMock.
+				ExpectExec(expectedDeliveryClaim).
+				WithArgs(attempts+1, sqlmock.AnyArg(), 7).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			sqlMock.ExpectCommit()
+			sqlMock.ExpectBegin()
+		}
+
+		Context("with acknowledging target", func() {
+			It("should send the signed event and mark the delivery as delivered", func() {
+				// Arrange
+				expectDueDelivery(0)
+				sqlMock.
+					ExpectExec(regexp.QuoteMeta(
+						`UPDATE "webhook_deliveries" SET "delivered_at"=$1,"last_error"=$2,"status"=$3 WHERE id = $4 AND attempts = $5`,
+					)).
+					WithArgs(sqlmock.AnyArg(), nil, "delivered", 7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				found, err := dispatcher.DeliverNext(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+
+				timestamp, err := strconv.ParseInt(targetRequest.Header.Get(webhook.TimestampHeader), 10, 64)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				Ω(targetRequest.Method).Should(Equal(http.MethodPost))
+				Ω(targetRequest.Header.Get(webhook.EventHeader)).Should(Equal("incident.created"))
+				Ω(targetRequest.Header.Get(webhook.DeliveryHeader)).Should(Equal("7"))
+				Ω(targetRequest.Header.Get(webhook.SignatureHeader)).
+					Should(Equal(webhook.Sign(secret, time.Unix(timestamp, 0), targetBody)))
+				Ω(string(targetBody)).Should(ContainSubstring(`"data":{"displayName":"Disk impact"}`))
+			})
+		})
+
+		Context("with failing target", func() {
+			It("should schedule another attempt", func() {
+				// Arrange
+				targetStatus = http.StatusServiceUnavailable
+
+				expectDueDelivery(0)
+				sqlMock.
+					ExpectExec(regexp.QuoteMeta(
+						`UPDATE "webhook_deliveries" SET "last_error"=$1,"next_attempt_at"=$2 WHERE id = $3 AND attempts = $4`,
+					)).
+					WithArgs("unexpected response status: 503", sqlmock.AnyArg(), 7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				found, err := dispatcher.DeliverNext(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+			})
+		})
+
+		Context("with failing target on the last attempt", func() {
+			It("should mark the delivery as dead", func() {
+				// Arrange
+				targetStatus = http.StatusInternalServerError
+
+				expectDueDelivery(config.MaxAttempts - 1)
+				sqlMock.
+					ExpectExec(regexp.QuoteMeta(
+						`UPDATE "webhook_deliveries" SET "last_error"=$1,"status"=$2 WHERE id = $3 AND attempts = $4`,
+					)).
+					WithArgs("unexpected response status: 500", "dead", 7, config.MaxAttempts).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				sqlMock.ExpectCommit()
+
+				// Act
+				found, err := dispatcher.DeliverNext(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+			})
+		})
+
+		Context("with expired lease", func() {
+			It("should leave the delivery to the later claim", func() {
+				// Arrange
+				expectDueDelivery(0)
+				sqlMock.
+					ExpectExec(regexp.QuoteMeta(
+						`UPDATE "webhook_deliveries" SET "delivered_at"=$1,"last_error"=$2,"status"=$3 WHERE id = $4 AND attempts = $5`,
+					)).
+					WithArgs(sqlmock.AnyArg(), nil, "delivered", 7, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectCommit()
+
+				// Act
+				found, err := dispatcher.DeliverNext(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeTrue())
+			})
+		})
+
+		Context("with database error while claiming", func() {
+			It("should not call the target", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedDueDeliveryQuery).WillReturnError(test.ErrTestError)
+				sqlMock.ExpectRollback()
+
+				// Act
+				found, err := dispatcher.DeliverNext(context.Background())
+
+				// Assert
+				Ω(err).Should(HaveOccurred())
+				Ω(found).Should(BeFalse())
+				Ω(targetRequest).Should(BeNil())
+			})
+		})
+
+		Context("without due delivery", func() {
+			It("should report nothing found", func() {
+				// Arrange
+				sqlMock.ExpectBegin()
+				sqlMock.ExpectQuery(expectedDueDeliveryQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				sqlMock.ExpectCommit()
+
+				// Act
+				found, err := dispatcher.DeliverNext(context.Background())
+
+				// Assert
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(found).Should(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,6 @@
+package webhook
+
+import "errors"
+
+// ErrUnexpectedStatus is an error, raised when a target does not acknowledge a delivery with a 2xx status.
+var ErrUnexpectedStatus = errors.New("unexpected response status")
@@ -0,0 +1,67 @@
+// Package webhook delivers events from the outbox to webhook subscriptions.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// EventHeader names the type of the delivered event.
+	EventHeader = "X-Status-Page-Event"
+	// DeliveryHeader identifies the delivery, it stays the same on retries and can be used to drop duplicates.
+	DeliveryHeader = "X-Status-Page-Delivery"
+	// TimestampHeader holds the unix time of the attempt, which is part of the signature.
+	TimestampHeader = "X-Status-Page-Timestamp"
+	// SignatureHeader holds the HMAC-SHA256 signature of the timestamp and the body.
+	SignatureHeader = "X-Status-Page-Signature"
+
+	// signaturePrefix names the algorithm of the signature.
+	signaturePrefix = "sha256="
+
+	// secretLength is the number of random bytes of a generated secret.
+	secretLength = 32
+)
+
+// GenerateSecret generates a random signing secret.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretLength)
+
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", fmt.Errorf("error generating secret: %w", err)
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+// Sign calculates the signature of a delivery as sent in the [SignatureHeader].
+// The signature is the hex encoded HMAC-SHA256 of `<timestamp>.<body>` keyed by the secret of the subscription.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff calculates the delay after the failed attempt, doubling from base up to limit.
+func Backoff(attempt int, base time.Duration, limit time.Duration) time.Duration {
+	delay := base
+
+	for range attempt - 1 {
+		delay *= 2
+		if delay >= limit {
+			return limit
+		}
+	}
+
+	return min(delay, limit)
+}
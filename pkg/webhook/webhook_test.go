@@ -0,0 +1,64 @@
+package webhook_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/SovereignCloudStack/status-page-api/pkg/webhook"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhook", func() {
+	Describe("GenerateSecret", func() {
+		It("should generate distinct secrets", func() {
+			// Act
+			first, err := webhook.GenerateSecret()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			second, err := webhook.GenerateSecret()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Assert
+			Ω(first).Should(HaveLen(64))
+			Ω(first).ShouldNot(Equal(second))
+		})
+	})
+
+	Describe("Sign", func() {
+		It("should sign timestamp and body", func() {
+			// Arrange
+			timestamp := time.Unix(1700000000, 0)
+			body := []byte(`{"id":1}`)
+
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write([]byte(`1700000000.{"id":1}`))
+
+			// Act
+			signature := webhook.Sign("secret", timestamp, body)
+
+			// Assert
+			Ω(signature).Should(Equal("sha256=" + hex.EncodeToString(mac.Sum(nil))))
+			Ω(webhook.Sign("other", timestamp, body)).ShouldNot(Equal(signature))
+			Ω(webhook.Sign("secret", timestamp.Add(time.Second), body)).ShouldNot(Equal(signature))
+		})
+	})
+
+	Describe("Backoff", func() {
+		DescribeTable("should double the delay up to the limit",
+			func(attempt int, expected time.Duration) {
+				// Act
+				delay := webhook.Backoff(attempt, 30*time.Second, time.Hour)
+
+				// Assert
+				Ω(delay).Should(Equal(expected))
+			},
+			Entry("after the first attempt", 1, 30*time.Second),
+			Entry("after the second attempt", 2, time.Minute),
+			Entry("after the fourth attempt", 4, 4*time.Minute),
+			Entry("after many attempts", 20, time.Hour),
+		)
+	})
+})